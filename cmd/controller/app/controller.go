@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -40,19 +41,34 @@ import (
 
 	"github.com/jetstack/cert-manager/cmd/controller/app/options"
 	"github.com/jetstack/cert-manager/pkg/acme/accounts"
+	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
 	clientset "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 	intscheme "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/scheme"
 	informers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
 	"github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/keyescrow"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/staleconsumers"
 	"github.com/jetstack/cert-manager/pkg/controller/clusterissuers"
+	"github.com/jetstack/cert-manager/pkg/controller/legacymigration"
+	"github.com/jetstack/cert-manager/pkg/controller/selfstatus"
 	dnsutil "github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/metrics"
 	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/pkg/util/cron"
 )
 
 const controllerAgentName = "cert-manager"
 
+const (
+	// backoffConfigMapName is the name of the ConfigMap in the leader
+	// election namespace the backoff of work queue items is persisted in
+	backoffConfigMapName = "cert-manager-controller-backoff"
+	// backoffPersistPeriod is the interval the backoff of work queue items is
+	// persisted at
+	backoffPersistPeriod = 10 * time.Second
+)
+
 func Run(opts *options.ControllerOptions, stopCh <-chan struct{}) {
 	rootCtx := util.ContextWithStopCh(context.Background(), stopCh)
 	rootCtx = logf.NewContext(rootCtx, nil, "controller")
@@ -71,13 +87,44 @@ func Run(opts *options.ControllerOptions, stopCh <-chan struct{}) {
 		os.Exit(1)
 	}
 
+	if opts.StatusAPIListenAddress != "" {
+		if err := startStatusAPI(ctx, opts, stopCh); err != nil {
+			log.Error(err, "failed to start status API")
+			os.Exit(1)
+		}
+	}
+
+	enabledControllers := append([]string{}, opts.EnabledControllers...)
+	if opts.EnableLegacyMigration {
+		enabledControllers = append(enabledControllers, legacymigration.ControllerName)
+	}
+	if opts.EnableStaleConsumerDetection {
+		enabledControllers = append(enabledControllers, staleconsumers.ControllerName)
+	}
+	if opts.EnableKeyEscrow {
+		enabledControllers = append(enabledControllers, keyescrow.ControllerName)
+	}
+	if opts.SelfStatusWebhookCASecret != "" || opts.SelfStatusWebhookAddress != "" || opts.StatusAPITLSCertFile != "" {
+		enabledControllers = append(enabledControllers, selfstatus.ControllerName)
+	}
+
 	var wg sync.WaitGroup
 	run := func(_ context.Context) {
+		if opts.PersistBackoff {
+			persister := controller.NewBackoffPersister(ctx.Client, opts.LeaderElectionNamespace, backoffConfigMapName, ctx.Clock)
+			// backoff is restored on a best effort basis, the controllers
+			// work correctly without it
+			if err := persister.Load(rootCtx); err != nil {
+				log.Error(err, "error loading persisted work queue backoff")
+			}
+			ctx.BackoffPersister = persister
+		}
+
 		for n, fn := range controller.Known() {
 			log := log.WithValues("controller", n)
 
 			// only run a controller if it's been enabled
-			if !util.Contains(opts.EnabledControllers, n) {
+			if !util.Contains(enabledControllers, n) {
 				log.Info("not starting controller as it's disabled")
 				continue
 			}
@@ -98,7 +145,7 @@ func Run(opts *options.ControllerOptions, stopCh <-chan struct{}) {
 				defer wg.Done()
 				log.Info("starting controller")
 
-				workers := 5
+				workers := ctx.RuntimeConfig.Get().NumberOfConcurrentWorkers
 				err := fn.Run(workers, stopCh)
 
 				if err != nil {
@@ -108,11 +155,21 @@ func Run(opts *options.ControllerOptions, stopCh <-chan struct{}) {
 			}(n, iface)
 		}
 
+		var backoffWg sync.WaitGroup
+		if ctx.BackoffPersister != nil {
+			backoffWg.Add(1)
+			go func() {
+				defer backoffWg.Done()
+				ctx.BackoffPersister.Run(rootCtx, backoffPersistPeriod)
+			}()
+		}
+
 		log.V(4).Info("starting shared informer factories")
 		ctx.SharedInformerFactory.Start(stopCh)
 		ctx.KubeSharedInformerFactory.Start(stopCh)
 		wg.Wait()
 		log.Info("control loops exited")
+		backoffWg.Wait()
 		ctx.Metrics.Shutdown(metricsServer)
 		os.Exit(0)
 	}
@@ -142,19 +199,61 @@ func buildControllerContext(ctx context.Context, stopCh <-chan struct{}, opts *o
 
 	// Add User-Agent to client
 	kubeCfg = rest.AddUserAgent(kubeCfg, util.CertManagerUserAgent)
+	if opts.FieldOwner != "" {
+		// the API server uses the first segment of the user agent as the
+		// field manager of requests that do not set one explicitly
+		kubeCfg.UserAgent = opts.FieldOwner + "/" + kubeCfg.UserAgent
+	}
+
+	runtimeConfigValues, err := opts.RuntimeConfigValues(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var reloader *configReloader
+	if opts.ConfigFile != "" {
+		reloader = &configReloader{opts: opts, log: logf.FromContext(ctx, "config-reloader")}
+		runtimeConfigValues, _, err = reloader.load()
+		if err != nil {
+			return nil, nil, fmt.Errorf("error loading config file %q: %v", opts.ConfigFile, err)
+		}
+		log.WithValues("path", opts.ConfigFile).Info("loaded config file")
+	}
+	runtimeConfig := controller.NewRuntimeConfig(runtimeConfigValues)
+
+	kubeCfg.QPS = runtimeConfigValues.KubernetesAPIQPS
+	kubeCfg.Burst = runtimeConfigValues.KubernetesAPIBurst
+
+	// The rate limiters of the clientsets are changed along with the
+	// runtime configuration, so that the clientsets and the informers built
+	// from them don't have to be recreated
+	intclRateLimiter := newDynamicRateLimiter(kubeCfg.QPS, kubeCfg.Burst)
+	clRateLimiter := newDynamicRateLimiter(kubeCfg.QPS, kubeCfg.Burst)
+	runtimeConfig.OnChange(func(values controller.RuntimeConfigValues) {
+		intclRateLimiter.SetLimits(values.KubernetesAPIQPS, values.KubernetesAPIBurst)
+		clRateLimiter.SetLimits(values.KubernetesAPIQPS, values.KubernetesAPIBurst)
+	})
 
 	// Create a cert-manager api client
-	intcl, err := clientset.NewForConfig(kubeCfg)
+	intclCfg := rest.CopyConfig(kubeCfg)
+	intclCfg.RateLimiter = intclRateLimiter
+	intcl, err := clientset.NewForConfig(intclCfg)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error creating internal group client: %s", err.Error())
 	}
 
 	// Create a Kubernetes api client
-	cl, err := kubernetes.NewForConfig(kubeCfg)
+	clCfg := rest.CopyConfig(kubeCfg)
+	clCfg.RateLimiter = clRateLimiter
+	cl, err := kubernetes.NewForConfig(clCfg)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error creating kubernetes client: %s", err.Error())
 	}
 
+	if reloader != nil {
+		reloader.runtimeConfig = runtimeConfig
+		go reloader.Run(stopCh)
+	}
+
 	nameservers := opts.DNS01RecursiveNameservers
 	if len(nameservers) == 0 {
 		nameservers = dnsutil.RecursiveNameservers
@@ -181,20 +280,58 @@ func buildControllerContext(ctx context.Context, stopCh <-chan struct{}, opts *o
 		return nil, nil, fmt.Errorf("error parsing ACMEHTTP01SolverResourceLimitsMemory: %s", err.Error())
 	}
 
+	renewalFreezeWindows, err := cron.ParseWindows(opts.CertificateRenewalFreezeWindows)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing CertificateRenewalFreezeWindows: %s", err.Error())
+	}
+	if len(renewalFreezeWindows) > 0 {
+		log.WithValues("windows", opts.CertificateRenewalFreezeWindows).Info("configured certificate renewal freeze windows")
+	}
+
+	// the format of the webhook CA Secret is checked by Validate
+	var webhookCANamespace, webhookCAName string
+	if parts := strings.SplitN(opts.SelfStatusWebhookCASecret, "/", 2); len(parts) == 2 {
+		webhookCANamespace, webhookCAName = parts[0], parts[1]
+	}
+
 	// Create event broadcaster
 	// Add cert-manager types to the default Kubernetes Scheme so Events can be
 	// logged properly
 	intscheme.AddToScheme(scheme.Scheme)
 	log.V(4).Info("creating event broadcaster")
 	eventBroadcaster := record.NewBroadcaster()
-	eventBroadcaster.StartLogging(klog.V(4).Infof)
-	eventBroadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: cl.CoreV1().Events("")})
-	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: controllerAgentName})
+	switch opts.EventsSink {
+	case controller.EventsSinkLog:
+		eventBroadcaster.StartLogging(klog.Infof)
+	default:
+		eventBroadcaster.StartLogging(klog.V(4).Infof)
+		var sink record.EventSink = &corev1.EventSinkImpl{Interface: cl.CoreV1().Events("")}
+		if opts.EventsNamespace != "" {
+			log.Info("recording events in a dedicated namespace", "namespace", opts.EventsNamespace)
+			sink = controller.NewNamespacedEventSink(sink, opts.EventsNamespace)
+		}
+		eventBroadcaster.StartRecordingToSink(sink)
+	}
+	// Events are dropped for resources that have turned down their event
+	// verbosity with the cert-manager.io/event-verbosity annotation.
+	recorder := controller.NewVerbosityRecorder(eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: controllerAgentName}))
 
 	sharedInformerFactory := informers.NewSharedInformerFactoryWithOptions(intcl, time.Second*30, informers.WithNamespace(opts.Namespace))
 	kubeSharedInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(cl, time.Second*30, kubeinformers.WithNamespace(opts.Namespace))
 
-	acmeAccountRegistry := accounts.NewDefaultRegistry()
+	acmeClientOptions := accounts.DefaultClientOptions
+	acmeClientOptions.Backoff = acmecl.ExponentialBackoff{
+		Base:       opts.ACMEHTTPBackoffBase,
+		Max:        opts.ACMEHTTPBackoffMax,
+		MaxRetries: opts.ACMEHTTPMaxRetries,
+	}
+	// a single circuit breaker is shared by the HTTP clients of all ACME
+	// issuers, so that failures of an endpoint are tracked across them
+	acmeClientOptions.CircuitBreaker = acmecl.NewCircuitBreaker(acmecl.CircuitBreakerOptions{
+		FailureThreshold: opts.ACMECircuitBreakerFailureThreshold,
+		Cooldown:         opts.ACMECircuitBreakerCooldown,
+	})
+	acmeAccountRegistry := accounts.NewRegistry(acmeClientOptions)
 
 	return &controller.Context{
 		RootContext:               ctx,
@@ -208,33 +345,54 @@ func buildControllerContext(ctx context.Context, stopCh <-chan struct{}, opts *o
 		Namespace:                 opts.Namespace,
 		Clock:                     clock.RealClock{},
 		Metrics:                   metrics.New(log),
+		RuntimeConfig:             runtimeConfig,
 		ACMEOptions: controller.ACMEOptions{
 			HTTP01SolverImage:                 opts.ACMEHTTP01SolverImage,
 			HTTP01SolverResourceRequestCPU:    HTTP01SolverResourceRequestCPU,
 			HTTP01SolverResourceRequestMemory: HTTP01SolverResourceRequestMemory,
 			HTTP01SolverResourceLimitsCPU:     HTTP01SolverResourceLimitsCPU,
 			HTTP01SolverResourceLimitsMemory:  HTTP01SolverResourceLimitsMemory,
+			HTTP01SolverPropagatedLabels:      opts.ACMEHTTP01SolverPropagatedLabels,
+			HTTP01SolverPriorityClassName:     opts.ACMEHTTP01SolverPriorityClassName,
+			HTTP01SolverRuntimeClassName:      opts.ACMEHTTP01SolverRuntimeClassName,
+			HTTP01SolverTopologySpreadKeys:    opts.ACMEHTTP01SolverTopologySpreadKeys,
 			DNS01CheckAuthoritative:           !opts.DNS01RecursiveNameserversOnly,
 			DNS01Nameservers:                  nameservers,
+			DNS01ExternalDNSOwnerID:           opts.DNS01ExternalDNSOwnerID,
+			DNS01ExternalDNSTXTPrefix:         opts.DNS01ExternalDNSTXTPrefix,
+			DNS01ProviderOutageThreshold:      opts.DNS01ProviderOutageThreshold,
+			DNS01ProviderOutageProbeInterval:  opts.DNS01ProviderOutageProbeInterval,
+			DNS01VerifyCredentials:            opts.DNS01VerifyCredentials,
 			AccountRegistry:                   acmeAccountRegistry,
+			ClientOptions:                     acmeClientOptions,
 		},
 		IssuerOptions: controller.IssuerOptions{
-			ClusterIssuerAmbientCredentials: opts.ClusterIssuerAmbientCredentials,
-			IssuerAmbientCredentials:        opts.IssuerAmbientCredentials,
-			ClusterResourceNamespace:        opts.ClusterResourceNamespace,
-			RenewBeforeExpiryDuration:       opts.RenewBeforeExpiryDuration,
-		},
-		IngressShimOptions: controller.IngressShimOptions{
-			DefaultIssuerName:                 opts.DefaultIssuerName,
-			DefaultIssuerKind:                 opts.DefaultIssuerKind,
-			DefaultIssuerGroup:                opts.DefaultIssuerGroup,
-			DefaultAutoCertificateAnnotations: opts.DefaultAutoCertificateAnnotations,
+			ClusterIssuerAmbientCredentials:  opts.ClusterIssuerAmbientCredentials,
+			IssuerAmbientCredentials:         opts.IssuerAmbientCredentials,
+			ClusterResourceNamespace:         opts.ClusterResourceNamespace,
+			RenewBeforeExpiryDuration:        opts.RenewBeforeExpiryDuration,
+			ClusterDomain:                    opts.ClusterDomain,
+			TrustPodServiceAccountAnnotation: opts.TrustPodServiceAccountAnnotation,
 		},
+		IngressShimOptions: runtimeConfigValues.IngressShimOptions,
 		CertificateOptions: controller.CertificateOptions{
-			EnableOwnerRef: opts.EnableCertificateOwnerRef,
+			EnableOwnerRef:               opts.EnableCertificateOwnerRef,
+			AttestationKeySecretName:     opts.SecretAttestationKeySecretName,
+			RenewalJitterPercent:         opts.CertificateRenewalJitterPercent,
+			RenewalJitterMax:             opts.CertificateRenewalJitterMax,
+			ValidateCertificates:         opts.ValidateCertificates,
+			RenewalFreezeWindows:         renewalFreezeWindows,
+			RenewalFreezeExpiryThreshold: opts.CertificateRenewalFreezeExpiryThreshold,
+			NextPrivateKeySecretTTL:      opts.NextPrivateKeySecretTTL,
+			EnableSecretConsumerPatches:  opts.EnableSecretConsumerPatches,
 		},
-		SchedulerOptions: controller.SchedulerOptions{
-			MaxConcurrentChallenges: opts.MaxConcurrentChallenges,
+		SchedulerOptions: runtimeConfigValues.SchedulerOptions,
+		SelfStatusOptions: controller.SelfStatusOptions{
+			WebhookCASecretNamespace: webhookCANamespace,
+			WebhookCASecretName:      webhookCAName,
+			WebhookAddress:           opts.SelfStatusWebhookAddress,
+			StatusAPICertFile:        opts.StatusAPITLSCertFile,
+			CheckInterval:            opts.SelfStatusCheckInterval,
 		},
 	}, kubeCfg, nil
 }
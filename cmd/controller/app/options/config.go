@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/jetstack/cert-manager/pkg/controller"
+)
+
+// ReloadableConfig is the contents of the file given with --config. The file
+// is re-read whilst the controller is running and changes to it are applied
+// without restarting. Fields that are not set take the value of the
+// corresponding flag.
+type ReloadableConfig struct {
+	KubernetesAPIQPS          *float32 `json:"kubernetesAPIQPS,omitempty"`
+	KubernetesAPIBurst        *int     `json:"kubernetesAPIBurst,omitempty"`
+	NumberOfConcurrentWorkers *int     `json:"numberOfConcurrentWorkers,omitempty"`
+	MaxConcurrentChallenges   *int     `json:"maxConcurrentChallenges,omitempty"`
+
+	DefaultIssuerName                 *string  `json:"defaultIssuerName,omitempty"`
+	DefaultIssuerKind                 *string  `json:"defaultIssuerKind,omitempty"`
+	DefaultIssuerGroup                *string  `json:"defaultIssuerGroup,omitempty"`
+	DefaultAutoCertificateAnnotations []string `json:"defaultAutoCertificateAnnotations,omitempty"`
+}
+
+// LoadConfigFile reads and decodes the configuration file at path. Unknown
+// fields are rejected.
+func LoadConfigFile(path string) (*ReloadableConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig decodes the contents of a configuration file.
+func ParseConfig(data []byte) (*ReloadableConfig, error) {
+	cfg := &ReloadableConfig{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("error decoding config file: %v", err)
+	}
+	return cfg, nil
+}
+
+// RuntimeConfigValues returns the values of the configuration that can be
+// changed whilst the controller is running, taken from cfg if set and from
+// the flags otherwise. cfg may be nil.
+func (o *ControllerOptions) RuntimeConfigValues(cfg *ReloadableConfig) (controller.RuntimeConfigValues, error) {
+	values := controller.RuntimeConfigValues{
+		KubernetesAPIQPS:          o.KubernetesAPIQPS,
+		KubernetesAPIBurst:        o.KubernetesAPIBurst,
+		NumberOfConcurrentWorkers: o.NumberOfConcurrentWorkers,
+		SchedulerOptions: controller.SchedulerOptions{
+			MaxConcurrentChallenges: o.MaxConcurrentChallenges,
+		},
+		IngressShimOptions: controller.IngressShimOptions{
+			DefaultIssuerName:                 o.DefaultIssuerName,
+			DefaultIssuerKind:                 o.DefaultIssuerKind,
+			DefaultIssuerGroup:                o.DefaultIssuerGroup,
+			DefaultAutoCertificateAnnotations: o.DefaultAutoCertificateAnnotations,
+		},
+	}
+
+	if cfg != nil {
+		if cfg.KubernetesAPIQPS != nil {
+			values.KubernetesAPIQPS = *cfg.KubernetesAPIQPS
+		}
+		if cfg.KubernetesAPIBurst != nil {
+			values.KubernetesAPIBurst = *cfg.KubernetesAPIBurst
+		}
+		if cfg.NumberOfConcurrentWorkers != nil {
+			values.NumberOfConcurrentWorkers = *cfg.NumberOfConcurrentWorkers
+		}
+		if cfg.MaxConcurrentChallenges != nil {
+			values.MaxConcurrentChallenges = *cfg.MaxConcurrentChallenges
+		}
+		if cfg.DefaultIssuerName != nil {
+			values.DefaultIssuerName = *cfg.DefaultIssuerName
+		}
+		if cfg.DefaultIssuerKind != nil {
+			values.DefaultIssuerKind = *cfg.DefaultIssuerKind
+		}
+		if cfg.DefaultIssuerGroup != nil {
+			values.DefaultIssuerGroup = *cfg.DefaultIssuerGroup
+		}
+		if cfg.DefaultAutoCertificateAnnotations != nil {
+			values.DefaultAutoCertificateAnnotations = cfg.DefaultAutoCertificateAnnotations
+		}
+	}
+
+	if err := validateRuntimeConfigValues(values); err != nil {
+		return controller.RuntimeConfigValues{}, err
+	}
+
+	return values, nil
+}
+
+func validateRuntimeConfigValues(values controller.RuntimeConfigValues) error {
+	if values.KubernetesAPIQPS <= 0 {
+		return fmt.Errorf("invalid Kubernetes API QPS, must be greater than 0: %v", values.KubernetesAPIQPS)
+	}
+
+	if values.KubernetesAPIBurst <= 0 {
+		return fmt.Errorf("invalid Kubernetes API burst, must be greater than 0: %d", values.KubernetesAPIBurst)
+	}
+
+	if values.NumberOfConcurrentWorkers <= 0 {
+		return fmt.Errorf("invalid number of concurrent workers, must be greater than 0: %d", values.NumberOfConcurrentWorkers)
+	}
+
+	if values.MaxConcurrentChallenges < 0 {
+		return fmt.Errorf("invalid max concurrent challenges: %d", values.MaxConcurrentChallenges)
+	}
+
+	switch values.DefaultIssuerKind {
+	case "Issuer":
+	case "ClusterIssuer":
+	default:
+		return fmt.Errorf("invalid default issuer kind: %v", values.DefaultIssuerKind)
+	}
+
+	return nil
+}
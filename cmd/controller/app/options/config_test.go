@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jetstack/cert-manager/pkg/controller"
+)
+
+func TestRuntimeConfigValues(t *testing.T) {
+	opts := NewControllerOptions()
+	fromFlags := controller.RuntimeConfigValues{
+		KubernetesAPIQPS:          defaultKubernetesAPIQPS,
+		KubernetesAPIBurst:        defaultKubernetesAPIBurst,
+		NumberOfConcurrentWorkers: defaultNumberOfConcurrentWorkers,
+		SchedulerOptions: controller.SchedulerOptions{
+			MaxConcurrentChallenges: defaultMaxConcurrentChallenges,
+		},
+		IngressShimOptions: controller.IngressShimOptions{
+			DefaultIssuerName:                 defaultTLSACMEIssuerName,
+			DefaultIssuerKind:                 defaultTLSACMEIssuerKind,
+			DefaultIssuerGroup:                defaultTLSACMEIssuerGroup,
+			DefaultAutoCertificateAnnotations: defaultAutoCertificateAnnotations,
+		},
+	}
+
+	tests := map[string]struct {
+		config    string
+		expValues func(values *controller.RuntimeConfigValues)
+		expErr    bool
+	}{
+		"an empty file should use the values of the flags": {
+			config:    "",
+			expValues: func(*controller.RuntimeConfigValues) {},
+		},
+		"values set in the file should take precedence over the flags": {
+			config: `
+kubernetesAPIQPS: 50
+kubernetesAPIBurst: 100
+numberOfConcurrentWorkers: 20
+maxConcurrentChallenges: 200
+defaultIssuerName: letsencrypt
+defaultIssuerKind: ClusterIssuer
+defaultIssuerGroup: cert-manager.io
+defaultAutoCertificateAnnotations:
+- example.com/tls
+`,
+			expValues: func(values *controller.RuntimeConfigValues) {
+				values.KubernetesAPIQPS = 50
+				values.KubernetesAPIBurst = 100
+				values.NumberOfConcurrentWorkers = 20
+				values.MaxConcurrentChallenges = 200
+				values.DefaultIssuerName = "letsencrypt"
+				values.DefaultIssuerKind = "ClusterIssuer"
+				values.DefaultIssuerGroup = "cert-manager.io"
+				values.DefaultAutoCertificateAnnotations = []string{"example.com/tls"}
+			},
+		},
+		"unknown fields should be rejected": {
+			config: "numberOfWorkers: 10",
+			expErr: true,
+		},
+		"a non-positive number of workers should be rejected": {
+			config: "numberOfConcurrentWorkers: 0",
+			expErr: true,
+		},
+		"a non-positive QPS should be rejected": {
+			config: "kubernetesAPIQPS: -1",
+			expErr: true,
+		},
+		"an invalid default issuer kind should be rejected": {
+			config: "defaultIssuerKind: Foo",
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := ParseConfig([]byte(test.config))
+			if err == nil {
+				var values controller.RuntimeConfigValues
+				values, err = opts.RuntimeConfigValues(cfg)
+				if err == nil {
+					exp := fromFlags
+					test.expValues(&exp)
+					if !reflect.DeepEqual(exp, values) {
+						t.Errorf("unexpected values, exp=%+v got=%+v", exp, values)
+					}
+				}
+			}
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
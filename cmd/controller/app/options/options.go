@@ -19,12 +19,15 @@ package options
 import (
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	cm "github.com/jetstack/cert-manager/pkg/apis/certmanager"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/controller"
 	challengescontroller "github.com/jetstack/cert-manager/pkg/controller/acmechallenges"
 	orderscontroller "github.com/jetstack/cert-manager/pkg/controller/acmeorders"
 	cracmecontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/acme"
@@ -33,15 +36,22 @@ import (
 	crvaultcontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/vault"
 	crvenaficontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/venafi"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/issuing"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/keyescrow"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/keymanager"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/keysweeper"
 	certificatesmetricscontroller "github.com/jetstack/cert-manager/pkg/controller/certificates/metrics"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/readiness"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/requestmanager"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/revocation"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/staleconsumers"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger"
 	clusterissuerscontroller "github.com/jetstack/cert-manager/pkg/controller/clusterissuers"
 	ingressshimcontroller "github.com/jetstack/cert-manager/pkg/controller/ingress-shim"
 	issuerscontroller "github.com/jetstack/cert-manager/pkg/controller/issuers"
+	"github.com/jetstack/cert-manager/pkg/controller/legacymigration"
+	"github.com/jetstack/cert-manager/pkg/controller/selfstatus"
 	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/pkg/util/cron"
 )
 
 type ControllerOptions struct {
@@ -50,12 +60,32 @@ type ControllerOptions struct {
 	ClusterResourceNamespace string
 	Namespace                string
 
+	// The name of the field manager of the controller's writes. Controllers
+	// that use server-side apply use it as the prefix of their own field
+	// manager. If empty, it is derived from the user agent.
+	FieldOwner string
+
+	// Path to a file holding the configuration that is reloaded without
+	// restarting when it changes. See ReloadableConfig.
+	ConfigFile string
+
+	// The rate of requests made to the apiserver by each clientset.
+	KubernetesAPIQPS   float32
+	KubernetesAPIBurst int
+
+	// The number of items each controller processes concurrently.
+	NumberOfConcurrentWorkers int
+
 	LeaderElect                 bool
 	LeaderElectionNamespace     string
 	LeaderElectionLeaseDuration time.Duration
 	LeaderElectionRenewDeadline time.Duration
 	LeaderElectionRetryPeriod   time.Duration
 
+	// PersistBackoff enables persisting the backoff of failing resources
+	// across restarts
+	PersistBackoff bool
+
 	EnabledControllers []string
 
 	ACMEHTTP01SolverImage                 string
@@ -63,10 +93,22 @@ type ControllerOptions struct {
 	ACMEHTTP01SolverResourceRequestMemory string
 	ACMEHTTP01SolverResourceLimitsCPU     string
 	ACMEHTTP01SolverResourceLimitsMemory  string
+	ACMEHTTP01SolverPropagatedLabels      []string
+
+	// Scheduling settings of all ACME HTTP01 challenge solver pods, which
+	// may be overridden by the pod template of a solver.
+	ACMEHTTP01SolverPriorityClassName  string
+	ACMEHTTP01SolverRuntimeClassName   string
+	ACMEHTTP01SolverTopologySpreadKeys []string
 
 	ClusterIssuerAmbientCredentials bool
 	IssuerAmbientCredentials        bool
 	RenewBeforeExpiryDuration       time.Duration
+	ClusterDomain                   string
+
+	// TrustPodServiceAccountAnnotation enables the serviceAccount function
+	// in the request defaults of CA and SelfSigned issuers.
+	TrustPodServiceAccountAnnotation bool
 
 	// Default issuer/certificates details consumed by ingress-shim
 	DefaultIssuerName                 string
@@ -80,13 +122,101 @@ type ControllerOptions struct {
 	// Normally authoritative nameservers are used for checking propagation.
 	DNS01RecursiveNameserversOnly bool
 
+	// The owner ID of external-dns ownership TXT records written alongside
+	// DNS01 challenge records, and the prefix of their names. Ownership
+	// records are disabled if the owner ID is empty.
+	DNS01ExternalDNSOwnerID   string
+	DNS01ExternalDNSTXTPrefix string
+
 	EnableCertificateOwnerRef bool
 
+	// The name of the Secret in the cluster resource namespace holding the
+	// P-256 private key used to sign attestations of issued certificates.
+	// Attestations are disabled if empty.
+	SecretAttestationKeySecretName string
+
+	// The maximum amount of time the renewal of a certificate is brought
+	// forward by, as a percentage of its renew before duration and as an
+	// absolute duration, to spread out the renewals of certificates issued at
+	// the same time. Jitter is disabled if both are zero.
+	CertificateRenewalJitterPercent int
+	CertificateRenewalJitterMax     time.Duration
+	ValidateCertificates            bool
+
+	// Cron schedules and durations of the windows during which automatic
+	// renewals are deferred, unless the certificate expires within the
+	// threshold.
+	CertificateRenewalFreezeWindows         []string
+	CertificateRenewalFreezeExpiryThreshold time.Duration
+
+	// The amount of time after which temporary 'next private key' Secrets
+	// that are not in use by an issuance in progress are deleted.
+	NextPrivateKeySecretTTL time.Duration
+
+	// Whether to run the controller that migrates resources of the legacy
+	// certmanager.k8s.io API group to cert-manager.io.
+	EnableLegacyMigration bool
+
+	// EnableStaleConsumerDetection enables the controller reporting running
+	// Pods that have not reloaded the certificate of a Certificate
+	EnableStaleConsumerDetection bool
+
+	// EnableKeyEscrow enables the controller delivering the private keys of
+	// Certificates to the key escrow service configured on their issuer
+	EnableKeyEscrow bool
+
+	// EnableSecretConsumerPatches enables applying the patches of the
+	// cert-manager.io/secret-consumer-patches annotation of Certificates
+	EnableSecretConsumerPatches bool
+
 	MaxConcurrentChallenges int
 
+	// The maximum number of times a failed request to an ACME server is retried.
+	ACMEHTTPMaxRetries int
+	// The wait before the first retry of a failed request to an ACME server.
+	ACMEHTTPBackoffBase time.Duration
+	// The maximum wait between two retries of a failed request to an ACME
+	// server.
+	ACMEHTTPBackoffMax time.Duration
+	// The number of consecutive failed requests to an ACME endpoint after
+	// which requests to that endpoint are short-circuited.
+	ACMECircuitBreakerFailureThreshold int
+	// How long requests to a failing ACME endpoint are short-circuited for.
+	ACMECircuitBreakerCooldown time.Duration
+
+	// The number of consecutive requests to a DNS01 provider that fail due
+	// to an outage of the provider after which all challenges using it are
+	// paused.
+	DNS01ProviderOutageThreshold int
+	// How often an unavailable DNS01 provider is probed for recovery.
+	DNS01ProviderOutageProbeInterval time.Duration
+	// Whether the credentials of DNS01 providers are verified when ACME
+	// issuers are set up.
+	DNS01VerifyCredentials bool
+
+	// The address the status API is served on over TLS with the given
+	// serving certificate. The status API is disabled if empty.
+	StatusAPIListenAddress string
+	StatusAPITLSCertFile   string
+	StatusAPITLSKeyFile    string
+
+	// The certificates of cert-manager components checked by the self status
+	// controller. The webhook CA Secret is given as namespace/name.
+	SelfStatusWebhookCASecret string
+	SelfStatusWebhookAddress  string
+	SelfStatusCheckInterval   time.Duration
+
 	// The host and port address, separated by a ':', that the Prometheus server
 	// should expose metrics on.
 	MetricsListenAddress string
+
+	// EventsSink selects where events are recorded, one of "kubernetes" or
+	// "log".
+	EventsSink string
+	// EventsNamespace is the namespace events are recorded in by the
+	// kubernetes events sink. If empty, events are recorded in the namespace
+	// of the resource they are about.
+	EventsNamespace string
 }
 
 const (
@@ -101,20 +231,55 @@ const (
 	defaultLeaderElectionRenewDeadline = 40 * time.Second
 	defaultLeaderElectionRetryPeriod   = 15 * time.Second
 
+	defaultPersistBackoff = true
+
 	defaultClusterIssuerAmbientCredentials = true
 	defaultIssuerAmbientCredentials        = false
 	defaultRenewBeforeExpiryDuration       = cmapi.DefaultRenewBefore
+	defaultClusterDomain                   = "cluster.local"
+
+	defaultTrustPodServiceAccountAnnotation = false
 
 	defaultTLSACMEIssuerName         = ""
 	defaultTLSACMEIssuerKind         = "Issuer"
 	defaultTLSACMEIssuerGroup        = cm.GroupName
 	defaultEnableCertificateOwnerRef = false
+	defaultEnableLegacyMigration     = false
+
+	defaultEnableStaleConsumerDetection = false
+	defaultEnableKeyEscrow              = false
+	defaultEnableSecretConsumerPatches  = false
+
+	defaultSecretAttestationKeySecretName = ""
+
+	defaultCertificateRenewalJitterPercent = 0
+	defaultCertificateRenewalJitterMax     = time.Duration(0)
+
+	defaultCertificateRenewalFreezeExpiryThreshold = time.Hour * 24 * 7
+
+	defaultNextPrivateKeySecretTTL = time.Hour
 
 	defaultDNS01RecursiveNameserversOnly = false
 
 	defaultMaxConcurrentChallenges = 60
 
+	defaultKubernetesAPIQPS          = 5
+	defaultKubernetesAPIBurst        = 10
+	defaultNumberOfConcurrentWorkers = 5
+
+	defaultACMEHTTPMaxRetries                 = 5
+	defaultACMEHTTPBackoffBase                = time.Second
+	defaultACMEHTTPBackoffMax                 = 10 * time.Second
+	defaultACMECircuitBreakerFailureThreshold = 5
+	defaultACMECircuitBreakerCooldown         = 30 * time.Second
+
+	defaultDNS01ProviderOutageThreshold     = 5
+	defaultDNS01ProviderOutageProbeInterval = time.Minute
+	defaultDNS01VerifyCredentials           = true
+
 	defaultPrometheusMetricsServerAddress = "0.0.0.0:9402"
+
+	defaultSelfStatusCheckInterval = time.Minute
 )
 
 var (
@@ -142,33 +307,60 @@ var (
 		trigger.ControllerName,
 		issuing.ControllerName,
 		keymanager.ControllerName,
+		keysweeper.ControllerName,
 		requestmanager.ControllerName,
 		readiness.ControllerName,
+		revocation.ControllerName,
 	}
 )
 
 func NewControllerOptions() *ControllerOptions {
 	return &ControllerOptions{
-		APIServerHost:                     defaultAPIServerHost,
-		ClusterResourceNamespace:          defaultClusterResourceNamespace,
-		Namespace:                         defaultNamespace,
-		LeaderElect:                       defaultLeaderElect,
-		LeaderElectionNamespace:           defaultLeaderElectionNamespace,
-		LeaderElectionLeaseDuration:       defaultLeaderElectionLeaseDuration,
-		LeaderElectionRenewDeadline:       defaultLeaderElectionRenewDeadline,
-		LeaderElectionRetryPeriod:         defaultLeaderElectionRetryPeriod,
-		EnabledControllers:                defaultEnabledControllers,
-		ClusterIssuerAmbientCredentials:   defaultClusterIssuerAmbientCredentials,
-		IssuerAmbientCredentials:          defaultIssuerAmbientCredentials,
-		RenewBeforeExpiryDuration:         defaultRenewBeforeExpiryDuration,
-		DefaultIssuerName:                 defaultTLSACMEIssuerName,
-		DefaultIssuerKind:                 defaultTLSACMEIssuerKind,
-		DefaultIssuerGroup:                defaultTLSACMEIssuerGroup,
-		DefaultAutoCertificateAnnotations: defaultAutoCertificateAnnotations,
-		DNS01RecursiveNameservers:         []string{},
-		DNS01RecursiveNameserversOnly:     defaultDNS01RecursiveNameserversOnly,
-		EnableCertificateOwnerRef:         defaultEnableCertificateOwnerRef,
-		MetricsListenAddress:              defaultPrometheusMetricsServerAddress,
+		APIServerHost:                           defaultAPIServerHost,
+		ClusterResourceNamespace:                defaultClusterResourceNamespace,
+		Namespace:                               defaultNamespace,
+		KubernetesAPIQPS:                        defaultKubernetesAPIQPS,
+		KubernetesAPIBurst:                      defaultKubernetesAPIBurst,
+		NumberOfConcurrentWorkers:               defaultNumberOfConcurrentWorkers,
+		LeaderElect:                             defaultLeaderElect,
+		LeaderElectionNamespace:                 defaultLeaderElectionNamespace,
+		LeaderElectionLeaseDuration:             defaultLeaderElectionLeaseDuration,
+		LeaderElectionRenewDeadline:             defaultLeaderElectionRenewDeadline,
+		LeaderElectionRetryPeriod:               defaultLeaderElectionRetryPeriod,
+		PersistBackoff:                          defaultPersistBackoff,
+		EnabledControllers:                      defaultEnabledControllers,
+		ClusterIssuerAmbientCredentials:         defaultClusterIssuerAmbientCredentials,
+		IssuerAmbientCredentials:                defaultIssuerAmbientCredentials,
+		RenewBeforeExpiryDuration:               defaultRenewBeforeExpiryDuration,
+		ClusterDomain:                           defaultClusterDomain,
+		TrustPodServiceAccountAnnotation:        defaultTrustPodServiceAccountAnnotation,
+		DefaultIssuerName:                       defaultTLSACMEIssuerName,
+		DefaultIssuerKind:                       defaultTLSACMEIssuerKind,
+		DefaultIssuerGroup:                      defaultTLSACMEIssuerGroup,
+		DefaultAutoCertificateAnnotations:       defaultAutoCertificateAnnotations,
+		DNS01RecursiveNameservers:               []string{},
+		DNS01RecursiveNameserversOnly:           defaultDNS01RecursiveNameserversOnly,
+		EnableCertificateOwnerRef:               defaultEnableCertificateOwnerRef,
+		SecretAttestationKeySecretName:          defaultSecretAttestationKeySecretName,
+		CertificateRenewalJitterPercent:         defaultCertificateRenewalJitterPercent,
+		CertificateRenewalJitterMax:             defaultCertificateRenewalJitterMax,
+		CertificateRenewalFreezeExpiryThreshold: defaultCertificateRenewalFreezeExpiryThreshold,
+		NextPrivateKeySecretTTL:                 defaultNextPrivateKeySecretTTL,
+		EnableLegacyMigration:                   defaultEnableLegacyMigration,
+		EnableStaleConsumerDetection:            defaultEnableStaleConsumerDetection,
+		EnableKeyEscrow:                         defaultEnableKeyEscrow,
+		EnableSecretConsumerPatches:             defaultEnableSecretConsumerPatches,
+		MetricsListenAddress:                    defaultPrometheusMetricsServerAddress,
+		ACMEHTTPMaxRetries:                      defaultACMEHTTPMaxRetries,
+		ACMEHTTPBackoffBase:                     defaultACMEHTTPBackoffBase,
+		ACMEHTTPBackoffMax:                      defaultACMEHTTPBackoffMax,
+		ACMECircuitBreakerFailureThreshold:      defaultACMECircuitBreakerFailureThreshold,
+		ACMECircuitBreakerCooldown:              defaultACMECircuitBreakerCooldown,
+		DNS01ProviderOutageThreshold:            defaultDNS01ProviderOutageThreshold,
+		DNS01ProviderOutageProbeInterval:        defaultDNS01ProviderOutageProbeInterval,
+		DNS01VerifyCredentials:                  defaultDNS01VerifyCredentials,
+		EventsSink:                              controller.EventsSinkKubernetes,
+		SelfStatusCheckInterval:                 defaultSelfStatusCheckInterval,
 	}
 }
 
@@ -184,6 +376,23 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.Namespace, "namespace", defaultNamespace, ""+
 		"If set, this limits the scope of cert-manager to a single namespace and ClusterIssuers are disabled. "+
 		"If not specified, all namespaces will be watched")
+	fs.StringVar(&s.FieldOwner, "field-owner", "", ""+
+		"The name of the field manager recorded in the managed fields of resources written by the controller, "+
+		"e.g. to tell its changes apart from those of GitOps tools. If the ServerSideApply feature gate is enabled, "+
+		"the Secrets of Certificates, the status of cert-manager resources, the Certificates of ingress-shim and "+
+		"the challenge solver Ingresses are written with server-side apply, using '<field-owner>-<controller>' as "+
+		"their field manager. If not specified, the name of the controller binary is used.")
+	fs.StringVar(&s.ConfigFile, "config", "", ""+
+		"Path to a YAML file configuring the Kubernetes API rate limits, the number of concurrent workers, "+
+		"the maximum number of concurrent challenges and the ingress-shim default issuer. Values set in the "+
+		"file take precedence over the corresponding flags. The file is checked for changes every 10 seconds "+
+		"and changes are applied without restarting.")
+	fs.Float32Var(&s.KubernetesAPIQPS, "kube-api-qps", defaultKubernetesAPIQPS, ""+
+		"The maximum number of queries per second made to the Kubernetes apiserver by each client.")
+	fs.IntVar(&s.KubernetesAPIBurst, "kube-api-burst", defaultKubernetesAPIBurst, ""+
+		"The maximum burst of queries made to the Kubernetes apiserver by each client.")
+	fs.IntVar(&s.NumberOfConcurrentWorkers, "concurrent-workers", defaultNumberOfConcurrentWorkers, ""+
+		"The number of items each controller processes concurrently.")
 	fs.BoolVar(&s.LeaderElect, "leader-elect", true, ""+
 		"If true, cert-manager will perform leader election between instances to ensure no more "+
 		"than one instance of cert-manager operates at a time")
@@ -203,6 +412,11 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 		"The duration the clients should wait between attempting acquisition and renewal "+
 		"of a leadership. This is only applicable if leader election is enabled.")
 
+	fs.BoolVar(&s.PersistBackoff, "persist-backoff", defaultPersistBackoff, ""+
+		"If true, the backoff of resources that failed to be processed is persisted in the "+
+		"ConfigMap 'cert-manager-controller-backoff' in the leader election namespace, so that "+
+		"they are not retried straight away when the controller restarts.")
+
 	fs.StringSliceVar(&s.EnabledControllers, "controllers", defaultEnabledControllers, ""+
 		"The set of controllers to enable.")
 
@@ -222,6 +436,21 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.ACMEHTTP01SolverResourceLimitsMemory, "acme-http01-solver-resource-limits-memory", defaultACMEHTTP01SolverResourceLimitsMemory, ""+
 		"Defines the resource limits Memory size when spawning new ACME HTTP01 challenge solver pods.")
 
+	fs.StringSliceVar(&s.ACMEHTTP01SolverPropagatedLabels, "acme-http01-solver-propagated-labels", []string{}, ""+
+		"The set of label keys that are copied from a Certificate onto the ACME HTTP01 challenge solver "+
+		"pods, services and ingresses created for it, e.g. to attribute their cost to a team. "+
+		"CertificateRequests, Orders and Challenges always carry all of the Certificate's labels.")
+	fs.StringVar(&s.ACMEHTTP01SolverPriorityClassName, "acme-http01-solver-priority-class-name", "", ""+
+		"The priority class of ACME HTTP01 challenge solver pods, e.g. so that they are not evicted under node "+
+		"pressure. The 'priorityClassName' of the pod template of a solver takes precedence.")
+	fs.StringVar(&s.ACMEHTTP01SolverRuntimeClassName, "acme-http01-solver-runtime-class-name", "", ""+
+		"The runtime class of ACME HTTP01 challenge solver pods. The 'runtimeClassName' of the pod template "+
+		"of a solver takes precedence.")
+	fs.StringSliceVar(&s.ACMEHTTP01SolverTopologySpreadKeys, "acme-http01-solver-topology-spread-keys", []string{}, ""+
+		"The node label keys, e.g. 'topology.kubernetes.io/zone', across whose values ACME HTTP01 challenge solver "+
+		"pods are spread on a best-effort basis. The 'topologySpreadConstraints' of the pod template of a solver "+
+		"are added to these.")
+
 	fs.BoolVar(&s.ClusterIssuerAmbientCredentials, "cluster-issuer-ambient-credentials", defaultClusterIssuerAmbientCredentials, ""+
 		"Whether a cluster-issuer may make use of ambient credentials for issuers. 'Ambient Credentials' are credentials drawn from the environment, metadata services, or local files which are not explicitly configured in the ClusterIssuer API object. "+
 		"When this flag is enabled, the following sources for credentials are also used: "+
@@ -234,6 +463,14 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 		"The default 'renew before expiry' time for Certificates. "+
 		"Once a certificate is within this duration until expiry, a new Certificate "+
 		"will be attempted to be issued.")
+	fs.StringVar(&s.ClusterDomain, "cluster-domain", defaultClusterDomain, ""+
+		"The domain of the cluster, returned by the 'clusterDomain' function in the request defaults of "+
+		"CA and SelfSigned issuers.")
+	fs.BoolVar(&s.TrustPodServiceAccountAnnotation, "trust-pod-service-account-annotation", defaultTrustPodServiceAccountAnnotation, ""+
+		"If true, the 'serviceAccount' function in the request defaults of CA and SelfSigned issuers returns the "+
+		"service account in the cert-manager.io/pod-service-account annotation of CertificateRequests. "+
+		"Only set this if the webhook is run with --enforce-pod-identity, which verifies the annotation "+
+		"against the identity of the Pod the CertificateRequest was created for at admission.")
 	fs.StringSliceVar(&s.DefaultAutoCertificateAnnotations, "auto-certificate-annotations", defaultAutoCertificateAnnotations, ""+
 		"The annotation consumed by the ingress-shim controller to indicate a ingress is requesting a certificate")
 
@@ -259,22 +496,229 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 			"DNS01 check requests. This should be a list containing host and port, "+
 			"for example 8.8.8.8:53,8.8.4.4:53")
 	fs.MarkDeprecated("dns01-self-check-nameservers", "Deprecated in favour of dns01-recursive-nameservers")
+	fs.StringVar(&s.DNS01ExternalDNSOwnerID, "dns01-external-dns-owner-id", "", ""+
+		"If set, an external-dns ownership TXT record with this owner ID is written alongside each DNS01 "+
+		"challenge record, so that external-dns instances managing the same zone with a different owner ID "+
+		"leave the challenge records alone. Requires --dns01-external-dns-txt-prefix.")
+	fs.StringVar(&s.DNS01ExternalDNSTXTPrefix, "dns01-external-dns-txt-prefix", "", ""+
+		"The prefix of the names of external-dns ownership TXT records, which must match the --txt-prefix "+
+		"external-dns is run with. A prefix is required, as the ownership record would otherwise share its "+
+		"name with the challenge record.")
 	fs.BoolVar(&s.EnableCertificateOwnerRef, "enable-certificate-owner-ref", defaultEnableCertificateOwnerRef, ""+
 		"Whether to set the certificate resource as an owner of secret where the tls certificate is stored. "+
-		"When this flag is enabled, the secret will be automatically removed when the certificate resource is deleted.")
+		"When this flag is enabled, the secret will be automatically removed when the certificate resource is deleted, "+
+		"unless a certificate resource with the same name is re-created within an hour, in which case it re-uses the "+
+		"certificate stored in the secret if it is still up to date.")
+	fs.StringVar(&s.SecretAttestationKeySecretName, "secret-attestation-key-secret-name", defaultSecretAttestationKeySecretName, ""+
+		"The name of a Secret in the cluster resource namespace holding a PEM encoded P-256 private key in its "+
+		"'tls.key' entry. If set, the Secrets of issued certificates are annotated with an attestation signed "+
+		"with this key, binding the certificate to its Certificate resource, issuer and the controller version. "+
+		"Attestations can be verified with 'kubectl cert-manager verify secret'.")
+	fs.IntVar(&s.CertificateRenewalJitterPercent, "certificate-renewal-jitter-percent", defaultCertificateRenewalJitterPercent, ""+
+		"The maximum percentage of the renew before duration of a certificate that its renewal is brought forward by. "+
+		"The amount is derived from the Certificate and its serial number, spreading out the renewals of certificates "+
+		"issued at the same time. If set together with --certificate-renewal-jitter-max, the smaller of the two is used.")
+	fs.DurationVar(&s.CertificateRenewalJitterMax, "certificate-renewal-jitter-max", defaultCertificateRenewalJitterMax, ""+
+		"The maximum amount of time the renewal of a certificate is brought forward by, e.g. 6h. "+
+		"If set together with --certificate-renewal-jitter-percent, the smaller of the two is used.")
+	fs.StringArrayVar(&s.CertificateRenewalFreezeWindows, "certificate-renewal-freeze-window", []string{}, ""+
+		"A window of time during which the automatic renewal of certificates is deferred until the window closes, "+
+		"e.g. to comply with a change freeze. The window is given as a 5 field cron schedule at which the window opens, "+
+		"followed by its duration, e.g. '0 0 20 12 * 336h' for a window from the 20th of December until the 3rd of "+
+		"January. Schedules are evaluated in UTC unless prefixed with 'CRON_TZ=<zone>'. May be specified multiple times. "+
+		"Certificates that have expired, or whose spec or Secret are out of date, are still issued during a window.")
+	fs.DurationVar(&s.CertificateRenewalFreezeExpiryThreshold, "certificate-renewal-freeze-expiry-threshold", defaultCertificateRenewalFreezeExpiryThreshold, ""+
+		"Certificates that expire within this duration are renewed even if a renewal freeze window is open.")
+	fs.DurationVar(&s.NextPrivateKeySecretTTL, "next-private-key-secret-ttl", defaultNextPrivateKeySecretTTL, ""+
+		"The amount of time after which the temporary Secrets holding the private key of an issuance are deleted "+
+		"if they are not in use by an issuance in progress, e.g. because the issuance was aborted or the "+
+		"Certificate was deleted or paused.")
+	fs.BoolVar(&s.ValidateCertificates, "validate-certificates", false, ""+
+		"Whether to validate Certificates when they are reconciled, reporting invalid Certificates as not Ready "+
+		"instead of issuing them. This should be enabled if the webhook, which otherwise rejects invalid "+
+		"Certificates, is not installed. Issuers, ClusterIssuers and CertificateRequests are always validated.")
+	fs.BoolVar(&s.EnableLegacyMigration, "enable-legacy-migration", defaultEnableLegacyMigration, ""+
+		"Whether to run the controller that converts Certificates, Issuers and ClusterIssuers of the "+
+		"legacy certmanager.k8s.io API group, and the annotations on Ingresses, to their cert-manager.io "+
+		"equivalent. The progress of the migration is reported in the '"+legacymigration.StatusConfigMapName+"' "+
+		"ConfigMap in the cluster resource namespace.")
+	fs.BoolVar(&s.EnableStaleConsumerDetection, "enable-stale-consumer-detection", defaultEnableStaleConsumerDetection, ""+
+		"Whether to run the '"+staleconsumers.ControllerName+"' controller, which reports running Pods that "+
+		"mount the Secret of a Certificate but were started before the certificate in it was issued, and so "+
+		"may not have reloaded it. They are counted by the 'certificate_stale_consumers' metric and named in "+
+		"the '"+string(cmapi.CertificateConditionStaleConsumers)+"' condition of the Certificate. The controller "+
+		"watches all Pods in the namespaces watched by cert-manager.")
+	fs.BoolVar(&s.EnableKeyEscrow, "enable-key-escrow", defaultEnableKeyEscrow, ""+
+		"Whether to run the '"+keyescrow.ControllerName+"' controller, which delivers the private keys of "+
+		"Certificates to the key escrow service configured in the 'keyEscrow' field of their Issuer or "+
+		"ClusterIssuer. Unless it is enabled, private keys are never sent outside of the cluster.")
+	fs.BoolVar(&s.EnableSecretConsumerPatches, "enable-secret-consumer-patches", defaultEnableSecretConsumerPatches, ""+
+		"Whether to apply the patches of the '"+cmapi.SecretConsumerPatchesAnnotationKey+"' annotation of "+
+		"Certificates once a new certificate has been stored in their Secret. Only Deployments, StatefulSets "+
+		"and DaemonSets of the apps/v1 API in the namespace of the Certificate can be patched, and the "+
+		"controller must be granted the permission to patch them.")
 	fs.IntVar(&s.MaxConcurrentChallenges, "max-concurrent-challenges", defaultMaxConcurrentChallenges, ""+
 		"The maximum number of challenges that can be scheduled as 'processing' at once.")
 
+	fs.IntVar(&s.ACMEHTTPMaxRetries, "acme-http-max-retries", defaultACMEHTTPMaxRetries, ""+
+		"The maximum number of times a request to an ACME server that failed due to a server error "+
+		"is retried. Requests rejected with a 'badNonce' error are retried separately.")
+	fs.DurationVar(&s.ACMEHTTPBackoffBase, "acme-http-backoff-base", defaultACMEHTTPBackoffBase, ""+
+		"The amount of time waited before the first retry of a request to an ACME server that failed due "+
+		"to a server error. The wait is doubled for every further retry.")
+	fs.DurationVar(&s.ACMEHTTPBackoffMax, "acme-http-backoff-max", defaultACMEHTTPBackoffMax, ""+
+		"The maximum amount of time waited between two retries of a request to an ACME server.")
+	fs.IntVar(&s.ACMECircuitBreakerFailureThreshold, "acme-circuit-breaker-failure-threshold", defaultACMECircuitBreakerFailureThreshold, ""+
+		"The number of consecutive failed requests to an ACME endpoint after which further requests "+
+		"to that endpoint fail immediately for the cooldown period. Set to 0 to disable circuit breaking.")
+	fs.DurationVar(&s.ACMECircuitBreakerCooldown, "acme-circuit-breaker-cooldown", defaultACMECircuitBreakerCooldown, ""+
+		"The amount of time requests to a failing ACME endpoint fail immediately before a trial request is sent.")
+
+	fs.IntVar(&s.DNS01ProviderOutageThreshold, "dns01-provider-outage-threshold", defaultDNS01ProviderOutageThreshold, ""+
+		"The number of consecutive requests to a DNS01 provider failing due to authentication errors, server "+
+		"errors or the provider being unreachable after which all challenges using the provider are paused with "+
+		"a ProviderUnavailable condition until it recovers. Set to 0 to disable outage detection.")
+	fs.DurationVar(&s.DNS01ProviderOutageProbeInterval, "dns01-provider-outage-probe-interval", defaultDNS01ProviderOutageProbeInterval, ""+
+		"How often a single challenge is let through to an unavailable DNS01 provider to check if it has recovered.")
+	fs.BoolVar(&s.DNS01VerifyCredentials, "dns01-verify-credentials", defaultDNS01VerifyCredentials, ""+
+		"If true, the credentials of the DNS01 providers of ACME issuers are verified against the API of the "+
+		"provider, e.g. by listing zones, when the issuer is set up. Issuers with credentials that are rejected "+
+		"are marked as not Ready. Providers that cannot be verified without changing records are not checked.")
+
 	fs.StringVar(&s.MetricsListenAddress, "metrics-listen-address", defaultPrometheusMetricsServerAddress, ""+
 		"The host and port that the metrics endpoint should listen on.")
+
+	fs.StringVar(&s.StatusAPIListenAddress, "status-api-listen-address", "", ""+
+		"The host and port the read-only status API, serving the status of Certificates as collected by "+
+		"'kubectl cert-manager status certificate' as JSON, listens on. Requests must carry a bearer token "+
+		"of a user that may get or list the Certificates, and only the parts of the status collected from "+
+		"resources the user may read are returned. Lists return at most 100 Certificates per page. Every "+
+		"replica serves the status API, whether it is the leader or not. The status API is disabled if empty.")
+	fs.StringVar(&s.StatusAPITLSCertFile, "status-api-tls-cert-file", "", ""+
+		"Path to the serving certificate of the status API. Changes to the file are picked up without restarting.")
+	fs.StringVar(&s.StatusAPITLSKeyFile, "status-api-tls-key-file", "", ""+
+		"Path to the private key of the serving certificate of the status API.")
+
+	fs.StringVar(&s.SelfStatusWebhookCASecret, "self-status-webhook-ca-secret", "", ""+
+		"The namespace/name of the Secret holding the CA of the webhook, whose expiry is reported by the "+
+		"'"+selfstatus.ControllerName+"' controller.")
+	fs.StringVar(&s.SelfStatusWebhookAddress, "self-status-webhook-address", "", ""+
+		"The host and port of the webhook service, which the '"+selfstatus.ControllerName+"' controller connects "+
+		"to in order to report the expiry of the serving certificate the webhook actually presents.")
+	fs.DurationVar(&s.SelfStatusCheckInterval, "self-status-check-interval", defaultSelfStatusCheckInterval, ""+
+		"How often the certificates of cert-manager components are checked. The '"+selfstatus.ControllerName+"' "+
+		"controller runs if the webhook CA Secret, the webhook address or the status API serving certificate is "+
+		"set, and reports their expiry and rotation state as metrics and as conditions on the '"+selfstatus.SelfStatusName+"' "+
+		"SelfStatus resource.")
+
+	fs.StringVar(&s.EventsSink, "events-sink", controller.EventsSinkKubernetes, ""+
+		"Where events are recorded: 'kubernetes' records Event resources in the API server, 'log' only writes "+
+		"events to the log of the controller. Events can be reduced for single Certificates with the "+
+		"'"+cmapi.EventVerbosityAnnotationKey+"' annotation.")
+	fs.StringVar(&s.EventsNamespace, "events-namespace", "", ""+
+		"Namespace that all events are recorded in, rather than in the namespace of the resource they are about. "+
+		"Keeps the volume of events recorded by cert-manager from hitting the event rate limits of other namespaces. "+
+		"Only used with the 'kubernetes' events sink.")
 }
 
 func (o *ControllerOptions) Validate() error {
-	switch o.DefaultIssuerKind {
-	case "Issuer":
-	case "ClusterIssuer":
+	if _, err := o.RuntimeConfigValues(nil); err != nil {
+		return err
+	}
+
+	if o.ConfigFile != "" {
+		cfg, err := LoadConfigFile(o.ConfigFile)
+		if err != nil {
+			return fmt.Errorf("invalid config file %q: %v", o.ConfigFile, err)
+		}
+		if _, err := o.RuntimeConfigValues(cfg); err != nil {
+			return fmt.Errorf("invalid config file %q: %v", o.ConfigFile, err)
+		}
+	}
+
+	if strings.Contains(o.FieldOwner, "/") {
+		return fmt.Errorf("invalid field owner %q, must not contain '/'", o.FieldOwner)
+	}
+
+	if o.ACMEHTTPMaxRetries < 0 {
+		return fmt.Errorf("invalid ACME HTTP max retries: %d", o.ACMEHTTPMaxRetries)
+	}
+
+	if o.CertificateRenewalJitterPercent < 0 || o.CertificateRenewalJitterPercent > 100 {
+		return fmt.Errorf("invalid certificate renewal jitter percent, must be between 0 and 100: %d", o.CertificateRenewalJitterPercent)
+	}
+
+	if o.CertificateRenewalJitterMax < 0 {
+		return fmt.Errorf("invalid certificate renewal jitter max: %s", o.CertificateRenewalJitterMax)
+	}
+
+	if _, err := cron.ParseWindows(o.CertificateRenewalFreezeWindows); err != nil {
+		return fmt.Errorf("invalid certificate renewal freeze window: %v", err)
+	}
+
+	if o.CertificateRenewalFreezeExpiryThreshold < 0 {
+		return fmt.Errorf("invalid certificate renewal freeze expiry threshold: %s", o.CertificateRenewalFreezeExpiryThreshold)
+	}
+
+	if o.NextPrivateKeySecretTTL <= 0 {
+		return fmt.Errorf("invalid next private key secret ttl, must be positive: %s", o.NextPrivateKeySecretTTL)
+	}
+
+	if o.ACMEHTTPBackoffBase <= 0 {
+		return fmt.Errorf("invalid ACME HTTP backoff base, must be positive: %s", o.ACMEHTTPBackoffBase)
+	}
+
+	if o.ACMEHTTPBackoffMax < o.ACMEHTTPBackoffBase {
+		return fmt.Errorf("invalid ACME HTTP backoff max %s, must not be less than the backoff base %s", o.ACMEHTTPBackoffMax, o.ACMEHTTPBackoffBase)
+	}
+
+	if o.ACMECircuitBreakerFailureThreshold < 0 {
+		return fmt.Errorf("invalid ACME circuit breaker failure threshold: %d", o.ACMECircuitBreakerFailureThreshold)
+	}
+
+	if o.DNS01ProviderOutageThreshold < 0 {
+		return fmt.Errorf("invalid DNS01 provider outage threshold: %d", o.DNS01ProviderOutageThreshold)
+	}
+
+	if o.DNS01ProviderOutageProbeInterval <= 0 {
+		return fmt.Errorf("invalid DNS01 provider outage probe interval, must be positive: %s", o.DNS01ProviderOutageProbeInterval)
+	}
+
+	if o.DNS01ExternalDNSOwnerID != "" && o.DNS01ExternalDNSTXTPrefix == "" {
+		return fmt.Errorf("--dns01-external-dns-txt-prefix must be set if --dns01-external-dns-owner-id is set")
+	}
+
+	for _, key := range o.ACMEHTTP01SolverTopologySpreadKeys {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid ACME HTTP01 solver topology spread key %q: %s", key, strings.Join(errs, ", "))
+		}
+	}
+
+	for _, key := range o.ACMEHTTP01SolverPropagatedLabels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid ACME HTTP01 solver propagated label %q: %s", key, strings.Join(errs, ", "))
+		}
+	}
+
+	if o.StatusAPIListenAddress != "" && (o.StatusAPITLSCertFile == "" || o.StatusAPITLSKeyFile == "") {
+		return fmt.Errorf("--status-api-tls-cert-file and --status-api-tls-key-file must be set if --status-api-listen-address is set")
+	}
+
+	if o.SelfStatusWebhookCASecret != "" && len(strings.Split(o.SelfStatusWebhookCASecret, "/")) != 2 {
+		return fmt.Errorf("invalid --self-status-webhook-ca-secret %q, must be of the form namespace/name", o.SelfStatusWebhookCASecret)
+	}
+	if o.SelfStatusCheckInterval <= 0 {
+		return fmt.Errorf("invalid self status check interval, must be positive: %s", o.SelfStatusCheckInterval)
+	}
+
+	switch o.EventsSink {
+	case controller.EventsSinkKubernetes:
+	case controller.EventsSinkLog:
+		if o.EventsNamespace != "" {
+			return fmt.Errorf("--events-namespace cannot be set with the %q events sink", o.EventsSink)
+		}
 	default:
-		return fmt.Errorf("invalid default issuer kind: %v", o.DefaultIssuerKind)
+		return fmt.Errorf("invalid events sink %q, must be one of %q or %q", o.EventsSink, controller.EventsSinkKubernetes, controller.EventsSinkLog)
 	}
 
 	for _, server := range o.DNS01RecursiveNameservers {
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// dynamicRateLimiter is a token bucket rate limiter for Kubernetes clients
+// whose rate and burst can be changed after the clients have been created.
+// Changing the limits replaces the underlying token bucket, so requests
+// already waiting for a token are admitted at the previous rate.
+type dynamicRateLimiter struct {
+	lock     sync.RWMutex
+	qps      float32
+	burst    int
+	delegate flowcontrol.RateLimiter
+}
+
+var _ flowcontrol.RateLimiter = &dynamicRateLimiter{}
+
+func newDynamicRateLimiter(qps float32, burst int) *dynamicRateLimiter {
+	return &dynamicRateLimiter{
+		qps:      qps,
+		burst:    burst,
+		delegate: flowcontrol.NewTokenBucketRateLimiter(qps, burst),
+	}
+}
+
+// SetLimits changes the rate and burst of the rate limiter.
+func (d *dynamicRateLimiter) SetLimits(qps float32, burst int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if qps == d.qps && burst == d.burst {
+		return
+	}
+	d.qps = qps
+	d.burst = burst
+	d.delegate = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+}
+
+func (d *dynamicRateLimiter) get() flowcontrol.RateLimiter {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.delegate
+}
+
+func (d *dynamicRateLimiter) TryAccept() bool {
+	return d.get().TryAccept()
+}
+
+func (d *dynamicRateLimiter) Accept() {
+	d.get().Accept()
+}
+
+func (d *dynamicRateLimiter) Stop() {
+	d.get().Stop()
+}
+
+func (d *dynamicRateLimiter) QPS() float32 {
+	return d.get().QPS()
+}
+
+func (d *dynamicRateLimiter) Wait(ctx context.Context) error {
+	return d.get().Wait(ctx)
+}
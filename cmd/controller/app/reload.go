@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/jetstack/cert-manager/cmd/controller/app/options"
+	"github.com/jetstack/cert-manager/pkg/controller"
+)
+
+// configReloadInterval is how often the configuration file is checked for
+// changes
+const configReloadInterval = time.Second * 10
+
+// configReloader applies changes made to the configuration file to the
+// runtime configuration of the controller.
+type configReloader struct {
+	opts          *options.ControllerOptions
+	runtimeConfig *controller.RuntimeConfig
+	log           logr.Logger
+
+	cachedData []byte
+}
+
+// Run checks the configuration file for changes until stopCh is closed. If
+// the file cannot be read or is invalid, the current configuration is kept.
+func (r *configReloader) Run(stopCh <-chan struct{}) {
+	wait.Until(func() {
+		values, changed, err := r.load()
+		if err != nil {
+			r.log.Error(err, "failed to reload config file, keeping the current configuration", "path", r.opts.ConfigFile)
+			return
+		}
+		if !changed {
+			return
+		}
+		r.log.Info("detected config file has changed, applying the new configuration", "path", r.opts.ConfigFile)
+		r.runtimeConfig.Set(values)
+	}, configReloadInterval, stopCh)
+}
+
+// load reads the configuration file and returns the resulting runtime
+// configuration, and whether the file has changed since it was last loaded.
+// Invalid contents are only reported once.
+func (r *configReloader) load() (controller.RuntimeConfigValues, bool, error) {
+	data, err := ioutil.ReadFile(r.opts.ConfigFile)
+	if err != nil {
+		return controller.RuntimeConfigValues{}, false, fmt.Errorf("error reading config file: %v", err)
+	}
+
+	if r.cachedData != nil && bytes.Equal(data, r.cachedData) {
+		return controller.RuntimeConfigValues{}, false, nil
+	}
+	r.cachedData = data
+
+	cfg, err := options.ParseConfig(data)
+	if err != nil {
+		return controller.RuntimeConfigValues{}, false, err
+	}
+
+	values, err := r.opts.RuntimeConfigValues(cfg)
+	if err != nil {
+		return controller.RuntimeConfigValues{}, false, err
+	}
+
+	return values, true, nil
+}
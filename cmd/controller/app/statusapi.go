@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+
+	"github.com/jetstack/cert-manager/cmd/controller/app/options"
+	"github.com/jetstack/cert-manager/pkg/controller"
+	ctlclients "github.com/jetstack/cert-manager/pkg/ctl/clients"
+	ctlstatus "github.com/jetstack/cert-manager/pkg/ctl/status"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/statusapi"
+	servertls "github.com/jetstack/cert-manager/pkg/webhook/server/tls"
+)
+
+const (
+	statusAPIReadTimeout     = 10 * time.Second
+	statusAPIWriteTimeout    = 60 * time.Second
+	statusAPIShutdownTimeout = 5 * time.Second
+)
+
+// startStatusAPI serves the status API over TLS until stopCh is closed.
+// Every replica of the controller serves the status API, including replicas
+// that are not the leader, as it only reads resources and holds no state
+// that needs to be consistent between replicas. The Service of the status
+// API therefore balances requests across all replicas.
+func startStatusAPI(ctx *controller.Context, opts *options.ControllerOptions, stopCh <-chan struct{}) error {
+	log := logf.FromContext(ctx.RootContext, "status-api")
+
+	dynamicClient, err := dynamic.NewForConfig(ctx.RESTConfig)
+	if err != nil {
+		return fmt.Errorf("error creating dynamic client: %v", err)
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(ctx.Client.Discovery()))
+
+	source := &servertls.FileCertificateSource{
+		CertPath: opts.StatusAPITLSCertFile,
+		KeyPath:  opts.StatusAPITLSKeyFile,
+		Log:      log,
+	}
+	go func() {
+		if err := source.Run(stopCh); err != nil {
+			log.Error(err, "failed to reload status API serving certificate")
+		}
+	}()
+
+	listener, err := net.Listen("tcp", opts.StatusAPIListenAddress)
+	if err != nil {
+		return fmt.Errorf("error listening on %q: %v", opts.StatusAPIListenAddress, err)
+	}
+
+	server := &http.Server{
+		ReadTimeout:  statusAPIReadTimeout,
+		WriteTimeout: statusAPIWriteTimeout,
+		TLSConfig: &tls.Config{
+			GetCertificate: source.GetCertificate,
+			MinVersion:     tls.VersionTLS12,
+		},
+		Handler: statusapi.NewServer(log, ctlstatus.Clients{
+			Kube:       ctx.Client,
+			CM:         ctx.CMClient,
+			Dynamic:    dynamicClient,
+			RESTMapper: mapper,
+		}, ctx.Namespace, ctlclients.DefaultChunkSize, statusapi.NewKubeAuthorizer(ctx.Client)),
+	}
+
+	go func() {
+		<-stopCh
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), statusAPIShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Error(err, "error shutting down status API")
+		}
+	}()
+
+	go func() {
+		log.Info("serving status API", "address", listener.Addr().String())
+		if err := server.ServeTLS(listener, "", ""); err != http.ErrServerClosed {
+			log.Error(err, "error serving status API")
+		}
+	}()
+
+	return nil
+}
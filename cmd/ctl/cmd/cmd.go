@@ -30,11 +30,26 @@ import (
 	"k8s.io/klog"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/approve"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/benchmark"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/check"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/completion"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/convert"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/create"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/experimental"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/explain"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/externalsigning"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/inspect"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/pause"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/rekey"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/renew"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/report"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/rotate"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/status"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/validate"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/verify"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/version"
+	"github.com/jetstack/cert-manager/pkg/ctl/output"
 )
 
 func NewCertManagerCtlCommand(in io.Reader, out, err io.Writer, stopCh <-chan struct{}) *cobra.Command {
@@ -42,10 +57,22 @@ func NewCertManagerCtlCommand(in io.Reader, out, err io.Writer, stopCh <-chan st
 		Use:   "cert-manager",
 		Short: "cert-manager CLI tool to manage and configure cert-manager resources",
 		Long: `
-kubectl cert-manager is a CLI tool manage and configure cert-manager resources for Kubernetes`,
+kubectl cert-manager is a CLI tool manage and configure cert-manager resources for Kubernetes
+
+The cluster and user are selected like with kubectl, using the global flags like --kubeconfig, --context, --cluster,
+--as and --as-group, which apply to every command. Reporting commands additionally accept --contexts to audit the
+clusters of several kubeconfig contexts in one invocation.`,
 	}
 	cmds.SetUsageTemplate(usageTemplate)
 
+	var noColor bool
+	cmds.PersistentFlags().BoolVar(&noColor, "no-color", false,
+		fmt.Sprintf("Disable colored output. Color is also disabled if the %s environment variable is set or the output is not a terminal", output.NoColorEnv))
+	cmds.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		output.SetColor(output.ShouldColor(out, noColor))
+		output.SetWidth(output.TerminalWidth(out))
+	}
+
 	kubeConfigFlags := genericclioptions.NewConfigFlags(true)
 	kubeConfigFlags.AddFlags(cmds.PersistentFlags())
 	matchVersionKubeConfigFlags := cmdutil.NewMatchVersionFlags(kubeConfigFlags)
@@ -66,7 +93,23 @@ kubectl cert-manager is a CLI tool manage and configure cert-manager resources f
 	cmds.AddCommand(convert.NewCmdConvert(ioStreams))
 	cmds.AddCommand(create.NewCmdCreate(ioStreams, factory))
 	cmds.AddCommand(renew.NewCmdRenew(ioStreams, factory))
-	cmds.AddCommand(status.NewCmdStatus(ioStreams, factory))
+	cmds.AddCommand(rekey.NewCmdRekey(ioStreams, factory))
+	cmds.AddCommand(rotate.NewCmdRotate(ioStreams, factory))
+	cmds.AddCommand(externalsigning.NewCmdExternalSigning(ioStreams, factory))
+	cmds.AddCommand(status.NewCmdStatus(ioStreams, factory, kubeConfigFlags, stopCh))
+	cmds.AddCommand(explain.NewCmdExplain(ioStreams))
+	cmds.AddCommand(pause.NewCmdPause(ioStreams, factory))
+	cmds.AddCommand(pause.NewCmdResume(ioStreams, factory))
+	cmds.AddCommand(approve.NewCmdApprove(ioStreams, factory))
+	cmds.AddCommand(approve.NewCmdDeny(ioStreams, factory))
+	cmds.AddCommand(check.NewCmdCheck(ioStreams, factory))
+	cmds.AddCommand(report.NewCmdReport(ioStreams, factory, kubeConfigFlags))
+	cmds.AddCommand(inspect.NewCmdInspect(ioStreams))
+	cmds.AddCommand(verify.NewCmdVerify(ioStreams, factory))
+	cmds.AddCommand(validate.NewCmdValidate(ioStreams))
+	cmds.AddCommand(benchmark.NewCmdBenchmark(ioStreams, factory))
+	cmds.AddCommand(experimental.NewCmdExperimental(ioStreams, factory))
+	cmds.AddCommand(completion.NewCmdCompletion(ioStreams))
 
 	return cmds
 }
@@ -0,0 +1,288 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/completion"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	ctlclients "github.com/jetstack/cert-manager/pkg/ctl/clients"
+)
+
+var (
+	approveLong = templates.LongDesc(i18n.T(`
+Approve cert-manager CertificateRequests, so that they are signed by their issuer.
+
+Approval sets the Approved condition of the CertificateRequests. If the CertificateRequestApproval feature
+gate of the controller is enabled, CertificateRequests are only signed once they have been approved.
+The approval of a CertificateRequest is final, an approved request cannot be denied.`))
+
+	approveExample = templates.Examples(i18n.T(`
+# Approve the CertificateRequest named 'my-app-1' in the current context namespace.
+kubectl cert-manager approve my-app-1
+
+# Approve all CertificateRequests with the label 'app=my-service' in all namespaces, recording the reason.
+kubectl cert-manager approve --all-namespaces -l app=my-service --reason ChangeRequest --message "approved in CR-1234"`))
+
+	denyLong = templates.LongDesc(i18n.T(`
+Deny cert-manager CertificateRequests, so that they are never signed.
+
+Denial sets the Denied condition of the CertificateRequests, and the controller marks them as failed.
+The denial of a CertificateRequest is final, a denied request cannot be approved.`))
+
+	denyExample = templates.Examples(i18n.T(`
+# Deny the CertificateRequest named 'my-app-1' in the current context namespace.
+kubectl cert-manager deny my-app-1 --message "the domain is not owned by the team"`))
+)
+
+const (
+	defaultReason = "KubectlCertManager"
+)
+
+// Options is a struct to support approve and deny commands
+type Options struct {
+	CMClient   cmclient.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace that the CertificateRequests reside in.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace     string
+	LabelSelector string
+	AllNamespaces bool
+	// ChunkSize is the number of CertificateRequests requested per page
+	// when listing CertificateRequests with --selector
+	ChunkSize int64
+
+	// Reason and Message are set on the Approved or Denied condition
+	Reason  string
+	Message string
+
+	// ConditionType is the condition set by the command, Approved for the
+	// approve command and Denied for the deny command
+	ConditionType cmapi.CertificateRequestConditionType
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options setting the condition of the given
+// type.
+func NewOptions(ioStreams genericclioptions.IOStreams, conditionType cmapi.CertificateRequestConditionType) *Options {
+	o := &Options{
+		IOStreams:     ioStreams,
+		ChunkSize:     ctlclients.DefaultChunkSize,
+		Reason:        defaultReason,
+		ConditionType: conditionType,
+	}
+	if conditionType == cmapi.CertificateRequestConditionApproved {
+		o.Message = "manually approved by kubectl cert-manager"
+	} else {
+		o.Message = "manually denied by kubectl cert-manager"
+	}
+	return o
+}
+
+// NewCmdApprove returns a cobra command for approving CertificateRequests
+func NewCmdApprove(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams, cmapi.CertificateRequestConditionApproved)
+	cmd := &cobra.Command{
+		Use:     "approve",
+		Short:   "Approve a CertificateRequest",
+		Long:    approveLong,
+		Example: approveExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		ValidArgsFunction: completion.CertificateRequestNames(factory, 0),
+	}
+	o.addFlags(cmd)
+	return cmd
+}
+
+// NewCmdDeny returns a cobra command for denying CertificateRequests
+func NewCmdDeny(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams, cmapi.CertificateRequestConditionDenied)
+	cmd := &cobra.Command{
+		Use:     "deny",
+		Short:   "Deny a CertificateRequest",
+		Long:    denyLong,
+		Example: denyExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		ValidArgsFunction: completion.CertificateRequestNames(factory, 0),
+	}
+	o.addFlags(cmd)
+	return cmd
+}
+
+func (o *Options) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Reason, "reason", o.Reason, "The reason set on the condition, a brief machine readable explanation in CamelCase.")
+	cmd.Flags().StringVar(&o.Message, "message", o.Message, "The message set on the condition, a human readable description of the decision.")
+	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, select CertificateRequests across namespaces with --selector. Namespace in current context is ignored even if specified with --namespace.")
+	cmd.Flags().Int64Var(&o.ChunkSize, "chunk-size", o.ChunkSize, "Return large lists in chunks rather than all at once with --selector. Pass 0 to disable.")
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(o.LabelSelector) > 0 && len(args) > 0 {
+		return errors.New("cannot specify CertificateRequest names in conjunction with label selectors")
+	}
+	if len(o.LabelSelector) == 0 && len(args) == 0 {
+		return errors.New("the names of CertificateRequests or a label selector have to be provided")
+	}
+	if o.AllNamespaces && len(args) > 0 {
+		return errors.New("cannot specify CertificateRequest names in conjunction with --all-namespaces")
+	}
+	if len(o.Reason) == 0 {
+		return errors.New("--reason must not be empty")
+	}
+	if o.ChunkSize < 0 {
+		return errors.New("--chunk-size must not be negative")
+	}
+	return nil
+}
+
+// Complete takes the command arguments and factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes approve or deny command
+func (o *Options) Run(args []string) error {
+	ctx := context.TODO()
+
+	var crs []*cmapi.CertificateRequest
+	if len(o.LabelSelector) > 0 {
+		var err error
+		crs, err = o.listCertificateRequests(ctx)
+		if err != nil {
+			return err
+		}
+		if len(crs) == 0 {
+			fmt.Fprintln(o.ErrOut, "No CertificateRequests found")
+			return nil
+		}
+	}
+	for _, name := range args {
+		cr, err := o.CMClient.CertmanagerV1alpha2().CertificateRequests(o.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		crs = append(crs, cr)
+	}
+
+	for _, cr := range crs {
+		if err := o.decide(ctx, cr.DeepCopy()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listCertificateRequests lists the CertificateRequests matching the label
+// selector in the namespace, or in all namespaces.
+func (o *Options) listCertificateRequests(ctx context.Context) ([]*cmapi.CertificateRequest, error) {
+	selector, err := labels.Parse(o.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %v", o.LabelSelector, err)
+	}
+	namespace := o.Namespace
+	if o.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+
+	crs, err := ctlclients.NewCache(nil, o.CMClient, o.ChunkSize).ListCertificateRequests(ctx, namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(crs, func(i, j int) bool {
+		if crs[i].Namespace != crs[j].Namespace {
+			return crs[i].Namespace < crs[j].Namespace
+		}
+		return crs[i].Name < crs[j].Name
+	})
+	return crs, nil
+}
+
+// decide sets the Approved or Denied condition of cr. CertificateRequests
+// that already carry the condition are skipped, and CertificateRequests
+// that carry the opposite condition cannot be changed.
+func (o *Options) decide(ctx context.Context, cr *cmapi.CertificateRequest) error {
+	verb, opposite := "approved", cmapi.CertificateRequestConditionDenied
+	if o.ConditionType == cmapi.CertificateRequestConditionDenied {
+		verb, opposite = "denied", cmapi.CertificateRequestConditionApproved
+	}
+
+	if apiutil.CertificateRequestHasCondition(cr, cmapi.CertificateRequestCondition{Type: opposite, Status: cmmeta.ConditionTrue}) {
+		return fmt.Errorf("CertificateRequest %s/%s has already been %s and cannot be %s", cr.Namespace, cr.Name, pastTense(opposite), verb)
+	}
+	if apiutil.CertificateRequestHasCondition(cr, cmapi.CertificateRequestCondition{Type: o.ConditionType, Status: cmmeta.ConditionTrue}) {
+		fmt.Fprintf(o.Out, "CertificateRequest %s/%s has already been %s\n", cr.Namespace, cr.Name, verb)
+		return nil
+	}
+
+	apiutil.SetCertificateRequestCondition(cr, o.ConditionType, cmmeta.ConditionTrue, o.Reason, o.Message)
+	if _, err := o.CMClient.CertmanagerV1alpha2().CertificateRequests(cr.Namespace).UpdateStatus(ctx, cr, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update CertificateRequest %s/%s: %v", cr.Namespace, cr.Name, err)
+	}
+	fmt.Fprintf(o.Out, "CertificateRequest %s/%s has been %s\n", cr.Namespace, cr.Name, verb)
+	return nil
+}
+
+func pastTense(conditionType cmapi.CertificateRequestConditionType) string {
+	if conditionType == cmapi.CertificateRequestConditionApproved {
+		return "approved"
+	}
+	return "denied"
+}
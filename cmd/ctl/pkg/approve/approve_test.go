@@ -0,0 +1,189 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approve
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		options *Options
+		args    []string
+		expErr  bool
+	}{
+		"If there are arguments, as well as label selector, error": {
+			options: &Options{LabelSelector: "foo=bar", Reason: defaultReason},
+			args:    []string{"abc"},
+			expErr:  true,
+		},
+		"If there are neither arguments nor label selector, error": {
+			options: &Options{Reason: defaultReason},
+			expErr:  true,
+		},
+		"If there are arguments and all namespaces selected, error": {
+			options: &Options{AllNamespaces: true, Reason: defaultReason},
+			args:    []string{"abc"},
+			expErr:  true,
+		},
+		"If the reason is empty, error": {
+			options: &Options{},
+			args:    []string{"abc"},
+			expErr:  true,
+		},
+		"If label selector in all namespaces, don't error": {
+			options: &Options{LabelSelector: "foo=bar", AllNamespaces: true, Reason: defaultReason},
+			expErr:  false,
+		},
+		"If there are arguments, don't error": {
+			options: &Options{Reason: defaultReason},
+			args:    []string{"abc", "def"},
+			expErr:  false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.options.Validate(test.args)
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func gen(namespace, name string, labels map[string]string, conditions ...cmapi.CertificateRequestCondition) *cmapi.CertificateRequest {
+	return &cmapi.CertificateRequest{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Status:     cmapi.CertificateRequestStatus{Conditions: conditions},
+	}
+}
+
+func TestRun(t *testing.T) {
+	approved := cmapi.CertificateRequestCondition{Type: cmapi.CertificateRequestConditionApproved, Status: cmmeta.ConditionTrue, Reason: "ByOperator"}
+	denied := cmapi.CertificateRequestCondition{Type: cmapi.CertificateRequestConditionDenied, Status: cmmeta.ConditionTrue, Reason: "ByOperator"}
+
+	tests := map[string]struct {
+		conditionType cmapi.CertificateRequestConditionType
+		existing      []*cmapi.CertificateRequest
+		selector      string
+		allNamespaces bool
+		args          []string
+
+		expErr bool
+		// expDecided are the namespace/name of the CertificateRequests
+		// expected to carry the condition set by the command
+		expDecided []string
+		expOut     string
+	}{
+		"approve a request by name": {
+			conditionType: cmapi.CertificateRequestConditionApproved,
+			existing:      []*cmapi.CertificateRequest{gen("default", "cr-1", nil), gen("default", "cr-2", nil)},
+			args:          []string{"cr-1"},
+			expDecided:    []string{"default/cr-1"},
+			expOut:        "CertificateRequest default/cr-1 has been approved\n",
+		},
+		"deny requests by selector in all namespaces": {
+			conditionType: cmapi.CertificateRequestConditionDenied,
+			existing: []*cmapi.CertificateRequest{
+				gen("default", "cr-1", map[string]string{"app": "foo"}),
+				gen("other", "cr-2", map[string]string{"app": "foo"}),
+				gen("other", "cr-3", map[string]string{"app": "bar"}),
+			},
+			selector:      "app=foo",
+			allNamespaces: true,
+			expDecided:    []string{"default/cr-1", "other/cr-2"},
+			expOut:        "CertificateRequest default/cr-1 has been denied\nCertificateRequest other/cr-2 has been denied\n",
+		},
+		"skip an already approved request": {
+			conditionType: cmapi.CertificateRequestConditionApproved,
+			existing:      []*cmapi.CertificateRequest{gen("default", "cr-1", nil, approved)},
+			args:          []string{"cr-1"},
+			expDecided:    []string{"default/cr-1"},
+			expOut:        "CertificateRequest default/cr-1 has already been approved\n",
+		},
+		"fail to approve a denied request": {
+			conditionType: cmapi.CertificateRequestConditionApproved,
+			existing:      []*cmapi.CertificateRequest{gen("default", "cr-1", nil, denied)},
+			args:          []string{"cr-1"},
+			expErr:        true,
+		},
+		"fail to deny an approved request": {
+			conditionType: cmapi.CertificateRequestConditionDenied,
+			existing:      []*cmapi.CertificateRequest{gen("default", "cr-1", nil, approved)},
+			args:          []string{"cr-1"},
+			expErr:        true,
+		},
+		"fail on a missing request": {
+			conditionType: cmapi.CertificateRequestConditionApproved,
+			args:          []string{"cr-1"},
+			expErr:        true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var objs []runtime.Object
+			for _, cr := range test.existing {
+				objs = append(objs, cr)
+			}
+			client := cmfake.NewSimpleClientset(objs...)
+
+			out := new(bytes.Buffer)
+			o := NewOptions(genericclioptions.IOStreams{Out: out, ErrOut: new(bytes.Buffer)}, test.conditionType)
+			o.CMClient = client
+			o.Namespace = "default"
+			o.LabelSelector = test.selector
+			o.AllNamespaces = test.allNamespaces
+
+			err := o.Run(test.args)
+			if test.expErr != (err != nil) {
+				t.Fatalf("expected error=%t got=%v", test.expErr, err)
+			}
+			if out.String() != test.expOut {
+				t.Errorf("unexpected output, exp=%q got=%q", test.expOut, out.String())
+			}
+
+			decided := make(map[string]bool)
+			for _, name := range test.expDecided {
+				decided[name] = true
+			}
+			for _, existing := range test.existing {
+				cr, err := client.CertmanagerV1alpha2().CertificateRequests(existing.Namespace).Get(context.TODO(), existing.Name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				key := cr.Namespace + "/" + cr.Name
+				has := apiutil.CertificateRequestHasCondition(cr, cmapi.CertificateRequestCondition{Type: test.conditionType, Status: cmmeta.ConditionTrue})
+				if has != decided[key] {
+					t.Errorf("expected CertificateRequest %s to have condition %s=%t, got %t", key, test.conditionType, decided[key], has)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,472 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Measure the throughput and latency of issuing certificates with cert-manager.
+
+A number of synthetic Certificates are created in the namespace, and the time from the creation of each Certificate
+until it is Ready is measured. At most --concurrency Certificates are being issued at the same time: the next
+Certificate is only created once one of them has been issued or its issuance failed. Once all Certificates are issued,
+or --timeout has passed, the throughput and the latency percentiles are printed, and the Certificates and their
+Secrets are deleted.
+
+By default the Certificates are issued by a SelfSigned Issuer that is created for the benchmark, which measures the
+overhead of cert-manager itself. Use --issuer to measure a real issuer instead. The Certificates request the DNS name
+<certificate name>.<dns zone>, so the issuer has to be able to issue certificates for --dns-zone, e.g. ACME issuers
+have to be able to solve challenges for it.
+
+The Certificates and the Issuer created by the benchmark are labelled cert-manager.io/benchmark-run with the ID of the
+run, and the Secrets are named like the Certificates, so they can be found and deleted if the command is interrupted or
+run with --cleanup=false.`))
+
+	example = templates.Examples(i18n.T(`
+# Issue 100 Certificates with a temporary SelfSigned Issuer in the 'benchmark' namespace, 10 at a time
+kubectl cert-manager benchmark issuance --namespace benchmark --count 100 --concurrency 10
+
+# Issue 20 Certificates with the ClusterIssuer 'letsencrypt-staging' for names under bench.example.com
+kubectl cert-manager benchmark issuance --issuer letsencrypt-staging --issuer-kind ClusterIssuer --dns-zone bench.example.com --count 20
+
+# Issue 1000 Certificates all at once and print the results as JSON
+kubectl cert-manager benchmark issuance --count 1000 --concurrency 1000 --timeout 30m -o json`))
+)
+
+const (
+	// runLabelKey is the label of all resources created by a benchmark run,
+	// with the ID of the run as value
+	runLabelKey = "cert-manager.io/benchmark-run"
+	// namePrefix is the prefix of the names of all resources created by a
+	// benchmark run, followed by the ID of the run
+	namePrefix = "cm-benchmark-"
+)
+
+var (
+	// pollInterval is how often the temporary Issuer is checked until it is
+	// Ready
+	pollInterval = time.Second
+)
+
+// Options is a struct to support benchmark issuance command
+type Options struct {
+	CMClient   cmclient.Interface
+	KubeClient kubernetes.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace that the Certificates are created in.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace string
+
+	// Count is the number of Certificates to issue
+	Count int
+	// Concurrency is the maximum number of Certificates being issued at the
+	// same time
+	Concurrency int
+	// IssuerName, IssuerKind and IssuerGroup reference the issuer of the
+	// Certificates. A SelfSigned Issuer is created if IssuerName is empty.
+	IssuerName  string
+	IssuerKind  string
+	IssuerGroup string
+	// DNSZone is the zone under which the DNS names of the Certificates are
+	// requested
+	DNSZone string
+	// Timeout is the maximum duration of the benchmark, after which
+	// Certificates that have not been issued are reported as unfinished
+	Timeout time.Duration
+	// Cleanup deletes the resources created by the benchmark once it is done
+	Cleanup bool
+	// Output is the output format, either empty for text or "json"
+	Output string
+
+	// clock returns the current time, and is replaced in tests
+	clock func() time.Time
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams:   ioStreams,
+		Count:       100,
+		Concurrency: 10,
+		IssuerKind:  cmapi.IssuerKind,
+		IssuerGroup: certmanager.GroupName,
+		DNSZone:     "benchmark.cert-manager.local",
+		Timeout:     10 * time.Minute,
+		Cleanup:     true,
+		clock:       time.Now,
+	}
+}
+
+// NewCmdBenchmarkIssuance returns a cobra command for benchmark issuance
+func NewCmdBenchmarkIssuance(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "issuance",
+		Short:   "Measure the throughput and latency of issuing Certificates",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().IntVar(&o.Count, "count", o.Count, "The number of Certificates to issue")
+	cmd.Flags().IntVar(&o.Concurrency, "concurrency", o.Concurrency, "The maximum number of Certificates being issued at the same time")
+	cmd.Flags().StringVar(&o.IssuerName, "issuer", o.IssuerName, "The name of the issuer of the Certificates. By default a SelfSigned Issuer is created for the benchmark")
+	cmd.Flags().StringVar(&o.IssuerKind, "issuer-kind", o.IssuerKind, "The kind of the issuer set with --issuer, e.g. Issuer or ClusterIssuer")
+	cmd.Flags().StringVar(&o.IssuerGroup, "issuer-group", o.IssuerGroup, "The API group of the issuer set with --issuer, for external issuers")
+	cmd.Flags().StringVar(&o.DNSZone, "dns-zone", o.DNSZone, "The DNS zone under which the DNS names of the Certificates are requested")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "The maximum duration of the benchmark, after which Certificates that have not been issued are reported as unfinished")
+	cmd.Flags().BoolVar(&o.Cleanup, "cleanup", o.Cleanup, "If true, the Certificates, Secrets and Issuer created by the benchmark are deleted once it is done")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format. Only 'json' is supported, which prints the results as a JSON object")
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("no arguments are accepted")
+	}
+	if o.Count < 1 {
+		return errors.New("--count must be at least 1")
+	}
+	if o.Concurrency < 1 {
+		return errors.New("--concurrency must be at least 1")
+	}
+	if o.Timeout <= 0 {
+		return errors.New("--timeout must be positive")
+	}
+	if o.IssuerName == "" && (o.IssuerKind != cmapi.IssuerKind || o.IssuerGroup != certmanager.GroupName) {
+		return errors.New("--issuer-kind and --issuer-group can only be used together with --issuer")
+	}
+	if errs := validation.IsDNS1123Subdomain(o.DNSZone); len(errs) > 0 {
+		return fmt.Errorf("invalid --dns-zone %q: %v", o.DNSZone, errs)
+	}
+	if o.Output != "" && o.Output != "json" {
+		return fmt.Errorf("unsupported output format %q, only 'json' is supported", o.Output)
+	}
+	return nil
+}
+
+// Complete takes the factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory) error {
+	var err error
+
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	// Client-side rate limiting must not limit the rate Certificates are
+	// created at, as that would be measured as the throughput of cert-manager
+	if qps := float32(2 * o.Concurrency); qps > o.RESTConfig.QPS {
+		o.RESTConfig.QPS = qps
+		o.RESTConfig.Burst = 2 * int(qps)
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	o.KubeClient, err = kubernetes.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes benchmark issuance command
+func (o *Options) Run() error {
+	ctx := context.TODO()
+	runID := utilrand.String(5)
+
+	issuerRef, err := o.prepareIssuer(ctx, runID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.ErrOut, "Issuing %d Certificates with %s %q in namespace %q, %d at a time...\n",
+		o.Count, issuerRef.Kind, issuerRef.Name, o.Namespace, o.Concurrency)
+	results, start, end, err := o.issue(ctx, runID, issuerRef)
+
+	var cleanupErr error
+	if o.Cleanup {
+		cleanupErr = o.cleanup(ctx, runID, results, issuerRef)
+	} else {
+		fmt.Fprintf(o.ErrOut, "The Certificates and Issuer created by the benchmark are labelled %s=%s, and their Secrets are named %s%s-<n>\n",
+			runLabelKey, runID, namePrefix, runID)
+	}
+	if err != nil {
+		return err
+	}
+
+	s := summarize(runID, o.Count, results, start, end)
+	if err := o.print(s); err != nil {
+		return err
+	}
+	if cleanupErr != nil {
+		return cleanupErr
+	}
+	if s.Issued < s.Certificates {
+		return fmt.Errorf("%d of %d Certificates were not issued", s.Certificates-s.Issued, s.Certificates)
+	}
+	return nil
+}
+
+// prepareIssuer returns a reference to the issuer of the Certificates,
+// either the issuer set with --issuer after checking it exists, or a
+// SelfSigned Issuer created for the run once it is Ready.
+func (o *Options) prepareIssuer(ctx context.Context, runID string) (cmmeta.ObjectReference, error) {
+	if o.IssuerName != "" {
+		ref := cmmeta.ObjectReference{Name: o.IssuerName, Kind: o.IssuerKind, Group: o.IssuerGroup}
+		if o.IssuerGroup != certmanager.GroupName {
+			// External issuers cannot be looked up without knowing their API
+			return ref, nil
+		}
+		var err error
+		switch o.IssuerKind {
+		case cmapi.IssuerKind:
+			_, err = o.CMClient.CertmanagerV1alpha2().Issuers(o.Namespace).Get(ctx, o.IssuerName, metav1.GetOptions{})
+		case cmapi.ClusterIssuerKind:
+			_, err = o.CMClient.CertmanagerV1alpha2().ClusterIssuers().Get(ctx, o.IssuerName, metav1.GetOptions{})
+		default:
+			return ref, fmt.Errorf("unsupported issuer kind %q, only %q and %q are supported in the %s group", o.IssuerKind, cmapi.IssuerKind, cmapi.ClusterIssuerKind, certmanager.GroupName)
+		}
+		if err != nil {
+			return ref, fmt.Errorf("error when getting %s %q: %w", o.IssuerKind, o.IssuerName, err)
+		}
+		return ref, nil
+	}
+
+	issuer := &cmapi.Issuer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      namePrefix + runID,
+			Namespace: o.Namespace,
+			Labels:    map[string]string{runLabelKey: runID},
+		},
+		Spec: cmapi.IssuerSpec{
+			IssuerConfig: cmapi.IssuerConfig{SelfSigned: &cmapi.SelfSignedIssuer{}},
+		},
+	}
+	issuer, err := o.CMClient.CertmanagerV1alpha2().Issuers(o.Namespace).Create(ctx, issuer, metav1.CreateOptions{})
+	if err != nil {
+		return cmmeta.ObjectReference{}, fmt.Errorf("error creating SelfSigned Issuer for the benchmark: %w", err)
+	}
+	ref := cmmeta.ObjectReference{Name: issuer.Name, Kind: cmapi.IssuerKind, Group: certmanager.GroupName}
+
+	readyCondition := cmapi.IssuerCondition{Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionTrue}
+	err = wait.PollImmediate(pollInterval, o.Timeout, func() (bool, error) {
+		issuer, err := o.CMClient.CertmanagerV1alpha2().Issuers(o.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return apiutil.IssuerHasCondition(issuer, readyCondition), nil
+	})
+	if err == wait.ErrWaitTimeout {
+		err = fmt.Errorf("timed out waiting for Issuer %q to be Ready", ref.Name)
+	}
+	if err != nil {
+		if o.Cleanup {
+			o.deleteIssuer(ctx, ref.Name)
+		}
+		return ref, err
+	}
+	return ref, nil
+}
+
+// issue creates the Certificates of the run with at most o.Concurrency
+// being issued at the same time, and returns the results of all Certificates
+// that were created before the timeout, and the time the first Certificate
+// was created and the last one finished.
+func (o *Options) issue(ctx context.Context, runID string, issuerRef cmmeta.ObjectReference) ([]*result, time.Time, time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	selector := labels.SelectorFromSet(labels.Set{runLabelKey: runID}).String()
+	// The watch is opened before the first Certificate is created, so that
+	// no update is missed
+	w, err := o.CMClient.CertmanagerV1alpha2().Certificates(o.Namespace).Watch(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, fmt.Errorf("error when watching Certificates: %w", err)
+	}
+	t := newTracker(o.clock)
+	var watchErr error
+	watchDone := make(chan struct{})
+	go func() {
+		defer close(watchDone)
+		if err := o.watchCertificates(ctx, w, selector, t); err != nil {
+			watchErr = err
+			cancel()
+		}
+	}()
+
+	names := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < o.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range names {
+				r := t.start(name)
+				crt := o.certificate(name, runID, issuerRef)
+				if _, err := o.CMClient.CertmanagerV1alpha2().Certificates(o.Namespace).Create(ctx, crt, metav1.CreateOptions{}); err != nil {
+					t.fail(name, fmt.Sprintf("failed to create Certificate: %v", err))
+					continue
+				}
+				select {
+				case <-r.done:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+create:
+	for i := 0; i < o.Count; i++ {
+		select {
+		case names <- fmt.Sprintf("%s%s-%d", namePrefix, runID, i):
+		case <-ctx.Done():
+			break create
+		}
+	}
+	close(names)
+	wg.Wait()
+	cancel()
+	<-watchDone
+
+	results, start, end := t.results()
+	return results, start, end, watchErr
+}
+
+// watchCertificates passes all Certificates observed by w to t, reopening
+// the watch with selector whenever it is closed, until ctx is cancelled.
+func (o *Options) watchCertificates(ctx context.Context, w watch.Interface, selector string, t *tracker) error {
+	defer func() { w.Stop() }()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-w.ResultChan():
+			if ok {
+				if crt, ok := ev.Object.(*cmapi.Certificate); ok {
+					t.observe(crt)
+				}
+				continue
+			}
+		}
+
+		// The API server closes long running watches periodically
+		w.Stop()
+		reopened, err := o.CMClient.CertmanagerV1alpha2().Certificates(o.Namespace).Watch(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("error when watching Certificates: %w", err)
+		}
+		w = reopened
+	}
+}
+
+// certificate returns the Certificate with the given name of the run
+func (o *Options) certificate(name, runID string, issuerRef cmmeta.ObjectReference) *cmapi.Certificate {
+	return &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: o.Namespace,
+			Labels:    map[string]string{runLabelKey: runID},
+		},
+		Spec: cmapi.CertificateSpec{
+			SecretName: name,
+			DNSNames:   []string{name + "." + o.DNSZone},
+			IssuerRef:  issuerRef,
+		},
+	}
+}
+
+// cleanup deletes the Certificates of results, their Secrets, and the Issuer
+// if it was created for the run. The CertificateRequests of the Certificates
+// are garbage collected.
+func (o *Options) cleanup(ctx context.Context, runID string, results []*result, issuerRef cmmeta.ObjectReference) error {
+	fmt.Fprintf(o.ErrOut, "Deleting the resources created by the benchmark...\n")
+	var errs []error
+	for _, r := range results {
+		if err := o.CMClient.CertmanagerV1alpha2().Certificates(o.Namespace).Delete(ctx, r.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("error deleting Certificate %q: %w", r.Name, err))
+		}
+		if err := o.KubeClient.CoreV1().Secrets(o.Namespace).Delete(ctx, r.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("error deleting Secret %q: %w", r.Name, err))
+		}
+	}
+	if o.IssuerName == "" {
+		if err := o.deleteIssuer(ctx, issuerRef.Name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to delete some resources of benchmark run %s, they are labelled %s=%s: %v", runID, runLabelKey, runID, errs)
+	}
+	return nil
+}
+
+// deleteIssuer deletes the Issuer created for the run
+func (o *Options) deleteIssuer(ctx context.Context, name string) error {
+	err := o.CMClient.CertmanagerV1alpha2().Issuers(o.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error deleting Issuer %q: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuance
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		modify func(o *Options)
+		args   []string
+		expErr bool
+	}{
+		"defaults": {
+			modify: func(o *Options) {},
+		},
+		"a ClusterIssuer": {
+			modify: func(o *Options) {
+				o.IssuerName = "letsencrypt"
+				o.IssuerKind = cmapi.ClusterIssuerKind
+			},
+		},
+		"an argument": {
+			modify: func(o *Options) {},
+			args:   []string{"abc"},
+			expErr: true,
+		},
+		"no Certificates": {
+			modify: func(o *Options) { o.Count = 0 },
+			expErr: true,
+		},
+		"no concurrency": {
+			modify: func(o *Options) { o.Concurrency = 0 },
+			expErr: true,
+		},
+		"no timeout": {
+			modify: func(o *Options) { o.Timeout = 0 },
+			expErr: true,
+		},
+		"an issuer kind without an issuer": {
+			modify: func(o *Options) { o.IssuerKind = cmapi.ClusterIssuerKind },
+			expErr: true,
+		},
+		"an invalid DNS zone": {
+			modify: func(o *Options) { o.DNSZone = "not a zone" },
+			expErr: true,
+		},
+		"an unsupported output format": {
+			modify: func(o *Options) { o.Output = "yaml" },
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewOptions(genericclioptions.IOStreams{})
+			test.modify(o)
+			err := o.Validate(test.args)
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error: %v, got: %v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	start := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	issued := func(name string, created, latency time.Duration) *result {
+		return &result{Name: name, Created: start.Add(created), Finished: start.Add(created + latency), Issued: true}
+	}
+
+	var results []*result
+	for i := 1; i <= 100; i++ {
+		results = append(results, issued("crt", 0, time.Duration(i)*time.Second))
+	}
+	results = append(results,
+		&result{Name: "failed", Created: start, Finished: start.Add(time.Second), Failure: "boom"},
+		&result{Name: "unfinished", Created: start},
+	)
+
+	s := summarize("abcde", 103, results, start, start.Add(200*time.Second))
+	if s.Issued != 100 || s.Failed != 1 || s.Unfinished != 2 {
+		t.Errorf("unexpected counts: issued %d, failed %d, unfinished %d", s.Issued, s.Failed, s.Unfinished)
+	}
+	if s.DurationSeconds != 200 || s.IssuedPerSecond != 0.5 {
+		t.Errorf("unexpected duration %v and throughput %v", s.DurationSeconds, s.IssuedPerSecond)
+	}
+	expLatency := latency{Min: 1, P50: 50, P90: 90, P99: 99, Max: 100}
+	if s.LatencySeconds == nil || *s.LatencySeconds != expLatency {
+		t.Errorf("expected latency %+v, got: %+v", expLatency, s.LatencySeconds)
+	}
+	if len(s.Failures) != 1 || s.Failures[0] != (failure{Certificate: "failed", Message: "boom"}) {
+		t.Errorf("unexpected failures: %+v", s.Failures)
+	}
+
+	s = summarize("abcde", 1, []*result{{Name: "unfinished", Created: start}}, start, start.Add(time.Minute))
+	if s.LatencySeconds != nil || s.IssuedPerSecond != 0 || s.Unfinished != 1 {
+		t.Errorf("unexpected summary without issued Certificates: %+v", s)
+	}
+}
+
+func TestRun(t *testing.T) {
+	defer func(interval time.Duration) { pollInterval = interval }(pollInterval)
+	pollInterval = 10 * time.Millisecond
+
+	const namespace = "benchmark"
+	cmClient := cmfake.NewSimpleClientset()
+	kubeClient := kubefake.NewSimpleClientset()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go simulateIssuance(ctx, t, cmClient, namespace, "-3")
+
+	out := &bytes.Buffer{}
+	o := NewOptions(genericclioptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}})
+	o.Namespace = namespace
+	o.CMClient = cmClient
+	o.KubeClient = kubeClient
+	o.Count = 10
+	o.Concurrency = 3
+	o.Timeout = 10 * time.Second
+
+	err := o.Run()
+	if err == nil || !strings.Contains(err.Error(), "1 of 10 Certificates were not issued") {
+		t.Errorf("expected the failed Certificate to be reported as error, got: %v", err)
+	}
+	for _, line := range []string{"Issued:         9\n", "Failed:         1\n", "Unfinished:     0\n", "issuance failed on purpose"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected output to contain %q, got:\n%s", line, out.String())
+		}
+	}
+
+	crts, err := cmClient.CertmanagerV1alpha2().Certificates(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(crts.Items) != 0 {
+		t.Errorf("expected all Certificates to be deleted, got %d", len(crts.Items))
+	}
+	issuers, err := cmClient.CertmanagerV1alpha2().Issuers(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issuers.Items) != 0 {
+		t.Errorf("expected the Issuer of the benchmark to be deleted, got %d", len(issuers.Items))
+	}
+}
+
+// simulateIssuance makes the Issuers in namespace Ready, and issues its
+// Certificates, except for those whose name has the given suffix, whose
+// issuance fails.
+func simulateIssuance(ctx context.Context, t *testing.T, cmClient cmclient.Interface, namespace, failSuffix string) {
+	issuers, err := cmClient.CertmanagerV1alpha2().Issuers(namespace).Watch(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer issuers.Stop()
+	crts, err := cmClient.CertmanagerV1alpha2().Certificates(namespace).Watch(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer crts.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-issuers.ResultChan():
+			issuer, ok := ev.Object.(*cmapi.Issuer)
+			if !ok || len(issuer.Status.Conditions) > 0 {
+				continue
+			}
+			issuer.Status.Conditions = []cmapi.IssuerCondition{{Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionTrue}}
+			cmClient.CertmanagerV1alpha2().Issuers(namespace).UpdateStatus(ctx, issuer, metav1.UpdateOptions{})
+		case ev := <-crts.ResultChan():
+			crt, ok := ev.Object.(*cmapi.Certificate)
+			if !ok || len(crt.Status.Conditions) > 0 {
+				continue
+			}
+			if strings.HasSuffix(crt.Name, failSuffix) {
+				failedAt := metav1.Now()
+				crt.Status.LastFailureTime = &failedAt
+				crt.Status.Conditions = []cmapi.CertificateCondition{{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionFalse, Message: "issuance failed on purpose"}}
+			} else {
+				revision := 1
+				crt.Status.Revision = &revision
+				crt.Status.Conditions = []cmapi.CertificateCondition{{Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionTrue}}
+			}
+			cmClient.CertmanagerV1alpha2().Certificates(namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+		}
+	}
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuance
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+// maxPrintedFailures is the number of failures printed in the text output
+const maxPrintedFailures = 5
+
+// result is the outcome of issuing a single Certificate of a benchmark run
+type result struct {
+	Name     string
+	Created  time.Time
+	Finished time.Time
+	Issued   bool
+	Failure  string
+
+	// done is closed once the Certificate has been issued or failed
+	done chan struct{}
+}
+
+func (r *result) finished() bool {
+	return !r.Finished.IsZero()
+}
+
+// tracker records when the Certificates of a benchmark run were created,
+// and when they were first observed to be issued or to have failed.
+type tracker struct {
+	clock func() time.Time
+
+	lock    sync.Mutex
+	results []*result
+	byName  map[string]*result
+}
+
+func newTracker(clock func() time.Time) *tracker {
+	return &tracker{
+		clock:  clock,
+		byName: make(map[string]*result),
+	}
+}
+
+// start records that the Certificate with the given name is about to be
+// created. It has to be called before the Certificate is created, so that
+// its updates cannot be observed before.
+func (t *tracker) start(name string) *result {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	r := &result{Name: name, Created: t.clock(), done: make(chan struct{})}
+	t.results = append(t.results, r)
+	t.byName[name] = r
+	return r
+}
+
+// observe records that crt has been issued if it is Ready, or that its
+// issuance failed if it failed after it was created.
+func (t *tracker) observe(crt *cmapi.Certificate) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	r, ok := t.byName[crt.Name]
+	if !ok || r.finished() {
+		return
+	}
+
+	readyCondition := cmapi.CertificateCondition{Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionTrue}
+	if crt.Status.Revision != nil && apiutil.CertificateHasCondition(crt, readyCondition) {
+		t.finish(r, true, "")
+		return
+	}
+	// The last failure time has a precision of seconds
+	if crt.Status.LastFailureTime != nil && !crt.Status.LastFailureTime.Time.Before(r.Created.Truncate(time.Second)) {
+		failure := "the issuance failed"
+		if cond := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuing); cond != nil && cond.Message != "" {
+			failure = cond.Message
+		}
+		t.finish(r, false, failure)
+	}
+}
+
+// fail records that the issuance of the Certificate with the given name
+// failed before it could be observed, e.g. because it could not be created.
+func (t *tracker) fail(name, failure string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if r, ok := t.byName[name]; ok && !r.finished() {
+		t.finish(r, false, failure)
+	}
+}
+
+func (t *tracker) finish(r *result, issued bool, failure string) {
+	r.Finished = t.clock()
+	r.Issued = issued
+	r.Failure = failure
+	close(r.done)
+}
+
+// results returns a copy of the results of all Certificates in the order
+// they were created, the time the first one was created, and the time the
+// last one finished, which is the current time if any has not finished.
+func (t *tracker) results() ([]*result, time.Time, time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	now := t.clock()
+	if len(t.results) == 0 {
+		return nil, now, now
+	}
+
+	results := make([]*result, len(t.results))
+	start, end := t.results[0].Created, time.Time{}
+	unfinished := false
+	for i, r := range t.results {
+		copied := *r
+		results[i] = &copied
+		if !r.finished() {
+			unfinished = true
+		} else if r.Finished.After(end) {
+			end = r.Finished
+		}
+	}
+	if unfinished {
+		end = now
+	}
+	return results, start, end
+}
+
+// JSONOutput returns a value of the type printed with --output json, from
+// which the schema of the output is generated.
+func JSONOutput() interface{} {
+	return summary{}
+}
+
+// summary is the outcome of a benchmark run
+type summary struct {
+	RunID string `json:"runID"`
+	// Certificates is the number of Certificates that should have been
+	// issued, including those not created before the timeout
+	Certificates int `json:"certificates"`
+	Issued       int `json:"issued"`
+	Failed       int `json:"failed"`
+	// Unfinished is the number of Certificates that were not issued and did
+	// not fail before the timeout
+	Unfinished int `json:"unfinished"`
+	// DurationSeconds is the time from the creation of the first Certificate
+	// until the last one finished
+	DurationSeconds float64 `json:"durationSeconds"`
+	// IssuedPerSecond is the number of Certificates issued per second over
+	// the duration of the run
+	IssuedPerSecond float64 `json:"issuedPerSecond"`
+	// LatencySeconds are the percentiles of the time from the creation of a
+	// Certificate until it was issued, if any was issued
+	LatencySeconds *latency  `json:"latencySeconds,omitempty"`
+	Failures       []failure `json:"failures,omitempty"`
+}
+
+// latency are the percentiles of the issuance latency in seconds
+type latency struct {
+	Min float64 `json:"min"`
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+type failure struct {
+	Certificate string `json:"certificate"`
+	Message     string `json:"message"`
+}
+
+// summarize computes the summary of the results of a run of count
+// Certificates that started and ended at the given times.
+func summarize(runID string, count int, results []*result, start, end time.Time) summary {
+	s := summary{
+		RunID:           runID,
+		Certificates:    count,
+		DurationSeconds: end.Sub(start).Seconds(),
+	}
+
+	var latencies []time.Duration
+	for _, r := range results {
+		switch {
+		case r.Issued:
+			s.Issued++
+			latencies = append(latencies, r.Finished.Sub(r.Created))
+		case r.finished():
+			s.Failed++
+			s.Failures = append(s.Failures, failure{Certificate: r.Name, Message: r.Failure})
+		}
+	}
+	s.Unfinished = count - s.Issued - s.Failed
+	if s.DurationSeconds > 0 {
+		s.IssuedPerSecond = float64(s.Issued) / s.DurationSeconds
+	}
+
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		s.LatencySeconds = &latency{
+			Min: latencies[0].Seconds(),
+			P50: percentile(latencies, 50).Seconds(),
+			P90: percentile(latencies, 90).Seconds(),
+			P99: percentile(latencies, 99).Seconds(),
+			Max: latencies[len(latencies)-1].Seconds(),
+		}
+	}
+	return s
+}
+
+// percentile returns the p-th percentile of the sorted durations, using the
+// nearest-rank method
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// print prints s in the output format of o
+func (o *Options) print(s summary) error {
+	if o.Output == "json" {
+		b, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "%s\n", b)
+		return nil
+	}
+
+	fmt.Fprintf(o.Out, "Benchmark run:  %s\n", s.RunID)
+	fmt.Fprintf(o.Out, "Certificates:   %d (concurrency %d)\n", s.Certificates, o.Concurrency)
+	fmt.Fprintf(o.Out, "Issued:         %d\n", s.Issued)
+	fmt.Fprintf(o.Out, "Failed:         %d\n", s.Failed)
+	fmt.Fprintf(o.Out, "Unfinished:     %d\n", s.Unfinished)
+	fmt.Fprintf(o.Out, "Duration:       %s\n", formatSeconds(s.DurationSeconds))
+	fmt.Fprintf(o.Out, "Throughput:     %.2f certificates/s\n", s.IssuedPerSecond)
+	if l := s.LatencySeconds; l != nil {
+		fmt.Fprintf(o.Out, "Latency:        min %s, p50 %s, p90 %s, p99 %s, max %s\n",
+			formatSeconds(l.Min), formatSeconds(l.P50), formatSeconds(l.P90), formatSeconds(l.P99), formatSeconds(l.Max))
+	}
+	if len(s.Failures) > 0 {
+		fmt.Fprintf(o.Out, "Failures:\n")
+		for i, f := range s.Failures {
+			if i == maxPrintedFailures {
+				fmt.Fprintf(o.Out, "  ... and %d more\n", len(s.Failures)-maxPrintedFailures)
+				break
+			}
+			fmt.Fprintf(o.Out, "  %s: %s\n", f.Certificate, f.Message)
+		}
+	}
+	return nil
+}
+
+// formatSeconds formats a number of seconds as a duration rounded to
+// milliseconds, e.g. 1.5s
+func formatSeconds(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond).String()
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Check if the cert-manager API is ready to accept requests.
+
+The check performs a dry-run create of a Certificate resource, which verifies that the cert-manager
+CRDs are installed and that the webhook is reachable by the Kubernetes API server.
+Nothing is persisted in the cluster.
+
+The command exits with a non-zero exit code if the API is not ready, so it can be used in install
+pipelines, e.g. in a Helm post-install hook or a CI smoke test.`))
+
+	example = templates.Examples(i18n.T(`
+# Check if the cert-manager API is ready.
+kubectl cert-manager check api
+
+# Wait for up to 2 minutes for the cert-manager API to become ready, checking every 5 seconds.
+kubectl cert-manager check api --wait 2m --interval 5s`))
+)
+
+// Options is a struct to support check api command
+type Options struct {
+	CMClient   cmclient.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace that the self-test Certificate is created in.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace string
+
+	// Wait is the length of time the command will keep retrying the check
+	// for. If zero, the check is performed only once.
+	Wait time.Duration
+	// Interval is the time to wait between two checks
+	Interval time.Duration
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdCheckAPI returns a cobra command for checking the cert-manager API
+func NewCmdCheckAPI(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "api",
+		Short:   "Check if the cert-manager API is ready",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().DurationVar(&o.Wait, "wait", 0,
+		"Time to keep retrying the check for until the API is ready, e.g. 2m. If not set, the check is performed only once")
+	cmd.Flags().DurationVar(&o.Interval, "interval", 5*time.Second,
+		"Time to wait between two checks if --wait is set")
+
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("check api does not accept any arguments")
+	}
+
+	if o.Wait < 0 {
+		return errors.New("--wait must not be negative")
+	}
+
+	if o.Wait > 0 && o.Interval <= 0 {
+		return errors.New("--interval must be greater than zero")
+	}
+
+	return nil
+}
+
+// Complete takes the command arguments and factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes check api command
+func (o *Options) Run() error {
+	ctx := context.TODO()
+
+	if o.Wait == 0 {
+		if err := o.check(ctx); err != nil {
+			return err
+		}
+		fmt.Fprintln(o.Out, "The cert-manager API is ready")
+		return nil
+	}
+
+	var lastErr error
+	err := wait.PollImmediate(o.Interval, o.Wait, func() (bool, error) {
+		lastErr = o.check(ctx)
+		if lastErr != nil {
+			fmt.Fprintf(o.ErrOut, "Not ready: %v\n", lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("timed out after %s waiting for the cert-manager API to be ready: %v", o.Wait, lastErr)
+	}
+
+	fmt.Fprintln(o.Out, "The cert-manager API is ready")
+	return nil
+}
+
+// check performs a dry-run create of a self-test Certificate.
+func (o *Options) check(ctx context.Context) error {
+	crt := &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "cmctl-check-api-",
+			Namespace:    o.Namespace,
+		},
+		Spec: cmapi.CertificateSpec{
+			DNSNames:   []string{"cmctl-check-api.example.com"},
+			SecretName: "cmctl-check-api",
+			IssuerRef: cmmeta.ObjectReference{
+				Name: "cmctl-check-api",
+			},
+		},
+	}
+
+	_, err := o.CMClient.CertmanagerV1alpha2().Certificates(o.Namespace).Create(ctx, crt, metav1.CreateOptions{
+		DryRun: []string{metav1.DryRunAll},
+	})
+	return translateError(err)
+}
+
+// translateError returns a more helpful error for the common failure modes of
+// a freshly installed cert-manager.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var statusErr *apierrors.StatusError
+	if errors.As(err, &statusErr) {
+		switch {
+		case apierrors.IsNotFound(statusErr):
+			return fmt.Errorf("the cert-manager CRDs are not yet installed on the Kubernetes API server: %v", err)
+		case isWebhookCallError(statusErr):
+			return fmt.Errorf("the cert-manager webhook is not reachable by the Kubernetes API server: %v", err)
+		}
+	}
+	return fmt.Errorf("error performing dry-run create of a Certificate: %v", err)
+}
+
+// isWebhookCallError returns true if err is the internal error returned by the
+// Kubernetes API server when it fails to call an admission webhook.
+func isWebhookCallError(err *apierrors.StatusError) bool {
+	status := err.Status()
+	if status.Reason != metav1.StatusReasonInternalError || status.Details == nil {
+		return false
+	}
+	for _, cause := range status.Details.Causes {
+		if strings.HasPrefix(cause.Message, "failed calling webhook") {
+			return true
+		}
+	}
+	return false
+}
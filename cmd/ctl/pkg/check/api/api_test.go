@@ -0,0 +1,191 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	coretesting "k8s.io/client-go/testing"
+
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+)
+
+var (
+	errNotFound = apierrors.NewNotFound(schema.GroupResource{Group: "cert-manager.io", Resource: "certificates"}, "")
+	errWebhook  = apierrors.NewInternalError(errors.New(`failed calling webhook "webhook.cert-manager.io": Post https://cert-manager-webhook.cert-manager.svc:443/mutate: connection refused`))
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		options *Options
+		args    []string
+		expErr  bool
+	}{
+		"If there are arguments, error": {
+			options: &Options{},
+			args:    []string{"abc"},
+			expErr:  true,
+		},
+		"If wait is negative, error": {
+			options: &Options{Wait: -time.Second},
+			expErr:  true,
+		},
+		"If wait is set and interval is not, error": {
+			options: &Options{Wait: time.Minute},
+			expErr:  true,
+		},
+		"If neither wait nor interval are set, don't error": {
+			options: &Options{},
+			expErr:  false,
+		},
+		"If wait and interval are set, don't error": {
+			options: &Options{Wait: time.Minute, Interval: time.Second},
+			expErr:  false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.options.Validate(test.args)
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestTranslateError(t *testing.T) {
+	tests := map[string]struct {
+		err    error
+		expErr string
+	}{
+		"no error": {},
+		"the CRDs are not installed": {
+			err:    errNotFound,
+			expErr: "the cert-manager CRDs are not yet installed on the Kubernetes API server: " + errNotFound.Error(),
+		},
+		"the CRDs are not installed, with a wrapped error": {
+			err:    fmt.Errorf("creating Certificate: %w", errNotFound),
+			expErr: "the cert-manager CRDs are not yet installed on the Kubernetes API server: creating Certificate: " + errNotFound.Error(),
+		},
+		"the webhook cannot be called": {
+			err:    errWebhook,
+			expErr: "the cert-manager webhook is not reachable by the Kubernetes API server: " + errWebhook.Error(),
+		},
+		"an internal error unrelated to the webhook": {
+			err:    apierrors.NewInternalError(errors.New("etcd unavailable")),
+			expErr: "error performing dry-run create of a Certificate: Internal error occurred: etcd unavailable",
+		},
+		"the webhook rejects the Certificate": {
+			err:    apierrors.NewBadRequest("admission webhook denied the request"),
+			expErr: "error performing dry-run create of a Certificate: admission webhook denied the request",
+		},
+		"a message mentioning the webhook in an error that is not a status error": {
+			err:    errors.New("failed calling webhook"),
+			expErr: "error performing dry-run create of a Certificate: failed calling webhook",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := translateError(test.err)
+			if test.expErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.expErr {
+				t.Errorf("unexpected error, exp=%q got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]struct {
+		// errs are the errors returned by the successive dry-run creates,
+		// which succeed once they are exhausted
+		errs []error
+		wait time.Duration
+
+		expErr     bool
+		expCreates int
+		expOut     string
+		expErrOut  string
+	}{
+		"ready": {
+			expCreates: 1,
+			expOut:     "The cert-manager API is ready\n",
+		},
+		"not ready without waiting": {
+			errs:       []error{errWebhook},
+			expErr:     true,
+			expCreates: 1,
+		},
+		"ready after waiting": {
+			errs:       []error{errNotFound},
+			wait:       time.Minute,
+			expCreates: 2,
+			expOut:     "The cert-manager API is ready\n",
+			expErrOut:  "Not ready: " + translateError(errNotFound).Error() + "\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := cmfake.NewSimpleClientset()
+			creates := 0
+			client.PrependReactor("create", "certificates", func(action coretesting.Action) (bool, runtime.Object, error) {
+				creates++
+				if creates <= len(test.errs) {
+					return true, nil, test.errs[creates-1]
+				}
+				return false, nil, nil
+			})
+
+			out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+			o := NewOptions(genericclioptions.IOStreams{Out: out, ErrOut: errOut})
+			o.CMClient = client
+			o.Namespace = "default"
+			o.Wait = test.wait
+			o.Interval = time.Millisecond
+
+			err := o.Run()
+			if test.expErr != (err != nil) {
+				t.Fatalf("expected error=%t got=%v", test.expErr, err)
+			}
+			if creates != test.expCreates {
+				t.Errorf("expected %d dry-run creates, got %d", test.expCreates, creates)
+			}
+			if out.String() != test.expOut {
+				t.Errorf("unexpected output, exp=%q got=%q", test.expOut, out.String())
+			}
+			if errOut.String() != test.expErrOut {
+				t.Errorf("unexpected error output, exp=%q got=%q", test.expErrOut, errOut.String())
+			}
+		})
+	}
+}
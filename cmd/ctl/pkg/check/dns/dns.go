@@ -0,0 +1,316 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	mdns "github.com/miekg/dns"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/pkg/acme"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	dnsutil "github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Check the propagation of the TXT record of a pending ACME DNS01 Challenge.
+
+The command reads the pending DNS01 Challenge for the domain, computes the TXT record the ACME server
+will look up, and queries each of the authoritative nameservers of the record's zone as well as the
+recursive nameservers cert-manager uses for its propagation self-check. It reports which of those
+servers are serving the expected value, which are still serving stale values and which are not serving
+the record at all.
+
+If no Challenge is given with --challenge, the pending DNS01 Challenges for the domain in the namespace
+are checked. The recursive nameservers default to those of this machine and should be set to the
+value of the --dns01-recursive-nameservers flag of the cert-manager controller, if it is set.
+
+The command exits with a non-zero exit code if any nameserver is not serving the expected value.`))
+
+	example = templates.Examples(i18n.T(`
+# Check the propagation of the TXT record of the pending DNS01 Challenge for example.com.
+kubectl cert-manager check dns example.com
+
+# Check the propagation of the TXT record of the Challenge 'my-crt-1234-5678' in the namespace 'my-namespace'.
+kubectl cert-manager check dns example.com --challenge my-crt-1234-5678 --namespace my-namespace
+
+# Check the propagation using the same recursive nameservers as the cert-manager controller.
+kubectl cert-manager check dns example.com --recursive-nameservers 8.8.8.8:53,1.1.1.1:53`))
+)
+
+const (
+	stateOK      = "OK"
+	stateStale   = "STALE"
+	stateMissing = "MISSING"
+	stateError   = "ERROR"
+)
+
+// Options is a struct to support check dns command
+type Options struct {
+	CMClient   cmclient.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace of the Challenges to check.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace string
+
+	// Domain is the domain to check the TXT record of
+	Domain string
+	// ChallengeName is the name of the Challenge to check. If empty, the
+	// pending DNS01 Challenges for Domain are checked.
+	ChallengeName string
+	// RecursiveNameservers are the recursive nameservers to query, in the
+	// format host:port
+	RecursiveNameservers []string
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdCheckDNS returns a cobra command for checking the propagation of
+// ACME DNS01 Challenge records
+func NewCmdCheckDNS(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "dns <domain>",
+		Short:   "Check the propagation of the TXT record of a pending ACME DNS01 Challenge",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().StringVar(&o.ChallengeName, "challenge", "",
+		"Name of the Challenge to check. If not set, the pending DNS01 Challenges for the domain are checked")
+	cmd.Flags().StringSliceVar(&o.RecursiveNameservers, "recursive-nameservers", dnsutil.RecursiveNameservers,
+		"Comma separated list of recursive nameservers to query, in the format host:port")
+
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) < 1 {
+		return errors.New("the domain to check is required")
+	}
+	if len(args) > 1 {
+		return errors.New("only one domain may be specified")
+	}
+
+	if len(o.RecursiveNameservers) == 0 {
+		return errors.New("at least one recursive nameserver is required")
+	}
+	for _, ns := range o.RecursiveNameservers {
+		if _, _, err := net.SplitHostPort(ns); err != nil {
+			return fmt.Errorf("invalid recursive nameserver %q, must be in the format host:port: %v", ns, err)
+		}
+	}
+
+	return nil
+}
+
+// Complete takes the command arguments and factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory, args []string) error {
+	var err error
+	o.Domain = normalizeDomain(args[0])
+
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes check dns command
+func (o *Options) Run() error {
+	ctx := context.TODO()
+
+	challenges, err := o.challenges(ctx)
+	if err != nil {
+		return err
+	}
+
+	failures := 0
+	for _, ch := range challenges {
+		n, err := o.checkChallenge(ch)
+		if err != nil {
+			return err
+		}
+		failures += n
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d nameserver(s) are not serving the expected TXT record", failures)
+	}
+	return nil
+}
+
+// challenges returns the Challenge named by o.ChallengeName, or the pending
+// DNS01 Challenges for o.Domain.
+func (o *Options) challenges(ctx context.Context) ([]*cmacme.Challenge, error) {
+	if o.ChallengeName != "" {
+		ch, err := o.CMClient.AcmeV1alpha2().Challenges(o.Namespace).Get(ctx, o.ChallengeName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if ch.Spec.Type != cmacme.ACMEChallengeTypeDNS01 {
+			return nil, fmt.Errorf("the Challenge %s is of type %s, not %s", ch.Name, ch.Spec.Type, cmacme.ACMEChallengeTypeDNS01)
+		}
+		if ch.Spec.DNSName != o.Domain {
+			return nil, fmt.Errorf("the Challenge %s is for domain %s, not %s", ch.Name, ch.Spec.DNSName, o.Domain)
+		}
+		return []*cmacme.Challenge{ch}, nil
+	}
+
+	list, err := o.CMClient.AcmeV1alpha2().Challenges(o.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing Challenge resources: %v", err)
+	}
+	challenges := pendingChallenges(list.Items, o.Domain)
+	if len(challenges) == 0 {
+		return nil, fmt.Errorf("no pending DNS01 Challenge found for domain %s in namespace %s", o.Domain, o.Namespace)
+	}
+	return challenges, nil
+}
+
+// checkChallenge prints the TXT records served for ch by each nameserver, and
+// returns the number of nameservers not serving the expected value.
+func (o *Options) checkChallenge(ch *cmacme.Challenge) (int, error) {
+	followCNAME := ch.Spec.Solver.DNS01 != nil && ch.Spec.Solver.DNS01.CNAMEStrategy == cmacme.FollowStrategy
+	fqdn, err := dnsutil.DNS01LookupFQDN(ch.Spec.DNSName, followCNAME, o.RecursiveNameservers...)
+	if err != nil {
+		return 0, fmt.Errorf("error looking up the record name for Challenge %s: %v", ch.Name, err)
+	}
+
+	fqdn, records, authErr := dnsutil.LookupTXTRecordsByNameserver(fqdn, o.RecursiveNameservers)
+	failures := printChallengeRecords(o.Out, ch, fqdn, records)
+	if authErr != nil {
+		fmt.Fprintf(o.Out, "  Could not determine the authoritative nameservers: %v\n", authErr)
+		failures++
+	}
+	return failures, nil
+}
+
+// pendingChallenges returns the DNS01 Challenges for domain that are not in
+// a final state.
+func pendingChallenges(challenges []cmacme.Challenge, domain string) []*cmacme.Challenge {
+	var pending []*cmacme.Challenge
+	for i := range challenges {
+		ch := &challenges[i]
+		if ch.Spec.Type == cmacme.ACMEChallengeTypeDNS01 &&
+			ch.Spec.DNSName == domain &&
+			!acme.IsFinalState(ch.Status.State) {
+			pending = append(pending, ch)
+		}
+	}
+	return pending
+}
+
+// printChallengeRecords prints the state of the TXT records served by each
+// nameserver, and returns the number of nameservers not serving the expected
+// value.
+func printChallengeRecords(out io.Writer, ch *cmacme.Challenge, fqdn string, records []dnsutil.NameserverTXTRecords) int {
+	fmt.Fprintf(out, "Challenge %s (%s):\n", ch.Name, ch.Spec.DNSName)
+	fmt.Fprintf(out, "  Expected record: %s TXT %q\n", fqdn, ch.Spec.Key)
+
+	failures := 0
+	printSection := func(title string, authoritative bool) {
+		fmt.Fprintf(out, "  %s:\n", title)
+		for _, r := range records {
+			if r.Authoritative != authoritative {
+				continue
+			}
+			state, detail := recordState(r, ch.Spec.Key)
+			if state != stateOK {
+				failures++
+			}
+			if detail != "" {
+				fmt.Fprintf(out, "    %s\t%s: %s\n", r.Nameserver, state, detail)
+			} else {
+				fmt.Fprintf(out, "    %s\t%s\n", r.Nameserver, state)
+			}
+		}
+	}
+	printSection("Authoritative nameservers", true)
+	printSection("Recursive nameservers", false)
+
+	return failures
+}
+
+// recordState returns whether the nameserver is serving the expected value,
+// and a description of what it is serving otherwise.
+func recordState(r dnsutil.NameserverTXTRecords, expected string) (string, string) {
+	if r.Err != nil {
+		return stateError, r.Err.Error()
+	}
+	if r.Rcode != mdns.RcodeSuccess && r.Rcode != mdns.RcodeNameError {
+		return stateError, fmt.Sprintf("returned %s", mdns.RcodeToString[r.Rcode])
+	}
+	for _, v := range r.Values {
+		if v == expected {
+			return stateOK, ""
+		}
+	}
+	if len(r.Values) == 0 {
+		return stateMissing, fmt.Sprintf("no TXT records (%s)", mdns.RcodeToString[r.Rcode])
+	}
+	quoted := make([]string, len(r.Values))
+	for i, v := range r.Values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return stateStale, "serving " + strings.Join(quoted, ", ")
+}
+
+// normalizeDomain returns the domain in the form used in the spec of
+// Challenges, i.e. without a trailing dot or wildcard label.
+func normalizeDomain(domain string) string {
+	return strings.TrimPrefix(strings.TrimSuffix(domain, "."), "*.")
+}
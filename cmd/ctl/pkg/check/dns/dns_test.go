@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"errors"
+	"testing"
+
+	mdns "github.com/miekg/dns"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	dnsutil "github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
+)
+
+func TestRecordState(t *testing.T) {
+	tests := map[string]struct {
+		records  dnsutil.NameserverTXTRecords
+		expState string
+	}{
+		"serving the expected value": {
+			records:  dnsutil.NameserverTXTRecords{Rcode: mdns.RcodeSuccess, Values: []string{"old", "expected"}},
+			expState: stateOK,
+		},
+		"serving only stale values": {
+			records:  dnsutil.NameserverTXTRecords{Rcode: mdns.RcodeSuccess, Values: []string{"old"}},
+			expState: stateStale,
+		},
+		"record does not exist": {
+			records:  dnsutil.NameserverTXTRecords{Rcode: mdns.RcodeNameError},
+			expState: stateMissing,
+		},
+		"nameserver fails to answer": {
+			records:  dnsutil.NameserverTXTRecords{Rcode: mdns.RcodeServerFailure},
+			expState: stateError,
+		},
+		"query fails": {
+			records:  dnsutil.NameserverTXTRecords{Err: errors.New("i/o timeout")},
+			expState: stateError,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			state, detail := recordState(test.records, "expected")
+			if state != test.expState {
+				t.Errorf("expected state %s, got: %s (%s)", test.expState, state, detail)
+			}
+			if state != stateOK && detail == "" {
+				t.Errorf("expected a description of the %s state", state)
+			}
+		})
+	}
+}
+
+func TestPendingChallenges(t *testing.T) {
+	newChallenge := func(name string, chType cmacme.ACMEChallengeType, dnsName string, state cmacme.State) cmacme.Challenge {
+		return cmacme.Challenge{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       cmacme.ChallengeSpec{Type: chType, DNSName: dnsName},
+			Status:     cmacme.ChallengeStatus{State: state},
+		}
+	}
+	challenges := []cmacme.Challenge{
+		newChallenge("pending", cmacme.ACMEChallengeTypeDNS01, "example.com", cmacme.Pending),
+		newChallenge("no-state", cmacme.ACMEChallengeTypeDNS01, "example.com", ""),
+		newChallenge("valid", cmacme.ACMEChallengeTypeDNS01, "example.com", cmacme.Valid),
+		newChallenge("http01", cmacme.ACMEChallengeTypeHTTP01, "example.com", cmacme.Pending),
+		newChallenge("other-domain", cmacme.ACMEChallengeTypeDNS01, "example.org", cmacme.Pending),
+	}
+
+	pending := pendingChallenges(challenges, normalizeDomain("*.example.com."))
+	var names []string
+	for _, ch := range pending {
+		names = append(names, ch.Name)
+	}
+	if len(names) != 2 || names[0] != "pending" || names[1] != "no-state" {
+		t.Errorf("expected Challenges [pending no-state], got: %v", names)
+	}
+}
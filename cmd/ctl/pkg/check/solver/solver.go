@@ -0,0 +1,259 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package solver
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	"github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/http"
+	"github.com/jetstack/cert-manager/pkg/util"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Check if the resources the ACME HTTP01 solver creates for Challenges would be admitted by the API server.
+
+For each HTTP01 Challenge, the check performs a server-side dry-run create of the solver Pod, Service
+and Ingress, or a dry-run update of the existing Ingress if the solver is configured to use one.
+This catches failures caused by RBAC, resource quotas, LimitRanges or pod security policies before
+the solver is needed, e.g. a solver pod that would never be scheduled. Nothing is persisted in the cluster.
+
+If no Challenge names are given, all HTTP01 Challenges in the namespace are checked. DNS01 Challenges
+do not create any resources in the cluster and are skipped.
+
+The solver image and resources flags should match the flags the cert-manager controller is run with.
+The command exits with a non-zero exit code if any solver resource would be rejected.`))
+
+	example = templates.Examples(i18n.T(`
+# Check the solver resources of all HTTP01 Challenges in the default namespace.
+kubectl cert-manager check solver
+
+# Check the solver resources of the Challenge 'my-crt-1234-5678' in the namespace 'my-namespace'.
+kubectl cert-manager check solver my-crt-1234-5678 --namespace my-namespace`))
+)
+
+// Options is a struct to support check solver command
+type Options struct {
+	CMClient   cmclient.Interface
+	KubeClient kubernetes.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace of the Challenges to check.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace string
+
+	// Names of the Challenges to check. If empty, all HTTP01 Challenges in
+	// the namespace are checked.
+	Names []string
+
+	HTTP01SolverImage                 string
+	HTTP01SolverResourceRequestCPU    string
+	HTTP01SolverResourceRequestMemory string
+	HTTP01SolverResourceLimitsCPU     string
+	HTTP01SolverResourceLimitsMemory  string
+
+	acmeOptions controller.ACMEOptions
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdCheckSolver returns a cobra command for checking the ACME HTTP01
+// solver resources of Challenges
+func NewCmdCheckSolver(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "solver [challenge...]",
+		Short:   "Check if the ACME HTTP01 solver resources of Challenges would be admitted",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory, args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	// The defaults match those of the cert-manager controller
+	cmd.Flags().StringVar(&o.HTTP01SolverImage, "acme-http01-solver-image",
+		fmt.Sprintf("quay.io/jetstack/cert-manager-acmesolver:%s", util.AppVersion),
+		"The docker image the cert-manager controller uses to solve ACME HTTP01 challenges")
+	cmd.Flags().StringVar(&o.HTTP01SolverResourceRequestCPU, "acme-http01-solver-resource-request-cpu", "10m",
+		"The CPU resource request of the ACME HTTP01 solver pods")
+	cmd.Flags().StringVar(&o.HTTP01SolverResourceRequestMemory, "acme-http01-solver-resource-request-memory", "64Mi",
+		"The memory resource request of the ACME HTTP01 solver pods")
+	cmd.Flags().StringVar(&o.HTTP01SolverResourceLimitsCPU, "acme-http01-solver-resource-limits-cpu", "100m",
+		"The CPU resource limit of the ACME HTTP01 solver pods")
+	cmd.Flags().StringVar(&o.HTTP01SolverResourceLimitsMemory, "acme-http01-solver-resource-limits-memory", "64Mi",
+		"The memory resource limit of the ACME HTTP01 solver pods")
+
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	quantities := []struct {
+		flag  string
+		value string
+		dest  *resource.Quantity
+	}{
+		{"acme-http01-solver-resource-request-cpu", o.HTTP01SolverResourceRequestCPU, &o.acmeOptions.HTTP01SolverResourceRequestCPU},
+		{"acme-http01-solver-resource-request-memory", o.HTTP01SolverResourceRequestMemory, &o.acmeOptions.HTTP01SolverResourceRequestMemory},
+		{"acme-http01-solver-resource-limits-cpu", o.HTTP01SolverResourceLimitsCPU, &o.acmeOptions.HTTP01SolverResourceLimitsCPU},
+		{"acme-http01-solver-resource-limits-memory", o.HTTP01SolverResourceLimitsMemory, &o.acmeOptions.HTTP01SolverResourceLimitsMemory},
+	}
+	for _, q := range quantities {
+		quantity, err := resource.ParseQuantity(q.value)
+		if err != nil {
+			return fmt.Errorf("invalid --%s %q: %v", q.flag, q.value, err)
+		}
+		*q.dest = quantity
+	}
+	o.acmeOptions.HTTP01SolverImage = o.HTTP01SolverImage
+
+	return nil
+}
+
+// Complete takes the command arguments and factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory, args []string) error {
+	var err error
+	o.Names = args
+
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	o.KubeClient, err = kubernetes.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes check solver command
+func (o *Options) Run() error {
+	ctx := context.TODO()
+
+	challenges, err := o.challenges(ctx)
+	if err != nil {
+		return err
+	}
+	if len(challenges) == 0 {
+		fmt.Fprintf(o.Out, "No HTTP01 Challenges found in namespace %s\n", o.Namespace)
+		return nil
+	}
+
+	failures := 0
+	for _, ch := range challenges {
+		if ch.Spec.Type != cmacme.ACMEChallengeTypeHTTP01 {
+			fmt.Fprintf(o.Out, "Challenge %s is of type %s, skipping\n", ch.Name, ch.Spec.Type)
+			continue
+		}
+		results, err := http.DryRun(ctx, o.KubeClient, ch, o.acmeOptions)
+		if err != nil {
+			fmt.Fprintf(o.Out, "Challenge %s (%s): %v\n", ch.Name, ch.Spec.DNSName, err)
+			failures++
+			continue
+		}
+		failures += printResults(o.Out, ch, results)
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d solver resource(s) would not be admitted", failures)
+	}
+	return nil
+}
+
+// challenges returns the Challenges named in o.Names, or all HTTP01
+// Challenges in the namespace if no names were given.
+func (o *Options) challenges(ctx context.Context) ([]*cmacme.Challenge, error) {
+	var challenges []*cmacme.Challenge
+	if len(o.Names) > 0 {
+		for _, name := range o.Names {
+			ch, err := o.CMClient.AcmeV1alpha2().Challenges(o.Namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			challenges = append(challenges, ch)
+		}
+		return challenges, nil
+	}
+
+	list, err := o.CMClient.AcmeV1alpha2().Challenges(o.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing Challenge resources: %v", err)
+	}
+	for i := range list.Items {
+		if list.Items[i].Spec.Type == cmacme.ACMEChallengeTypeHTTP01 {
+			challenges = append(challenges, &list.Items[i])
+		}
+	}
+	return challenges, nil
+}
+
+// printResults prints the dry-run results of the solver resources of ch and
+// returns the number of resources that would not be admitted.
+func printResults(out io.Writer, ch *cmacme.Challenge, results []http.DryRunResult) int {
+	fmt.Fprintf(out, "Challenge %s (%s):\n", ch.Name, ch.Spec.DNSName)
+	failures := 0
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Fprintf(out, "  %s: FAILED: %v\n", result.Kind, result.Err)
+			failures++
+			continue
+		}
+		if result.Name != "" {
+			fmt.Fprintf(out, "  %s %q: OK\n", result.Kind, result.Name)
+		} else {
+			fmt.Fprintf(out, "  %s: OK\n", result.Kind)
+		}
+	}
+	return failures
+}
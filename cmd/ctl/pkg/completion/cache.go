@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// nameCache caches listed resource names on disk, as every completion runs
+// in a new process. Errors reading or writing the cache are ignored, so
+// that completion falls back to listing resources.
+type nameCache struct {
+	// dir is the directory cache files are stored in. The cache is
+	// disabled if dir is empty.
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+type cacheEntry struct {
+	Expires time.Time `json:"expires"`
+	Names   []string  `json:"names"`
+}
+
+// cacheKey returns the key of the names of the given resource in the
+// namespace of the cluster with the given API server host.
+func cacheKey(host, namespace, resource string) string {
+	sum := sha256.Sum256([]byte(host + "\x00" + namespace + "\x00" + resource))
+	return hex.EncodeToString(sum[:])
+}
+
+// get returns the cached names for key, and false if they are not cached or
+// have expired.
+func (c *nameCache) get(key string) ([]string, bool) {
+	if c.dir == "" {
+		return nil, false
+	}
+	data, err := ioutil.ReadFile(filepath.Join(c.dir, key))
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	if !c.now().Before(entry.Expires) {
+		return nil, false
+	}
+	return entry.Names, true
+}
+
+// set caches names for key.
+func (c *nameCache) set(key string, names []string) {
+	if c.dir == "" {
+		return
+	}
+	data, err := json.Marshal(cacheEntry{Expires: c.now().Add(c.ttl), Names: names})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return
+	}
+	// Write to a temporary file first, so that concurrent completions
+	// never read a partially written entry
+	tmp, err := ioutil.TempFile(c.dir, key+".tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(c.dir, key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNameCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "completion")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	c := &nameCache{
+		dir: filepath.Join(dir, "cache"),
+		ttl: 10 * time.Second,
+		now: func() time.Time { return now },
+	}
+	key := cacheKey("https://example.com", "default", "certificates")
+
+	if _, ok := c.get(key); ok {
+		t.Fatal("expected no cached names before they are set")
+	}
+
+	names := []string{"a", "b"}
+	c.set(key, names)
+	if got, ok := c.get(key); !ok || !reflect.DeepEqual(got, names) {
+		t.Errorf("expected cached names %v, got %v (cached=%t)", names, got, ok)
+	}
+
+	otherKey := cacheKey("https://example.com", "other", "certificates")
+	if _, ok := c.get(otherKey); ok {
+		t.Error("expected no cached names for a different namespace")
+	}
+
+	now = now.Add(10 * time.Second)
+	if _, ok := c.get(key); ok {
+		t.Error("expected cached names to expire after the TTL")
+	}
+
+	files, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("expected no temporary files to be left in the cache directory, got %d files", len(files))
+	}
+}
+
+func TestNameCacheDisabled(t *testing.T) {
+	c := &nameCache{}
+	key := cacheKey("https://example.com", "default", "certificates")
+	c.set(key, []string{"a"})
+	if _, ok := c.get(key); ok {
+		t.Error("expected a cache without directory to never return names")
+	}
+}
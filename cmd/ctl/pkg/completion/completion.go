@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Output the shell completion code for the specified shell (bash, zsh or fish).
+The completion code must be evaluated to provide interactive completion of commands, flags and the names of
+cert-manager resources.
+
+Resource names are completed by listing the resources in the namespace given by --namespace, or the namespace
+of the current context. The lists are cached for a few seconds to keep completion responsive.
+
+Completion only works for the plugin binary itself, e.g. kubectl-cert_manager, as kubectl does not delegate
+completion to plugins.`))
+
+	example = templates.Examples(i18n.T(`
+# Load the completion code for bash into the current shell
+source <(kubectl-cert_manager completion bash)
+
+# Load the completion code for zsh into the current shell
+source <(kubectl-cert_manager completion zsh)
+
+# Write the completion code for fish to the completions directory
+kubectl-cert_manager completion fish > ~/.config/fish/completions/kubectl-cert_manager.fish`))
+)
+
+var shells = []string{"bash", "zsh", "fish"}
+
+// Options is a struct to support completion command
+type Options struct {
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdCompletion returns a cobra command for completion
+func NewCmdCompletion(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:       "completion <shell>",
+		Short:     "Output shell completion code for the specified shell (bash, zsh or fish)",
+		Long:      long,
+		Example:   example,
+		ValidArgs: shells,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Run(cmd.Root(), filepath.Base(os.Args[0]), args[0]))
+		},
+	}
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) < 1 {
+		return errors.New("the shell has to be provided as argument, one of bash, zsh or fish")
+	}
+	if len(args) > 1 {
+		return errors.New("only one argument can be passed in: the shell")
+	}
+	return nil
+}
+
+// Run executes completion command. The completion code is registered for
+// the binary with the given name, as the name of the root command does not
+// match the name of the plugin binary.
+func (o *Options) Run(root *cobra.Command, binary, shell string) error {
+	root.Use = binary
+	switch shell {
+	case "bash":
+		return root.GenBashCompletion(o.Out)
+	case "zsh":
+		return root.GenZshCompletion(o.Out)
+	case "fish":
+		return root.GenFishCompletion(o.Out, true)
+	default:
+		return fmt.Errorf("unsupported shell %q, must be one of bash, zsh or fish", shell)
+	}
+}
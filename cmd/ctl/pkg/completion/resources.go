@@ -0,0 +1,255 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+)
+
+const (
+	// listTimeout is the maximum time spent listing resources to complete
+	// their names, so that completion does not hang on unreachable clusters
+	listTimeout = 5 * time.Second
+	// cacheTTL is the time listed resource names are reused for
+	cacheTTL = 10 * time.Second
+)
+
+// ValidArgsFunc completes the arguments of a command, see
+// cobra.Command.ValidArgsFunction.
+type ValidArgsFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// nameLister lists the names of a kind of resource in the given namespace.
+// Cluster scoped resources ignore the namespace.
+type nameLister func(ctx context.Context, cl cmclient.Interface, namespace string) ([]string, error)
+
+// resource is a kind of cert-manager resource whose names can be completed
+type resource struct {
+	name       string
+	kind       string
+	namespaced bool
+	list       nameLister
+}
+
+var (
+	certificates = resource{
+		name:       "certificates",
+		kind:       cmapi.CertificateKind,
+		namespaced: true,
+		list: func(ctx context.Context, cl cmclient.Interface, namespace string) ([]string, error) {
+			list, err := cl.CertmanagerV1alpha2().Certificates(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			var names []string
+			for _, obj := range list.Items {
+				names = append(names, obj.Name)
+			}
+			return names, nil
+		},
+	}
+	certificateRequests = resource{
+		name:       "certificaterequests",
+		kind:       cmapi.CertificateRequestKind,
+		namespaced: true,
+		list: func(ctx context.Context, cl cmclient.Interface, namespace string) ([]string, error) {
+			list, err := cl.CertmanagerV1alpha2().CertificateRequests(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			var names []string
+			for _, obj := range list.Items {
+				names = append(names, obj.Name)
+			}
+			return names, nil
+		},
+	}
+	issuers = resource{
+		name:       "issuers",
+		kind:       cmapi.IssuerKind,
+		namespaced: true,
+		list: func(ctx context.Context, cl cmclient.Interface, namespace string) ([]string, error) {
+			list, err := cl.CertmanagerV1alpha2().Issuers(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			var names []string
+			for _, obj := range list.Items {
+				names = append(names, obj.Name)
+			}
+			return names, nil
+		},
+	}
+	clusterIssuers = resource{
+		name: "clusterissuers",
+		kind: cmapi.ClusterIssuerKind,
+		list: func(ctx context.Context, cl cmclient.Interface, _ string) ([]string, error) {
+			list, err := cl.CertmanagerV1alpha2().ClusterIssuers().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			var names []string
+			for _, obj := range list.Items {
+				names = append(names, obj.Name)
+			}
+			return names, nil
+		},
+	}
+)
+
+// CertificateNames completes the names of Certificates in the namespace of
+// the command. At most maxArgs names are completed, or any number if
+// maxArgs is zero.
+func CertificateNames(factory cmdutil.Factory, maxArgs int) ValidArgsFunc {
+	return completeNames(factory, certificates, 0, maxArgs)
+}
+
+// CertificateRequestNames completes the names of CertificateRequests in the
+// namespace of the command. At most maxArgs names are completed, or any
+// number if maxArgs is zero.
+func CertificateRequestNames(factory cmdutil.Factory, maxArgs int) ValidArgsFunc {
+	return completeNames(factory, certificateRequests, 0, maxArgs)
+}
+
+// IssuerNames completes the name of an Issuer in the namespace of the
+// command.
+func IssuerNames(factory cmdutil.Factory) ValidArgsFunc {
+	return completeNames(factory, issuers, 0, 1)
+}
+
+// ClusterIssuerNames completes the name of a ClusterIssuer.
+func ClusterIssuerNames(factory cmdutil.Factory) ValidArgsFunc {
+	return completeNames(factory, clusterIssuers, 0, 1)
+}
+
+// ResourceTypeAndNames completes one of resourceTypes as first argument,
+// followed by the names of resources of that type. kindOf returns the kind
+// of resource of a resource type given as argument, including aliases. The
+// names of resources of other kinds than Certificate, CertificateRequest,
+// Issuer and ClusterIssuer are not completed.
+func ResourceTypeAndNames(factory cmdutil.Factory, resourceTypes []string, kindOf func(string) (string, error)) ValidArgsFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return filterNames(resourceTypes, nil, toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+		kind, err := kindOf(args[0])
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		for _, res := range []resource{certificates, certificateRequests, issuers, clusterIssuers} {
+			if res.kind == kind {
+				return completeNames(factory, res, 1, 0)(cmd, args, toComplete)
+			}
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeNames completes the names of resources of res, skipping the
+// first offset arguments of the command. At most maxArgs names are
+// completed, or any number if maxArgs is zero.
+func completeNames(factory cmdutil.Factory, res resource, offset, maxArgs int) ValidArgsFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if maxArgs > 0 && len(args)-offset >= maxArgs {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		all, err := listNames(factory, res)
+		if err != nil {
+			cobra.CompDebugln(err.Error(), false)
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return filterNames(all, args[offset:], toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// listNames lists the names of resources of res in the namespace of the
+// current context, or in the namespace given by --namespace. Names are read
+// from the cache if they have been listed recently.
+func listNames(factory cmdutil.Factory, res resource) ([]string, error) {
+	restConfig, err := factory.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	namespace := ""
+	if res.namespaced {
+		namespace, _, err = factory.ToRawKubeConfigLoader().Namespace()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cache := newNameCache()
+	key := cacheKey(restConfig.Host, namespace, res.name)
+	if names, ok := cache.get(key); ok {
+		return names, nil
+	}
+
+	cl, err := cmclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), listTimeout)
+	defer cancel()
+	names, err := res.list(ctx, cl, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.set(key, names)
+	return names, nil
+}
+
+// filterNames returns the sorted names that start with toComplete and have
+// not already been given as arguments.
+func filterNames(names, args []string, toComplete string) []string {
+	given := make(map[string]bool, len(args))
+	for _, arg := range args {
+		given[arg] = true
+	}
+	var filtered []string
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) && !given[name] {
+			filtered = append(filtered, name)
+		}
+	}
+	sort.Strings(filtered)
+	return filtered
+}
+
+func newNameCache() *nameCache {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		// Completion still works without a cache, only slower
+		return &nameCache{}
+	}
+	return &nameCache{
+		dir: filepath.Join(dir, "cert-manager", "completion"),
+		ttl: cacheTTL,
+		now: time.Now,
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterNames(t *testing.T) {
+	names := []string{"web-tls", "api-tls", "web-internal-tls"}
+
+	tests := map[string]struct {
+		args       []string
+		toComplete string
+		exp        []string
+	}{
+		"all names are completed sorted": {
+			exp: []string{"api-tls", "web-internal-tls", "web-tls"},
+		},
+		"names are filtered by prefix": {
+			toComplete: "web-",
+			exp:        []string{"web-internal-tls", "web-tls"},
+		},
+		"names given as arguments are not completed again": {
+			args:       []string{"web-tls"},
+			toComplete: "web-",
+			exp:        []string{"web-internal-tls"},
+		},
+		"no names match": {
+			toComplete: "db",
+			exp:        nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := filterNames(names, test.args, test.toComplete); !reflect.DeepEqual(got, test.exp) {
+				t.Errorf("expected %v, got %v", test.exp, got)
+			}
+		})
+	}
+}
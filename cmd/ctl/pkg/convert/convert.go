@@ -18,6 +18,7 @@ package convert
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
@@ -33,6 +34,7 @@ import (
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
 
+	cmacmev1alpha2 "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
 	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	"github.com/jetstack/cert-manager/pkg/ctl"
 )
@@ -42,6 +44,12 @@ var (
 		# Convert 'cert.yaml' to latest version and print to stdout.
 		kubectl cert-manager convert -f cert.yaml
 
+		# Convert all manifests in the 'manifests' directory and its subdirectories to 'cert-manager.io/v1beta1'
+		kubectl cert-manager convert -R -f ./manifests --output-version cert-manager.io/v1beta1
+
+		# Convert a manifest read from stdin to 'cert-manager.io/v1alpha3'
+		cat cert.yaml | kubectl cert-manager convert -f - --output-version cert-manager.io/v1alpha3
+
 		# Convert kustomize overlay under current directory to 'cert-manager.io/v1alpha3'
 		kubectl cert-manager convert -k . --output-version cert-manager.io/v1alpha3`))
 
@@ -49,9 +57,10 @@ var (
 Convert cert-manager config files between different API versions. Both YAML
 and JSON formats are accepted.
 
-The command takes filename, directory, or URL as input, and converts into the
-format of the version specified by --output-version flag. If target version is
-not specified or not supported, it will convert to the latest version
+The command takes filename, directory, URL or '-' for stdin as input, and
+converts into the format of the version specified by --output-version flag. If
+target version is not specified, it will convert to the latest version. An
+error is returned if the target version is not served by cert-manager.
 
 The default output will be printed to stdout in YAML format. One can use -o option
 to change to output destination.`))
@@ -130,13 +139,13 @@ func (o *Options) Run() error {
 		LocalParam(true).FilenameParam(false, &o.FilenameOptions).Flatten().Do()
 
 	if err := r.Err(); err != nil {
-		return fmt.Errorf("error here: %s", err)
+		return fmt.Errorf("error reading manifests: %s", err)
 	}
 
 	singleItemImplied := false
 	infos, err := r.IntoSingleItemImplied(&singleItemImplied).Infos()
 	if err != nil {
-		return fmt.Errorf("error here instead: %s", err)
+		return fmt.Errorf("error decoding manifests: %s", err)
 	}
 
 	if len(infos) == 0 {
@@ -149,6 +158,9 @@ func (o *Options) Run() error {
 		if err != nil {
 			return err
 		}
+		if err := validateOutputVersion(specifiedOutputVersion); err != nil {
+			return err
+		}
 	}
 
 	factory := serializer.NewCodecFactory(scheme)
@@ -162,6 +174,22 @@ func (o *Options) Run() error {
 	return o.Printer.PrintObj(objects, o.Out)
 }
 
+// validateOutputVersion returns an error if the given version is not an
+// external version of a cert-manager API group.
+func validateOutputVersion(gv schema.GroupVersion) error {
+	var supported []string
+	for _, group := range []string{cmapiv1alpha2.SchemeGroupVersion.Group, cmacmev1alpha2.SchemeGroupVersion.Group} {
+		for _, version := range scheme.PrioritizedVersionsForGroup(group) {
+			if version == gv {
+				return nil
+			}
+			supported = append(supported, version.String())
+		}
+	}
+
+	return fmt.Errorf("unsupported output version %q, must be one of: %s", gv, strings.Join(supported, ", "))
+}
+
 // asVersionedObject converts a list of infos into a single object - either a List containing
 // the objects as children, or if only a single Object is present, as that object. The provided
 // version will be preferred as the conversion target, but the Object's mapping version will be
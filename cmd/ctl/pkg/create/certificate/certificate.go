@@ -0,0 +1,418 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	"github.com/jetstack/cert-manager/pkg/ctl"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Create a new Certificate resource by answering questions about its most common fields.
+
+The issuer is chosen from the Issuers in the namespace and the ClusterIssuers installed in the cluster.
+Answers are checked as they are given, and the resulting Certificate is validated before it is printed,
+or created in the cluster if --apply is set. Press enter to accept the default shown in brackets.`))
+
+	example = templates.Examples(i18n.T(`
+# Answer questions about the Certificate named 'my-app' and print it as YAML
+kubectl cert-manager create certificate my-app > my-app-certificate.yaml
+
+# Answer questions about the Certificate named 'my-app' and create it in the 'sandbox' namespace
+kubectl cert-manager create certificate my-app --namespace sandbox --apply
+`))
+)
+
+var (
+	defaultDuration = cmapiv1alpha2.DefaultCertificateDuration
+	defaultUsages   = []cmapiv1alpha2.KeyUsage{cmapiv1alpha2.UsageDigitalSignature, cmapiv1alpha2.UsageKeyEncipherment}
+)
+
+// Options is a struct to support create certificate command
+type Options struct {
+	CMClient   cmclient.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace that the Certificate is created in.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace string
+
+	// Apply creates the Certificate in the cluster instead of printing it
+	Apply bool
+
+	PrintFlags *genericclioptions.PrintFlags
+	Printer    printers.ResourcePrinter
+
+	genericclioptions.IOStreams
+}
+
+// issuerChoice is an issuer the Certificate can reference
+type issuerChoice struct {
+	Ref   cmmeta.ObjectReference
+	Ready bool
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams:  ioStreams,
+		PrintFlags: genericclioptions.NewPrintFlags("created").WithDefaultOutput("yaml"),
+	}
+}
+
+// NewCmdCreateCertificate returns a cobra command for create certificate
+func NewCmdCreateCertificate(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "certificate <name>",
+		Aliases: []string{"cert"},
+		Short:   "Interactively create a cert-manager Certificate resource",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run(args))
+		},
+	}
+	cmd.Flags().BoolVar(&o.Apply, "apply", o.Apply, "If true, the Certificate is created in the cluster instead of being printed")
+	o.PrintFlags.AddFlags(cmd)
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) < 1 {
+		return errors.New("the name of the Certificate to be created has to be provided as argument")
+	}
+	if len(args) > 1 {
+		return errors.New("only one argument can be passed in: the name of the Certificate")
+	}
+	return nil
+}
+
+// Complete takes the command arguments and factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	o.Printer, err = o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes create certificate command
+func (o *Options) Run(args []string) error {
+	ctx := context.TODO()
+
+	issuers, err := listIssuers(ctx, o.CMClient, o.Namespace)
+	if err != nil {
+		return err
+	}
+	if len(issuers) == 0 {
+		return fmt.Errorf("no Issuers found in namespace %q and no ClusterIssuers found, create an issuer first", o.Namespace)
+	}
+
+	// Questions are written to ErrOut, so that the manifest written to Out
+	// can be redirected to a file
+	crt, err := runWizard(newPrompter(o.In, o.ErrOut), o.Namespace, args[0], issuers)
+	if err != nil {
+		return err
+	}
+
+	if o.Apply {
+		crt, err = o.CMClient.CertmanagerV1alpha2().Certificates(o.Namespace).Create(ctx, crt, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("error creating Certificate: %w", err)
+		}
+		fmt.Fprintf(o.ErrOut, "Certificate %s has been created in namespace %s\n", crt.Name, crt.Namespace)
+		return nil
+	}
+
+	crt.SetGroupVersionKind(cmapiv1alpha2.SchemeGroupVersion.WithKind(cmapiv1alpha2.CertificateKind))
+	return o.Printer.PrintObj(crt, o.Out)
+}
+
+// listIssuers returns the Issuers in namespace and all ClusterIssuers
+func listIssuers(ctx context.Context, cmClient cmclient.Interface, namespace string) ([]issuerChoice, error) {
+	var choices []issuerChoice
+	readyCondition := cmapiv1alpha2.IssuerCondition{Type: cmapiv1alpha2.IssuerConditionReady, Status: cmmeta.ConditionTrue}
+
+	issuers, err := cmClient.CertmanagerV1alpha2().Issuers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing Issuers: %w", err)
+	}
+	for i := range issuers.Items {
+		issuer := &issuers.Items[i]
+		choices = append(choices, issuerChoice{
+			Ref:   cmmeta.ObjectReference{Name: issuer.Name, Kind: cmapiv1alpha2.IssuerKind},
+			Ready: apiutil.IssuerHasCondition(issuer, readyCondition),
+		})
+	}
+
+	clusterIssuers, err := cmClient.CertmanagerV1alpha2().ClusterIssuers().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing ClusterIssuers: %w", err)
+	}
+	for i := range clusterIssuers.Items {
+		issuer := &clusterIssuers.Items[i]
+		choices = append(choices, issuerChoice{
+			Ref:   cmmeta.ObjectReference{Name: issuer.Name, Kind: cmapiv1alpha2.ClusterIssuerKind},
+			Ready: apiutil.IssuerHasCondition(issuer, readyCondition),
+		})
+	}
+
+	return choices, nil
+}
+
+// runWizard asks the questions needed to build the Certificate with the
+// given namespace and name, referencing one of issuers, and returns it once
+// it passes validation.
+func runWizard(p *prompter, namespace, name string, issuers []issuerChoice) (*cmapiv1alpha2.Certificate, error) {
+	crt := &cmapiv1alpha2.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}
+
+	var err error
+	if crt.Spec.SecretName, err = p.ask("Name of the Secret to store the certificate in", name+"-tls", nonEmpty); err != nil {
+		return nil, err
+	}
+	_, err = p.ask("Common name (optional)", "", func(answer string) error {
+		crt.Spec.CommonName = answer
+		return validateFields(crt, "spec.commonName")
+	})
+	if err != nil {
+		return nil, err
+	}
+	dnsNames, err := p.ask("DNS names, comma separated", "", func(answer string) error {
+		if answer == "" && crt.Spec.CommonName == "" {
+			return errors.New("at least one DNS name is required if no common name is set")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	crt.Spec.DNSNames = splitList(dnsNames)
+
+	if crt.Spec.IssuerRef, err = askIssuer(p, issuers); err != nil {
+		return nil, err
+	}
+
+	// The answers below are validated by setting them on crt and validating
+	// the fields they are converted to
+	_, err = p.ask("Duration of the certificate", defaultDuration.String(), func(answer string) error {
+		d, err := time.ParseDuration(answer)
+		if err != nil {
+			return err
+		}
+		crt.Spec.Duration = &metav1.Duration{Duration: d}
+		return validateFields(crt, "spec.duration", "spec.renewBefore")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.ask("Private key algorithm (rsa or ecdsa)", string(cmapiv1alpha2.RSAKeyAlgorithm), func(answer string) error {
+		crt.Spec.KeyAlgorithm = cmapiv1alpha2.KeyAlgorithm(answer)
+		return validateFields(crt, "spec.privateKey.algorithm")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defaultKeySize := 2048
+	if crt.Spec.KeyAlgorithm == cmapiv1alpha2.ECDSAKeyAlgorithm {
+		defaultKeySize = 256
+	}
+	_, err = p.ask("Private key size in bits", strconv.Itoa(defaultKeySize), func(answer string) error {
+		size, err := strconv.Atoi(answer)
+		if err != nil {
+			return fmt.Errorf("invalid key size %q", answer)
+		}
+		crt.Spec.KeySize = size
+		return validateFields(crt, "spec.privateKey.size")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.ask("Key usages, comma separated", joinUsages(defaultUsages), func(answer string) error {
+		crt.Spec.Usages = nil
+		for _, usage := range splitList(answer) {
+			crt.Spec.Usages = append(crt.Spec.Usages, cmapiv1alpha2.KeyUsage(usage))
+		}
+		return validateFields(crt, "spec.usages")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateCertificate(crt); err != nil {
+		return nil, err
+	}
+	return crt, nil
+}
+
+// askIssuer asks for the issuer of the Certificate, by number or name, out
+// of issuers. Issuers that are not ready can be chosen, with a warning.
+func askIssuer(p *prompter, issuers []issuerChoice) (cmmeta.ObjectReference, error) {
+	p.printf("Available issuers:\n")
+	for i, issuer := range issuers {
+		ready := ""
+		if !issuer.Ready {
+			ready = " (not ready)"
+		}
+		p.printf("  %d) %s %q%s\n", i+1, issuer.Ref.Kind, issuer.Ref.Name, ready)
+	}
+
+	var chosen *issuerChoice
+	_, err := p.ask("Issuer, by number or name", "1", func(answer string) error {
+		chosen = findIssuer(issuers, answer)
+		if chosen == nil {
+			return fmt.Errorf("no issuer %q found", answer)
+		}
+		return nil
+	})
+	if err != nil {
+		return cmmeta.ObjectReference{}, err
+	}
+	if !chosen.Ready {
+		p.printf("Warning: %s %q is not ready, the Certificate will not be issued until it is\n", chosen.Ref.Kind, chosen.Ref.Name)
+	}
+	return chosen.Ref, nil
+}
+
+// findIssuer returns the issuer with the given 1-based number or name. A
+// name matching both an Issuer and a ClusterIssuer can be qualified with the
+// kind, e.g. 'ClusterIssuer/ca'.
+func findIssuer(issuers []issuerChoice, answer string) *issuerChoice {
+	if i, err := strconv.Atoi(answer); err == nil {
+		if i < 1 || i > len(issuers) {
+			return nil
+		}
+		return &issuers[i-1]
+	}
+
+	kind, name := "", answer
+	if parts := strings.SplitN(answer, "/", 2); len(parts) == 2 {
+		kind, name = parts[0], parts[1]
+	}
+	for i := range issuers {
+		if issuers[i].Ref.Name == name && (kind == "" || strings.EqualFold(issuers[i].Ref.Kind, kind)) {
+			return &issuers[i]
+		}
+	}
+	return nil
+}
+
+// validateCertificate validates crt with the same rules as the webhook
+func validateCertificate(crt *cmapiv1alpha2.Certificate) error {
+	errs, err := ctl.ValidateCertificate(crt)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("the Certificate is not valid: %w", errs.ToAggregate())
+	}
+	return nil
+}
+
+// validateFields validates crt with the same rules as the webhook, only
+// returning the errors of fields starting with one of prefixes
+func validateFields(crt *cmapiv1alpha2.Certificate, prefixes ...string) error {
+	errs, err := ctl.ValidateCertificate(crt)
+	if err != nil {
+		return err
+	}
+	var fieldErrs field.ErrorList
+	for _, e := range errs {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(e.Field, prefix) {
+				fieldErrs = append(fieldErrs, e)
+				break
+			}
+		}
+	}
+	return fieldErrs.ToAggregate()
+}
+
+func nonEmpty(answer string) error {
+	if answer == "" {
+		return errors.New("a value is required")
+	}
+	return nil
+}
+
+// splitList splits a comma separated list, ignoring empty items
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func joinUsages(usages []cmapiv1alpha2.KeyUsage) string {
+	strs := make([]string, len(usages))
+	for i, usage := range usages {
+		strs[i] = string(usage)
+	}
+	return strings.Join(strs, ", ")
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestRunWizard(t *testing.T) {
+	issuers := []issuerChoice{
+		{Ref: cmmeta.ObjectReference{Name: "ca", Kind: cmapiv1alpha2.IssuerKind}, Ready: true},
+		{Ref: cmmeta.ObjectReference{Name: "ca", Kind: cmapiv1alpha2.ClusterIssuerKind}, Ready: true},
+		{Ref: cmmeta.ObjectReference{Name: "letsencrypt", Kind: cmapiv1alpha2.ClusterIssuerKind}},
+	}
+
+	tests := map[string]struct {
+		input string
+
+		expSpec   *cmapiv1alpha2.CertificateSpec
+		expErr    bool
+		expOutput []string
+	}{
+		"defaults accepted": {
+			input: "\n\nexample.com\n\n\n\n\n\n",
+			expSpec: &cmapiv1alpha2.CertificateSpec{
+				SecretName:   "test-tls",
+				DNSNames:     []string{"example.com"},
+				IssuerRef:    cmmeta.ObjectReference{Name: "ca", Kind: cmapiv1alpha2.IssuerKind},
+				Duration:     &metav1.Duration{Duration: cmapiv1alpha2.DefaultCertificateDuration},
+				KeyAlgorithm: cmapiv1alpha2.RSAKeyAlgorithm,
+				KeySize:      2048,
+				Usages:       []cmapiv1alpha2.KeyUsage{cmapiv1alpha2.UsageDigitalSignature, cmapiv1alpha2.UsageKeyEncipherment},
+			},
+		},
+		"invalid answers are asked again": {
+			input: strings.Join([]string{
+				"my-tls",
+				"",
+				"", // no DNS names without common name
+				"a.example.com, b.example.com",
+				"missing", // unknown issuer
+				"ClusterIssuer/ca",
+				"10m", // shorter than the minimum duration
+				"1440h",
+				"dsa", // unsupported algorithm
+				"ecdsa",
+				"255", // unsupported size
+				"",
+				"server auth, bogus", // unknown usage
+				"server auth",
+			}, "\n") + "\n",
+			expSpec: &cmapiv1alpha2.CertificateSpec{
+				SecretName:   "my-tls",
+				DNSNames:     []string{"a.example.com", "b.example.com"},
+				IssuerRef:    cmmeta.ObjectReference{Name: "ca", Kind: cmapiv1alpha2.ClusterIssuerKind},
+				Duration:     &metav1.Duration{Duration: 1440 * time.Hour},
+				KeyAlgorithm: cmapiv1alpha2.ECDSAKeyAlgorithm,
+				KeySize:      256,
+				Usages:       []cmapiv1alpha2.KeyUsage{cmapiv1alpha2.UsageServerAuth},
+			},
+			expOutput: []string{
+				"Invalid answer: at least one DNS name is required if no common name is set\n",
+				`Invalid answer: no issuer "missing" found`,
+			},
+		},
+		"issuer that is not ready": {
+			input: "\nexample.com\n\n3\n\n\n\n\n",
+			expSpec: &cmapiv1alpha2.CertificateSpec{
+				SecretName:   "test-tls",
+				CommonName:   "example.com",
+				IssuerRef:    cmmeta.ObjectReference{Name: "letsencrypt", Kind: cmapiv1alpha2.ClusterIssuerKind},
+				Duration:     &metav1.Duration{Duration: cmapiv1alpha2.DefaultCertificateDuration},
+				KeyAlgorithm: cmapiv1alpha2.RSAKeyAlgorithm,
+				KeySize:      2048,
+				Usages:       []cmapiv1alpha2.KeyUsage{cmapiv1alpha2.UsageDigitalSignature, cmapiv1alpha2.UsageKeyEncipherment},
+			},
+			expOutput: []string{`Warning: ClusterIssuer "letsencrypt" is not ready`},
+		},
+		"input ends before all questions are answered": {
+			input:  "\n\nexample.com\n",
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			crt, err := runWizard(newPrompter(strings.NewReader(test.input), out), "default", "test", issuers)
+			if (err != nil) != test.expErr {
+				t.Fatalf("expected error: %t, got: %v\noutput:\n%s", test.expErr, err, out)
+			}
+			if test.expErr {
+				return
+			}
+
+			if crt.Name != "test" || crt.Namespace != "default" {
+				t.Errorf("unexpected Certificate %s/%s", crt.Namespace, crt.Name)
+			}
+			if !reflect.DeepEqual(&crt.Spec, test.expSpec) {
+				t.Errorf("unexpected spec;\nexpected: %+v\ngot: %+v", test.expSpec, crt.Spec)
+			}
+			for _, exp := range test.expOutput {
+				if !strings.Contains(out.String(), exp) {
+					t.Errorf("expected output to contain %q, got:\n%s", exp, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// prompter asks questions on out and reads the answers from in, one per line
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+}
+
+// ask asks question until an answer passing validate, if not nil, is given.
+// An empty answer is replaced by def.
+func (p *prompter) ask(question, def string, validate func(answer string) error) (string, error) {
+	for {
+		if def != "" {
+			p.printf("%s [%s]: ", question, def)
+		} else {
+			p.printf("%s: ", question)
+		}
+
+		line, err := p.in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			if err == io.EOF {
+				return "", errors.New("input ended before all questions were answered")
+			}
+			return "", err
+		}
+
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = def
+		}
+		if validate == nil {
+			return answer, nil
+		}
+		if err := validate(answer); err != nil {
+			p.printf("Invalid answer: %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+func (p *prompter) printf(format string, a ...interface{}) {
+	fmt.Fprintf(p.out, format, a...)
+}
@@ -22,15 +22,18 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"time"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/i18n"
@@ -47,7 +50,13 @@ import (
 
 var (
 	long = templates.LongDesc(i18n.T(`
-Create a new CertificateRequest resource based on a Certificate resource, by generating a private key locally and create a 'certificate signing request' to be submitted to a cert-manager Issuer.`))
+Create a new CertificateRequest resource based on a Certificate resource, by generating a private key locally and create a 'certificate signing request' to be submitted to a cert-manager Issuer.
+
+Instead of a Certificate resource, the common name, DNS names and issuer to use can be given as flags.
+As the private key is generated locally, it is never stored in the cluster unless --output-secret is set.
+If --output-secret is set, the private key is only stored in the Secret and not written to a local file, so it
+is lost if the CertificateRequest is not signed before the timeout. An existing Secret is only replaced if
+--overwrite is set.`))
 
 	example = templates.Examples(i18n.T(`
 # Create a CertificateRequest with the name 'my-cr', saving the private key in a file named 'my-cr.key'.
@@ -64,6 +73,15 @@ kubectl cert-manager create certificaterequest my-cr --from-certificate-file my-
 
 # Create a CertificateRequest, wait for it to be signed for up to 20 minutes and store the x509 certificate in file 'my-cr.crt'.
 kubectl cert-manager create certificaterequest my-cr --from-certificate-file my-certificate.yaml --fetch-certificate --timeout 20m
+
+# Create a CertificateRequest for 'example.com' signed by the ClusterIssuer 'my-ca', without using a Certificate resource.
+kubectl cert-manager create certificaterequest my-cr --dns-names example.com --issuer-name my-ca --issuer-kind ClusterIssuer --fetch-certificate
+
+# Create a CertificateRequest and store the private key and signed certificate in the new Secret 'my-tls'.
+kubectl cert-manager create certificaterequest my-cr --from-certificate-file my-certificate.yaml --fetch-certificate --output-secret my-tls
+
+# Create a CertificateRequest and replace the private key and certificate stored in the existing Secret 'my-tls'.
+kubectl cert-manager create certificaterequest my-cr --from-certificate-file my-certificate.yaml --fetch-certificate --output-secret my-tls --overwrite
 `))
 )
 
@@ -76,6 +94,7 @@ var (
 // Options is a struct to support create certificaterequest command
 type Options struct {
 	CMClient   cmclient.Interface
+	KubeClient kubernetes.Interface
 	RESTConfig *restclient.Config
 	// Namespace resulting from the merged result of all overrides
 	// since namespace can be specified in file, as flag and in kube config
@@ -93,8 +112,26 @@ type Options struct {
 	CertFileName string
 	// Path to a file containing a Certificate resource used as a template
 	// when generating the CertificateRequest resource
-	// Required
+	// Required, unless IssuerName is set
 	InputFilename string
+	// Name of the Secret that the private key and x509 certificate will be
+	// stored in if --fetch-certificate flag is set
+	// If set, the private key is not written to a file
+	SecretName string
+	// If true, the private key and x509 certificate replace those stored in
+	// the Secret named SecretName if it already exists
+	Overwrite bool
+
+	// Fields used to build the Certificate template from flags when no
+	// InputFilename is given
+	CommonName  string
+	DNSNames    []string
+	IPAddresses []string
+	URISANs     []string
+	IssuerName  string
+	IssuerKind  string
+	IssuerGroup string
+	Duration    time.Duration
 	// Length of time the command blocks to wait on CertificateRequest to be ready if --fetch-certificate flag is set
 	// If not specified, default value is 5 minutes
 	Timeout time.Duration
@@ -134,6 +171,26 @@ func NewCmdCreateCR(ioStreams genericclioptions.IOStreams, factory cmdutil.Facto
 		"If set to true, command will wait for CertificateRequest to be signed to store x509 certificate in a file")
 	cmd.Flags().DurationVar(&o.Timeout, "timeout", 5*time.Minute,
 		"Time before timeout when waiting for CertificateRequest to be signed, must include unit, e.g. 10m or 1h")
+	cmd.Flags().StringVar(&o.SecretName, "output-secret", o.SecretName,
+		"Name of a Secret in the CertificateRequest's namespace that the private key and certificate will be stored in instead of a private key file, requires --fetch-certificate")
+	cmd.Flags().BoolVar(&o.Overwrite, "overwrite", o.Overwrite,
+		"If set to true, the private key and certificate replace those stored in the Secret set by --output-secret if it already exists")
+	cmd.Flags().StringVar(&o.CommonName, "common-name", o.CommonName,
+		"Common name of the requested certificate, used if --from-certificate-file is not set")
+	cmd.Flags().StringSliceVar(&o.DNSNames, "dns-names", o.DNSNames,
+		"Comma separated list of DNS names of the requested certificate, used if --from-certificate-file is not set")
+	cmd.Flags().StringSliceVar(&o.IPAddresses, "ip-addresses", o.IPAddresses,
+		"Comma separated list of IP addresses of the requested certificate, used if --from-certificate-file is not set")
+	cmd.Flags().StringSliceVar(&o.URISANs, "uri-sans", o.URISANs,
+		"Comma separated list of URI SANs of the requested certificate, used if --from-certificate-file is not set")
+	cmd.Flags().StringVar(&o.IssuerName, "issuer-name", o.IssuerName,
+		"Name of the issuer that will sign the CertificateRequest, used if --from-certificate-file is not set")
+	cmd.Flags().StringVar(&o.IssuerKind, "issuer-kind", cmapiv1alpha2.IssuerKind,
+		"Kind of the issuer that will sign the CertificateRequest, used if --from-certificate-file is not set")
+	cmd.Flags().StringVar(&o.IssuerGroup, "issuer-group", o.IssuerGroup,
+		"API group of the issuer that will sign the CertificateRequest, used if --from-certificate-file is not set")
+	cmd.Flags().DurationVar(&o.Duration, "duration", o.Duration,
+		"Requested duration of the certificate, used if --from-certificate-file is not set")
 
 	return cmd
 }
@@ -147,8 +204,16 @@ func (o *Options) Validate(args []string) error {
 		return errors.New("only one argument can be passed in: the name of the CertificateRequest")
 	}
 
-	if o.InputFilename == "" {
-		return errors.New("the path to a YAML manifest of a Certificate resource cannot be empty, please specify by using --from-certificate-file flag")
+	if o.InputFilename == "" && o.IssuerName == "" {
+		return errors.New("either the path to a YAML manifest of a Certificate resource has to be specified by using --from-certificate-file flag, or the issuer by using --issuer-name flag")
+	}
+
+	if o.InputFilename != "" && o.IssuerName != "" {
+		return errors.New("cannot specify --issuer-name in conjunction with --from-certificate-file, the issuer is read from the Certificate resource")
+	}
+
+	if o.IssuerName != "" && o.CommonName == "" && len(o.DNSNames) == 0 && len(o.IPAddresses) == 0 && len(o.URISANs) == 0 {
+		return errors.New("at least one of --common-name, --dns-names, --ip-addresses or --uri-sans must be set when not using --from-certificate-file")
 	}
 
 	if o.KeyFilename != "" && o.CertFileName != "" && o.KeyFilename == o.CertFileName {
@@ -159,6 +224,18 @@ func (o *Options) Validate(args []string) error {
 		return errors.New("cannot specify file to store certificate if not waiting for and fetching certificate, please set --fetch-certificate flag")
 	}
 
+	if !o.FetchCert && o.SecretName != "" {
+		return errors.New("cannot specify Secret to store certificate if not waiting for and fetching certificate, please set --fetch-certificate flag")
+	}
+
+	if o.SecretName != "" && o.KeyFilename != "" {
+		return errors.New("cannot specify file to store private key if storing it in a Secret, the private key is only stored in the Secret set by --output-secret")
+	}
+
+	if o.Overwrite && o.SecretName == "" {
+		return errors.New("cannot overwrite a Secret if no Secret is specified, please set --output-secret flag")
+	}
+
 	return nil
 }
 
@@ -181,48 +258,25 @@ func (o *Options) Complete(f cmdutil.Factory) error {
 		return err
 	}
 
-	return nil
-}
-
-// Run executes create certificaterequest command
-func (o *Options) Run(args []string) error {
-	builder := new(resource.Builder)
-
-	// Read file as internal API version
-	r := builder.
-		WithScheme(scheme, schema.GroupVersion{Group: cmapiv1alpha2.SchemeGroupVersion.Group, Version: runtime.APIVersionInternal}).
-		LocalParam(true).ContinueOnError().
-		NamespaceParam(o.CmdNamespace).DefaultNamespace().
-		FilenameParam(o.EnforceNamespace, &resource.FilenameOptions{Filenames: []string{o.InputFilename}}).Flatten().Do()
-
-	if err := r.Err(); err != nil {
-		return err
-	}
-
-	singleItemImplied := false
-	infos, err := r.IntoSingleItemImplied(&singleItemImplied).Infos()
+	o.KubeClient, err = kubernetes.NewForConfig(o.RESTConfig)
 	if err != nil {
 		return err
 	}
 
-	// Ensure only one object per command
-	if len(infos) == 0 {
-		return fmt.Errorf("no objects found in manifest file %q. Expected one Certificate object", o.InputFilename)
-	}
-	if len(infos) > 1 {
-		return fmt.Errorf("multiple objects found in manifest file %q. Expected only one Certificate object", o.InputFilename)
-	}
-	info := infos[0]
-	// Convert to v1alpha2 because that version is needed for functions that follow
-	crtObj, err := scheme.ConvertToVersion(info.Object, cmapiv1alpha2.SchemeGroupVersion)
-	if err != nil {
-		return fmt.Errorf("failed to convert object into version v1alpha2: %w", err)
-	}
+	return nil
+}
 
-	// Cast Object into Certificate
-	crt, ok := crtObj.(*cmapiv1alpha2.Certificate)
-	if !ok {
-		return errors.New("decoded object is not a v1alpha2 Certificate")
+// Run executes create certificaterequest command
+func (o *Options) Run(args []string) error {
+	var crt *cmapiv1alpha2.Certificate
+	if o.InputFilename != "" {
+		var err error
+		crt, err = o.certificateFromFile()
+		if err != nil {
+			return err
+		}
+	} else {
+		crt = o.certificateFromFlags()
 	}
 
 	signer, err := pki.GeneratePrivateKeyForCertificate(crt)
@@ -237,15 +291,28 @@ func (o *Options) Run(args []string) error {
 
 	crName := args[0]
 
-	// Storing private key to file
-	keyFileName := crName + ".key"
-	if o.KeyFilename != "" {
-		keyFileName = o.KeyFilename
+	ns := crt.Namespace
+	if ns == "" {
+		ns = o.CmdNamespace
 	}
-	if err := ioutil.WriteFile(keyFileName, keyData, 0600); err != nil {
-		return fmt.Errorf("error when writing private key to file: %w", err)
+
+	if o.SecretName != "" {
+		// fail before the CertificateRequest is created rather than once
+		// it has been signed
+		if err := o.checkSecretCanBeStored(context.TODO(), ns); err != nil {
+			return err
+		}
+	} else {
+		// Storing private key to file
+		keyFileName := crName + ".key"
+		if o.KeyFilename != "" {
+			keyFileName = o.KeyFilename
+		}
+		if err := ioutil.WriteFile(keyFileName, keyData, 0600); err != nil {
+			return fmt.Errorf("error when writing private key to file: %w", err)
+		}
+		fmt.Fprintf(o.ErrOut, "Private key written to file %s\n", keyFileName)
 	}
-	fmt.Fprintf(o.ErrOut, "Private key written to file %s\n", keyFileName)
 
 	// Build CertificateRequest with name as specified by argument
 	req, err := buildCertificateRequest(crt, keyData, crName)
@@ -253,10 +320,6 @@ func (o *Options) Run(args []string) error {
 		return fmt.Errorf("error when building CertificateRequest: %w", err)
 	}
 
-	ns := crt.Namespace
-	if ns == "" {
-		ns = o.CmdNamespace
-	}
 	req, err = o.CMClient.CertmanagerV1alpha2().CertificateRequests(ns).Create(context.TODO(), req, metav1.CreateOptions{})
 	if err != nil {
 		return fmt.Errorf("error creating CertificateRequest: %w", err)
@@ -291,11 +354,144 @@ func (o *Options) Run(args []string) error {
 			return fmt.Errorf("error when writing certificate to file: %w", err)
 		}
 		fmt.Fprintf(o.ErrOut, "Certificate written to file %s\n", actualCertFileName)
+
+		if o.SecretName != "" {
+			if err := o.storeInSecret(context.TODO(), req, keyData); err != nil {
+				return fmt.Errorf("error when writing private key and certificate to Secret: %w", err)
+			}
+			fmt.Fprintf(o.ErrOut, "Private key and certificate written to Secret %s in namespace %s\n", o.SecretName, req.Namespace)
+		}
 	}
 
 	return nil
 }
 
+// certificateFromFile reads the Certificate to be used as a template for the
+// CertificateRequest from the file given by InputFilename.
+func (o *Options) certificateFromFile() (*cmapiv1alpha2.Certificate, error) {
+	builder := new(resource.Builder)
+
+	// Read file as internal API version
+	r := builder.
+		WithScheme(scheme, schema.GroupVersion{Group: cmapiv1alpha2.SchemeGroupVersion.Group, Version: runtime.APIVersionInternal}).
+		LocalParam(true).ContinueOnError().
+		NamespaceParam(o.CmdNamespace).DefaultNamespace().
+		FilenameParam(o.EnforceNamespace, &resource.FilenameOptions{Filenames: []string{o.InputFilename}}).Flatten().Do()
+
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	singleItemImplied := false
+	infos, err := r.IntoSingleItemImplied(&singleItemImplied).Infos()
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure only one object per command
+	if len(infos) == 0 {
+		return nil, fmt.Errorf("no objects found in manifest file %q. Expected one Certificate object", o.InputFilename)
+	}
+	if len(infos) > 1 {
+		return nil, fmt.Errorf("multiple objects found in manifest file %q. Expected only one Certificate object", o.InputFilename)
+	}
+	info := infos[0]
+	// Convert to v1alpha2 because that version is needed for functions that follow
+	crtObj, err := scheme.ConvertToVersion(info.Object, cmapiv1alpha2.SchemeGroupVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert object into version v1alpha2: %w", err)
+	}
+
+	// Cast Object into Certificate
+	crt, ok := crtObj.(*cmapiv1alpha2.Certificate)
+	if !ok {
+		return nil, errors.New("decoded object is not a v1alpha2 Certificate")
+	}
+
+	return crt, nil
+}
+
+// certificateFromFlags builds a Certificate to be used as a template for the
+// CertificateRequest from the command's flags.
+func (o *Options) certificateFromFlags() *cmapiv1alpha2.Certificate {
+	crt := &cmapiv1alpha2.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: o.CmdNamespace,
+		},
+		Spec: cmapiv1alpha2.CertificateSpec{
+			CommonName:  o.CommonName,
+			DNSNames:    o.DNSNames,
+			IPAddresses: o.IPAddresses,
+			URISANs:     o.URISANs,
+			IssuerRef: cmmeta.ObjectReference{
+				Name:  o.IssuerName,
+				Kind:  o.IssuerKind,
+				Group: o.IssuerGroup,
+			},
+		},
+	}
+	if o.Duration > 0 {
+		crt.Spec.Duration = &metav1.Duration{Duration: o.Duration}
+	}
+	return crt
+}
+
+// checkSecretCanBeStored returns an error if the Secret named SecretName
+// already exists in namespace ns, unless Overwrite is set.
+func (o *Options) checkSecretCanBeStored(ctx context.Context, ns string) error {
+	if o.Overwrite {
+		return nil
+	}
+	_, err := o.KubeClient.CoreV1().Secrets(ns).Get(ctx, o.SecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error when reading Secret %s/%s: %w", ns, o.SecretName, err)
+	}
+	return fmt.Errorf("Secret %s/%s already exists, please set --overwrite flag to replace the private key and certificate stored in it", ns, o.SecretName)
+}
+
+// storeInSecret writes the private key and the signed certificate of req to
+// the Secret named SecretName, creating it if it does not exist. An existing
+// Secret is only updated if Overwrite is set.
+func (o *Options) storeInSecret(ctx context.Context, req *cmapiv1alpha2.CertificateRequest, keyData []byte) error {
+	data := map[string][]byte{
+		corev1.TLSPrivateKeyKey: keyData,
+		corev1.TLSCertKey:       req.Status.Certificate,
+		cmmeta.TLSCAKey:         req.Status.CA,
+	}
+
+	secret, err := o.KubeClient.CoreV1().Secrets(req.Namespace).Get(ctx, o.SecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      o.SecretName,
+				Namespace: req.Namespace,
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: data,
+		}
+		_, err = o.KubeClient.CoreV1().Secrets(req.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if !o.Overwrite {
+		return fmt.Errorf("Secret %s/%s already exists, please set --overwrite flag to replace the private key and certificate stored in it", req.Namespace, o.SecretName)
+	}
+
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	for k, v := range data {
+		secret.Data[k] = v
+	}
+	_, err = o.KubeClient.CoreV1().Secrets(req.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
 // Builds a CertificateRequest
 func buildCertificateRequest(crt *cmapiv1alpha2.Certificate, pk []byte, crName string) (*cmapiv1alpha2.CertificateRequest, error) {
 	csrPEM, err := generateCSR(crt, pk)
@@ -17,9 +17,24 @@ limitations under the License.
 package certificaterequest
 
 import (
+	"bytes"
+	"context"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	coretesting "k8s.io/client-go/testing"
+
+	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
 )
 
 func TestValidate(t *testing.T) {
@@ -29,6 +44,10 @@ func TestValidate(t *testing.T) {
 		keyFilename  string
 		certFilename string
 		fetchCert    bool
+		secretName   string
+		overwrite    bool
+		issuerName   string
+		dnsNames     []string
 
 		expErr    bool
 		expErrMsg string
@@ -49,7 +68,26 @@ func TestValidate(t *testing.T) {
 			inputFile: "",
 			inputArgs: []string{"hello"},
 			expErr:    true,
-			expErrMsg: "the path to a YAML manifest of a Certificate resource cannot be empty, please specify by using --from-certificate-file flag",
+			expErrMsg: "either the path to a YAML manifest of a Certificate resource has to be specified by using --from-certificate-file flag, or the issuer by using --issuer-name flag",
+		},
+		"issuer and SANs given as flags instead of yaml manifest": {
+			inputArgs:  []string{"hello"},
+			issuerName: "my-issuer",
+			dnsNames:   []string{"example.com"},
+			expErr:     false,
+		},
+		"cannot specify issuer name with yaml manifest": {
+			inputFile:  "example.yaml",
+			inputArgs:  []string{"hello"},
+			issuerName: "my-issuer",
+			expErr:     true,
+			expErrMsg:  "cannot specify --issuer-name in conjunction with --from-certificate-file, the issuer is read from the Certificate resource",
+		},
+		"specifying issuer name without any SANs throws error": {
+			inputArgs:  []string{"hello"},
+			issuerName: "my-issuer",
+			expErr:     true,
+			expErrMsg:  "at least one of --common-name, --dns-names, --ip-addresses or --uri-sans must be set when not using --from-certificate-file",
 		},
 		"key filename and cert filename are optional flags": {
 			inputFile:    "example.yaml",
@@ -74,6 +112,38 @@ func TestValidate(t *testing.T) {
 			expErr:       true,
 			expErrMsg:    "cannot specify file to store certificate if not waiting for and fetching certificate, please set --fetch-certificate flag",
 		},
+		"cannot specify secret name without fetch-certificate flag": {
+			inputFile:  "example.yaml",
+			inputArgs:  []string{"hello"},
+			secretName: "my-tls",
+			fetchCert:  false,
+			expErr:     true,
+			expErrMsg:  "cannot specify Secret to store certificate if not waiting for and fetching certificate, please set --fetch-certificate flag",
+		},
+		"cannot specify key filename when storing the key in a secret": {
+			inputFile:   "example.yaml",
+			inputArgs:   []string{"hello"},
+			keyFilename: "tls.key",
+			secretName:  "my-tls",
+			fetchCert:   true,
+			expErr:      true,
+			expErrMsg:   "cannot specify file to store private key if storing it in a Secret, the private key is only stored in the Secret set by --output-secret",
+		},
+		"cannot overwrite without secret name": {
+			inputFile: "example.yaml",
+			inputArgs: []string{"hello"},
+			overwrite: true,
+			expErr:    true,
+			expErrMsg: "cannot overwrite a Secret if no Secret is specified, please set --output-secret flag",
+		},
+		"secret name can be overwritten": {
+			inputFile:  "example.yaml",
+			inputArgs:  []string{"hello"},
+			secretName: "my-tls",
+			overwrite:  true,
+			fetchCert:  true,
+			expErr:     false,
+		},
 	}
 
 	for name, test := range tests {
@@ -83,6 +153,10 @@ func TestValidate(t *testing.T) {
 				KeyFilename:   test.keyFilename,
 				CertFileName:  test.certFilename,
 				FetchCert:     test.fetchCert,
+				SecretName:    test.secretName,
+				Overwrite:     test.overwrite,
+				IssuerName:    test.issuerName,
+				DNSNames:      test.dnsNames,
 			}
 
 			// Validating args and flags
@@ -240,3 +314,158 @@ spec:
 		})
 	}
 }
+
+// TestRunOutputSecret tests that the private key and certificate are only
+// stored in the Secret given by --output-secret, which is not replaced unless
+// --overwrite is set.
+func TestRunOutputSecret(t *testing.T) {
+	const (
+		crName     = "testcr-4"
+		ns         = "testns-1"
+		secretName = "my-tls"
+	)
+
+	existingSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: secretName},
+		Data: map[string][]byte{
+			corev1.TLSCertKey: []byte("old-cert"),
+			"other":           []byte("other"),
+		},
+	}
+
+	tests := map[string]struct {
+		existingSecret *corev1.Secret
+		overwrite      bool
+
+		expErr    bool
+		expErrMsg string
+		// expCreated is true if the CertificateRequest is expected to be
+		// created
+		expCreated bool
+		// expSecretData are the expected values of the Secret's data, a
+		// nil value means the key is expected to be set to any value
+		expSecretData map[string][]byte
+	}{
+		"create the Secret if it does not exist": {
+			expCreated: true,
+			expSecretData: map[string][]byte{
+				corev1.TLSCertKey:       []byte("cert"),
+				corev1.TLSPrivateKeyKey: nil,
+				cmmeta.TLSCAKey:         []byte("ca"),
+			},
+		},
+		"do not replace an existing Secret without --overwrite": {
+			existingSecret: existingSecret,
+			expErr:         true,
+			expErrMsg:      "Secret testns-1/my-tls already exists, please set --overwrite flag to replace the private key and certificate stored in it",
+			expSecretData:  existingSecret.Data,
+		},
+		"replace the private key and certificate of an existing Secret with --overwrite": {
+			existingSecret: existingSecret,
+			overwrite:      true,
+			expCreated:     true,
+			expSecretData: map[string][]byte{
+				corev1.TLSCertKey:       []byte("cert"),
+				corev1.TLSPrivateKeyKey: nil,
+				cmmeta.TLSCAKey:         []byte("ca"),
+				"other":                 []byte("other"),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "create-cr")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			inputFile := filepath.Join(dir, "certificate.yaml")
+			if err := ioutil.WriteFile(inputFile, []byte(`---
+apiVersion: cert-manager.io/v1alpha2
+kind: Certificate
+metadata:
+  name: testcert-1
+  namespace: testns-1
+spec:
+  secretName: my-tls
+  commonName: my-app
+  issuerRef:
+    name: ca-issuer
+`), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var kubeObjects []runtime.Object
+			if test.existingSecret != nil {
+				kubeObjects = append(kubeObjects, test.existingSecret.DeepCopy())
+			}
+			kubeClient := kubefake.NewSimpleClientset(kubeObjects...)
+			cmClient := cmfake.NewSimpleClientset()
+			// sign the CertificateRequest as soon as it is created
+			cmClient.PrependReactor("create", "certificaterequests", func(action coretesting.Action) (bool, runtime.Object, error) {
+				cr := action.(coretesting.CreateAction).GetObject().(*cmapiv1alpha2.CertificateRequest)
+				cr.Status.Certificate = []byte("cert")
+				cr.Status.CA = []byte("ca")
+				cr.Status.Conditions = []cmapiv1alpha2.CertificateRequestCondition{
+					{Type: cmapiv1alpha2.CertificateRequestConditionReady, Status: cmmeta.ConditionTrue},
+				}
+				return false, nil, nil
+			})
+
+			opts := NewOptions(genericclioptions.IOStreams{Out: new(bytes.Buffer), ErrOut: new(bytes.Buffer)})
+			opts.CMClient = cmClient
+			opts.KubeClient = kubeClient
+			opts.CmdNamespace = ns
+			opts.InputFilename = inputFile
+			opts.CertFileName = filepath.Join(dir, "tls.crt")
+			opts.FetchCert = true
+			opts.Timeout = time.Minute
+			opts.SecretName = secretName
+			opts.Overwrite = test.overwrite
+
+			if err := opts.Validate([]string{crName}); err != nil {
+				t.Fatal(err)
+			}
+			err = opts.Run([]string{crName})
+			defer os.Remove(crName + ".key")
+			if err != nil {
+				if !test.expErr {
+					t.Fatalf("got unexpected error when trying to create CR: %v", err)
+				}
+				if err.Error() != test.expErrMsg {
+					t.Fatalf("got unexpected error when trying to create CR, expected: %v; actual: %v", test.expErrMsg, err)
+				}
+			} else if test.expErr {
+				t.Errorf("expected but got no error when creating CR")
+			}
+
+			if _, err := os.Stat(crName + ".key"); !os.IsNotExist(err) {
+				t.Errorf("expected private key not to be written to a file when storing it in a Secret")
+			}
+
+			crs, err := cmClient.CertmanagerV1alpha2().CertificateRequests(ns).List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if created := len(crs.Items) > 0; created != test.expCreated {
+				t.Errorf("expected CertificateRequest created=%t, got %t", test.expCreated, created)
+			}
+
+			secret, err := kubeClient.CoreV1().Secrets(ns).Get(context.TODO(), secretName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(secret.Data) != len(test.expSecretData) {
+				t.Errorf("unexpected Secret data keys, exp=%d got=%d", len(test.expSecretData), len(secret.Data))
+			}
+			for k, v := range test.expSecretData {
+				got, ok := secret.Data[k]
+				if !ok || (v != nil && !bytes.Equal(got, v)) {
+					t.Errorf("unexpected value of Secret data key %q, exp=%q got=%q", k, v, got)
+				}
+			}
+		})
+	}
+}
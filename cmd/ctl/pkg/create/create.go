@@ -21,6 +21,7 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/create/certificate"
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/create/certificaterequest"
 )
 
@@ -28,9 +29,10 @@ func NewCmdCreate(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory
 	cmds := &cobra.Command{
 		Use:   "create",
 		Short: "Create cert-manager resources",
-		Long:  `Create cert-manager resources e.g. a CertificateRequest`,
+		Long:  `Create cert-manager resources e.g. a Certificate or a CertificateRequest`,
 	}
 
+	cmds.AddCommand(certificate.NewCmdCreateCertificate(ioStreams, factory))
 	cmds.AddCommand(certificaterequest.NewCmdCreateCR(ioStreams, factory))
 
 	return cmds
@@ -0,0 +1,243 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+)
+
+const (
+	// archiveKind and archiveVersion identify the format of archives
+	archiveKind    = "CertManagerBackup"
+	archiveVersion = "v1"
+
+	// encryptedMagic prefixes encrypted archives, followed by the scrypt
+	// salt, the AES-GCM nonce and the encrypted compressed archive
+	encryptedMagic = "cert-manager-backup-encrypted-v1\n"
+	saltSize       = 16
+
+	// scrypt parameters recommended for interactive use in 2017
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// Archive holds the cert-manager resources and Secrets exported by the
+// backup command. Server populated metadata and status are stripped, except
+// for the revision of Certificates.
+type Archive struct {
+	Kind      string      `json:"kind"`
+	Version   string      `json:"version"`
+	CreatedAt metav1.Time `json:"createdAt"`
+
+	Certificates   []cmapi.Certificate   `json:"certificates,omitempty"`
+	Issuers        []cmapi.Issuer        `json:"issuers,omitempty"`
+	ClusterIssuers []cmapi.ClusterIssuer `json:"clusterIssuers,omitempty"`
+	Secrets        []corev1.Secret       `json:"secrets,omitempty"`
+}
+
+// encodeArchive returns the archive as gzip compressed JSON, encrypted with
+// a key derived from passphrase if it is not empty.
+func encodeArchive(archive *Archive, passphrase string) ([]byte, error) {
+	archive.Kind, archive.Version = archiveKind, archiveVersion
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(archive); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	if passphrase == "" {
+		return buf.Bytes(), nil
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	out := append([]byte(encryptedMagic), salt...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, buf.Bytes(), []byte(encryptedMagic)), nil
+}
+
+// decodeArchive decodes an archive returned by encodeArchive. passphrase is
+// required if the archive is encrypted.
+func decodeArchive(data []byte, passphrase string) (*Archive, error) {
+	if bytes.HasPrefix(data, []byte(encryptedMagic)) {
+		if passphrase == "" {
+			return nil, errors.New("the archive is encrypted, a passphrase is required")
+		}
+		encrypted := data[len(encryptedMagic):]
+		if len(encrypted) < saltSize {
+			return nil, errors.New("the encrypted archive is truncated")
+		}
+		aead, err := newAEAD(passphrase, encrypted[:saltSize])
+		if err != nil {
+			return nil, err
+		}
+		encrypted = encrypted[saltSize:]
+		if len(encrypted) < aead.NonceSize() {
+			return nil, errors.New("the encrypted archive is truncated")
+		}
+		nonce, ciphertext := encrypted[:aead.NonceSize()], encrypted[aead.NonceSize():]
+		data, err = aead.Open(nil, nonce, ciphertext, []byte(encryptedMagic))
+		if err != nil {
+			return nil, errors.New("the archive could not be decrypted, the passphrase may be wrong")
+		}
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing archive: %w", err)
+	}
+	jsonData, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing archive: %w", err)
+	}
+
+	archive := &Archive{}
+	if err := json.Unmarshal(jsonData, archive); err != nil {
+		return nil, fmt.Errorf("error decoding archive: %w", err)
+	}
+	if archive.Kind != archiveKind || archive.Version != archiveVersion {
+		return nil, fmt.Errorf("unsupported archive %s/%s", archive.Kind, archive.Version)
+	}
+	return archive, nil
+}
+
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// stripObjectMeta returns the metadata of an object that can be used to
+// create it in another cluster. Owner references are kept, to be rewritten
+// on restore.
+func stripObjectMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace:       meta.Namespace,
+		Name:            meta.Name,
+		Labels:          meta.Labels,
+		Annotations:     meta.Annotations,
+		OwnerReferences: meta.OwnerReferences,
+	}
+}
+
+// referencedSecretNames returns the names of the Secrets referenced by spec,
+// found as the 'name' of any field whose name contains 'secret', such as
+// 'privateKeySecretRef' or 'tokenSecretRef', or as the value of
+// 'secretName' fields. Venafi 'credentialsRef' fields are also included.
+func referencedSecretNames(spec interface{}) ([]string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	collectSecretNames(obj, names)
+
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted, nil
+}
+
+func collectSecretNames(obj interface{}, names map[string]bool) {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			isRef := strings.Contains(strings.ToLower(key), "secret") || key == "credentialsRef"
+			switch value := value.(type) {
+			case string:
+				if key == "secretName" && value != "" {
+					names[value] = true
+				}
+			case map[string]interface{}:
+				if name, ok := value["name"].(string); isRef && ok && name != "" {
+					names[name] = true
+				}
+			}
+			collectSecretNames(value, names)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectSecretNames(item, names)
+		}
+	}
+}
+
+// rewriteOwnerReferences returns the owner references of an object restored
+// in namespace, with the UIDs of references to Certificates replaced by the
+// UIDs of the restored Certificates, given by name. References to other
+// objects are dropped, since their UIDs are not valid in the new cluster.
+func rewriteOwnerReferences(refs []metav1.OwnerReference, certificateUIDs map[string]types.UID) []metav1.OwnerReference {
+	var rewritten []metav1.OwnerReference
+	for _, ref := range refs {
+		if ref.Kind != cmapi.CertificateKind || !strings.HasPrefix(ref.APIVersion, cmapi.SchemeGroupVersion.Group+"/") {
+			continue
+		}
+		uid, ok := certificateUIDs[ref.Name]
+		if !ok {
+			continue
+		}
+		ref.UID = uid
+		rewritten = append(rewritten, ref)
+	}
+	return rewritten
+}
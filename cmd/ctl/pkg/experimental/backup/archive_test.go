@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestEncodeDecodeArchive(t *testing.T) {
+	revision := 3
+	archive := &Archive{
+		Certificates: []cmapi.Certificate{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+			Spec:       cmapi.CertificateSpec{SecretName: "test-tls", DNSNames: []string{"example.com"}},
+			Status:     cmapi.CertificateStatus{Revision: &revision},
+		}},
+		Secrets: []corev1.Secret{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-tls"},
+			Type:       corev1.SecretTypeTLS,
+			Data:       map[string][]byte{corev1.TLSPrivateKeyKey: []byte("key")},
+		}},
+	}
+
+	tests := map[string]struct {
+		encryptPassphrase string
+		decryptPassphrase string
+		expErr            bool
+	}{
+		"unencrypted archive": {},
+		"unencrypted archive decoded with a passphrase": {
+			decryptPassphrase: "secret",
+		},
+		"encrypted archive": {
+			encryptPassphrase: "secret",
+			decryptPassphrase: "secret",
+		},
+		"encrypted archive decoded with the wrong passphrase": {
+			encryptPassphrase: "secret",
+			decryptPassphrase: "wrong",
+			expErr:            true,
+		},
+		"encrypted archive decoded without passphrase": {
+			encryptPassphrase: "secret",
+			expErr:            true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := encodeArchive(archive, test.encryptPassphrase)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := decodeArchive(data, test.decryptPassphrase)
+			if (err != nil) != test.expErr {
+				t.Fatalf("expected error: %t, got: %v", test.expErr, err)
+			}
+			if test.expErr {
+				return
+			}
+			if !reflect.DeepEqual(decoded.Certificates, archive.Certificates) || !reflect.DeepEqual(decoded.Secrets, archive.Secrets) {
+				t.Errorf("decoded archive does not match;\nexpected: %+v\ngot: %+v", archive, decoded)
+			}
+		})
+	}
+}
+
+func TestReferencedSecretNames(t *testing.T) {
+	tests := map[string]struct {
+		spec     interface{}
+		expNames []string
+	}{
+		"Certificate with a PKCS12 keystore": {
+			spec: cmapi.CertificateSpec{
+				SecretName: "test-tls",
+				Keystores: &cmapi.CertificateKeystores{
+					PKCS12: &cmapi.PKCS12Keystore{
+						Create:            true,
+						PasswordSecretRef: cmmeta.SecretKeySelector{LocalObjectReference: cmmeta.LocalObjectReference{Name: "keystore-password"}},
+					},
+				},
+			},
+			expNames: []string{"keystore-password", "test-tls"},
+		},
+		"CA issuer": {
+			spec: cmapi.IssuerSpec{IssuerConfig: cmapi.IssuerConfig{
+				CA: &cmapi.CAIssuer{SecretName: "ca-key-pair"},
+			}},
+			expNames: []string{"ca-key-pair"},
+		},
+		"ACME issuer with a DNS01 solver": {
+			spec: cmapi.IssuerSpec{IssuerConfig: cmapi.IssuerConfig{
+				ACME: &cmacme.ACMEIssuer{
+					PrivateKey: cmmeta.SecretKeySelector{LocalObjectReference: cmmeta.LocalObjectReference{Name: "account-key"}},
+					Solvers: []cmacme.ACMEChallengeSolver{{
+						DNS01: &cmacme.ACMEChallengeSolverDNS01{
+							Cloudflare: &cmacme.ACMEIssuerDNS01ProviderCloudflare{
+								APIToken: &cmmeta.SecretKeySelector{LocalObjectReference: cmmeta.LocalObjectReference{Name: "cloudflare-token"}},
+							},
+						},
+					}},
+				},
+			}},
+			expNames: []string{"account-key", "cloudflare-token"},
+		},
+		"self-signed issuer": {
+			spec: cmapi.IssuerSpec{IssuerConfig: cmapi.IssuerConfig{
+				SelfSigned: &cmapi.SelfSignedIssuer{},
+			}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			names, err := referencedSecretNames(test.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(names, test.expNames) {
+				t.Errorf("unexpected Secret names; expected: %v, got: %v", test.expNames, names)
+			}
+		})
+	}
+}
+
+func TestRewriteOwnerReferences(t *testing.T) {
+	controller := true
+	refs := []metav1.OwnerReference{
+		{APIVersion: "cert-manager.io/v1alpha2", Kind: "Certificate", Name: "test", UID: "old-uid", Controller: &controller},
+		{APIVersion: "cert-manager.io/v1alpha2", Kind: "Certificate", Name: "deleted", UID: "deleted-uid"},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "test", UID: "configmap-uid"},
+	}
+
+	rewritten := rewriteOwnerReferences(refs, map[string]types.UID{"test": "new-uid"})
+	expected := []metav1.OwnerReference{
+		{APIVersion: "cert-manager.io/v1alpha2", Kind: "Certificate", Name: "test", UID: "new-uid", Controller: &controller},
+	}
+	if !reflect.DeepEqual(rewritten, expected) {
+		t.Errorf("unexpected owner references; expected: %+v, got: %+v", expected, rewritten)
+	}
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+)
+
+var (
+	backupLong = templates.LongDesc(i18n.T(`
+Export the Certificates and Issuers of a namespace, or of all namespaces, all ClusterIssuers and the Secrets
+they reference to a single archive file, to be imported in another cluster with the restore command.
+
+Server populated metadata and the status of the resources are not exported, except for the revision of
+Certificates. The archive holds private keys and credentials in plain text unless --passphrase-file is set,
+in which case it is encrypted with a key derived from the passphrase.`))
+
+	backupExample = templates.Examples(i18n.T(`
+# Export the cert-manager resources of all namespaces to 'backup.cm', encrypted with the passphrase in 'passphrase.txt'
+kubectl cert-manager x backup backup.cm --all-namespaces --passphrase-file passphrase.txt
+
+# Export the cert-manager resources of the 'sandbox' namespace and all ClusterIssuers
+kubectl cert-manager x backup sandbox.cm --namespace sandbox
+`))
+)
+
+// Options is a struct to support backup and restore commands
+type Options struct {
+	KubeClient kubernetes.Interface
+	CMClient   cmclient.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace whose resources are exported.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace string
+	// AllNamespaces exports the resources of all namespaces
+	AllNamespaces bool
+	// ClusterResourceNamespace is the namespace the Secrets referenced by
+	// ClusterIssuers are stored in
+	ClusterResourceNamespace string
+	// PassphraseFile is the file holding the passphrase the archive is
+	// encrypted with, if set
+	PassphraseFile string
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdBackup returns a cobra command for exporting cert-manager resources
+func NewCmdBackup(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "backup <archive file>",
+		Short:   "Export cert-manager resources and their Secrets to an archive file",
+		Long:    backupLong,
+		Example: backupExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.RunBackup(args[0]))
+		},
+	}
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces,
+		"If true, export the resources of all namespaces")
+	cmd.Flags().StringVar(&o.ClusterResourceNamespace, "cluster-resource-namespace", "kube-system",
+		"Namespace the controller stores the Secrets referenced by ClusterIssuers in")
+	cmd.Flags().StringVar(&o.PassphraseFile, "passphrase-file", o.PassphraseFile,
+		"Path to a file holding the passphrase the archive is encrypted with")
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) < 1 {
+		return errors.New("the path to the archive file has to be provided as argument")
+	}
+	if len(args) > 1 {
+		return errors.New("only one argument can be passed in: the path to the archive file")
+	}
+	return nil
+}
+
+// Complete takes the command arguments and factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.KubeClient, err = kubernetes.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RunBackup executes backup command
+func (o *Options) RunBackup(path string) error {
+	ctx := context.TODO()
+
+	passphrase, err := o.readPassphrase()
+	if err != nil {
+		return err
+	}
+
+	namespace := o.Namespace
+	if o.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+	archive, err := o.collect(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	data, err := encodeArchive(archive, passphrase)
+	if err != nil {
+		return fmt.Errorf("error encoding archive: %w", err)
+	}
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("error writing archive: %w", err)
+	}
+
+	fmt.Fprintf(o.Out, "Exported %d Certificate(s), %d Issuer(s), %d ClusterIssuer(s) and %d Secret(s) to %s\n",
+		len(archive.Certificates), len(archive.Issuers), len(archive.ClusterIssuers), len(archive.Secrets), path)
+	if passphrase == "" {
+		fmt.Fprintf(o.ErrOut, "Warning: the archive is not encrypted and holds private keys, store it securely\n")
+	}
+	return nil
+}
+
+// collect gets the Certificates and Issuers in namespace, all ClusterIssuers
+// and the Secrets they reference, stripped of server populated fields.
+func (o *Options) collect(ctx context.Context, namespace string) (*Archive, error) {
+	archive := &Archive{CreatedAt: metav1.NewTime(time.Now())}
+	// secretRefs are the names of the referenced Secrets, by namespace
+	secretRefs := make(map[string][]string)
+	addSecretRefs := func(namespace string, spec interface{}) error {
+		names, err := referencedSecretNames(spec)
+		if err != nil {
+			return err
+		}
+		secretRefs[namespace] = append(secretRefs[namespace], names...)
+		return nil
+	}
+
+	crts, err := o.CMClient.CertmanagerV1alpha2().Certificates(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing Certificates: %w", err)
+	}
+	for _, crt := range crts.Items {
+		if err := addSecretRefs(crt.Namespace, crt.Spec); err != nil {
+			return nil, err
+		}
+		archive.Certificates = append(archive.Certificates, cmapi.Certificate{
+			ObjectMeta: stripObjectMeta(crt.ObjectMeta),
+			Spec:       crt.Spec,
+			// The revision is kept so that the revisions of
+			// CertificateRequests keep increasing after the restore
+			Status: cmapi.CertificateStatus{Revision: crt.Status.Revision},
+		})
+	}
+
+	issuers, err := o.CMClient.CertmanagerV1alpha2().Issuers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing Issuers: %w", err)
+	}
+	for _, issuer := range issuers.Items {
+		if err := addSecretRefs(issuer.Namespace, issuer.Spec); err != nil {
+			return nil, err
+		}
+		archive.Issuers = append(archive.Issuers, cmapi.Issuer{
+			ObjectMeta: stripObjectMeta(issuer.ObjectMeta),
+			Spec:       issuer.Spec,
+		})
+	}
+
+	clusterIssuers, err := o.CMClient.CertmanagerV1alpha2().ClusterIssuers().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing ClusterIssuers: %w", err)
+	}
+	for _, issuer := range clusterIssuers.Items {
+		if err := addSecretRefs(o.ClusterResourceNamespace, issuer.Spec); err != nil {
+			return nil, err
+		}
+		archive.ClusterIssuers = append(archive.ClusterIssuers, cmapi.ClusterIssuer{
+			ObjectMeta: stripObjectMeta(issuer.ObjectMeta),
+			Spec:       issuer.Spec,
+		})
+	}
+
+	// Secrets are exported sorted by namespace, for the archive to be
+	// deterministic
+	var namespaces []string
+	for secretNamespace := range secretRefs {
+		namespaces = append(namespaces, secretNamespace)
+	}
+	sort.Strings(namespaces)
+
+	seen := make(map[string]bool)
+	for _, secretNamespace := range namespaces {
+		for _, name := range secretRefs[secretNamespace] {
+			key := secretNamespace + "/" + name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			secret, err := o.KubeClient.CoreV1().Secrets(secretNamespace).Get(ctx, name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				fmt.Fprintf(o.ErrOut, "Warning: referenced Secret %s does not exist, skipping\n", key)
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error when getting Secret %s: %w", key, err)
+			}
+			archive.Secrets = append(archive.Secrets, corev1.Secret{
+				ObjectMeta: stripObjectMeta(secret.ObjectMeta),
+				Type:       secret.Type,
+				Data:       secret.Data,
+			})
+		}
+	}
+
+	return archive, nil
+}
+
+// readPassphrase returns the passphrase in PassphraseFile, or an empty
+// string if it is not set
+func (o *Options) readPassphrase() (string, error) {
+	if o.PassphraseFile == "" {
+		return "", nil
+	}
+	data, err := ioutil.ReadFile(o.PassphraseFile)
+	if err != nil {
+		return "", fmt.Errorf("error when reading passphrase file: %w", err)
+	}
+	passphrase := strings.TrimSpace(string(data))
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase file %q is empty", o.PassphraseFile)
+	}
+	return passphrase, nil
+}
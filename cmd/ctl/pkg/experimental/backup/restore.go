@@ -0,0 +1,187 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+)
+
+var (
+	restoreLong = templates.LongDesc(i18n.T(`
+Import the cert-manager resources and Secrets exported to an archive file by the backup command.
+
+Secrets are created first, so that the certificates they hold are re-used rather than issued again.
+The owner references of Secrets to Certificates are then rewritten to the restored Certificates, and
+the revision of each Certificate is restored, so that the revisions of new CertificateRequests keep
+increasing. Resources that already exist are left untouched.`))
+
+	restoreExample = templates.Examples(i18n.T(`
+# Import the cert-manager resources exported to 'backup.cm', decrypted with the passphrase in 'passphrase.txt'
+kubectl cert-manager x restore backup.cm --passphrase-file passphrase.txt
+`))
+)
+
+// NewCmdRestore returns a cobra command for importing cert-manager resources
+func NewCmdRestore(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "restore <archive file>",
+		Short:   "Import cert-manager resources and their Secrets from an archive file",
+		Long:    restoreLong,
+		Example: restoreExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.RunRestore(args[0]))
+		},
+	}
+	cmd.Flags().StringVar(&o.PassphraseFile, "passphrase-file", o.PassphraseFile,
+		"Path to a file holding the passphrase the archive was encrypted with")
+	return cmd
+}
+
+// RunRestore executes restore command
+func (o *Options) RunRestore(path string) error {
+	ctx := context.TODO()
+
+	passphrase, err := o.readPassphrase()
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading archive: %w", err)
+	}
+	archive, err := decodeArchive(data, passphrase)
+	if err != nil {
+		return err
+	}
+
+	// Secrets are created without owner references, as the Certificates
+	// owning them do not exist yet
+	for _, secret := range archive.Secrets {
+		secret := secret.DeepCopy()
+		secret.OwnerReferences = nil
+		_, err := o.KubeClient.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+		if err := o.checkCreate("Secret", secret.Namespace, secret.Name, err); err != nil {
+			return err
+		}
+	}
+
+	for _, issuer := range archive.Issuers {
+		issuer := issuer.DeepCopy()
+		_, err := o.CMClient.CertmanagerV1alpha2().Issuers(issuer.Namespace).Create(ctx, issuer, metav1.CreateOptions{})
+		if err := o.checkCreate("Issuer", issuer.Namespace, issuer.Name, err); err != nil {
+			return err
+		}
+	}
+
+	for _, issuer := range archive.ClusterIssuers {
+		issuer := issuer.DeepCopy()
+		_, err := o.CMClient.CertmanagerV1alpha2().ClusterIssuers().Create(ctx, issuer, metav1.CreateOptions{})
+		if err := o.checkCreate("ClusterIssuer", "", issuer.Name, err); err != nil {
+			return err
+		}
+	}
+
+	// certificateUIDs are the UIDs of the Certificates in the cluster, by
+	// namespace and name
+	certificateUIDs := make(map[string]map[string]types.UID)
+	for _, crt := range archive.Certificates {
+		crt := crt.DeepCopy()
+		revision := crt.Status.Revision
+		crt.Status = cmapi.CertificateStatus{}
+
+		created, err := o.CMClient.CertmanagerV1alpha2().Certificates(crt.Namespace).Create(ctx, crt, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			existing, err := o.CMClient.CertmanagerV1alpha2().Certificates(crt.Namespace).Get(ctx, crt.Name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("error getting Certificate %s/%s: %w", crt.Namespace, crt.Name, err)
+			}
+			fmt.Fprintf(o.ErrOut, "Certificate %s/%s already exists, skipping\n", crt.Namespace, crt.Name)
+			addUID(certificateUIDs, existing)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error creating Certificate %s/%s: %w", crt.Namespace, crt.Name, err)
+		}
+		addUID(certificateUIDs, created)
+
+		if revision != nil {
+			created.Status.Revision = revision
+			if _, err := o.CMClient.CertmanagerV1alpha2().Certificates(created.Namespace).UpdateStatus(ctx, created, metav1.UpdateOptions{}); err != nil {
+				return fmt.Errorf("error restoring the revision of Certificate %s/%s: %w", created.Namespace, created.Name, err)
+			}
+		}
+	}
+
+	for _, secret := range archive.Secrets {
+		refs := rewriteOwnerReferences(secret.OwnerReferences, certificateUIDs[secret.Namespace])
+		if len(refs) == 0 {
+			continue
+		}
+		existing, err := o.KubeClient.CoreV1().Secrets(secret.Namespace).Get(ctx, secret.Name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("error getting Secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+		existing.OwnerReferences = refs
+		if _, err := o.KubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("error updating the owner references of Secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+	}
+
+	fmt.Fprintf(o.Out, "Restored %d Certificate(s), %d Issuer(s), %d ClusterIssuer(s) and %d Secret(s) from %s\n",
+		len(archive.Certificates), len(archive.Issuers), len(archive.ClusterIssuers), len(archive.Secrets), path)
+	return nil
+}
+
+// checkCreate returns the error of creating the resource of the given kind,
+// namespace and name, if any. Resources that already exist are reported and
+// skipped.
+func (o *Options) checkCreate(kind, namespace, name string, err error) error {
+	if namespace != "" {
+		name = namespace + "/" + name
+	}
+	if apierrors.IsAlreadyExists(err) {
+		fmt.Fprintf(o.ErrOut, "%s %s already exists, skipping\n", kind, name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error creating %s %s: %w", kind, name, err)
+	}
+	return nil
+}
+
+func addUID(uids map[string]map[string]types.UID, crt *cmapi.Certificate) {
+	if uids[crt.Namespace] == nil {
+		uids[crt.Namespace] = make(map[string]types.UID)
+	}
+	uids[crt.Namespace][crt.Name] = crt.UID
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experimental
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/experimental/backup"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/experimental/keygen"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/experimental/scaffold"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/experimental/schema"
+)
+
+func NewCmdExperimental(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	cmds := &cobra.Command{
+		Use:     "experimental",
+		Aliases: []string{"x"},
+		Short:   "Interact with experimental features",
+		Long:    `Interact with experimental features, whose commands and flags may change in future releases`,
+	}
+
+	cmds.AddCommand(backup.NewCmdBackup(ioStreams, factory))
+	cmds.AddCommand(backup.NewCmdRestore(ioStreams, factory))
+	cmds.AddCommand(keygen.NewCmdKeygen(ioStreams))
+	cmds.AddCommand(scaffold.NewCmdScaffold(ioStreams, factory))
+	cmds.AddCommand(schema.NewCmdSchema(ioStreams))
+
+	return cmds
+}
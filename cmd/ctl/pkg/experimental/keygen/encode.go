@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keygen
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// encodingPKCS1 encodes RSA keys as PKCS#1 and ECDSA keys as SEC1, like
+	// the "pkcs1" key encoding of Certificates
+	encodingPKCS1 = "pkcs1"
+	encodingPKCS8 = "pkcs8"
+	encodingSEC1  = "sec1"
+
+	// pbkdf2Iterations is the number of PBKDF2 iterations used to derive the
+	// key encrypting PKCS#8 private keys
+	pbkdf2Iterations = 100000
+	pbkdf2SaltSize   = 16
+)
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// encryptedPrivateKeyInfo is the EncryptedPrivateKeyInfo structure of
+// RFC 5208, section 6
+type encryptedPrivateKeyInfo struct {
+	EncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedData       []byte
+}
+
+// pbes2Params are the PBES2-params of RFC 8018, appendix A.4
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params are the PBKDF2-params of RFC 8018, appendix A.2
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	PRF            pkix.AlgorithmIdentifier
+}
+
+// encodePrivateKey PEM encodes key with the given encoding. If passphrase is
+// not empty, the key is encrypted as an encrypted PKCS#8 private key, which
+// requires the PKCS#8 encoding.
+func encodePrivateKey(key crypto.Signer, encoding, passphrase string) ([]byte, error) {
+	var block *pem.Block
+	switch encoding {
+	case encodingPKCS1:
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}
+		case *ecdsa.PrivateKey:
+			return encodePrivateKey(key, encodingSEC1, passphrase)
+		default:
+			return nil, fmt.Errorf("keys of type %T cannot be encoded as %s, use %s", key, encoding, encodingPKCS8)
+		}
+	case encodingSEC1:
+		k, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("only ECDSA keys can be encoded as %s", encoding)
+		}
+		der, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding private key: %v", err)
+		}
+		block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+	case encodingPKCS8:
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding private key: %v", err)
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	default:
+		return nil, fmt.Errorf("unsupported key encoding %q", encoding)
+	}
+
+	if passphrase == "" {
+		return pem.EncodeToMemory(block), nil
+	}
+	if block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("only %s encoded keys can be encrypted", encodingPKCS8)
+	}
+	der, err := encryptPKCS8(block.Bytes, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: der}), nil
+}
+
+// encryptPKCS8 encrypts the DER encoded PKCS#8 private key der with PBES2,
+// using a key derived from passphrase with PBKDF2-HMAC-SHA256 and
+// AES-256-CBC, as supported by OpenSSL and most other tools.
+func encryptPKCS8(der []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, pbkdf2SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	key := pbkdf2.Key([]byte(passphrase), salt, pbkdf2Iterations, 32, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	padding := aes.BlockSize - len(der)%aes.BlockSize
+	encrypted := append(append([]byte{}, der...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, encrypted)
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:           salt,
+		IterationCount: pbkdf2Iterations,
+		PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ivParam, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParam}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		EncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData:       encrypted,
+	})
+}
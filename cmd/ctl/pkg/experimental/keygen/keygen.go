@@ -0,0 +1,214 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keygen
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Generate a private key locally, encoded like the private keys the cert-manager controllers generate and accept,
+so that keys generated outside of the cluster can be stored in the Secret of a Certificate.
+
+RSA keys are encoded as PKCS#1 and ECDSA keys as SEC1 with --encoding=pkcs1, the default, which matches the
+'pkcs1' key encoding of Certificates. Ed25519 keys can only be encoded as PKCS#8.
+
+With --passphrase-file, the key is written as an encrypted PKCS#8 private key, using PBES2 with PBKDF2 and
+AES-256-CBC. The controllers cannot read encrypted keys, so encrypted keys have to be decrypted before they are
+stored in a Secret.
+
+The key is written to the file given as argument with permissions 0600, or to stdout if no file is given.`))
+
+	example = templates.Examples(i18n.T(`
+# Generate a 2048 bit RSA private key, encoded as PKCS#1
+kubectl cert-manager x keygen tls.key
+
+# Generate an ECDSA private key on the P-384 curve, encoded as PKCS#8
+kubectl cert-manager x keygen tls.key --algorithm ecdsa --size 384 --encoding pkcs8
+
+# Generate an Ed25519 private key, encrypted with the passphrase in 'passphrase.txt'
+kubectl cert-manager x keygen tls.key --algorithm ed25519 --encoding pkcs8 --passphrase-file passphrase.txt
+`))
+)
+
+// ed25519KeyAlgorithm is the Ed25519 key algorithm, which is not supported
+// by Certificates yet
+const ed25519KeyAlgorithm = "ed25519"
+
+// Options is a struct to support keygen command
+type Options struct {
+	// Algorithm is the key algorithm, one of rsa, ecdsa or ed25519
+	Algorithm string
+	// Size is the size of RSA keys in bits, or the curve size of ECDSA keys.
+	// Zero means the default size of Certificates.
+	Size int
+	// Encoding is the key encoding, one of pkcs1, pkcs8 or sec1
+	Encoding string
+	// PassphraseFile is the file holding the passphrase the key is
+	// encrypted with, if set
+	PassphraseFile string
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		Algorithm: string(cmapi.RSAKeyAlgorithm),
+		Encoding:  encodingPKCS1,
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdKeygen returns a cobra command for keygen
+func NewCmdKeygen(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "keygen [file]",
+		Short:   "Generate a private key encoded like the keys of Certificates",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Run(args))
+		},
+	}
+	cmd.Flags().StringVar(&o.Algorithm, "algorithm", o.Algorithm, "The key algorithm, one of rsa, ecdsa or ed25519")
+	cmd.Flags().IntVar(&o.Size, "size", o.Size, "The size of RSA keys in bits (default 2048), or the curve size of ECDSA keys, one of 256 (default), 384 or 521")
+	cmd.Flags().StringVar(&o.Encoding, "encoding", o.Encoding, "The key encoding, one of pkcs1, pkcs8 or sec1. pkcs1 encodes ECDSA keys as SEC1")
+	cmd.Flags().StringVar(&o.PassphraseFile, "passphrase-file", o.PassphraseFile, "Path to a file holding the passphrase the key is encrypted with. Requires --encoding=pkcs8")
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) > 1 {
+		return errors.New("only one argument can be passed in: the path to the key file")
+	}
+	o.Algorithm = strings.ToLower(o.Algorithm)
+	o.Encoding = strings.ToLower(o.Encoding)
+
+	switch o.Algorithm {
+	case string(cmapi.RSAKeyAlgorithm), string(cmapi.ECDSAKeyAlgorithm):
+	case ed25519KeyAlgorithm:
+		if o.Size != 0 {
+			return errors.New("--size cannot be used with Ed25519 keys")
+		}
+		if o.Encoding != encodingPKCS8 {
+			return fmt.Errorf("Ed25519 keys can only be encoded as %s", encodingPKCS8)
+		}
+	default:
+		return fmt.Errorf("unsupported key algorithm %q, must be one of rsa, ecdsa or ed25519", o.Algorithm)
+	}
+
+	switch o.Encoding {
+	case encodingPKCS1, encodingPKCS8:
+	case encodingSEC1:
+		if o.Algorithm != string(cmapi.ECDSAKeyAlgorithm) {
+			return fmt.Errorf("only ECDSA keys can be encoded as %s", encodingSEC1)
+		}
+	default:
+		return fmt.Errorf("unsupported key encoding %q, must be one of pkcs1, pkcs8 or sec1", o.Encoding)
+	}
+
+	if o.PassphraseFile != "" && o.Encoding != encodingPKCS8 {
+		return fmt.Errorf("--passphrase-file can only be used with --encoding=%s", encodingPKCS8)
+	}
+	return nil
+}
+
+// Run executes keygen command
+func (o *Options) Run(args []string) error {
+	passphrase, err := o.readPassphrase()
+	if err != nil {
+		return err
+	}
+
+	key, err := generateKey(o.Algorithm, o.Size)
+	if err != nil {
+		return err
+	}
+	data, err := encodePrivateKey(key, o.Encoding, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		_, err := o.Out.Write(data)
+		return err
+	}
+	if err := ioutil.WriteFile(args[0], data, 0600); err != nil {
+		return fmt.Errorf("error when writing key file: %w", err)
+	}
+	fmt.Fprintf(o.ErrOut, "Private key written to %q\n", args[0])
+	return nil
+}
+
+// readPassphrase returns the passphrase in PassphraseFile, or an empty
+// string if it is not set
+func (o *Options) readPassphrase() (string, error) {
+	if o.PassphraseFile == "" {
+		return "", nil
+	}
+	data, err := ioutil.ReadFile(o.PassphraseFile)
+	if err != nil {
+		return "", fmt.Errorf("error when reading passphrase file: %w", err)
+	}
+	passphrase := strings.TrimSpace(string(data))
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase file %q is empty", o.PassphraseFile)
+	}
+	return passphrase, nil
+}
+
+// generateKey generates a key with the given algorithm and size, defaulting
+// the size like the controllers do for Certificates.
+func generateKey(algorithm string, size int) (crypto.Signer, error) {
+	switch algorithm {
+	case string(cmapi.RSAKeyAlgorithm):
+		if size == 0 {
+			size = pki.MinRSAKeySize
+		}
+		return pki.GenerateRSAPrivateKey(size)
+	case string(cmapi.ECDSAKeyAlgorithm):
+		if size == 0 {
+			size = pki.ECCurve256
+		}
+		return pki.GenerateECPrivateKey(size)
+	case ed25519KeyAlgorithm:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return nil, fmt.Errorf("unsupported key algorithm %q", algorithm)
+	}
+}
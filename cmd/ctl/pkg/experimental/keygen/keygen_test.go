@@ -0,0 +1,211 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keygen
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		options Options
+		args    []string
+		expErr  bool
+	}{
+		"RSA key encoded as PKCS#1": {
+			options: Options{Algorithm: "rsa", Encoding: "pkcs1"},
+		},
+		"ECDSA key encoded as SEC1": {
+			options: Options{Algorithm: "ECDSA", Encoding: "sec1"},
+		},
+		"encrypted Ed25519 key": {
+			options: Options{Algorithm: "ed25519", Encoding: "pkcs8", PassphraseFile: "passphrase.txt"},
+		},
+		"RSA key encoded as SEC1": {
+			options: Options{Algorithm: "rsa", Encoding: "sec1"},
+			expErr:  true,
+		},
+		"Ed25519 key encoded as PKCS#1": {
+			options: Options{Algorithm: "ed25519", Encoding: "pkcs1"},
+			expErr:  true,
+		},
+		"Ed25519 key with a size": {
+			options: Options{Algorithm: "ed25519", Encoding: "pkcs8", Size: 256},
+			expErr:  true,
+		},
+		"encrypted PKCS#1 key": {
+			options: Options{Algorithm: "rsa", Encoding: "pkcs1", PassphraseFile: "passphrase.txt"},
+			expErr:  true,
+		},
+		"unknown algorithm": {
+			options: Options{Algorithm: "dsa", Encoding: "pkcs8"},
+			expErr:  true,
+		},
+		"multiple files": {
+			options: Options{Algorithm: "rsa", Encoding: "pkcs1"},
+			args:    []string{"a.key", "b.key"},
+			expErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.options.Validate(test.args)
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error %t, got: %v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestEncodePrivateKey(t *testing.T) {
+	tests := map[string]struct {
+		algorithm  string
+		size       int
+		encoding   string
+		passphrase string
+		expPEMType string
+		expKeyType interface{}
+	}{
+		"RSA as PKCS#1": {
+			algorithm:  "rsa",
+			encoding:   encodingPKCS1,
+			expPEMType: "RSA PRIVATE KEY",
+			expKeyType: &rsa.PrivateKey{},
+		},
+		"ECDSA as PKCS#1 is SEC1": {
+			algorithm:  "ecdsa",
+			encoding:   encodingPKCS1,
+			expPEMType: "EC PRIVATE KEY",
+			expKeyType: &ecdsa.PrivateKey{},
+		},
+		"ECDSA P-384 as SEC1": {
+			algorithm:  "ecdsa",
+			size:       384,
+			encoding:   encodingSEC1,
+			expPEMType: "EC PRIVATE KEY",
+			expKeyType: &ecdsa.PrivateKey{},
+		},
+		"ECDSA as PKCS#8": {
+			algorithm:  "ecdsa",
+			encoding:   encodingPKCS8,
+			expPEMType: "PRIVATE KEY",
+			expKeyType: &ecdsa.PrivateKey{},
+		},
+		"Ed25519 as PKCS#8": {
+			algorithm:  ed25519KeyAlgorithm,
+			encoding:   encodingPKCS8,
+			expPEMType: "PRIVATE KEY",
+			expKeyType: ed25519.PrivateKey{},
+		},
+		"encrypted RSA as PKCS#8": {
+			algorithm:  "rsa",
+			encoding:   encodingPKCS8,
+			passphrase: "secret",
+			expPEMType: "ENCRYPTED PRIVATE KEY",
+			expKeyType: &rsa.PrivateKey{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			key, err := generateKey(test.algorithm, test.size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := encodePrivateKey(key, test.encoding, test.passphrase)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			block, _ := pem.Decode(data)
+			if block == nil || block.Type != test.expPEMType {
+				t.Fatalf("expected a PEM block of type %q, got: %s", test.expPEMType, data)
+			}
+			if test.passphrase != "" {
+				der := decryptPKCS8(t, block.Bytes, test.passphrase)
+				data = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+			}
+
+			// the controllers must be able to read the key
+			decoded, err := pki.DecodePrivateKeyBytes(data)
+			if err != nil {
+				t.Fatalf("expected the key to be decoded, got: %v", err)
+			}
+			if reflect.TypeOf(decoded) != reflect.TypeOf(test.expKeyType) {
+				t.Errorf("expected a key of type %T, got %T", test.expKeyType, decoded)
+			}
+			if test.algorithm == ed25519KeyAlgorithm {
+				return
+			}
+			if ok, err := pki.PublicKeysEqual(key.Public(), decoded.Public()); err != nil || !ok {
+				t.Errorf("expected the decoded key to match the generated key, got: %v", err)
+			}
+		})
+	}
+}
+
+// decryptPKCS8 decrypts a PKCS#8 private key encrypted by encryptPKCS8
+func decryptPKCS8(t *testing.T, der []byte, passphrase string) []byte {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		t.Fatal(err)
+	}
+	if !info.EncryptionAlgorithm.Algorithm.Equal(oidPBES2) {
+		t.Fatalf("expected PBES2, got %v", info.EncryptionAlgorithm.Algorithm)
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.EncryptionAlgorithm.Parameters.FullBytes, &params); err != nil {
+		t.Fatal(err)
+	}
+	var kdfParams pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		t.Fatal(err)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		t.Fatal(err)
+	}
+
+	key := pbkdf2.Key([]byte(passphrase), kdfParams.Salt, kdfParams.IterationCount, 32, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, info.EncryptedData)
+	decrypted = decrypted[:len(decrypted)-int(decrypted[len(decrypted)-1])]
+
+	if _, err := x509.ParsePKCS8PrivateKey(decrypted); err != nil {
+		t.Fatalf("expected the decrypted key to be a PKCS#8 private key, got: %v", err)
+	}
+	return decrypted
+}
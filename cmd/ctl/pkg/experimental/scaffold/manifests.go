@@ -0,0 +1,209 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+const (
+	serverImage = "nginx:1.19"
+	clientImage = "curlimages/curl:7.72.0"
+
+	// serverPort is the port the nginx server listens on for TLS connections
+	serverPort = 8443
+	// tlsMountPath is the path the Secrets of Certificates are mounted at in
+	// the sample workloads
+	tlsMountPath = "/etc/tls"
+)
+
+// mtlsParams are the parameters of the manifests generated by scaffold mtls
+type mtlsParams struct {
+	Namespace        string
+	Name             string
+	ServerDNSNames   []string
+	ClientCommonName string
+}
+
+func selfSignedIssuerName(name string) string { return name + "-selfsigned" }
+func caName(name string) string               { return name + "-ca" }
+func serverName(name string) string           { return name + "-server" }
+func clientName(name string) string           { return name + "-client" }
+
+// mtlsManifests returns the resources making up the mutual TLS example, in
+// the order they should be applied.
+func mtlsManifests(p mtlsParams) []runtime.Object {
+	objects := []runtime.Object{
+		&corev1.Namespace{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: p.Namespace},
+		},
+
+		// Bootstrap the CA with a self-signed certificate, and use it to
+		// issue the server and client certificates
+		newIssuer(p, selfSignedIssuerName(p.Name), cmapi.IssuerConfig{SelfSigned: &cmapi.SelfSignedIssuer{}}),
+		newCertificate(p, caName(p.Name), selfSignedIssuerName(p.Name), cmapi.CertificateSpec{
+			CommonName: caName(p.Name),
+			IsCA:       true,
+			Usages:     []cmapi.KeyUsage{cmapi.UsageCertSign, cmapi.UsageCRLSign},
+		}),
+		newIssuer(p, caName(p.Name), cmapi.IssuerConfig{CA: &cmapi.CAIssuer{SecretName: caName(p.Name)}}),
+		newCertificate(p, serverName(p.Name), caName(p.Name), cmapi.CertificateSpec{
+			DNSNames: p.ServerDNSNames,
+			Usages:   []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageKeyEncipherment, cmapi.UsageServerAuth},
+		}),
+		newCertificate(p, clientName(p.Name), caName(p.Name), cmapi.CertificateSpec{
+			CommonName: p.ClientCommonName,
+			Usages:     []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageKeyEncipherment, cmapi.UsageClientAuth},
+		}),
+	}
+
+	return append(objects, workloadManifests(p)...)
+}
+
+// workloadManifests returns the nginx server and curl client Deployments, along
+// with the configuration of the server and the Service exposing it.
+func workloadManifests(p mtlsParams) []runtime.Object {
+	server := serverName(p.Name)
+	client := clientName(p.Name)
+
+	// nginx only accepts clients presenting a certificate signed by the CA
+	// in the ca.crt of its own Secret, i.e. the CA that issued both
+	// certificates
+	nginxConf := fmt.Sprintf(`server {
+    listen %d ssl;
+    ssl_certificate %s/tls.crt;
+    ssl_certificate_key %s/tls.key;
+    ssl_client_certificate %s/ca.crt;
+    ssl_verify_client on;
+
+    location / {
+        return 200 "Hello, $ssl_client_s_dn\n";
+    }
+}
+`, serverPort, tlsMountPath, tlsMountPath, tlsMountPath)
+
+	clientScript := fmt.Sprintf(`while true; do
+  curl -sS --cacert %[1]s/ca.crt --cert %[1]s/tls.crt --key %[1]s/tls.key https://%[2]s/
+  sleep 10
+done
+`, tlsMountPath, p.ServerDNSNames[0])
+
+	return []runtime.Object{
+		&corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: objectMeta(p, server),
+			Data:       map[string]string{"default.conf": nginxConf},
+		},
+		&corev1.Service{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+			ObjectMeta: objectMeta(p, server),
+			Spec: corev1.ServiceSpec{
+				Selector: labels(server),
+				Ports: []corev1.ServicePort{{
+					Name:       "https",
+					Port:       443,
+					TargetPort: intstr.FromInt(serverPort),
+				}},
+			},
+		},
+		newDeployment(p, server, corev1.Container{
+			Name:  "nginx",
+			Image: serverImage,
+			Ports: []corev1.ContainerPort{{Name: "https", ContainerPort: serverPort}},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "tls", MountPath: tlsMountPath, ReadOnly: true},
+				{Name: "config", MountPath: "/etc/nginx/conf.d", ReadOnly: true},
+			},
+		}, corev1.Volume{
+			Name: "config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: server}},
+			},
+		}),
+		newDeployment(p, client, corev1.Container{
+			Name:         "curl",
+			Image:        clientImage,
+			Command:      []string{"/bin/sh", "-c", clientScript},
+			VolumeMounts: []corev1.VolumeMount{{Name: "tls", MountPath: tlsMountPath, ReadOnly: true}},
+		}),
+	}
+}
+
+func objectMeta(p mtlsParams, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Namespace: p.Namespace, Name: name, Labels: labels(name)}
+}
+
+func labels(name string) map[string]string {
+	return map[string]string{"app": name}
+}
+
+func newIssuer(p mtlsParams, name string, config cmapi.IssuerConfig) *cmapi.Issuer {
+	issuer := &cmapi.Issuer{
+		ObjectMeta: objectMeta(p, name),
+		Spec:       cmapi.IssuerSpec{IssuerConfig: config},
+	}
+	issuer.SetGroupVersionKind(cmapi.SchemeGroupVersion.WithKind(cmapi.IssuerKind))
+	return issuer
+}
+
+// newCertificate returns a Certificate issued by the Issuer issuerName,
+// stored in a Secret with the same name as the Certificate.
+func newCertificate(p mtlsParams, name, issuerName string, spec cmapi.CertificateSpec) *cmapi.Certificate {
+	spec.SecretName = name
+	spec.IssuerRef = cmmeta.ObjectReference{Name: issuerName, Kind: cmapi.IssuerKind}
+	crt := &cmapi.Certificate{
+		ObjectMeta: objectMeta(p, name),
+		Spec:       spec,
+	}
+	crt.SetGroupVersionKind(cmapi.SchemeGroupVersion.WithKind(cmapi.CertificateKind))
+	return crt
+}
+
+// newDeployment returns a Deployment running container, with the Secret of
+// the Certificate of the same name mounted as the 'tls' volume.
+func newDeployment(p mtlsParams, name string, container corev1.Container, volumes ...corev1.Volume) *appsv1.Deployment {
+	replicas := int32(1)
+	volumes = append([]corev1.Volume{{
+		Name:         "tls",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: name}},
+	}}, volumes...)
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: objectMeta(p, name),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels(name)},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels(name)},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{container},
+					Volumes:    volumes,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	mtlsLong = templates.LongDesc(i18n.T(`
+Generate a complete set of manifests for two workloads talking to each other over mutual TLS.
+
+The manifests contain a self-signed CA bootstrapped with a SelfSigned Issuer, a CA Issuer using it, a server
+Certificate and a client Certificate issued by the CA Issuer, and two sample Deployments mounting them: an
+nginx server that only accepts clients presenting a certificate signed by the CA, and a curl client calling
+the server every ten seconds. All resources are created in the namespace given with --namespace, which is
+included in the manifests.
+
+The client connects to the first of the --server-dns-names, which therefore has to resolve to the Service of
+the server. By default the server Certificate contains the in-cluster names of that Service.
+
+The manifests are printed, so that they can be reviewed or modified before being applied.`))
+
+	mtlsExample = templates.Examples(i18n.T(`
+# Generate the manifests in namespace 'mtls-demo' and apply them
+kubectl cert-manager x scaffold mtls --namespace mtls-demo | kubectl apply -f -
+
+# Generate the manifests with resources named 'payments-*' and an additional server DNS name
+kubectl cert-manager x scaffold mtls --name payments --server-dns-names payments-server.sandbox.svc,payments.example.com --namespace sandbox
+`))
+)
+
+// MTLSOptions is a struct to support scaffold mtls command
+type MTLSOptions struct {
+	// The Namespace that the resources are generated in.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace string
+
+	// Name is the prefix of the names of the generated resources
+	Name string
+	// ServerDNSNames are the DNS names of the server certificate. They
+	// default to the in-cluster names of the Service of the server.
+	ServerDNSNames []string
+	// ClientCommonName is the common name of the client certificate. It
+	// defaults to the name of the client.
+	ClientCommonName string
+
+	PrintFlags *genericclioptions.PrintFlags
+	Printer    printers.ResourcePrinter
+
+	genericclioptions.IOStreams
+}
+
+// NewMTLSOptions returns initialized MTLSOptions
+func NewMTLSOptions(ioStreams genericclioptions.IOStreams) *MTLSOptions {
+	return &MTLSOptions{
+		Name:       "mtls",
+		IOStreams:  ioStreams,
+		PrintFlags: genericclioptions.NewPrintFlags("generated").WithDefaultOutput("yaml"),
+	}
+}
+
+// NewCmdScaffoldMTLS returns a cobra command for scaffold mtls
+func NewCmdScaffoldMTLS(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewMTLSOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "mtls",
+		Short:   "Generate manifests for a CA, server and client certificates and sample workloads using mutual TLS",
+		Long:    mtlsLong,
+		Example: mtlsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().StringVar(&o.Name, "name", o.Name, "Prefix of the names of the generated resources")
+	cmd.Flags().StringSliceVar(&o.ServerDNSNames, "server-dns-names", o.ServerDNSNames,
+		"DNS names of the server certificate. The first one is used by the client to connect to the server (default <name>-server.<namespace>.svc,<name>-server.<namespace>.svc.cluster.local)")
+	cmd.Flags().StringVar(&o.ClientCommonName, "client-common-name", o.ClientCommonName, "Common name of the client certificate (default <name>-client)")
+	o.PrintFlags.AddFlags(cmd)
+	return cmd
+}
+
+// Complete takes the factory and infers any remaining options.
+func (o *MTLSOptions) Complete(f cmdutil.Factory) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	if len(o.ServerDNSNames) == 0 {
+		service := fmt.Sprintf("%s.%s.svc", serverName(o.Name), o.Namespace)
+		o.ServerDNSNames = []string{service, service + ".cluster.local"}
+	}
+	if o.ClientCommonName == "" {
+		o.ClientCommonName = clientName(o.Name)
+	}
+
+	o.Printer, err = o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates the provided options
+func (o *MTLSOptions) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("no arguments are accepted")
+	}
+	if errs := validation.IsDNS1123Label(o.Namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid namespace %q: %s", o.Namespace, strings.Join(errs, ", "))
+	}
+	// The longest name generated from the prefix is that of the Service of
+	// the server, which has to be a DNS label
+	if errs := validation.IsDNS1123Label(serverName(o.Name)); len(errs) > 0 {
+		return fmt.Errorf("invalid --name %q: %s", o.Name, strings.Join(errs, ", "))
+	}
+	for _, dnsName := range o.ServerDNSNames {
+		if errs := validation.IsDNS1123Subdomain(dnsName); len(errs) > 0 {
+			return fmt.Errorf("invalid server DNS name %q: %s", dnsName, strings.Join(errs, ", "))
+		}
+	}
+	if o.ClientCommonName == "" {
+		return errors.New("--client-common-name must not be empty")
+	}
+	return nil
+}
+
+// Run executes scaffold mtls command
+func (o *MTLSOptions) Run() error {
+	objects := mtlsManifests(mtlsParams{
+		Namespace:        o.Namespace,
+		Name:             o.Name,
+		ServerDNSNames:   o.ServerDNSNames,
+		ClientCommonName: o.ClientCommonName,
+	})
+	for _, obj := range objects {
+		if err := o.Printer.PrintObj(obj, o.Out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
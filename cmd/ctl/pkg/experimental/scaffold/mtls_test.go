@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+)
+
+func TestMTLSManifests(t *testing.T) {
+	p := mtlsParams{
+		Namespace:        "sandbox",
+		Name:             "payments",
+		ServerDNSNames:   []string{"payments-server.sandbox.svc", "payments.example.com"},
+		ClientCommonName: "payments-client",
+	}
+	objects := mtlsManifests(p)
+
+	certs := map[string]*cmapi.Certificate{}
+	issuers := map[string]*cmapi.Issuer{}
+	deployments := map[string]*appsv1.Deployment{}
+	for _, obj := range objects {
+		if obj.GetObjectKind().GroupVersionKind().Kind == "" {
+			t.Errorf("expected kind to be set on %T", obj)
+		}
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := obj.(*corev1.Namespace); !ok && accessor.GetNamespace() != p.Namespace {
+			t.Errorf("expected %T %q in namespace %q, got %q", obj, accessor.GetName(), p.Namespace, accessor.GetNamespace())
+		}
+		switch o := obj.(type) {
+		case *cmapi.Certificate:
+			certs[o.Name] = o
+		case *cmapi.Issuer:
+			issuers[o.Name] = o
+		case *appsv1.Deployment:
+			deployments[o.Name] = o
+		}
+	}
+
+	ca := certs["payments-ca"]
+	if ca == nil || !ca.Spec.IsCA || ca.Spec.IssuerRef.Name != "payments-selfsigned" {
+		t.Fatalf("expected CA Certificate issued by the SelfSigned Issuer, got: %+v", ca)
+	}
+	if issuers["payments-selfsigned"] == nil || issuers["payments-selfsigned"].Spec.SelfSigned == nil {
+		t.Errorf("expected SelfSigned Issuer 'payments-selfsigned'")
+	}
+	caIssuer := issuers["payments-ca"]
+	if caIssuer == nil || caIssuer.Spec.CA == nil || caIssuer.Spec.CA.SecretName != ca.Spec.SecretName {
+		t.Errorf("expected CA Issuer using the Secret of the CA Certificate, got: %+v", caIssuer)
+	}
+
+	server, client := certs["payments-server"], certs["payments-client"]
+	if server == nil || client == nil {
+		t.Fatalf("expected server and client Certificates, got: %v", certs)
+	}
+	if !equal(server.Spec.DNSNames, p.ServerDNSNames) || !hasUsage(server, cmapi.UsageServerAuth) {
+		t.Errorf("expected server Certificate for %v with server auth usage, got: %+v", p.ServerDNSNames, server.Spec)
+	}
+	if client.Spec.CommonName != p.ClientCommonName || !hasUsage(client, cmapi.UsageClientAuth) {
+		t.Errorf("expected client Certificate for %q with client auth usage, got: %+v", p.ClientCommonName, client.Spec)
+	}
+	for _, crt := range []*cmapi.Certificate{server, client} {
+		if crt.Spec.IssuerRef.Name != "payments-ca" {
+			t.Errorf("expected Certificate %q to be issued by the CA Issuer, got: %v", crt.Name, crt.Spec.IssuerRef)
+		}
+		deployment := deployments[crt.Name]
+		if deployment == nil {
+			t.Fatalf("expected Deployment %q", crt.Name)
+		}
+		if secret := deployment.Spec.Template.Spec.Volumes[0].Secret; secret == nil || secret.SecretName != crt.Spec.SecretName {
+			t.Errorf("expected Deployment %q to mount Secret %q, got: %+v", crt.Name, crt.Spec.SecretName, deployment.Spec.Template.Spec.Volumes)
+		}
+	}
+
+	command := strings.Join(deployments["payments-client"].Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.Contains(command, "https://payments-server.sandbox.svc/") {
+		t.Errorf("expected client to connect to the first server DNS name, got: %s", command)
+	}
+}
+
+func hasUsage(crt *cmapi.Certificate, usage cmapi.KeyUsage) bool {
+	for _, u := range crt.Spec.Usages {
+		if u == usage {
+			return true
+		}
+	}
+	return false
+}
+
+func equal(a, b []string) bool {
+	return strings.Join(a, ",") == strings.Join(b, ",")
+}
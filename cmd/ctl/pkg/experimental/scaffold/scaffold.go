@@ -0,0 +1,36 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// NewCmdScaffold returns a cobra command for scaffold
+func NewCmdScaffold(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	cmds := &cobra.Command{
+		Use:   "scaffold",
+		Short: "Generate example manifests using cert-manager",
+		Long:  `Generate complete, working sets of example manifests using cert-manager, to evaluate features or reproduce bug reports`,
+	}
+
+	cmds.AddCommand(NewCmdScaffoldMTLS(ioStreams, factory))
+
+	return cmds
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Schema is the subset of an OpenAPI v3 or JSON Schema draft-07 schema
+// object that is generated from Go types.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+
+	// PreserveUnknownFields is set on schemas of arbitrary JSON values
+	PreserveUnknownFields bool `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+	// GroupVersionKind is set on the schemas of API resources
+	GroupVersionKind []GroupVersionKind `json:"x-kubernetes-group-version-kind,omitempty"`
+}
+
+// GroupVersionKind identifies the API resource a schema describes, as in
+// the OpenAPI definitions served by the Kubernetes apiserver.
+type GroupVersionKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	metaTimeType  = reflect.TypeOf(metav1.Time{})
+	microTimeType = reflect.TypeOf(metav1.MicroTime{})
+	durationType  = reflect.TypeOf(metav1.Duration{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// generator generates the schemas of Go types from the way encoding/json
+// marshals them. Named struct types are generated once as definitions and
+// referenced by the schemas of other types.
+type generator struct {
+	// refPrefix is the prefix of references to definitions
+	refPrefix string
+	// openAPI is true if OpenAPI v3 schemas are generated, and false if
+	// JSON Schema draft-07 schemas are generated. They only differ in how
+	// null values are allowed.
+	openAPI bool
+
+	definitions map[string]*Schema
+}
+
+func newGenerator(openAPI bool) *generator {
+	g := &generator{
+		openAPI:     openAPI,
+		definitions: make(map[string]*Schema),
+	}
+	if openAPI {
+		g.refPrefix = "#/components/schemas/"
+	} else {
+		g.refPrefix = "#/definitions/"
+	}
+	return g
+}
+
+// schemaFor returns the schema of the JSON encoding of values of type t.
+func (g *generator) schemaFor(t reflect.Type) *Schema {
+	switch t {
+	case timeType, metaTimeType, microTimeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "string"}
+	}
+	if t.Kind() != reflect.Ptr && (t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType)) {
+		// the encoding of types with a custom marshaler is unknown
+		return &Schema{PreserveUnknownFields: true}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Ptr:
+		return g.schemaFor(t.Elem())
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// byte slices are encoded as base64 strings
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := definitionName(t)
+		if _, ok := g.definitions[name]; !ok {
+			// register the definition before generating it, as the type
+			// may refer to itself
+			g.definitions[name] = nil
+			g.definitions[name] = g.structSchema(t)
+		}
+		return &Schema{Ref: g.refPrefix + name}
+	default:
+		// interfaces, and kinds that encoding/json cannot marshal
+		return &Schema{PreserveUnknownFields: true}
+	}
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(s, t)
+	return s
+}
+
+// addFields adds the fields of the struct type t to s, including the
+// fields of embedded structs that encoding/json inlines.
+func (g *generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			g.addFields(s, ft)
+			continue
+		}
+		if f.PkgPath != "" {
+			// unexported field
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fieldSchema := g.schemaFor(f.Type)
+		omitEmpty := hasOption(opts, "omitempty")
+		if !omitEmpty {
+			s.Required = append(s.Required, name)
+			switch f.Type.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+				// nil values are encoded as null
+				fieldSchema = g.nullable(fieldSchema)
+			}
+		}
+		s.Properties[name] = fieldSchema
+	}
+}
+
+// nullable returns a schema that allows the values of s and null.
+func (g *generator) nullable(s *Schema) *Schema {
+	if s.PreserveUnknownFields {
+		return s
+	}
+	if !g.openAPI {
+		return &Schema{AnyOf: []*Schema{s, {Type: "null"}}}
+	}
+	if s.Ref != "" {
+		// siblings of references are ignored in OpenAPI v3
+		return &Schema{AllOf: []*Schema{s}, Nullable: true}
+	}
+	s.Nullable = true
+	return s
+}
+
+func hasOption(opts, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// definitionName returns the name of the definition of the named type t,
+// following the naming of the OpenAPI definitions of Kubernetes, e.g.
+// io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta.
+func definitionName(t reflect.Type) string {
+	pkg := strings.Split(t.PkgPath(), "/")
+	domain := strings.Split(pkg[0], ".")
+	for i, j := 0, len(domain)-1; i < j; i, j = i+1, j-1 {
+		domain[i], domain[j] = domain[j], domain[i]
+	}
+	parts := append(domain, pkg[1:]...)
+	// unexported types are exported by the generated clients
+	name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+	return strings.Join(append(parts, name), ".")
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/benchmark/issuance"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/report/expiry"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/report/inventory"
+	cmacmev1alpha2 "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmacmev1alpha3 "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha3"
+	cmacmev1beta1 "github.com/jetstack/cert-manager/pkg/apis/acme/v1beta1"
+	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmapiv1alpha3 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha3"
+	cmapiv1beta1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1beta1"
+	"github.com/jetstack/cert-manager/pkg/util"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Print the schemas of the cert-manager custom resources at all of their served API versions, and of the
+structured output of the kubectl cert-manager commands, so that typed clients can be generated for them in
+other languages.
+
+The schemas are printed as the components of an OpenAPI v3 document with --format=openapi, the default,
+or as the definitions of a JSON Schema draft-07 document with --format=jsonschema.
+
+The schemas of custom resources are named like the OpenAPI definitions served by the Kubernetes apiserver,
+e.g. com.github.jetstack.cert-manager.pkg.apis.certmanager.v1beta1.Certificate. The schemas of command
+output are named after the command, e.g. io.cert-manager.cmctl.ReportExpiry is the output of
+'kubectl cert-manager report expiry --output json'.`))
+
+	example = templates.Examples(i18n.T(`
+# Print the OpenAPI v3 schemas as JSON
+kubectl cert-manager x schema
+
+# Print the JSON Schema definitions as YAML
+kubectl cert-manager x schema --format jsonschema --output yaml
+`))
+)
+
+const (
+	formatOpenAPI    = "openapi"
+	formatJSONSchema = "jsonschema"
+
+	// commandDefinitionPrefix is the prefix of the names of the schemas of
+	// command output
+	commandDefinitionPrefix = "io.cert-manager.cmctl."
+)
+
+// resources are the custom resources of cert-manager by served API
+// version.
+var resources = map[schema.GroupVersion][]interface{}{
+	cmapiv1alpha2.SchemeGroupVersion: {
+		cmapiv1alpha2.Certificate{}, cmapiv1alpha2.CertificateRequest{}, cmapiv1alpha2.Issuer{}, cmapiv1alpha2.ClusterIssuer{},
+	},
+	cmapiv1alpha3.SchemeGroupVersion: {
+		cmapiv1alpha3.Certificate{}, cmapiv1alpha3.CertificateRequest{}, cmapiv1alpha3.Issuer{}, cmapiv1alpha3.ClusterIssuer{},
+	},
+	cmapiv1beta1.SchemeGroupVersion: {
+		cmapiv1beta1.Certificate{}, cmapiv1beta1.CertificateRequest{}, cmapiv1beta1.Issuer{}, cmapiv1beta1.ClusterIssuer{},
+	},
+	cmacmev1alpha2.SchemeGroupVersion: {cmacmev1alpha2.Order{}, cmacmev1alpha2.Challenge{}},
+	cmacmev1alpha3.SchemeGroupVersion: {cmacmev1alpha3.Order{}, cmacmev1alpha3.Challenge{}},
+	cmacmev1beta1.SchemeGroupVersion:  {cmacmev1beta1.Order{}, cmacmev1beta1.Challenge{}},
+}
+
+// commandOutputs are the values printed by the commands with structured
+// output, by the name of the schema of their output.
+var commandOutputs = map[string]interface{}{
+	"BenchmarkIssuance": issuance.JSONOutput(),
+	"ReportExpiry":      expiry.JSONOutput(),
+	"ReportInventory":   inventory.JSONOutput(),
+	"Version":           util.VersionInfo(),
+}
+
+// Options is a struct to support schema command
+type Options struct {
+	// Format is the format of the schemas, one of openapi or jsonschema
+	Format string
+	// Output is the encoding of the document, one of json or yaml
+	Output string
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		Format:    formatOpenAPI,
+		Output:    "json",
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdSchema returns a cobra command for schema
+func NewCmdSchema(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "schema",
+		Short:   "Print the schemas of the custom resources and of the structured output of commands",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().StringVar(&o.Format, "format", o.Format, "The format of the schemas, one of openapi or jsonschema")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "The encoding of the document, one of json or yaml")
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("schema does not accept arguments")
+	}
+	o.Format = strings.ToLower(o.Format)
+	if o.Format != formatOpenAPI && o.Format != formatJSONSchema {
+		return fmt.Errorf("unsupported format %q, must be one of %s or %s", o.Format, formatOpenAPI, formatJSONSchema)
+	}
+	if o.Output != "json" && o.Output != "yaml" {
+		return fmt.Errorf("unsupported output %q, must be one of json or yaml", o.Output)
+	}
+	return nil
+}
+
+// Run executes schema command
+func (o *Options) Run() error {
+	doc := document(o.Format == formatOpenAPI)
+
+	var b []byte
+	var err error
+	if o.Output == "yaml" {
+		b, err = yaml.Marshal(doc)
+	} else {
+		b, err = json.MarshalIndent(doc, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "%s\n", strings.TrimSuffix(string(b), "\n"))
+	return nil
+}
+
+// definitions returns the schemas of the custom resources and of the
+// command outputs, and of all the types they refer to, by name.
+func definitions(openAPI bool) map[string]*Schema {
+	g := newGenerator(openAPI)
+	for gv, objs := range resources {
+		for _, obj := range objs {
+			t := reflect.TypeOf(obj)
+			g.schemaFor(t)
+			g.definitions[definitionName(t)].GroupVersionKind = []GroupVersionKind{
+				{Group: gv.Group, Version: gv.Version, Kind: t.Name()},
+			}
+		}
+	}
+	for name, output := range commandOutputs {
+		g.definitions[commandDefinitionPrefix+name] = g.schemaFor(reflect.TypeOf(output))
+	}
+	return g.definitions
+}
+
+// document returns the OpenAPI v3 or JSON Schema document holding the
+// definitions.
+func document(openAPI bool) interface{} {
+	defs := definitions(openAPI)
+	if !openAPI {
+		return map[string]interface{}{
+			"$schema":     "http://json-schema.org/draft-07/schema#",
+			"definitions": defs,
+		}
+	}
+	return map[string]interface{}{
+		"openapi": "3.0.0",
+		"info": map[string]interface{}{
+			"title":   "cert-manager",
+			"version": util.AppVersion,
+		},
+		"paths": map[string]interface{}{},
+		"components": map[string]interface{}{
+			"schemas": defs,
+		},
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+type testEmbedded struct {
+	Embedded string `json:"embedded"`
+}
+
+type testNode struct {
+	testEmbedded `json:",inline"`
+
+	Name     string            `json:"name"`
+	Count    *int32            `json:"count"`
+	Created  metav1.Time       `json:"created"`
+	Timeout  *metav1.Duration  `json:"timeout,omitempty"`
+	Data     []byte            `json:"data,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Children []*testNode       `json:"children,omitempty"`
+	Ignored  string            `json:"-"`
+	Raw      json.RawMessage   `json:"raw,omitempty"`
+	private  string
+}
+
+func TestSchemaFor(t *testing.T) {
+	nodeName := "com.github.jetstack.cert-manager.cmd.ctl.pkg.experimental.schema.TestNode"
+	expected := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"embedded": {Type: "string"},
+			"name":     {Type: "string"},
+			"count":    {Type: "integer", Format: "int32", Nullable: true},
+			"created":  {Type: "string", Format: "date-time"},
+			"timeout":  {Type: "string"},
+			"data":     {Type: "string", Format: "byte"},
+			"labels":   {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+			"children": {Type: "array", Items: &Schema{Ref: "#/components/schemas/" + nodeName}},
+			"raw":      {PreserveUnknownFields: true},
+		},
+		Required: []string{"embedded", "name", "count", "created"},
+	}
+
+	g := newGenerator(true)
+	ref := g.schemaFor(reflect.TypeOf(testNode{}))
+	if ref.Ref != "#/components/schemas/"+nodeName {
+		t.Errorf("unexpected reference %q", ref.Ref)
+	}
+	if len(g.definitions) != 1 {
+		t.Errorf("expected a single definition, got %d", len(g.definitions))
+	}
+	if !reflect.DeepEqual(g.definitions[nodeName], expected) {
+		t.Errorf("unexpected schema, exp=%s got=%s", mustJSON(t, expected), mustJSON(t, g.definitions[nodeName]))
+	}
+}
+
+func TestNullable(t *testing.T) {
+	type test struct {
+		Time  *time.Time `json:"time"`
+		Items []string   `json:"items"`
+	}
+
+	g := newGenerator(true)
+	s := g.structSchema(reflect.TypeOf(test{}))
+	if exp := (&Schema{Type: "string", Format: "date-time", Nullable: true}); !reflect.DeepEqual(s.Properties["time"], exp) {
+		t.Errorf("unexpected OpenAPI schema, exp=%s got=%s", mustJSON(t, exp), mustJSON(t, s.Properties["time"]))
+	}
+
+	g = newGenerator(false)
+	s = g.structSchema(reflect.TypeOf(test{}))
+	exp := &Schema{AnyOf: []*Schema{{Type: "array", Items: &Schema{Type: "string"}}, {Type: "null"}}}
+	if !reflect.DeepEqual(s.Properties["items"], exp) {
+		t.Errorf("unexpected JSON Schema, exp=%s got=%s", mustJSON(t, exp), mustJSON(t, s.Properties["items"]))
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]struct {
+		format    string
+		refPrefix string
+		schemas   func(doc map[string]interface{}) map[string]interface{}
+	}{
+		"openapi": {
+			format:    formatOpenAPI,
+			refPrefix: "#/components/schemas/",
+			schemas: func(doc map[string]interface{}) map[string]interface{} {
+				return doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+			},
+		},
+		"jsonschema": {
+			format:    formatJSONSchema,
+			refPrefix: "#/definitions/",
+			schemas: func(doc map[string]interface{}) map[string]interface{} {
+				return doc["definitions"].(map[string]interface{})
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			o := NewOptions(genericclioptions.IOStreams{Out: out})
+			o.Format = test.format
+			if err := o.Validate(nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := o.Run(); err != nil {
+				t.Fatal(err)
+			}
+
+			var doc map[string]interface{}
+			if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+				t.Fatalf("invalid JSON output: %v", err)
+			}
+			schemas := test.schemas(doc)
+
+			for _, version := range []string{"v1alpha2", "v1alpha3", "v1beta1"} {
+				name := "com.github.jetstack.cert-manager.pkg.apis.certmanager." + version + ".Certificate"
+				crt, ok := schemas[name].(map[string]interface{})
+				if !ok {
+					t.Errorf("missing schema %s", name)
+					continue
+				}
+				gvk := crt["x-kubernetes-group-version-kind"].([]interface{})[0].(map[string]interface{})
+				if gvk["group"] != "cert-manager.io" || gvk["version"] != version || gvk["kind"] != "Certificate" {
+					t.Errorf("unexpected group version kind of %s: %v", name, gvk)
+				}
+			}
+
+			expiry, ok := schemas[commandDefinitionPrefix+"ReportExpiry"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("missing schema of report expiry output")
+			}
+			items := expiry["items"].(map[string]interface{})
+			ref := test.refPrefix + "com.github.jetstack.cert-manager.cmd.ctl.pkg.report.expiry.Entry"
+			if items["$ref"] != ref {
+				t.Errorf("unexpected items of report expiry output, exp=%s got=%v", ref, items["$ref"])
+			}
+
+			// all references must resolve
+			var check func(v interface{})
+			check = func(v interface{}) {
+				switch v := v.(type) {
+				case map[string]interface{}:
+					if ref, ok := v["$ref"].(string); ok {
+						if _, ok := schemas[ref[len(test.refPrefix):]]; !ok {
+							t.Errorf("unresolved reference %s", ref)
+						}
+					}
+					for _, e := range v {
+						check(e)
+					}
+				case []interface{}:
+					for _, e := range v {
+						check(e)
+					}
+				}
+			}
+			check(schemas)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, o := range []*Options{
+		{Format: "swagger", Output: "json"},
+		{Format: formatOpenAPI, Output: "table"},
+	} {
+		if err := o.Validate(nil); err == nil {
+			t.Errorf("expected error for format %q and output %q", o.Format, o.Output)
+		}
+	}
+	if err := NewOptions(genericclioptions.IOStreams{}).Validate([]string{"certificate"}); err == nil {
+		t.Errorf("expected error for arguments")
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotations
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/ctl/output"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+List the annotations that are read by cert-manager, optionally limited to those that apply to a given resource kind.`))
+
+	example = templates.Examples(i18n.T(`
+# List all annotations supported by cert-manager
+kubectl cert-manager explain annotations
+
+# List the annotations supported on Ingress resources
+kubectl cert-manager explain annotations ingress
+`))
+)
+
+// Options is a struct to support explain annotations command
+type Options struct {
+	// Kind to filter the listed annotations by. If empty, all annotations
+	// are listed.
+	Kind string
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdExplainAnnotations returns a cobra command for explain annotations
+func NewCmdExplainAnnotations(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "annotations [kind]",
+		Short:   "List the annotations supported by cert-manager",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) > 1 {
+		return errors.New("only one argument can be passed in: the kind of resource")
+	}
+	if len(args) == 1 {
+		o.Kind = args[0]
+	}
+	return nil
+}
+
+// Run executes explain annotations command
+func (o *Options) Run() error {
+	specs := apiutil.KnownAnnotations()
+	if o.Kind != "" {
+		specs = apiutil.AnnotationsForKind(o.Kind)
+	}
+	if len(specs) == 0 {
+		return fmt.Errorf("no annotations are supported on resources of kind %q", o.Kind)
+	}
+
+	w := output.NewTabWriter(o.Out)
+	fmt.Fprintf(w, "KEY\tKINDS\tDESCRIPTION\n")
+	for _, spec := range specs {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", spec.Key, strings.Join(spec.Kinds, ","), spec.Description)
+	}
+	return w.Flush()
+}
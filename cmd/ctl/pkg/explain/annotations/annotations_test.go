@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotations
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		expKind string
+		expErr  bool
+	}{
+		"If there are no arguments, list all annotations": {},
+		"If there is one argument, use it as the kind": {
+			args:    []string{"ingress"},
+			expKind: "ingress",
+		},
+		"If there are more than one argument, error": {
+			args:   []string{"ingress", "certificate"},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewOptions(genericclioptions.IOStreams{})
+			err := o.Validate(test.args)
+			if test.expErr != (err != nil) {
+				t.Fatalf("expected error=%t got=%v", test.expErr, err)
+			}
+			if o.Kind != test.expKind {
+				t.Errorf("unexpected kind, exp=%q got=%q", test.expKind, o.Kind)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]struct {
+		kind string
+
+		expErr bool
+		// expKeys are annotation keys expected in the output, and
+		// expMissingKeys are annotation keys expected not to be
+		expKeys        []string
+		expMissingKeys []string
+	}{
+		"all annotations are listed without a kind": {
+			expKeys: []string{cmapi.IngressIssuerNameAnnotationKey, cmapi.PausedAnnotationKey},
+		},
+		"only the annotations of the kind are listed, ignoring case": {
+			kind:           "ingress",
+			expKeys:        []string{cmapi.IngressIssuerNameAnnotationKey, cmapi.CommonNameAnnotationKey},
+			expMissingKeys: []string{cmapi.PausedAnnotationKey},
+		},
+		"an unknown kind is an error": {
+			kind:   "Pod",
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			o := NewOptions(genericclioptions.IOStreams{Out: out, ErrOut: new(bytes.Buffer)})
+			o.Kind = test.kind
+
+			err := o.Run()
+			if test.expErr != (err != nil) {
+				t.Fatalf("expected error=%t got=%v", test.expErr, err)
+			}
+			if test.expErr {
+				return
+			}
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if !strings.HasPrefix(lines[0], "KEY") {
+				t.Errorf("expected a header line, got %q", lines[0])
+			}
+			keys := make(map[string]bool)
+			for _, line := range lines[1:] {
+				keys[strings.Fields(line)[0]] = true
+			}
+			for _, key := range test.expKeys {
+				if !keys[key] {
+					t.Errorf("expected annotation %q to be listed, got:\n%s", key, out.String())
+				}
+			}
+			for _, key := range test.expMissingKeys {
+				if keys[key] {
+					t.Errorf("expected annotation %q not to be listed, got:\n%s", key, out.String())
+				}
+			}
+		})
+	}
+}
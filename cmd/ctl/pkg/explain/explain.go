@@ -0,0 +1,36 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explain
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/explain/annotations"
+)
+
+func NewCmdExplain(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	cmds := &cobra.Command{
+		Use:   "explain",
+		Short: "Get documentation about the cert-manager API surface",
+		Long:  `Get documentation about the cert-manager API surface, e.g. supported annotations`,
+	}
+
+	cmds.AddCommand(annotations.NewCmdExplainAnnotations(ioStreams))
+
+	return cmds
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exportcsr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/completion"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/util"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Export the CSR of the pending issuance of a Certificate that is signed outside of cert-manager.
+
+The Certificate must have spec.externalSigning set and an issuance must be in progress. The PEM encoded CSR is
+written to stdout, or to the file given with --output-file.`))
+
+	example = templates.Examples(i18n.T(`
+# Export the CSR of the Certificate named 'intermediate-ca' in the current context namespace
+kubectl cert-manager external-signing export-csr intermediate-ca
+
+# Export the CSR of the Certificate named 'intermediate-ca' in namespace 'pki' to the file 'intermediate-ca.csr'
+kubectl cert-manager external-signing export-csr intermediate-ca --namespace pki --output-file intermediate-ca.csr`))
+)
+
+// Options is a struct to support export-csr command
+type Options struct {
+	CMClient   cmclient.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace that the Certificate resides in.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace string
+
+	// OutputFile is the file the CSR is written to. If empty, the CSR is
+	// written to stdout.
+	OutputFile string
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdExportCSR returns a cobra command for exporting the CSR of a
+// Certificate that is signed externally
+func NewCmdExportCSR(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "export-csr",
+		Short:   "Export the CSR of a Certificate that is signed outside of cert-manager",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		ValidArgsFunction: completion.CertificateNames(factory, 1),
+	}
+
+	cmd.Flags().StringVar(&o.OutputFile, "output-file", o.OutputFile, "Name of the file the CSR is written to, defaults to stdout")
+
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) < 1 {
+		return errors.New("the name of the Certificate has to be provided as argument")
+	}
+	if len(args) > 1 {
+		return errors.New("only one argument can be passed in: the name of the Certificate")
+	}
+	return nil
+}
+
+// Complete takes the factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory) error {
+	var err error
+
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes export-csr command
+func (o *Options) Run(args []string) error {
+	ctx := context.TODO()
+
+	crt, err := o.CMClient.CertmanagerV1alpha2().Certificates(o.Namespace).Get(ctx, args[0], metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error when getting Certificate resource: %v", err)
+	}
+
+	req, err := util.PendingExternalSigningRequest(ctx, o.CMClient, crt)
+	if err != nil {
+		return err
+	}
+	if len(req.Status.Certificate) > 0 {
+		return fmt.Errorf("the signed certificate has already been imported into CertificateRequest %s/%s", req.Namespace, req.Name)
+	}
+
+	if o.OutputFile == "" {
+		_, err := o.Out.Write(req.Spec.CSRPEM)
+		return err
+	}
+	if err := ioutil.WriteFile(o.OutputFile, req.Spec.CSRPEM, 0644); err != nil {
+		return fmt.Errorf("error when writing CSR to file: %w", err)
+	}
+	fmt.Fprintf(o.ErrOut, "CSR of CertificateRequest %s/%s written to %s\n", req.Namespace, req.Name, o.OutputFile)
+	return nil
+}
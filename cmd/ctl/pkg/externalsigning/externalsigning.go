@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsigning
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/externalsigning/exportcsr"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/externalsigning/importcert"
+)
+
+func NewCmdExternalSigning(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	cmds := &cobra.Command{
+		Use:   "external-signing",
+		Short: "Sign Certificates outside of cert-manager, e.g. with an offline root CA",
+		Long: `Sign Certificates outside of cert-manager, e.g. with an offline root CA.
+
+For Certificates with spec.externalSigning set, cert-manager generates the private key and a CSR for each issuance,
+but does not sign it. Export the CSR, sign it with the external CA and import the signed certificate to complete
+the issuance.`,
+	}
+
+	cmds.AddCommand(exportcsr.NewCmdExportCSR(ioStreams, factory))
+	cmds.AddCommand(importcert.NewCmdImportCert(ioStreams, factory))
+
+	return cmds
+}
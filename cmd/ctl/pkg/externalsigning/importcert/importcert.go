@@ -0,0 +1,233 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importcert
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/completion"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/util"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Import a certificate signed outside of cert-manager to complete the pending issuance of a Certificate.
+
+The certificate given with --cert must be PEM encoded, may be followed by the intermediate certificates of its
+chain, and must be issued for the public key of the exported CSR. If the certificate of the signing CA is given
+with --ca, the signature of the imported certificate is verified against it and the CA certificate is stored in
+the Secret of the Certificate as ca.crt.`))
+
+	example = templates.Examples(i18n.T(`
+# Import the externally signed certificate for the Certificate named 'intermediate-ca'
+kubectl cert-manager external-signing import intermediate-ca --cert intermediate-ca.crt --ca root-ca.crt`))
+)
+
+// Options is a struct to support import command
+type Options struct {
+	CMClient   cmclient.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace that the Certificate resides in.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace string
+
+	// CertFile is the file containing the signed certificate
+	CertFile string
+	// CAFile is the file containing the certificate of the signing CA
+	CAFile string
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdImportCert returns a cobra command for importing the externally
+// signed certificate of a Certificate
+func NewCmdImportCert(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "import",
+		Short:   "Import a certificate signed outside of cert-manager",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		ValidArgsFunction: completion.CertificateNames(factory, 1),
+	}
+
+	cmd.Flags().StringVar(&o.CertFile, "cert", o.CertFile, "Name of the file containing the PEM encoded signed certificate")
+	cmd.Flags().StringVar(&o.CAFile, "ca", o.CAFile, "Name of the file containing the PEM encoded certificate of the signing CA")
+
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) < 1 {
+		return errors.New("the name of the Certificate has to be provided as argument")
+	}
+	if len(args) > 1 {
+		return errors.New("only one argument can be passed in: the name of the Certificate")
+	}
+	if o.CertFile == "" {
+		return errors.New("the file containing the signed certificate has to be provided with --cert")
+	}
+	return nil
+}
+
+// Complete takes the factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory) error {
+	var err error
+
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes import command
+func (o *Options) Run(args []string) error {
+	ctx := context.TODO()
+
+	certPEM, err := ioutil.ReadFile(o.CertFile)
+	if err != nil {
+		return fmt.Errorf("error when reading signed certificate: %w", err)
+	}
+	var caPEM []byte
+	if o.CAFile != "" {
+		caPEM, err = ioutil.ReadFile(o.CAFile)
+		if err != nil {
+			return fmt.Errorf("error when reading CA certificate: %w", err)
+		}
+	}
+
+	crt, err := o.CMClient.CertmanagerV1alpha2().Certificates(o.Namespace).Get(ctx, args[0], metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error when getting Certificate resource: %v", err)
+	}
+
+	req, err := util.PendingExternalSigningRequest(ctx, o.CMClient, crt)
+	if err != nil {
+		return err
+	}
+	if len(req.Status.Certificate) > 0 {
+		return fmt.Errorf("the signed certificate has already been imported into CertificateRequest %s/%s", req.Namespace, req.Name)
+	}
+
+	if err := verifySignedCertificate(req.Spec.CSRPEM, certPEM, caPEM); err != nil {
+		return fmt.Errorf("the certificate cannot be imported into CertificateRequest %s/%s: %v", req.Namespace, req.Name, err)
+	}
+
+	req = req.DeepCopy()
+	req.Status.Certificate = certPEM
+	req.Status.CA = caPEM
+	apiutil.SetCertificateRequestCondition(req, cmapi.CertificateRequestConditionReady, cmmeta.ConditionTrue, cmapi.CertificateRequestReasonIssued, "Certificate signed externally and imported")
+	if _, err := o.CMClient.CertmanagerV1alpha2().CertificateRequests(req.Namespace).UpdateStatus(ctx, req, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to import the signed certificate into CertificateRequest %s/%s: %v", req.Namespace, req.Name, err)
+	}
+
+	fmt.Fprintf(o.Out, "Imported the signed certificate into CertificateRequest %s/%s, it will be stored in Secret %q\n", req.Namespace, req.Name, crt.Spec.SecretName)
+	return nil
+}
+
+// verifySignedCertificate checks that the first certificate of the PEM encoded
+// chain is a CA certificate issued for the public key of the CSR. If caPEM is
+// not empty, the chain is also verified against it.
+func verifySignedCertificate(csrPEM, certPEM, caPEM []byte) error {
+	csr, err := pki.DecodeX509CertificateRequestBytes(csrPEM)
+	if err != nil {
+		return fmt.Errorf("failed to decode CSR: %v", err)
+	}
+	chain, err := pki.DecodeX509CertificateChainBytes(certPEM)
+	if err != nil {
+		return fmt.Errorf("failed to decode signed certificate: %v", err)
+	}
+	cert := chain[0]
+
+	matches, err := pki.PublicKeyMatchesCertificate(csr.PublicKey, cert)
+	if err != nil {
+		return err
+	}
+	if !matches {
+		return errors.New("the signed certificate was not issued for the public key of the CSR")
+	}
+	if !cert.IsCA {
+		return errors.New("the signed certificate is not a CA certificate")
+	}
+
+	if len(caPEM) == 0 {
+		return nil
+	}
+	cas, err := pki.DecodeX509CertificateChainBytes(caPEM)
+	if err != nil {
+		return fmt.Errorf("failed to decode CA certificate: %v", err)
+	}
+	roots := x509.NewCertPool()
+	for _, ca := range cas {
+		roots.AddCert(ca)
+	}
+	intermediates := x509.NewCertPool()
+	for _, intermediate := range chain[1:] {
+		intermediates.AddCert(intermediate)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return fmt.Errorf("the signed certificate cannot be verified against the CA certificate: %v", err)
+	}
+	return nil
+}
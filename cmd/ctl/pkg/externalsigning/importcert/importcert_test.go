@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importcert
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+func TestVerifySignedCertificate(t *testing.T) {
+	mustKey := func() crypto.Signer {
+		key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	sign := func(cn string, isCA bool, pub crypto.PublicKey, parent *x509.Certificate, parentKey crypto.Signer) ([]byte, *x509.Certificate) {
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: cn},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(time.Hour),
+			BasicConstraintsValid: true,
+			IsCA:                  isCA,
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		}
+		if parent == nil {
+			parent = template
+		}
+		certPEM, cert, err := pki.SignCertificate(template, parent, pub, parentKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return certPEM, cert
+	}
+
+	rootKey := mustKey()
+	rootPEM, root := sign("root", true, rootKey.Public(), nil, rootKey)
+	otherRootKey := mustKey()
+	otherRootPEM, _ := sign("other-root", true, otherRootKey.Public(), nil, otherRootKey)
+
+	key := mustKey()
+	csrDER, err := pki.EncodeCSR(&x509.CertificateRequest{Subject: pkix.Name{CommonName: "intermediate"}}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+	intermediatePEM, _ := sign("intermediate", true, key.Public(), root, rootKey)
+	leafPEM, _ := sign("leaf", false, key.Public(), root, rootKey)
+	otherKeyPEM, _ := sign("intermediate", true, mustKey().Public(), root, rootKey)
+
+	tests := map[string]struct {
+		certPEM   []byte
+		caPEM     []byte
+		expectErr bool
+	}{
+		"CA certificate for the CSR without a CA certificate": {
+			certPEM: intermediatePEM,
+		},
+		"CA certificate for the CSR signed by the given CA": {
+			certPEM: intermediatePEM,
+			caPEM:   rootPEM,
+		},
+		"CA certificate for the CSR signed by another CA": {
+			certPEM:   intermediatePEM,
+			caPEM:     otherRootPEM,
+			expectErr: true,
+		},
+		"certificate for another public key": {
+			certPEM:   otherKeyPEM,
+			expectErr: true,
+		},
+		"certificate that is not a CA certificate": {
+			certPEM:   leafPEM,
+			expectErr: true,
+		},
+		"invalid certificate PEM": {
+			certPEM:   []byte("not a certificate"),
+			expectErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := verifySignedCertificate(csrPEM, test.certPEM, test.caPEM)
+			if test.expectErr != (err != nil) {
+				t.Errorf("expected error %t but got: %v", test.expectErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	ctlstatus "github.com/jetstack/cert-manager/pkg/ctl/status"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Print the details of the PEM encoded certificates in a file, without connecting to a cluster.
+
+The certificates are printed in the same format as the certificate stored in the Secret of a Certificate
+is printed by 'kubectl cert-manager status certificate'. The certificates in the file are expected to be
+ordered from the leaf certificate to the root, each one signed by the next.
+
+If a private key is given, it is checked to match the first certificate in the file. If CA certificates
+are given, the last certificate in the file is checked to be signed by one of them.
+Signature algorithms and keys considered weak are reported as warnings.
+
+The command fails if the chain is not ordered, the private key does not match or the chain is not signed
+by the given CA.`))
+
+	example = templates.Examples(i18n.T(`
+# Print the details of the certificates in tls.crt
+kubectl cert-manager inspect file tls.crt
+
+# Check that tls.key is the private key of the certificate, and that the chain is signed by ca.crt
+kubectl cert-manager inspect file tls.crt --key tls.key --ca ca.crt
+`))
+)
+
+var (
+	// weakSignatureAlgorithms are signature algorithms that rely on
+	// deprecated hash functions or DSA
+	weakSignatureAlgorithms = map[x509.SignatureAlgorithm]bool{
+		x509.MD2WithRSA:    true,
+		x509.MD5WithRSA:    true,
+		x509.SHA1WithRSA:   true,
+		x509.DSAWithSHA1:   true,
+		x509.DSAWithSHA256: true,
+		x509.ECDSAWithSHA1: true,
+	}
+
+	// minRSAKeySize is the minimum size of RSA keys not considered weak
+	minRSAKeySize = 2048
+	// minECDSAKeySize is the minimum size of ECDSA keys not considered weak
+	minECDSAKeySize = 256
+)
+
+// Options is a struct to support inspect file command
+type Options struct {
+	// CertFile is the file holding the PEM encoded certificate chain
+	CertFile string
+	// KeyFile is the file holding the PEM encoded private key of the first
+	// certificate in CertFile, if set
+	KeyFile string
+	// CAFile is the file holding the PEM encoded CA certificates that sign
+	// the chain in CertFile, if set
+	CAFile string
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdInspectFile returns a cobra command for inspect file
+func NewCmdInspectFile(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "file <certificate file>",
+		Short:   "Print the details of the certificates in a file and check them for common problems",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().StringVar(&o.KeyFile, "key", o.KeyFile, "Path to the PEM encoded private key of the first certificate in the file")
+	cmd.Flags().StringVar(&o.CAFile, "ca", o.CAFile, "Path to the PEM encoded CA certificates expected to sign the last certificate in the file")
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) < 1 {
+		return errors.New("the path to the certificate file has to be provided as argument")
+	}
+	if len(args) > 1 {
+		return errors.New("only one argument can be passed in: the path to the certificate file")
+	}
+	o.CertFile = args[0]
+	return nil
+}
+
+// Run executes inspect file command
+func (o *Options) Run() error {
+	certs, err := readCertificates(o.CertFile)
+	if err != nil {
+		return err
+	}
+
+	var key crypto.Signer
+	if o.KeyFile != "" {
+		keyPEM, err := ioutil.ReadFile(o.KeyFile)
+		if err != nil {
+			return fmt.Errorf("error when reading private key file: %w", err)
+		}
+		key, err = pki.DecodePrivateKeyBytes(keyPEM)
+		if err != nil {
+			return fmt.Errorf("error when parsing private key file %q: %w", o.KeyFile, err)
+		}
+	}
+
+	var cas []*x509.Certificate
+	if o.CAFile != "" {
+		cas, err = readCertificates(o.CAFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	problems, warnings := checkBundle(certs, key, cas)
+	fmt.Fprint(o.Out, describeBundle(certs, problems, warnings))
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problem(s) with the certificates in %q", len(problems), o.CertFile)
+	}
+	return nil
+}
+
+// readCertificates reads the PEM encoded certificates in the file at path
+func readCertificates(path string) ([]*x509.Certificate, error) {
+	certPEM, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error when reading certificate file: %w", err)
+	}
+	certs, err := pki.DecodeX509CertificateChainBytes(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("error when parsing certificate file %q: %w", path, err)
+	}
+	return certs, nil
+}
+
+// checkBundle checks that certs are ordered from the leaf to the root, that
+// key, if not nil, is the private key of the leaf, and that the chain is
+// signed by one of cas, if any. It returns the problems found, and warnings
+// about weak algorithms used by certs or cas.
+func checkBundle(certs []*x509.Certificate, key crypto.Signer, cas []*x509.Certificate) (problems, warnings []string) {
+	for i := 0; i < len(certs)-1; i++ {
+		if err := certs[i].CheckSignatureFrom(certs[i+1]); err != nil {
+			problems = append(problems, fmt.Sprintf("certificate %d is not signed by certificate %d, the chain is not ordered from leaf to root: %v", i+1, i+2, err))
+		}
+	}
+
+	if len(cas) > 0 && !signedByAny(certs[len(certs)-1], cas) {
+		problems = append(problems, fmt.Sprintf("certificate %d is not signed by any of the CA certificates", len(certs)))
+	}
+
+	if key != nil {
+		matches, err := pki.PublicKeyMatchesCertificate(key.Public(), certs[0])
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("private key could not be compared with certificate 1: %v", err))
+		case !matches:
+			problems = append(problems, "private key does not match certificate 1")
+		}
+	}
+
+	for i, cert := range certs {
+		warnings = append(warnings, weakAlgorithms(fmt.Sprintf("certificate %d", i+1), cert)...)
+	}
+	for i, ca := range cas {
+		warnings = append(warnings, weakAlgorithms(fmt.Sprintf("CA certificate %d", i+1), ca)...)
+	}
+
+	return problems, warnings
+}
+
+// signedByAny returns true if cert is one of cas, or is signed by one of them
+func signedByAny(cert *x509.Certificate, cas []*x509.Certificate) bool {
+	for _, ca := range cas {
+		if cert.Equal(ca) || cert.CheckSignatureFrom(ca) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// weakAlgorithms returns a warning for each weak algorithm used by cert. The
+// signature of self-signed certificates is not checked, since it is not
+// relied upon when verifying a chain.
+func weakAlgorithms(name string, cert *x509.Certificate) []string {
+	var warnings []string
+	if weakSignatureAlgorithms[cert.SignatureAlgorithm] && !bytes.Equal(cert.RawIssuer, cert.RawSubject) {
+		warnings = append(warnings, fmt.Sprintf("%s is signed with the weak signature algorithm %s", name, cert.SignatureAlgorithm))
+	}
+
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if size := pub.N.BitLen(); size < minRSAKeySize {
+			warnings = append(warnings, fmt.Sprintf("%s has a weak %d bit RSA key, at least %d bits are recommended", name, size, minRSAKeySize))
+		}
+	case *ecdsa.PublicKey:
+		if size := pub.Curve.Params().BitSize; size < minECDSAKeySize {
+			warnings = append(warnings, fmt.Sprintf("%s has a weak %d bit ECDSA key, at least %d bits are recommended", name, size, minECDSAKeySize))
+		}
+	case *dsa.PublicKey:
+		warnings = append(warnings, fmt.Sprintf("%s has a DSA key, which is deprecated", name))
+	}
+	return warnings
+}
+
+// describeBundle returns the details of certs, followed by the problems and
+// warnings found, as a string to be printed as output
+func describeBundle(certs []*x509.Certificate, problems, warnings []string) string {
+	output := ""
+	for i, cert := range certs {
+		output += fmt.Sprintf("Certificate %d of %d:\n", i+1, len(certs))
+		output += fmt.Sprintf("  Subject: %s\n", cert.Subject)
+		output += fmt.Sprintf("  DNS Names: %s\n", strings.Join(cert.DNSNames, ", "))
+		output += fmt.Sprintf("  Not Before: %s\n", cert.NotBefore.Format(time.RFC3339))
+		output += fmt.Sprintf("  Not After: %s\n", cert.NotAfter.Format(time.RFC3339))
+		output += ctlstatus.DescribeX509Certificate(cert, "  ")
+	}
+	output += "Problems:\n" + formatList(problems)
+	output += "Warnings:\n" + formatList(warnings)
+	return output
+}
+
+func formatList(items []string) string {
+	if len(items) == 0 {
+		return "  None\n"
+	}
+	output := ""
+	for _, item := range items {
+		output += fmt.Sprintf("  - %s\n", item)
+	}
+	return output
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckBundle(t *testing.T) {
+	newCert := func(name string, key crypto.Signer, isCA bool, parent *x509.Certificate, parentKey crypto.Signer) *x509.Certificate {
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: name},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(time.Hour),
+			IsCA:                  isCA,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		}
+		if parent == nil {
+			parent, parentKey = template, key
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cert
+	}
+	newECDSAKey := func(curve elliptic.Curve) crypto.Signer {
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+
+	rootKey, intermediateKey, leafKey := newECDSAKey(elliptic.P256()), newECDSAKey(elliptic.P256()), newECDSAKey(elliptic.P256())
+	root := newCert("root", rootKey, true, nil, nil)
+	intermediate := newCert("intermediate", intermediateKey, true, root, rootKey)
+	leaf := newCert("leaf", leafKey, false, intermediate, intermediateKey)
+	otherRoot := newCert("other-root", newECDSAKey(elliptic.P256()), true, nil, nil)
+
+	weakRSAKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weakLeaf := newCert("weak-rsa", weakRSAKey, false, intermediate, intermediateKey)
+	weakCA := newCert("weak-ecdsa", newECDSAKey(elliptic.P224()), true, nil, nil)
+
+	tests := map[string]struct {
+		certs []*x509.Certificate
+		key   crypto.Signer
+		cas   []*x509.Certificate
+
+		expProblems []string
+		expWarnings []string
+	}{
+		"ordered chain with matching key signed by CA": {
+			certs: []*x509.Certificate{leaf, intermediate},
+			key:   leafKey,
+			cas:   []*x509.Certificate{otherRoot, root},
+		},
+		"chain including the root CA": {
+			certs: []*x509.Certificate{leaf, intermediate, root},
+			cas:   []*x509.Certificate{root},
+		},
+		"chain in the wrong order": {
+			certs:       []*x509.Certificate{intermediate, leaf},
+			expProblems: []string{"certificate 1 is not signed by certificate 2, the chain is not ordered from leaf to root"},
+		},
+		"private key of another certificate": {
+			certs:       []*x509.Certificate{leaf, intermediate},
+			key:         intermediateKey,
+			expProblems: []string{"private key does not match certificate 1"},
+		},
+		"chain not signed by CA": {
+			certs:       []*x509.Certificate{leaf, intermediate},
+			cas:         []*x509.Certificate{otherRoot},
+			expProblems: []string{"certificate 2 is not signed by any of the CA certificates"},
+		},
+		"weak keys": {
+			certs: []*x509.Certificate{weakLeaf, intermediate},
+			key:   weakRSAKey,
+			cas:   []*x509.Certificate{weakCA},
+			expProblems: []string{
+				"certificate 2 is not signed by any of the CA certificates",
+			},
+			expWarnings: []string{
+				"certificate 1 has a weak 1024 bit RSA key, at least 2048 bits are recommended",
+				"CA certificate 1 has a weak 224 bit ECDSA key, at least 256 bits are recommended",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			problems, warnings := checkBundle(test.certs, test.key, test.cas)
+
+			// Problems can end with the error returned by crypto/x509, so
+			// only their prefix is compared
+			if len(problems) != len(test.expProblems) {
+				t.Fatalf("unexpected problems; expected: %q, got: %q", test.expProblems, problems)
+			}
+			for i := range problems {
+				if !strings.HasPrefix(problems[i], test.expProblems[i]) {
+					t.Errorf("unexpected problems; expected: %q, got: %q", test.expProblems, problems)
+				}
+			}
+			if len(warnings) > 0 || len(test.expWarnings) > 0 {
+				if !reflect.DeepEqual(warnings, test.expWarnings) {
+					t.Errorf("unexpected warnings; expected: %q, got: %q", test.expWarnings, warnings)
+				}
+			}
+		})
+	}
+}
+
+func TestDescribeBundle(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com", "www.example.com"},
+		NotBefore:    time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := describeBundle([]*x509.Certificate{cert}, nil, []string{"certificate 1 is weak"})
+	for _, exp := range []string{
+		"Certificate 1 of 1:\n  Subject: CN=example.com\n  DNS Names: example.com, www.example.com\n",
+		"  Not Before: 2020-06-01T00:00:00Z\n  Not After: 2020-09-01T00:00:00Z\n",
+		"  Issuer Common Name: example.com\n",
+		"  Public Key Algorithm: ECDSA\n",
+		"  Serial Number: 2a\n",
+		"Problems:\n  None\nWarnings:\n  - certificate 1 is weak\n",
+	} {
+		if !strings.Contains(output, exp) {
+			t.Errorf("expected output to contain:\n%s\ngot:\n%s", exp, output)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package multicluster implements the --contexts flag of commands that can
+// audit several clusters in one invocation.
+package multicluster
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// AddContextsFlag registers the --contexts flag on flags, storing the
+// selected kubeconfig contexts in contexts.
+func AddContextsFlag(flags *pflag.FlagSet, contexts *[]string) {
+	flags.StringSliceVar(contexts, "contexts", *contexts, "Comma separated list of kubeconfig contexts to run the command against, one after the other. "+
+		"The namespace of each context is used unless --namespace or --all-namespaces is given")
+}
+
+// Validate returns an error if contexts are selected together with global
+// flags that pin the command to a single cluster.
+func Validate(flags *genericclioptions.ConfigFlags, contexts []string) error {
+	if len(contexts) == 0 {
+		return nil
+	}
+	if flags != nil {
+		if isSet(flags.Context) {
+			return errors.New("--contexts and --context cannot be used together")
+		}
+		if isSet(flags.ClusterName) || isSet(flags.APIServer) {
+			return errors.New("--contexts cannot be used together with --cluster or --server")
+		}
+	}
+	seen := make(map[string]bool, len(contexts))
+	for _, c := range contexts {
+		if c == "" {
+			return errors.New("--contexts must not contain empty context names")
+		}
+		if seen[c] {
+			return fmt.Errorf("context %q is given more than once in --contexts", c)
+		}
+		seen[c] = true
+	}
+	return nil
+}
+
+// FactoryForContext returns a factory that is configured like flags, e.g.
+// with the same kubeconfig, namespace and impersonation settings, but that
+// connects to the cluster of the given kubeconfig context.
+func FactoryForContext(flags *genericclioptions.ConfigFlags, context string) cmdutil.Factory {
+	f := genericclioptions.NewConfigFlags(true)
+	f.CacheDir = flags.CacheDir
+	f.KubeConfig = flags.KubeConfig
+	f.ClusterName = flags.ClusterName
+	f.AuthInfoName = flags.AuthInfoName
+	f.Context = &context
+	f.Namespace = flags.Namespace
+	f.APIServer = flags.APIServer
+	f.TLSServerName = flags.TLSServerName
+	f.Insecure = flags.Insecure
+	f.CertFile = flags.CertFile
+	f.KeyFile = flags.KeyFile
+	f.CAFile = flags.CAFile
+	f.BearerToken = flags.BearerToken
+	f.Impersonate = flags.Impersonate
+	f.ImpersonateGroup = flags.ImpersonateGroup
+	f.Username = flags.Username
+	f.Password = flags.Password
+	f.Timeout = flags.Timeout
+	return cmdutil.NewFactory(cmdutil.NewMatchVersionFlags(f))
+}
+
+func isSet(s *string) bool {
+	return s != nil && *s != ""
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+current-context: a
+clusters:
+- name: a
+  cluster:
+    server: https://a.example.com
+- name: b
+  cluster:
+    server: https://b.example.com
+users:
+- name: admin
+  user:
+    token: secret
+contexts:
+- name: a
+  context:
+    cluster: a
+    user: admin
+    namespace: ns-a
+- name: b
+  context:
+    cluster: b
+    user: admin
+    namespace: ns-b
+`
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		context, cluster string
+		contexts         []string
+		expErr           bool
+	}{
+		"no contexts with --context is valid": {
+			context: "a",
+		},
+		"several contexts are valid": {
+			contexts: []string{"a", "b"},
+		},
+		"contexts with --context is invalid": {
+			context:  "a",
+			contexts: []string{"a", "b"},
+			expErr:   true,
+		},
+		"contexts with --cluster is invalid": {
+			cluster:  "a",
+			contexts: []string{"a", "b"},
+			expErr:   true,
+		},
+		"empty context name is invalid": {
+			contexts: []string{"a", ""},
+			expErr:   true,
+		},
+		"duplicate context is invalid": {
+			contexts: []string{"a", "b", "a"},
+			expErr:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			flags := genericclioptions.NewConfigFlags(true)
+			*flags.Context = test.context
+			*flags.ClusterName = test.cluster
+			err := Validate(flags, test.contexts)
+			if err != nil && !test.expErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if err == nil && test.expErr {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestFactoryForContext(t *testing.T) {
+	dir, err := ioutil.TempDir("", "multicluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(path, []byte(kubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		namespace, impersonate string
+		expHost, expNamespace  string
+	}{
+		"uses the server and namespace of the context": {
+			expHost:      "https://b.example.com",
+			expNamespace: "ns-b",
+		},
+		"keeps --namespace and --as of the global flags": {
+			namespace:    "other",
+			impersonate:  "auditor",
+			expHost:      "https://b.example.com",
+			expNamespace: "other",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			flags := genericclioptions.NewConfigFlags(true)
+			*flags.KubeConfig = path
+			*flags.Namespace = test.namespace
+			*flags.Impersonate = test.impersonate
+
+			f := FactoryForContext(flags, "b")
+			config, err := f.ToRESTConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Host != test.expHost {
+				t.Errorf("expected host %q, got %q", test.expHost, config.Host)
+			}
+			if config.Impersonate.UserName != test.impersonate {
+				t.Errorf("expected to impersonate %q, got %q", test.impersonate, config.Impersonate.UserName)
+			}
+			namespace, _, err := f.ToRawKubeConfigLoader().Namespace()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if namespace != test.expNamespace {
+				t.Errorf("expected namespace %q, got %q", test.expNamespace, namespace)
+			}
+		})
+	}
+}
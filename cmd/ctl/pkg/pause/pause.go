@@ -0,0 +1,235 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pause
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/completion"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+)
+
+var (
+	pauseLong = templates.LongDesc(i18n.T(`
+Pause reconciliation of cert-manager resources.
+
+While a resource is paused cert-manager will not make any changes to it. Pausing a Certificate also stops
+the revocation of its certificates and the sweeping of its private keys, but it does not pause its
+CertificateRequests, which have to be paused separately. Pausing an Order also pauses its Challenges.
+This can be used to stop cert-manager acting on a misbehaving resource without deleting it.
+Supported resource types are certificate, certificaterequest and order.`))
+
+	pauseExample = templates.Examples(i18n.T(`
+# Pause the Certificate named 'my-app' in the current context namespace.
+kubectl cert-manager pause certificate my-app
+
+# Pause the Orders named 'my-app-1-123' and 'my-app-1-456' in the 'sandbox' namespace.
+kubectl cert-manager pause order my-app-1-123 my-app-1-456 --namespace sandbox`))
+
+	resumeLong = templates.LongDesc(i18n.T(`
+Resume reconciliation of cert-manager resources that have previously been paused.
+Supported resource types are certificate, certificaterequest and order.`))
+
+	resumeExample = templates.Examples(i18n.T(`
+# Resume the Certificate named 'my-app' in the current context namespace.
+kubectl cert-manager resume certificate my-app`))
+)
+
+// Options is a struct to support pause and resume commands
+type Options struct {
+	CMClient   cmclient.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace that the resources to be paused reside in.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace string
+
+	// Paused is the desired state of the resources, true for the pause
+	// command and false for the resume command
+	Paused bool
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams, paused bool) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+		Paused:    paused,
+	}
+}
+
+// NewCmdPause returns a cobra command for pausing reconciliation of resources
+func NewCmdPause(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams, true)
+	return &cobra.Command{
+		Use:     "pause",
+		Short:   "Pause reconciliation of a cert-manager resource",
+		Long:    pauseLong,
+		Example: pauseExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		ValidArgsFunction: completion.ResourceTypeAndNames(factory, resourceTypes, resourceKind),
+	}
+}
+
+// NewCmdResume returns a cobra command for resuming reconciliation of
+// resources
+func NewCmdResume(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams, false)
+	return &cobra.Command{
+		Use:     "resume",
+		Short:   "Resume reconciliation of a paused cert-manager resource",
+		Long:    resumeLong,
+		Example: resumeExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		ValidArgsFunction: completion.ResourceTypeAndNames(factory, resourceTypes, resourceKind),
+	}
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) < 2 {
+		return errors.New("the resource type and the name of at least one resource have to be provided as arguments")
+	}
+
+	if _, err := resourceKind(args[0]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Complete takes the command arguments and factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes pause or resume command
+func (o *Options) Run(args []string) error {
+	ctx := context.TODO()
+
+	kind, err := resourceKind(args[0])
+	if err != nil {
+		return err
+	}
+
+	patch, err := pausedPatch(o.Paused)
+	if err != nil {
+		return err
+	}
+
+	action := "Paused"
+	if !o.Paused {
+		action = "Resumed"
+	}
+
+	for _, name := range args[1:] {
+		if err := o.patch(ctx, kind, name, patch); err != nil {
+			return fmt.Errorf("failed to update %s %s/%s: %v", kind, o.Namespace, name, err)
+		}
+		fmt.Fprintf(o.Out, "%s reconciliation of %s %s/%s\n", action, kind, o.Namespace, name)
+	}
+
+	return nil
+}
+
+func (o *Options) patch(ctx context.Context, kind, name string, patch []byte) error {
+	var err error
+	switch kind {
+	case cmapi.CertificateKind:
+		_, err = o.CMClient.CertmanagerV1alpha2().Certificates(o.Namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	case cmapi.CertificateRequestKind:
+		_, err = o.CMClient.CertmanagerV1alpha2().CertificateRequests(o.Namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	case orderKind:
+		_, err = o.CMClient.AcmeV1alpha2().Orders(o.Namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	}
+	return err
+}
+
+const orderKind = "Order"
+
+// resourceTypes are the resource types completed as first argument
+var resourceTypes = []string{"certificate", "certificaterequest", "order"}
+
+// resourceKind returns the kind of the resource type given as argument, which
+// may be singular, plural or a short name.
+func resourceKind(resource string) (string, error) {
+	switch strings.ToLower(resource) {
+	case "certificate", "certificates", "cert", "certs":
+		return cmapi.CertificateKind, nil
+	case "certificaterequest", "certificaterequests", "cr", "crs":
+		return cmapi.CertificateRequestKind, nil
+	case "order", "orders":
+		return orderKind, nil
+	default:
+		return "", fmt.Errorf("unsupported resource type %q, must be one of certificate, certificaterequest or order", resource)
+	}
+}
+
+// pausedPatch returns a JSON merge patch that sets the paused annotation, or
+// removes it if paused is false.
+func pausedPatch(paused bool) ([]byte, error) {
+	var value interface{}
+	if paused {
+		value = "true"
+	}
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				cmapi.PausedAnnotationKey: value,
+			},
+		},
+	})
+}
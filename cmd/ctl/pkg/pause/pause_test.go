@@ -0,0 +1,182 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pause
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		args   []string
+		expErr bool
+	}{
+		"If there are no arguments, error": {
+			expErr: true,
+		},
+		"If there is no resource name, error": {
+			args:   []string{"certificate"},
+			expErr: true,
+		},
+		"If the resource type is not supported, error": {
+			args:   []string{"issuer", "ca"},
+			expErr: true,
+		},
+		"If the resource type and names are given, don't error": {
+			args: []string{"certs", "foo", "bar"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := NewOptions(genericclioptions.IOStreams{}, true).Validate(test.args)
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestResourceKind(t *testing.T) {
+	for resource, kind := range map[string]string{
+		"certificate":         cmapi.CertificateKind,
+		"Certs":               cmapi.CertificateKind,
+		"certificaterequests": cmapi.CertificateRequestKind,
+		"cr":                  cmapi.CertificateRequestKind,
+		"orders":              orderKind,
+	} {
+		got, err := resourceKind(resource)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", resource, err)
+		}
+		if got != kind {
+			t.Errorf("unexpected kind for %q, exp=%s got=%s", resource, kind, got)
+		}
+	}
+	if _, err := resourceKind("challenge"); err == nil {
+		t.Errorf("expected error for unsupported resource type")
+	}
+}
+
+func TestPausedPatch(t *testing.T) {
+	for paused, exp := range map[bool]string{
+		true:  `{"metadata":{"annotations":{"cert-manager.io/paused":"true"}}}`,
+		false: `{"metadata":{"annotations":{"cert-manager.io/paused":null}}}`,
+	} {
+		patch, err := pausedPatch(paused)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(patch) != exp {
+			t.Errorf("unexpected patch for paused=%t, exp=%s got=%s", paused, exp, patch)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	meta := func(name string, paused bool) metav1.ObjectMeta {
+		m := metav1.ObjectMeta{Namespace: "default", Name: name}
+		if paused {
+			m.Annotations = map[string]string{cmapi.PausedAnnotationKey: "true"}
+		}
+		return m
+	}
+
+	tests := map[string]struct {
+		paused   bool
+		existing []runtime.Object
+		args     []string
+
+		// get returns the resource that was patched
+		get    func(cl *cmfake.Clientset) (metav1.Object, error)
+		expErr bool
+		expOut string
+	}{
+		"pause a Certificate": {
+			paused:   true,
+			existing: []runtime.Object{&cmapi.Certificate{ObjectMeta: meta("crt", false)}},
+			args:     []string{"certificate", "crt"},
+			get: func(cl *cmfake.Clientset) (metav1.Object, error) {
+				return cl.CertmanagerV1alpha2().Certificates("default").Get(context.TODO(), "crt", metav1.GetOptions{})
+			},
+			expOut: "Paused reconciliation of Certificate default/crt\n",
+		},
+		"pause a CertificateRequest": {
+			paused:   true,
+			existing: []runtime.Object{&cmapi.CertificateRequest{ObjectMeta: meta("cr", false)}},
+			args:     []string{"cr", "cr"},
+			get: func(cl *cmfake.Clientset) (metav1.Object, error) {
+				return cl.CertmanagerV1alpha2().CertificateRequests("default").Get(context.TODO(), "cr", metav1.GetOptions{})
+			},
+			expOut: "Paused reconciliation of CertificateRequest default/cr\n",
+		},
+		"resume an Order": {
+			paused:   false,
+			existing: []runtime.Object{&cmacme.Order{ObjectMeta: meta("order", true)}},
+			args:     []string{"order", "order"},
+			get: func(cl *cmfake.Clientset) (metav1.Object, error) {
+				return cl.AcmeV1alpha2().Orders("default").Get(context.TODO(), "order", metav1.GetOptions{})
+			},
+			expOut: "Resumed reconciliation of Order default/order\n",
+		},
+		"fail if the resource does not exist": {
+			paused: true,
+			args:   []string{"certificate", "crt"},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := cmfake.NewSimpleClientset(test.existing...)
+			out := new(bytes.Buffer)
+			o := NewOptions(genericclioptions.IOStreams{Out: out}, test.paused)
+			o.CMClient = client
+			o.Namespace = "default"
+
+			err := o.Run(test.args)
+			if test.expErr != (err != nil) {
+				t.Fatalf("expected error=%t got=%v", test.expErr, err)
+			}
+			if out.String() != test.expOut {
+				t.Errorf("unexpected output, exp=%q got=%q", test.expOut, out.String())
+			}
+			if test.get == nil {
+				return
+			}
+
+			obj, err := test.get(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if paused := apiutil.IsPaused(obj); paused != test.paused {
+				t.Errorf("expected resource to be paused=%t, got %t", test.paused, paused)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rekey
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/completion"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/util"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Trigger the issuance of a cert-manager Certificate with a newly generated private key, regardless of the
+rotation policy of the Certificate.
+
+The Certificate is annotated with cert-manager.io/rotate-private-key, set to the revision of the next issuance,
+and marked for manual renewal. Later issuances follow the rotation policy of the Certificate again.
+
+If the Secret of the Certificate is mounted by running Pods, they are listed and confirmation is asked for
+before rekeying, as the workloads have to reload the new private key. Use --yes to skip the confirmation.`))
+
+	example = templates.Examples(i18n.T(`
+# Rekey the Certificate named 'my-app' in the current context namespace
+kubectl cert-manager rekey my-app
+
+# Rekey the Certificate named 'my-app' and wait up to 10 minutes for it to be issued
+kubectl cert-manager rekey my-app --wait --timeout 10m`))
+)
+
+const (
+	// pollInterval is how often the Certificate is checked with --wait
+	pollInterval = 2 * time.Second
+	// defaultTimeout is the default time to wait for the issuance with --wait
+	defaultTimeout = 5 * time.Minute
+)
+
+// Options is a struct to support rekey command
+type Options struct {
+	CMClient   cmclient.Interface
+	KubeClient kubernetes.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace that the Certificate to be rekeyed resides in.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace string
+
+	// Wait makes the command wait until the Certificate has been issued with
+	// the new private key
+	Wait bool
+	// Timeout is how long to wait with Wait
+	Timeout time.Duration
+	// Yes skips the confirmation if the Secret is mounted by running Pods
+	Yes bool
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		Timeout:   defaultTimeout,
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdRekey returns a cobra command for rekeying a Certificate
+func NewCmdRekey(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "rekey",
+		Short:   "Trigger the issuance of a Certificate with a new private key",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		ValidArgsFunction: completion.CertificateNames(factory, 1),
+	}
+
+	cmd.Flags().BoolVar(&o.Wait, "wait", o.Wait, "Wait until the Certificate has been issued with the new private key")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "The maximum time to wait with --wait")
+	cmd.Flags().BoolVarP(&o.Yes, "yes", "y", o.Yes, "Do not ask for confirmation if the Secret of the Certificate is mounted by running Pods")
+
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) < 1 {
+		return errors.New("the name of the Certificate has to be provided as argument")
+	}
+	if len(args) > 1 {
+		return errors.New("only one argument can be passed in: the name of the Certificate")
+	}
+	if o.Timeout <= 0 {
+		return errors.New("--timeout must be positive")
+	}
+	return nil
+}
+
+// Complete takes the factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory) error {
+	var err error
+
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	o.KubeClient, err = kubernetes.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes rekey command
+func (o *Options) Run(args []string) error {
+	ctx := context.TODO()
+
+	crt, err := o.CMClient.CertmanagerV1alpha2().Certificates(o.Namespace).Get(ctx, args[0], metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error when getting Certificate resource: %v", err)
+	}
+	if apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
+		Type:   cmapi.CertificateConditionIssuing,
+		Status: cmmeta.ConditionTrue,
+	}) {
+		return fmt.Errorf("an issuance of Certificate %s/%s is already in progress, wait for it to complete before rekeying", crt.Namespace, crt.Name)
+	}
+
+	pods, err := o.KubeClient.CoreV1().Pods(crt.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error when listing Pods: %v", err)
+	}
+	if consumers := util.PodsMountingSecret(pods.Items, crt.Spec.SecretName); len(consumers) > 0 && !o.Yes {
+		fmt.Fprintf(o.Out, "Secret %q of Certificate %s/%s is mounted by running Pods, which have to reload the new private key:\n", crt.Spec.SecretName, crt.Namespace, crt.Name)
+		for _, name := range consumers {
+			fmt.Fprintf(o.Out, "  - %s\n", name)
+		}
+		ok, err := o.confirm("Rekey the Certificate?")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("rekeying aborted")
+		}
+	}
+
+	// revisions begin from 1
+	nextRevision := 1
+	if crt.Status.Revision != nil {
+		nextRevision = *crt.Status.Revision + 1
+	}
+	crt = crt.DeepCopy()
+	if crt.Annotations == nil {
+		crt.Annotations = make(map[string]string)
+	}
+	crt.Annotations[cmapi.RotatePrivateKeyAnnotationKey] = strconv.Itoa(nextRevision)
+	updated, err := o.CMClient.CertmanagerV1alpha2().Certificates(crt.Namespace).Update(ctx, crt, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to request a new private key for Certificate %s/%s: %v", crt.Namespace, crt.Name, err)
+	}
+	crt = updated
+
+	triggered := time.Now()
+	apiutil.SetCertificateCondition(crt, cmapi.CertificateConditionIssuing, cmmeta.ConditionTrue, "ManuallyTriggered", "Certificate re-issuance with a new private key manually triggered")
+	if _, err := o.CMClient.CertmanagerV1alpha2().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to trigger issuance of Certificate %s/%s: %v", crt.Namespace, crt.Name, err)
+	}
+	fmt.Fprintf(o.Out, "Manually triggered issuance of Certificate %s/%s with a new private key\n", crt.Namespace, crt.Name)
+
+	if !o.Wait {
+		return nil
+	}
+	return o.waitForIssuance(ctx, crt.Namespace, crt.Name, nextRevision, triggered)
+}
+
+// waitForIssuance waits until the Certificate has been issued with the given
+// revision, or its issuance failed after triggered.
+func (o *Options) waitForIssuance(ctx context.Context, namespace, name string, revision int, triggered time.Time) error {
+	fmt.Fprintf(o.Out, "Waiting for Certificate %s/%s to be issued...\n", namespace, name)
+	var failure error
+	err := wait.PollImmediate(pollInterval, o.Timeout, func() (bool, error) {
+		crt, err := o.CMClient.CertmanagerV1alpha2().Certificates(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if crt.Status.Revision != nil && *crt.Status.Revision >= revision {
+			return true, nil
+		}
+		if crt.Status.LastFailureTime != nil && !crt.Status.LastFailureTime.Time.Before(triggered.Truncate(time.Second)) {
+			failure = errors.New("the issuance failed")
+			if cond := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuing); cond != nil && cond.Message != "" {
+				failure = fmt.Errorf("the issuance failed: %s", cond.Message)
+			}
+			return true, nil
+		}
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for Certificate %s/%s to be issued", namespace, name)
+	}
+	if err != nil {
+		return err
+	}
+	if failure != nil {
+		return failure
+	}
+	fmt.Fprintf(o.Out, "Certificate %s/%s has been issued with a new private key\n", namespace, name)
+	return nil
+}
+
+// confirm asks question and returns whether it was answered with yes
+func (o *Options) confirm(question string) (bool, error) {
+	fmt.Fprintf(o.Out, "%s [y/N]: ", question)
+	answer, err := bufio.NewReader(o.In).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, fmt.Errorf("no answer given, use --yes to rekey without confirmation: %v", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -20,10 +20,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
@@ -31,10 +33,12 @@ import (
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
 
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/completion"
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	ctlclients "github.com/jetstack/cert-manager/pkg/ctl/clients"
 )
 
 var (
@@ -63,6 +67,9 @@ type Options struct {
 	LabelSelector string
 	All           bool
 	AllNamespaces bool
+	// ChunkSize is the number of Certificates requested per page when
+	// listing Certificates with --all or --selector
+	ChunkSize int64
 
 	genericclioptions.IOStreams
 }
@@ -71,6 +78,7 @@ type Options struct {
 func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
 	return &Options{
 		IOStreams: ioStreams,
+		ChunkSize: ctlclients.DefaultChunkSize,
 	}
 }
 
@@ -87,11 +95,13 @@ func NewCmdRenew(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory)
 			cmdutil.CheckErr(o.Validate(cmd, args))
 			cmdutil.CheckErr(o.Run(args))
 		},
+		ValidArgsFunction: completion.CertificateNames(factory, 0),
 	}
 
 	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
 	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, mark Certificates across namespaces for manual renewal. Namespace in current context is ignored even if specified with --namespace.")
 	cmd.Flags().BoolVar(&o.All, "all", o.All, "Renew all Certificates in the given Namespace, or all namespaces with --all-namespaces enabled.")
+	cmd.Flags().Int64Var(&o.ChunkSize, "chunk-size", o.ChunkSize, "Return large lists in chunks rather than all at once with --all or --selector. Pass 0 to disable.")
 
 	return cmd
 }
@@ -114,6 +124,10 @@ func (o *Options) Validate(cmd *cobra.Command, args []string) error {
 		return errors.New("cannot specify --namespace flag in conjunction with --all flag")
 	}
 
+	if o.ChunkSize < 0 {
+		return errors.New("--chunk-size must not be negative")
+	}
+
 	return nil
 }
 
@@ -142,9 +156,18 @@ func (o *Options) Complete(f cmdutil.Factory) error {
 func (o *Options) Run(args []string) error {
 	ctx := context.TODO()
 
+	var crts []cmapi.Certificate
+	if o.All || len(o.LabelSelector) > 0 {
+		var err error
+		crts, err = o.listCertificates(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	nss := []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: o.Namespace}}}
 
-	if o.AllNamespaces {
+	if len(args) > 0 && o.AllNamespaces {
 		kubeClient, err := kubernetes.NewForConfig(o.RESTConfig)
 		if err != nil {
 			return err
@@ -158,28 +181,14 @@ func (o *Options) Run(args []string) error {
 		nss = nsList.Items
 	}
 
-	var crts []cmapi.Certificate
 	for _, ns := range nss {
-		switch {
-		case o.All, len(o.LabelSelector) > 0:
-			crtsList, err := o.CMClient.CertmanagerV1alpha2().Certificates(ns.Name).List(ctx, metav1.ListOptions{
-				LabelSelector: o.LabelSelector,
-			})
+		for _, crtName := range args {
+			crt, err := o.CMClient.CertmanagerV1alpha2().Certificates(ns.Name).Get(ctx, crtName, metav1.GetOptions{})
 			if err != nil {
 				return err
 			}
 
-			crts = append(crts, crtsList.Items...)
-
-		default:
-			for _, crtName := range args {
-				crt, err := o.CMClient.CertmanagerV1alpha2().Certificates(ns.Name).Get(ctx, crtName, metav1.GetOptions{})
-				if err != nil {
-					return err
-				}
-
-				crts = append(crts, *crt)
-			}
+			crts = append(crts, *crt)
 		}
 	}
 
@@ -202,6 +211,35 @@ func (o *Options) Run(args []string) error {
 	return nil
 }
 
+// listCertificates lists the Certificates matching the label selector in the
+// namespace, or in all namespaces, with a single paged LIST.
+func (o *Options) listCertificates(ctx context.Context) ([]cmapi.Certificate, error) {
+	selector, err := labels.Parse(o.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %v", o.LabelSelector, err)
+	}
+	namespace := o.Namespace
+	if o.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+
+	list, err := ctlclients.NewCache(nil, o.CMClient, o.ChunkSize).ListCertificates(ctx, namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Namespace != list[j].Namespace {
+			return list[i].Namespace < list[j].Namespace
+		}
+		return list[i].Name < list[j].Name
+	})
+	crts := make([]cmapi.Certificate, 0, len(list))
+	for _, crt := range list {
+		crts = append(crts, *crt.DeepCopy())
+	}
+	return crts, nil
+}
+
 func (o *Options) renewCertificate(ctx context.Context, crt *cmapi.Certificate) error {
 	apiutil.SetCertificateCondition(crt, cmapi.CertificateConditionIssuing, cmmeta.ConditionTrue, "ManuallyTriggered", "Certificate re-issuance manually triggered")
 	_, err := o.CMClient.CertmanagerV1alpha2().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
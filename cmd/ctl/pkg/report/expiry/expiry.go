@@ -0,0 +1,487 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expiry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/pager"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/multicluster"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	ctlclients "github.com/jetstack/cert-manager/pkg/ctl/clients"
+	"github.com/jetstack/cert-manager/pkg/ctl/output"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Print the expiry dates of Certificates, sorted by the number of days remaining until they expire.
+
+The expiry date of a Certificate is read from its status. Certificates that have not been issued yet are
+listed last. With --include-unmanaged, TLS Secrets that are not managed by cert-manager are listed too,
+and their expiry date is read from the certificate they contain.
+
+With --expiring-within, only the certificates that expire within the given duration, or have already
+expired, are listed.
+
+With --contexts, the certificates of the clusters of several kubeconfig contexts are listed in one report,
+with a CONTEXT column. The namespace of each context is used unless --namespace or --all-namespaces is given.
+Clusters that cannot be reached are reported after the others have been listed.`))
+
+	example = templates.Examples(i18n.T(`
+# Print the expiry dates of the Certificates in the current context namespace
+kubectl cert-manager report expiry
+
+# Print the certificates in all namespaces that expire within 30 days, including unmanaged TLS Secrets
+kubectl cert-manager report expiry -A --include-unmanaged --expiring-within 720h
+
+# Print the expiry dates as JSON
+kubectl cert-manager report expiry -A -o json
+
+# Print the certificates expiring within 30 days in the clusters of the 'prod-eu' and 'prod-us' contexts
+kubectl cert-manager report expiry -A --expiring-within 720h --contexts prod-eu,prod-us`))
+)
+
+const (
+	kindCertificate = "Certificate"
+	kindSecret      = "Secret"
+
+	// soonThreshold is the time before expiry after which certificates are
+	// highlighted. It matches the default renewBefore of Certificates.
+	soonThreshold = cmapi.DefaultRenewBefore
+)
+
+// Options is a struct to support report expiry command
+type Options struct {
+	CMClient   cmclient.Interface
+	KubeClient kubernetes.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace to report the expiry dates of.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace     string
+	AllNamespaces bool
+
+	// IncludeUnmanaged makes the command also list TLS Secrets that are not
+	// managed by cert-manager
+	IncludeUnmanaged bool
+	// ExpiringWithin limits the report to certificates expiring within the
+	// given duration, if non-zero
+	ExpiringWithin time.Duration
+	// Output is the output format, either empty for a table or "json"
+	Output string
+	// ChunkSize is the number of resources requested per page when listing
+	// Certificates and Secrets
+	ChunkSize int64
+
+	// Contexts are the kubeconfig contexts of the clusters to report on, if
+	// more than the cluster of the current context. The clients of every
+	// context are created from ConfigFlags when the command is run.
+	Contexts    []string
+	ConfigFlags *genericclioptions.ConfigFlags
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams, configFlags *genericclioptions.ConfigFlags) *Options {
+	return &Options{
+		IOStreams:   ioStreams,
+		ChunkSize:   ctlclients.DefaultChunkSize,
+		ConfigFlags: configFlags,
+	}
+}
+
+// NewCmdReportExpiry returns a cobra command for report expiry
+func NewCmdReportExpiry(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory, configFlags *genericclioptions.ConfigFlags) *cobra.Command {
+	o := NewOptions(ioStreams, configFlags)
+	cmd := &cobra.Command{
+		Use:     "expiry",
+		Short:   "Print the expiry dates of certificates, sorted by the days remaining",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, report the certificates of all namespaces. Namespace in current context is ignored even if specified with --namespace.")
+	cmd.Flags().BoolVar(&o.IncludeUnmanaged, "include-unmanaged", o.IncludeUnmanaged, "Also list TLS Secrets that are not managed by cert-manager")
+	cmd.Flags().DurationVar(&o.ExpiringWithin, "expiring-within", o.ExpiringWithin, "Only list certificates that expire within this duration, e.g. 720h. By default all certificates are listed")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format. Only 'json' is supported, which prints a JSON array instead of a table")
+	cmd.Flags().Int64Var(&o.ChunkSize, "chunk-size", o.ChunkSize, "Return large lists in chunks rather than all at once. Pass 0 to disable.")
+	multicluster.AddContextsFlag(cmd.Flags(), &o.Contexts)
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("no arguments are accepted")
+	}
+	if o.ExpiringWithin < 0 {
+		return errors.New("--expiring-within must not be negative")
+	}
+	if o.Output != "" && o.Output != "json" {
+		return fmt.Errorf("unsupported output format %q, only 'json' is supported", o.Output)
+	}
+	if o.ChunkSize < 0 {
+		return errors.New("--chunk-size must not be negative")
+	}
+	return multicluster.Validate(o.ConfigFlags, o.Contexts)
+}
+
+// Complete takes the factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory) error {
+	if len(o.Contexts) > 0 {
+		// The clients of every context are created by Run
+		return nil
+	}
+
+	var err error
+
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	o.KubeClient, err = kubernetes.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes report expiry command
+func (o *Options) Run() error {
+	ctx := context.TODO()
+
+	if len(o.Contexts) == 0 {
+		entries, err := o.collect(ctx)
+		if err != nil {
+			return err
+		}
+		return o.print(entries)
+	}
+
+	// A cluster that cannot be reached must not hide the certificates of the
+	// others, so its error is only returned once the report is printed.
+	var entries []*entry
+	var errs []error
+	for _, kubeContext := range o.Contexts {
+		co := *o
+		co.Contexts = nil
+		if err := co.Complete(multicluster.FactoryForContext(o.ConfigFlags, kubeContext)); err != nil {
+			errs = append(errs, fmt.Errorf("context %q: %v", kubeContext, err))
+			continue
+		}
+		contextEntries, err := co.collect(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("context %q: %v", kubeContext, err))
+			continue
+		}
+		for _, e := range contextEntries {
+			e.Context = kubeContext
+		}
+		entries = append(entries, contextEntries...)
+	}
+	sortEntries(entries)
+
+	if err := o.print(entries); err != nil {
+		return err
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// collect returns the expiry dates of the certificates in the cluster of
+// the clients of o.
+func (o *Options) collect(ctx context.Context) ([]*entry, error) {
+	namespace := o.Namespace
+	if o.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+
+	cache := ctlclients.NewCache(o.KubeClient, o.CMClient, o.ChunkSize)
+	list, err := cache.ListCertificates(ctx, namespace, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	crts := make([]cmapi.Certificate, 0, len(list))
+	for _, crt := range list {
+		crts = append(crts, *crt)
+	}
+
+	var secrets []corev1.Secret
+	if o.IncludeUnmanaged {
+		p := pager.New(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return o.KubeClient.CoreV1().Secrets(namespace).List(ctx, opts)
+		})
+		p.PageSize = o.ChunkSize
+		list, err := p.List(ctx, metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("type", string(corev1.SecretTypeTLS)).String(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error when listing Secrets: %v", err)
+		}
+		err = meta.EachListItem(list, func(obj runtime.Object) error {
+			secrets = append(secrets, *obj.(*corev1.Secret))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return buildReport(crts, secrets, time.Now(), o.ExpiringWithin), nil
+}
+
+// print writes the report to the output in the format of o.
+func (o *Options) print(entries []*entry) error {
+	if o.Output == "json" {
+		return printJSON(o.Out, entries)
+	}
+
+	if len(entries) == 0 {
+		if o.AllNamespaces || len(o.Contexts) > 0 {
+			fmt.Fprintln(o.ErrOut, "No certificates found.")
+		} else {
+			fmt.Fprintf(o.ErrOut, "No certificates found in %s namespace.\n", o.Namespace)
+		}
+		return nil
+	}
+
+	fmt.Fprint(o.Out, formatTable(entries, time.Now()))
+	return nil
+}
+
+// entry is the expiry date of a single Certificate or unmanaged Secret
+type entry struct {
+	// Context is the kubeconfig context of the cluster, if the report
+	// covers several clusters
+	Context    string `json:"context,omitempty"`
+	Namespace  string `json:"namespace"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	SecretName string `json:"secretName"`
+	Issuer     string `json:"issuer,omitempty"`
+	// NotAfter is nil if the expiry date is unknown, e.g. because the
+	// Certificate has not been issued yet
+	NotAfter *time.Time `json:"notAfter"`
+	// DaysRemaining is the number of whole days until NotAfter, negative if
+	// the certificate has expired. It is nil if NotAfter is nil.
+	DaysRemaining *int `json:"daysRemaining"`
+	// Error is the reason the expiry date of an unmanaged Secret could not
+	// be read, if any
+	Error string `json:"error,omitempty"`
+}
+
+// buildReport returns the expiry dates of the Certificates and of the
+// Secrets that are not managed by cert-manager, sorted by expiry date with
+// unknown expiry dates last. If within is non-zero, only entries expiring
+// within that duration of now are returned.
+func buildReport(crts []cmapi.Certificate, secrets []corev1.Secret, now time.Time, within time.Duration) []*entry {
+	var entries []*entry
+
+	for _, crt := range crts {
+		e := &entry{
+			Namespace:  crt.Namespace,
+			Kind:       kindCertificate,
+			Name:       crt.Name,
+			SecretName: crt.Spec.SecretName,
+			Issuer:     issuerName(crt.Spec.IssuerRef),
+		}
+		if crt.Status.NotAfter != nil {
+			e.setNotAfter(crt.Status.NotAfter.Time, now)
+		}
+		entries = append(entries, e)
+	}
+
+	for _, secret := range secrets {
+		if _, ok := secret.Annotations[cmapi.CertificateNameKey]; ok {
+			continue
+		}
+		e := &entry{
+			Namespace:  secret.Namespace,
+			Kind:       kindSecret,
+			Name:       secret.Name,
+			SecretName: secret.Name,
+		}
+		cert, err := pki.DecodeX509CertificateBytes(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			e.Error = err.Error()
+		} else {
+			e.setNotAfter(cert.NotAfter, now)
+		}
+		entries = append(entries, e)
+	}
+
+	if within > 0 {
+		deadline := now.Add(within)
+		var filtered []*entry
+		for _, e := range entries {
+			if e.NotAfter != nil && !e.NotAfter.After(deadline) {
+				filtered = append(filtered, e)
+			}
+		}
+		entries = filtered
+	}
+
+	sortEntries(entries)
+	return entries
+}
+
+// sortEntries sorts entries by expiry date with unknown expiry dates last,
+// and then by context, namespace and name.
+func sortEntries(entries []*entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch {
+		case a.NotAfter == nil || b.NotAfter == nil:
+			if (a.NotAfter == nil) != (b.NotAfter == nil) {
+				return b.NotAfter == nil
+			}
+		case !a.NotAfter.Equal(*b.NotAfter):
+			return a.NotAfter.Before(*b.NotAfter)
+		}
+		if a.Context != b.Context {
+			return a.Context < b.Context
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+}
+
+func (e *entry) setNotAfter(notAfter, now time.Time) {
+	notAfter = notAfter.UTC()
+	days := int(math.Floor(notAfter.Sub(now).Hours() / 24))
+	e.NotAfter = &notAfter
+	e.DaysRemaining = &days
+}
+
+// issuerName formats ref as '<kind>/<name>', including the group of issuers
+// of third party API groups.
+func issuerName(ref cmmeta.ObjectReference) string {
+	kind := ref.Kind
+	if kind == "" {
+		kind = cmapi.IssuerKind
+	}
+	if ref.Group != "" && ref.Group != "cert-manager.io" {
+		kind += "." + ref.Group
+	}
+	return kind + "/" + ref.Name
+}
+
+// formatTable renders entries as a table. Expired certificates are
+// highlighted in red, and certificates expiring soon in yellow. The table
+// has a CONTEXT column if the entries are of several clusters.
+func formatTable(entries []*entry, now time.Time) string {
+	withContext := false
+	for _, e := range entries {
+		if e.Context != "" {
+			withContext = true
+			break
+		}
+	}
+	headers := []string{"NAMESPACE", "KIND", "NAME", "SECRET", "ISSUER", "NOT AFTER", "DAYS LEFT"}
+	if withContext {
+		headers = append([]string{"CONTEXT"}, headers...)
+	}
+	table := output.NewTable(headers...)
+	for _, e := range entries {
+		issuer := e.Issuer
+		if issuer == "" {
+			issuer = "<none>"
+		}
+		notAfter, days := "<unknown>", "<unknown>"
+		if e.Error != "" {
+			notAfter = output.Red("invalid: " + e.Error)
+		}
+		if e.NotAfter != nil {
+			notAfter = e.NotAfter.Format(time.RFC3339)
+			days = strconv.Itoa(*e.DaysRemaining)
+			switch {
+			case !e.NotAfter.After(now):
+				days = output.Red(days)
+			case e.NotAfter.Sub(now) < soonThreshold:
+				days = output.Yellow(days)
+			}
+		}
+		row := []string{e.Namespace, e.Kind, e.Name, e.SecretName, issuer, notAfter, days}
+		if withContext {
+			row = append([]string{e.Context}, row...)
+		}
+		table.AddRow(row...)
+	}
+	return table.String()
+}
+
+// JSONOutput returns a value of the type printed with --output json, from
+// which the schema of the output is generated.
+func JSONOutput() interface{} {
+	return []*entry{}
+}
+
+func printJSON(out io.Writer, entries []*entry) error {
+	if entries == nil {
+		entries = []*entry{}
+	}
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, string(b))
+	return nil
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expiry
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestBuildReport(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	newCrt := func(namespace, name string, notAfter *time.Time) cmapi.Certificate {
+		crt := cmapi.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: cmapi.CertificateSpec{
+				SecretName: name + "-tls",
+				IssuerRef:  cmmeta.ObjectReference{Name: "ca", Kind: cmapi.ClusterIssuerKind},
+			},
+		}
+		if notAfter != nil {
+			crt.Status.NotAfter = &metav1.Time{Time: *notAfter}
+		}
+		return crt
+	}
+	newSecret := func(namespace, name string, managed bool, certPEM []byte) corev1.Secret {
+		secret := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Type:       corev1.SecretTypeTLS,
+			Data:       map[string][]byte{corev1.TLSCertKey: certPEM},
+		}
+		if managed {
+			secret.Annotations = map[string]string{cmapi.CertificateNameKey: name}
+		}
+		return secret
+	}
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	days := func(d int) *int {
+		return &d
+	}
+
+	tests := map[string]struct {
+		crts    []cmapi.Certificate
+		secrets []corev1.Secret
+		within  time.Duration
+		exp     []*entry
+	}{
+		"no certificates": {},
+		"certificates are sorted by expiry with unknown expiry last": {
+			crts: []cmapi.Certificate{
+				newCrt("b", "pending", nil),
+				newCrt("b", "later", at(90*24*time.Hour)),
+				newCrt("a", "expired", at(-36*time.Hour)),
+				newCrt("a", "sooner", at(10*24*time.Hour+time.Hour)),
+			},
+			exp: []*entry{
+				{Namespace: "a", Kind: kindCertificate, Name: "expired", SecretName: "expired-tls", Issuer: "ClusterIssuer/ca", NotAfter: at(-36 * time.Hour), DaysRemaining: days(-2)},
+				{Namespace: "a", Kind: kindCertificate, Name: "sooner", SecretName: "sooner-tls", Issuer: "ClusterIssuer/ca", NotAfter: at(10*24*time.Hour + time.Hour), DaysRemaining: days(10)},
+				{Namespace: "b", Kind: kindCertificate, Name: "later", SecretName: "later-tls", Issuer: "ClusterIssuer/ca", NotAfter: at(90 * 24 * time.Hour), DaysRemaining: days(90)},
+				{Namespace: "b", Kind: kindCertificate, Name: "pending", SecretName: "pending-tls", Issuer: "ClusterIssuer/ca"},
+			},
+		},
+		"expiring within filters out later and unknown expiry dates": {
+			crts: []cmapi.Certificate{
+				newCrt("a", "pending", nil),
+				newCrt("a", "later", at(90*24*time.Hour)),
+				newCrt("a", "expired", at(-time.Hour)),
+				newCrt("a", "sooner", at(30*24*time.Hour)),
+			},
+			within: 30 * 24 * time.Hour,
+			exp: []*entry{
+				{Namespace: "a", Kind: kindCertificate, Name: "expired", SecretName: "expired-tls", Issuer: "ClusterIssuer/ca", NotAfter: at(-time.Hour), DaysRemaining: days(-1)},
+				{Namespace: "a", Kind: kindCertificate, Name: "sooner", SecretName: "sooner-tls", Issuer: "ClusterIssuer/ca", NotAfter: at(30 * 24 * time.Hour), DaysRemaining: days(30)},
+			},
+		},
+		"only unmanaged secrets are listed": {
+			secrets: []corev1.Secret{
+				newSecret("a", "managed", true, mustCertificatePEM(t, *at(time.Hour))),
+				newSecret("a", "unmanaged", false, mustCertificatePEM(t, *at(5 * 24 * time.Hour))),
+				newSecret("a", "invalid", false, []byte("not a certificate")),
+			},
+			exp: []*entry{
+				{Namespace: "a", Kind: kindSecret, Name: "unmanaged", SecretName: "unmanaged", NotAfter: at(5 * 24 * time.Hour), DaysRemaining: days(5)},
+				{Namespace: "a", Kind: kindSecret, Name: "invalid", SecretName: "invalid", Error: "error decoding certificate PEM block"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries := buildReport(test.crts, test.secrets, now, test.within)
+			if !reflect.DeepEqual(entries, test.exp) {
+				var got, exp bytes.Buffer
+				printJSON(&got, entries)
+				printJSON(&exp, test.exp)
+				t.Errorf("unexpected report, exp=%s got=%s", exp.String(), got.String())
+			}
+		})
+	}
+}
+
+func TestFormatTable(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := now.Add(90 * 24 * time.Hour)
+	days := 90
+
+	tests := map[string]struct {
+		entries []*entry
+		exp     string
+	}{
+		"entries of a single cluster have no context column": {
+			entries: []*entry{
+				{Namespace: "a", Kind: kindCertificate, Name: "crt", SecretName: "crt-tls", Issuer: "Issuer/ca", NotAfter: &notAfter, DaysRemaining: &days},
+				{Namespace: "a", Kind: kindSecret, Name: "unmanaged", SecretName: "unmanaged"},
+			},
+			exp: `NAMESPACE  KIND         NAME       SECRET     ISSUER     NOT AFTER             DAYS LEFT
+a          Certificate  crt        crt-tls    Issuer/ca  2020-08-30T00:00:00Z  90
+a          Secret       unmanaged  unmanaged  <none>     <unknown>             <unknown>
+`,
+		},
+		"entries of several clusters have a context column": {
+			entries: []*entry{
+				{Context: "prod-eu", Namespace: "a", Kind: kindCertificate, Name: "crt", SecretName: "crt-tls", Issuer: "Issuer/ca", NotAfter: &notAfter, DaysRemaining: &days},
+				{Context: "prod-us", Namespace: "b", Kind: kindCertificate, Name: "crt", SecretName: "crt-tls", Issuer: "Issuer/ca"},
+			},
+			exp: `CONTEXT  NAMESPACE  KIND         NAME  SECRET   ISSUER     NOT AFTER             DAYS LEFT
+prod-eu  a          Certificate  crt   crt-tls  Issuer/ca  2020-08-30T00:00:00Z  90
+prod-us  b          Certificate  crt   crt-tls  Issuer/ca  <unknown>             <unknown>
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := formatTable(test.entries, now); got != test.exp {
+				t.Errorf("unexpected table, exp=\n%s\ngot=\n%s", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestIssuerName(t *testing.T) {
+	tests := map[string]struct {
+		ref cmmeta.ObjectReference
+		exp string
+	}{
+		"kind defaults to Issuer": {
+			ref: cmmeta.ObjectReference{Name: "ca"},
+			exp: "Issuer/ca",
+		},
+		"cert-manager group is omitted": {
+			ref: cmmeta.ObjectReference{Name: "ca", Kind: "ClusterIssuer", Group: "cert-manager.io"},
+			exp: "ClusterIssuer/ca",
+		},
+		"external group is included": {
+			ref: cmmeta.ObjectReference{Name: "pca", Kind: "AWSPCAIssuer", Group: "awspca.cert-manager.io"},
+			exp: "AWSPCAIssuer.awspca.cert-manager.io/pca",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := issuerName(test.ref); got != test.exp {
+				t.Errorf("expected %q, got %q", test.exp, got)
+			}
+		})
+	}
+}
+
+func mustCertificatePEM(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
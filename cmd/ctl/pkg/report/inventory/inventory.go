@@ -0,0 +1,459 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/util"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	ctlclients "github.com/jetstack/cert-manager/pkg/ctl/clients"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Print a machine-readable inventory of every Certificate managed by cert-manager, e.g. to hand to a security team
+or to feed into an asset inventory.
+
+For every Certificate, the inventory lists the subject and SANs, serial number, SHA-256 fingerprint, key and
+signature algorithms and validity of the certificate stored in its Secret, the issuer it is issued by and the
+SHA-256 fingerprints of the certificates of the issuer chain stored alongside it. The workloads consuming the
+certificate are the controllers, e.g. Deployments or StatefulSets, of the running Pods mounting the Secret.
+
+Certificates whose Secret is missing or does not hold a valid certificate are listed with the SANs of their spec
+and the reason in the error field.`))
+
+	example = templates.Examples(i18n.T(`
+# Print the inventory of the Certificates in the current context namespace as JSON
+kubectl cert-manager report inventory
+
+# Write the inventory of the Certificates in all namespaces to a CSV file
+kubectl cert-manager report inventory -A -o csv > inventory.csv`))
+)
+
+const (
+	outputJSON = "json"
+	outputCSV  = "csv"
+
+	// listSeparator separates the values of list fields in CSV output
+	listSeparator = ";"
+)
+
+// Options is a struct to support report inventory command
+type Options struct {
+	CMClient   cmclient.Interface
+	KubeClient kubernetes.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace to report the inventory of.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace     string
+	AllNamespaces bool
+
+	// Output is the output format, either "json" or "csv"
+	Output string
+	// ChunkSize is the number of resources requested per page when listing
+	// Certificates and Secrets
+	ChunkSize int64
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+		Output:    outputJSON,
+		ChunkSize: ctlclients.DefaultChunkSize,
+	}
+}
+
+// NewCmdReportInventory returns a cobra command for report inventory
+func NewCmdReportInventory(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "inventory",
+		Short:   "Print a machine-readable inventory of all certificates, their issuers and consuming workloads",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, report the certificates of all namespaces. Namespace in current context is ignored even if specified with --namespace.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format. One of: json|csv")
+	cmd.Flags().Int64Var(&o.ChunkSize, "chunk-size", o.ChunkSize, "Return large lists in chunks rather than all at once. Pass 0 to disable.")
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("no arguments are accepted")
+	}
+	if o.Output != outputJSON && o.Output != outputCSV {
+		return fmt.Errorf("unsupported output format %q, must be one of 'json' or 'csv'", o.Output)
+	}
+	if o.ChunkSize < 0 {
+		return errors.New("--chunk-size must not be negative")
+	}
+	return nil
+}
+
+// Complete takes the factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory) error {
+	var err error
+
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	o.KubeClient, err = kubernetes.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes report inventory command
+func (o *Options) Run() error {
+	ctx := context.TODO()
+
+	namespace := o.Namespace
+	if o.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+
+	cache := ctlclients.NewCache(o.KubeClient, o.CMClient, o.ChunkSize)
+	crts, err := cache.ListCertificates(ctx, namespace, labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	secrets := make(map[string]*corev1.Secret)
+	for _, crt := range crts {
+		secret, err := cache.GetSecret(ctx, crt.Namespace, crt.Spec.SecretName)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		secrets[crt.Namespace+"/"+crt.Spec.SecretName] = secret
+	}
+
+	pods, err := o.KubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error when listing Pods: %v", err)
+	}
+	replicaSets, err := o.KubeClient.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error when listing ReplicaSets: %v", err)
+	}
+
+	entries := buildInventory(crts, secrets, pods.Items, replicaSets.Items)
+	if o.Output == outputCSV {
+		return printCSV(o.Out, entries)
+	}
+	return printJSON(o.Out, entries)
+}
+
+// entry is the inventory of a single Certificate
+type entry struct {
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	SecretName string `json:"secretName"`
+	Issuer     string `json:"issuer"`
+	Ready      bool   `json:"ready"`
+
+	CommonName     string   `json:"commonName,omitempty"`
+	DNSNames       []string `json:"dnsNames,omitempty"`
+	IPAddresses    []string `json:"ipAddresses,omitempty"`
+	URIs           []string `json:"uris,omitempty"`
+	EmailAddresses []string `json:"emailAddresses,omitempty"`
+
+	SerialNumber       string     `json:"serialNumber,omitempty"`
+	Fingerprint        string     `json:"sha256Fingerprint,omitempty"`
+	KeyAlgorithm       string     `json:"keyAlgorithm,omitempty"`
+	SignatureAlgorithm string     `json:"signatureAlgorithm,omitempty"`
+	NotBefore          *time.Time `json:"notBefore,omitempty"`
+	NotAfter           *time.Time `json:"notAfter,omitempty"`
+
+	// IssuerChain are the certificates of the chain of the issuer, from the
+	// certificate that signed the leaf certificate up to the root if known
+	IssuerChain []chainEntry `json:"issuerChain,omitempty"`
+	// Workloads are the workloads of the running Pods mounting the Secret
+	Workloads []string `json:"workloads,omitempty"`
+
+	// Error is the reason the certificate of the Secret could not be read,
+	// if any
+	Error string `json:"error,omitempty"`
+}
+
+// chainEntry is a certificate of the issuer chain of a Certificate
+type chainEntry struct {
+	Subject     string `json:"subject"`
+	Fingerprint string `json:"sha256Fingerprint"`
+}
+
+// buildInventory returns the inventory of the Certificates, sorted by
+// namespace and name. secrets holds the Secrets of the Certificates by
+// '<namespace>/<name>', and pods and replicaSets those of all namespaces of
+// the Certificates.
+func buildInventory(crts []*cmapi.Certificate, secrets map[string]*corev1.Secret, pods []corev1.Pod, replicaSets []appsv1.ReplicaSet) []*entry {
+	podsByNamespace := make(map[string][]corev1.Pod)
+	for _, pod := range pods {
+		podsByNamespace[pod.Namespace] = append(podsByNamespace[pod.Namespace], pod)
+	}
+	replicaSetsByNamespace := make(map[string][]appsv1.ReplicaSet)
+	for _, rs := range replicaSets {
+		replicaSetsByNamespace[rs.Namespace] = append(replicaSetsByNamespace[rs.Namespace], rs)
+	}
+
+	entries := make([]*entry, 0, len(crts))
+	for _, crt := range crts {
+		e := &entry{
+			Namespace:  crt.Namespace,
+			Name:       crt.Name,
+			SecretName: crt.Spec.SecretName,
+			Issuer:     issuerName(crt.Spec.IssuerRef),
+			Ready: apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
+				Type:   cmapi.CertificateConditionReady,
+				Status: cmmeta.ConditionTrue,
+			}),
+			Workloads: util.WorkloadsMountingSecret(podsByNamespace[crt.Namespace], replicaSetsByNamespace[crt.Namespace], crt.Spec.SecretName),
+		}
+
+		secret, ok := secrets[crt.Namespace+"/"+crt.Spec.SecretName]
+		if !ok {
+			e.setSpec(crt)
+			e.Error = fmt.Sprintf("Secret %q not found", crt.Spec.SecretName)
+			entries = append(entries, e)
+			continue
+		}
+		chain, err := pki.DecodeX509CertificateChainBytes(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			e.setSpec(crt)
+			e.Error = err.Error()
+			entries = append(entries, e)
+			continue
+		}
+		e.setCertificate(chain[0])
+		e.setIssuerChain(chain[1:], secret.Data[cmmeta.TLSCAKey])
+		entries = append(entries, e)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Namespace != entries[j].Namespace {
+			return entries[i].Namespace < entries[j].Namespace
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// setSpec sets the subject and SANs requested by the spec of the
+// Certificate, for Certificates without a valid certificate.
+func (e *entry) setSpec(crt *cmapi.Certificate) {
+	e.CommonName = crt.Spec.CommonName
+	e.DNSNames = crt.Spec.DNSNames
+	e.IPAddresses = crt.Spec.IPAddresses
+	e.URIs = crt.Spec.URISANs
+	e.EmailAddresses = crt.Spec.EmailSANs
+}
+
+func (e *entry) setCertificate(cert *x509.Certificate) {
+	e.CommonName = cert.Subject.CommonName
+	e.DNSNames = cert.DNSNames
+	for _, ip := range cert.IPAddresses {
+		e.IPAddresses = append(e.IPAddresses, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		e.URIs = append(e.URIs, uri.String())
+	}
+	e.EmailAddresses = cert.EmailAddresses
+
+	e.SerialNumber = cert.SerialNumber.Text(16)
+	e.Fingerprint = fingerprint(cert)
+	e.KeyAlgorithm = keyAlgorithm(cert)
+	e.SignatureAlgorithm = cert.SignatureAlgorithm.String()
+	notBefore, notAfter := cert.NotBefore.UTC(), cert.NotAfter.UTC()
+	e.NotBefore = &notBefore
+	e.NotAfter = &notAfter
+}
+
+// setIssuerChain sets the issuer chain from the intermediate certificates
+// bundled with the leaf certificate, followed by the certificates of the CA
+// that are not part of the bundle. An invalid CA is ignored, as not all
+// issuers store the CA.
+func (e *entry) setIssuerChain(intermediates []*x509.Certificate, caPEM []byte) {
+	seen := make(map[string]bool)
+	add := func(cert *x509.Certificate) {
+		fp := fingerprint(cert)
+		if seen[fp] {
+			return
+		}
+		seen[fp] = true
+		e.IssuerChain = append(e.IssuerChain, chainEntry{Subject: cert.Subject.String(), Fingerprint: fp})
+	}
+
+	for _, cert := range intermediates {
+		add(cert)
+	}
+	if len(caPEM) == 0 {
+		return
+	}
+	cas, err := pki.DecodeX509CertificateChainBytes(caPEM)
+	if err != nil {
+		return
+	}
+	for _, cert := range cas {
+		add(cert)
+	}
+}
+
+// fingerprint returns the hex encoded SHA-256 fingerprint of cert
+func fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// keyAlgorithm returns the algorithm and size of the public key of cert,
+// e.g. 'RSA-2048' or 'ECDSA-P-256'.
+func keyAlgorithm(cert *x509.Certificate) string {
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA-%d", pub.N.BitLen())
+	case *ecdsa.PublicKey:
+		return "ECDSA-" + pub.Curve.Params().Name
+	}
+	return cert.PublicKeyAlgorithm.String()
+}
+
+// issuerName formats ref as '<kind>/<name>', including the group of issuers
+// of third party API groups.
+func issuerName(ref cmmeta.ObjectReference) string {
+	kind := ref.Kind
+	if kind == "" {
+		kind = cmapi.IssuerKind
+	}
+	if ref.Group != "" && ref.Group != "cert-manager.io" {
+		kind += "." + ref.Group
+	}
+	return kind + "/" + ref.Name
+}
+
+// JSONOutput returns a value of the type printed with --output json, from
+// which the schema of the output is generated.
+func JSONOutput() interface{} {
+	return []*entry{}
+}
+
+func printJSON(out io.Writer, entries []*entry) error {
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, string(b))
+	return nil
+}
+
+// csvHeader are the columns of the CSV output. Lists are joined with
+// listSeparator, and the issuer chain is given as its fingerprints.
+var csvHeader = []string{
+	"namespace", "name", "secretName", "issuer", "ready",
+	"commonName", "dnsNames", "ipAddresses", "uris", "emailAddresses",
+	"serialNumber", "sha256Fingerprint", "keyAlgorithm", "signatureAlgorithm", "notBefore", "notAfter",
+	"issuerChainFingerprints", "workloads", "error",
+}
+
+func printCSV(out io.Writer, entries []*entry) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		var chain []string
+		for _, c := range e.IssuerChain {
+			chain = append(chain, c.Fingerprint)
+		}
+		row := []string{
+			e.Namespace, e.Name, e.SecretName, e.Issuer, strconv.FormatBool(e.Ready),
+			e.CommonName, strings.Join(e.DNSNames, listSeparator), strings.Join(e.IPAddresses, listSeparator),
+			strings.Join(e.URIs, listSeparator), strings.Join(e.EmailAddresses, listSeparator),
+			e.SerialNumber, e.Fingerprint, e.KeyAlgorithm, e.SignatureAlgorithm, formatTime(e.NotBefore), formatTime(e.NotAfter),
+			strings.Join(chain, listSeparator), strings.Join(e.Workloads, listSeparator), e.Error,
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestBuildInventory(t *testing.T) {
+	caKey, caCert, caPEM := mustCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Example CA"},
+		NotBefore:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:              time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+	_, _, leafPEM := mustCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(255),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com", "www.example.com"},
+		NotBefore:    time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC),
+	}, caCert, caKey)
+
+	crt := func(namespace, name string) *cmapi.Certificate {
+		return &cmapi.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: cmapi.CertificateSpec{
+				SecretName: name + "-tls",
+				DNSNames:   []string{name + ".example.com"},
+				IssuerRef:  cmmeta.ObjectReference{Name: "ca", Kind: cmapi.ClusterIssuerKind},
+			},
+			Status: cmapi.CertificateStatus{Conditions: []cmapi.CertificateCondition{
+				{Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionTrue},
+			}},
+		}
+	}
+	secrets := map[string]*corev1.Secret{
+		"default/web-tls": {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-tls"},
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       leafPEM,
+				cmmeta.TLSCAKey:         caPEM,
+				corev1.TLSPrivateKeyKey: []byte("key"),
+			},
+		},
+	}
+	controller := true
+	pods := []corev1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "web-5d4f8-a",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-5d4f8", Controller: &controller}},
+		},
+		Spec: corev1.PodSpec{Volumes: []corev1.Volume{{Name: "tls", VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: "web-tls"},
+		}}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}}
+	replicaSets := []appsv1.ReplicaSet{{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "web-5d4f8",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "web", Controller: &controller}},
+		},
+	}}
+
+	entries := buildInventory([]*cmapi.Certificate{crt("other", "api"), crt("default", "web")}, secrets, pods, replicaSets)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	web := entries[0]
+	if web.Name != "web" {
+		t.Fatalf("expected entries to be sorted by namespace, got %q first", web.Name)
+	}
+	caSum := sha256.Sum256(caCert.Raw)
+	notAfter := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+	expWeb := &entry{
+		Namespace:          "default",
+		Name:               "web",
+		SecretName:         "web-tls",
+		Issuer:             "ClusterIssuer/ca",
+		Ready:              true,
+		CommonName:         "example.com",
+		DNSNames:           []string{"example.com", "www.example.com"},
+		SerialNumber:       "ff",
+		Fingerprint:        web.Fingerprint,
+		KeyAlgorithm:       "ECDSA-P-256",
+		SignatureAlgorithm: "ECDSA-SHA256",
+		NotBefore:          web.NotBefore,
+		NotAfter:           &notAfter,
+		IssuerChain:        []chainEntry{{Subject: "CN=Example CA", Fingerprint: hex.EncodeToString(caSum[:])}},
+		Workloads:          []string{"Deployment/web"},
+	}
+	if !reflect.DeepEqual(web, expWeb) {
+		t.Errorf("unexpected entry for Certificate with Secret:\nexpected %+v\ngot      %+v", expWeb, web)
+	}
+	if len(web.Fingerprint) != 64 {
+		t.Errorf("expected a hex encoded SHA-256 fingerprint, got %q", web.Fingerprint)
+	}
+
+	api := entries[1]
+	if api.Error == "" || api.Fingerprint != "" {
+		t.Errorf("expected Certificate without Secret to have an error and no fingerprint, got %+v", api)
+	}
+	if exp := []string{"api.example.com"}; !reflect.DeepEqual(api.DNSNames, exp) {
+		t.Errorf("expected SANs of the spec %v, got %v", exp, api.DNSNames)
+	}
+}
+
+func TestPrintCSV(t *testing.T) {
+	notAfter := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+	entries := []*entry{{
+		Namespace:   "default",
+		Name:        "web",
+		SecretName:  "web-tls",
+		Issuer:      "ClusterIssuer/ca",
+		Ready:       true,
+		DNSNames:    []string{"example.com", "www.example.com"},
+		NotAfter:    &notAfter,
+		IssuerChain: []chainEntry{{Subject: "CN=Example CA", Fingerprint: "abc"}},
+		Workloads:   []string{"Deployment/web", "StatefulSet/db"},
+	}}
+
+	var out bytes.Buffer
+	if err := printCSV(&out, entries); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected a header and 1 row, got %d records", len(records))
+	}
+
+	row := make(map[string]string)
+	for i, column := range records[0] {
+		row[column] = records[1][i]
+	}
+	exp := map[string]string{
+		"dnsNames":                "example.com;www.example.com",
+		"ready":                   "true",
+		"notBefore":               "",
+		"notAfter":                "2020-09-01T00:00:00Z",
+		"issuerChainFingerprints": "abc",
+		"workloads":               "Deployment/web;StatefulSet/db",
+	}
+	for column, value := range exp {
+		if row[column] != value {
+			t.Errorf("expected column %q to be %q, got %q", column, value, row[column])
+		}
+	}
+}
+
+// mustCertificate creates a certificate from template signed by parent and
+// parentKey, or self-signed if parent is nil.
+func mustCertificate(t *testing.T, template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*ecdsa.PrivateKey, *x509.Certificate, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/report/expiry"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/report/inventory"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/report/usage"
+)
+
+func NewCmdReport(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory, configFlags *genericclioptions.ConfigFlags) *cobra.Command {
+	cmds := &cobra.Command{
+		Use:   "report",
+		Short: "Print reports about the usage of cert-manager",
+		Long:  `Print reports about the usage of cert-manager, e.g. the certificates issued per namespace and team, the certificates expiring soon, or an inventory of all certificates`,
+	}
+
+	cmds.AddCommand(usage.NewCmdReportUsage(ioStreams, factory))
+	cmds.AddCommand(expiry.NewCmdReportExpiry(ioStreams, factory, configFlags))
+	cmds.AddCommand(inventory.NewCmdReportInventory(ioStreams, factory))
+
+	return cmds
+}
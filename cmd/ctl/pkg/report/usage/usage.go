@@ -0,0 +1,284 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	"github.com/jetstack/cert-manager/pkg/ctl/output"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Print the number of CertificateRequests, issued certificates and ACME Orders per namespace, team and issuer.
+
+Each CertificateRequest is a request to the CA of its issuer, and each ACME Order consumes the quota of the
+ACME account. Requests are attributed to the team set in their 'cert-manager.io/team' label, which they inherit
+from their Certificate. The same counters are exposed by the controller as the
+'certmanager_certificaterequest_sign_call_count' and 'certmanager_certificate_issuance_count' metrics.
+
+Only CertificateRequests that still exist in the cluster are counted.`))
+
+	example = templates.Examples(i18n.T(`
+# Print the usage of the current context namespace
+kubectl cert-manager report usage
+
+# Print the usage of all namespaces in the last 7 days
+kubectl cert-manager report usage --all-namespaces --since 168h
+
+# Attribute requests to the team set in the 'example.com/cost-center' label
+kubectl cert-manager report usage -A --team-label example.com/cost-center`))
+)
+
+// Options is a struct to support report usage command
+type Options struct {
+	CMClient   cmclient.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace to report the usage of.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace     string
+	AllNamespaces bool
+
+	// TeamLabel is the label of CertificateRequests that holds the team
+	// they are attributed to
+	TeamLabel string
+	// Since limits the report to CertificateRequests created within the
+	// given duration, if non-zero
+	Since time.Duration
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+		TeamLabel: cmapi.TeamLabelKey,
+	}
+}
+
+// NewCmdReportUsage returns a cobra command for report usage
+func NewCmdReportUsage(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "usage",
+		Short:   "Print the certificates issued and CA requests made per namespace and team",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, report the usage of all namespaces. Namespace in current context is ignored even if specified with --namespace.")
+	cmd.Flags().StringVar(&o.TeamLabel, "team-label", o.TeamLabel, "Label of CertificateRequests that holds the team they are attributed to")
+	cmd.Flags().DurationVar(&o.Since, "since", o.Since, "Only count CertificateRequests created within this duration, e.g. 720h. By default all CertificateRequests are counted")
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) > 0 {
+		return errors.New("no arguments are accepted")
+	}
+	if o.Since < 0 {
+		return errors.New("--since must not be negative")
+	}
+	return nil
+}
+
+// Complete takes the factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory) error {
+	var err error
+
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes report usage command
+func (o *Options) Run() error {
+	ctx := context.TODO()
+
+	namespace := o.Namespace
+	if o.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+
+	crs, err := o.CMClient.CertmanagerV1alpha2().CertificateRequests(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error when listing CertificateRequests: %v", err)
+	}
+	orders, err := o.CMClient.AcmeV1alpha2().Orders(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error when listing Orders: %v", err)
+	}
+
+	var since time.Time
+	if o.Since > 0 {
+		since = time.Now().Add(-o.Since)
+	}
+
+	rows := buildReport(crs.Items, orders.Items, o.TeamLabel, since)
+	if len(rows) == 0 {
+		if o.AllNamespaces {
+			fmt.Fprintln(o.ErrOut, "No CertificateRequests found.")
+		} else {
+			fmt.Fprintf(o.ErrOut, "No CertificateRequests found in %s namespace.\n", o.Namespace)
+		}
+		return nil
+	}
+
+	return printReport(o.Out, rows)
+}
+
+// reportRow holds the usage of a single issuer by a team in a namespace
+type reportRow struct {
+	Namespace string
+	Team      string
+	Issuer    string
+
+	Requests int
+	Issued   int
+	Failed   int
+	Orders   int
+}
+
+// buildReport aggregates the CertificateRequests created after since, and
+// the Orders owned by them, per namespace, team and issuer. Rows are sorted
+// by namespace, team and issuer.
+func buildReport(crs []cmapi.CertificateRequest, orders []cmacme.Order, teamLabel string, since time.Time) []*reportRow {
+	rows := make(map[reportRow]*reportRow)
+	rowsByCR := make(map[types.UID]*reportRow)
+
+	for i := range crs {
+		cr := &crs[i]
+		if cr.CreationTimestamp.Time.Before(since) {
+			continue
+		}
+
+		key := reportRow{Namespace: cr.Namespace, Team: cr.Labels[teamLabel], Issuer: issuerName(cr.Spec.IssuerRef)}
+		row, ok := rows[key]
+		if !ok {
+			row = &reportRow{Namespace: key.Namespace, Team: key.Team, Issuer: key.Issuer}
+			rows[key] = row
+		}
+		rowsByCR[cr.UID] = row
+
+		row.Requests++
+		switch apiutil.CertificateRequestReadyReason(cr) {
+		case cmapi.CertificateRequestReasonIssued:
+			row.Issued++
+		case cmapi.CertificateRequestReasonFailed:
+			row.Failed++
+		}
+	}
+
+	for _, order := range orders {
+		owner := metav1.GetControllerOf(&order)
+		if owner == nil {
+			continue
+		}
+		if row, ok := rowsByCR[owner.UID]; ok {
+			row.Orders++
+		}
+	}
+
+	out := make([]*reportRow, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, row)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		if out[i].Team != out[j].Team {
+			return out[i].Team < out[j].Team
+		}
+		return out[i].Issuer < out[j].Issuer
+	})
+	return out
+}
+
+// issuerName formats ref as '<kind>/<name>', including the group of issuers
+// of third party API groups.
+func issuerName(ref cmmeta.ObjectReference) string {
+	kind := ref.Kind
+	if kind == "" {
+		kind = cmapi.IssuerKind
+	}
+	if ref.Group != "" && ref.Group != "cert-manager.io" {
+		kind += "." + ref.Group
+	}
+	return kind + "/" + ref.Name
+}
+
+// printReport prints rows as a table, followed by the total of each column.
+func printReport(out io.Writer, rows []*reportRow) error {
+	tw := output.NewTabWriter(out)
+	fmt.Fprintf(tw, "NAMESPACE\tTEAM\tISSUER\tREQUESTS\tISSUED\tFAILED\tACME ORDERS\n")
+
+	var total reportRow
+	for _, row := range rows {
+		team := row.Team
+		if team == "" {
+			team = "<none>"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%d\t%d\n", row.Namespace, team, row.Issuer, row.Requests, row.Issued, row.Failed, row.Orders)
+		total.Requests += row.Requests
+		total.Issued += row.Issued
+		total.Failed += row.Failed
+		total.Orders += row.Orders
+	}
+	fmt.Fprintf(tw, "TOTAL\t\t\t%d\t%d\t%d\t%d\n", total.Requests, total.Issued, total.Failed, total.Orders)
+
+	return tw.Flush()
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestBuildReport(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	newCR := func(namespace, uid, team string, created time.Time, issuerRef cmmeta.ObjectReference, reason string) cmapi.CertificateRequest {
+		cr := cmapi.CertificateRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         namespace,
+				Name:              uid,
+				UID:               types.UID(uid),
+				CreationTimestamp: metav1.Time{Time: created},
+			},
+			Spec: cmapi.CertificateRequestSpec{IssuerRef: issuerRef},
+		}
+		if team != "" {
+			cr.Labels = map[string]string{cmapi.TeamLabelKey: team}
+		}
+		if reason != "" {
+			status := cmmeta.ConditionFalse
+			if reason == cmapi.CertificateRequestReasonIssued {
+				status = cmmeta.ConditionTrue
+			}
+			cr.Status.Conditions = []cmapi.CertificateRequestCondition{{Type: cmapi.CertificateRequestConditionReady, Status: status, Reason: reason}}
+		}
+		return cr
+	}
+	newOrder := func(namespace, ownerUID string) cmacme.Order {
+		isController := true
+		return cmacme.Order{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       namespace,
+			OwnerReferences: []metav1.OwnerReference{{UID: types.UID(ownerUID), Controller: &isController}},
+		}}
+	}
+
+	acme := cmmeta.ObjectReference{Name: "letsencrypt", Kind: "ClusterIssuer"}
+	venafi := cmmeta.ObjectReference{Name: "venafi"}
+	pca := cmmeta.ObjectReference{Name: "pca", Kind: "AWSPCAIssuer", Group: "awspca.cert-manager.io"}
+
+	crs := []cmapi.CertificateRequest{
+		newCR("web", "web-1", "team-a", now.Add(-time.Hour), acme, cmapi.CertificateRequestReasonIssued),
+		newCR("web", "web-2", "team-a", now.Add(-2*time.Hour), acme, cmapi.CertificateRequestReasonFailed),
+		newCR("web", "web-3", "", now.Add(-time.Hour), venafi, cmapi.CertificateRequestReasonPending),
+		newCR("api", "api-1", "team-b", now.Add(-time.Hour), pca, ""),
+		newCR("api", "api-old", "team-b", now.Add(-48*time.Hour), pca, cmapi.CertificateRequestReasonIssued),
+	}
+	orders := []cmacme.Order{
+		newOrder("web", "web-1"),
+		newOrder("web", "web-2"),
+		newOrder("web", "web-2"),
+		newOrder("web", "deleted"),
+	}
+
+	rows := buildReport(crs, orders, cmapi.TeamLabelKey, now.Add(-24*time.Hour))
+	expRows := []*reportRow{
+		{Namespace: "api", Team: "team-b", Issuer: "AWSPCAIssuer.awspca.cert-manager.io/pca", Requests: 1},
+		{Namespace: "web", Team: "", Issuer: "Issuer/venafi", Requests: 1},
+		{Namespace: "web", Team: "team-a", Issuer: "ClusterIssuer/letsencrypt", Requests: 2, Issued: 1, Failed: 1, Orders: 3},
+	}
+	if !reflect.DeepEqual(rows, expRows) {
+		t.Errorf("unexpected report rows; expected: %+v, got: %+v", expRows, rows)
+	}
+
+	var out bytes.Buffer
+	if err := printReport(&out, rows); err != nil {
+		t.Fatal(err)
+	}
+	expOut := `NAMESPACE  TEAM    ISSUER                                   REQUESTS  ISSUED  FAILED  ACME ORDERS
+api        team-b  AWSPCAIssuer.awspca.cert-manager.io/pca  1         0       0       0
+web        <none>  Issuer/venafi                            1         0       0       0
+web        team-a  ClusterIssuer/letsencrypt                2         1       1       3
+TOTAL                                                       4         1       1       3
+`
+	if out.String() != expOut {
+		t.Errorf("unexpected output; expected:\n%s\ngot:\n%s", expOut, out.String())
+	}
+}
@@ -0,0 +1,499 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotate
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/completion"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/util"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	ctlclients "github.com/jetstack/cert-manager/pkg/ctl/clients"
+	"github.com/jetstack/cert-manager/pkg/ctl/output"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Rotate the private keys of cert-manager Certificates and revoke their current certificates, e.g. in response to
+the compromise of the private keys.
+
+For each selected Certificate, the issuance of a new certificate with a newly generated private key is triggered,
+regardless of the rotation policy of the Certificate. If the current certificate was issued by an ACME issuer, a
+revocation request is stored alongside it in a Secret labelled cert-manager.io/revocation-request, and the
+certificate is revoked by cert-manager once the Certificate has been issued with the new private key. Other issuers
+do not support revocation, and their certificates have to be revoked out of band.
+
+A report of the affected Secrets is printed, listing the serial numbers of the replaced certificates and the running
+Pods that mount the Secrets, which have to reload the new private key. Use --dry-run to only print the report.`))
+
+	example = templates.Examples(i18n.T(`
+# Rotate the Certificate named 'my-app' in the current context namespace, as its private key was compromised
+kubectl cert-manager rotate my-app --reason compromise
+
+# Rotate all Certificates in all namespaces with the label 'app=my-service', without asking for confirmation
+kubectl cert-manager rotate --all-namespaces -l app=my-service --reason compromise --yes
+
+# Print the Secrets and Pods that would be affected by rotating all Certificates in the 'payments' namespace
+kubectl cert-manager rotate --namespace payments --all --reason compromise --dry-run`))
+)
+
+// reasons maps the values of the --reason flag to the revocation reasons
+// understood by cert-manager.
+var reasons = map[string]string{
+	"compromise":             "keyCompromise",
+	"superseded":             "superseded",
+	"affiliation-changed":    "affiliationChanged",
+	"cessation-of-operation": "cessationOfOperation",
+}
+
+const (
+	revocationRequested     = "Requested"
+	revocationNotSupported  = "NotSupported"
+	revocationNoCertificate = "NoCertificate"
+)
+
+// Options is a struct to support rotate command
+type Options struct {
+	CMClient   cmclient.Interface
+	KubeClient kubernetes.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace that the Certificates to be rotated reside in.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace     string
+	LabelSelector string
+	All           bool
+	AllNamespaces bool
+
+	// Reason is the reason the certificates are revoked for, one of the
+	// keys of reasons
+	Reason string
+	// DryRun only prints the report of the affected Secrets
+	DryRun bool
+	// Yes skips the confirmation before rotating
+	Yes bool
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdRotate returns a cobra command for rotating the private keys of
+// Certificates
+func NewCmdRotate(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "rotate",
+		Short:   "Rotate the private keys of Certificates and revoke their current certificates",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(cmd, args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		ValidArgsFunction: completion.CertificateNames(factory, 0),
+	}
+
+	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", o.LabelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present, rotate Certificates across namespaces. Namespace in current context is ignored even if specified with --namespace.")
+	cmd.Flags().BoolVar(&o.All, "all", o.All, "Rotate all Certificates in the given Namespace, or all namespaces with --all-namespaces enabled.")
+	cmd.Flags().StringVar(&o.Reason, "reason", o.Reason, fmt.Sprintf("The reason the current certificates are revoked for, one of: %s", strings.Join(reasonNames(), ", ")))
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", o.DryRun, "Only print the Secrets and Pods that would be affected")
+	cmd.Flags().BoolVarP(&o.Yes, "yes", "y", o.Yes, "Do not ask for confirmation before rotating the Certificates")
+
+	return cmd
+}
+
+func reasonNames() []string {
+	var names []string
+	for name := range reasons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(cmd *cobra.Command, args []string) error {
+	if _, ok := reasons[o.Reason]; !ok {
+		return fmt.Errorf("--reason must be one of: %s", strings.Join(reasonNames(), ", "))
+	}
+
+	if len(o.LabelSelector) == 0 && !o.All && len(args) == 0 {
+		return errors.New("the names of the Certificates, a label selector or --all have to be provided")
+	}
+
+	if len(o.LabelSelector) > 0 && len(args) > 0 {
+		return errors.New("cannot specify Certificate names in conjunction with label selectors")
+	}
+
+	if len(o.LabelSelector) > 0 && o.All {
+		return errors.New("cannot specify label selectors in conjunction with --all flag")
+	}
+
+	if o.All && len(args) > 0 {
+		return errors.New("cannot specify Certificate names in conjunction with --all flag")
+	}
+
+	if len(args) > 0 && o.AllNamespaces {
+		return errors.New("cannot specify Certificate names in conjunction with --all-namespaces flag")
+	}
+
+	return nil
+}
+
+// Complete takes the factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory) error {
+	var err error
+
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	o.KubeClient, err = kubernetes.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// affectedCertificate is a row of the report of the rotate command
+type affectedCertificate struct {
+	crt        *cmapi.Certificate
+	secret     *corev1.Secret
+	serial     string
+	issuer     cmmeta.ObjectReference
+	revocation string
+	mountedBy  []string
+	err        error
+}
+
+// Run executes rotate command
+func (o *Options) Run(args []string) error {
+	ctx := context.TODO()
+
+	crts, err := o.certificates(ctx, args)
+	if err != nil {
+		return err
+	}
+	if len(crts) == 0 {
+		if o.AllNamespaces {
+			fmt.Fprintln(o.ErrOut, "No Certificates found")
+		} else {
+			fmt.Fprintf(o.ErrOut, "No Certificates found in %s namespace.\n", o.Namespace)
+		}
+		return nil
+	}
+
+	var affected []*affectedCertificate
+	pods := make(map[string][]corev1.Pod)
+	for _, crt := range crts {
+		a, err := o.inspect(ctx, crt, pods)
+		if err != nil {
+			return err
+		}
+		affected = append(affected, a)
+	}
+
+	if o.DryRun {
+		fmt.Fprint(o.Out, report(affected))
+		return nil
+	}
+
+	if !o.Yes {
+		fmt.Fprint(o.Out, report(affected))
+		ok, err := o.confirm(fmt.Sprintf("Rotate the private keys of %d Certificates and revoke their certificates for reason %s?", len(affected), reasons[o.Reason]))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("rotation aborted")
+		}
+	}
+
+	failed := 0
+	for _, a := range affected {
+		if a.err == nil {
+			a.err = o.rotate(ctx, a)
+		}
+		if a.err != nil {
+			failed++
+		}
+	}
+
+	fmt.Fprint(o.Out, report(affected))
+	if failed > 0 {
+		return fmt.Errorf("failed to rotate %d of %d Certificates", failed, len(affected))
+	}
+	return nil
+}
+
+// certificates returns the Certificates selected by the arguments and flags
+func (o *Options) certificates(ctx context.Context, args []string) ([]*cmapi.Certificate, error) {
+	var crts []*cmapi.Certificate
+	for _, name := range args {
+		crt, err := o.CMClient.CertmanagerV1alpha2().Certificates(o.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error when getting Certificate resource: %v", err)
+		}
+		crts = append(crts, crt)
+	}
+	if len(args) > 0 {
+		return crts, nil
+	}
+
+	selector, err := labels.Parse(o.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %v", o.LabelSelector, err)
+	}
+	namespace := o.Namespace
+	if o.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+	list, err := ctlclients.NewCache(nil, o.CMClient, ctlclients.DefaultChunkSize).ListCertificates(ctx, namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Namespace != list[j].Namespace {
+			return list[i].Namespace < list[j].Namespace
+		}
+		return list[i].Name < list[j].Name
+	})
+	for _, crt := range list {
+		crts = append(crts, crt.DeepCopy())
+	}
+	return crts, nil
+}
+
+// inspect gathers the current certificate of the Certificate, whether it can
+// be revoked and the running Pods mounting its Secret. pods caches the Pods
+// listed per namespace.
+func (o *Options) inspect(ctx context.Context, crt *cmapi.Certificate, pods map[string][]corev1.Pod) (*affectedCertificate, error) {
+	a := &affectedCertificate{crt: crt, issuer: crt.Spec.IssuerRef}
+
+	if apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
+		Type:   cmapi.CertificateConditionIssuing,
+		Status: cmmeta.ConditionTrue,
+	}) {
+		a.err = errors.New("an issuance is already in progress, wait for it to complete before rotating")
+	}
+
+	if _, ok := pods[crt.Namespace]; !ok {
+		list, err := o.KubeClient.CoreV1().Pods(crt.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error when listing Pods: %v", err)
+		}
+		pods[crt.Namespace] = list.Items
+	}
+	a.mountedBy = util.PodsMountingSecret(pods[crt.Namespace], crt.Spec.SecretName)
+
+	secret, err := o.KubeClient.CoreV1().Secrets(crt.Namespace).Get(ctx, crt.Spec.SecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		a.revocation = revocationNoCertificate
+		return a, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error when getting Secret %s/%s: %v", crt.Namespace, crt.Spec.SecretName, err)
+	}
+	cert, err := pki.DecodeX509CertificateBytes(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		a.revocation = revocationNoCertificate
+		return a, nil
+	}
+	a.secret = secret
+	a.serial = cert.SerialNumber.String()
+
+	// the controller only revokes certificates through the issuer the
+	// Certificate refers to
+	supported, err := o.supportsRevocation(ctx, crt.Namespace, a.issuer)
+	if err != nil {
+		return nil, err
+	}
+	if supported {
+		a.revocation = revocationRequested
+	} else {
+		a.revocation = revocationNotSupported
+	}
+
+	return a, nil
+}
+
+// supportsRevocation returns true if the referenced issuer is an ACME issuer,
+// the only type of issuer that supports revocation.
+func (o *Options) supportsRevocation(ctx context.Context, namespace string, ref cmmeta.ObjectReference) (bool, error) {
+	if ref.Group != "" && ref.Group != certmanager.GroupName {
+		return false, nil
+	}
+
+	var spec *cmapi.IssuerSpec
+	switch ref.Kind {
+	case "", cmapi.IssuerKind:
+		iss, err := o.CMClient.CertmanagerV1alpha2().Issuers(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("error when getting Issuer %s/%s: %v", namespace, ref.Name, err)
+		}
+		spec = &iss.Spec
+	case cmapi.ClusterIssuerKind:
+		iss, err := o.CMClient.CertmanagerV1alpha2().ClusterIssuers().Get(ctx, ref.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("error when getting ClusterIssuer %s: %v", ref.Name, err)
+		}
+		spec = &iss.Spec
+	default:
+		return false, nil
+	}
+
+	return spec.ACME != nil, nil
+}
+
+// rotate requests the revocation of the current certificate, if supported,
+// and triggers the issuance of the Certificate with a new private key.
+func (o *Options) rotate(ctx context.Context, a *affectedCertificate) error {
+	crt := a.crt.DeepCopy()
+
+	// revisions begin from 1
+	currentRevision, nextRevision := 0, 1
+	if crt.Status.Revision != nil {
+		currentRevision = *crt.Status.Revision
+		nextRevision = currentRevision + 1
+	}
+
+	if a.revocation == revocationRequested {
+		request := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: crt.Namespace,
+				Name:      fmt.Sprintf("%s-revocation-%d", crt.Name, currentRevision),
+				Labels: map[string]string{
+					cmapi.RevocationRequestLabelKey: "true",
+				},
+				Annotations: map[string]string{
+					cmapi.CertificateNameKey:               crt.Name,
+					cmapi.RevocationReasonAnnotationKey:    reasons[o.Reason],
+					cmapi.RevokeAfterRevisionAnnotationKey: strconv.Itoa(nextRevision),
+				},
+			},
+			Data: map[string][]byte{
+				corev1.TLSCertKey: a.secret.Data[corev1.TLSCertKey],
+			},
+		}
+		_, err := o.KubeClient.CoreV1().Secrets(crt.Namespace).Create(ctx, request, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to request revocation: %v", err)
+		}
+	}
+
+	if crt.Annotations == nil {
+		crt.Annotations = make(map[string]string)
+	}
+	crt.Annotations[cmapi.RotatePrivateKeyAnnotationKey] = strconv.Itoa(nextRevision)
+	crt, err := o.CMClient.CertmanagerV1alpha2().Certificates(crt.Namespace).Update(ctx, crt, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to request a new private key: %v", err)
+	}
+
+	apiutil.SetCertificateCondition(crt, cmapi.CertificateConditionIssuing, cmmeta.ConditionTrue, "ManuallyTriggered",
+		fmt.Sprintf("Certificate re-issuance with a new private key manually triggered for reason %s", reasons[o.Reason]))
+	if _, err := o.CMClient.CertmanagerV1alpha2().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to trigger issuance: %v", err)
+	}
+
+	return nil
+}
+
+// report renders the affected Certificates as a table
+func report(affected []*affectedCertificate) string {
+	table := output.NewTable("NAMESPACE", "CERTIFICATE", "SECRET", "SERIAL", "REVOCATION", "MOUNTED BY", "ERROR")
+	for _, a := range affected {
+		serial := a.serial
+		if serial == "" {
+			serial = "<none>"
+		}
+		mountedBy := strings.Join(a.mountedBy, ",")
+		if mountedBy == "" {
+			mountedBy = "<none>"
+		}
+		errText := ""
+		if a.err != nil {
+			errText = a.err.Error()
+		}
+		table.AddRow(a.crt.Namespace, a.crt.Name, a.crt.Spec.SecretName, serial, a.revocation, mountedBy, errText)
+	}
+	return table.String()
+}
+
+// confirm asks question and returns whether it was answered with yes
+func (o *Options) confirm(question string) (bool, error) {
+	fmt.Fprintf(o.Out, "%s [y/N]: ", question)
+	answer, err := bufio.NewReader(o.In).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, fmt.Errorf("no answer given, use --yes to rotate without confirmation: %v", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotate
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		options *Options
+		args    []string
+		expErr  bool
+	}{
+		"a Certificate name and a reason": {
+			options: &Options{Reason: "compromise"},
+			args:    []string{"abc"},
+		},
+		"a label selector and a reason": {
+			options: &Options{Reason: "compromise", LabelSelector: "foo=bar"},
+		},
+		"all certificates in all namespaces": {
+			options: &Options{Reason: "superseded", All: true, AllNamespaces: true},
+		},
+		"no reason": {
+			options: &Options{},
+			args:    []string{"abc"},
+			expErr:  true,
+		},
+		"an unknown reason": {
+			options: &Options{Reason: "boredom"},
+			args:    []string{"abc"},
+			expErr:  true,
+		},
+		"no Certificates selected": {
+			options: &Options{Reason: "compromise"},
+			expErr:  true,
+		},
+		"arguments and label selector": {
+			options: &Options{Reason: "compromise", LabelSelector: "foo=bar"},
+			args:    []string{"abc"},
+			expErr:  true,
+		},
+		"all and label selector": {
+			options: &Options{Reason: "compromise", LabelSelector: "foo=bar", All: true},
+			expErr:  true,
+		},
+		"arguments and all": {
+			options: &Options{Reason: "compromise", All: true},
+			args:    []string{"abc"},
+			expErr:  true,
+		},
+		"arguments and all namespaces": {
+			options: &Options{Reason: "compromise", AllNamespaces: true},
+			args:    []string{"abc"},
+			expErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.options.Validate(&cobra.Command{}, test.args)
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error=%t but got: %v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	acmeIssuer := gen.Issuer("acme-issuer", gen.SetIssuerNamespace("testns"), gen.SetIssuerACME(cmacme.ACMEIssuer{}))
+	caIssuer := gen.Issuer("ca-issuer", gen.SetIssuerNamespace("testns"), gen.SetIssuerCA(cmapi.CAIssuer{}))
+
+	certificate := func(name, issuerName string) *cmapi.Certificate {
+		return gen.Certificate(name,
+			gen.SetCertificateNamespace("testns"),
+			gen.SetCertificateCommonName("example.com"),
+			gen.SetCertificateSecretName(name+"-tls"),
+			gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: issuerName, Kind: cmapi.IssuerKind}),
+			gen.SetCertificateRevision(3),
+		)
+	}
+	secret := func(crt *cmapi.Certificate) *corev1.Secret {
+		pk, err := pki.GenerateRSAPrivateKey(2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template, err := pki.GenerateTemplate(crt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		certPEM, _, err := pki.SignCertificate(template, template, pk.Public(), pk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: crt.Namespace,
+				Name:      crt.Spec.SecretName,
+				Annotations: map[string]string{
+					cmapi.IssuerNameAnnotationKey: crt.Spec.IssuerRef.Name,
+					cmapi.IssuerKindAnnotationKey: crt.Spec.IssuerRef.Kind,
+				},
+			},
+			Data: map[string][]byte{corev1.TLSCertKey: certPEM},
+		}
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "app"},
+		Spec: corev1.PodSpec{Volumes: []corev1.Volume{{Name: "tls", VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: "acme-tls"},
+		}}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	tests := map[string]struct {
+		crt     *cmapi.Certificate
+		secret  bool
+		dryRun  bool
+		issuing bool
+
+		expRevocation        string
+		expRevocationRequest bool
+		expRotated           bool
+		expErr               bool
+	}{
+		"request revocation of a certificate issued by an ACME issuer": {
+			crt:                  certificate("acme", "acme-issuer"),
+			secret:               true,
+			expRevocation:        revocationRequested,
+			expRevocationRequest: true,
+			expRotated:           true,
+		},
+		"do not request revocation of a certificate issued by a CA issuer": {
+			crt:           certificate("ca", "ca-issuer"),
+			secret:        true,
+			expRevocation: revocationNotSupported,
+			expRotated:    true,
+		},
+		"rotate a Certificate without a Secret": {
+			crt:           certificate("acme", "acme-issuer"),
+			expRevocation: revocationNoCertificate,
+			expRotated:    true,
+		},
+		"do not change anything with --dry-run": {
+			crt:           certificate("acme", "acme-issuer"),
+			secret:        true,
+			dryRun:        true,
+			expRevocation: revocationRequested,
+		},
+		"fail to rotate a Certificate with an issuance in progress": {
+			crt:           certificate("acme", "acme-issuer"),
+			secret:        true,
+			issuing:       true,
+			expRevocation: revocationRequested,
+			expErr:        true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := test.crt
+			if test.issuing {
+				crt = gen.CertificateFrom(crt, gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:   cmapi.CertificateConditionIssuing,
+					Status: cmmeta.ConditionTrue,
+				}))
+			}
+			kubeObjects := []runtime.Object{pod}
+			if test.secret {
+				kubeObjects = append(kubeObjects, secret(crt))
+			}
+
+			out := new(bytes.Buffer)
+			o := NewOptions(genericclioptions.IOStreams{Out: out, ErrOut: out})
+			o.Namespace = "testns"
+			o.Reason = "compromise"
+			o.Yes = true
+			o.DryRun = test.dryRun
+			o.KubeClient = kubefake.NewSimpleClientset(kubeObjects...)
+			o.CMClient = cmfake.NewSimpleClientset(crt, acmeIssuer, caIssuer)
+
+			err := o.Run([]string{crt.Name})
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error=%t but got: %v\noutput: %s", test.expErr, err, out)
+			}
+
+			if !strings.Contains(out.String(), test.expRevocation) {
+				t.Errorf("expected report to contain %q, got:\n%s", test.expRevocation, out)
+			}
+			if test.crt.Name == "acme" && test.secret && !strings.Contains(out.String(), pod.Name) {
+				t.Errorf("expected report to list Pod %q, got:\n%s", pod.Name, out)
+			}
+
+			ctx := context.Background()
+			request, err := o.KubeClient.CoreV1().Secrets("testns").Get(ctx, crt.Name+"-revocation-3", metav1.GetOptions{})
+			if test.expRevocationRequest != (err == nil) {
+				t.Fatalf("expected revocation request=%t, got error: %v", test.expRevocationRequest, err)
+			}
+			if test.expRevocationRequest {
+				if exp := "4"; request.Annotations[cmapi.RevokeAfterRevisionAnnotationKey] != exp {
+					t.Errorf("expected revocation to be deferred until revision %s, got %q", exp, request.Annotations[cmapi.RevokeAfterRevisionAnnotationKey])
+				}
+				if exp := "keyCompromise"; request.Annotations[cmapi.RevocationReasonAnnotationKey] != exp {
+					t.Errorf("expected revocation reason %s, got %q", exp, request.Annotations[cmapi.RevocationReasonAnnotationKey])
+				}
+			}
+
+			updated, err := o.CMClient.CertmanagerV1alpha2().Certificates("testns").Get(ctx, crt.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			rotated := updated.Annotations[cmapi.RotatePrivateKeyAnnotationKey] == "4"
+			if rotated != test.expRotated {
+				t.Errorf("expected rotated=%t but got %t", test.expRotated, rotated)
+			}
+		})
+	}
+}
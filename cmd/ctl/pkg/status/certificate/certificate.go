@@ -20,56 +20,185 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/spf13/cobra"
-	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/reference"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
+	utilexec "k8s.io/utils/exec"
 
-	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/completion"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/multicluster"
 	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
-	"github.com/jetstack/cert-manager/pkg/ctl"
-	"github.com/jetstack/cert-manager/pkg/util/predicate"
+	ctlclients "github.com/jetstack/cert-manager/pkg/ctl/clients"
+	ctlstatus "github.com/jetstack/cert-manager/pkg/ctl/status"
 )
 
 var (
 	long = templates.LongDesc(i18n.T(`
-Get details about the current status of a cert-manager Certificate resource, including information on related resources like CertificateRequest.`))
+Get details about the current status of a cert-manager Certificate resource, including information on related resources like CertificateRequest.
+
+Issuers of third party API groups are looked up using the discovery API, and the conditions in their status are printed.
+
+With --related, all resources that have been created to issue the Certificate are printed as a tree, from its
+CertificateRequests down to the Orders, Challenges and HTTP01 solver Pods, Services and Ingresses of ACME issuers.
+
+With --graph, only the graph of resources involved in issuing the Certificate is printed, with the ready state of every
+resource: the related resources printed with --related, the Issuer or ClusterIssuer, and the resources the Certificate
+was created for, like an Ingress annotated for ingress-shim. Use -o dot to render it with Graphviz, e.g. to attach the
+topology of a failed issuance to a support ticket.
+
+Long DNS names and messages are wrapped to the width of the terminal, or the number of columns in the COLUMNS
+environment variable. With -o markdown, the status is printed as Markdown instead, with every related resource in a
+section of its own and conditions and events as tables, to be pasted into an issue or a support ticket.
+
+While the CertificateRequest of the current issuance is not Ready, the pending ACME Challenges are printed with the Pods,
+Services and Ingresses of their HTTP01 solvers, their Events, and problems like images that cannot be pulled or Ingresses
+that no ingress controller has picked up. With --show-solver-logs, the last lines of the logs of the solver Pods are
+printed too.
+
+With --watch, the command keeps running after printing the status and prints every change to the Certificate, its
+CertificateRequests, Orders and Challenges, and their Events, until the Certificate is Ready or the command is interrupted.
+
+With --wait-for=condition=Ready, the command waits like with --watch, but also stops once the issuance failed, and
+reports the result in its exit code: 0 if the Certificate is Ready, 1 if the --timeout expired or an error occurred,
+and 2 if the issuance failed.
+
+With --all, the status of all Certificates in the namespace, or all namespaces with --all-namespaces, is printed.
+Each kind of related resource is then listed once, in pages of --chunk-size resources, instead of getting the
+resources of every Certificate one by one. With --contexts, the status of all Certificates is printed for the clusters of
+several kubeconfig contexts, one cluster after the other.`))
 
 	example = templates.Examples(i18n.T(`
 # Query status of Certificate with name 'my-crt' in namespace 'my-namespace'
 kubectl cert-manager status certificate my-crt --namespace my-namespace
+
+# Print the status of Certificate 'my-crt', including the logs of the HTTP01 solver Pods of its pending Challenges
+kubectl cert-manager status certificate my-crt --show-solver-logs
+
+# Print the status of Certificate 'my-crt' and all resources that have been created to issue it
+kubectl cert-manager status certificate my-crt --related
+
+# Render the resources that have been created to issue Certificate 'my-crt' as an image using Graphviz
+kubectl cert-manager status certificate my-crt --related -o dot | dot -Tsvg > my-crt.svg
+
+# Render the graph of all resources involved in issuing Certificate 'my-crt', including its issuer and owners
+kubectl cert-manager status certificate my-crt --graph -o dot | dot -Tpng > my-crt.png
+
+# Print the status of Certificate 'my-crt' as Markdown, e.g. to paste it into a support ticket
+kubectl cert-manager status certificate my-crt -o markdown
+
+# Print the status of Certificate 'my-crt' and then follow its progress until it is Ready
+kubectl cert-manager status certificate my-crt --watch
+
+# Block for up to 5 minutes until Certificate 'my-crt' is Ready, e.g. in a deployment pipeline
+kubectl cert-manager status certificate my-crt --wait-for=condition=Ready --timeout=5m
+
+# Print the status of all Certificates in all namespaces
+kubectl cert-manager status certificate --all --all-namespaces
+
+# Print the status of all Certificates in the clusters of the 'prod-eu' and 'prod-us' contexts, impersonating 'auditor'
+kubectl cert-manager status certificate --all --all-namespaces --contexts prod-eu,prod-us --as auditor
 `))
 )
 
+const (
+	// waitForReady is the only condition supported by --wait-for
+	waitForReady = "condition=Ready"
+
+	// exitCodeTimeout is the exit code when the Certificate did not become
+	// Ready within the timeout of --wait-for
+	exitCodeTimeout = 1
+	// exitCodeIssuanceFailed is the exit code when the issuance of the
+	// Certificate failed while waiting for it to become Ready
+	exitCodeIssuanceFailed = 2
+
+	// outputMarkdown and outputDOT are the formats supported by --output
+	outputMarkdown = "markdown"
+	outputDOT      = "dot"
+
+	// solverLogTailLines is the number of lines of the logs of HTTP01 solver
+	// Pods printed with --show-solver-logs
+	solverLogTailLines = 20
+)
+
 // Options is a struct to support status certificate command
 type Options struct {
 	CMClient   cmclient.Interface
 	RESTConfig *restclient.Config
+	// DynamicClient and RESTMapper are used to get issuers of third party
+	// API groups
+	DynamicClient dynamic.Interface
+	RESTMapper    meta.RESTMapper
 	// The Namespace that the Certificate to be queried about resides in.
 	// This flag registration is handled by cmdutil.Factory
 	Namespace string
 
+	// Related makes the command print the tree of resources created for the
+	// Certificate
+	Related bool
+	// Graph makes the command print only the graph of resources involved in
+	// issuing the Certificate, including its issuer and owners
+	Graph bool
+	// ShowSolverLogs makes the command print the last lines of the logs of
+	// the HTTP01 solver Pods of pending Challenges
+	ShowSolverLogs bool
+	// Output is the format the status is printed in: empty for text,
+	// "markdown" for Markdown, or "dot" for a Graphviz digraph of the
+	// related resources or graph
+	Output string
+
+	// Watch makes the command follow the progress of the Certificate after
+	// printing its status, until it becomes Ready
+	Watch bool
+	// StopCh is closed when the command is interrupted
+	StopCh <-chan struct{}
+
+	// WaitFor is the condition to wait for after printing the status. Only
+	// "condition=Ready" is supported
+	WaitFor string
+	// Timeout is how long to wait for the condition, if non-zero
+	Timeout time.Duration
+
+	// All makes the command print the status of all Certificates in the
+	// namespace, or in all namespaces if AllNamespaces is set
+	All           bool
+	AllNamespaces bool
+	// ChunkSize is the number of resources requested per page when listing
+	// resources with All
+	ChunkSize int64
+	// Contexts are the kubeconfig contexts of the clusters to print the
+	// status of all Certificates of with All. The clients of every context
+	// are created from ConfigFlags when the command is run.
+	Contexts    []string
+	ConfigFlags *genericclioptions.ConfigFlags
+
 	genericclioptions.IOStreams
 }
 
 // NewOptions returns initialized Options
-func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+func NewOptions(ioStreams genericclioptions.IOStreams, configFlags *genericclioptions.ConfigFlags, stopCh <-chan struct{}) *Options {
 	return &Options{
-		IOStreams: ioStreams,
+		IOStreams:   ioStreams,
+		StopCh:      stopCh,
+		ChunkSize:   ctlclients.DefaultChunkSize,
+		ConfigFlags: configFlags,
 	}
 }
 
 // NewCmdStatusCert returns a cobra command for status certificate
-func NewCmdStatusCert(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
-	o := NewOptions(ioStreams)
+func NewCmdStatusCert(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory, configFlags *genericclioptions.ConfigFlags, stopCh <-chan struct{}) *cobra.Command {
+	o := NewOptions(ioStreams, configFlags, stopCh)
 	cmd := &cobra.Command{
 		Use:     "certificate",
 		Short:   "Get details about the current status of a cert-manager Certificate resource",
@@ -80,23 +209,102 @@ func NewCmdStatusCert(ioStreams genericclioptions.IOStreams, factory cmdutil.Fac
 			cmdutil.CheckErr(o.Complete(factory))
 			cmdutil.CheckErr(o.Run(args))
 		},
+		ValidArgsFunction: completion.CertificateNames(factory, 1),
 	}
+	cmd.Flags().BoolVar(&o.Related, "related", o.Related, "Print all resources created to issue the Certificate, like CertificateRequests, Orders, Challenges and solver Pods")
+	cmd.Flags().BoolVar(&o.Graph, "graph", o.Graph, "Print only the graph of resources involved in issuing the Certificate, including its issuer and the resources it was created for")
+	cmd.Flags().BoolVar(&o.ShowSolverLogs, "show-solver-logs", o.ShowSolverLogs, fmt.Sprintf("Print the last %d lines of the logs of the HTTP01 solver Pods of pending Challenges", solverLogTailLines))
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format. One of: markdown, which prints the status as Markdown, or dot, which prints the resources of --related or --graph as a Graphviz digraph instead of the status")
+	cmd.Flags().BoolVarP(&o.Watch, "watch", "w", o.Watch, "After printing the status, watch the Certificate and its related resources and print changes until it is Ready")
+	cmd.Flags().StringVar(&o.WaitFor, "wait-for", o.WaitFor, "Wait for the Certificate to meet the condition after printing the status. Only 'condition=Ready' is supported. "+
+		"The command exits with 0 once the condition is met, 1 if the timeout expired and 2 if the issuance failed")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "The maximum time to wait with --wait-for, e.g. 5m. Zero means wait forever")
+	cmd.Flags().BoolVar(&o.All, "all", o.All, "Print the status of all Certificates in the given Namespace, or all namespaces with --all-namespaces enabled.")
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "If present with --all, print the status of Certificates across namespaces. Namespace in current context is ignored even if specified with --namespace.")
+	cmd.Flags().Int64Var(&o.ChunkSize, "chunk-size", o.ChunkSize, "Return large lists in chunks rather than all at once with --all. Pass 0 to disable.")
+	multicluster.AddContextsFlag(cmd.Flags(), &o.Contexts)
 	return cmd
 }
 
 // Validate validates the provided options
 func (o *Options) Validate(args []string) error {
+	if o.ChunkSize < 0 {
+		return errors.New("--chunk-size must not be negative")
+	}
+	if o.AllNamespaces && !o.All {
+		return errors.New("--all-namespaces can only be used together with --all")
+	}
+	if len(o.Contexts) > 0 && !o.All {
+		return errors.New("--contexts can only be used together with --all")
+	}
+	if o.All {
+		if len(args) > 0 {
+			return errors.New("cannot specify Certificate names in conjunction with --all flag")
+		}
+		if o.Related || o.Graph || o.Watch || o.WaitFor != "" {
+			return errors.New("--all cannot be used together with --related, --graph, --watch or --wait-for")
+		}
+		if o.Output != "" && o.Output != outputMarkdown {
+			return fmt.Errorf("only %q is supported as --output together with --all", outputMarkdown)
+		}
+		return multicluster.Validate(o.ConfigFlags, o.Contexts)
+	}
 	if len(args) < 1 {
 		return errors.New("the name of the Certificate has to be provided as argument")
 	}
 	if len(args) > 1 {
 		return errors.New("only one argument can be passed in: the name of the Certificate")
 	}
+	if o.ShowSolverLogs && (o.Graph || o.Output == outputDOT) {
+		return errors.New("--show-solver-logs cannot be used together with --graph or --output dot")
+	}
+	if o.Graph {
+		if o.Related {
+			return errors.New("--graph and --related cannot be used together")
+		}
+		if o.Watch || o.WaitFor != "" {
+			return errors.New("--graph cannot be used together with --watch or --wait-for")
+		}
+	}
+	if o.Output != "" {
+		switch o.Output {
+		case outputDOT:
+			if !o.Related && !o.Graph {
+				return errors.New("--output dot can only be used together with --related or --graph")
+			}
+		case outputMarkdown:
+			if o.Related || o.Graph {
+				return errors.New("--output markdown cannot be used together with --related or --graph")
+			}
+		default:
+			return fmt.Errorf("unsupported output format %q, only %q and %q are supported", o.Output, outputMarkdown, outputDOT)
+		}
+		if o.Watch {
+			return errors.New("--output and --watch cannot be used together")
+		}
+		if o.WaitFor != "" {
+			return errors.New("--output and --wait-for cannot be used together")
+		}
+	}
+	if o.WaitFor != "" && o.WaitFor != waitForReady {
+		return fmt.Errorf("unsupported condition %q for --wait-for, only %q is supported", o.WaitFor, waitForReady)
+	}
+	if o.Timeout < 0 {
+		return errors.New("--timeout must not be negative")
+	}
+	if o.Timeout != 0 && o.WaitFor == "" {
+		return errors.New("--timeout can only be used together with --wait-for")
+	}
 	return nil
 }
 
 // Complete takes the factory and infers any remaining options.
 func (o *Options) Complete(f cmdutil.Factory) error {
+	if len(o.Contexts) > 0 {
+		// The clients of every context are created by Run
+		return nil
+	}
+
 	var err error
 
 	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
@@ -114,142 +322,226 @@ func (o *Options) Complete(f cmdutil.Factory) error {
 		return err
 	}
 
+	o.DynamicClient, err = f.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	o.RESTMapper, err = f.ToRESTMapper()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // Run executes status certificate command
 func (o *Options) Run(args []string) error {
 	ctx := context.TODO()
-	crtName := args[0]
+
+	if len(o.Contexts) > 0 {
+		return o.runContexts(ctx)
+	}
 
 	clientSet, err := kubernetes.NewForConfig(o.RESTConfig)
 	if err != nil {
 		return err
 	}
 
+	if o.All {
+		return o.runAll(ctx, clientSet)
+	}
+	crtName := args[0]
+
 	crt, err := o.CMClient.CertmanagerV1alpha2().Certificates(o.Namespace).Get(ctx, crtName, metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("error when getting Certificate resource: %v", err)
 	}
 
-	crtRef, err := reference.GetReference(ctl.Scheme, crt)
-	if err != nil {
-		return err
+	if o.Graph {
+		graph, err := buildGraph(ctx, graphClients{
+			CM:         o.CMClient,
+			Kube:       clientSet,
+			Dynamic:    o.DynamicClient,
+			RESTMapper: o.RESTMapper,
+		}, crt)
+		if err != nil {
+			return err
+		}
+		if o.Output == outputDOT {
+			fmt.Fprint(o.Out, graph.DOT())
+		} else {
+			fmt.Fprint(o.Out, graph)
+		}
+		return nil
 	}
-	// Ignore error, since if there was an error, crtEvents would be nil and handled down the line in DescribeEvents
-	crtEvents, _ := clientSet.CoreV1().Events(o.Namespace).Search(ctl.Scheme, crtRef)
 
-	secret, secretErr := clientSet.CoreV1().Secrets(o.Namespace).Get(ctx, crt.Spec.SecretName, metav1.GetOptions{})
-	if secretErr != nil {
-		secretErr = fmt.Errorf("error when finding Secret %q: %w\n", crt.Spec.SecretName, secretErr)
+	if o.Related && o.Output == outputDOT {
+		tree, err := buildRelatedTree(ctx, o.CMClient, clientSet, crt)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(o.Out, tree.DOT())
+		return nil
 	}
 
-	// TODO: What about timing issues? When I query condition it's not ready yet, but then looking for cr it's finished and deleted
-	// Try find the CertificateRequest that is owned by crt and has the correct revision
-	req, reqErr := findMatchingCR(o.CMClient, ctx, crt)
-	if reqErr != nil {
-		reqErr = fmt.Errorf("error when finding CertificateRequest: %w\n", reqErr)
+	status, err := ctlstatus.CollectStatusForCertificateWithOptions(ctx, ctlstatus.Clients{
+		Kube:       clientSet,
+		CM:         o.CMClient,
+		Dynamic:    o.DynamicClient,
+		RESTMapper: o.RESTMapper,
+	}, crt, o.collectOptions())
+	if err != nil {
+		return err
 	}
-	if req == nil {
-		reqErr = errors.New("No CertificateRequest found for this Certificate\n")
+
+	if o.Output == outputMarkdown {
+		fmt.Fprint(o.Out, status.Markdown())
+		return nil
 	}
 
-	var reqEvents *corev1.EventList
-	if req != nil {
-		reqRef, err := reference.GetReference(ctl.Scheme, req)
+	fmt.Fprint(o.Out, status.String())
+
+	if o.Related {
+		tree, err := buildRelatedTree(ctx, o.CMClient, clientSet, crt)
 		if err != nil {
 			return err
 		}
-		// Ignore error, since if there was an error, reqEvents would be nil and handled down the line in DescribeEvents
-		reqEvents, _ = clientSet.CoreV1().Events(o.Namespace).Search(ctl.Scheme, reqRef)
+		fmt.Fprintf(o.Out, "Related resources:\n%s", tree)
 	}
 
-	// Build status of Certificate with data gathered
-	status := newCertificateStatusFromCert(crt).
-		withEvents(crtEvents).
-		withSecret(secret, secretErr).
-		withCR(req, reqEvents, reqErr)
-
-	issuerKind := crt.Spec.IssuerRef.Kind
-	if issuerKind == "" {
-		issuerKind = "Issuer"
+	waitFor := o.WaitFor != ""
+	if !(o.Watch || waitFor) || isReady(crt) {
+		return nil
 	}
 
-	// Get info on Issuer/ClusterIssuer
-	if crt.Spec.IssuerRef.Group != "cert-manager.io" && crt.Spec.IssuerRef.Group != "" {
-		// TODO: Support Issuers/ClusterIssuers from other groups as well
-		status = status.withIssuer(nil, fmt.Errorf("The %s %q is not of the group cert-manager.io, this command currently does not support third party issuers.\nTo get more information about %q, try 'kubectl describe'\n",
-			issuerKind, crt.Spec.IssuerRef.Name, crt.Spec.IssuerRef.Name))
-	} else if issuerKind == "Issuer" {
-		issuer, issuerErr := o.CMClient.CertmanagerV1alpha2().Issuers(crt.Namespace).Get(ctx, crt.Spec.IssuerRef.Name, metav1.GetOptions{})
-		if issuerErr != nil {
-			issuerErr = fmt.Errorf("error when getting Issuer: %v\n", issuerErr)
-		}
-		status = status.withIssuer(issuer, issuerErr)
-	} else {
-		// ClusterIssuer
-		clusterIssuer, issuerErr := o.CMClient.CertmanagerV1alpha2().ClusterIssuers().Get(ctx, crt.Spec.IssuerRef.Name, metav1.GetOptions{})
-		if issuerErr != nil {
-			issuerErr = fmt.Errorf("error when getting ClusterIssuer: %v\n", issuerErr)
-		}
-		status = status.withClusterIssuer(clusterIssuer, issuerErr)
+	fmt.Fprintf(o.Out, "\nWatching Certificate %q for changes...\n", crt.Name)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if o.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
 	}
+	go func() {
+		select {
+		case <-o.StopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
 
-	fmt.Fprintf(o.Out, status.String())
+	err = watchCertificate(ctx, o.Out, o.CMClient, clientSet, crt, waitFor)
+	if !waitFor {
+		return err
+	}
 
+	var failedErr *issuanceFailedError
+	switch {
+	case errors.As(err, &failedErr):
+		return utilexec.CodeExitError{Err: err, Code: exitCodeIssuanceFailed}
+	case err != nil:
+		return err
+	case ctx.Err() == context.DeadlineExceeded:
+		return utilexec.CodeExitError{Err: fmt.Errorf("timed out waiting for Certificate %q to become Ready", crt.Name), Code: exitCodeTimeout}
+	case ctx.Err() != nil:
+		return fmt.Errorf("interrupted while waiting for Certificate %q to become Ready", crt.Name)
+	}
 	return nil
 }
 
-// formatStringSlice takes in a string slice and formats the contents of the slice
-// into a single string where each element of the slice is prefixed with "- " and on a new line
-func formatStringSlice(strings []string) string {
-	result := ""
-	for _, str := range strings {
-		result += "- " + str + "\n"
+// collectOptions returns the details to collect in addition to the status
+// of Certificates.
+func (o *Options) collectOptions() ctlstatus.CollectOptions {
+	var opts ctlstatus.CollectOptions
+	if o.ShowSolverLogs {
+		opts.SolverLogTailLines = solverLogTailLines
 	}
-	return result
+	return opts
 }
 
-// formatTimeString returns the time as a string
-// If nil, return "<none>"
-func formatTimeString(t *metav1.Time) string {
-	if t == nil {
-		return "<none>"
+// runContexts prints the status of all Certificates in the cluster of every
+// context of o.Contexts. A cluster that cannot be reached does not stop the
+// others from being printed; its error is returned at the end.
+func (o *Options) runContexts(ctx context.Context) error {
+	var errs []error
+	for i, kubeContext := range o.Contexts {
+		if i > 0 {
+			fmt.Fprintln(o.Out)
+		}
+		fmt.Fprintf(o.Out, "Context: %s\n\n", kubeContext)
+
+		if err := o.runContext(ctx, kubeContext); err != nil {
+			fmt.Fprintf(o.ErrOut, "Error: %v\n", err)
+			errs = append(errs, fmt.Errorf("context %q: %v", kubeContext, err))
+		}
 	}
-	return t.Time.Format(time.RFC3339)
+	return utilerrors.NewAggregate(errs)
 }
 
-// findMatchingCR tries to find a CertificateRequest that is owned by crt and has the correct revision annotated from reqs.
-// If none found returns nil
-// If one found returns the CR
-// If multiple found or error occurs when listing CRs, returns error
-func findMatchingCR(cmClient cmclient.Interface, ctx context.Context, crt *cmapi.Certificate) (*cmapi.CertificateRequest, error) {
-	reqs, err := cmClient.CertmanagerV1alpha2().CertificateRequests(crt.Namespace).List(ctx, metav1.ListOptions{})
+// runContext prints the status of all Certificates in the cluster of the
+// given kubeconfig context.
+func (o *Options) runContext(ctx context.Context, kubeContext string) error {
+	co := *o
+	co.Contexts = nil
+	if err := co.Complete(multicluster.FactoryForContext(o.ConfigFlags, kubeContext)); err != nil {
+		return err
+	}
+	clientSet, err := kubernetes.NewForConfig(co.RESTConfig)
 	if err != nil {
-		return nil, fmt.Errorf("error when listing CertificateRequest resources: %w", err)
+		return err
 	}
+	return co.runAll(ctx, clientSet)
+}
 
-	possibleMatches := []*cmapi.CertificateRequest{}
+// runAll prints the status of all Certificates in the namespace, or in all
+// namespaces. Related resources are looked up in a Cache, so that every kind
+// of resource is only listed once per namespace.
+func (o *Options) runAll(ctx context.Context, clientSet kubernetes.Interface) error {
+	namespace := o.Namespace
+	if o.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
 
-	// CertificateRequest revisions begin from 1.
-	// If no revision is set on the Certificate then assume the revision on the CertificateRequest should be 1.
-	// If revision is set on the Certificate then revision on the CertificateRequest should be crt.Status.Revision + 1.
-	nextRevision := 1
-	if crt.Status.Revision != nil {
-		nextRevision = *crt.Status.Revision + 1
+	cache := ctlclients.NewCache(clientSet, o.CMClient, o.ChunkSize)
+	crts, err := cache.ListCertificates(ctx, namespace, labels.Everything())
+	if err != nil {
+		return err
 	}
-	for _, req := range reqs.Items {
-		if predicate.CertificateRequestRevision(nextRevision)(&req) &&
-			predicate.ResourceOwnedBy(crt)(&req) {
-			possibleMatches = append(possibleMatches, req.DeepCopy())
+	if len(crts) == 0 {
+		if o.AllNamespaces {
+			fmt.Fprintln(o.ErrOut, "No Certificates found")
+		} else {
+			fmt.Fprintf(o.ErrOut, "No Certificates found in %s namespace.\n", o.Namespace)
 		}
+		return nil
 	}
-
-	if len(possibleMatches) < 1 {
-		return nil, nil
-	} else if len(possibleMatches) == 1 {
-		return possibleMatches[0], nil
-	} else {
-		return nil, errors.New("found multiple certificate requests with expected revision and owner")
+	sort.Slice(crts, func(i, j int) bool {
+		if crts[i].Namespace != crts[j].Namespace {
+			return crts[i].Namespace < crts[j].Namespace
+		}
+		return crts[i].Name < crts[j].Name
+	})
+
+	clients := ctlstatus.Clients{
+		Kube:       clientSet,
+		CM:         o.CMClient,
+		Dynamic:    o.DynamicClient,
+		RESTMapper: o.RESTMapper,
+		Cache:      cache,
+	}
+	for i, crt := range crts {
+		status, err := ctlstatus.CollectStatusForCertificateWithOptions(ctx, clients, crt, o.collectOptions())
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(o.Out)
+		}
+		if o.Output == outputMarkdown {
+			fmt.Fprint(o.Out, status.Markdown())
+			continue
+		}
+		fmt.Fprint(o.Out, status.String())
 	}
+	return nil
 }
@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+)
+
+// maxOwnerDepth is the maximum number of owners of a Certificate that are
+// added to its graph, which guards against cycles of owner references.
+const maxOwnerDepth = 10
+
+// graphClients are the clients used to build the graph of a Certificate.
+type graphClients struct {
+	CM   cmclient.Interface
+	Kube kubernetes.Interface
+	// Dynamic and RESTMapper are used to get the owners of the Certificate
+	// and issuers of third party API groups
+	Dynamic    dynamic.Interface
+	RESTMapper meta.RESTMapper
+}
+
+// buildGraph returns the tree of resources that are involved in issuing crt:
+// the related resources returned by buildRelatedTree, its issuer, and the
+// chain of resources controlling crt, like the Ingress it was created for by
+// ingress-shim. The root of the returned tree is the outermost owner of crt.
+func buildGraph(ctx context.Context, clients graphClients, crt *cmapi.Certificate) (*resourceNode, error) {
+	root, err := buildRelatedTree(ctx, clients.CM, clients.Kube, crt)
+	if err != nil {
+		return nil, err
+	}
+
+	issuer, err := issuerNode(ctx, clients, crt)
+	if err != nil {
+		return nil, err
+	}
+	// the issuer is added after the Secrets, before the CertificateRequests
+	i := 0
+	for i < len(root.Children) && root.Children[i].Kind == "Secret" {
+		i++
+	}
+	root.Children = append(root.Children[:i], append([]*resourceNode{issuer}, root.Children[i:]...)...)
+
+	var obj metav1.Object = crt
+	for depth := 0; depth < maxOwnerDepth; depth++ {
+		ref := metav1.GetControllerOf(obj)
+		if ref == nil {
+			break
+		}
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid owner reference of %s %q: %v", root.Kind, root.Name, err)
+		}
+		owner, err := getObject(ctx, clients, gv.WithKind(ref.Kind), crt.Namespace, ref.Name)
+		node := &resourceNode{Kind: ref.Kind, Name: ref.Name, Children: []*resourceNode{root}}
+		root = node
+		if apierrors.IsNotFound(err) || (err == nil && owner.GetUID() != ref.UID) {
+			node.Phase = "NotFound"
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error when getting %s %q: %v", ref.Kind, ref.Name, err)
+		}
+		node.Phase = unstructuredPhase(owner)
+		obj = owner
+	}
+
+	return root, nil
+}
+
+// issuerNode returns the node of the issuer referenced by crt, which may be
+// an Issuer, a ClusterIssuer or an issuer of a third party API group.
+func issuerNode(ctx context.Context, clients graphClients, crt *cmapi.Certificate) (*resourceNode, error) {
+	ref := crt.Spec.IssuerRef
+	kind := ref.Kind
+	if kind == "" {
+		kind = cmapi.IssuerKind
+	}
+	node := &resourceNode{Kind: kind, Name: ref.Name}
+
+	var conditions []cmapi.IssuerCondition
+	var err error
+	switch {
+	case ref.Group != "" && ref.Group != cmapi.SchemeGroupVersion.Group:
+		node.Kind = kind + "." + ref.Group
+		var issuer *unstructured.Unstructured
+		issuer, err = getObject(ctx, clients, schema.GroupVersionKind{Group: ref.Group, Kind: kind}, crt.Namespace, ref.Name)
+		if err == nil {
+			node.Phase = unstructuredPhase(issuer)
+			return node, nil
+		}
+	case kind == cmapi.ClusterIssuerKind:
+		var issuer *cmapi.ClusterIssuer
+		issuer, err = clients.CM.CertmanagerV1alpha2().ClusterIssuers().Get(ctx, ref.Name, metav1.GetOptions{})
+		if err == nil {
+			conditions = issuer.Status.Conditions
+		}
+	default:
+		var issuer *cmapi.Issuer
+		issuer, err = clients.CM.CertmanagerV1alpha2().Issuers(crt.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err == nil {
+			conditions = issuer.Status.Conditions
+		}
+	}
+	switch {
+	case apierrors.IsNotFound(err):
+		node.Phase = "NotFound"
+		return node, nil
+	case err != nil:
+		return nil, fmt.Errorf("error when getting %s %q: %v", node.Kind, ref.Name, err)
+	}
+
+	node.Phase = "Unknown"
+	for _, c := range conditions {
+		if c.Type == cmapi.IssuerConditionReady {
+			node.Phase = readyPhase(string(c.Status), c.Reason)
+		}
+	}
+	return node, nil
+}
+
+// getObject gets the resource of the given kind, which is looked up in the
+// namespace if the kind is namespaced. If the version of gvk is empty, the
+// preferred version of the kind is used.
+func getObject(ctx context.Context, clients graphClients, gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	if clients.Dynamic == nil || clients.RESTMapper == nil {
+		return nil, fmt.Errorf("no dynamic client configured to get %s %q", gvk.Kind, name)
+	}
+	mapping, err := clients.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return clients.Dynamic.Resource(mapping.Resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	return clients.Dynamic.Resource(mapping.Resource).Get(ctx, name, metav1.GetOptions{})
+}
+
+// unstructuredPhase returns the phase of obj from its Ready condition, if it
+// has one. Resources without conditions have no phase.
+func unstructuredPhase(obj *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		status, _ := condition["status"].(string)
+		reason, _ := condition["reason"].(string)
+		return readyPhase(status, reason)
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+)
+
+func TestBuildGraph(t *testing.T) {
+	ingressGVK := schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress"}
+	externalIssuerGVK := schema.GroupVersionKind{Group: "awspca.cert-manager.io", Version: "v1beta1", Kind: "AWSPCAIssuer"}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(ingressGVK, meta.RESTScopeNamespace)
+	mapper.Add(externalIssuerGVK, meta.RESTScopeNamespace)
+
+	ingress := &unstructured.Unstructured{}
+	ingress.SetGroupVersionKind(ingressGVK)
+	ingress.SetNamespace("default")
+	ingress.SetName("my-ing")
+	ingress.SetUID("ing")
+
+	externalIssuer := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False", "reason": "Unreachable"},
+			},
+		},
+	}}
+	externalIssuer.SetGroupVersionKind(externalIssuerGVK)
+	externalIssuer.SetNamespace("default")
+	externalIssuer.SetName("pca")
+
+	clusterIssuer := &cmapi.ClusterIssuer{
+		ObjectMeta: metav1.ObjectMeta{Name: "letsencrypt"},
+		Status: cmapi.IssuerStatus{Conditions: []cmapi.IssuerCondition{
+			{Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionTrue, Reason: "ACMEAccountRegistered"},
+		}},
+	}
+
+	isController := true
+	ownedBy := func(uid types.UID) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{
+			APIVersion: ingressGVK.GroupVersion().String(),
+			Kind:       ingressGVK.Kind,
+			Name:       "my-ing",
+			UID:        uid,
+			Controller: &isController,
+		}}
+	}
+
+	tests := map[string]struct {
+		issuerRef cmmeta.ObjectReference
+		owners    []metav1.OwnerReference
+		expTree   string
+	}{
+		"certificate without owner of a ClusterIssuer": {
+			issuerRef: cmmeta.ObjectReference{Name: "letsencrypt", Kind: cmapi.ClusterIssuerKind},
+			expTree: `Certificate/my-crt [Unknown]
+├── Secret/my-crt-tls [NotFound]
+└── ClusterIssuer/letsencrypt [Ready=True (ACMEAccountRegistered)]
+`,
+		},
+		"certificate created for an Ingress with a missing Issuer": {
+			issuerRef: cmmeta.ObjectReference{Name: "missing"},
+			owners:    ownedBy("ing"),
+			expTree: `Ingress/my-ing
+└── Certificate/my-crt [Unknown]
+    ├── Secret/my-crt-tls [NotFound]
+    └── Issuer/missing [NotFound]
+`,
+		},
+		"owner with a different UID is not found": {
+			issuerRef: cmmeta.ObjectReference{Name: "letsencrypt", Kind: cmapi.ClusterIssuerKind},
+			owners:    ownedBy("other"),
+			expTree: `Ingress/my-ing [NotFound]
+└── Certificate/my-crt [Unknown]
+    ├── Secret/my-crt-tls [NotFound]
+    └── ClusterIssuer/letsencrypt [Ready=True (ACMEAccountRegistered)]
+`,
+		},
+		"issuer of a third party API group": {
+			issuerRef: cmmeta.ObjectReference{Name: "pca", Kind: externalIssuerGVK.Kind, Group: externalIssuerGVK.Group},
+			expTree: `Certificate/my-crt [Unknown]
+├── Secret/my-crt-tls [NotFound]
+└── AWSPCAIssuer.awspca.cert-manager.io/pca [Ready=False (Unreachable)]
+`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-crt", Namespace: "default", UID: "crt", OwnerReferences: test.owners},
+				Spec:       cmapi.CertificateSpec{SecretName: "my-crt-tls", IssuerRef: test.issuerRef},
+			}
+			clients := graphClients{
+				CM:         cmfake.NewSimpleClientset(crt, clusterIssuer),
+				Kube:       kubefake.NewSimpleClientset(),
+				Dynamic:    dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), ingress, externalIssuer),
+				RESTMapper: mapper,
+			}
+
+			graph, err := buildGraph(context.TODO(), clients, crt)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if graph.String() != test.expTree {
+				t.Errorf("Unexpected graph; expected: \n%s\nactual: \n%s", test.expTree, graph.String())
+			}
+		})
+	}
+}
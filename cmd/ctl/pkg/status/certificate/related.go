@@ -0,0 +1,229 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+)
+
+// solverSelector selects the Pods, Services and Ingresses created by the
+// HTTP01 solver.
+const solverSelector = "acme.cert-manager.io/http01-solver=true"
+
+// resourceNode is a resource in the graph of resources that are involved in
+// issuing a Certificate.
+type resourceNode struct {
+	Kind  string
+	Name  string
+	Phase string
+
+	Children []*resourceNode
+}
+
+// buildRelatedTree returns the tree of resources that have been created for
+// crt: its Secrets, CertificateRequests and, for ACME issuers, the Orders,
+// Challenges and solver Pods, Services and Ingresses.
+func buildRelatedTree(ctx context.Context, cmClient cmclient.Interface, clientSet kubernetes.Interface, crt *cmapi.Certificate) (*resourceNode, error) {
+	ns := crt.Namespace
+	root := &resourceNode{Kind: cmapi.CertificateKind, Name: crt.Name, Phase: certificatePhase(crt)}
+
+	secretNames := []string{crt.Spec.SecretName}
+	if crt.Status.NextPrivateKeySecretName != nil {
+		secretNames = append(secretNames, *crt.Status.NextPrivateKeySecretName)
+	}
+	for _, name := range secretNames {
+		phase := "Exists"
+		if _, err := clientSet.CoreV1().Secrets(ns).Get(ctx, name, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+			phase = "NotFound"
+		} else if err != nil {
+			return nil, fmt.Errorf("error when getting Secret %q: %v", name, err)
+		}
+		root.Children = append(root.Children, &resourceNode{Kind: "Secret", Name: name, Phase: phase})
+	}
+
+	// nodes holds all nodes by UID, so that every resource can be attached
+	// to the node of the resource that controls it
+	nodes := map[types.UID]*resourceNode{crt.UID: root}
+	attach := func(obj metav1.Object, kind, phase string) {
+		ref := metav1.GetControllerOf(obj)
+		if ref == nil {
+			return
+		}
+		parent, ok := nodes[ref.UID]
+		if !ok {
+			return
+		}
+		node := &resourceNode{Kind: kind, Name: obj.GetName(), Phase: phase}
+		parent.Children = append(parent.Children, node)
+		nodes[obj.GetUID()] = node
+	}
+
+	reqs, err := cmClient.CertmanagerV1alpha2().CertificateRequests(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing CertificateRequest resources: %v", err)
+	}
+	for i := range reqs.Items {
+		req := &reqs.Items[i]
+		attach(req, cmapi.CertificateRequestKind, certificateRequestPhase(req))
+	}
+
+	orders, err := cmClient.AcmeV1alpha2().Orders(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing Order resources: %v", err)
+	}
+	for i := range orders.Items {
+		order := &orders.Items[i]
+		attach(order, "Order", acmePhase(string(order.Status.State)))
+	}
+
+	challenges, err := cmClient.AcmeV1alpha2().Challenges(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing Challenge resources: %v", err)
+	}
+	for i := range challenges.Items {
+		ch := &challenges.Items[i]
+		attach(ch, "Challenge", acmePhase(string(ch.Status.State)))
+	}
+
+	solverOpts := metav1.ListOptions{LabelSelector: solverSelector}
+	pods, err := clientSet.CoreV1().Pods(ns).List(ctx, solverOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error when listing solver Pods: %v", err)
+	}
+	for i := range pods.Items {
+		attach(&pods.Items[i], "Pod", string(pods.Items[i].Status.Phase))
+	}
+
+	services, err := clientSet.CoreV1().Services(ns).List(ctx, solverOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error when listing solver Services: %v", err)
+	}
+	for i := range services.Items {
+		attach(&services.Items[i], "Service", string(services.Items[i].Spec.Type))
+	}
+
+	ingresses, err := clientSet.ExtensionsV1beta1().Ingresses(ns).List(ctx, solverOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error when listing solver Ingresses: %v", err)
+	}
+	for i := range ingresses.Items {
+		attach(&ingresses.Items[i], "Ingress", "")
+	}
+
+	return root, nil
+}
+
+func certificatePhase(crt *cmapi.Certificate) string {
+	c := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionReady)
+	if c == nil {
+		return "Unknown"
+	}
+	return readyPhase(string(c.Status), c.Reason)
+}
+
+func certificateRequestPhase(req *cmapi.CertificateRequest) string {
+	c := apiutil.GetCertificateRequestCondition(req, cmapi.CertificateRequestConditionReady)
+	if c == nil {
+		return "Unknown"
+	}
+	return readyPhase(string(c.Status), c.Reason)
+}
+
+func readyPhase(status, reason string) string {
+	phase := "Ready=" + status
+	if reason != "" {
+		phase += " (" + reason + ")"
+	}
+	return phase
+}
+
+func acmePhase(state string) string {
+	if state == "" {
+		return "unknown"
+	}
+	return state
+}
+
+// String returns the tree rooted at n in a format similar to the tree command.
+func (n *resourceNode) String() string {
+	var b strings.Builder
+	b.WriteString(n.label() + "\n")
+	n.writeChildren(&b, "")
+	return b.String()
+}
+
+func (n *resourceNode) writeChildren(b *strings.Builder, prefix string) {
+	for i, child := range n.Children {
+		branch, indent := "├── ", "│   "
+		if i == len(n.Children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		b.WriteString(prefix + branch + child.label() + "\n")
+		child.writeChildren(b, prefix+indent)
+	}
+}
+
+func (n *resourceNode) label() string {
+	label := fmt.Sprintf("%s/%s", n.Kind, n.Name)
+	if n.Phase != "" {
+		label += " [" + n.Phase + "]"
+	}
+	return label
+}
+
+// DOT returns the tree rooted at n as a Graphviz DOT digraph.
+func (n *resourceNode) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph {\n")
+	b.WriteString("  node [shape=box];\n")
+	n.writeDOT(&b)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func (n *resourceNode) writeDOT(b *strings.Builder) {
+	label := fmt.Sprintf("%s\\n%s", n.Kind, n.Name)
+	if n.Phase != "" {
+		label += fmt.Sprintf("\\n%s", n.Phase)
+	}
+	fmt.Fprintf(b, "  %s [label=%s];\n", n.dotID(), quoteDOT(label))
+	for _, child := range n.Children {
+		child.writeDOT(b)
+		fmt.Fprintf(b, "  %s -> %s;\n", n.dotID(), child.dotID())
+	}
+}
+
+func (n *resourceNode) dotID() string {
+	return quoteDOT(n.Kind + "/" + n.Name)
+}
+
+// quoteDOT quotes s as a DOT string. Backslashes are kept as they are, so that
+// escape sequences like \n can be used in labels.
+func quoteDOT(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+)
+
+func TestBuildRelatedTree(t *testing.T) {
+	controlled := func(name string, uid, owner types.UID, labels map[string]string) metav1.ObjectMeta {
+		isController := true
+		meta := metav1.ObjectMeta{Name: name, Namespace: "default", UID: uid, Labels: labels}
+		if owner != "" {
+			meta.OwnerReferences = []metav1.OwnerReference{{UID: owner, Controller: &isController}}
+		}
+		return meta
+	}
+	solverLabels := map[string]string{"acme.cert-manager.io/http01-solver": "true"}
+
+	crt := &cmapi.Certificate{
+		ObjectMeta: controlled("my-crt", "crt", "", nil),
+		Spec:       cmapi.CertificateSpec{SecretName: "my-crt-tls"},
+		Status: cmapi.CertificateStatus{Conditions: []cmapi.CertificateCondition{
+			{Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionFalse, Reason: "DoesNotExist"},
+		}},
+	}
+	cr := &cmapi.CertificateRequest{
+		ObjectMeta: controlled("my-crt-1", "cr", "crt", nil),
+		Status: cmapi.CertificateRequestStatus{Conditions: []cmapi.CertificateRequestCondition{
+			{Type: cmapi.CertificateRequestConditionReady, Status: cmmeta.ConditionFalse, Reason: "Pending"},
+		}},
+	}
+	otherCR := &cmapi.CertificateRequest{ObjectMeta: controlled("other-1", "other-cr", "other", nil)}
+	order := &cmacme.Order{
+		ObjectMeta: controlled("my-crt-1-1", "order", "cr", nil),
+		Status:     cmacme.OrderStatus{State: cmacme.Pending},
+	}
+	challenge := &cmacme.Challenge{ObjectMeta: controlled("my-crt-1-1-1", "challenge", "order", nil)}
+	pod := &corev1.Pod{
+		ObjectMeta: controlled("cm-acme-http-solver-abcde", "pod", "challenge", solverLabels),
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	svc := &corev1.Service{
+		ObjectMeta: controlled("cm-acme-http-solver-fghij", "svc", "challenge", solverLabels),
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort},
+	}
+
+	cmClient := cmfake.NewSimpleClientset(crt, cr, otherCR, order, challenge)
+	kubeClient := kubefake.NewSimpleClientset(pod, svc)
+
+	tree, err := buildRelatedTree(context.TODO(), cmClient, kubeClient, crt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expTree := `Certificate/my-crt [Ready=False (DoesNotExist)]
+├── Secret/my-crt-tls [NotFound]
+└── CertificateRequest/my-crt-1 [Ready=False (Pending)]
+    └── Order/my-crt-1-1 [pending]
+        └── Challenge/my-crt-1-1-1 [unknown]
+            ├── Pod/cm-acme-http-solver-abcde [Running]
+            └── Service/cm-acme-http-solver-fghij [NodePort]
+`
+	if tree.String() != expTree {
+		t.Errorf("Unexpected tree; expected: \n%s\nactual: \n%s", expTree, tree.String())
+	}
+
+	expDOT := `digraph {
+  node [shape=box];
+  "Certificate/my-crt" [label="Certificate\nmy-crt\nReady=False (DoesNotExist)"];
+  "Secret/my-crt-tls" [label="Secret\nmy-crt-tls\nNotFound"];
+  "Certificate/my-crt" -> "Secret/my-crt-tls";
+  "CertificateRequest/my-crt-1" [label="CertificateRequest\nmy-crt-1\nReady=False (Pending)"];
+  "Order/my-crt-1-1" [label="Order\nmy-crt-1-1\npending"];
+  "Challenge/my-crt-1-1-1" [label="Challenge\nmy-crt-1-1-1\nunknown"];
+  "Pod/cm-acme-http-solver-abcde" [label="Pod\ncm-acme-http-solver-abcde\nRunning"];
+  "Challenge/my-crt-1-1-1" -> "Pod/cm-acme-http-solver-abcde";
+  "Service/cm-acme-http-solver-fghij" [label="Service\ncm-acme-http-solver-fghij\nNodePort"];
+  "Challenge/my-crt-1-1-1" -> "Service/cm-acme-http-solver-fghij";
+  "Order/my-crt-1-1" -> "Challenge/my-crt-1-1-1";
+  "CertificateRequest/my-crt-1" -> "Order/my-crt-1-1";
+  "Certificate/my-crt" -> "CertificateRequest/my-crt-1";
+}
+`
+	if tree.DOT() != expDOT {
+		t.Errorf("Unexpected DOT output; expected: \n%s\nactual: \n%s", expDOT, tree.DOT())
+	}
+}
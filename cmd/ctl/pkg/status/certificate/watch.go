@@ -0,0 +1,378 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	"github.com/jetstack/cert-manager/pkg/ctl/output"
+)
+
+// watchFunc opens a new watch. It is called again whenever the API server
+// closes a watch, which happens periodically for long running watches.
+type watchFunc func(ctx context.Context) (watch.Interface, error)
+
+// watchCertificate watches crt, the CertificateRequests, Orders and Challenges
+// created for it, and the Events of all of these, and prints every state
+// transition to out. It returns once the Certificate is Ready and not being
+// issued, or when ctx is cancelled. If stopOnFailure is true, it also returns
+// an *issuanceFailedError once the issuance of the Certificate failed.
+func watchCertificate(ctx context.Context, out io.Writer, cmClient cmclient.Interface, clientSet kubernetes.Interface, crt *cmapi.Certificate, stopOnFailure bool) error {
+	ns := crt.Namespace
+	watchers := []watchFunc{
+		func(ctx context.Context) (watch.Interface, error) {
+			return cmClient.CertmanagerV1alpha2().Certificates(ns).Watch(ctx, metav1.ListOptions{
+				FieldSelector: fields.OneTermEqualSelector("metadata.name", crt.Name).String(),
+			})
+		},
+		func(ctx context.Context) (watch.Interface, error) {
+			return cmClient.CertmanagerV1alpha2().CertificateRequests(ns).Watch(ctx, metav1.ListOptions{})
+		},
+		func(ctx context.Context) (watch.Interface, error) {
+			return cmClient.AcmeV1alpha2().Orders(ns).Watch(ctx, metav1.ListOptions{})
+		},
+		func(ctx context.Context) (watch.Interface, error) {
+			return cmClient.AcmeV1alpha2().Challenges(ns).Watch(ctx, metav1.ListOptions{})
+		},
+		func(ctx context.Context) (watch.Interface, error) {
+			return clientSet.CoreV1().Events(ns).Watch(ctx, metav1.ListOptions{})
+		},
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events := make(chan watch.Event)
+	errs := make(chan error, len(watchers))
+	for _, fn := range watchers {
+		go func(fn watchFunc) {
+			if err := forwardEvents(ctx, fn, events); err != nil {
+				errs <- err
+			}
+		}(fn)
+	}
+
+	w := newCertificateWatcher(out, crt, time.Now)
+	w.stopOnFailure = stopOnFailure
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			return err
+		case ev := <-events:
+			if w.handle(ev) {
+				return w.failure
+			}
+		}
+	}
+}
+
+// forwardEvents sends all events of the watch opened by fn to events,
+// reopening the watch whenever it is closed, until ctx is cancelled.
+func forwardEvents(ctx context.Context, fn watchFunc, events chan<- watch.Event) error {
+	for {
+		w, err := fn(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("error when opening watch: %v", err)
+		}
+
+		for ev := range w.ResultChan() {
+			if ev.Type == watch.Error {
+				continue
+			}
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				w.Stop()
+				return nil
+			}
+		}
+		w.Stop()
+
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// certificateWatcher keeps track of the resources that belong to a
+// Certificate and their last observed state, so that only changes are printed.
+type certificateWatcher struct {
+	out   io.Writer
+	crt   *cmapi.Certificate
+	clock func() time.Time
+	start time.Time
+
+	// owned contains the UIDs of all resources that belong to the Certificate
+	owned map[types.UID]bool
+	// pending holds the latest version of objects whose owner has not been
+	// observed yet, keyed by the UID of the owner. Watches are independent,
+	// so an Order may be observed before the CertificateRequest that created
+	// it.
+	pending map[types.UID]map[types.UID]runtime.Object
+	// states holds the last printed state of each resource
+	states map[types.UID]string
+
+	// stopOnFailure makes handle return true once the issuance of the
+	// Certificate failed, which is then recorded in failure
+	stopOnFailure bool
+	failure       error
+}
+
+// issuanceFailedError is returned by watchCertificate when the issuance of
+// the Certificate failed.
+type issuanceFailedError struct {
+	name    string
+	reason  string
+	message string
+}
+
+func (e *issuanceFailedError) Error() string {
+	return fmt.Sprintf("issuance of Certificate %q failed: %s: %s", e.name, e.reason, e.message)
+}
+
+func newCertificateWatcher(out io.Writer, crt *cmapi.Certificate, clock func() time.Time) *certificateWatcher {
+	return &certificateWatcher{
+		out:     out,
+		crt:     crt,
+		clock:   clock,
+		start:   clock().Truncate(time.Second),
+		owned:   map[types.UID]bool{crt.UID: true},
+		pending: make(map[types.UID]map[types.UID]runtime.Object),
+		states:  make(map[types.UID]string),
+	}
+}
+
+// handle processes a single watch event and returns true once the
+// Certificate has become Ready, or its issuance failed if stopOnFailure is
+// set.
+func (w *certificateWatcher) handle(ev watch.Event) bool {
+	if ev.Type == watch.Deleted {
+		if obj, ok := ev.Object.(metav1.Object); ok && w.owned[obj.GetUID()] {
+			w.printf("%s %q deleted", kindOf(ev.Object), obj.GetName())
+			delete(w.states, obj.GetUID())
+		}
+		return false
+	}
+	return w.handleObject(ev.Object)
+}
+
+func (w *certificateWatcher) handleObject(obj runtime.Object) bool {
+	switch o := obj.(type) {
+	case *cmapi.Certificate:
+		if o.UID != w.crt.UID {
+			return false
+		}
+		w.transition(o, cmapi.CertificateKind, certificateState(o))
+		if isReady(o) {
+			return true
+		}
+		if w.stopOnFailure {
+			// The issuing controller sets the Issuing condition to False
+			// when the CertificateRequest failed
+			issuing := apiutil.GetCertificateCondition(o, cmapi.CertificateConditionIssuing)
+			if issuing != nil && issuing.Status == cmmeta.ConditionFalse {
+				w.failure = &issuanceFailedError{name: o.Name, reason: issuing.Reason, message: issuing.Message}
+				return true
+			}
+		}
+		return false
+
+	case *cmapi.CertificateRequest, *cmacme.Order, *cmacme.Challenge:
+		meta := obj.(metav1.Object)
+		if !w.isOwned(meta) {
+			// Keep the object around in case its owner shows up later
+			for _, ref := range meta.GetOwnerReferences() {
+				w.addPending(ref.UID, meta.GetUID(), obj)
+			}
+			return false
+		}
+		w.owned[meta.GetUID()] = true
+		w.transition(meta, kindOf(obj), resourceState(obj))
+
+		// Replay objects and Events that were observed before this one
+		pending := w.pending[meta.GetUID()]
+		delete(w.pending, meta.GetUID())
+		for _, p := range pending {
+			w.handleObject(p)
+		}
+
+	case *corev1.Event:
+		if eventTime(o).Before(w.start) {
+			return false
+		}
+		if !w.owned[o.InvolvedObject.UID] {
+			w.addPending(o.InvolvedObject.UID, o.UID, o)
+			return false
+		}
+		// Events are aggregated, so the same Event is observed again with
+		// an increased count every time it reoccurs
+		state := fmt.Sprintf("%d", o.Count)
+		if w.states[o.UID] == state {
+			return false
+		}
+		w.states[o.UID] = state
+		w.printf("Event %s %s %q: %s: %s", o.Type, o.InvolvedObject.Kind, o.InvolvedObject.Name, o.Reason, strings.TrimSpace(o.Message))
+	}
+
+	return false
+}
+
+// isOwned returns true if obj is owned by the Certificate or any of the
+// resources created for it.
+func (w *certificateWatcher) isOwned(obj metav1.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if w.owned[ref.UID] {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *certificateWatcher) addPending(owner, uid types.UID, obj runtime.Object) {
+	if w.pending[owner] == nil {
+		w.pending[owner] = make(map[types.UID]runtime.Object)
+	}
+	w.pending[owner][uid] = obj
+}
+
+// transition prints the state of obj if it is different to the last state
+// that was printed for it.
+func (w *certificateWatcher) transition(obj metav1.Object, kind, state string) {
+	if w.states[obj.GetUID()] == state {
+		return
+	}
+	w.states[obj.GetUID()] = state
+	w.printf("%s %q: %s", kind, obj.GetName(), state)
+}
+
+func (w *certificateWatcher) printf(format string, args ...interface{}) {
+	fmt.Fprintf(w.out, "%s  %s\n", w.clock().Format("15:04:05"), fmt.Sprintf(format, args...))
+}
+
+// isReady returns true if crt is Ready and not currently being issued.
+func isReady(crt *cmapi.Certificate) bool {
+	ready := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionReady)
+	if ready == nil || ready.Status != cmmeta.ConditionTrue {
+		return false
+	}
+	issuing := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuing)
+	return issuing == nil || issuing.Status != cmmeta.ConditionTrue
+}
+
+func certificateState(crt *cmapi.Certificate) string {
+	var conds []string
+	for _, c := range crt.Status.Conditions {
+		conds = append(conds, formatCondition(string(c.Type), c.Status, c.Reason, c.Message))
+	}
+	if len(conds) == 0 {
+		return "no conditions"
+	}
+	return strings.Join(conds, ", ")
+}
+
+func resourceState(obj runtime.Object) string {
+	switch o := obj.(type) {
+	case *cmapi.CertificateRequest:
+		var conds []string
+		for _, c := range o.Status.Conditions {
+			conds = append(conds, formatCondition(string(c.Type), c.Status, c.Reason, c.Message))
+		}
+		if len(conds) == 0 {
+			return "no conditions"
+		}
+		return strings.Join(conds, ", ")
+	case *cmacme.Order:
+		return formatACMEState(o.Status.State, o.Status.Reason)
+	case *cmacme.Challenge:
+		state := formatACMEState(o.Status.State, o.Status.Reason)
+		if o.Status.Presented {
+			state = "presented, " + state
+		}
+		return state
+	}
+	return ""
+}
+
+func formatCondition(condType string, status cmmeta.ConditionStatus, reason, message string) string {
+	s := fmt.Sprintf("%s=%s", condType, output.ConditionStatus(condType, string(status)))
+	if reason != "" {
+		s += fmt.Sprintf(" (%s)", reason)
+	}
+	if message != "" {
+		s += fmt.Sprintf(": %s", message)
+	}
+	return s
+}
+
+func formatACMEState(state cmacme.State, reason string) string {
+	s := string(state)
+	if s == "" {
+		s = "unknown"
+	}
+	if reason != "" {
+		s += ": " + reason
+	}
+	return "state " + s
+}
+
+func kindOf(obj runtime.Object) string {
+	switch obj.(type) {
+	case *cmapi.Certificate:
+		return cmapi.CertificateKind
+	case *cmapi.CertificateRequest:
+		return cmapi.CertificateRequestKind
+	case *cmacme.Order:
+		return "Order"
+	case *cmacme.Challenge:
+		return "Challenge"
+	}
+	return reflect.TypeOf(obj).Elem().Name()
+}
+
+// eventTime returns the time an Event was last observed at.
+func eventTime(ev *corev1.Event) time.Time {
+	if !ev.LastTimestamp.IsZero() {
+		return ev.LastTimestamp.Time
+	}
+	if !ev.EventTime.IsZero() {
+		return ev.EventTime.Time
+	}
+	return ev.CreationTimestamp.Time
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestCertificateWatcher(t *testing.T) {
+	now := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	objectMeta := func(name string, uid, owner types.UID) metav1.ObjectMeta {
+		meta := metav1.ObjectMeta{Name: name, Namespace: "default", UID: uid}
+		if owner != "" {
+			meta.OwnerReferences = []metav1.OwnerReference{{UID: owner}}
+		}
+		return meta
+	}
+	crt := func(ready cmmeta.ConditionStatus) *cmapi.Certificate {
+		return &cmapi.Certificate{
+			ObjectMeta: objectMeta("my-crt", "crt", ""),
+			Status: cmapi.CertificateStatus{Conditions: []cmapi.CertificateCondition{
+				{Type: cmapi.CertificateConditionReady, Status: ready},
+			}},
+		}
+	}
+	cr := &cmapi.CertificateRequest{ObjectMeta: objectMeta("my-crt-1", "cr", "crt")}
+	order := func(state cmacme.State) *cmacme.Order {
+		return &cmacme.Order{
+			ObjectMeta: objectMeta("my-crt-1-1", "order", "cr"),
+			Status:     cmacme.OrderStatus{State: state},
+		}
+	}
+	event := &corev1.Event{
+		ObjectMeta:     objectMeta("my-crt-1-1.1", "event", ""),
+		InvolvedObject: corev1.ObjectReference{Kind: "Order", Name: "my-crt-1-1", UID: "order"},
+		Type:           corev1.EventTypeNormal,
+		Reason:         "Created",
+		Message:        "Created Challenge resource",
+		Count:          1,
+		LastTimestamp:  metav1.NewTime(now),
+	}
+	unrelated := &cmacme.Order{ObjectMeta: objectMeta("other", "other", "other-cr")}
+	failed := crt(cmmeta.ConditionFalse)
+	failed.Status.Conditions = append(failed.Status.Conditions, cmapi.CertificateCondition{
+		Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionFalse, Reason: "Failed", Message: "CA is unavailable",
+	})
+
+	tests := map[string]struct {
+		events        []runtime.Object
+		stopOnFailure bool
+		expReady      bool
+		expFailure    bool
+		expOutput     string
+	}{
+		"only changes are printed": {
+			events: []runtime.Object{crt(cmmeta.ConditionFalse), crt(cmmeta.ConditionFalse), cr, order(cmacme.Pending), order(cmacme.Pending), unrelated},
+			expOutput: `12:00:00  Certificate "my-crt": Ready=False
+12:00:00  CertificateRequest "my-crt-1": no conditions
+12:00:00  Order "my-crt-1-1": state pending
+`,
+		},
+		"resources observed before their owner are printed once the owner is observed": {
+			events: []runtime.Object{event, order(cmacme.Pending), order(cmacme.Valid), cr},
+			expOutput: `12:00:00  CertificateRequest "my-crt-1": no conditions
+12:00:00  Order "my-crt-1-1": state valid
+12:00:00  Event Normal Order "my-crt-1-1": Created: Created Challenge resource
+`,
+		},
+		"returns true once the Certificate is Ready": {
+			events:   []runtime.Object{crt(cmmeta.ConditionFalse), crt(cmmeta.ConditionTrue)},
+			expReady: true,
+			expOutput: `12:00:00  Certificate "my-crt": Ready=False
+12:00:00  Certificate "my-crt": Ready=True
+`,
+		},
+		"failed issuance is printed but does not stop watching": {
+			events: []runtime.Object{failed},
+			expOutput: `12:00:00  Certificate "my-crt": Ready=False, Issuing=False (Failed): CA is unavailable
+`,
+		},
+		"returns true and records the failure once the issuance failed with stopOnFailure": {
+			events:        []runtime.Object{crt(cmmeta.ConditionFalse), failed},
+			stopOnFailure: true,
+			expReady:      true,
+			expFailure:    true,
+			expOutput: `12:00:00  Certificate "my-crt": Ready=False
+12:00:00  Certificate "my-crt": Ready=False, Issuing=False (Failed): CA is unavailable
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			w := newCertificateWatcher(out, crt(cmmeta.ConditionFalse), clock)
+			w.stopOnFailure = test.stopOnFailure
+
+			ready := false
+			for _, obj := range test.events {
+				ready = w.handle(watch.Event{Type: watch.Modified, Object: obj})
+			}
+
+			if ready != test.expReady {
+				t.Errorf("expected ready to be %t but got %t", test.expReady, ready)
+			}
+			if (w.failure != nil) != test.expFailure {
+				t.Errorf("expected failure: %t, got: %v", test.expFailure, w.failure)
+			}
+			if out.String() != test.expOutput {
+				t.Errorf("Unexpected output; expected: \n%s\nactual: \n%s", test.expOutput, out.String())
+			}
+		})
+	}
+}
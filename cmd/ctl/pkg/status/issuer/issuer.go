@@ -0,0 +1,247 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/completion"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	ctlstatus "github.com/jetstack/cert-manager/pkg/ctl/status"
+)
+
+var (
+	issuerLong = templates.LongDesc(i18n.T(`
+Get details about the current status of a cert-manager Issuer resource.
+
+The conditions of the Issuer are printed. For ACME Issuers, the account URL, contact email and external
+account binding stored in the Issuer are printed too, along with whether the Secret containing the account
+private key exists and contains a valid RSA private key. Unless --skip-account-lookup is set, the account
+private key is used to look up the account registered with the ACME server.
+
+With --acme-account, the registered account is inspected in depth: its orders URL is printed and its URI,
+status and contact are compared with the state recorded in the Issuer. This detects an account private key
+that does not belong to the recorded account, for example after restoring a cluster from a backup.`))
+
+	issuerExample = templates.Examples(i18n.T(`
+# Query status of Issuer with name 'my-issuer' in namespace 'my-namespace'
+kubectl cert-manager status issuer my-issuer --namespace my-namespace
+`))
+
+	clusterIssuerLong = templates.LongDesc(i18n.T(`
+Get details about the current status of a cert-manager ClusterIssuer resource.
+
+The conditions of the ClusterIssuer are printed. For ACME ClusterIssuers, the account URL, contact email and
+external account binding stored in the ClusterIssuer are printed too, along with whether the Secret containing
+the account private key exists and contains a valid RSA private key. Unless --skip-account-lookup is set, the
+account private key is used to look up the account registered with the ACME server.
+
+With --acme-account, the registered account is inspected in depth: its orders URL is printed and its URI,
+status and contact are compared with the state recorded in the ClusterIssuer. This detects an account private
+key that does not belong to the recorded account, for example after restoring a cluster from a backup.
+
+The Secrets referenced by ClusterIssuers are looked up in the --cluster-resource-namespace, which should be set
+to the value of the flag of the same name of the cert-manager controller.`))
+
+	clusterIssuerExample = templates.Examples(i18n.T(`
+# Query status of ClusterIssuer with name 'letsencrypt'
+kubectl cert-manager status clusterissuer letsencrypt
+
+# Query status of ClusterIssuer 'letsencrypt' without contacting the ACME server
+kubectl cert-manager status clusterissuer letsencrypt --skip-account-lookup
+
+# Compare the account registered with the ACME server with the state recorded in ClusterIssuer 'letsencrypt'
+kubectl cert-manager status clusterissuer letsencrypt --acme-account
+`))
+)
+
+// Options is a struct to support status issuer and status clusterissuer
+// commands
+type Options struct {
+	CMClient   cmclient.Interface
+	KubeClient kubernetes.Interface
+	RESTConfig *restclient.Config
+	// The Namespace that the Issuer to be queried about resides in.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace string
+
+	// ClusterScoped is true for the status clusterissuer command
+	ClusterScoped bool
+	// ClusterResourceNamespace is the namespace the Secrets referenced by
+	// ClusterIssuers are stored in
+	ClusterResourceNamespace string
+	// SkipAccountLookup disables looking up the ACME account registered with
+	// the ACME server
+	SkipAccountLookup bool
+	// InspectACMEAccount compares the ACME account registered with the ACME
+	// server with the state recorded in the issuer
+	InspectACMEAccount bool
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams, clusterScoped bool) *Options {
+	return &Options{
+		IOStreams:     ioStreams,
+		ClusterScoped: clusterScoped,
+	}
+}
+
+// NewCmdStatusIssuer returns a cobra command for status issuer
+func NewCmdStatusIssuer(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams, false)
+	cmd := &cobra.Command{
+		Use:     "issuer",
+		Short:   "Get details about the current status of a cert-manager Issuer resource",
+		Long:    issuerLong,
+		Example: issuerExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		ValidArgsFunction: completion.IssuerNames(factory),
+	}
+	o.addFlags(cmd)
+	return cmd
+}
+
+// NewCmdStatusClusterIssuer returns a cobra command for status clusterissuer
+func NewCmdStatusClusterIssuer(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams, true)
+	cmd := &cobra.Command{
+		Use:     "clusterissuer",
+		Short:   "Get details about the current status of a cert-manager ClusterIssuer resource",
+		Long:    clusterIssuerLong,
+		Example: clusterIssuerExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run(args))
+		},
+		ValidArgsFunction: completion.ClusterIssuerNames(factory),
+	}
+	o.addFlags(cmd)
+	cmd.Flags().StringVar(&o.ClusterResourceNamespace, "cluster-resource-namespace", "kube-system",
+		"Namespace the Secrets referenced by ClusterIssuers are stored in")
+	return cmd
+}
+
+func (o *Options) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.SkipAccountLookup, "skip-account-lookup", o.SkipAccountLookup,
+		"Do not look up the account registered with the ACME server of ACME issuers")
+	cmd.Flags().BoolVar(&o.InspectACMEAccount, "acme-account", o.InspectACMEAccount,
+		"Compare the account registered with the ACME server with the state recorded in ACME issuers")
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	kind := o.kind()
+	if len(args) < 1 {
+		return fmt.Errorf("the name of the %s has to be provided as argument", kind)
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("only one argument can be passed in: the name of the %s", kind)
+	}
+	if o.SkipAccountLookup && o.InspectACMEAccount {
+		return errors.New("--acme-account cannot be used together with --skip-account-lookup")
+	}
+	if o.ClusterScoped && o.ClusterResourceNamespace == "" {
+		return errors.New("--cluster-resource-namespace must not be empty")
+	}
+	return nil
+}
+
+// Complete takes the factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory) error {
+	var err error
+
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	o.KubeClient, err = kubernetes.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes status issuer or status clusterissuer command
+func (o *Options) Run(args []string) error {
+	ctx := context.TODO()
+	name := args[0]
+
+	var issuer cmapi.GenericIssuer
+	var err error
+	if o.ClusterScoped {
+		issuer, err = o.CMClient.CertmanagerV1alpha2().ClusterIssuers().Get(ctx, name, metav1.GetOptions{})
+	} else {
+		issuer, err = o.CMClient.CertmanagerV1alpha2().Issuers(o.Namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("error when getting %s resource: %v", o.kind(), err)
+	}
+
+	var fetchAccount ctlstatus.ACMEAccountFetcher
+	if !o.SkipAccountLookup {
+		fetchAccount = ctlstatus.FetchACMEAccount
+	}
+
+	status := ctlstatus.CollectStatusForIssuer(ctx, ctlstatus.Clients{
+		Kube: o.KubeClient,
+		CM:   o.CMClient,
+	}, issuer, o.ClusterResourceNamespace, fetchAccount)
+	if o.InspectACMEAccount && status.ACMEAccount != nil {
+		ctlstatus.InspectACMEAccount(status.ACMEAccount)
+	}
+	fmt.Fprint(o.Out, status.String())
+
+	return nil
+}
+
+func (o *Options) kind() string {
+	if o.ClusterScoped {
+		return cmapi.ClusterIssuerKind
+	}
+	return cmapi.IssuerKind
+}
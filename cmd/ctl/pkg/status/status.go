@@ -22,16 +22,19 @@ import (
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
 	"github.com/jetstack/cert-manager/cmd/ctl/pkg/status/certificate"
+	"github.com/jetstack/cert-manager/cmd/ctl/pkg/status/issuer"
 )
 
-func NewCmdStatus(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+func NewCmdStatus(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory, configFlags *genericclioptions.ConfigFlags, stopCh <-chan struct{}) *cobra.Command {
 	cmds := &cobra.Command{
 		Use:   "status",
 		Short: "Get details on current status of cert-manager resources",
-		Long:  `Get details on current status of cert-manager resources, e.g. Certificate`,
+		Long:  `Get details on current status of cert-manager resources, e.g. Certificate, Issuer or ClusterIssuer`,
 	}
 
-	cmds.AddCommand(certificate.NewCmdStatusCert(ioStreams, factory))
+	cmds.AddCommand(certificate.NewCmdStatusCert(ioStreams, factory, configFlags, stopCh))
+	cmds.AddCommand(issuer.NewCmdStatusIssuer(ioStreams, factory))
+	cmds.AddCommand(issuer.NewCmdStatusClusterIssuer(ioStreams, factory))
 
 	return cmds
 }
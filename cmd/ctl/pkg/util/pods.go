@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodsMountingSecret returns the sorted names of the running Pods that
+// mount the Secret with the given name as a volume, directly or projected.
+func PodsMountingSecret(pods []corev1.Pod, secretName string) []string {
+	var names []string
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if mountsSecret(pod.Spec.Volumes, secretName) {
+			names = append(names, pod.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WorkloadsMountingSecret returns the sorted workloads of the running Pods
+// that mount the Secret with the given name, as '<kind>/<name>' of the
+// controller of each Pod. Pods of a ReplicaSet that is controlled by a
+// Deployment are attributed to the Deployment, and Pods without a controller
+// are returned as 'Pod/<name>'. The Pods and ReplicaSets must be of the
+// namespace of the Secret.
+func WorkloadsMountingSecret(pods []corev1.Pod, replicaSets []appsv1.ReplicaSet, secretName string) []string {
+	rsOwners := make(map[string]*metav1.OwnerReference)
+	for i := range replicaSets {
+		rsOwners[replicaSets[i].Name] = metav1.GetControllerOf(&replicaSets[i])
+	}
+
+	seen := make(map[string]bool)
+	var workloads []string
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != corev1.PodRunning || !mountsSecret(pod.Spec.Volumes, secretName) {
+			continue
+		}
+
+		workload := "Pod/" + pod.Name
+		if owner := metav1.GetControllerOf(pod); owner != nil {
+			workload = owner.Kind + "/" + owner.Name
+			if rsOwner := rsOwners[owner.Name]; owner.Kind == "ReplicaSet" && rsOwner != nil {
+				workload = rsOwner.Kind + "/" + rsOwner.Name
+			}
+		}
+		if !seen[workload] {
+			seen[workload] = true
+			workloads = append(workloads, workload)
+		}
+	}
+	sort.Strings(workloads)
+	return workloads
+}
+
+func mountsSecret(volumes []corev1.Volume, secretName string) bool {
+	for _, volume := range volumes {
+		if volume.Secret != nil && volume.Secret.SecretName == secretName {
+			return true
+		}
+		if volume.Projected == nil {
+			continue
+		}
+		for _, source := range volume.Projected.Sources {
+			if source.Secret != nil && source.Secret.Name == secretName {
+				return true
+			}
+		}
+	}
+	return false
+}
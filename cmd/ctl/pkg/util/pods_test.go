@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodsMountingSecret(t *testing.T) {
+	pod := func(name string, phase corev1.PodPhase, volume corev1.VolumeSource) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.PodSpec{Volumes: []corev1.Volume{{Name: "tls", VolumeSource: volume}}},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	secretVolume := func(name string) corev1.VolumeSource {
+		return corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: name}}
+	}
+	projectedVolume := func(name string) corev1.VolumeSource {
+		return corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+			{Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: name}}},
+		}}}
+	}
+
+	tests := map[string]struct {
+		pods []corev1.Pod
+		exp  []string
+	}{
+		"no pods": {},
+		"running pods mounting the secret": {
+			pods: []corev1.Pod{
+				pod("b", corev1.PodRunning, projectedVolume("tls")),
+				pod("a", corev1.PodRunning, secretVolume("tls")),
+			},
+			exp: []string{"a", "b"},
+		},
+		"running pod mounting another secret": {
+			pods: []corev1.Pod{pod("a", corev1.PodRunning, secretVolume("other"))},
+		},
+		"completed pod mounting the secret": {
+			pods: []corev1.Pod{pod("a", corev1.PodSucceeded, secretVolume("tls"))},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := PodsMountingSecret(test.pods, "tls"); !reflect.DeepEqual(got, test.exp) {
+				t.Errorf("expected pods %v, got %v", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestWorkloadsMountingSecret(t *testing.T) {
+	controlledBy := func(kind, name string) []metav1.OwnerReference {
+		controller := true
+		return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+	}
+	pod := func(name string, owners []metav1.OwnerReference, secretName string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, OwnerReferences: owners},
+			Spec: corev1.PodSpec{Volumes: []corev1.Volume{{Name: "tls", VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: secretName},
+			}}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	pods := []corev1.Pod{
+		pod("web-5d4f8-a", controlledBy("ReplicaSet", "web-5d4f8"), "tls"),
+		pod("web-5d4f8-b", controlledBy("ReplicaSet", "web-5d4f8"), "tls"),
+		pod("db-0", controlledBy("StatefulSet", "db"), "tls"),
+		pod("debug", nil, "tls"),
+		pod("other", nil, "other-tls"),
+	}
+	replicaSets := []appsv1.ReplicaSet{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-5d4f8", OwnerReferences: controlledBy("Deployment", "web")}},
+	}
+
+	exp := []string{"Deployment/web", "Pod/debug", "StatefulSet/db"}
+	if got := WorkloadsMountingSecret(pods, replicaSets, "tls"); !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected workloads %v, got %v", exp, got)
+	}
+}
@@ -17,13 +17,18 @@ limitations under the License.
 package util
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 )
 
 // FetchCertificateFromCR fetches the x509 certificate from a CR and stores the certificate in file specified by certFilename.
@@ -45,3 +50,36 @@ func FetchCertificateFromCR(req *cmapiv1alpha2.CertificateRequest, certFileName
 
 	return nil
 }
+
+// PendingExternalSigningRequest returns the CertificateRequest created for the
+// next issuance of the Certificate, which is to be signed outside of
+// cert-manager. Returns an error if the Certificate is not signed externally
+// or no such CertificateRequest exists.
+func PendingExternalSigningRequest(ctx context.Context, cmClient cmclient.Interface, crt *cmapiv1alpha2.Certificate) (*cmapiv1alpha2.CertificateRequest, error) {
+	if !crt.Spec.ExternalSigning {
+		return nil, fmt.Errorf("Certificate %s/%s is not signed externally, spec.externalSigning is not set", crt.Namespace, crt.Name)
+	}
+
+	// revisions begin from 1
+	nextRevision := 1
+	if crt.Status.Revision != nil {
+		nextRevision = *crt.Status.Revision + 1
+	}
+
+	reqs, err := cmClient.CertmanagerV1alpha2().CertificateRequests(crt.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing CertificateRequest resources: %w", err)
+	}
+	for i := range reqs.Items {
+		req := &reqs.Items[i]
+		if !metav1.IsControlledBy(req, crt) || !apiutil.IsExternalSigningRequest(req) {
+			continue
+		}
+		if req.Annotations[cmapiv1alpha2.CertificateRequestRevisionAnnotationKey] != strconv.Itoa(nextRevision) {
+			continue
+		}
+		return req, nil
+	}
+
+	return nil, fmt.Errorf("no CertificateRequest to be signed externally found for revision %d of Certificate %s/%s, check that an issuance is in progress", nextRevision, crt.Namespace, crt.Name)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/jetstack/cert-manager/pkg/webhook"
+)
+
+var (
+	example = templates.Examples(i18n.T(`
+		# Validate the cert-manager resources in 'cert.yaml'
+		kubectl cert-manager validate -f cert.yaml
+
+		# Validate all manifests in the 'manifests' directory and its subdirectories
+		kubectl cert-manager validate -R -f ./manifests
+
+		# Validate a kustomize overlay under the current directory
+		kubectl cert-manager validate -k .`))
+
+	longDesc = templates.LongDesc(i18n.T(`
+Validate cert-manager resources in config files without contacting a cluster.
+
+The same validations are run as by the cert-manager webhook when resources are
+created, which makes it possible to check manifests before applying them to
+clusters that do not run the webhook. Resources that are not cert-manager
+resources are ignored.
+
+The command fails if any of the resources are invalid. Validations that depend
+on the state of the cluster, such as the previous version of a resource or the
+user creating it, are not run.`))
+)
+
+// Options is a struct to support validate command
+type Options struct {
+	resource.FilenameOptions
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdValidate returns a cobra command for validating cert-manager resources
+func NewCmdValidate(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "validate",
+		Short:                 "Validate cert-manager resources in config files",
+		Long:                  longDesc,
+		Example:               example,
+		DisableFlagsInUseLine: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Complete())
+			cmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmdutil.AddFilenameOptionFlags(cmd, &o.FilenameOptions, "Path to a file containing cert-manager resources to be validated.")
+
+	return cmd
+}
+
+// Complete collects information required to run Validate command from command line.
+func (o *Options) Complete() error {
+	return o.FilenameOptions.RequireFilenameOrKustomize()
+}
+
+// Run executes validate command
+func (o *Options) Run() error {
+	r := new(resource.Builder).
+		Unstructured().
+		Local().
+		FilenameParam(false, &o.FilenameOptions).
+		Flatten().
+		Do()
+
+	infos, err := r.Infos()
+	if err != nil {
+		return fmt.Errorf("error reading manifests: %s", err)
+	}
+
+	validated, invalid := 0, 0
+	for _, info := range infos {
+		u, ok := info.Object.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		if !webhook.Scheme.Recognizes(u.GroupVersionKind()) {
+			continue
+		}
+
+		validated++
+		el := validateObject(u)
+		printResult(o.Out, u, el)
+		if len(el) > 0 {
+			invalid++
+		}
+	}
+
+	if validated == 0 {
+		return fmt.Errorf("no cert-manager resources found")
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d cert-manager resources failed validation", invalid, validated)
+	}
+
+	return nil
+}
+
+// validateObject runs the validations enforced by the webhook against the
+// given cert-manager resource.
+func validateObject(u *unstructured.Unstructured) field.ErrorList {
+	gvk := u.GroupVersionKind()
+	obj, err := webhook.Scheme.New(gvk)
+	if err != nil {
+		return field.ErrorList{field.InternalError(nil, err)}
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+		return field.ErrorList{field.Invalid(nil, u.GetName(), fmt.Sprintf("failed to decode resource: %v", err))}
+	}
+
+	return webhook.ValidationRegistry.Validate(obj, gvk)
+}
+
+func printResult(out io.Writer, u *unstructured.Unstructured, el field.ErrorList) {
+	name := fmt.Sprintf("%s/%s", strings.ToLower(u.GetKind()), u.GetName())
+	if ns := u.GetNamespace(); ns != "" {
+		name = fmt.Sprintf("%s (namespace %q)", name, ns)
+	}
+
+	if len(el) == 0 {
+		fmt.Fprintf(out, "%s is valid\n", name)
+		return
+	}
+
+	fmt.Fprintf(out, "%s is invalid:\n", name)
+	for _, err := range el {
+		fmt.Fprintf(out, "  - %s\n", err.Error())
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+const validCertificate = `apiVersion: cert-manager.io/v1alpha2
+kind: Certificate
+metadata:
+  name: valid
+  namespace: default
+spec:
+  secretName: valid-tls
+  dnsNames:
+  - example.com
+  issuerRef:
+    name: ca-issuer
+`
+
+const invalidCertificate = `apiVersion: cert-manager.io/v1alpha2
+kind: Certificate
+metadata:
+  name: invalid
+  namespace: default
+spec:
+  dnsNames:
+  - example.com
+  issuerRef:
+    name: ca-issuer
+`
+
+const configMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unrelated
+data:
+  foo: bar
+`
+
+func TestRun(t *testing.T) {
+	tests := map[string]struct {
+		manifest string
+		expErr   bool
+	}{
+		"a valid Certificate should pass validation": {
+			manifest: validCertificate,
+			expErr:   false,
+		},
+		"a Certificate without a secretName should fail validation": {
+			manifest: invalidCertificate,
+			expErr:   true,
+		},
+		"one invalid resource amongst valid ones should fail validation": {
+			manifest: validCertificate + "---\n" + invalidCertificate,
+			expErr:   true,
+		},
+		"resources that are not cert-manager resources should be ignored": {
+			manifest: validCertificate + "---\n" + configMap,
+			expErr:   false,
+		},
+		"a manifest without cert-manager resources should error": {
+			manifest: configMap,
+			expErr:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "cert-manager-validate")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "manifest.yaml")
+			if err := ioutil.WriteFile(path, []byte(test.manifest), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			out := new(bytes.Buffer)
+			o := NewOptions(genericclioptions.IOStreams{Out: out, ErrOut: out})
+			o.FilenameOptions = resource.FilenameOptions{Filenames: []string{path}}
+
+			if err := o.Complete(); err != nil {
+				t.Fatal(err)
+			}
+
+			err = o.Run()
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error=%t but got: %v\noutput: %s", test.expErr, err, out.String())
+			}
+		})
+	}
+}
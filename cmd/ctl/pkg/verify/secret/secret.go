@@ -0,0 +1,258 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	"github.com/jetstack/cert-manager/pkg/util/attestation"
+)
+
+var (
+	long = templates.LongDesc(i18n.T(`
+Verify the attestation of the certificate stored in a Secret.
+
+If the controller is started with --secret-attestation-key-secret-name, the Secrets of issued certificates
+are annotated with an attestation signed by the controller, binding the certificate to the Certificate
+resource and issuer it was issued for, and to the version of the controller.
+
+The command fails if the Secret has no attestation, if the attestation is not signed by the given public key,
+or if it was signed for another certificate. Changes to the Certificate resource since the certificate was
+issued are reported as warnings.`))
+
+	example = templates.Examples(i18n.T(`
+# Verify the attestation of the Secret named 'my-app-tls' in the current context namespace
+kubectl cert-manager verify secret my-app-tls --public-key attestation.pub
+`))
+)
+
+// Options is a struct to support verify secret command
+type Options struct {
+	KubeClient kubernetes.Interface
+	CMClient   cmclient.Interface
+	RESTConfig *restclient.Config
+
+	// The Namespace that the Secret to be verified resides in.
+	// This flag registration is handled by cmdutil.Factory
+	Namespace string
+
+	// PublicKeyFile is the file holding the PEM encoded public key of the
+	// controller's attestation key
+	PublicKeyFile string
+
+	genericclioptions.IOStreams
+}
+
+// NewOptions returns initialized Options
+func NewOptions(ioStreams genericclioptions.IOStreams) *Options {
+	return &Options{
+		IOStreams: ioStreams,
+	}
+}
+
+// NewCmdVerifySecret returns a cobra command for verify secret
+func NewCmdVerifySecret(ioStreams genericclioptions.IOStreams, factory cmdutil.Factory) *cobra.Command {
+	o := NewOptions(ioStreams)
+	cmd := &cobra.Command{
+		Use:     "secret <name>",
+		Short:   "Verify the attestation of the certificate stored in a Secret",
+		Long:    long,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(o.Validate(args))
+			cmdutil.CheckErr(o.Complete(factory))
+			cmdutil.CheckErr(o.Run(args))
+		},
+	}
+	cmd.Flags().StringVar(&o.PublicKeyFile, "public-key", o.PublicKeyFile, "Path to the PEM encoded public key of the controller's attestation key")
+	return cmd
+}
+
+// Validate validates the provided options
+func (o *Options) Validate(args []string) error {
+	if len(args) < 1 {
+		return errors.New("the name of the Secret has to be provided as argument")
+	}
+	if len(args) > 1 {
+		return errors.New("only one argument can be passed in: the name of the Secret")
+	}
+	if o.PublicKeyFile == "" {
+		return errors.New("the path to the public key has to be provided with --public-key")
+	}
+	return nil
+}
+
+// Complete takes the command arguments and factory and infers any remaining options.
+func (o *Options) Complete(f cmdutil.Factory) error {
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	o.RESTConfig, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	o.KubeClient, err = kubernetes.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	o.CMClient, err = cmclient.NewForConfig(o.RESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run executes verify secret command
+func (o *Options) Run(args []string) error {
+	ctx := context.TODO()
+
+	pubPEM, err := ioutil.ReadFile(o.PublicKeyFile)
+	if err != nil {
+		return fmt.Errorf("error when reading public key file: %w", err)
+	}
+	pub, err := attestation.ParsePublicKey(pubPEM)
+	if err != nil {
+		return fmt.Errorf("error when parsing public key file %q: %w", o.PublicKeyFile, err)
+	}
+
+	secret, err := o.KubeClient.CoreV1().Secrets(o.Namespace).Get(ctx, args[0], metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error when getting Secret: %w", err)
+	}
+
+	var warnings []string
+	statement, problems := verifySecret(secret, pub)
+	if statement != nil {
+		// The Certificate is looked up by the name in the signed attestation,
+		// rather than by the annotations of the Secret
+		crt, err := o.CMClient.CertmanagerV1alpha2().Certificates(secret.Namespace).Get(ctx, statement.Certificate.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			crt, err = nil, nil
+		}
+		if err != nil {
+			return fmt.Errorf("error when getting Certificate: %w", err)
+		}
+		ps, ws := checkCertificate(statement, crt)
+		problems = append(problems, ps...)
+		warnings = append(warnings, ws...)
+	}
+
+	fmt.Fprint(o.Out, describeVerification(statement, problems, warnings))
+	if len(problems) > 0 {
+		return fmt.Errorf("the attestation of Secret %q could not be verified", secret.Name)
+	}
+	return nil
+}
+
+// verifySecret verifies the attestation of secret with pub, and checks that
+// it was signed for the certificate stored in secret. The Statement of the
+// attestation is returned if its signature is valid, with the problems found.
+func verifySecret(secret *corev1.Secret, pub *ecdsa.PublicKey) (statement *attestation.Statement, problems []string) {
+	att, ok := secret.Annotations[cmapi.AttestationAnnotationKey]
+	if !ok {
+		return nil, []string{fmt.Sprintf("Secret has no %q annotation", cmapi.AttestationAnnotationKey)}
+	}
+
+	statement, err := attestation.Verify(att, pub)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	if statement.CertificateSHA256 != attestation.CertificateHash(secret.Data[corev1.TLSCertKey]) {
+		problems = append(problems, "attestation was signed for another certificate than the one stored in the Secret")
+	}
+	if statement.Certificate.Namespace != secret.Namespace {
+		problems = append(problems, fmt.Sprintf("attestation was signed for a Certificate in namespace %q", statement.Certificate.Namespace))
+	}
+	return statement, problems
+}
+
+// checkCertificate checks statement against crt, the Certificate named in
+// it, or nil if it does not exist anymore. Changes to the Certificate since
+// the attestation was signed are returned as warnings.
+func checkCertificate(statement *attestation.Statement, crt *cmapi.Certificate) (problems, warnings []string) {
+	switch {
+	case crt == nil:
+		warnings = append(warnings, fmt.Sprintf("Certificate %q does not exist anymore", statement.Certificate.Name))
+	case crt.UID != statement.Certificate.UID:
+		warnings = append(warnings, fmt.Sprintf("certificate was issued for a previous Certificate resource named %q", statement.Certificate.Name))
+	default:
+		specHash, err := attestation.SpecHash(&crt.Spec)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else if specHash != statement.SpecSHA256 {
+			warnings = append(warnings, "spec of the Certificate has changed since the certificate was issued")
+		}
+	}
+	return problems, warnings
+}
+
+// describeVerification returns the details of statement, if not nil,
+// followed by the problems and warnings found, as a string to be printed as
+// output
+func describeVerification(statement *attestation.Statement, problems, warnings []string) string {
+	output := ""
+	if statement != nil {
+		issuerGroup := statement.Issuer.Group
+		if issuerGroup == "" {
+			issuerGroup = "cert-manager.io"
+		}
+		output += "Attestation:\n"
+		output += fmt.Sprintf("  Certificate: %s/%s (UID: %s)\n", statement.Certificate.Namespace, statement.Certificate.Name, statement.Certificate.UID)
+		output += fmt.Sprintf("  Issuer: %s.%s %q\n", statement.Issuer.Kind, issuerGroup, statement.Issuer.Name)
+		output += fmt.Sprintf("  Controller Version: %s\n", statement.ControllerVersion)
+		output += fmt.Sprintf("  Signed At: %s\n", time.Unix(statement.IssuedAt, 0).UTC().Format(time.RFC3339))
+	}
+	output += "Problems:\n" + formatList(problems)
+	output += "Warnings:\n" + formatList(warnings)
+	return output
+}
+
+func formatList(items []string) string {
+	if len(items) == 0 {
+		return "  None\n"
+	}
+	output := ""
+	for _, item := range items {
+		output += fmt.Sprintf("  - %s\n", item)
+	}
+	return output
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/util/attestation"
+)
+
+func TestVerifySecret(t *testing.T) {
+	newKey := func() *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	key, otherKey := newKey(), newKey()
+
+	crt := &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", UID: "test-uid"},
+		Spec: cmapi.CertificateSpec{
+			SecretName: "test-tls",
+			DNSNames:   []string{"example.com"},
+			IssuerRef:  cmmeta.ObjectReference{Name: "ca"},
+		},
+	}
+	newSecret := func(signingKey *ecdsa.PrivateKey, attestedCert []byte) *corev1.Secret {
+		statement, err := attestation.NewStatement(crt, attestedCert, "v0.16.0", time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		att, err := attestation.Sign(signingKey, statement)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        "test-tls",
+				Annotations: map[string]string{cmapi.AttestationAnnotationKey: att},
+			},
+			Data: map[string][]byte{corev1.TLSCertKey: []byte("certificate")},
+		}
+	}
+
+	tests := map[string]struct {
+		secret *corev1.Secret
+
+		expStatement bool
+		expProblems  []string
+	}{
+		"attestation of the stored certificate": {
+			secret:       newSecret(key, []byte("certificate")),
+			expStatement: true,
+		},
+		"attestation of another certificate": {
+			secret:       newSecret(key, []byte("previous certificate")),
+			expStatement: true,
+			expProblems:  []string{"attestation was signed for another certificate than the one stored in the Secret"},
+		},
+		"attestation signed by another key": {
+			secret:      newSecret(otherKey, []byte("certificate")),
+			expProblems: []string{"attestation signature is not valid for the public key"},
+		},
+		"Secret without attestation": {
+			secret:      &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-tls"}},
+			expProblems: []string{`Secret has no "cert-manager.io/attestation" annotation`},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			statement, problems := verifySecret(test.secret, &key.PublicKey)
+			if (statement != nil) != test.expStatement {
+				t.Errorf("expected statement: %t, got: %+v", test.expStatement, statement)
+			}
+			if !reflect.DeepEqual(problems, test.expProblems) {
+				t.Errorf("unexpected problems; expected: %q, got: %q", test.expProblems, problems)
+			}
+		})
+	}
+}
+
+func TestCheckCertificate(t *testing.T) {
+	crt := &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", UID: "test-uid"},
+		Spec: cmapi.CertificateSpec{
+			SecretName: "test-tls",
+			DNSNames:   []string{"example.com"},
+			IssuerRef:  cmmeta.ObjectReference{Name: "ca"},
+		},
+	}
+	statement, err := attestation.NewStatement(crt, []byte("certificate"), "v0.16.0", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changedCrt := crt.DeepCopy()
+	changedCrt.Spec.DNSNames = []string{"example.org"}
+	recreatedCrt := crt.DeepCopy()
+	recreatedCrt.UID = "other-uid"
+
+	tests := map[string]struct {
+		crt         *cmapi.Certificate
+		expWarnings []string
+	}{
+		"unchanged Certificate": {
+			crt: crt,
+		},
+		"Certificate with a changed spec": {
+			crt:         changedCrt,
+			expWarnings: []string{"spec of the Certificate has changed since the certificate was issued"},
+		},
+		"Certificate re-created with the same name": {
+			crt:         recreatedCrt,
+			expWarnings: []string{`certificate was issued for a previous Certificate resource named "test"`},
+		},
+		"deleted Certificate": {
+			expWarnings: []string{`Certificate "test" does not exist anymore`},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			problems, warnings := checkCertificate(&statement, test.crt)
+			if len(problems) > 0 {
+				t.Errorf("unexpected problems: %q", problems)
+			}
+			if !reflect.DeepEqual(warnings, test.expWarnings) {
+				t.Errorf("unexpected warnings; expected: %q, got: %q", test.expWarnings, warnings)
+			}
+		})
+	}
+}
@@ -55,6 +55,38 @@ type WebhookOptions struct {
 	// MinTLSVersion is the minimum TLS version supported.
 	// Values are from tls package constants (https://golang.org/pkg/crypto/tls/#pkg-constants).
 	MinTLSVersion string
+
+	// EnforceIssuerDNSSuffixes enables rejecting CertificateRequests at
+	// admission if they request DNS names not allowed by the referenced
+	// issuer. This requires the webhook to be able to list and watch Issuer
+	// and ClusterIssuer resources.
+	EnforceIssuerDNSSuffixes bool
+
+	// MaxSANs is the maximum number of subject alternative names a
+	// Certificate or CertificateRequest may request. Zero means no limit.
+	MaxSANs int
+
+	// MaxCSRBytes is the maximum size in bytes of the PEM encoded CSR of a
+	// CertificateRequest. Zero means no limit.
+	MaxCSRBytes int
+
+	// EnforcePodIdentity enables rejecting CertificateRequests created by
+	// Pods, or by PodIdentityAgents on behalf of Pods, at admission if they
+	// request names that cannot be derived from the identity of the Pod.
+	// This requires the webhook to be able to read Pod resources.
+	EnforcePodIdentity bool
+	// PodIdentityDNSNames are templates of the DNS names Pods may request.
+	PodIdentityDNSNames []string
+	// PodIdentityURIs are templates of the URI SANs Pods may request.
+	PodIdentityURIs []string
+	// PodIdentityAgents are the usernames of trusted agents that create
+	// CertificateRequests on behalf of Pods.
+	PodIdentityAgents []string
+	// PodIdentityTrustedUsers and PodIdentityTrustedGroups are the
+	// usernames and groups of users whose CertificateRequests are not bound
+	// to the identity of a Pod.
+	PodIdentityTrustedUsers  []string
+	PodIdentityTrustedGroups []string
 }
 
 func (o *WebhookOptions) AddFlags(fs *pflag.FlagSet) {
@@ -77,6 +109,26 @@ func (o *WebhookOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.MinTLSVersion, "tls-min-version", o.MinTLSVersion,
 		"Minimum TLS version supported. "+
 			"Possible values: "+strings.Join(tlsPossibleVersions, ", "))
+	fs.BoolVar(&o.EnforceIssuerDNSSuffixes, "enforce-issuer-dns-suffixes", false,
+		"if true, CertificateRequests for DNS names not allowed by the allowedDNSSuffixes of the referenced issuer are rejected at admission")
+	fs.IntVar(&o.MaxSANs, "max-sans", 0,
+		"maximum number of subject alternative names a Certificate or CertificateRequest may request. Requests exceeding it are rejected at admission. 0 means no limit")
+	fs.IntVar(&o.MaxCSRBytes, "max-csr-size", 0,
+		"maximum size in bytes of the PEM encoded CSR of a CertificateRequest. Requests exceeding it are rejected at admission. 0 means no limit")
+	fs.BoolVar(&o.EnforcePodIdentity, "enforce-pod-identity", false,
+		"if true, CertificateRequests created by Pods using bound service account tokens, or by --pod-identity-agents on behalf of Pods, are rejected at admission if they request names that cannot be derived from the identity of the Pod. "+
+			"CertificateRequests created by any other user that is not in --pod-identity-trusted-users or --pod-identity-trusted-groups are rejected")
+	fs.StringSliceVar(&o.PodIdentityDNSNames, "pod-identity-dns-names", []string{"{serviceaccount}.{namespace}.svc", "{serviceaccount}.{namespace}.svc.cluster.local"},
+		"DNS names Pods may request certificates for when --enforce-pod-identity is set. {namespace}, {serviceaccount} and {pod} are replaced by the namespace, service account and name of the Pod")
+	fs.StringSliceVar(&o.PodIdentityURIs, "pod-identity-uris", []string{"spiffe://cluster.local/ns/{namespace}/sa/{serviceaccount}"},
+		"URI subject alternative names Pods may request certificates for when --enforce-pod-identity is set. {namespace}, {serviceaccount} and {pod} are replaced by the namespace, service account and name of the Pod")
+	fs.StringSliceVar(&o.PodIdentityAgents, "pod-identity-agents", nil,
+		"usernames of trusted agents, such as CSI drivers, that create CertificateRequests on behalf of Pods. Agents must name the Pod in the cert-manager.io/pod-name annotation of the CertificateRequest")
+	fs.StringSliceVar(&o.PodIdentityTrustedUsers, "pod-identity-trusted-users", nil,
+		"usernames of users whose CertificateRequests are not bound to the identity of a Pod when --enforce-pod-identity is set. "+
+			"This must include the service account of the cert-manager controller, e.g. system:serviceaccount:cert-manager:cert-manager, for Certificates to be issued")
+	fs.StringSliceVar(&o.PodIdentityTrustedGroups, "pod-identity-trusted-groups", []string{"system:masters"},
+		"groups of users whose CertificateRequests are not bound to the identity of a Pod when --enforce-pod-identity is set")
 }
 
 func FileTLSSourceEnabled(o WebhookOptions) bool {
@@ -19,26 +19,38 @@ package app
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/jetstack/cert-manager/cmd/webhook/app/options"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/util"
 	"github.com/jetstack/cert-manager/pkg/webhook"
 	"github.com/jetstack/cert-manager/pkg/webhook/authority"
 	"github.com/jetstack/cert-manager/pkg/webhook/handlers"
+	"github.com/jetstack/cert-manager/pkg/webhook/issuerpolicy"
+	"github.com/jetstack/cert-manager/pkg/webhook/podidentity"
 	"github.com/jetstack/cert-manager/pkg/webhook/server"
 	"github.com/jetstack/cert-manager/pkg/webhook/server/tls"
+	"github.com/jetstack/cert-manager/pkg/webhook/sizelimits"
 )
 
+// resyncPeriod is the resync period of the informers used by admission
+// checks that read other resources.
+const resyncPeriod = 10 * time.Hour
+
 var validationHook handlers.ValidatingAdmissionHook = handlers.NewRegistryBackedValidator(logf.Log, webhook.Scheme, webhook.ValidationRegistry)
 var mutationHook handlers.MutatingAdmissionHook = handlers.NewSchemeBackedDefaulter(logf.Log, webhook.Scheme)
 var conversionHook handlers.ConversionHook = handlers.NewSchemeBackedConverter(logf.Log, webhook.Scheme)
 
-func NewServerWithOptions(log logr.Logger, opts options.WebhookOptions) (*server.Server, error) {
+func NewServerWithOptions(log logr.Logger, opts options.WebhookOptions, stopCh <-chan struct{}) (*server.Server, error) {
 	var source tls.CertificateSource
 	switch {
 	case options.FileTLSSourceEnabled(opts):
@@ -69,6 +81,65 @@ func NewServerWithOptions(log logr.Logger, opts options.WebhookOptions) (*server
 		log.Info("warning: serving insecurely as tls certificate data not provided")
 	}
 
+	if opts.EnforceIssuerDNSSuffixes {
+		restcfg, err := clientcmd.BuildConfigFromFlags("", opts.Kubeconfig)
+		if err != nil {
+			return nil, err
+		}
+		cl, err := cmclient.NewForConfig(restcfg)
+		if err != nil {
+			return nil, err
+		}
+
+		// issuers are read from informer caches, so that admission does not
+		// wait for the API server
+		factory := cminformers.NewSharedInformerFactory(cl, resyncPeriod)
+		issuers := factory.Certmanager().V1alpha2().Issuers()
+		clusterIssuers := factory.Certmanager().V1alpha2().ClusterIssuers()
+		issuers.Informer()
+		clusterIssuers.Informer()
+		factory.Start(stopCh)
+		if !cache.WaitForCacheSync(stopCh, issuers.Informer().HasSynced, clusterIssuers.Informer().HasSynced) {
+			return nil, fmt.Errorf("timed out waiting for issuer caches to sync")
+		}
+
+		log.Info("enforcing allowed DNS suffixes of issuers on CertificateRequest admission")
+		if err := issuerpolicy.InstallAllowedDNSSuffixesValidation(webhook.ValidationRegistry, issuers.Lister(), clusterIssuers.Lister()); err != nil {
+			return nil, err
+		}
+	}
+
+	if limits := (sizelimits.Limits{MaxSANs: opts.MaxSANs, MaxCSRBytes: opts.MaxCSRBytes}); limits.Enabled() {
+		log.Info("enforcing size limits on Certificate and CertificateRequest admission", "max_sans", limits.MaxSANs, "max_csr_size", limits.MaxCSRBytes)
+		if err := sizelimits.InstallValidation(webhook.ValidationRegistry, limits); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.EnforcePodIdentity {
+		restcfg, err := clientcmd.BuildConfigFromFlags("", opts.Kubeconfig)
+		if err != nil {
+			return nil, err
+		}
+		cl, err := kubernetes.NewForConfig(restcfg)
+		if err != nil {
+			return nil, err
+		}
+
+		policy := podidentity.Policy{
+			DNSNames:      opts.PodIdentityDNSNames,
+			URIs:          opts.PodIdentityURIs,
+			Agents:        opts.PodIdentityAgents,
+			TrustedUsers:  opts.PodIdentityTrustedUsers,
+			TrustedGroups: opts.PodIdentityTrustedGroups,
+		}
+		log.Info("enforcing pod identity on CertificateRequest admission", "dns_names", policy.DNSNames, "uris", policy.URIs, "agents", policy.Agents,
+			"trusted_users", policy.TrustedUsers, "trusted_groups", policy.TrustedGroups)
+		if err := podidentity.InstallValidation(webhook.ValidationRegistry, policy, cl.CoreV1()); err != nil {
+			return nil, err
+		}
+	}
+
 	return &server.Server{
 		ListenAddr:        fmt.Sprintf(":%d", opts.ListenPort),
 		HealthzAddr:       fmt.Sprintf(":%d", opts.HealthzPort),
@@ -94,7 +165,7 @@ func NewServerCommand(stopCh <-chan struct{}) *cobra.Command {
 			ctx = logf.NewContext(ctx, nil, "webhook")
 			log := logf.FromContext(ctx)
 
-			srv, err := NewServerWithOptions(log, opts)
+			srv, err := NewServerWithOptions(log, opts, stopCh)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	ctlclients "github.com/jetstack/cert-manager/pkg/ctl/clients"
+	ctlstatus "github.com/jetstack/cert-manager/pkg/ctl/status"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/pkg/webui"
+)
+
+const (
+	readTimeout     = 10 * time.Second
+	writeTimeout    = 60 * time.Second
+	shutdownTimeout = 5 * time.Second
+)
+
+// WebUIOptions are the options of the web UI.
+type WebUIOptions struct {
+	APIServerHost string
+	Kubeconfig    string
+	Namespace     string
+	ListenAddress string
+	ChunkSize     int64
+}
+
+func (o *WebUIOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.APIServerHost, "master", "", ""+
+		"Optional apiserver host address to connect to. If not specified, autoconfiguration "+
+		"will be attempted.")
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", "", ""+
+		"Paths to a kubeconfig. Only required if out-of-cluster.")
+	fs.StringVar(&o.Namespace, "namespace", "", ""+
+		"If set, only Certificates in this namespace are listed and shown.")
+	fs.StringVar(&o.ListenAddress, "listen-address", ":8080", ""+
+		"The host and port the web UI listens on.")
+	fs.Int64Var(&o.ChunkSize, "chunk-size", ctlclients.DefaultChunkSize, ""+
+		"The number of resources requested per page when listing Certificates. Pass 0 to disable paging.")
+}
+
+// NewCommandStartWebUI returns the command running the read-only web UI.
+func NewCommandStartWebUI(stopCh <-chan struct{}) *cobra.Command {
+	o := &WebUIOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "webui",
+		Short: fmt.Sprintf("Read-only web UI for cert-manager (%s) (%s)", util.AppVersion, util.AppGitCommit),
+		Long: `
+cert-manager web UI lists Certificates and shows their status, rendered the
+same way as by 'kubectl cert-manager status certificate', for teams without
+terminal access to the cluster.
+
+The web UI only reads resources. It does not authenticate users, so it should
+only be exposed behind an authenticating proxy.`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(stopCh)
+		},
+	}
+
+	o.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Run serves the web UI until stopCh is closed.
+func (o WebUIOptions) Run(stopCh <-chan struct{}) error {
+	log := logf.Log.WithName("webui")
+	log.Info("starting web UI", "version", util.AppVersion, "revision", util.AppGitCommit)
+
+	if o.ChunkSize < 0 {
+		return fmt.Errorf("--chunk-size must not be negative")
+	}
+
+	restConfig, err := clientcmd.BuildConfigFromFlags(o.APIServerHost, o.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("error creating rest config: %v", err)
+	}
+	restConfig.UserAgent = util.CertManagerUserAgent
+
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("error creating kubernetes client: %v", err)
+	}
+	cmClient, err := cmclient.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("error creating cert-manager client: %v", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("error creating dynamic client: %v", err)
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(kubeClient.Discovery()))
+
+	server := &http.Server{
+		Addr:         o.ListenAddress,
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+		Handler: webui.NewServer(log, ctlstatus.Clients{
+			Kube:       kubeClient,
+			CM:         cmClient,
+			Dynamic:    dynamicClient,
+			RESTMapper: mapper,
+		}, o.Namespace, o.ChunkSize),
+	}
+
+	go func() {
+		<-stopCh
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			log.Error(err, "error shutting down web UI")
+		}
+	}()
+
+	log.Info("listening for connections", "address", o.ListenAddress)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -126,3 +126,42 @@ Create the name of the service account to use
     {{ default "default" .Values.cainjector.serviceAccount.name }}
 {{- end -}}
 {{- end -}}
+
+{{/*
+webui templates
+*/}}
+
+{{/*
+Expand the name of the chart.
+*/}}
+{{- define "webui.name" -}}
+{{- printf "webui" -}}
+{{- end -}}
+
+{{/*
+Create a default fully qualified app name.
+We truncate at 63 chars because some Kubernetes name fields are limited to this (by the DNS naming spec).
+If release name contains chart name it will be used as a full name.
+*/}}
+{{- define "webui.fullname" -}}
+{{- $trimmedName := printf "%s" (include "cert-manager.fullname" .) | trunc 57 | trimSuffix "-" -}}
+{{- printf "%s-webui" $trimmedName | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+
+{{/*
+Create chart name and version as used by the chart label.
+*/}}
+{{- define "webui.chart" -}}
+{{- printf "%s-%s" .Chart.Name .Chart.Version | replace "+" "_" | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+
+{{/*
+Create the name of the service account to use
+*/}}
+{{- define "webui.serviceAccountName" -}}
+{{- if .Values.webui.serviceAccount.create -}}
+    {{ default (include "webui.fullname" .) .Values.webui.serviceAccount.name }}
+{{- else -}}
+    {{ default "default" .Values.webui.serviceAccount.name }}
+{{- end -}}
+{{- end -}}
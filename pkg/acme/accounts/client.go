@@ -31,13 +31,35 @@ import (
 	"github.com/jetstack/cert-manager/pkg/util"
 )
 
+// ClientOptions configures how ACME clients retry failed requests and stop
+// sending requests to failing ACME endpoints.
+type ClientOptions struct {
+	// Backoff is used to retry requests that failed due to a server error.
+	Backoff acmecl.BackoffPolicy
+	// BadNonceBackoff is used to retry requests that were rejected with a
+	// 'badNonce' error.
+	BadNonceBackoff acmecl.BackoffPolicy
+	// CircuitBreaker short-circuits requests to failing endpoints. It is
+	// shared by all HTTP clients built with these options. If nil, circuit
+	// breaking is disabled.
+	CircuitBreaker *acmecl.CircuitBreaker
+}
+
+// DefaultClientOptions are the ClientOptions used if none are configured.
+var DefaultClientOptions = ClientOptions{
+	Backoff:         acmecl.DefaultBackoffPolicy,
+	BadNonceBackoff: acmecl.DefaultBadNonceBackoffPolicy,
+	CircuitBreaker:  acmecl.NewCircuitBreaker(acmecl.DefaultCircuitBreakerOptions),
+}
+
 // NewClient will return a new ACME client.
-func NewClient(client *http.Client, config cmacme.ACMEIssuer, privateKey *rsa.PrivateKey) acmecl.Interface {
+func NewClient(client *http.Client, config cmacme.ACMEIssuer, privateKey *rsa.PrivateKey, opts ClientOptions) acmecl.Interface {
 	return &acmeapi.Client{
 		Key:          privateKey,
 		HTTPClient:   client,
 		DirectoryURL: config.Server,
 		UserAgent:    util.CertManagerUserAgent,
+		RetryBackoff: acmecl.RetryBackoff(opts.Backoff, opts.BadNonceBackoff),
 	}
 }
 
@@ -48,8 +70,11 @@ func NewClient(client *http.Client, config cmacme.ACMEIssuer, privateKey *rsa.Pr
 // itself.
 // In future, we may change to having two global HTTP clients - one that ignores
 // TLS connection errors, and the other that does not.
-func BuildHTTPClient(metrics *metrics.Metrics, skipTLSVerify bool) *http.Client {
-	return acmecl.NewInstrumentedClient(metrics,
+// The circuit breaker wraps the instrumented transport so that requests that
+// are never sent are not recorded in metrics. Its state is kept by the
+// CircuitBreaker of opts, so it is not reset when a new client is built.
+func BuildHTTPClient(metrics *metrics.Metrics, skipTLSVerify bool, opts ClientOptions) *http.Client {
+	return acmecl.NewCircuitBreakerClient(opts.CircuitBreaker, acmecl.NewInstrumentedClient(metrics,
 		&http.Client{
 			Transport: &http.Transport{
 				Proxy: http.ProxyFromEnvironment,
@@ -64,5 +89,5 @@ func BuildHTTPClient(metrics *metrics.Metrics, skipTLSVerify bool) *http.Client
 				ExpectContinueTimeout: 1 * time.Second,
 			},
 			Timeout: time.Second * 30,
-		})
+		}))
 }
@@ -60,8 +60,15 @@ type Getter interface {
 
 // NewDefaultRegistry returns a new default instantiation of a client registry.
 func NewDefaultRegistry() Registry {
+	return NewRegistry(DefaultClientOptions)
+}
+
+// NewRegistry returns a new client registry that constructs ACME clients
+// using the given options.
+func NewRegistry(opts ClientOptions) Registry {
 	return &registry{
 		clients: make(map[string]clientWithMeta),
+		opts:    opts,
 	}
 }
 
@@ -71,6 +78,9 @@ type registry struct {
 
 	// a map of an issuer's 'uid' to an ACME client with metadata
 	clients map[string]clientWithMeta
+
+	// options used to construct ACME clients
+	opts ClientOptions
 }
 
 // stableOptions contains data about an ACME client that can be used to compare
@@ -134,7 +144,7 @@ func (r *registry) ensureClient(client *http.Client, uid string, config cmacme.A
 	// create a new client if one is not registered or if the
 	// 'metadata' does not match
 	r.clients[uid] = clientWithMeta{
-		Interface:     NewClient(client, config, privateKey),
+		Interface:     NewClient(client, config, privateKey, r.opts),
 		stableOptions: newOpts,
 	}
 }
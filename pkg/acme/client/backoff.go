@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// BackoffPolicy decides whether, and how long after, a failed request to an
+// ACME server should be retried.
+type BackoffPolicy interface {
+	// Backoff returns the duration to wait before making retry attempt n,
+	// where n starts at 1. The response may be nil if the request failed
+	// before a response was received.
+	// A zero or negative duration means the request should not be retried.
+	Backoff(n int, req *http.Request, resp *http.Response) time.Duration
+}
+
+// ExponentialBackoff is a BackoffPolicy that doubles the wait between each
+// attempt, adding up to a fifth of the wait as jitter, until either
+// MaxRetries has been reached or the wait would exceed Max.
+// A Retry-After header returned by the server always takes precedence.
+type ExponentialBackoff struct {
+	// Base is the wait before the first retry.
+	Base time.Duration
+	// Max is the upper bound for the wait between two attempts.
+	Max time.Duration
+	// MaxRetries is the maximum number of retries. Zero disables retries.
+	MaxRetries int
+}
+
+var _ BackoffPolicy = ExponentialBackoff{}
+
+// Backoff implements BackoffPolicy
+func (e ExponentialBackoff) Backoff(n int, req *http.Request, resp *http.Response) time.Duration {
+	if n > e.MaxRetries {
+		return 0
+	}
+	if d, ok := retryAfter(resp); ok {
+		return d
+	}
+
+	d := e.Base
+	for i := 1; i < n && d < e.Max; i++ {
+		d *= 2
+	}
+	// the jitter is proportional to the wait so that short waits, such as
+	// those for 'badNonce' errors, are not dominated by it
+	if jitter := int64(d) / 5; jitter > 0 {
+		d += time.Duration(rand.Int63n(jitter))
+	}
+	if d > e.Max {
+		d = e.Max
+	}
+	return d
+}
+
+var (
+	// DefaultBackoffPolicy is the BackoffPolicy used for requests that failed
+	// with a server error or a transport error.
+	DefaultBackoffPolicy BackoffPolicy = ExponentialBackoff{
+		Base:       time.Second,
+		Max:        10 * time.Second,
+		MaxRetries: 5,
+	}
+
+	// DefaultBadNonceBackoffPolicy is the BackoffPolicy used for requests that
+	// were rejected by the ACME server with a 'badNonce' error. These are
+	// expected to happen from time to time and are safe to retry quickly as
+	// the ACME client will sign the retry with a fresh nonce.
+	DefaultBadNonceBackoffPolicy BackoffPolicy = ExponentialBackoff{
+		Base:       10 * time.Millisecond,
+		Max:        time.Second,
+		MaxRetries: 3,
+	}
+)
+
+// RetryBackoff returns a function suitable for use as the RetryBackoff field
+// of an ACME client.
+// The ACME client only calls RetryBackoff for responses with a 400 status code
+// if the error was a 'badNonce' error, so these are handled by the badNonce
+// policy instead of the general backoff policy.
+// If either policy is nil, the corresponding default policy is used.
+func RetryBackoff(policy, badNonce BackoffPolicy) func(n int, req *http.Request, resp *http.Response) time.Duration {
+	if policy == nil {
+		policy = DefaultBackoffPolicy
+	}
+	if badNonce == nil {
+		badNonce = DefaultBadNonceBackoffPolicy
+	}
+	return func(n int, req *http.Request, resp *http.Response) time.Duration {
+		var d time.Duration
+		if resp != nil && resp.StatusCode == http.StatusBadRequest {
+			d = badNonce.Backoff(n, req, resp)
+		} else {
+			d = policy.Backoff(n, req, resp)
+		}
+		// the ACME client treats anything other than a positive duration as
+		// an instruction to stop retrying
+		if d <= 0 {
+			return -1
+		}
+		return d
+	}
+}
+
+// retryAfter parses the Retry-After header of resp, if present.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/acme"
+)
+
+// fixedBackoff is a BackoffPolicy that returns d for the first maxRetries
+// attempts, and records the status codes it was called for.
+type fixedBackoff struct {
+	d          time.Duration
+	maxRetries int
+	calls      []int
+}
+
+func (f *fixedBackoff) Backoff(n int, req *http.Request, resp *http.Response) time.Duration {
+	code := 0
+	if resp != nil {
+		code = resp.StatusCode
+	}
+	f.calls = append(f.calls, code)
+	if n > f.maxRetries {
+		return 0
+	}
+	return f.d
+}
+
+func TestRetryBackoff(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "https://acme.example.com/acme/order/1", nil)
+
+	tests := map[string]struct {
+		n int
+		// status is the status code of the response, or zero if the
+		// request failed before a response was received
+		status int
+
+		expBackoff       time.Duration
+		expPolicyCalls   []int
+		expBadNonceCalls []int
+	}{
+		"a transport error is retried with the policy": {
+			n:              1,
+			expBackoff:     time.Second,
+			expPolicyCalls: []int{0},
+		},
+		"a server error is retried with the policy": {
+			n:              1,
+			status:         http.StatusServiceUnavailable,
+			expBackoff:     time.Second,
+			expPolicyCalls: []int{http.StatusServiceUnavailable},
+		},
+		"a rate limited request is retried with the policy": {
+			n:              2,
+			status:         http.StatusTooManyRequests,
+			expBackoff:     time.Second,
+			expPolicyCalls: []int{http.StatusTooManyRequests},
+		},
+		"a badNonce error is retried with the badNonce policy": {
+			n:                1,
+			status:           http.StatusBadRequest,
+			expBackoff:       time.Millisecond,
+			expBadNonceCalls: []int{http.StatusBadRequest},
+		},
+		"a server error is not retried once the policy reaches its maximum attempts": {
+			n:              4,
+			status:         http.StatusInternalServerError,
+			expBackoff:     -1,
+			expPolicyCalls: []int{http.StatusInternalServerError},
+		},
+		"a badNonce error is not retried once the badNonce policy reaches its maximum attempts": {
+			n:                3,
+			status:           http.StatusBadRequest,
+			expBackoff:       -1,
+			expBadNonceCalls: []int{http.StatusBadRequest},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy := &fixedBackoff{d: time.Second, maxRetries: 3}
+			badNonce := &fixedBackoff{d: time.Millisecond, maxRetries: 2}
+			var resp *http.Response
+			if test.status != 0 {
+				resp = &http.Response{StatusCode: test.status, Header: http.Header{}}
+			}
+
+			d := RetryBackoff(policy, badNonce)(test.n, req, resp)
+			if d != test.expBackoff {
+				t.Errorf("unexpected backoff, exp=%s got=%s", test.expBackoff, d)
+			}
+			if !equalInts(policy.calls, test.expPolicyCalls) {
+				t.Errorf("unexpected calls to the policy, exp=%v got=%v", test.expPolicyCalls, policy.calls)
+			}
+			if !equalInts(badNonce.calls, test.expBadNonceCalls) {
+				t.Errorf("unexpected calls to the badNonce policy, exp=%v got=%v", test.expBadNonceCalls, badNonce.calls)
+			}
+		})
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	policy := ExponentialBackoff{Base: time.Second, Max: 10 * time.Second, MaxRetries: 5}
+	req := httptest.NewRequest(http.MethodGet, "https://acme.example.com/directory", nil)
+
+	for n, base := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 8 * time.Second} {
+		d := policy.Backoff(n, req, nil)
+		if d < base || d > base+base/5 {
+			t.Errorf("expected backoff of attempt %d to be between %s and %s, got %s", n, base, base+base/5, d)
+		}
+	}
+	short := ExponentialBackoff{Base: 10 * time.Millisecond, Max: time.Second, MaxRetries: 3}
+	if d := short.Backoff(1, req, nil); d < 10*time.Millisecond || d > 12*time.Millisecond {
+		t.Errorf("expected jitter to be proportional to a short backoff, got %s", d)
+	}
+	if d := policy.Backoff(5, req, nil); d != 10*time.Second {
+		t.Errorf("expected backoff of attempt 5 to be capped at 10s, got %s", d)
+	}
+	if d := policy.Backoff(6, req, nil); d > 0 {
+		t.Errorf("expected no retry after MaxRetries, got %s", d)
+	}
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"30"}}}
+	if d := policy.Backoff(1, req, resp); d != 30*time.Second {
+		t.Errorf("expected the Retry-After header to take precedence, got %s", d)
+	}
+	if d := (ExponentialBackoff{Base: time.Second, Max: time.Minute}).Backoff(1, req, nil); d > 0 {
+		t.Errorf("expected no retry if MaxRetries is zero, got %s", d)
+	}
+}
+
+// TestRetryBackoffWithClient checks how the ACME client retries requests
+// using RetryBackoff.
+func TestRetryBackoffWithClient(t *testing.T) {
+	tests := map[string]struct {
+		// statuses are the status codes returned by the server for the
+		// successive requests, the server responds with the last one once
+		// they are exhausted
+		statuses []int
+		policy   BackoffPolicy
+		// cancel cancels the context of the request once the first request
+		// has been received
+		cancel bool
+
+		expErr      bool
+		expRequests int32
+	}{
+		"a server error is retried": {
+			statuses:    []int{http.StatusInternalServerError, http.StatusOK},
+			policy:      ExponentialBackoff{Base: time.Millisecond, Max: 10 * time.Millisecond, MaxRetries: 3},
+			expRequests: 2,
+		},
+		"a client error is not retried": {
+			statuses:    []int{http.StatusForbidden},
+			policy:      ExponentialBackoff{Base: time.Millisecond, Max: 10 * time.Millisecond, MaxRetries: 3},
+			expErr:      true,
+			expRequests: 1,
+		},
+		"a server error is retried until the maximum attempts are reached": {
+			statuses:    []int{http.StatusServiceUnavailable},
+			policy:      ExponentialBackoff{Base: time.Millisecond, Max: 10 * time.Millisecond, MaxRetries: 3},
+			expErr:      true,
+			expRequests: 4,
+		},
+		"retries stop when the context is cancelled": {
+			statuses:    []int{http.StatusInternalServerError},
+			policy:      ExponentialBackoff{Base: time.Hour, Max: time.Hour, MaxRetries: 3},
+			cancel:      true,
+			expErr:      true,
+			expRequests: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(atomic.AddInt32(&requests, 1))
+				if test.cancel {
+					cancel()
+				}
+				status := test.statuses[len(test.statuses)-1]
+				if n <= len(test.statuses) {
+					status = test.statuses[n-1]
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			cl := &acme.Client{
+				HTTPClient:   server.Client(),
+				DirectoryURL: server.URL,
+				RetryBackoff: RetryBackoff(test.policy, nil),
+			}
+
+			done := make(chan error, 1)
+			go func() {
+				_, err := cl.Discover(ctx)
+				done <- err
+			}()
+			select {
+			case err := <-done:
+				if test.expErr != (err != nil) {
+					t.Errorf("expected error=%t got=%v", test.expErr, err)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatalf("timed out waiting for the request to return")
+			}
+
+			if got := atomic.LoadInt32(&requests); got != test.expRequests {
+				t.Errorf("expected %d requests, got %d", test.expRequests, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// This file implements a http.RoundTripper that tracks failures per ACME
+// endpoint and stops sending requests to an endpoint that keeps failing for a
+// cool down period.
+//
+// A single HTTP client is shared between all orders using the same ACME
+// account, so without this a flaky endpoint would cause every order to wait
+// for the full request timeout before failing.
+
+// CircuitBreakerOptions configures a CircuitBreaker.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failed requests to an
+	// endpoint after which the circuit is opened.
+	// If zero, circuit breaking is disabled.
+	FailureThreshold int
+	// Cooldown is the amount of time the circuit remains open before a single
+	// trial request is let through to the endpoint.
+	Cooldown time.Duration
+}
+
+// DefaultCircuitBreakerOptions are the CircuitBreakerOptions used by default.
+var DefaultCircuitBreakerOptions = CircuitBreakerOptions{
+	FailureThreshold: 5,
+	Cooldown:         30 * time.Second,
+}
+
+// ErrCircuitOpen is returned by a CircuitBreaker for requests to an endpoint
+// whose circuit is currently open.
+type ErrCircuitOpen struct {
+	Endpoint string
+	Until    time.Time
+}
+
+func (e *ErrCircuitOpen) Error() string {
+	return fmt.Sprintf("not sending request to ACME endpoint %q due to repeated failures, retry after %s",
+		e.Endpoint, e.Until.Format(time.RFC3339))
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuit tracks the state of a single endpoint.
+type circuit struct {
+	state     circuitState
+	failures  int
+	openUntil time.Time
+}
+
+// CircuitBreaker tracks the failures of requests to ACME endpoints and
+// short-circuits requests to endpoints that have repeatedly failed.
+// Endpoints are identified by their host and the first two segments of the
+// request path, e.g. 'acme-v02.api.letsencrypt.org/acme/order'.
+// HTTP clients are built whenever an ACME issuer is set up, so a single
+// CircuitBreaker is shared between them to keep the state of endpoints.
+type CircuitBreaker struct {
+	opts CircuitBreakerOptions
+
+	// now is used to access the current time and is overridden in tests
+	now func() time.Time
+
+	lock     sync.Mutex
+	circuits map[string]*circuit
+}
+
+// NewCircuitBreakerClient takes a *http.Client and returns a *http.Client that
+// has its RoundTripper wrapped with the given CircuitBreaker. The client is
+// returned as is if cb is nil or circuit breaking is disabled.
+func NewCircuitBreakerClient(cb *CircuitBreaker, client *http.Client) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if client.Transport == nil {
+		client.Transport = http.DefaultTransport
+	}
+
+	if cb != nil && cb.opts.FailureThreshold > 0 {
+		client.Transport = cb.RoundTripper(client.Transport)
+	}
+
+	return client
+}
+
+// NewCircuitBreaker returns a CircuitBreaker with no failed endpoints.
+func NewCircuitBreaker(opts CircuitBreakerOptions) *CircuitBreaker {
+	return &CircuitBreaker{
+		opts:     opts,
+		now:      time.Now,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+// RoundTripper returns a http.RoundTripper that sends requests using rt
+// unless the circuit of their endpoint is open, and records their outcome.
+func (cb *CircuitBreaker) RoundTripper(rt http.RoundTripper) http.RoundTripper {
+	return &circuitBreakerRoundTripper{cb: cb, wrappedRT: rt}
+}
+
+// circuitBreakerRoundTripper is a http.RoundTripper short-circuiting requests
+// using the circuits of a CircuitBreaker.
+type circuitBreakerRoundTripper struct {
+	cb        *CircuitBreaker
+	wrappedRT http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *circuitBreakerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := req.URL.Host + pathProcessor(req.URL.Path)
+	if err := rt.cb.allow(endpoint); err != nil {
+		return nil, err
+	}
+
+	resp, err := rt.wrappedRT.RoundTrip(req)
+	// requests cancelled by the caller say nothing about the endpoint's health
+	if req.Context().Err() != nil {
+		rt.cb.release(endpoint)
+	} else {
+		rt.cb.record(endpoint, isFailure(resp, err))
+	}
+
+	return resp, err
+}
+
+// allow returns an error if requests to the endpoint are not currently
+// permitted.
+func (cb *CircuitBreaker) allow(endpoint string) error {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	c, ok := cb.circuits[endpoint]
+	if !ok {
+		return nil
+	}
+
+	switch c.state {
+	case circuitOpen:
+		if cb.now().Before(c.openUntil) {
+			return &ErrCircuitOpen{Endpoint: endpoint, Until: c.openUntil}
+		}
+		// let a single trial request through
+		c.state = circuitHalfOpen
+		return nil
+	case circuitHalfOpen:
+		// a trial request is already in flight
+		return &ErrCircuitOpen{Endpoint: endpoint, Until: c.openUntil}
+	default:
+		return nil
+	}
+}
+
+// record updates the state of the endpoint's circuit with the outcome of a
+// request.
+func (cb *CircuitBreaker) record(endpoint string, failed bool) {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	c, ok := cb.circuits[endpoint]
+	if !failed {
+		if ok {
+			delete(cb.circuits, endpoint)
+		}
+		return
+	}
+
+	if !ok {
+		c = &circuit{}
+		cb.circuits[endpoint] = c
+	}
+
+	c.failures++
+	if c.state == circuitHalfOpen || c.failures >= cb.opts.FailureThreshold {
+		c.state = circuitOpen
+		c.openUntil = cb.now().Add(cb.opts.Cooldown)
+	}
+}
+
+// release returns a half-open circuit to the open state without changing its
+// cool down, allowing the next request to be used as the trial request.
+func (cb *CircuitBreaker) release(endpoint string) {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	if c, ok := cb.circuits[endpoint]; ok && c.state == circuitHalfOpen {
+		c.state = circuitOpen
+	}
+}
+
+// isFailure returns true if the outcome of a request indicates the endpoint
+// is unhealthy. Client errors, including 'badNonce' errors, are not failures
+// as they show the endpoint is processing requests.
+func isFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	status := http.StatusInternalServerError
+	calls := 0
+	rt := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: status}, nil
+	})
+
+	now := time.Now()
+	cb := NewCircuitBreaker(CircuitBreakerOptions{FailureThreshold: 2, Cooldown: time.Minute})
+	cb.now = func() time.Time { return now }
+	client := cb.RoundTripper(rt)
+
+	orderReq := httptest.NewRequest(http.MethodPost, "https://acme.example.com/acme/order/1", nil)
+	dirReq := httptest.NewRequest(http.MethodGet, "https://acme.example.com/directory", nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.RoundTrip(orderReq); err != nil {
+			t.Fatalf("unexpected error before threshold reached: %v", err)
+		}
+	}
+	if _, err := client.RoundTrip(orderReq); err == nil {
+		t.Errorf("expected request to be short-circuited after threshold reached")
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 requests to be sent but got %d", calls)
+	}
+
+	// other endpoints are not affected
+	if _, err := client.RoundTrip(dirReq); err != nil {
+		t.Errorf("unexpected error for other endpoint: %v", err)
+	}
+
+	// after the cooldown a trial request is sent, and a success closes the
+	// circuit
+	now = now.Add(time.Minute)
+	status = http.StatusOK
+	if _, err := client.RoundTrip(orderReq); err != nil {
+		t.Errorf("expected trial request to be sent after cooldown: %v", err)
+	}
+	if _, err := client.RoundTrip(orderReq); err != nil {
+		t.Errorf("expected circuit to be closed after successful trial request: %v", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	rt := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusBadRequest}, nil
+	})
+	client := NewCircuitBreaker(CircuitBreakerOptions{FailureThreshold: 1, Cooldown: time.Minute}).RoundTripper(rt)
+
+	req := httptest.NewRequest(http.MethodPost, "https://acme.example.com/acme/new-order", nil)
+	for i := 0; i < 3; i++ {
+		if _, err := client.RoundTrip(req); err != nil {
+			t.Fatalf("unexpected error for badNonce style response: %v", err)
+		}
+	}
+}
+
+func TestCircuitBreakerSharedBetweenClients(t *testing.T) {
+	rt := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable}, nil
+	})
+	cb := NewCircuitBreaker(CircuitBreakerOptions{FailureThreshold: 2, Cooldown: time.Minute})
+
+	req := httptest.NewRequest(http.MethodPost, "https://acme.example.com/acme/new-order", nil)
+	// the failures of clients built for different issuers add up
+	for i := 0; i < 2; i++ {
+		if _, err := cb.RoundTripper(rt).RoundTrip(req); err != nil {
+			t.Fatalf("unexpected error before threshold reached: %v", err)
+		}
+	}
+	if _, err := cb.RoundTripper(rt).RoundTrip(req); err == nil {
+		t.Errorf("expected request of a new client to be short-circuited after threshold reached")
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	fn := RetryBackoff(
+		ExponentialBackoff{Base: time.Second, Max: time.Minute, MaxRetries: 2},
+		ExponentialBackoff{Base: time.Millisecond, Max: time.Second, MaxRetries: 1},
+	)
+	req := httptest.NewRequest(http.MethodPost, "https://acme.example.com/acme/new-order", nil)
+
+	serverErr := &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}}
+	if d := fn(2, req, serverErr); d < 2*time.Second {
+		t.Errorf("expected second retry to wait at least 2s, got %s", d)
+	}
+	if d := fn(3, req, serverErr); d > 0 {
+		t.Errorf("expected no more retries after MaxRetries, got %s", d)
+	}
+
+	badNonce := &http.Response{StatusCode: http.StatusBadRequest, Header: http.Header{}}
+	if d := fn(1, req, badNonce); d <= 0 || d > time.Second {
+		t.Errorf("expected badNonce retry to use badNonce policy, got %s", d)
+	}
+	if d := fn(2, req, badNonce); d > 0 {
+		t.Errorf("expected no more badNonce retries, got %s", d)
+	}
+
+	retryAfter := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": []string{"30"}}}
+	if d := fn(1, req, retryAfter); d != 30*time.Second {
+		t.Errorf("expected Retry-After header to be honoured, got %s", d)
+	}
+}
@@ -18,6 +18,7 @@ package client
 
 import (
 	"context"
+	"crypto"
 	"fmt"
 
 	"golang.org/x/crypto/acme"
@@ -43,6 +44,7 @@ type FakeACME struct {
 	FakeDNS01ChallengeRecord    func(token string) (string, error)
 	FakeDiscover                func(ctx context.Context) (acme.Directory, error)
 	FakeUpdateReg               func(ctx context.Context, a *acme.Account) (*acme.Account, error)
+	FakeRevokeCert              func(ctx context.Context, key crypto.Signer, cert []byte, reason acme.CRLReasonCode) error
 }
 
 var _ Interface = &FakeACME{}
@@ -153,3 +155,10 @@ func (f *FakeACME) UpdateReg(ctx context.Context, a *acme.Account) (*acme.Accoun
 	}
 	return nil, fmt.Errorf("UpdateReg not implemented")
 }
+
+func (f *FakeACME) RevokeCert(ctx context.Context, key crypto.Signer, cert []byte, reason acme.CRLReasonCode) error {
+	if f.FakeRevokeCert != nil {
+		return f.FakeRevokeCert(ctx, key, cert, reason)
+	}
+	return fmt.Errorf("RevokeCert not implemented")
+}
@@ -18,6 +18,7 @@ package client
 
 import (
 	"context"
+	"crypto"
 
 	"golang.org/x/crypto/acme"
 )
@@ -38,6 +39,7 @@ type Interface interface {
 	DNS01ChallengeRecord(token string) (string, error)
 	Discover(ctx context.Context) (acme.Directory, error)
 	UpdateReg(ctx context.Context, a *acme.Account) (*acme.Account, error)
+	RevokeCert(ctx context.Context, key crypto.Signer, cert []byte, reason acme.CRLReasonCode) error
 }
 
 var _ Interface = &acme.Client{}
@@ -18,6 +18,7 @@ package middleware
 
 import (
 	"context"
+	"crypto"
 	"time"
 
 	"golang.org/x/crypto/acme"
@@ -167,3 +168,12 @@ func (l *Logger) UpdateReg(ctx context.Context, a *acme.Account) (*acme.Account,
 
 	return l.baseCl.UpdateReg(ctx, a)
 }
+
+func (l *Logger) RevokeCert(ctx context.Context, key crypto.Signer, cert []byte, reason acme.CRLReasonCode) error {
+	klog.Infof("Calling RevokeCert")
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return l.baseCl.RevokeCert(ctx, key, cert, reason)
+}
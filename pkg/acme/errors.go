@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acme
+
+import (
+	"strings"
+
+	acmeapi "golang.org/x/crypto/acme"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+)
+
+// problemTypePrefixes are the namespaces used for ACME problem document types.
+// RFC 8555 servers use 'urn:ietf:params:acme:error:', whilst servers
+// implementing earlier drafts of the specification use 'urn:acme:error:'.
+var problemTypePrefixes = []string{"urn:ietf:params:acme:error:", "urn:acme:error:"}
+
+// problemErrorTypes maps the suffix of an ACME problem document type to the
+// ACMEErrorType it is recorded as.
+var problemErrorTypes = map[string]cmacme.ACMEErrorType{
+	"rateLimited":  cmacme.ACMEErrorRateLimited,
+	"unauthorized": cmacme.ACMEErrorUnauthorized,
+	"caa":          cmacme.ACMEErrorCAA,
+	"dns":          cmacme.ACMEErrorDNS,
+	"connection":   cmacme.ACMEErrorConnection,
+}
+
+// ErrorType returns the ACMEErrorType describing the given error.
+// If err is an AuthorizationError, the type of the first ACME problem document
+// it contains is returned.
+// If err does not contain an ACME problem document, an empty string is
+// returned.
+func ErrorType(err error) cmacme.ACMEErrorType {
+	switch err := err.(type) {
+	case *acmeapi.Error:
+		return ProblemErrorType(err.ProblemType)
+	case *acmeapi.AuthorizationError:
+		for _, e := range err.Errors {
+			if t := ErrorType(e); t != "" {
+				return t
+			}
+		}
+	}
+	return ""
+}
+
+// ProblemErrorType returns the ACMEErrorType for the given ACME problem
+// document type, e.g. 'urn:ietf:params:acme:error:rateLimited'.
+// Problem types that are not recognised are mapped to ACMEErrorOther.
+func ProblemErrorType(problemType string) cmacme.ACMEErrorType {
+	for _, prefix := range problemTypePrefixes {
+		if !strings.HasPrefix(problemType, prefix) {
+			continue
+		}
+		if t, ok := problemErrorTypes[strings.TrimPrefix(problemType, prefix)]; ok {
+			return t
+		}
+		break
+	}
+	return cmacme.ACMEErrorOther
+}
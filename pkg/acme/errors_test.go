@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acme
+
+import (
+	"errors"
+	"testing"
+
+	acmeapi "golang.org/x/crypto/acme"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+)
+
+func TestErrorType(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected cmacme.ACMEErrorType
+	}{
+		"nil error": {
+			err:      nil,
+			expected: "",
+		},
+		"non-ACME error": {
+			err:      errors.New("connection refused"),
+			expected: "",
+		},
+		"rate limited": {
+			err:      &acmeapi.Error{ProblemType: "urn:ietf:params:acme:error:rateLimited"},
+			expected: cmacme.ACMEErrorRateLimited,
+		},
+		"unauthorized": {
+			err:      &acmeapi.Error{ProblemType: "urn:ietf:params:acme:error:unauthorized"},
+			expected: cmacme.ACMEErrorUnauthorized,
+		},
+		"caa": {
+			err:      &acmeapi.Error{ProblemType: "urn:ietf:params:acme:error:caa"},
+			expected: cmacme.ACMEErrorCAA,
+		},
+		"dns": {
+			err:      &acmeapi.Error{ProblemType: "urn:ietf:params:acme:error:dns"},
+			expected: cmacme.ACMEErrorDNS,
+		},
+		"connection": {
+			err:      &acmeapi.Error{ProblemType: "urn:ietf:params:acme:error:connection"},
+			expected: cmacme.ACMEErrorConnection,
+		},
+		"legacy problem type namespace": {
+			err:      &acmeapi.Error{ProblemType: "urn:acme:error:rateLimited"},
+			expected: cmacme.ACMEErrorRateLimited,
+		},
+		"unrecognised problem type": {
+			err:      &acmeapi.Error{ProblemType: "urn:ietf:params:acme:error:badCSR"},
+			expected: cmacme.ACMEErrorOther,
+		},
+		"problem type in an unknown namespace": {
+			err:      &acmeapi.Error{ProblemType: "urn:example:error:dns"},
+			expected: cmacme.ACMEErrorOther,
+		},
+		"authorization error containing an ACME error": {
+			err: &acmeapi.AuthorizationError{
+				Errors: []error{
+					errors.New("not an ACME error"),
+					&acmeapi.Error{ProblemType: "urn:ietf:params:acme:error:caa"},
+				},
+			},
+			expected: cmacme.ACMEErrorCAA,
+		},
+		"authorization error without an ACME error": {
+			err: &acmeapi.AuthorizationError{
+				Errors: []error{errors.New("not an ACME error")},
+			},
+			expected: "",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := ErrorType(test.err); got != test.expected {
+				t.Errorf("expected error type %q but got %q", test.expected, got)
+			}
+		})
+	}
+}
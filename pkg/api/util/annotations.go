@@ -0,0 +1,356 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/util/consumerpatch"
+)
+
+// IngressKind is the kind used to register annotations that are read from
+// Ingress resources by the ingress-shim controller.
+const IngressKind = "Ingress"
+
+// AnnotationValidateFunc validates the value of an annotation, returning an
+// error describing why the value is invalid.
+type AnnotationValidateFunc func(value string) error
+
+// AnnotationSpec describes an annotation that forms part of the cert-manager
+// API surface.
+type AnnotationSpec struct {
+	// Key is the full annotation key, e.g. cert-manager.io/issuer
+	Key string
+	// Kinds are the resource kinds this annotation is read from.
+	Kinds []string
+	// Description is a short human readable description of the annotation.
+	Description string
+	// Validate, if set, is used to check the annotation's value.
+	Validate AnnotationValidateFunc
+}
+
+// knownAnnotations is the registry of all user facing annotations that are
+// read by cert-manager components, keyed by annotation key.
+var knownAnnotations = map[string]AnnotationSpec{}
+
+// RegisterAnnotation adds the given annotation to the registry of known
+// annotations. It will panic if an annotation with the same key has already
+// been registered.
+func RegisterAnnotation(spec AnnotationSpec) {
+	if _, ok := knownAnnotations[spec.Key]; ok {
+		panic(fmt.Sprintf("annotation %q registered twice", spec.Key))
+	}
+	knownAnnotations[spec.Key] = spec
+}
+
+func init() {
+	for _, spec := range []AnnotationSpec{
+		{
+			Key:         cmapi.IngressIssuerNameAnnotationKey,
+			Kinds:       []string{IngressKind},
+			Description: "Name of the Issuer used to create Certificates for this Ingress.",
+			Validate:    validateNonEmpty,
+		},
+		{
+			Key:         cmapi.IngressClusterIssuerNameAnnotationKey,
+			Kinds:       []string{IngressKind},
+			Description: "Name of the ClusterIssuer used to create Certificates for this Ingress.",
+			Validate:    validateNonEmpty,
+		},
+		{
+			Key:         cmapi.IssuerKindAnnotationKey,
+			Kinds:       []string{IngressKind},
+			Description: "Kind of the external issuer referenced by the cert-manager.io/issuer annotation.",
+			Validate:    validateNonEmpty,
+		},
+		{
+			Key:         cmapi.IssuerGroupAnnotationKey,
+			Kinds:       []string{IngressKind},
+			Description: "API group of the external issuer referenced by the cert-manager.io/issuer annotation.",
+			Validate:    validateNonEmpty,
+		},
+		{
+			Key:         cmapi.CommonNameAnnotationKey,
+			Kinds:       []string{IngressKind},
+			Description: "Common name to set on Certificates created for this Ingress.",
+			Validate:    validateCommonName,
+		},
+		{
+			Key:         cmapi.IngressACMEIssuerHTTP01IngressClassAnnotationKey,
+			Kinds:       []string{IngressKind},
+			Description: "Ingress class used by the ACME HTTP01 solver for Certificates created for this Ingress.",
+			Validate:    validateNonEmpty,
+		},
+		{
+			Key:         cmacme.IngressEditInPlaceAnnotationKey,
+			Kinds:       []string{IngressKind},
+			Description: "If 'true', the ACME HTTP01 solver will edit this Ingress in place instead of creating a new one.",
+			Validate:    validateBool,
+		},
+		{
+			Key:         cmapi.IssueTemporaryCertificateAnnotation,
+			Kinds:       []string{cmapi.CertificateKind},
+			Description: "If 'true', a temporary self signed certificate is stored in the Secret whilst the real certificate is being issued.",
+			Validate:    validateBool,
+		},
+		{
+			Key:         cmapi.PausedAnnotationKey,
+			Kinds:       []string{cmapi.CertificateKind, cmapi.CertificateRequestKind, "Order", "Challenge"},
+			Description: "If 'true', cert-manager will not reconcile this resource until the annotation is removed.",
+			Validate:    validateBool,
+		},
+		{
+			Key:         cmapi.RotatePrivateKeyAnnotationKey,
+			Kinds:       []string{cmapi.CertificateKind},
+			Description: "Revision of the next issuance of the Certificate for which a new private key is generated, regardless of the rotation policy.",
+			Validate:    validatePositiveInt,
+		},
+		{
+			Key:         cmapi.EventVerbosityAnnotationKey,
+			Kinds:       []string{cmapi.CertificateKind, cmapi.CertificateRequestKind, "Order", "Challenge"},
+			Description: "If 'quiet', only Warning events are recorded for this resource and the resources created for it. If 'none', no events are recorded.",
+			Validate:    validateEventVerbosity,
+		},
+		{
+			Key:         cmacme.ACMECertificateHTTP01IngressNameOverride,
+			Kinds:       []string{cmapi.CertificateKind},
+			Description: "Overrides the ingress name used by the ACME HTTP01 solver.",
+			Validate:    validateNonEmpty,
+		},
+		{
+			Key:         cmacme.ACMECertificateHTTP01IngressClassOverride,
+			Kinds:       []string{cmapi.CertificateKind},
+			Description: "Overrides the ingress class used by the ACME HTTP01 solver.",
+			Validate:    validateNonEmpty,
+		},
+		{
+			Key:         cmapi.CertificateRequestRevisionAnnotationKey,
+			Kinds:       []string{cmapi.CertificateRequestKind},
+			Description: "Revision of the owning Certificate this CertificateRequest was created for.",
+			Validate:    validatePositiveInt,
+		},
+		{
+			Key:         cmapi.CertificateRequestPrivateKeyAnnotationKey,
+			Kinds:       []string{cmapi.CertificateRequestKind},
+			Description: "Name of the Secret containing the private key, used by the SelfSigned issuer.",
+			Validate:    validateNonEmpty,
+		},
+		{
+			Key:         cmapi.CertificateRequestExternalSigningAnnotationKey,
+			Kinds:       []string{cmapi.CertificateRequestKind},
+			Description: "If 'true', the CertificateRequest is not processed by any issuer and is signed outside of cert-manager. Set by cert-manager.",
+			Validate:    validateBool,
+		},
+		{
+			Key:         cmapi.CertificateRequestPodNameAnnotationKey,
+			Kinds:       []string{cmapi.CertificateRequestKind},
+			Description: "Name of the Pod a trusted agent created the CertificateRequest for. Used to bind the request to the identity of the Pod.",
+			Validate:    validateNonEmpty,
+		},
+		{
+			Key:         cmapi.CertificateRequestPodServiceAccountAnnotationKey,
+			Kinds:       []string{cmapi.CertificateRequestKind},
+			Description: "Service account of the Pod the CertificateRequest was created for, verified by the webhook with --enforce-pod-identity. Returned by the serviceAccount function of issuer request defaults if the controller is run with --trust-pod-service-account-annotation.",
+			Validate:    validateNonEmpty,
+		},
+		{
+			Key:         cmapi.SecretConsumerPatchesAnnotationKey,
+			Kinds:       []string{cmapi.CertificateKind},
+			Description: "JSON encoded list of JSON patches applied to the apps/v1 Deployments, StatefulSets and DaemonSets consuming the Secret after a certificate has been stored in it, if enabled with --enable-secret-consumer-patches.",
+			Validate:    validateSecretConsumerPatches,
+		},
+		{
+			Key:         cmapi.VenafiCustomFieldsAnnotationKey,
+			Kinds:       []string{cmapi.CertificateKind, cmapi.CertificateRequestKind},
+			Description: "JSON encoded list of custom fields passed on to the Venafi issuer.",
+		},
+		{
+			Key:         cmapi.IPSANAnnotationKey,
+			Kinds:       []string{"Secret"},
+			Description: "Comma separated list of IP address SANs of the stored certificate. Set by cert-manager.",
+			Validate:    validateIPList,
+		},
+		{
+			Key:         cmapi.URISANAnnotationKey,
+			Kinds:       []string{"Secret"},
+			Description: "Comma separated list of URI SANs of the stored certificate. Set by cert-manager.",
+			Validate:    validateURIList,
+		},
+	} {
+		RegisterAnnotation(spec)
+	}
+}
+
+// IsPaused returns true if reconciliation of the given resource has been
+// paused by setting the cert-manager.io/paused annotation to "true".
+func IsPaused(obj metav1.Object) bool {
+	return obj.GetAnnotations()[cmapi.PausedAnnotationKey] == "true"
+}
+
+// IsExternalSigningRequest returns true if the CertificateRequest is to be
+// signed outside of cert-manager, as denoted by the
+// cert-manager.io/external-signing annotation.
+func IsExternalSigningRequest(cr *cmapi.CertificateRequest) bool {
+	return cr.Annotations[cmapi.CertificateRequestExternalSigningAnnotationKey] == "true"
+}
+
+// IsPrivateKeyRotationRequested returns true if a new private key has been
+// requested for the next issuance of crt by setting the
+// cert-manager.io/rotate-private-key annotation to its revision.
+func IsPrivateKeyRotationRequested(crt *cmapi.Certificate) bool {
+	value, ok := crt.Annotations[cmapi.RotatePrivateKeyAnnotationKey]
+	if !ok {
+		return false
+	}
+	revision, err := strconv.Atoi(value)
+	if err != nil {
+		return false
+	}
+	// revisions begin from 1
+	nextRevision := 1
+	if crt.Status.Revision != nil {
+		nextRevision = *crt.Status.Revision + 1
+	}
+	return revision == nextRevision
+}
+
+// KnownAnnotations returns all registered annotations, sorted by key.
+func KnownAnnotations() []AnnotationSpec {
+	var specs []AnnotationSpec
+	for _, spec := range knownAnnotations {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Key < specs[j].Key
+	})
+	return specs
+}
+
+// AnnotationsForKind returns all registered annotations that are read from
+// resources of the given kind, sorted by key. The kind is matched case
+// insensitively.
+func AnnotationsForKind(kind string) []AnnotationSpec {
+	var specs []AnnotationSpec
+	for _, spec := range KnownAnnotations() {
+		for _, k := range spec.Kinds {
+			if strings.EqualFold(k, kind) {
+				specs = append(specs, spec)
+				break
+			}
+		}
+	}
+	return specs
+}
+
+// ValidateAnnotations validates the values of all known annotations in the
+// given map that apply to the given kind. Unknown annotations are ignored.
+// The returned map is keyed by the annotation key that failed validation.
+func ValidateAnnotations(kind string, annotations map[string]string) map[string]error {
+	errs := make(map[string]error)
+	for _, spec := range AnnotationsForKind(kind) {
+		value, ok := annotations[spec.Key]
+		if !ok || spec.Validate == nil {
+			continue
+		}
+		if err := spec.Validate(value); err != nil {
+			errs[spec.Key] = err
+		}
+	}
+	return errs
+}
+
+func validateNonEmpty(value string) error {
+	if len(value) == 0 {
+		return fmt.Errorf("must not be empty")
+	}
+	return nil
+}
+
+func validateBool(value string) error {
+	if _, err := strconv.ParseBool(value); err != nil {
+		return fmt.Errorf("must be a boolean value, e.g. 'true' or 'false'")
+	}
+	return nil
+}
+
+func validatePositiveInt(value string) error {
+	i, err := strconv.Atoi(value)
+	if err != nil || i < 1 {
+		return fmt.Errorf("must be a positive integer")
+	}
+	return nil
+}
+
+func validateEventVerbosity(value string) error {
+	switch value {
+	case cmapi.EventVerbosityNormal, cmapi.EventVerbosityQuiet, cmapi.EventVerbosityNone:
+		return nil
+	}
+	return fmt.Errorf("must be one of %q, %q or %q", cmapi.EventVerbosityNormal, cmapi.EventVerbosityQuiet, cmapi.EventVerbosityNone)
+}
+
+func validateSecretConsumerPatches(value string) error {
+	_, err := consumerpatch.Parse(value)
+	return err
+}
+
+func validateCommonName(value string) error {
+	if err := validateNonEmpty(value); err != nil {
+		return err
+	}
+	if len(value) > 64 {
+		return fmt.Errorf("must be no more than 64 characters")
+	}
+	return nil
+}
+
+func validateIPList(value string) error {
+	for _, ip := range splitList(value) {
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("invalid IP address %q", ip)
+		}
+	}
+	return nil
+}
+
+func validateURIList(value string) error {
+	for _, uri := range splitList(value) {
+		if _, err := url.Parse(uri); err != nil {
+			return fmt.Errorf("invalid URI %q: %v", uri, err)
+		}
+	}
+	return nil
+}
+
+func splitList(value string) []string {
+	var out []string
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); len(s) > 0 {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+)
+
+func TestAnnotationsForKind(t *testing.T) {
+	specs := AnnotationsForKind("ingress")
+	if len(specs) == 0 {
+		t.Fatalf("expected annotations to be registered for Ingress resources")
+	}
+	for _, spec := range specs {
+		if spec.Key == cmapi.IssueTemporaryCertificateAnnotation {
+			t.Errorf("did not expect %q to be registered for Ingress resources", spec.Key)
+		}
+	}
+	if len(AnnotationsForKind("Unknown")) != 0 {
+		t.Errorf("expected no annotations to be registered for unknown kind")
+	}
+}
+
+func TestIsPaused(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		exp         bool
+	}{
+		"no annotations": {},
+		"paused": {
+			annotations: map[string]string{cmapi.PausedAnnotationKey: "true"},
+			exp:         true,
+		},
+		"not paused": {
+			annotations: map[string]string{cmapi.PausedAnnotationKey: "false"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := &cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			if got := IsPaused(crt); got != test.exp {
+				t.Errorf("expected IsPaused to return %t but got %t", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestIsPrivateKeyRotationRequested(t *testing.T) {
+	revision := 2
+	tests := map[string]struct {
+		annotations map[string]string
+		revision    *int
+		exp         bool
+	}{
+		"no annotations": {},
+		"rotation requested for first issuance": {
+			annotations: map[string]string{cmapi.RotatePrivateKeyAnnotationKey: "1"},
+			exp:         true,
+		},
+		"rotation requested for next issuance": {
+			annotations: map[string]string{cmapi.RotatePrivateKeyAnnotationKey: "3"},
+			revision:    &revision,
+			exp:         true,
+		},
+		"rotation requested for past issuance": {
+			annotations: map[string]string{cmapi.RotatePrivateKeyAnnotationKey: "2"},
+			revision:    &revision,
+		},
+		"invalid revision": {
+			annotations: map[string]string{cmapi.RotatePrivateKeyAnnotationKey: "next"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations},
+				Status:     cmapi.CertificateStatus{Revision: test.revision},
+			}
+			if got := IsPrivateKeyRotationRequested(crt); got != test.exp {
+				t.Errorf("expected IsPrivateKeyRotationRequested to return %t but got %t", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestValidateAnnotations(t *testing.T) {
+	tests := map[string]struct {
+		kind        string
+		annotations map[string]string
+		expErrKeys  []string
+	}{
+		"valid temporary certificate annotation": {
+			kind:        cmapi.CertificateKind,
+			annotations: map[string]string{cmapi.IssueTemporaryCertificateAnnotation: "true"},
+		},
+		"invalid temporary certificate annotation": {
+			kind:        cmapi.CertificateKind,
+			annotations: map[string]string{cmapi.IssueTemporaryCertificateAnnotation: "yes please"},
+			expErrKeys:  []string{cmapi.IssueTemporaryCertificateAnnotation},
+		},
+		"annotation not applicable to kind is ignored": {
+			kind:        cmapi.CertificateRequestKind,
+			annotations: map[string]string{cmapi.IssueTemporaryCertificateAnnotation: "yes please"},
+		},
+		"unknown annotations are ignored": {
+			kind:        cmapi.CertificateKind,
+			annotations: map[string]string{"example.com/foo": ""},
+		},
+		"invalid revision annotation": {
+			kind:        cmapi.CertificateRequestKind,
+			annotations: map[string]string{cmapi.CertificateRequestRevisionAnnotationKey: "0"},
+			expErrKeys:  []string{cmapi.CertificateRequestRevisionAnnotationKey},
+		},
+		"valid event verbosity annotation": {
+			kind:        cmapi.CertificateKind,
+			annotations: map[string]string{cmapi.EventVerbosityAnnotationKey: cmapi.EventVerbosityQuiet},
+		},
+		"invalid event verbosity annotation": {
+			kind:        cmapi.CertificateKind,
+			annotations: map[string]string{cmapi.EventVerbosityAnnotationKey: "silent"},
+			expErrKeys:  []string{cmapi.EventVerbosityAnnotationKey},
+		},
+		"empty issuer name on Ingress": {
+			kind: IngressKind,
+			annotations: map[string]string{
+				cmapi.IngressIssuerNameAnnotationKey: "",
+				cmapi.CommonNameAnnotationKey:        "example.com",
+			},
+			expErrKeys: []string{cmapi.IngressIssuerNameAnnotationKey},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs := ValidateAnnotations(test.kind, test.annotations)
+			if len(errs) != len(test.expErrKeys) {
+				t.Fatalf("expected %d errors but got %d: %v", len(test.expErrKeys), len(errs), errs)
+			}
+			for _, k := range test.expErrKeys {
+				if _, ok := errs[k]; !ok {
+					t.Errorf("expected error for annotation %q but got none", k)
+				}
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"k8s.io/klog"
 	"k8s.io/utils/clock"
 
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 )
@@ -92,6 +93,22 @@ func SetIssuerCondition(i cmapi.GenericIssuer, conditionType cmapi.IssuerConditi
 	klog.Infof("Setting lastTransitionTime for Issuer %q condition %q to %v", i.GetObjectMeta().Name, conditionType, nowTime.Time)
 }
 
+// RemoveIssuerCondition will remove any condition with this condition type
+// from the given GenericIssuer.
+func RemoveIssuerCondition(i cmapi.GenericIssuer, conditionType cmapi.IssuerConditionType) {
+	var updatedConditions []cmapi.IssuerCondition
+
+	// Search through existing conditions
+	for _, cond := range i.GetStatus().Conditions {
+		// Only add unrelated conditions
+		if cond.Type != conditionType {
+			updatedConditions = append(updatedConditions, cond)
+		}
+	}
+
+	i.GetStatus().Conditions = updatedConditions
+}
+
 // CertificateHasCondition will return true if the given Certificate has a
 // condition matching the provided CertificateCondition.
 // Only the Type and Status field will be used in the comparison, meaning that
@@ -254,6 +271,29 @@ func CertificateRequestHasCondition(cr *cmapi.CertificateRequest, c cmapi.Certif
 	return false
 }
 
+// CertificateRequestIsApproved returns true if the CertificateRequest has an
+// Approved condition with status True.
+func CertificateRequestIsApproved(cr *cmapi.CertificateRequest) bool {
+	return CertificateRequestHasCondition(cr, cmapi.CertificateRequestCondition{
+		Type:   cmapi.CertificateRequestConditionApproved,
+		Status: cmmeta.ConditionTrue,
+	})
+}
+
+// CertificateRequestDeniedCondition returns the Denied condition of the
+// CertificateRequest if its status is True, and nil otherwise.
+func CertificateRequestDeniedCondition(cr *cmapi.CertificateRequest) *cmapi.CertificateRequestCondition {
+	if cr == nil {
+		return nil
+	}
+	for i, cond := range cr.Status.Conditions {
+		if cond.Type == cmapi.CertificateRequestConditionDenied && cond.Status == cmmeta.ConditionTrue {
+			return &cr.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
 // This returns the status reason of a CertificateRequest. The order of reason
 // hierarchy is 'Failed' -> 'Ready' -> 'Pending' -> ''
 func CertificateRequestReadyReason(cr *cmapi.CertificateRequest) string {
@@ -306,3 +346,59 @@ func CertificateRequestHasInvalidRequest(cr *cmapi.CertificateRequest) bool {
 
 	return false
 }
+
+// GetChallengeCondition returns the condition of the given type of the
+// Challenge, or nil if it is not set.
+func GetChallengeCondition(ch *cmacme.Challenge, conditionType cmacme.ChallengeConditionType) *cmacme.ChallengeCondition {
+	for _, cond := range ch.Status.Conditions {
+		if cond.Type == conditionType {
+			return &cond
+		}
+	}
+	return nil
+}
+
+// SetChallengeCondition will set a 'condition' on the given Challenge.
+// - If no condition of the same type already exists, the condition will be
+//   inserted with the LastTransitionTime set to the current time.
+// - If a condition of the same type and state already exists, the condition
+//   will be updated but the LastTransitionTime will not be modified.
+// - If a condition of the same type and different state already exists, the
+//   condition will be updated and the LastTransitionTime set to the current
+//   time.
+func SetChallengeCondition(ch *cmacme.Challenge, conditionType cmacme.ChallengeConditionType, status cmmeta.ConditionStatus, reason, message string) {
+	newCondition := cmacme.ChallengeCondition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+
+	nowTime := metav1.NewTime(Clock.Now())
+	newCondition.LastTransitionTime = &nowTime
+
+	// Search through existing conditions
+	for idx, cond := range ch.Status.Conditions {
+		// Skip unrelated conditions
+		if cond.Type != conditionType {
+			continue
+		}
+
+		// If this update doesn't contain a state transition, we don't update
+		// the conditions LastTransitionTime to Now()
+		if cond.Status == status {
+			newCondition.LastTransitionTime = cond.LastTransitionTime
+		} else {
+			klog.Infof("Found status change for Challenge %q condition %q: %q -> %q; setting lastTransitionTime to %v", ch.Name, conditionType, cond.Status, status, nowTime.Time)
+		}
+
+		// Overwrite the existing condition
+		ch.Status.Conditions[idx] = newCondition
+		return
+	}
+
+	// If we've not found an existing condition of this type, we simply insert
+	// the new condition into the slice.
+	ch.Status.Conditions = append(ch.Status.Conditions, newCondition)
+	klog.Infof("Setting lastTransitionTime for Challenge %q condition %q to %v", ch.Name, conditionType, nowTime.Time)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2019 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+// ReasonDeprecatedAPIUsage is the reason of the Deprecated condition of
+// resources whose spec was written using deprecated APIs.
+const ReasonDeprecatedAPIUsage = "DeprecatedAPIUsage"
+
+// deprecatedAPIVersions maps the deprecated apiVersions of the cert-manager.io
+// API group to the apiVersion that replaces them.
+var deprecatedAPIVersions = map[string]string{
+	"cert-manager.io/v1alpha2": "cert-manager.io/v1beta1",
+	"cert-manager.io/v1alpha3": "cert-manager.io/v1beta1",
+}
+
+// deprecatedField is a field of a deprecated apiVersion that has moved in
+// the apiVersion that replaces it.
+type deprecatedField struct {
+	path        []string
+	replacement string
+}
+
+// deprecatedCertificateFields are the spec fields of Certificates that have
+// moved in cert-manager.io/v1beta1, by the apiVersion they were deprecated in.
+var deprecatedCertificateFields = map[string][]deprecatedField{
+	"cert-manager.io/v1alpha2": {
+		{path: []string{"spec", "organization"}, replacement: "spec.subject.organizations"},
+		{path: []string{"spec", "keySize"}, replacement: "spec.privateKey.size"},
+		{path: []string{"spec", "keyAlgorithm"}, replacement: "spec.privateKey.algorithm"},
+		{path: []string{"spec", "keyEncoding"}, replacement: "spec.privateKey.encoding"},
+	},
+	"cert-manager.io/v1alpha3": {
+		{path: []string{"spec", "keySize"}, replacement: "spec.privateKey.size"},
+		{path: []string{"spec", "keyAlgorithm"}, replacement: "spec.privateKey.algorithm"},
+		{path: []string{"spec", "keyEncoding"}, replacement: "spec.privateKey.encoding"},
+	},
+}
+
+// CertificateDeprecations returns how to migrate the spec of crt away from
+// the deprecated apiVersions and fields it was written with.
+// The apiVersions are read from the managed fields of the resource, which
+// record the apiVersion every field manager used. Since all versions are
+// converted to the storage version, this is the only way to tell which
+// version the resource was written with. Field managers that did not write
+// the spec, and ignoreManager, which should be the field manager of the
+// caller, are not taken into account. The field managers of the individual
+// controllers, which are prefixed with ignoreManager, are ignored as well.
+func CertificateDeprecations(crt metav1.Object, ignoreManager string) []string {
+	return deprecations(crt, deprecatedCertificateFields, ignoreManager)
+}
+
+// IssuerDeprecations returns how to migrate the spec of an Issuer or
+// ClusterIssuer away from the deprecated apiVersions it was written with.
+// See CertificateDeprecations.
+func IssuerDeprecations(issuer metav1.Object, ignoreManager string) []string {
+	return deprecations(issuer, nil, ignoreManager)
+}
+
+func deprecations(obj metav1.Object, fields map[string][]deprecatedField, ignoreManager string) []string {
+	var messages []string
+	seen := make(map[string]bool)
+	add := func(msg string) {
+		if !seen[msg] {
+			seen[msg] = true
+			messages = append(messages, msg)
+		}
+	}
+
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Manager == ignoreManager || strings.HasPrefix(entry.Manager, ignoreManager+"-") || entry.FieldsV1 == nil {
+			continue
+		}
+		replacement, ok := deprecatedAPIVersions[entry.APIVersion]
+		if !ok {
+			continue
+		}
+		var set map[string]interface{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &set); err != nil {
+			continue
+		}
+		if !hasField(set, []string{"spec"}) {
+			continue
+		}
+		add(fmt.Sprintf("apiVersion %s is deprecated, use %s instead", entry.APIVersion, replacement))
+		for _, field := range fields[entry.APIVersion] {
+			if hasField(set, field.path) {
+				add(fmt.Sprintf("field %s is deprecated, use %s of %s instead", strings.Join(field.path, "."), field.replacement, replacement))
+			}
+		}
+	}
+
+	sort.Strings(messages)
+	return messages
+}
+
+// hasField returns true if the field set of a managed fields entry, in the
+// FieldsV1 format, contains the field with the given path.
+func hasField(set map[string]interface{}, path []string) bool {
+	for _, name := range path {
+		child, ok := set["f:"+name].(map[string]interface{})
+		if !ok {
+			return false
+		}
+		set = child
+	}
+	return true
+}
+
+// SetCertificateDeprecatedCondition sets the Deprecated condition of crt if
+// its spec was written with deprecated apiVersions or fields, and removes it
+// otherwise. See CertificateDeprecations.
+func SetCertificateDeprecatedCondition(crt *cmapi.Certificate, ignoreManager string) {
+	messages := CertificateDeprecations(crt, ignoreManager)
+	if len(messages) == 0 {
+		RemoveCertificateCondition(crt, cmapi.CertificateConditionDeprecated)
+		return
+	}
+	SetCertificateCondition(crt, cmapi.CertificateConditionDeprecated, cmmeta.ConditionTrue, ReasonDeprecatedAPIUsage, strings.Join(messages, "; "))
+}
+
+// SetIssuerDeprecatedCondition sets the Deprecated condition of the Issuer or
+// ClusterIssuer if its spec was written with deprecated apiVersions, and
+// removes it otherwise. See IssuerDeprecations.
+func SetIssuerDeprecatedCondition(i cmapi.GenericIssuer, ignoreManager string) {
+	messages := IssuerDeprecations(i.GetObjectMeta(), ignoreManager)
+	if len(messages) == 0 {
+		RemoveIssuerCondition(i, cmapi.IssuerConditionDeprecated)
+		return
+	}
+	SetIssuerCondition(i, cmapi.IssuerConditionDeprecated, cmmeta.ConditionTrue, ReasonDeprecatedAPIUsage, strings.Join(messages, "; "))
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCertificateDeprecations(t *testing.T) {
+	entry := func(manager, apiVersion, fields string) metav1.ManagedFieldsEntry {
+		return metav1.ManagedFieldsEntry{
+			Manager:    manager,
+			Operation:  metav1.ManagedFieldsOperationUpdate,
+			APIVersion: apiVersion,
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(fields)},
+		}
+	}
+
+	tests := map[string]struct {
+		managedFields []metav1.ManagedFieldsEntry
+		exp           []string
+	}{
+		"no managed fields": {},
+		"spec written with v1beta1": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				entry("kubectl", "cert-manager.io/v1beta1", `{"f:spec":{"f:privateKey":{"f:size":{}}}}`),
+			},
+		},
+		"spec written with v1alpha3": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				entry("kubectl", "cert-manager.io/v1alpha3", `{"f:spec":{"f:secretName":{}}}`),
+			},
+			exp: []string{"apiVersion cert-manager.io/v1alpha3 is deprecated, use cert-manager.io/v1beta1 instead"},
+		},
+		"deprecated fields written with v1alpha2": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				entry("kubectl", "cert-manager.io/v1alpha2", `{"f:spec":{"f:keySize":{},"f:organization":{}}}`),
+			},
+			exp: []string{
+				"apiVersion cert-manager.io/v1alpha2 is deprecated, use cert-manager.io/v1beta1 instead",
+				"field spec.keySize is deprecated, use spec.privateKey.size of cert-manager.io/v1beta1 instead",
+				"field spec.organization is deprecated, use spec.subject.organizations of cert-manager.io/v1beta1 instead",
+			},
+		},
+		"only status written with v1alpha2": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				entry("kubectl", "cert-manager.io/v1alpha2", `{"f:status":{"f:conditions":{}}}`),
+			},
+		},
+		"spec written with v1alpha2 by the ignored manager": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				entry("controller", "cert-manager.io/v1alpha2", `{"f:spec":{"f:keySize":{}}}`),
+			},
+		},
+		"spec written with v1alpha2 by a controller of the ignored manager": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				entry("controller-certificateissuing", "cert-manager.io/v1alpha2", `{"f:spec":{"f:keySize":{}}}`),
+			},
+		},
+		"the same deprecation by several managers is reported once": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				entry("kubectl", "cert-manager.io/v1alpha3", `{"f:spec":{"f:secretName":{}}}`),
+				entry("helm", "cert-manager.io/v1alpha3", `{"f:spec":{"f:dnsNames":{}}}`),
+			},
+			exp: []string{"apiVersion cert-manager.io/v1alpha3 is deprecated, use cert-manager.io/v1beta1 instead"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := &metav1.ObjectMeta{ManagedFields: test.managedFields}
+			got := CertificateDeprecations(crt, "controller")
+			if !reflect.DeepEqual(got, test.exp) {
+				t.Errorf("expected %q, got %q", test.exp, got)
+			}
+		})
+	}
+}
+
+func TestIssuerDeprecations(t *testing.T) {
+	issuer := &metav1.ObjectMeta{ManagedFields: []metav1.ManagedFieldsEntry{{
+		Manager:    "kubectl",
+		APIVersion: "cert-manager.io/v1alpha2",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:ca":{"f:keySize":{}}}}`)},
+	}}}
+	exp := []string{"apiVersion cert-manager.io/v1alpha2 is deprecated, use cert-manager.io/v1beta1 instead"}
+	if got := IssuerDeprecations(issuer, "controller"); !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected %q, got %q", exp, got)
+	}
+}
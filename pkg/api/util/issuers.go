@@ -18,7 +18,9 @@ package util
 
 import (
 	"fmt"
+	"strings"
 
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 )
@@ -61,3 +63,80 @@ func IssuerKind(ref cmmeta.ObjectReference) string {
 	}
 	return ref.Kind
 }
+
+// DNSNamesNotAllowedByIssuer returns the names that are not permitted by the
+// AllowedDNSSuffixes of the given issuer spec. A name is permitted if it is
+// equal to, or a subdomain of, one of the allowed suffixes.
+// If the issuer does not restrict DNS names, nil is returned.
+func DNSNamesNotAllowedByIssuer(spec *cmapi.IssuerSpec, names []string) []string {
+	if len(spec.AllowedDNSSuffixes) == 0 {
+		return nil
+	}
+
+	var notAllowed []string
+	for _, name := range names {
+		allowed := false
+		for _, suffix := range spec.AllowedDNSSuffixes {
+			if dnsNameHasSuffix(name, suffix) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			notAllowed = append(notAllowed, name)
+		}
+	}
+
+	return notAllowed
+}
+
+// dnsNameHasSuffix returns true if name is equal to, or a subdomain of,
+// suffix. Wildcard names are matched using their parent domain.
+func dnsNameHasSuffix(name, suffix string) bool {
+	name = strings.ToLower(strings.TrimPrefix(name, "*."))
+	suffix = strings.ToLower(strings.TrimPrefix(suffix, "."))
+	if suffix == "" {
+		return false
+	}
+	return name == suffix || strings.HasSuffix(name, "."+suffix)
+}
+
+// ACMESolversReferenceSecret returns true if the credentials of the DNS01
+// provider of any of the given solvers are stored in the Secret with the
+// given name.
+func ACMESolversReferenceSecret(solvers []cmacme.ACMEChallengeSolver, name string) bool {
+	for _, slv := range solvers {
+		dns01 := slv.DNS01
+		if dns01 == nil {
+			continue
+		}
+		var refs []string
+		switch {
+		case dns01.Akamai != nil:
+			refs = []string{dns01.Akamai.ClientToken.Name, dns01.Akamai.ClientSecret.Name, dns01.Akamai.AccessToken.Name}
+		case dns01.CloudDNS != nil && dns01.CloudDNS.ServiceAccount != nil:
+			refs = []string{dns01.CloudDNS.ServiceAccount.Name}
+		case dns01.Cloudflare != nil:
+			if dns01.Cloudflare.APIKey != nil {
+				refs = append(refs, dns01.Cloudflare.APIKey.Name)
+			}
+			if dns01.Cloudflare.APIToken != nil {
+				refs = append(refs, dns01.Cloudflare.APIToken.Name)
+			}
+		case dns01.DigitalOcean != nil:
+			refs = []string{dns01.DigitalOcean.Token.Name}
+		case dns01.Route53 != nil:
+			refs = []string{dns01.Route53.SecretAccessKey.Name}
+		case dns01.AzureDNS != nil && dns01.AzureDNS.ClientSecret != nil:
+			refs = []string{dns01.AzureDNS.ClientSecret.Name}
+		case dns01.AcmeDNS != nil:
+			refs = []string{dns01.AcmeDNS.AccountSecret.Name}
+		}
+		for _, ref := range refs {
+			if ref != "" && ref == name {
+				return true
+			}
+		}
+	}
+	return false
+}
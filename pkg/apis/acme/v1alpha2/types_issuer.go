@@ -19,6 +19,7 @@ package v1alpha2
 import (
 	corev1 "k8s.io/api/core/v1"
 	apiext "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 )
@@ -127,6 +128,54 @@ type ACMEChallengeSolver struct {
 	// performing the DNS01 challenge flow.
 	// +optional
 	DNS01 *ACMEChallengeSolverDNS01 `json:"dns01,omitempty"`
+
+	// Hooks configures webhooks that are called before the challenges solved
+	// by this solver are presented and after they have been cleaned up, e.g.
+	// to open a firewall rule or to purge a CDN cache for the
+	// /.well-known/acme-challenge paths.
+	// +optional
+	Hooks *ACMEChallengeSolverHooks `json:"hooks,omitempty"`
+}
+
+// ACMEChallengeSolverHooks configures the webhooks that are called around
+// the presentation of challenges.
+// Hooks may be called more than once for the same challenge and event, so
+// they must be idempotent.
+type ACMEChallengeSolverHooks struct {
+	// PrePresent is called before a challenge is presented. The challenge is
+	// not presented until the hook has succeeded, and failed calls are
+	// retried with back-off.
+	// +optional
+	PrePresent *ACMEChallengeHook `json:"prePresent,omitempty"`
+
+	// PostCleanUp is called after a challenge has been cleaned up. Failures
+	// are recorded on the Challenge but do not prevent its clean up.
+	// +optional
+	PostCleanUp *ACMEChallengeHook `json:"postCleanUp,omitempty"`
+}
+
+// ACMEChallengeHook is a webhook that is called with a POST request whose
+// JSON body describes the challenge and the event, e.g. 'PrePresent'.
+// Any 2xx response is considered a success.
+type ACMEChallengeHook struct {
+	// URL of the webhook, which must use the http or https scheme.
+	URL string `json:"url"`
+
+	// CABundle is a PEM encoded bundle of CA certificates used to verify the
+	// certificate of the webhook. If not set, the system trust store is used.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// Timeout of each request to the webhook. Defaults to 10s, and must not
+	// exceed 1m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Retries is the number of times a request that fails with a network
+	// error, a 429 or a 5xx response is retried before the call is
+	// considered failed. Defaults to 2, and must not exceed 5.
+	// +optional
+	Retries *int `json:"retries,omitempty"`
 }
 
 // CertificateDomainSelector selects certificates using a label selector, and
@@ -215,8 +264,9 @@ type ACMEChallengeSolverHTTP01IngressPodTemplate struct {
 	ACMEChallengeSolverHTTP01IngressPodObjectMeta `json:"metadata"`
 
 	// PodSpec defines overrides for the HTTP01 challenge solver pod.
-	// Only the 'priorityClassName', 'nodeSelector', 'affinity',
-	// 'serviceAccountName' and 'tolerations' fields are supported currently.
+	// Only the 'priorityClassName', 'runtimeClassName', 'nodeSelector',
+	// 'affinity', 'serviceAccountName', 'tolerations' and
+	// 'topologySpreadConstraints' fields are supported currently.
 	// All other fields will be ignored.
 	// +optional
 	Spec ACMEChallengeSolverHTTP01IngressPodSpec `json:"spec"`
@@ -251,6 +301,15 @@ type ACMEChallengeSolverHTTP01IngressPodSpec struct {
 	// +optional
 	PriorityClassName string `json:"priorityClassName,omitempty"`
 
+	// If specified, the pod's runtimeClassName.
+	// +optional
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	// If specified, the pod's topology spread constraints. They are added to
+	// the constraints configured for all solver pods of the controller.
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
 	// If specified, the pod's service account
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
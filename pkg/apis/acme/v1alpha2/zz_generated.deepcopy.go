@@ -24,6 +24,7 @@ import (
 	metav1 "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	v1 "k8s.io/api/core/v1"
 	v1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apismetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -69,6 +70,37 @@ func (in *ACMEChallenge) DeepCopy() *ACMEChallenge {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ACMEChallengeHook) DeepCopyInto(out *ACMEChallengeHook) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(apismetav1.Duration)
+		**out = **in
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ACMEChallengeHook.
+func (in *ACMEChallengeHook) DeepCopy() *ACMEChallengeHook {
+	if in == nil {
+		return nil
+	}
+	out := new(ACMEChallengeHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ACMEChallengeSolver) DeepCopyInto(out *ACMEChallengeSolver) {
 	*out = *in
@@ -87,6 +119,11 @@ func (in *ACMEChallengeSolver) DeepCopyInto(out *ACMEChallengeSolver) {
 		*out = new(ACMEChallengeSolverDNS01)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(ACMEChallengeSolverHooks)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -295,6 +332,18 @@ func (in *ACMEChallengeSolverHTTP01IngressPodSpec) DeepCopyInto(out *ACMEChallen
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RuntimeClassName != nil {
+		in, out := &in.RuntimeClassName, &out.RuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -343,6 +392,32 @@ func (in *ACMEChallengeSolverHTTP01IngressTemplate) DeepCopy() *ACMEChallengeSol
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ACMEChallengeSolverHooks) DeepCopyInto(out *ACMEChallengeSolverHooks) {
+	*out = *in
+	if in.PrePresent != nil {
+		in, out := &in.PrePresent, &out.PrePresent
+		*out = new(ACMEChallengeHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostCleanUp != nil {
+		in, out := &in.PostCleanUp, &out.PostCleanUp
+		*out = new(ACMEChallengeHook)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ACMEChallengeSolverHooks.
+func (in *ACMEChallengeSolverHooks) DeepCopy() *ACMEChallengeSolverHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(ACMEChallengeSolverHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ACMEExternalAccountBinding) DeepCopyInto(out *ACMEExternalAccountBinding) {
 	*out = *in
@@ -620,7 +695,7 @@ func (in *Challenge) DeepCopyInto(out *Challenge) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -642,6 +717,26 @@ func (in *Challenge) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChallengeCondition) DeepCopyInto(out *ChallengeCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChallengeCondition.
+func (in *ChallengeCondition) DeepCopy() *ChallengeCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ChallengeCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChallengeList) DeepCopyInto(out *ChallengeList) {
 	*out = *in
@@ -696,6 +791,13 @@ func (in *ChallengeSpec) DeepCopy() *ChallengeSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChallengeStatus) DeepCopyInto(out *ChallengeStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ChallengeCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
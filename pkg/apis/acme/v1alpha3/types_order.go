@@ -96,6 +96,13 @@ type OrderStatus struct {
 	// +optional
 	Certificate []byte `json:"certificate,omitempty"`
 
+	// CertificateURL is the URL that the certificate of this Order can be
+	// downloaded from once it has been finalized. It is used to download the
+	// certificate again, e.g. if the stored copy is lost or invalid, instead
+	// of creating a new order.
+	// +optional
+	CertificateURL string `json:"certificateURL,omitempty"`
+
 	// State contains the current state of this Order resource.
 	// States 'success' and 'expired' are 'final'
 	// +optional
@@ -106,6 +113,11 @@ type OrderStatus struct {
 	// +optional
 	Reason string `json:"reason,omitempty"`
 
+	// FailureType categorises the ACME error that caused this Order to fail,
+	// if the failure was reported by the ACME server.
+	// +optional
+	FailureType ACMEErrorType `json:"failureType,omitempty"`
+
 	// FailureTime stores the time that this order failed.
 	// This is used to influence garbage collection and back-off.
 	// +optional
@@ -224,3 +236,38 @@ const (
 	// This is a final state.
 	Errored State = "errored"
 )
+
+// ACMEErrorType categorises the problem document returned by an ACME server
+// when a request fails.
+// Full details of the underlying error types can be found here: https://tools.ietf.org/html/rfc8555#section-6.7
+// Clients utilising this type must also gracefully handle unknown
+// values, as the contents of this enumeration may be added to over time.
+// +kubebuilder:validation:Enum=RateLimited;Unauthorized;CAA;DNS;Connection;Other
+type ACMEErrorType string
+
+const (
+	// ACMEErrorRateLimited signifies that the request exceeded a rate limit
+	// imposed by the ACME server (urn:ietf:params:acme:error:rateLimited).
+	ACMEErrorRateLimited ACMEErrorType = "RateLimited"
+
+	// ACMEErrorUnauthorized signifies that the client lacks sufficient
+	// authorization to perform the request, e.g. because a validation failed
+	// (urn:ietf:params:acme:error:unauthorized).
+	ACMEErrorUnauthorized ACMEErrorType = "Unauthorized"
+
+	// ACMEErrorCAA signifies that a CAA record forbids the ACME server from
+	// issuing a certificate for the identifier (urn:ietf:params:acme:error:caa).
+	ACMEErrorCAA ACMEErrorType = "CAA"
+
+	// ACMEErrorDNS signifies that the ACME server encountered a problem while
+	// performing a DNS lookup (urn:ietf:params:acme:error:dns).
+	ACMEErrorDNS ACMEErrorType = "DNS"
+
+	// ACMEErrorConnection signifies that the ACME server could not connect to
+	// the validation target (urn:ietf:params:acme:error:connection).
+	ACMEErrorConnection ACMEErrorType = "Connection"
+
+	// ACMEErrorOther is used for all ACME problem documents that do not map
+	// to one of the other error types.
+	ACMEErrorOther ACMEErrorType = "Other"
+)
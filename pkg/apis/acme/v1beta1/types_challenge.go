@@ -138,8 +138,56 @@ type ChallengeStatus struct {
 	// +optional
 	Reason string `json:"reason,omitempty"`
 
+	// FailureType categorises the ACME error that caused this Challenge to
+	// fail, if the failure was reported by the ACME server.
+	// +optional
+	FailureType ACMEErrorType `json:"failureType,omitempty"`
+
 	// Contains the current 'state' of the challenge.
 	// If not set, the state of the challenge is unknown.
 	// +optional
 	State State `json:"state,omitempty"`
+
+	// List of status conditions to indicate the status of the Challenge.
+	// Known condition types are `ProviderUnavailable`.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []ChallengeCondition `json:"conditions,omitempty"`
+}
+
+// ChallengeCondition contains condition information for a Challenge.
+type ChallengeCondition struct {
+	// Type of the condition, known values are (`ProviderUnavailable`).
+	Type ChallengeConditionType `json:"type"`
+
+	// Status of the condition, one of ('True', 'False', 'Unknown').
+	Status cmmeta.ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the timestamp corresponding to the last status
+	// change of this condition.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Reason is a brief machine readable explanation for the condition's last
+	// transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable description of the details of the last
+	// transition, complementing reason.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
+
+// ChallengeConditionType represents a Challenge condition value.
+type ChallengeConditionType string
+
+const (
+	// ChallengeConditionProviderUnavailable indicates that the DNS01 provider
+	// used to solve the Challenge is failing systematically, e.g. because it
+	// rejects the configured credentials or keeps returning server errors.
+	// Challenges with this condition set to True are not presented until a
+	// probe shows that the provider has recovered.
+	ChallengeConditionProviderUnavailable ChallengeConditionType = "ProviderUnavailable"
+)
@@ -56,6 +56,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ClusterIssuerList{},
 		&CertificateRequest{},
 		&CertificateRequestList{},
+		&SelfStatus{},
+		&SelfStatusList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -105,7 +105,9 @@ type CertificateRequestSpec struct {
 // resulting signed certificate.
 type CertificateRequestStatus struct {
 	// List of status conditions to indicate the status of a CertificateRequest.
-	// Known condition types are `Ready` and `InvalidRequest`.
+	// Known condition types are `Ready`, `InvalidRequest`, `Approved` and `Denied`.
+	// +listType=map
+	// +listMapKey=type
 	// +optional
 	Conditions []CertificateRequestCondition `json:"conditions,omitempty"`
 
@@ -132,7 +134,7 @@ type CertificateRequestStatus struct {
 
 // CertificateRequestCondition contains condition information for a CertificateRequest.
 type CertificateRequestCondition struct {
-	// Type of the condition, known values are ('Ready', 'InvalidRequest').
+	// Type of the condition, known values are ('Ready', 'InvalidRequest', 'Approved', 'Denied').
 	Type CertificateRequestConditionType `json:"type"`
 
 	// Status of the condition, one of ('True', 'False', 'Unknown').
@@ -168,4 +170,15 @@ const (
 	// parameters being invalid. Additional information about why the request
 	// was rejected can be found in the `reason` and `message` fields.
 	CertificateRequestConditionInvalidRequest CertificateRequestConditionType = "InvalidRequest"
+
+	// CertificateRequestConditionApproved indicates that a certificate
+	// request has been approved by an operator, e.g. with 'kubectl
+	// cert-manager approve'. If the CertificateRequestApproval feature gate
+	// is enabled, requests are only signed once they have been approved.
+	CertificateRequestConditionApproved CertificateRequestConditionType = "Approved"
+
+	// CertificateRequestConditionDenied indicates that a certificate request
+	// has been denied by an operator, e.g. with 'kubectl cert-manager deny'.
+	// Denied requests are never signed, and are marked as failed.
+	CertificateRequestConditionDenied CertificateRequestConditionType = "Denied"
 )
@@ -87,6 +87,103 @@ type IssuerList struct {
 // configuration required for the issuer.
 type IssuerSpec struct {
 	IssuerConfig `json:",inline"`
+
+	// AllowedDNSSuffixes restricts the DNS names this issuer will sign
+	// certificates for. If set, CertificateRequests containing a DNS name or
+	// common name that is not equal to, or a subdomain of, one of the listed
+	// suffixes are failed before the issuer is contacted.
+	// If not set, any DNS name is allowed.
+	// +optional
+	AllowedDNSSuffixes []string `json:"allowedDNSSuffixes,omitempty"`
+
+	// RequestDefaults are names that this issuer adds to the certificates it
+	// signs if they are not already requested, e.g. to give every workload
+	// a SPIFFE identity. They are only applied by issuers that build the
+	// certificate themselves, i.e. CA and SelfSigned issuers.
+	// +optional
+	RequestDefaults *RequestDefaults `json:"requestDefaults,omitempty"`
+
+	// KeyEscrow configures the delivery of the private keys of Certificates
+	// using this issuer to an escrow service. Keys are delivered
+	// asynchronously once they have been stored in the Secret of the
+	// Certificate, so a failed delivery does not block issuance but is
+	// retried with back-off and reported as an event on the Certificate.
+	// Keys are only delivered if the controller is run with
+	// --enable-key-escrow.
+	// +optional
+	KeyEscrow *KeyEscrow `json:"keyEscrow,omitempty"`
+}
+
+// RequestDefaults are the subject and subject alternative names added by an
+// issuer to the certificates it signs.
+// Every value is a Go template that may use the functions `namespace`,
+// `serviceAccount`, `clusterDomain` and `issuerName`, which return the
+// namespace of the CertificateRequest, the service account of the Pod it was
+// created for, the cluster domain configured on the controller and the name of
+// the issuer, e.g.
+// `spiffe://{{ clusterDomain }}/ns/{{ namespace }}/sa/{{ serviceAccount }}`.
+// The service account is read from the `cert-manager.io/pod-service-account`
+// annotation, and only if the controller is run with
+// --trust-pod-service-account-annotation, as the annotation is verified by the
+// webhook with --enforce-pod-identity.
+type RequestDefaults struct {
+	// CommonName is the common name of certificates that do not request one.
+	// +optional
+	CommonName string `json:"commonName,omitempty"`
+
+	// DNSNames are DNS subject alternative names added to every certificate.
+	// +optional
+	DNSNames []string `json:"dnsNames,omitempty"`
+
+	// URISANs are URI subject alternative names added to every certificate.
+	// +optional
+	URISANs []string `json:"uriSANs,omitempty"`
+}
+
+// KeyEscrow configures the escrow service that private keys are delivered
+// to. Exactly one of HTTPS or KMIP must be set. The escrow service must
+// authenticate cert-manager with the client certificate referenced by
+// ClientCertSecretRef.
+type KeyEscrow struct {
+	// HTTPS delivers keys to an HTTPS endpoint.
+	// +optional
+	HTTPS *HTTPSKeyEscrow `json:"https,omitempty"`
+
+	// KMIP registers keys as private key objects with a KMIP server.
+	// +optional
+	KMIP *KMIPKeyEscrow `json:"kmip,omitempty"`
+
+	// CABundle is a PEM encoded bundle of CA certificates used to verify the
+	// certificate of the escrow service. If not set, the system trust store
+	// is used.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// ClientCertSecretRef references a Secret holding the client certificate
+	// and private key in its `tls.crt` and `tls.key` entries, which are used
+	// to authenticate to the escrow service. The Secret must be in the
+	// namespace of the Issuer, or in the cluster resource namespace for
+	// ClusterIssuers.
+	ClientCertSecretRef cmmeta.LocalObjectReference `json:"clientCertSecretRef"`
+}
+
+// HTTPSKeyEscrow delivers private keys with a POST request whose JSON body
+// holds the PKCS#8 PEM encoded key, the SHA-256 fingerprint of its public
+// key and the namespace and name of the Certificate and Secret it belongs
+// to. Any 2xx response is considered a success.
+type HTTPSKeyEscrow struct {
+	// URL of the endpoint, which must use the https scheme.
+	URL string `json:"url"`
+}
+
+// KMIPKeyEscrow registers private keys with a KMIP server using the
+// Register operation of KMIP 1.2. Keys are registered in PKCS#8 format and
+// named after the namespace and name of their Certificate and the SHA-256
+// fingerprint of their public key. Only RSA and ECDSA keys are supported.
+type KMIPKeyEscrow struct {
+	// Server is the host and port of the KMIP server, e.g.
+	// `kmip.example.com:5696`.
+	Server string `json:"server"`
 }
 
 type IssuerConfig struct {
@@ -203,8 +300,46 @@ type VaultIssuer struct {
 	// are used to validate the TLS connection.
 	// +optional
 	CABundle []byte `json:"caBundle,omitempty"`
+
+	// SigningMode selects the endpoint of the Vault PKI backend used to sign
+	// certificates. If set to `Role`, the `sign` endpoint of the role in
+	// `path` is used, so that the role's constraints and defaults apply. If
+	// set to `Verbatim`, the `sign-verbatim` endpoint is used, which signs
+	// the CSR with its subject and subject alternative names as is.
+	// `path` must have the form `<mount>/sign/<role>` or
+	// `<mount>/sign-verbatim[/<role>]` if this field is set.
+	// If not set, `path` is used as given.
+	// +optional
+	SigningMode VaultSigningMode `json:"signingMode,omitempty"`
+
+	// IssuerRef selects the issuer of a Vault PKI mount with multiple
+	// issuers that certificates are signed by, by name or ID. If not set,
+	// the default issuer of the mount is used.
+	// +optional
+	IssuerRef string `json:"issuerRef,omitempty"`
+
+	// ExcludeCNFromSANs controls whether Vault excludes the common name of
+	// the CSR from the DNS and email subject alternative names of signed
+	// certificates. Defaults to true.
+	// +optional
+	ExcludeCNFromSANs *bool `json:"excludeCNFromSANs,omitempty"`
 }
 
+// VaultSigningMode denotes the endpoint of the Vault PKI backend used to sign
+// certificates.
+// +kubebuilder:validation:Enum=Role;Verbatim
+type VaultSigningMode string
+
+const (
+	// VaultSigningModeRole signs certificates using the `sign` endpoint of a
+	// Vault PKI role.
+	VaultSigningModeRole VaultSigningMode = "Role"
+
+	// VaultSigningModeVerbatim signs certificates using the `sign-verbatim`
+	// endpoint of a Vault PKI mount.
+	VaultSigningModeVerbatim VaultSigningMode = "Verbatim"
+)
+
 // Configuration used to authenticate with a Vault server.
 // Only one of `tokenSecretRef`, `appRole` or `kubernetes` may be specified.
 type VaultAuth struct {
@@ -277,6 +412,8 @@ type CAIssuer struct {
 type IssuerStatus struct {
 	// List of status conditions to indicate the status of a CertificateRequest.
 	// Known condition types are `Ready`.
+	// +listType=map
+	// +listMapKey=type
 	// +optional
 	Conditions []IssuerCondition `json:"conditions,omitempty"`
 
@@ -320,4 +457,10 @@ const (
 	// If the `status` of this condition is `False`, CertificateRequest controllers
 	// should prevent attempts to sign certificates.
 	IssuerConditionReady IssuerConditionType = "Ready"
+
+	// IssuerConditionDeprecated is set to True when the spec of the issuer
+	// was written using a deprecated apiVersion. The message of the condition
+	// explains how to migrate.
+	// It is removed once the issuer no longer uses deprecated APIs.
+	IssuerConditionDeprecated IssuerConditionType = "Deprecated"
 )
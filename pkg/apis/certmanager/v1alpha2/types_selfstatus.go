@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// A SelfStatus reports the state of the certificates used by the components
+// of cert-manager itself, such as the serving certificate of the webhook.
+// It is maintained by the controller, in a single resource named after the
+// installation.
+type SelfStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Status of the certificates of the components. This is set and managed
+	// automatically.
+	Status SelfStatusStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SelfStatusList is a list of SelfStatuses
+type SelfStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []SelfStatus `json:"items"`
+}
+
+// SelfStatusStatus defines the observed state of the certificates of the
+// cert-manager components.
+type SelfStatusStatus struct {
+	// Components holds the state of the certificate of each component that
+	// is checked by the controller, e.g. 'webhook'.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Components []ComponentCertificateStatus `json:"components,omitempty"`
+}
+
+// ComponentCertificateStatus is the state of the certificate used by a
+// cert-manager component.
+type ComponentCertificateStatus struct {
+	// Name of the component, one of ('webhook-ca', 'webhook', 'status-api').
+	Name string `json:"name"`
+
+	// The time at which the certificate of the component becomes valid.
+	// +optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+
+	// The expiration time of the certificate of the component.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// List of status conditions to indicate the state of the certificate of
+	// the component.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []SelfStatusCondition `json:"conditions,omitempty"`
+}
+
+// SelfStatusCondition contains condition information for the certificate of
+// a cert-manager component.
+type SelfStatusCondition struct {
+	// Type of the condition, known values are ('Ready', 'RotationOverdue').
+	Type SelfStatusConditionType `json:"type"`
+
+	// Status of the condition, one of ('True', 'False', 'Unknown').
+	Status cmmeta.ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the timestamp corresponding to the last status
+	// change of this condition.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Reason is a brief machine readable explanation for the condition's last
+	// transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable description of the details of the last
+	// transition, complementing reason.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// SelfStatusConditionType represents a SelfStatus condition value.
+type SelfStatusConditionType string
+
+const (
+	// SelfStatusConditionReady indicates that the certificate of the
+	// component could be loaded and is currently valid.
+	SelfStatusConditionReady SelfStatusConditionType = "Ready"
+
+	// SelfStatusConditionRotationOverdue indicates that the certificate of
+	// the component should have been rotated already, i.e. less than a
+	// quarter of its lifetime remains. Components rotate their certificates
+	// once a third of their lifetime remains.
+	SelfStatusConditionRotationOverdue SelfStatusConditionType = "RotationOverdue"
+)
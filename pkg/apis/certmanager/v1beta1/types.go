@@ -45,6 +45,19 @@ const (
 	// Annotation key used to denote whether a Secret is named on a Certificate
 	// as a 'next private key' Secret resource.
 	IsNextPrivateKeySecretLabelKey = "cert-manager.io/next-private-key"
+
+	// Label key for the team that a Certificate or CertificateRequest is
+	// attributed to in usage metrics and reports.
+	TeamLabelKey = "cert-manager.io/team"
+
+	// Annotation key for the signed attestation binding the certificate
+	// stored in a Secret to the Certificate it was issued for.
+	AttestationAnnotationKey = "cert-manager.io/attestation"
+
+	// Annotation key for the SHA-256 fingerprint of the public key of the
+	// private key stored in a Secret, set once the private key has been
+	// delivered to the escrow service configured on the issuer.
+	EscrowedKeyFingerprintAnnotationKey = "cert-manager.io/escrowed-key-fingerprint"
 )
 
 // Deprecated annotation names for Secrets
@@ -83,6 +96,46 @@ const (
 
 	// Annotation to declare the CertificateRequest "revision", belonging to a Certificate Resource
 	CertificateRequestRevisionAnnotationKey = "cert-manager.io/certificate-revision"
+
+	// Label added to CertificateRequest resources to denote the "revision" of
+	// the Certificate resource it belongs to, so that it can be selected for
+	// without listing all CertificateRequest resources in the namespace.
+	// The Certificate resource name is set using the CertificateNameKey label.
+	CertificateRequestRevisionLabelKey = "cert-manager.io/certificate-revision"
+
+	// Annotation that can be added to Issuer and ClusterIssuer resources to
+	// limit the number of CertificateRequests that are being signed by the
+	// issuer at once, e.g. to honour the rate limits of the upstream CA.
+	// Further CertificateRequests are kept pending until a slot is released.
+	IssuerMaxConcurrentRequestsAnnotationKey = "cert-manager.io/max-concurrent-requests"
+
+	// Annotation added to CertificateRequest resources created for
+	// Certificates with `externalSigning` set. If it is set to "true", the
+	// CertificateRequest is not processed by any issuer and the signed
+	// certificate has to be imported into its status instead.
+	CertificateRequestExternalSigningAnnotationKey = "cert-manager.io/external-signing"
+
+	// Annotation that trusted agents, such as CSI drivers, set on the
+	// CertificateRequests they create on behalf of a Pod to the name of that
+	// Pod, so that the request can be bound to the identity of the Pod.
+	CertificateRequestPodNameAnnotationKey = "cert-manager.io/pod-name"
+
+	// Annotation that Pods, and trusted agents creating CertificateRequests
+	// on behalf of a Pod, set to the name of the service account of the Pod.
+	// It is verified against the identity of the Pod at admission by the
+	// webhook when pod identity is enforced, cannot be set by any other user
+	// and cannot be changed once the CertificateRequest has been created.
+	CertificateRequestPodServiceAccountAnnotationKey = "cert-manager.io/pod-service-account"
+
+	// Annotations added by cert-manager to the CertificateRequest resources it
+	// creates for Certificates, recording the version of the controller, the
+	// type of the issuer, e.g. 'acme' or 'ca', and the state of the feature
+	// gates of the controller, e.g. 'ServerSideApply=false,ValidateCAA=true',
+	// so that the behaviour of an issuance can be correlated with upgrades.
+	// The issuer type is only set for issuers of the cert-manager.io group.
+	CertificateRequestControllerVersionAnnotationKey = "cert-manager.io/controller-version"
+	CertificateRequestIssuerTypeAnnotationKey        = "cert-manager.io/issuer-type"
+	CertificateRequestFeatureGatesAnnotationKey      = "cert-manager.io/feature-gates"
 )
 
 const (
@@ -92,6 +145,105 @@ const (
 	// stored in the target Secret resource whilst the real Issuer is processing
 	// the certificate request.
 	IssueTemporaryCertificateAnnotation = "cert-manager.io/issue-temporary-certificate"
+
+	// PausedAnnotationKey is an annotation that can be added to Certificate,
+	// CertificateRequest, Order and Challenge resources.
+	// If it is set to "true", cert-manager will stop reconciling the resource
+	// until the annotation is removed or set to any other value.
+	// Pausing a Certificate stops all of the certificates controllers acting
+	// on it, including the revocation of its certificates, and the sweeping
+	// of its next private key Secrets. It does not pause the
+	// CertificateRequests of the Certificate. Pausing an Order also pauses
+	// its Challenges.
+	PausedAnnotationKey = "cert-manager.io/paused"
+
+	// RotatePrivateKeyAnnotationKey is an annotation that can be added to
+	// Certificate resources.
+	// If it is set to the revision of the next issuance of the Certificate, a
+	// new private key is generated for that issuance, regardless of the
+	// rotation policy of the Certificate.
+	RotatePrivateKeyAnnotationKey = "cert-manager.io/rotate-private-key"
+
+	// EventVerbosityAnnotationKey is an annotation that can be added to
+	// Certificate resources to reduce the number of events cert-manager
+	// records for the Certificate and the CertificateRequests, Orders and
+	// Challenges created for it.
+	// If it is set to "quiet", only Warning events are recorded. If it is set
+	// to "none", no events are recorded at all.
+	EventVerbosityAnnotationKey = "cert-manager.io/event-verbosity"
+
+	// SecretConsumerPatchesAnnotationKey is an annotation that can be added
+	// to Certificate resources to patch the resources consuming its Secret,
+	// such as a Deployment that needs to be rolled, after a new certificate
+	// has been stored in the Secret.
+	// The value is a JSON encoded list of objects with a target resource in
+	// the namespace of the Certificate and a JSON patch (RFC 6902) to apply
+	// to it, for example: `[{"target": {"apiVersion": "apps/v1", "kind":
+	// "Deployment", "name": "web"}, "patch": [{"op": "add", "path":
+	// "/spec/template/metadata/annotations/example.com~1tls-version",
+	// "value": "{{ .SecretResourceVersion }}"}]}]`
+	// String values of the patch are Go templates that can use the fields
+	// .CertificateName, .SecretName, .SecretResourceVersion, .Revision,
+	// .SerialNumber and .NotAfter of the stored certificate.
+	// Patches are only applied if the controller is run with
+	// --enable-secret-consumer-patches, and only to Deployments, StatefulSets
+	// and DaemonSets of the apps/v1 API.
+	SecretConsumerPatchesAnnotationKey = "cert-manager.io/secret-consumer-patches"
+
+	// SecretRetentionFinalizer is added to Certificates if the controller
+	// is run with --enable-certificate-owner-ref. When the Certificate is
+	// deleted, the owner reference is removed from its Secret, which is then
+	// annotated with SecretOrphanedAtAnnotationKey rather than garbage
+	// collected right away.
+	SecretRetentionFinalizer = "cert-manager.io/secret-retention"
+
+	// SecretOrphanedAtAnnotationKey is the time at which a Secret was
+	// orphaned by the deletion of its Certificate. A Certificate with the
+	// same name that is created within an hour re-uses the certificate stored
+	// in the Secret if it is still up to date, otherwise the Secret is
+	// deleted.
+	SecretOrphanedAtAnnotationKey = "cert-manager.io/orphaned-at"
+)
+
+// Values of the EventVerbosityAnnotationKey annotation
+const (
+	// EventVerbosityNormal records all events. This is the default.
+	EventVerbosityNormal = "normal"
+	// EventVerbosityQuiet only records Warning events.
+	EventVerbosityQuiet = "quiet"
+	// EventVerbosityNone records no events.
+	EventVerbosityNone = "none"
+)
+
+// Label and annotation names for revocation requests
+const (
+	// RevocationRequestLabelKey is a label that is added to Secret resources
+	// holding a certificate in their 'tls.crt' entry that is to be revoked.
+	// The certificate is only revoked if it was issued for the Certificate
+	// named in the CertificateNameKey annotation, in the same namespace, and
+	// it is revoked by the issuer of that Certificate.
+	RevocationRequestLabelKey = "cert-manager.io/revocation-request"
+
+	// RevocationReasonAnnotationKey is the reason the certificate of a
+	// revocation request is revoked for, one of the CRL reason codes of
+	// RFC 5280 such as 'keyCompromise' or 'superseded'.
+	RevocationReasonAnnotationKey = "cert-manager.io/revocation-reason"
+
+	// RevokeAfterRevisionAnnotationKey can be added to revocation requests to
+	// defer the revocation until the Certificate named in the
+	// CertificateNameKey annotation has been issued with this revision, so
+	// that the certificate is only revoked once it has been replaced.
+	RevokeAfterRevisionAnnotationKey = "cert-manager.io/revoke-after-revision"
+
+	// RevocationStatusAnnotationKey is set on revocation requests once they
+	// have been processed.
+	RevocationStatusAnnotationKey = "cert-manager.io/revocation-status"
+
+	// RevocationStatusRevoked denotes that the certificate has been revoked.
+	RevocationStatusRevoked = "Revoked"
+	// RevocationStatusNotSupported denotes that the certificate was not
+	// revoked as its issuer does not support revocation.
+	RevocationStatusNotSupported = "NotSupported"
 )
 
 // Common/known resource kinds.
@@ -100,6 +252,7 @@ const (
 	IssuerKind             = "Issuer"
 	CertificateKind        = "Certificate"
 	CertificateRequestKind = "CertificateRequest"
+	SelfStatusKind         = "SelfStatus"
 )
 
 const (
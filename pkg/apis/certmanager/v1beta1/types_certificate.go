@@ -95,6 +95,18 @@ type CertificateSpec struct {
 	// +optional
 	CommonName string `json:"commonName,omitempty"`
 
+	// SubjectAltNamesPolicy controls how the commonName of the Certificate is
+	// derived from its spec. If set to `UseCommonName`, `commonName` must be
+	// set and is used literally. If set to `OmitCommonName`, no common name is
+	// requested and the Certificate is identified by its subjectAltNames only.
+	// If set to `CopyFirstDNSName`, the first entry of `dnsNames` is used as
+	// the common name.
+	// If not set, `commonName` is used as given, if at all.
+	// Not every issuer supports every policy; the Certificate will not be
+	// issued if its issuer cannot honour the policy.
+	// +optional
+	SubjectAltNamesPolicy SubjectAltNamesPolicy `json:"subjectAltNamesPolicy,omitempty"`
+
 	// The requested 'duration' (i.e. lifetime) of the Certificate.
 	// This option may be ignored/overridden by some issuer types.
 	// If overridden and `renewBefore` is greater than the actual certificate
@@ -138,6 +150,16 @@ type CertificateSpec struct {
 	// +optional
 	Keystores *CertificateKeystores `json:"keystores,omitempty"`
 
+	// SecretKeysPerName denotes that the certificate and private key are
+	// additionally stored in the `secretName` Secret resource under keys
+	// named after each of the DNS names and IP addresses of the certificate,
+	// e.g. `example.com.crt` and `example.com.key`, for servers that select
+	// the certificate served for a name by its file name.
+	// The `*` of wildcard DNS names is replaced with `_` and the `:` of IPv6
+	// addresses with `-`, as they are not valid in Secret keys.
+	// +optional
+	SecretKeysPerName bool `json:"secretKeysPerName,omitempty"`
+
 	// IssuerRef is a reference to the issuer for this certificate.
 	// If the 'kind' field is not set, or set to 'Issuer', an Issuer resource
 	// with the given name in the same namespace as the Certificate will be used.
@@ -151,6 +173,17 @@ type CertificateSpec struct {
 	// +optional
 	IsCA bool `json:"isCA,omitempty"`
 
+	// ExternalSigning denotes that the certificate is signed outside of
+	// cert-manager, e.g. by an offline root CA. The private key is generated
+	// by cert-manager as usual, but the CertificateRequest created for each
+	// issuance is not processed by any issuer. Instead its CSR has to be
+	// exported, signed externally and the signed certificate imported back
+	// into the CertificateRequest, e.g. using the `external-signing`
+	// commands of the kubectl plugin.
+	// Only supported if `isCA` is true.
+	// +optional
+	ExternalSigning bool `json:"externalSigning,omitempty"`
+
 	// Usages is the set of x509 usages that are requested for the certificate.
 	// Defaults to `digital signature` and `key encipherment` if not specified.
 	// +optional
@@ -161,6 +194,24 @@ type CertificateSpec struct {
 	PrivateKey *CertificatePrivateKey `json:"privateKey,omitempty"`
 }
 
+// SubjectAltNamesPolicy denotes how the common name of a Certificate is
+// derived from its spec.
+type SubjectAltNamesPolicy string
+
+const (
+	// UseCommonNamePolicy requires `commonName` to be set and uses it as the
+	// common name of the Certificate, as given.
+	UseCommonNamePolicy SubjectAltNamesPolicy = "UseCommonName"
+
+	// OmitCommonNamePolicy means no common name will be requested. The
+	// Certificate is identified by its subjectAltNames only.
+	OmitCommonNamePolicy SubjectAltNamesPolicy = "OmitCommonName"
+
+	// CopyFirstDNSNamePolicy means the first entry of `dnsNames` will be used
+	// as the common name of the Certificate.
+	CopyFirstDNSNamePolicy SubjectAltNamesPolicy = "CopyFirstDNSName"
+)
+
 // CertificatePrivateKey contains configuration options for private keys
 // used by the Certificate controller.
 // This allows control of how private keys are rotated.
@@ -299,6 +350,8 @@ type PKCS12Keystore struct {
 type CertificateStatus struct {
 	// List of status conditions to indicate the status of certificates.
 	// Known condition types are `Ready` and `Issuing`.
+	// +listType=map
+	// +listMapKey=type
 	// +optional
 	Conditions []CertificateCondition `json:"conditions,omitempty"`
 
@@ -406,4 +459,17 @@ const (
 	//
 	// It will be removed by the 'issuing' controller upon completing issuance.
 	CertificateConditionIssuing CertificateConditionType = "Issuing"
+
+	// CertificateConditionDeprecated is set to True when the spec of the
+	// Certificate was written using a deprecated apiVersion or deprecated
+	// fields. The message of the condition explains how to migrate.
+	// It is removed once the Certificate no longer uses deprecated APIs.
+	CertificateConditionDeprecated CertificateConditionType = "Deprecated"
+
+	// CertificateConditionStaleConsumers is set to True by the optional
+	// 'CertificateStaleConsumers' controller when running Pods mounting the
+	// Secret of the Certificate were started before the certificate in the
+	// Secret was issued, and so may still be serving an older certificate.
+	// It is set to False once all such Pods have been restarted.
+	CertificateConditionStaleConsumers CertificateConditionType = "StaleConsumers"
 )
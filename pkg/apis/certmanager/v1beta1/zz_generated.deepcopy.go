@@ -498,6 +498,53 @@ func (in *ClusterIssuerList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentCertificateStatus) DeepCopyInto(out *ComponentCertificateStatus) {
+	*out = *in
+	if in.NotBefore != nil {
+		in, out := &in.NotBefore, &out.NotBefore
+		*out = (*in).DeepCopy()
+	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]SelfStatusCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentCertificateStatus.
+func (in *ComponentCertificateStatus) DeepCopy() *ComponentCertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentCertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPSKeyEscrow) DeepCopyInto(out *HTTPSKeyEscrow) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSKeyEscrow.
+func (in *HTTPSKeyEscrow) DeepCopy() *HTTPSKeyEscrow {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPSKeyEscrow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Issuer) DeepCopyInto(out *Issuer) {
 	*out = *in
@@ -624,6 +671,21 @@ func (in *IssuerList) DeepCopyObject() runtime.Object {
 func (in *IssuerSpec) DeepCopyInto(out *IssuerSpec) {
 	*out = *in
 	in.IssuerConfig.DeepCopyInto(&out.IssuerConfig)
+	if in.AllowedDNSSuffixes != nil {
+		in, out := &in.AllowedDNSSuffixes, &out.AllowedDNSSuffixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequestDefaults != nil {
+		in, out := &in.RequestDefaults, &out.RequestDefaults
+		*out = new(RequestDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KeyEscrow != nil {
+		in, out := &in.KeyEscrow, &out.KeyEscrow
+		*out = new(KeyEscrow)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -682,6 +744,54 @@ func (in *JKSKeystore) DeepCopy() *JKSKeystore {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KMIPKeyEscrow) DeepCopyInto(out *KMIPKeyEscrow) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KMIPKeyEscrow.
+func (in *KMIPKeyEscrow) DeepCopy() *KMIPKeyEscrow {
+	if in == nil {
+		return nil
+	}
+	out := new(KMIPKeyEscrow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyEscrow) DeepCopyInto(out *KeyEscrow) {
+	*out = *in
+	if in.HTTPS != nil {
+		in, out := &in.HTTPS, &out.HTTPS
+		*out = new(HTTPSKeyEscrow)
+		**out = **in
+	}
+	if in.KMIP != nil {
+		in, out := &in.KMIP, &out.KMIP
+		*out = new(KMIPKeyEscrow)
+		**out = **in
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	out.ClientCertSecretRef = in.ClientCertSecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyEscrow.
+func (in *KeyEscrow) DeepCopy() *KeyEscrow {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyEscrow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PKCS12Keystore) DeepCopyInto(out *PKCS12Keystore) {
 	*out = *in
@@ -699,6 +809,32 @@ func (in *PKCS12Keystore) DeepCopy() *PKCS12Keystore {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequestDefaults) DeepCopyInto(out *RequestDefaults) {
+	*out = *in
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.URISANs != nil {
+		in, out := &in.URISANs, &out.URISANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestDefaults.
+func (in *RequestDefaults) DeepCopy() *RequestDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(RequestDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SelfSignedIssuer) DeepCopyInto(out *SelfSignedIssuer) {
 	*out = *in
@@ -720,6 +856,109 @@ func (in *SelfSignedIssuer) DeepCopy() *SelfSignedIssuer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfStatus) DeepCopyInto(out *SelfStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfStatus.
+func (in *SelfStatus) DeepCopy() *SelfStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SelfStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfStatusCondition) DeepCopyInto(out *SelfStatusCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfStatusCondition.
+func (in *SelfStatusCondition) DeepCopy() *SelfStatusCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfStatusCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfStatusList) DeepCopyInto(out *SelfStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SelfStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfStatusList.
+func (in *SelfStatusList) DeepCopy() *SelfStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SelfStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfStatusStatus) DeepCopyInto(out *SelfStatusStatus) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentCertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfStatusStatus.
+func (in *SelfStatusStatus) DeepCopy() *SelfStatusStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfStatusStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultAppRole) DeepCopyInto(out *VaultAppRole) {
 	*out = *in
@@ -777,6 +1016,11 @@ func (in *VaultIssuer) DeepCopyInto(out *VaultIssuer) {
 		*out = make([]byte, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExcludeCNFromSANs != nil {
+		in, out := &in.ExcludeCNFromSANs, &out.ExcludeCNFromSANs
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -30,6 +30,7 @@ type CertmanagerV1alpha2Interface interface {
 	CertificateRequestsGetter
 	ClusterIssuersGetter
 	IssuersGetter
+	SelfStatusesGetter
 }
 
 // CertmanagerV1alpha2Client is used to interact with features provided by the cert-manager.io group.
@@ -53,6 +54,10 @@ func (c *CertmanagerV1alpha2Client) Issuers(namespace string) IssuerInterface {
 	return newIssuers(c, namespace)
 }
 
+func (c *CertmanagerV1alpha2Client) SelfStatuses() SelfStatusInterface {
+	return newSelfStatuses(c)
+}
+
 // NewForConfig creates a new CertmanagerV1alpha2Client for the given config.
 func NewForConfig(c *rest.Config) (*CertmanagerV1alpha2Client, error) {
 	config := *c
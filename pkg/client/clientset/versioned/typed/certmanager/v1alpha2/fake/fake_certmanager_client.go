@@ -44,6 +44,10 @@ func (c *FakeCertmanagerV1alpha2) Issuers(namespace string) v1alpha2.IssuerInter
 	return &FakeIssuers{c, namespace}
 }
 
+func (c *FakeCertmanagerV1alpha2) SelfStatuses() v1alpha2.SelfStatusInterface {
+	return &FakeSelfStatuses{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeCertmanagerV1alpha2) RESTClient() rest.Interface {
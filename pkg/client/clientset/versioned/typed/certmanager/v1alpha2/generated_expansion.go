@@ -25,3 +25,5 @@ type CertificateRequestExpansion interface{}
 type ClusterIssuerExpansion interface{}
 
 type IssuerExpansion interface{}
+
+type SelfStatusExpansion interface{}
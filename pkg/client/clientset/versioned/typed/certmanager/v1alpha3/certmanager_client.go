@@ -30,6 +30,7 @@ type CertmanagerV1alpha3Interface interface {
 	CertificateRequestsGetter
 	ClusterIssuersGetter
 	IssuersGetter
+	SelfStatusesGetter
 }
 
 // CertmanagerV1alpha3Client is used to interact with features provided by the cert-manager.io group.
@@ -53,6 +54,10 @@ func (c *CertmanagerV1alpha3Client) Issuers(namespace string) IssuerInterface {
 	return newIssuers(c, namespace)
 }
 
+func (c *CertmanagerV1alpha3Client) SelfStatuses() SelfStatusInterface {
+	return newSelfStatuses(c)
+}
+
 // NewForConfig creates a new CertmanagerV1alpha3Client for the given config.
 func NewForConfig(c *rest.Config) (*CertmanagerV1alpha3Client, error) {
 	config := *c
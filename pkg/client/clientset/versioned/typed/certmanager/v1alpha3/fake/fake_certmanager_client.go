@@ -44,6 +44,10 @@ func (c *FakeCertmanagerV1alpha3) Issuers(namespace string) v1alpha3.IssuerInter
 	return &FakeIssuers{c, namespace}
 }
 
+func (c *FakeCertmanagerV1alpha3) SelfStatuses() v1alpha3.SelfStatusInterface {
+	return &FakeSelfStatuses{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeCertmanagerV1alpha3) RESTClient() rest.Interface {
@@ -30,6 +30,7 @@ type CertmanagerV1beta1Interface interface {
 	CertificateRequestsGetter
 	ClusterIssuersGetter
 	IssuersGetter
+	SelfStatusesGetter
 }
 
 // CertmanagerV1beta1Client is used to interact with features provided by the cert-manager.io group.
@@ -53,6 +54,10 @@ func (c *CertmanagerV1beta1Client) Issuers(namespace string) IssuerInterface {
 	return newIssuers(c, namespace)
 }
 
+func (c *CertmanagerV1beta1Client) SelfStatuses() SelfStatusInterface {
+	return newSelfStatuses(c)
+}
+
 // NewForConfig creates a new CertmanagerV1beta1Client for the given config.
 func NewForConfig(c *rest.Config) (*CertmanagerV1beta1Client, error) {
 	config := *c
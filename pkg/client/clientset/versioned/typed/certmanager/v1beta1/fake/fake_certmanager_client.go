@@ -44,6 +44,10 @@ func (c *FakeCertmanagerV1beta1) Issuers(namespace string) v1beta1.IssuerInterfa
 	return &FakeIssuers{c, namespace}
 }
 
+func (c *FakeCertmanagerV1beta1) SelfStatuses() v1beta1.SelfStatusInterface {
+	return &FakeSelfStatuses{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeCertmanagerV1beta1) RESTClient() rest.Interface {
@@ -32,6 +32,8 @@ type Interface interface {
 	ClusterIssuers() ClusterIssuerInformer
 	// Issuers returns a IssuerInformer.
 	Issuers() IssuerInformer
+	// SelfStatuses returns a SelfStatusInformer.
+	SelfStatuses() SelfStatusInformer
 }
 
 type version struct {
@@ -64,3 +66,8 @@ func (v *version) ClusterIssuers() ClusterIssuerInformer {
 func (v *version) Issuers() IssuerInformer {
 	return &issuerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// SelfStatuses returns a SelfStatusInformer.
+func (v *version) SelfStatuses() SelfStatusInformer {
+	return &selfStatusInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
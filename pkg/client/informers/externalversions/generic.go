@@ -84,6 +84,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Certmanager().V1alpha2().ClusterIssuers().Informer()}, nil
 	case certmanagerv1alpha2.SchemeGroupVersion.WithResource("issuers"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Certmanager().V1alpha2().Issuers().Informer()}, nil
+	case certmanagerv1alpha2.SchemeGroupVersion.WithResource("selfstatuses"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Certmanager().V1alpha2().SelfStatuses().Informer()}, nil
 
 		// Group=cert-manager.io, Version=v1alpha3
 	case certmanagerv1alpha3.SchemeGroupVersion.WithResource("certificates"):
@@ -94,6 +96,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Certmanager().V1alpha3().ClusterIssuers().Informer()}, nil
 	case certmanagerv1alpha3.SchemeGroupVersion.WithResource("issuers"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Certmanager().V1alpha3().Issuers().Informer()}, nil
+	case certmanagerv1alpha3.SchemeGroupVersion.WithResource("selfstatuses"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Certmanager().V1alpha3().SelfStatuses().Informer()}, nil
 
 		// Group=cert-manager.io, Version=v1beta1
 	case certmanagerv1beta1.SchemeGroupVersion.WithResource("certificates"):
@@ -104,6 +108,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Certmanager().V1beta1().ClusterIssuers().Informer()}, nil
 	case certmanagerv1beta1.SchemeGroupVersion.WithResource("issuers"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Certmanager().V1beta1().Issuers().Informer()}, nil
+	case certmanagerv1beta1.SchemeGroupVersion.WithResource("selfstatuses"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Certmanager().V1beta1().SelfStatuses().Informer()}, nil
 
 	}
 
@@ -45,3 +45,7 @@ type IssuerListerExpansion interface{}
 // IssuerNamespaceListerExpansion allows custom methods to be added to
 // IssuerNamespaceLister.
 type IssuerNamespaceListerExpansion interface{}
+
+// SelfStatusListerExpansion allows custom methods to be added to
+// SelfStatusLister.
+type SelfStatusListerExpansion interface{}
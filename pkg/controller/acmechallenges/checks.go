@@ -16,4 +16,64 @@ limitations under the License.
 
 package acmechallenges
 
-// no checks for the acme orders controller yet
+import (
+	"fmt"
+
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+)
+
+var orderGvk = cmacme.SchemeGroupVersion.WithKind("Order")
+
+// handleOrder queues the Challenges owned by an Order, so that they are
+// processed again when the Order is paused or resumed.
+func (c *controller) handleOrder(obj interface{}) {
+	order, ok := obj.(*cmacme.Order)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("Object is not an Order %#v", obj))
+		return
+	}
+
+	challenges, err := c.challengeLister.Challenges(order.Namespace).List(labels.Everything())
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("Error listing Challenges of Order %s/%s: %v", order.Namespace, order.Name, err))
+		return
+	}
+	for _, ch := range challenges {
+		if !metav1.IsControlledBy(ch, order) {
+			continue
+		}
+		key, err := cache.MetaNamespaceKeyFunc(ch)
+		if err != nil {
+			runtime.HandleError(err)
+			continue
+		}
+		c.queue.Add(key)
+	}
+}
+
+// isPaused returns true if the Challenge, or the Order that owns it, has
+// been paused with the cert-manager.io/paused annotation.
+func (c *controller) isPaused(ch *cmacme.Challenge) (bool, error) {
+	if apiutil.IsPaused(ch) {
+		return true, nil
+	}
+	ref := metav1.GetControllerOf(ch)
+	if ref == nil || ref.APIVersion != orderGvk.GroupVersion().String() || ref.Kind != orderGvk.Kind {
+		return false, nil
+	}
+	order, err := c.orderLister.Orders(ch.Namespace).Get(ref.Name)
+	if k8sErrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return order.UID == ref.UID && apiutil.IsPaused(order), nil
+}
@@ -23,7 +23,6 @@ import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
@@ -39,6 +38,7 @@ import (
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/http"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
 )
 
 type controller struct {
@@ -50,6 +50,7 @@ type controller struct {
 
 	// all the listers used by this controller
 	challengeLister     cmacmelisters.ChallengeLister
+	orderLister         cmacmelisters.OrderLister
 	issuerLister        cmlisters.IssuerLister
 	clusterIssuerLister cmlisters.ClusterIssuerLister
 	secretLister        corelisters.SecretLister
@@ -68,6 +69,22 @@ type controller struct {
 	recorder record.EventRecorder
 	// clientset used to update cert-manager API resources
 	cmClient cmclient.Interface
+	// metrics is used to record the types of ACME errors that cause
+	// Challenges to fail
+	metrics *metrics.Metrics
+	// fieldManager and schedulerFieldManager are the field managers used to
+	// apply the status of Challenges in Sync and in the scheduler if the
+	// ServerSideApply feature is enabled
+	fieldManager          string
+	schedulerFieldManager string
+
+	// providerOutages pauses DNS01 Challenges while their provider is
+	// unavailable
+	providerOutages *providerOutages
+
+	// hooks calls the pre-present and post-cleanup hooks of challenge
+	// solvers
+	hooks *hookRunner
 
 	// maintain a reference to the workqueue for this controller
 	// so the handleOwnedResource method can enqueue resources
@@ -84,10 +101,11 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.log = logf.FromContext(ctx.RootContext, ControllerName)
 
 	// create a queue used to queue up items to be processed
-	c.queue = workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*5, time.Minute*30), ControllerName)
+	c.queue = controllerpkg.NewRateLimitingQueue(ctx.BackoffPersister, workqueue.NewItemExponentialFailureRateLimiter(time.Second*5, time.Minute*30), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	challengeInformer := ctx.SharedInformerFactory.Acme().V1alpha2().Challenges()
+	orderInformer := ctx.SharedInformerFactory.Acme().V1alpha2().Orders()
 	issuerInformer := ctx.SharedInformerFactory.Certmanager().V1alpha2().Issuers()
 	secretInformer := ctx.KubeSharedInformerFactory.Core().V1().Secrets()
 	// we register these informers here so the HTTP01 solver has a synced
@@ -99,6 +117,7 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	// the controller will only begin processing items once all of these informers have synced.
 	mustSync := []cache.InformerSynced{
 		challengeInformer.Informer().HasSynced,
+		orderInformer.Informer().HasSynced,
 		issuerInformer.Informer().HasSynced,
 		secretInformer.Informer().HasSynced,
 		podInformer.Informer().HasSynced,
@@ -108,6 +127,7 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 
 	// set all the references to the listers for used by the Sync function
 	c.challengeLister = challengeInformer.Lister()
+	c.orderLister = orderInformer.Lister()
 	c.issuerLister = issuerInformer.Lister()
 	c.secretLister = secretInformer.Lister()
 
@@ -121,11 +141,21 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 
 	// register handler functions
 	challengeInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: c.queue})
+	// Challenges are re-queued when their Order is paused or resumed
+	orderInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{WorkFunc: c.handleOrder})
 
 	c.helper = issuer.NewHelper(c.issuerLister, c.clusterIssuerLister)
 	c.scheduler = scheduler.New(logf.NewContext(ctx.RootContext, c.log), c.challengeLister, ctx.SchedulerOptions.MaxConcurrentChallenges)
+	if ctx.RuntimeConfig != nil {
+		ctx.RuntimeConfig.OnChange(func(values controllerpkg.RuntimeConfigValues) {
+			c.scheduler.SetMaxConcurrentChallenges(values.MaxConcurrentChallenges)
+		})
+	}
 	c.recorder = ctx.Recorder
 	c.cmClient = ctx.CMClient
+	c.fieldManager = ctx.FieldManagerFor(ControllerName)
+	c.schedulerFieldManager = ctx.FieldManagerFor(ControllerName + "-scheduler")
+	c.metrics = ctx.Metrics
 	c.httpSolver = http.NewSolver(ctx)
 	c.accountRegistry = ctx.ACMEOptions.AccountRegistry
 
@@ -137,6 +167,8 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 
 	// read options from context
 	c.dns01Nameservers = ctx.ACMEOptions.DNS01Nameservers
+	c.hooks = newHookRunner()
+	c.providerOutages = newProviderOutages(ctx.ACMEOptions.DNS01ProviderOutageThreshold, ctx.ACMEOptions.DNS01ProviderOutageProbeInterval, ctx.Clock)
 
 	return c.queue, mustSync, nil
 }
@@ -164,10 +196,11 @@ func (c *controller) runScheduler(ctx context.Context) {
 
 	for _, ch := range toSchedule {
 		log := logf.WithResource(log, ch)
+		existing := ch
 		ch = ch.DeepCopy()
 		ch.Status.Processing = true
 
-		_, err := c.cmClient.AcmeV1alpha2().Challenges(ch.Namespace).UpdateStatus(context.TODO(), ch, metav1.UpdateOptions{})
+		_, err := c.updateStatus(context.TODO(), c.schedulerFieldManager, schedulerStatusFields, existing, ch)
 		if err != nil {
 			log.Error(err, "error scheduling challenge for processing")
 			return
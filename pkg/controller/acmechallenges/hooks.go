@@ -0,0 +1,201 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acmechallenges
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+const (
+	// reasonPrePresentHookError and reasonPostCleanUpHookError are the
+	// reasons of the Events recorded on a Challenge when one of the hooks of
+	// its solver fails
+	reasonPrePresentHookError  = "PrePresentHookError"
+	reasonPostCleanUpHookError = "PostCleanUpHookError"
+
+	// defaultHookTimeout and defaultHookRetries are used for hooks that do
+	// not set a timeout or number of retries
+	defaultHookTimeout = 10 * time.Second
+	defaultHookRetries = 2
+
+	// maxHookDuration bounds the total time spent calling a hook, including
+	// all of its retries and the back-off between them, so that a hook cannot
+	// block a worker of the controller for longer
+	maxHookDuration = time.Minute
+)
+
+// hookEvent is the event a hook is called for.
+type hookEvent string
+
+const (
+	hookEventPrePresent  hookEvent = "PrePresent"
+	hookEventPostCleanUp hookEvent = "PostCleanUp"
+)
+
+// hookPayload is the JSON body POSTed to hooks. The key of the challenge is
+// not sent, as it must only be known to the solver presenting it.
+type hookPayload struct {
+	Event     hookEvent `json:"event"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	DNSName   string    `json:"dnsName"`
+	Wildcard  bool      `json:"wildcard"`
+	Token     string    `json:"token"`
+}
+
+// hookRunner calls the hooks configured on the solvers of Challenges.
+type hookRunner struct {
+	// retryInterval is the time waited before the first retry of a failed
+	// request, and is doubled for every further retry
+	retryInterval time.Duration
+	// maxDuration is the total time a single call of a hook may take
+	maxDuration time.Duration
+}
+
+func newHookRunner() *hookRunner {
+	return &hookRunner{retryInterval: time.Second, maxDuration: maxHookDuration}
+}
+
+// prePresentHook and postCleanUpHook return the hooks of the solver of ch,
+// or nil if it is not configured.
+func prePresentHook(ch *cmacme.Challenge) *cmacme.ACMEChallengeHook {
+	if ch.Spec.Solver.Hooks == nil {
+		return nil
+	}
+	return ch.Spec.Solver.Hooks.PrePresent
+}
+
+func postCleanUpHook(ch *cmacme.Challenge) *cmacme.ACMEChallengeHook {
+	if ch.Spec.Solver.Hooks == nil {
+		return nil
+	}
+	return ch.Spec.Solver.Hooks.PostCleanUp
+}
+
+// run calls hook for the given event of ch. Requests that fail with a
+// network error, a 429 or a 5xx response are retried with back-off up to
+// the number of retries of the hook. Any other non-2xx response fails the
+// call immediately. The call fails once it has taken longer than the
+// maximum duration of the runner, regardless of the retries left.
+func (r *hookRunner) run(ctx context.Context, event hookEvent, hook *cmacme.ACMEChallengeHook, ch *cmacme.Challenge) error {
+	log := logf.FromContext(ctx, "hooks").WithValues("event", event)
+
+	ctx, cancel := context.WithTimeout(ctx, r.maxDuration)
+	defer cancel()
+
+	body, err := json.Marshal(hookPayload{
+		Event:     event,
+		Namespace: ch.Namespace,
+		Name:      ch.Name,
+		Type:      string(ch.Spec.Type),
+		DNSName:   ch.Spec.DNSName,
+		Wildcard:  ch.Spec.Wildcard,
+		Token:     ch.Spec.Token,
+	})
+	if err != nil {
+		return err
+	}
+
+	client, err := hookClient(hook)
+	if err != nil {
+		return err
+	}
+	// every call uses its own transport, so do not keep connections open
+	defer client.CloseIdleConnections()
+
+	retries := defaultHookRetries
+	if hook.Retries != nil {
+		retries = *hook.Retries
+	}
+
+	interval := r.retryInterval
+	for attempt := 0; ; attempt++ {
+		retry, err := callHook(ctx, client, hook.URL, body)
+		if err == nil {
+			log.V(logf.DebugLevel).Info("called challenge hook", "url", hook.URL)
+			return nil
+		}
+		if !retry || attempt >= retries {
+			return fmt.Errorf("calling %s hook %q: %v", event, hook.URL, err)
+		}
+
+		log.V(logf.DebugLevel).Info("challenge hook failed, retrying", "url", hook.URL, "error", err.Error(), "retry_in", interval)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("calling %s hook %q: %v", event, hook.URL, ctx.Err())
+		case <-time.After(interval):
+		}
+		interval *= 2
+	}
+}
+
+// callHook POSTs body to url, returning an error if the request fails and
+// whether it should be retried.
+func callHook(ctx context.Context, client *http.Client, url string, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	// drain the body so that the connection can be reused
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("unexpected response status %q", resp.Status)
+}
+
+// hookClient returns an HTTP client using the timeout and CA bundle of hook.
+func hookClient(hook *cmacme.ACMEChallengeHook) (*http.Client, error) {
+	timeout := defaultHookTimeout
+	if hook.Timeout != nil {
+		timeout = hook.Timeout.Duration
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(hook.CABundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(hook.CABundle) {
+			return nil, fmt.Errorf("no valid certificates found in the CA bundle of hook %q", hook.URL)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acmechallenges
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+// hookServer returns a server responding with the given status codes in
+// order, repeating the last one, and counting the requests it received.
+func hookServer(statuses ...int) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		n := int(atomic.AddInt32(&calls, 1))
+		if n > len(statuses) {
+			n = len(statuses)
+		}
+		w.WriteHeader(statuses[n-1])
+	}))
+	return srv, &calls
+}
+
+func TestHookRunnerRun(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	tests := map[string]struct {
+		statuses    []int
+		retries     *int
+		expectErr   bool
+		expectCalls int32
+	}{
+		"succeeds on a 2xx response": {
+			statuses:    []int{nethttp.StatusNoContent},
+			expectCalls: 1,
+		},
+		"retries 5xx responses": {
+			statuses:    []int{nethttp.StatusServiceUnavailable, nethttp.StatusOK},
+			expectCalls: 2,
+		},
+		"retries 429 responses": {
+			statuses:    []int{nethttp.StatusTooManyRequests, nethttp.StatusTooManyRequests, nethttp.StatusOK},
+			expectCalls: 3,
+		},
+		"fails once retries are exhausted": {
+			statuses:    []int{nethttp.StatusInternalServerError},
+			expectErr:   true,
+			expectCalls: 3,
+		},
+		"respects the number of retries of the hook": {
+			statuses:    []int{nethttp.StatusInternalServerError},
+			retries:     intPtr(0),
+			expectErr:   true,
+			expectCalls: 1,
+		},
+		"does not retry 4xx responses": {
+			statuses:    []int{nethttp.StatusForbidden},
+			expectErr:   true,
+			expectCalls: 1,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv, calls := hookServer(test.statuses...)
+			defer srv.Close()
+
+			r := &hookRunner{retryInterval: time.Millisecond, maxDuration: time.Minute}
+			hook := &cmacme.ACMEChallengeHook{URL: srv.URL, Retries: test.retries}
+			err := r.run(context.Background(), hookEventPrePresent, hook, gen.Challenge("test"))
+			if test.expectErr != (err != nil) {
+				t.Errorf("expected error %t, got: %v", test.expectErr, err)
+			}
+			if got := atomic.LoadInt32(calls); got != test.expectCalls {
+				t.Errorf("expected %d calls, got %d", test.expectCalls, got)
+			}
+		})
+	}
+}
+
+func TestHookRunnerMaxDuration(t *testing.T) {
+	srv, calls := hookServer(nethttp.StatusServiceUnavailable)
+	defer srv.Close()
+
+	// the back-off alone would exceed the maximum duration of the runner
+	r := &hookRunner{retryInterval: time.Hour, maxDuration: 50 * time.Millisecond}
+	retries := 5
+	hook := &cmacme.ACMEChallengeHook{URL: srv.URL, Retries: &retries}
+
+	start := time.Now()
+	if err := r.run(context.Background(), hookEventPrePresent, hook, gen.Challenge("test")); err == nil {
+		t.Errorf("expected an error once the maximum duration is exceeded")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected the call to be aborted after the maximum duration, took %s", elapsed)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("expected 1 call, got %d", got)
+	}
+}
+
+func TestHookRunnerPayload(t *testing.T) {
+	var payload map[string]interface{}
+	srv := httptest.NewTLSServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("unexpected content type %q", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	ch := gen.Challenge("test",
+		gen.SetChallengeType("dns-01"),
+		gen.SetChallengeDNSName("example.com"),
+		gen.SetChallengeWildcard(true),
+	)
+	ch.Spec.Token = "token"
+	ch.Spec.Key = "key"
+
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	hook := &cmacme.ACMEChallengeHook{URL: srv.URL, CABundle: caBundle}
+	if err := newHookRunner().run(context.Background(), hookEventPostCleanUp, hook, ch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"event":     "PostCleanUp",
+		"namespace": gen.DefaultTestNamespace,
+		"name":      "test",
+		"type":      "dns-01",
+		"dnsName":   "example.com",
+		"wildcard":  true,
+		"token":     "token",
+	}
+	if !reflect.DeepEqual(payload, expected) {
+		t.Errorf("unexpected payload, exp=%v got=%v", expected, payload)
+	}
+}
+
+func TestSyncPrePresentHookFailure(t *testing.T) {
+	srv, _ := hookServer(nethttp.StatusForbidden)
+	defer srv.Close()
+
+	testIssuer := gen.Issuer("testissuer", gen.SetIssuerACME(cmacme.ACMEIssuer{}))
+	challenge := gen.Challenge("testchal",
+		gen.SetChallengeIssuer(cmmeta.ObjectReference{
+			Name: "testissuer",
+		}),
+		gen.SetChallengeProcessing(true),
+		gen.SetChallengeURL("testurl"),
+		gen.SetChallengeState(cmacme.Pending),
+		gen.SetChallengeType("http-01"),
+		gen.SetChallengeSolver(cmacme.ACMEChallengeSolver{
+			HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+				Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{},
+			},
+			Hooks: &cmacme.ACMEChallengeSolverHooks{
+				PrePresent: &cmacme.ACMEChallengeHook{URL: srv.URL},
+			},
+		}),
+	)
+	hookErr := fmt.Sprintf(`calling PrePresent hook %q: unexpected response status "403 Forbidden"`, srv.URL)
+
+	runTest(t, testT{
+		challenge: challenge,
+		httpSolver: &fakeSolver{
+			fakePresent: func(ctx context.Context, issuer v1alpha2.GenericIssuer, ch *cmacme.Challenge) error {
+				return errors.New("challenge presented despite failed pre-present hook")
+			},
+		},
+		builder: &testpkg.Builder{
+			CertManagerObjects: []runtime.Object{challenge, testIssuer},
+			ExpectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("challenges"),
+					"status",
+					gen.DefaultTestNamespace,
+					gen.ChallengeFrom(challenge,
+						gen.SetChallengeReason("Pre-present hook failed: "+hookErr),
+					))),
+			},
+			ExpectedEvents: []string{
+				"Warning PrePresentHookError Pre-present hook failed: " + hookErr,
+			},
+		},
+		expectErr: true,
+	})
+}
+
+func TestSyncPostCleanUpHookFailure(t *testing.T) {
+	srv, calls := hookServer(nethttp.StatusForbidden)
+	defer srv.Close()
+
+	testIssuer := gen.Issuer("testissuer", gen.SetIssuerACME(cmacme.ACMEIssuer{}))
+	challenge := gen.Challenge("testchal",
+		gen.SetChallengeIssuer(cmmeta.ObjectReference{
+			Name: "testissuer",
+		}),
+		gen.SetChallengeProcessing(true),
+		gen.SetChallengePresented(true),
+		gen.SetChallengeURL("testurl"),
+		gen.SetChallengeState(cmacme.Valid),
+		gen.SetChallengeType("http-01"),
+		gen.SetChallengeSolver(cmacme.ACMEChallengeSolver{
+			HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+				Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{},
+			},
+			Hooks: &cmacme.ACMEChallengeSolverHooks{
+				PostCleanUp: &cmacme.ACMEChallengeHook{URL: srv.URL},
+			},
+		}),
+	)
+	hookErr := fmt.Sprintf(`calling PostCleanUp hook %q: unexpected response status "403 Forbidden"`, srv.URL)
+
+	runTest(t, testT{
+		challenge: challenge,
+		httpSolver: &fakeSolver{
+			fakeCleanUp: func(ctx context.Context, issuer v1alpha2.GenericIssuer, ch *cmacme.Challenge) error {
+				if atomic.LoadInt32(calls) != 0 {
+					t.Errorf("post-cleanup hook called before the challenge was cleaned up")
+				}
+				return nil
+			},
+		},
+		builder: &testpkg.Builder{
+			CertManagerObjects: []runtime.Object{challenge, testIssuer},
+			ExpectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("challenges"),
+					"status",
+					gen.DefaultTestNamespace,
+					gen.ChallengeFrom(challenge,
+						gen.SetChallengePresented(false),
+						gen.SetChallengeProcessing(false),
+						gen.SetChallengeReason("Post-cleanup hook failed: "+hookErr),
+					))),
+			},
+			ExpectedEvents: []string{
+				"Warning PostCleanUpHookError Post-cleanup hook failed: " + hookErr,
+			},
+		},
+	})
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acmechallenges
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	dnsutil "github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
+)
+
+// This file implements the detection of outages of DNS01 providers.
+//
+// When the API of a DNS provider is down or rejects its credentials, every
+// Challenge using it fails to be presented and is retried on its own, which
+// floods the logs and the provider's API with requests that cannot succeed.
+// Instead, once a number of consecutive requests to a provider have failed
+// systematically, all Challenges using it are paused, and a single Challenge
+// is let through every probe interval to find out if the provider has
+// recovered. Once it has, the paused Challenges are resumed.
+
+// providerOutages tracks the health of the DNS01 providers used by
+// Challenges.
+// Providers are identified by the issuer and DNS01 solver configuration of
+// the Challenge, see dnsProviderKey.
+type providerOutages struct {
+	// threshold is the number of consecutive failures after which a
+	// provider is considered unavailable. If zero, outage detection is
+	// disabled.
+	threshold int
+	// probeInterval is the amount of time between requests to an
+	// unavailable provider checking if it has recovered
+	probeInterval time.Duration
+	clock         clock.Clock
+
+	lock      sync.Mutex
+	providers map[string]*providerHealth
+}
+
+// providerHealth is the state of a single provider that has failed.
+type providerHealth struct {
+	failures    int
+	unavailable bool
+	lastError   string
+	// nextProbe is the time after which the next request is let through to
+	// an unavailable provider
+	nextProbe time.Time
+	// probing is true while a probe request is in flight
+	probing bool
+	// paused are the keys of the Challenges that have been paused while the
+	// provider is unavailable
+	paused map[string]struct{}
+}
+
+func newProviderOutages(threshold int, probeInterval time.Duration, clock clock.Clock) *providerOutages {
+	return &providerOutages{
+		threshold:     threshold,
+		probeInterval: probeInterval,
+		clock:         clock,
+		providers:     make(map[string]*providerHealth),
+	}
+}
+
+// allow returns true if the Challenge with the given key may send requests
+// to the provider. If not, the Challenge is paused until the provider has
+// recovered, and allow returns the last error of the provider and when the
+// Challenge should be checked again.
+// If the provider is unavailable but due to be probed, the Challenge is let
+// through as the probe. Its outcome must be reported with record.
+func (o *providerOutages) allow(provider, key string) (bool, string, time.Duration) {
+	if o.threshold <= 0 {
+		return true, "", 0
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	h, ok := o.providers[provider]
+	if !ok || !h.unavailable {
+		return true, "", 0
+	}
+
+	now := o.clock.Now()
+	if !h.probing && !now.Before(h.nextProbe) {
+		h.probing = true
+		delete(h.paused, key)
+		return true, "", 0
+	}
+
+	h.paused[key] = struct{}{}
+	retryAfter := h.nextProbe.Sub(now)
+	if h.probing || retryAfter <= 0 {
+		retryAfter = o.probeInterval
+	}
+	return false, h.lastError, retryAfter
+}
+
+// record records the outcome of a request to the provider. It returns true if
+// the provider is unavailable after the request, and whether it has just
+// become unavailable. If the provider has recovered, the keys of the
+// Challenges that were paused are returned so that they can be resumed.
+func (o *providerOutages) record(provider string, err error) (unavailable, becameUnavailable bool, resumed []string) {
+	if o.threshold <= 0 {
+		return false, false, nil
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	h, ok := o.providers[provider]
+	if !dnsutil.IsProviderUnavailable(err) {
+		// any other outcome shows that the provider is processing requests
+		if !ok {
+			return false, false, nil
+		}
+		delete(o.providers, provider)
+		for key := range h.paused {
+			resumed = append(resumed, key)
+		}
+		sort.Strings(resumed)
+		return false, false, resumed
+	}
+
+	if !ok {
+		h = &providerHealth{paused: make(map[string]struct{})}
+		o.providers[provider] = h
+	}
+
+	h.failures++
+	h.lastError = err.Error()
+	h.probing = false
+	if h.unavailable {
+		h.nextProbe = o.clock.Now().Add(o.probeInterval)
+		return true, false, nil
+	}
+	if h.failures >= o.threshold {
+		h.unavailable = true
+		h.nextProbe = o.clock.Now().Add(o.probeInterval)
+		return true, true, nil
+	}
+	return false, false, nil
+}
+
+// dnsProviderKey returns the key identifying the DNS01 provider that the
+// Challenge is solved with: Challenges of the same issuer using the same
+// DNS01 solver configuration, and thereby the same credentials, share a
+// provider. It returns an empty string for other types of Challenges.
+func dnsProviderKey(ch *cmacme.Challenge) string {
+	if ch.Spec.Type != cmacme.ACMEChallengeTypeDNS01 || ch.Spec.Solver.DNS01 == nil {
+		return ""
+	}
+
+	kind := ch.Spec.IssuerRef.Kind
+	if kind == "" {
+		kind = cmapi.IssuerKind
+	}
+	issuer := ch.Spec.IssuerRef.Name
+	if kind == cmapi.IssuerKind {
+		issuer = ch.Namespace + "/" + issuer
+	}
+
+	config, err := json.Marshal(ch.Spec.Solver.DNS01)
+	if err != nil {
+		// this should never happen, but fall back to not grouping
+		// challenges rather than failing them
+		return ""
+	}
+	sum := sha256.Sum256(config)
+
+	return fmt.Sprintf("%s/%s/%s", kind, issuer, hex.EncodeToString(sum[:8]))
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acmechallenges
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	dnsutil "github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestProviderOutages(t *testing.T) {
+	clock := fakeclock.NewFakeClock(time.Now())
+	o := newProviderOutages(2, time.Minute, clock)
+	outage := &dnsutil.ProviderError{StatusCode: http.StatusServiceUnavailable, Err: errors.New("service unavailable")}
+
+	for i := 0; i < 2; i++ {
+		if ok, _, _ := o.allow("provider", "ns/ch-1"); !ok {
+			t.Fatalf("expected challenge to be allowed before threshold reached")
+		}
+		unavailable, became, _ := o.record("provider", outage)
+		if exp := i == 1; unavailable != exp || became != exp {
+			t.Errorf("expected unavailable=%t after %d failures, got unavailable=%t became=%t", exp, i+1, unavailable, became)
+		}
+	}
+
+	ok, lastErr, retryAfter := o.allow("provider", "ns/ch-2")
+	if ok {
+		t.Fatalf("expected challenge to be paused while the provider is unavailable")
+	}
+	if lastErr != outage.Error() {
+		t.Errorf("expected last error %q, got: %q", outage.Error(), lastErr)
+	}
+	if retryAfter != time.Minute {
+		t.Errorf("expected challenge to be retried after the probe interval, got: %s", retryAfter)
+	}
+
+	// other providers are not affected
+	if ok, _, _ := o.allow("other-provider", "ns/ch-3"); !ok {
+		t.Errorf("expected challenge of another provider to be allowed")
+	}
+
+	// a single probe is let through once the probe interval has passed
+	clock.Step(time.Minute)
+	if ok, _, _ := o.allow("provider", "ns/ch-2"); !ok {
+		t.Fatalf("expected probe to be let through after the probe interval")
+	}
+	if ok, _, _ := o.allow("provider", "ns/ch-4"); ok {
+		t.Errorf("expected only a single probe to be let through")
+	}
+
+	// a failed probe keeps the provider unavailable
+	if unavailable, became, _ := o.record("provider", outage); !unavailable || became {
+		t.Errorf("expected provider to stay unavailable after failed probe, got unavailable=%t became=%t", unavailable, became)
+	}
+
+	// a successful probe resumes all paused challenges
+	clock.Step(time.Minute)
+	if ok, _, _ := o.allow("provider", "ns/ch-4"); !ok {
+		t.Fatalf("expected probe to be let through after the probe interval")
+	}
+	unavailable, _, resumed := o.record("provider", nil)
+	if unavailable {
+		t.Errorf("expected provider to be available after successful probe")
+	}
+	if exp := []string{"ns/ch-2"}; !reflect.DeepEqual(resumed, exp) {
+		t.Errorf("expected resumed challenges %v, got: %v", exp, resumed)
+	}
+	if ok, _, _ := o.allow("provider", "ns/ch-2"); !ok {
+		t.Errorf("expected challenge to be allowed after the provider recovered")
+	}
+}
+
+func TestProviderOutagesIgnoresOtherErrors(t *testing.T) {
+	o := newProviderOutages(1, time.Minute, fakeclock.NewFakeClock(time.Now()))
+
+	errs := []error{
+		errors.New("no hosted zone found for example.com"),
+		&dnsutil.ProviderError{StatusCode: http.StatusBadRequest, Err: errors.New("invalid record")},
+	}
+	for _, err := range errs {
+		if unavailable, _, _ := o.record("provider", err); unavailable {
+			t.Errorf("expected %q not to make the provider unavailable", err)
+		}
+	}
+
+	o = newProviderOutages(0, time.Minute, fakeclock.NewFakeClock(time.Now()))
+	if unavailable, _, _ := o.record("provider", &dnsutil.ProviderError{Err: errors.New("connection refused")}); unavailable {
+		t.Errorf("expected outage detection to be disabled with a threshold of 0")
+	}
+}
+
+func TestDNSProviderKey(t *testing.T) {
+	cloudflare := cmacme.ACMEChallengeSolver{
+		DNS01: &cmacme.ACMEChallengeSolverDNS01{
+			Cloudflare: &cmacme.ACMEIssuerDNS01ProviderCloudflare{Email: "admin@example.com"},
+		},
+	}
+	route53 := cmacme.ACMEChallengeSolver{
+		DNS01: &cmacme.ACMEChallengeSolverDNS01{
+			Route53: &cmacme.ACMEIssuerDNS01ProviderRoute53{Region: "eu-west-1"},
+		},
+	}
+	challenge := func(name, namespace, issuerKind string, solver cmacme.ACMEChallengeSolver) *cmacme.Challenge {
+		return gen.Challenge(name,
+			gen.SetChallengeNamespace(namespace),
+			gen.SetChallengeType(string(cmacme.ACMEChallengeTypeDNS01)),
+			gen.SetChallengeIssuer(cmmeta.ObjectReference{Name: "issuer", Kind: issuerKind}),
+			gen.SetChallengeSolver(solver),
+		)
+	}
+
+	a := dnsProviderKey(challenge("a", "ns", cmapi.IssuerKind, cloudflare))
+	if a == "" {
+		t.Fatalf("expected a provider key for a DNS01 challenge")
+	}
+	if b := dnsProviderKey(challenge("b", "ns", cmapi.IssuerKind, cloudflare)); a != b {
+		t.Errorf("expected challenges with the same issuer and solver to share a provider, got %q and %q", a, b)
+	}
+	if b := dnsProviderKey(challenge("b", "other-ns", cmapi.IssuerKind, cloudflare)); a == b {
+		t.Errorf("expected Issuers in different namespaces not to share a provider")
+	}
+	if b := dnsProviderKey(challenge("b", "ns", cmapi.IssuerKind, route53)); a == b {
+		t.Errorf("expected challenges with different solvers not to share a provider")
+	}
+
+	c := dnsProviderKey(challenge("c", "ns", cmapi.ClusterIssuerKind, cloudflare))
+	if d := dnsProviderKey(challenge("d", "other-ns", cmapi.ClusterIssuerKind, cloudflare)); c != d {
+		t.Errorf("expected challenges of a ClusterIssuer to share a provider across namespaces, got %q and %q", c, d)
+	}
+
+	http01 := gen.Challenge("e", gen.SetChallengeType(string(cmacme.ACMEChallengeTypeHTTP01)))
+	if key := dnsProviderKey(http01); key != "" {
+		t.Errorf("expected no provider key for an HTTP01 challenge, got: %q", key)
+	}
+}
@@ -19,6 +19,7 @@ package scheduler
 import (
 	"context"
 	"sort"
+	"sync"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/labels"
@@ -33,8 +34,10 @@ import (
 // to challenge resources in order to determine which challenges should be
 // processing at a given time.
 type Scheduler struct {
-	log                     logr.Logger
-	challengeLister         cmacmelisters.ChallengeLister
+	log             logr.Logger
+	challengeLister cmacmelisters.ChallengeLister
+
+	lock                    sync.RWMutex
 	maxConcurrentChallenges int
 }
 
@@ -44,6 +47,21 @@ func New(ctx context.Context, l cmacmelisters.ChallengeLister, maxConcurrentChal
 	return &Scheduler{log: log, challengeLister: l, maxConcurrentChallenges: maxConcurrentChallenges}
 }
 
+// SetMaxConcurrentChallenges changes the maximum number of challenges that
+// may be processing at once. Challenges that are already processing are not
+// affected if the maximum is lowered.
+func (s *Scheduler) SetMaxConcurrentChallenges(n int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.maxConcurrentChallenges = n
+}
+
+func (s *Scheduler) getMaxConcurrentChallenges() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.maxConcurrentChallenges
+}
+
 // ScheduleN will return a maximum of N challenge resources that should be
 // scheduled for processing.
 // It may return an empty list if there are no challenges that can/should be
@@ -68,7 +86,7 @@ func (s *Scheduler) scheduleN(n int, allChallenges []*cmacme.Challenge) ([]*cmac
 	}
 
 	numberToSelect := n
-	remainingNumberAllowedChallenges := s.maxConcurrentChallenges - inProgressChallengeCount
+	remainingNumberAllowedChallenges := s.getMaxConcurrentChallenges() - inProgressChallengeCount
 	if numberToSelect > remainingNumberAllowedChallenges {
 		numberToSelect = remainingNumberAllowedChallenges
 	}
@@ -106,8 +124,9 @@ func (s *Scheduler) determineChallengeCandidates(allChallenges []*cmacme.Challen
 	// Ensure we only run a max of MaxConcurrentChallenges at a time
 	// We perform this check here to avoid extra processing if we've already
 	// hit the maximum number of challenges.
-	if inProgressChallengeCount >= s.maxConcurrentChallenges {
-		s.log.V(logs.DebugLevel).Info("hit maximum concurrent challenge limit. refusing to schedule more challenges.", "in_progress", len(inProgress), "max_concurrent", s.maxConcurrentChallenges)
+	maxConcurrentChallenges := s.getMaxConcurrentChallenges()
+	if inProgressChallengeCount >= maxConcurrentChallenges {
+		s.log.V(logs.DebugLevel).Info("hit maximum concurrent challenge limit. refusing to schedule more challenges.", "in_progress", len(inProgress), "max_concurrent", maxConcurrentChallenges)
 		return []*cmacme.Challenge{}, inProgressChallengeCount, nil
 	}
 
@@ -326,3 +326,25 @@ func TestScheduleN(t *testing.T) {
 		})
 	}
 }
+
+func TestSetMaxConcurrentChallenges(t *testing.T) {
+	s := New(context.Background(), nil, 2)
+	challenges := ascendingChallengeN(10)
+
+	chs, err := s.scheduleN(10, challenges)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chs) != 2 {
+		t.Errorf("expected 2 challenges to be scheduled but got %d", len(chs))
+	}
+
+	s.SetMaxConcurrentChallenges(5)
+	chs, err = s.scheduleN(10, challenges)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(chs, challenges[:5]) {
+		t.Errorf("expected the 5 oldest challenges to be scheduled: %v", diff.ObjectDiff(challenges[:5], chs))
+	}
+}
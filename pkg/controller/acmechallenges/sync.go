@@ -18,6 +18,7 @@ package acmechallenges
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"time"
@@ -29,17 +30,44 @@ import (
 
 	"github.com/jetstack/cert-manager/pkg/acme"
 	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/feature"
 	dnsutil "github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/http"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/apply"
 	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 )
 
+var (
+	// statusFields are the status fields and conditions of Challenges that
+	// are managed by Sync.
+	statusFields = apply.Fields{
+		Status:     []string{"processing", "presented", "reason", "state", "failureType"},
+		Conditions: []string{string(cmacme.ChallengeConditionProviderUnavailable)},
+	}
+	// schedulerStatusFields are the status fields of Challenges that are
+	// managed by the scheduler.
+	schedulerStatusFields = apply.Fields{
+		Status: []string{"processing"},
+	}
+)
+
 const (
 	reasonDomainVerified = "DomainVerified"
+
+	// reasonProviderUnavailable and reasonProviderAvailable are the reasons
+	// of the ProviderUnavailable condition and of the events fired when a
+	// DNS01 provider becomes unavailable or recovers
+	reasonProviderUnavailable = "ProviderUnavailable"
+	reasonProviderAvailable   = "ProviderAvailable"
+
+	// challengeKind is the kind used to label metrics recorded for Challenges
+	challengeKind = "Challenge"
 )
 
 // solver solves ACME challenges by presenting the given token and key in an
@@ -61,11 +89,20 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 	log := logf.FromContext(ctx).WithValues("dnsName", ch.Spec.DNSName, "type", ch.Spec.Type)
 	ctx = logf.NewContext(ctx, log)
 
+	paused, err := c.isPaused(ch)
+	if err != nil {
+		return err
+	}
+	if paused {
+		log.V(logf.DebugLevel).Info("challenge or its order is paused, skipping processing")
+		return nil
+	}
+
 	oldChal := ch
 	ch = ch.DeepCopy()
 
 	if ch.DeletionTimestamp != nil {
-		return c.handleFinalizer(ctx, ch)
+		return c.handleFinalizer(ctx, oldChal, ch)
 	}
 
 	defer func() {
@@ -73,7 +110,7 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 		if reflect.DeepEqual(oldChal.Status, ch.Status) && len(oldChal.Finalizers) == len(ch.Finalizers) {
 			return
 		}
-		_, updateErr := c.cmClient.AcmeV1alpha2().Challenges(ch.Namespace).UpdateStatus(context.TODO(), ch, metav1.UpdateOptions{})
+		_, updateErr := c.updateStatus(context.TODO(), c.fieldManager, statusFields, oldChal, ch)
 		if updateErr != nil {
 			err = utilerrors.NewAggregate([]error{err, updateErr})
 		}
@@ -109,6 +146,7 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 			}
 
 			ch.Status.Presented = false
+			c.runPostCleanUpHook(ctx, ch)
 		}
 
 		ch.Status.Processing = false
@@ -124,7 +162,7 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 	if ch.Status.State == "" {
 		err := c.syncChallengeStatus(ctx, cl, ch)
 		if err != nil {
-			return handleError(ch, err)
+			return c.handleError(ch, err)
 		}
 
 		// if the state has not changed, return an error
@@ -147,7 +185,7 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 		// Find out which identity the ACME server says it will use.
 		dir, err := cl.Discover(ctx)
 		if err != nil {
-			return handleError(ch, err)
+			return c.handleError(ch, err)
 		}
 		// TODO(dmo): figure out if missing CAA identity in directory
 		// means no CAA check is performed by ACME server or if any valid
@@ -167,7 +205,40 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 	}
 
 	if !ch.Status.Presented {
+		provider := dnsProviderKey(ch)
+		if provider != "" {
+			paused, err := c.pauseIfProviderUnavailable(ch, provider)
+			if paused || err != nil {
+				return err
+			}
+		}
+
+		if hook := prePresentHook(ch); hook != nil {
+			if err := c.hooks.run(ctx, hookEventPrePresent, hook, ch); err != nil {
+				log.Error(err, "pre-present hook failed")
+				c.recorder.Eventf(ch, corev1.EventTypeWarning, reasonPrePresentHookError, "Pre-present hook failed: %v", err)
+				ch.Status.Reason = fmt.Sprintf("Pre-present hook failed: %v", err)
+				return err
+			}
+		}
+
 		err := solver.Present(ctx, genericIssuer, ch)
+		if provider != "" {
+			paused, err := c.recordProviderOutcome(ctx, ch, provider, err)
+			if paused || err != nil {
+				return err
+			}
+		}
+		var budgetErr *http.ResourceBudgetError
+		if errors.As(err, &budgetErr) {
+			// retrying will not succeed until the ResourceQuota or LimitRange
+			// of the namespace is changed, so fail the challenge so that the
+			// order can be retried
+			c.recorder.Eventf(ch, corev1.EventTypeWarning, "PresentError", "Error presenting challenge: %v", err)
+			ch.Status.State = cmacme.Errored
+			ch.Status.Reason = budgetErr.Error()
+			return nil
+		}
 		if err != nil {
 			c.recorder.Eventf(ch, corev1.EventTypeWarning, "PresentError", "Error presenting challenge: %v", err)
 			ch.Status.Reason = err.Error()
@@ -203,10 +274,73 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 	return nil
 }
 
+// pauseIfProviderUnavailable pauses the Challenge if its DNS01 provider is
+// unavailable, setting the ProviderUnavailable condition and checking the
+// Challenge again once the provider is due to be probed. It returns true if
+// the Challenge has been paused.
+func (c *controller) pauseIfProviderUnavailable(ch *cmacme.Challenge, provider string) (bool, error) {
+	key, err := controllerpkg.KeyFunc(ch)
+	if err != nil {
+		return false, err
+	}
+
+	ok, lastErr, retryAfter := c.providerOutages.allow(provider, key)
+	if ok {
+		return false, nil
+	}
+
+	message := fmt.Sprintf("Waiting for the DNS01 provider to recover: %s", lastErr)
+	apiutil.SetChallengeCondition(ch, cmacme.ChallengeConditionProviderUnavailable, cmmeta.ConditionTrue, reasonProviderUnavailable, message)
+	ch.Status.Reason = message
+	c.queue.AddAfter(key, retryAfter)
+	return true, nil
+}
+
+// recordProviderOutcome records the outcome of presenting the Challenge with
+// its DNS01 provider. If the provider is unavailable, the Challenge is paused
+// and true is returned. If the provider has recovered from an outage, the
+// Challenges that were paused are resumed.
+func (c *controller) recordProviderOutcome(ctx context.Context, ch *cmacme.Challenge, provider string, presentErr error) (bool, error) {
+	log := logf.FromContext(ctx)
+
+	unavailable, becameUnavailable, resumed := c.providerOutages.record(provider, presentErr)
+	if len(resumed) > 0 {
+		log.Info("DNS01 provider has recovered, resuming paused challenges", "resumed", len(resumed))
+		c.recorder.Eventf(ch, corev1.EventTypeNormal, reasonProviderAvailable, "DNS01 provider has recovered, resuming %d paused challenges", len(resumed))
+		for _, key := range resumed {
+			c.queue.Add(key)
+		}
+	}
+
+	if !unavailable {
+		if cond := apiutil.GetChallengeCondition(ch, cmacme.ChallengeConditionProviderUnavailable); cond != nil && cond.Status == cmmeta.ConditionTrue {
+			apiutil.SetChallengeCondition(ch, cmacme.ChallengeConditionProviderUnavailable, cmmeta.ConditionFalse, reasonProviderAvailable, "The DNS01 provider is available")
+		}
+		return false, nil
+	}
+
+	if becameUnavailable {
+		// only the request that causes the provider to be considered
+		// unavailable is logged, the Challenges paused afterwards are not
+		log.Error(presentErr, "DNS01 provider is unavailable, pausing all challenges using it until it recovers")
+		c.recorder.Eventf(ch, corev1.EventTypeWarning, reasonProviderUnavailable, "DNS01 provider is unavailable, pausing all challenges using it until it recovers: %v", presentErr)
+	}
+
+	key, err := controllerpkg.KeyFunc(ch)
+	if err != nil {
+		return false, err
+	}
+	message := fmt.Sprintf("Waiting for the DNS01 provider to recover: %v", presentErr)
+	apiutil.SetChallengeCondition(ch, cmacme.ChallengeConditionProviderUnavailable, cmmeta.ConditionTrue, reasonProviderUnavailable, message)
+	ch.Status.Reason = message
+	c.queue.AddAfter(key, c.providerOutages.probeInterval)
+	return true, nil
+}
+
 // handleError will handle ACME error types, updating the challenge resource
 // with any new information found whilst inspecting the error response.
 // This may include marking the challenge as expired.
-func handleError(ch *cmacme.Challenge, err error) error {
+func (c *controller) handleError(ch *cmacme.Challenge, err error) error {
 	if err == nil {
 		return nil
 	}
@@ -231,6 +365,7 @@ func handleError(ch *cmacme.Challenge, err error) error {
 	if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
 		ch.Status.State = cmacme.Errored
 		ch.Status.Reason = fmt.Sprintf("Failed to retrieve Order resource: %v", err)
+		c.recordACMEError(ch, acmeErr)
 		return nil
 	}
 
@@ -238,8 +373,9 @@ func handleError(ch *cmacme.Challenge, err error) error {
 }
 
 // handleFinalizer will attempt to 'finalize' the Challenge resource by calling
-// CleanUp if the resource is in a 'processing' state.
-func (c *controller) handleFinalizer(ctx context.Context, ch *cmacme.Challenge) (err error) {
+// CleanUp if the resource is in a 'processing' state. existing is the
+// Challenge that ch was copied from.
+func (c *controller) handleFinalizer(ctx context.Context, existing, ch *cmacme.Challenge) (err error) {
 	log := logf.FromContext(ctx, "finalizer")
 	if len(ch.Finalizers) == 0 {
 		return nil
@@ -251,7 +387,7 @@ func (c *controller) handleFinalizer(ctx context.Context, ch *cmacme.Challenge)
 
 	defer func() {
 		// call UpdateStatus first as we may have updated the challenge.status.reason field
-		ch, updateErr := c.cmClient.AcmeV1alpha2().Challenges(ch.Namespace).UpdateStatus(context.TODO(), ch, metav1.UpdateOptions{})
+		ch, updateErr := c.updateStatus(context.TODO(), c.fieldManager, statusFields, existing, ch)
 		if updateErr != nil {
 			err = utilerrors.NewAggregate([]error{err, updateErr})
 			return
@@ -288,9 +424,26 @@ func (c *controller) handleFinalizer(ctx context.Context, ch *cmacme.Challenge)
 		return nil
 	}
 
+	c.runPostCleanUpHook(ctx, ch)
+
 	return nil
 }
 
+// runPostCleanUpHook calls the post-cleanup hook of the solver of ch, if
+// configured. Failures are recorded on the Challenge but are not returned,
+// as the challenge has already been cleaned up.
+func (c *controller) runPostCleanUpHook(ctx context.Context, ch *cmacme.Challenge) {
+	hook := postCleanUpHook(ch)
+	if hook == nil {
+		return
+	}
+	if err := c.hooks.run(ctx, hookEventPostCleanUp, hook, ch); err != nil {
+		logf.FromContext(ctx).Error(err, "post-cleanup hook failed")
+		c.recorder.Eventf(ch, corev1.EventTypeWarning, reasonPostCleanUpHookError, "Post-cleanup hook failed: %v", err)
+		ch.Status.Reason = fmt.Sprintf("Post-cleanup hook failed: %v", err)
+	}
+}
+
 // syncChallengeStatus will communicate with the ACME server to retrieve the current
 // state of the Challenge. It will then update the Challenge's status block with the new
 // state of the Challenge.
@@ -313,12 +466,20 @@ func (c *controller) syncChallengeStatus(ctx context.Context, cl acmecl.Interfac
 	// an account). We might be able to handle errors more gracefully using
 	// this info
 	ch.Status.Reason = ""
+	ch.Status.FailureType = ""
 	if acmeChallenge.Error != nil {
 		if acmeErr, ok := acmeChallenge.Error.(*acmeapi.Error); ok {
 			ch.Status.Reason = acmeErr.Detail
 		} else {
 			ch.Status.Reason = acmeChallenge.Error.Error()
 		}
+		// only count the error the first time the challenge is observed to
+		// have failed, as the challenge status may be synced many times
+		if !acme.IsFailureState(ch.Status.State) && acme.IsFailureState(cmState) {
+			c.recordACMEError(ch, acmeChallenge.Error)
+		} else {
+			ch.Status.FailureType = acme.ErrorType(acmeChallenge.Error)
+		}
 	}
 	ch.Status.State = cmState
 
@@ -347,7 +508,7 @@ func (c *controller) acceptChallenge(ctx context.Context, cl acmecl.Interface, c
 	if err != nil {
 		log.Error(err, "error accepting challenge")
 		ch.Status.Reason = fmt.Sprintf("Error accepting challenge: %v", err)
-		return handleError(ch, err)
+		return c.handleError(ch, err)
 	}
 
 	log.Info("waiting for authorization for domain")
@@ -367,7 +528,7 @@ func (c *controller) acceptChallenge(ctx context.Context, cl acmecl.Interface, c
 func (c *controller) handleAuthorizationError(ch *cmacme.Challenge, err error) error {
 	authErr, ok := err.(*acmeapi.AuthorizationError)
 	if !ok {
-		return handleError(ch, err)
+		return c.handleError(ch, err)
 	}
 
 	// TODO: the AuthorizationError above could technically contain the final
@@ -378,6 +539,7 @@ func (c *controller) handleAuthorizationError(ch *cmacme.Challenge, err error) e
 	//   if the returned state is 'invalid'
 	ch.Status.State = cmacme.Invalid
 	ch.Status.Reason = fmt.Sprintf("Error accepting authorization: %v", authErr)
+	c.recordACMEError(ch, authErr)
 	c.recorder.Eventf(ch, corev1.EventTypeWarning, "Failed", "Accepting challenge authorization failed: %v", authErr)
 
 	// return nil here, as accepting the challenge did not error, the challenge
@@ -385,6 +547,18 @@ func (c *controller) handleAuthorizationError(ch *cmacme.Challenge, err error) e
 	return nil
 }
 
+// recordACMEError records the type of the ACME error that caused the given
+// Challenge to fail on the Challenge's status, and increments the ACME error
+// metric accordingly.
+// Errors that do not contain an ACME problem document are not recorded.
+func (c *controller) recordACMEError(ch *cmacme.Challenge, err error) {
+	ch.Status.FailureType = acme.ErrorType(err)
+	if ch.Status.FailureType == "" {
+		return
+	}
+	c.metrics.IncrementACMEErrorCount(challengeKind, ch.Status.FailureType)
+}
+
 func (c *controller) solverFor(challengeType cmacme.ACMEChallengeType) (solver, error) {
 	switch challengeType {
 	case "http-01":
@@ -394,3 +568,13 @@ func (c *controller) solverFor(challengeType cmacme.ACMEChallengeType) (solver,
 	}
 	return nil, fmt.Errorf("no solver for %q implemented", challengeType)
 }
+
+// updateStatus writes the status of ch, which was computed from existing,
+// with server-side apply using the given field manager if the ServerSideApply
+// feature is enabled, and with an update otherwise.
+func (c *controller) updateStatus(ctx context.Context, fieldManager string, fields apply.Fields, existing, ch *cmacme.Challenge) (*cmacme.Challenge, error) {
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		return apply.ChallengeStatus(ctx, c.cmClient, fieldManager, fields, existing, ch)
+	}
+	return c.cmClient.AcmeV1alpha2().Challenges(ch.Namespace).UpdateStatus(ctx, ch, metav1.UpdateOptions{})
+}
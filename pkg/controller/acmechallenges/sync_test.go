@@ -18,12 +18,16 @@ package acmechallenges
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	acmeapi "golang.org/x/crypto/acme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
 
 	accountstest "github.com/jetstack/cert-manager/pkg/acme/accounts/test"
 	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
@@ -32,6 +36,8 @@ import (
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
 	"github.com/jetstack/cert-manager/pkg/issuer"
+	dnsutil "github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/http"
 	"github.com/jetstack/cert-manager/test/unit/gen"
 )
 
@@ -67,6 +73,10 @@ type testT struct {
 	dnsSolver  *fakeSolver
 	expectErr  bool
 	acmeClient *acmecl.FakeACME
+
+	// dns01ProviderOutageThreshold is set as the DNS01 provider outage
+	// threshold of the controller. If zero, outage detection is disabled.
+	dns01ProviderOutageThreshold int
 }
 
 func TestSyncHappyPath(t *testing.T) {
@@ -85,6 +95,17 @@ func TestSyncHappyPath(t *testing.T) {
 		}),
 	)
 
+	dns01Challenge := gen.ChallengeFrom(baseChallenge,
+		gen.SetChallengeType("dns-01"),
+		gen.SetChallengeSolver(cmacme.ACMEChallengeSolver{
+			DNS01: &cmacme.ACMEChallengeSolverDNS01{
+				Cloudflare: &cmacme.ACMEIssuerDNS01ProviderCloudflare{Email: "admin@example.com"},
+			},
+		}),
+	)
+	fixedClock := fakeclock.NewFakeClock(time.Now())
+	fixedClockNow := metav1.NewTime(fixedClock.Now())
+
 	tests := map[string]testT{
 		"update status if state is unknown": {
 			challenge: gen.ChallengeFrom(baseChallenge,
@@ -154,6 +175,84 @@ func TestSyncHappyPath(t *testing.T) {
 				},
 			},
 		},
+		"fail the challenge if the solver resources would be rejected": {
+			challenge: gen.ChallengeFrom(baseChallenge,
+				gen.SetChallengeProcessing(true),
+				gen.SetChallengeURL("testurl"),
+				gen.SetChallengeState(cmacme.Pending),
+				gen.SetChallengeType("http-01"),
+			),
+			httpSolver: &fakeSolver{
+				fakePresent: func(ctx context.Context, issuer v1alpha2.GenericIssuer, ch *cmacme.Challenge) error {
+					return &http.ResourceBudgetError{Reasons: []string{"some reason"}}
+				},
+			},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{gen.ChallengeFrom(baseChallenge,
+					gen.SetChallengeProcessing(true),
+					gen.SetChallengeURL("testurl"),
+					gen.SetChallengeState(cmacme.Pending),
+					gen.SetChallengeType("http-01"),
+				), testIssuerHTTP01Enabled},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("challenges"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.ChallengeFrom(baseChallenge,
+							gen.SetChallengeProcessing(true),
+							gen.SetChallengeURL("testurl"),
+							gen.SetChallengeState(cmacme.Errored),
+							gen.SetChallengeType("http-01"),
+							gen.SetChallengeReason("the HTTP01 solver resources would be rejected: some reason"),
+						))),
+				},
+				ExpectedEvents: []string{
+					"Warning PresentError Error presenting challenge: the HTTP01 solver resources would be rejected: some reason",
+				},
+			},
+		},
+		"pause the challenge if its DNS01 provider is unavailable": {
+			challenge: gen.ChallengeFrom(dns01Challenge,
+				gen.SetChallengeProcessing(true),
+				gen.SetChallengeURL("testurl"),
+				gen.SetChallengeState(cmacme.Pending),
+			),
+			dnsSolver: &fakeSolver{
+				fakePresent: func(ctx context.Context, issuer v1alpha2.GenericIssuer, ch *cmacme.Challenge) error {
+					return &dnsutil.ProviderError{StatusCode: 503, Err: errors.New("service unavailable")}
+				},
+			},
+			dns01ProviderOutageThreshold: 1,
+			builder: &testpkg.Builder{
+				Clock: fixedClock,
+				CertManagerObjects: []runtime.Object{gen.ChallengeFrom(dns01Challenge,
+					gen.SetChallengeProcessing(true),
+					gen.SetChallengeURL("testurl"),
+					gen.SetChallengeState(cmacme.Pending),
+				), testIssuerHTTP01Enabled},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("challenges"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.ChallengeFrom(dns01Challenge,
+							gen.SetChallengeProcessing(true),
+							gen.SetChallengeURL("testurl"),
+							gen.SetChallengeState(cmacme.Pending),
+							gen.SetChallengeReason("Waiting for the DNS01 provider to recover: service unavailable (HTTP status 503)"),
+							gen.SetChallengeStatusCondition(cmacme.ChallengeCondition{
+								Type:               cmacme.ChallengeConditionProviderUnavailable,
+								Status:             cmmeta.ConditionTrue,
+								LastTransitionTime: &fixedClockNow,
+								Reason:             "ProviderUnavailable",
+								Message:            "Waiting for the DNS01 provider to recover: service unavailable (HTTP status 503)",
+							}),
+						))),
+				},
+				ExpectedEvents: []string{
+					"Warning ProviderUnavailable DNS01 provider is unavailable, pausing all challenges using it until it recovers: service unavailable (HTTP status 503)",
+				},
+			},
+		},
 		"accept the challenge if the self check is passing": {
 			challenge: gen.ChallengeFrom(baseChallenge,
 				gen.SetChallengeProcessing(true),
@@ -304,6 +403,7 @@ func TestSyncHappyPath(t *testing.T) {
 							gen.SetChallengeType("http-01"),
 							gen.SetChallengePresented(true),
 							gen.SetChallengeReason("Error accepting authorization: acme: authorization error for example.com: 400 fakeerror: this is a very detailed error"),
+							gen.SetChallengeFailureType(cmacme.ACMEErrorOther),
 						))),
 				},
 				ExpectedEvents: []string{
@@ -411,11 +511,52 @@ func TestSyncHappyPath(t *testing.T) {
 	}
 }
 
+func TestSyncPaused(t *testing.T) {
+	paused := map[string]string{v1alpha2.PausedAnnotationKey: "true"}
+	order := gen.Order("testorder")
+	order.UID = "order-uid"
+	pausedOrder := gen.OrderFrom(order)
+	pausedOrder.Annotations = paused
+
+	ownedChallenge := gen.Challenge("testchal",
+		gen.SetChallengeIssuer(cmmeta.ObjectReference{Name: "testissuer"}),
+		gen.SetChallengeProcessing(true),
+		gen.SetChallengeURL("testurl"),
+	)
+	ownedChallenge.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(order, orderGvk)}
+	pausedChallenge := gen.ChallengeFrom(ownedChallenge)
+	pausedChallenge.Annotations = paused
+
+	tests := map[string]testT{
+		"do nothing if the challenge is paused": {
+			challenge: pausedChallenge,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{pausedChallenge, order},
+			},
+		},
+		"do nothing if the order owning the challenge is paused": {
+			challenge: ownedChallenge,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{ownedChallenge, pausedOrder},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			runTest(t, test)
+		})
+	}
+}
+
 func runTest(t *testing.T, test testT) {
 	test.builder.T = t
 	test.builder.Init()
 	defer test.builder.Stop()
 
+	test.builder.Context.ACMEOptions.DNS01ProviderOutageThreshold = test.dns01ProviderOutageThreshold
+	test.builder.Context.ACMEOptions.DNS01ProviderOutageProbeInterval = time.Minute
+
 	c := &controller{}
 	c.Register(test.builder.Context)
 	c.helper = issuer.NewHelper(
@@ -35,6 +35,7 @@ import (
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
 )
 
 type controller struct {
@@ -57,6 +58,12 @@ type controller struct {
 	recorder record.EventRecorder
 	// clientset used to update cert-manager API resources
 	cmClient cmclient.Interface
+	// metrics is used to record the types of ACME errors that cause Orders
+	// to fail
+	metrics *metrics.Metrics
+	// fieldManager is the field manager used to apply the status of Orders
+	// if the ServerSideApply feature is enabled
+	fieldManager string
 
 	// maintain a reference to the workqueue for this controller
 	// so the handleOwnedResource method can enqueue resources
@@ -74,7 +81,7 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.log = logf.FromContext(ctx.RootContext, ControllerName)
 
 	// create a queue used to queue up items to be processed
-	c.queue = workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*5, time.Minute*30), ControllerName)
+	c.queue = controllerpkg.NewRateLimitingQueue(ctx.BackoffPersister, workqueue.NewItemExponentialFailureRateLimiter(time.Second*5, time.Minute*30), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	orderInformer := ctx.SharedInformerFactory.Acme().V1alpha2().Orders()
@@ -117,6 +124,8 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.helper = issuer.NewHelper(c.issuerLister, c.clusterIssuerLister)
 	c.recorder = ctx.Recorder
 	c.cmClient = ctx.CMClient
+	c.fieldManager = ctx.FieldManagerFor(ControllerName)
+	c.metrics = ctx.Metrics
 	// clock is used when setting the failureTime on an Order's status
 	c.clock = ctx.Clock
 	c.accountRegistry = ctx.ACMEOptions.AccountRegistry
@@ -33,14 +33,29 @@ import (
 
 	"github.com/jetstack/cert-manager/pkg/acme"
 	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/feature"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/apply"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 )
 
+// statusFields are the status fields of Orders that are managed by this
+// controller.
+var statusFields = apply.Fields{
+	Status: []string{"url", "finalizeURL", "authorizations", "certificate", "certificateURL", "state", "reason", "failureType", "failureTime"},
+}
+
 func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 	log := logf.FromContext(ctx)
 	dbg := log.V(logf.DebugLevel)
 
+	if apiutil.IsPaused(o) {
+		dbg.Info("order is paused, skipping processing")
+		return nil
+	}
+
 	oldOrder := o
 	o = o.DeepCopy()
 
@@ -51,7 +66,12 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 			return
 		}
 		log.Info("updating Order resource status")
-		_, updateErr := c.cmClient.AcmeV1alpha2().Orders(o.Namespace).UpdateStatus(context.TODO(), o, metav1.UpdateOptions{})
+		var updateErr error
+		if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+			_, updateErr = apply.OrderStatus(context.TODO(), c.cmClient, c.fieldManager, statusFields, oldOrder, o)
+		} else {
+			_, updateErr = c.cmClient.AcmeV1alpha2().Orders(o.Namespace).UpdateStatus(context.TODO(), o, metav1.UpdateOptions{})
+		}
 		if updateErr != nil {
 			log.Error(err, "failed to update status")
 			err = utilerrors.NewAggregate([]error{err, updateErr})
@@ -79,7 +99,7 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 		if acmeErr, ok := err.(*acmeapi.Error); ok {
 			if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
 				log.Error(err, "failed to update Order status due to a 4xx error, marking Order as failed")
-				c.setOrderState(&o.Status, string(cmacme.Errored))
+				c.setOrderACMEError(o, acmeErr)
 				o.Status.Reason = fmt.Sprintf("Failed to retrieve Order resource: %v", err)
 				return nil
 			}
@@ -151,7 +171,7 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 		if acmeErr, ok := err.(*acmeapi.Error); ok {
 			if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
 				log.Error(err, "failed to update Order status due to a 4xx error, marking Order as failed")
-				c.setOrderState(&o.Status, string(cmacme.Errored))
+				c.setOrderACMEError(o, acmeErr)
 				o.Status.Reason = fmt.Sprintf("Failed to retrieve Order resource: %v", err)
 				return nil
 			}
@@ -165,7 +185,7 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 		if acmeErr, ok := err.(*acmeapi.Error); ok {
 			if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
 				log.Error(err, "failed to update Order status due to a 4xx error, marking Order as failed")
-				c.setOrderState(&o.Status, string(cmacme.Errored))
+				c.setOrderACMEError(o, acmeErr)
 				o.Status.Reason = fmt.Sprintf("Failed to retrieve Order resource: %v", err)
 				return nil
 			}
@@ -197,7 +217,7 @@ func (c *controller) createOrder(ctx context.Context, cl acmecl.Interface, o *cm
 	if acmeErr, ok := err.(*acmeapi.Error); ok {
 		if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
 			log.Error(err, "failed to create Order resource due to bad request, marking Order as failed")
-			c.setOrderState(&o.Status, string(cmacme.Errored))
+			c.setOrderACMEError(o, acmeErr)
 			o.Status.Reason = fmt.Sprintf("Failed to create Order: %v", err)
 			return nil
 		}
@@ -235,6 +255,9 @@ func (c *controller) updateOrderStatus(ctx context.Context, cl acmecl.Interface,
 		o.Status.URL = acmeOrder.URI
 	}
 	o.Status.FinalizeURL = acmeOrder.FinalizeURL
+	if acmeOrder.CertURL != "" {
+		o.Status.CertificateURL = acmeOrder.CertURL
+	}
 	c.setOrderState(&o.Status, acmeOrder.Status)
 	// once the 'authorizations' slice contains at least one item, it cannot be
 	// updated. If it does not contain any items, update it containing the list
@@ -258,6 +281,15 @@ func (c *controller) setOrderState(o *cmacme.OrderStatus, s string) {
 	}
 }
 
+// setOrderACMEError marks the given Order as Errored because of an error
+// returned by the ACME server. The type of the error is recorded on the Order's
+// status so that failures can be broken down by their cause.
+func (c *controller) setOrderACMEError(o *cmacme.Order, acmeErr *acmeapi.Error) {
+	c.setOrderState(&o.Status, string(cmacme.Errored))
+	o.Status.FailureType = acme.ErrorType(acmeErr)
+	c.metrics.IncrementACMEErrorCount(orderGvk.Kind, o.Status.FailureType)
+}
+
 // constructAuthorizations will construct a slice of ACMEAuthorizations must be
 // completed for the given ACME order.
 // It does *not* perform a query against the ACME server for each authorization
@@ -292,7 +324,7 @@ func (c *controller) fetchMetadataForAuthorizations(ctx context.Context, o *cmac
 		if acmeErr, ok := err.(*acmeapi.Error); ok {
 			if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
 				log.Error(err, "failed to fetch authorization metadata from acme server")
-				c.setOrderState(&o.Status, string(cmacme.Errored))
+				c.setOrderACMEError(o, acmeErr)
 				o.Status.Reason = fmt.Sprintf("Failed to fetch authorization: %v", err)
 				return nil
 			}
@@ -438,13 +470,13 @@ func (c *controller) finalizeOrder(ctx context.Context, cl acmecl.Interface, o *
 		derBytes = block.Bytes
 	}
 
-	certSlice, _, err := cl.CreateOrderCert(ctx, o.Status.FinalizeURL, derBytes, true)
+	certSlice, certURL, err := cl.CreateOrderCert(ctx, o.Status.FinalizeURL, derBytes, true)
 	// if an ACME error is returned and it's a 4xx error, mark this Order as
 	// failed and do not retry it until after applying the global backoff.
 	if acmeErr, ok := err.(*acmeapi.Error); ok {
 		if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
 			log.Error(err, "failed to finalize Order resource due to bad request, marking Order as failed")
-			c.setOrderState(&o.Status, string(cmacme.Errored))
+			c.setOrderACMEError(o, acmeErr)
 			o.Status.Reason = fmt.Sprintf("Failed to finalize Order: %v", err)
 			return nil
 		}
@@ -460,7 +492,7 @@ func (c *controller) finalizeOrder(ctx context.Context, cl acmecl.Interface, o *
 	if acmeErr, ok := err.(*acmeapi.Error); ok {
 		if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
 			log.Error(err, "failed to update Order status due to a 4xx error, marking Order as failed")
-			c.setOrderState(&o.Status, string(cmacme.Errored))
+			c.setOrderACMEError(o, acmeErr)
 			o.Status.Reason = fmt.Sprintf("Failed to retrieve Order resource: %v", err)
 			return nil
 		}
@@ -472,6 +504,9 @@ func (c *controller) finalizeOrder(ctx context.Context, cl acmecl.Interface, o *
 	if err != nil {
 		return fmt.Errorf("error finalizing order: %v", err)
 	}
+	if certURL != "" {
+		o.Status.CertificateURL = certURL
+	}
 
 	return c.storeCertificateOnStatus(ctx, o, certSlice)
 }
@@ -498,11 +533,19 @@ func (c *controller) storeCertificateOnStatus(ctx context.Context, o *cmacme.Ord
 
 func (c *controller) fetchCertificateData(ctx context.Context, cl acmecl.Interface, o *cmacme.Order) error {
 	log := logf.FromContext(ctx)
+
+	// if the URL of the certificate has been recorded, download it again from
+	// there without fetching the order
+	if o.Status.CertificateURL != "" {
+		log.Info("Fetching existing Certificate from certificate URL", "certificate_url", o.Status.CertificateURL)
+		return c.fetchCertificate(ctx, cl, o, o.Status.CertificateURL)
+	}
+
 	acmeOrder, err := c.updateOrderStatus(ctx, cl, o)
 	if acmeErr, ok := err.(*acmeapi.Error); ok {
 		if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
 			log.Error(err, "failed to update Order status due to a 4xx error, marking Order as failed")
-			c.setOrderState(&o.Status, string(cmacme.Errored))
+			c.setOrderACMEError(o, acmeErr)
 			o.Status.Reason = fmt.Sprintf("Failed to retrieve Order resource: %v", err)
 			return nil
 		}
@@ -522,11 +565,18 @@ func (c *controller) fetchCertificateData(ctx context.Context, cl acmecl.Interfa
 		return nil
 	}
 
-	certs, err := cl.FetchCert(ctx, acmeOrder.CertURL, true)
+	return c.fetchCertificate(ctx, cl, o, acmeOrder.CertURL)
+}
+
+// fetchCertificate downloads the certificate of the Order, including its
+// chain, from the given certificate URL and stores it on the Order status.
+func (c *controller) fetchCertificate(ctx context.Context, cl acmecl.Interface, o *cmacme.Order, certURL string) error {
+	log := logf.FromContext(ctx)
+	certs, err := cl.FetchCert(ctx, certURL, true)
 	if acmeErr, ok := err.(*acmeapi.Error); ok {
 		if acmeErr.StatusCode >= 400 && acmeErr.StatusCode < 500 {
 			log.Error(err, "failed to retrieve issued certificate from ACME server")
-			c.setOrderState(&o.Status, string(cmacme.Errored))
+			c.setOrderACMEError(o, acmeErr)
 			o.Status.Reason = fmt.Sprintf("Failed to retrieve signed certificate: %v", err)
 			return nil
 		}
@@ -535,10 +585,5 @@ func (c *controller) fetchCertificateData(ctx context.Context, cl acmecl.Interfa
 		return err
 	}
 
-	err = c.storeCertificateOnStatus(ctx, o, certs)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return c.storeCertificateOnStatus(ctx, o, certs)
 }
@@ -89,6 +89,9 @@ func TestSyncHappyPath(t *testing.T) {
 dGVzdA==
 -----END CERTIFICATE-----
 `)
+	testOrderValid.Status.CertificateURL = "http://testurl.com/abcde/cert"
+	testOrderValidWithoutCertificate := testOrderValid.DeepCopy()
+	testOrderValidWithoutCertificate.Status.Certificate = nil
 	testOrderReady := testOrderPending.DeepCopy()
 	testOrderReady.Status.State = cmacme.Ready
 
@@ -137,6 +140,7 @@ dGVzdA==
 	testACMEOrderValid := &acmeapi.Order{}
 	*testACMEOrderValid = *testACMEOrderPending
 	testACMEOrderValid.Status = acmeapi.StatusValid
+	testACMEOrderValid.CertURL = testOrderValid.Status.CertificateURL
 	// shallow copy
 	testACMEOrderReady := &acmeapi.Order{}
 	*testACMEOrderReady = *testACMEOrderPending
@@ -387,7 +391,7 @@ dGVzdA==
 				},
 				FakeCreateOrderCert: func(_ context.Context, url string, csr []byte, bundle bool) ([][]byte, string, error) {
 					testData := []byte("test")
-					return [][]byte{testData}, "http://testurl", nil
+					return [][]byte{testData}, testOrderValid.Status.CertificateURL, nil
 				},
 				FakeHTTP01ChallengeResponse: func(s string) (string, error) {
 					// TODO: assert s = "token"
@@ -438,6 +442,28 @@ dGVzdA==
 			},
 			acmeClient: &acmecl.FakeACME{},
 		},
+		"fetch the certificate again from the certificate URL if the order is valid but has no certificate": {
+			order: testOrderValidWithoutCertificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuerHTTP01TestCom, testOrderValidWithoutCertificate},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+						"status",
+						testOrderValid.Namespace, testOrderValid)),
+				},
+				ExpectedEvents: []string{
+					"Normal Complete Order completed successfully",
+				},
+			},
+			acmeClient: &acmecl.FakeACME{
+				FakeFetchCert: func(_ context.Context, url string, bundle bool) ([][]byte, error) {
+					if url != testOrderValid.Status.CertificateURL {
+						t.Errorf("expected certificate to be fetched from %q but got %q", testOrderValid.Status.CertificateURL, url)
+					}
+					return [][]byte{[]byte("test")}, nil
+				},
+			},
+		},
 		"do nothing if the order is failed": {
 			order: testOrderInvalid,
 			builder: &testpkg.Builder{
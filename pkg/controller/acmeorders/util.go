@@ -69,10 +69,19 @@ func buildChallenge(ctx context.Context, cl acmecl.Interface, issuer cmapi.Gener
 		return nil, err
 	}
 
+	// Challenges inherit the event verbosity of the Order, so that no events
+	// are recorded for them if the Certificate has been made quiet.
+	var annotations map[string]string
+	if verbosity, ok := o.Annotations[cmapi.EventVerbosityAnnotationKey]; ok {
+		annotations = map[string]string{cmapi.EventVerbosityAnnotationKey: verbosity}
+	}
+
 	return &cmacme.Challenge{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            chName,
 			Namespace:       o.Namespace,
+			Labels:          o.Labels,
+			Annotations:     annotations,
 			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(o, orderGvk)},
 			Finalizers:      []string{cmacme.ACMEFinalizer},
 		},
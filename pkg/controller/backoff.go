@@ -0,0 +1,344 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+// maxPersistedBackoffItems is the maximum number of items whose backoff is
+// persisted per controller, to keep the ConfigMap well below the size limit
+// of Kubernetes objects. The items retried last are dropped first.
+const maxPersistedBackoffItems = 2000
+
+// ItemBackoff is the persisted rate limiting backoff of a work queue item.
+type ItemBackoff struct {
+	// Failures is the number of times processing the item has failed
+	Failures int `json:"failures"`
+	// NotBefore is the time the item is retried after
+	NotBefore time.Time `json:"notBefore"`
+}
+
+// BackoffPersister persists the rate limiting backoff of the items of the
+// work queues of controllers in a ConfigMap, so that a restarted controller
+// continues backing off items that were failing before it restarted,
+// instead of retrying them straight away with the initial backoff.
+// The ConfigMap contains one key per controller, with the backoff of the
+// failing items of that controller encoded as JSON.
+type BackoffPersister struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+	clock     clock.Clock
+
+	lock sync.Mutex
+	// loaded is the backoff read from the ConfigMap, by controller name
+	loaded map[string]map[string]ItemBackoff
+	// limiters are the rate limiters of the queues of controllers, by
+	// controller name
+	limiters map[string]*persistentRateLimiter
+}
+
+// NewBackoffPersister returns a BackoffPersister storing backoff in the
+// ConfigMap with the given namespace and name.
+func NewBackoffPersister(client kubernetes.Interface, namespace, name string, clock clock.Clock) *BackoffPersister {
+	return &BackoffPersister{
+		client:    client,
+		namespace: namespace,
+		name:      name,
+		clock:     clock,
+		loaded:    make(map[string]map[string]ItemBackoff),
+		limiters:  make(map[string]*persistentRateLimiter),
+	}
+}
+
+// Load reads the persisted backoff from the ConfigMap. It must be called
+// before the queues of controllers are created for their backoff to be
+// restored. A missing ConfigMap is not an error.
+func (p *BackoffPersister) Load(ctx context.Context) error {
+	cm, err := p.client.CoreV1().ConfigMaps(p.namespace).Get(ctx, p.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error getting ConfigMap %s/%s: %v", p.namespace, p.name, err)
+	}
+
+	loaded := make(map[string]map[string]ItemBackoff)
+	for controllerName, data := range cm.Data {
+		var items map[string]ItemBackoff
+		if err := json.Unmarshal([]byte(data), &items); err != nil {
+			return fmt.Errorf("error decoding backoff of controller %q: %v", controllerName, err)
+		}
+		loaded[controllerName] = items
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.loaded = loaded
+	return nil
+}
+
+// NewRateLimitingQueue returns a named rate limiting queue using limiter. If
+// p is not nil, the backoff of items is persisted by p and the backoff of
+// the controller with the given name is restored.
+func NewRateLimitingQueue(p *BackoffPersister, limiter workqueue.RateLimiter, name string) workqueue.RateLimitingInterface {
+	if p == nil {
+		return workqueue.NewNamedRateLimitingQueue(limiter, name)
+	}
+
+	l := newPersistentRateLimiter(limiter, p.clock)
+	p.lock.Lock()
+	l.restore(p.loaded[name])
+	p.limiters[name] = l
+	p.lock.Unlock()
+
+	return &persistentQueue{
+		RateLimitingInterface: workqueue.NewNamedRateLimitingQueue(l, name),
+		limiter:               l,
+	}
+}
+
+// Run periodically persists the backoff of all queues until ctx is done,
+// and once more before returning.
+func (p *BackoffPersister) Run(ctx context.Context, period time.Duration) {
+	log := logf.FromContext(ctx, "backoff-persister")
+	flush := func() {
+		if err := p.Flush(ctx); err != nil {
+			log.Error(err, "error persisting work queue backoff")
+		}
+	}
+	wait.Until(flush, period, ctx.Done())
+	// use a new context, as ctx is done already
+	if err := p.Flush(context.Background()); err != nil {
+		log.Error(err, "error persisting work queue backoff")
+	}
+}
+
+// Flush writes the backoff of all queues to the ConfigMap, if it has
+// changed since the last Flush.
+func (p *BackoffPersister) Flush(ctx context.Context) error {
+	p.lock.Lock()
+	data := make(map[string]string)
+	changed := false
+	for controllerName, l := range p.limiters {
+		items, dirty := l.snapshot()
+		changed = changed || dirty
+		if len(items) == 0 {
+			continue
+		}
+		b, err := json.Marshal(items)
+		if err != nil {
+			p.lock.Unlock()
+			return err
+		}
+		data[controllerName] = string(b)
+	}
+	p.lock.Unlock()
+
+	if !changed {
+		return nil
+	}
+
+	cm, err := p.client.CoreV1().ConfigMaps(p.namespace).Get(ctx, p.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = p.client.CoreV1().ConfigMaps(p.namespace).Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: p.namespace, Name: p.name},
+			Data:       data,
+		}, metav1.CreateOptions{})
+	} else if err == nil {
+		cm = cm.DeepCopy()
+		cm.Data = data
+		_, err = p.client.CoreV1().ConfigMaps(p.namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		// persist the backoff again on the next Flush
+		p.markDirty()
+		return fmt.Errorf("error writing ConfigMap %s/%s: %v", p.namespace, p.name, err)
+	}
+	return nil
+}
+
+func (p *BackoffPersister) markDirty() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, l := range p.limiters {
+		l.markDirty()
+	}
+}
+
+// persistentRateLimiter records the backoff of the items of a rate limiter,
+// so that it can be persisted and restored.
+type persistentRateLimiter struct {
+	workqueue.RateLimiter
+	clock clock.Clock
+
+	lock  sync.Mutex
+	items map[string]ItemBackoff
+	// restored is the time restored items are retried after, until they
+	// have been added to the queue once
+	restored map[string]time.Time
+	dirty    bool
+}
+
+var _ workqueue.RateLimiter = &persistentRateLimiter{}
+
+func newPersistentRateLimiter(limiter workqueue.RateLimiter, clock clock.Clock) *persistentRateLimiter {
+	return &persistentRateLimiter{
+		RateLimiter: limiter,
+		clock:       clock,
+		items:       make(map[string]ItemBackoff),
+		restored:    make(map[string]time.Time),
+	}
+}
+
+func (l *persistentRateLimiter) When(item interface{}) time.Duration {
+	delay := l.RateLimiter.When(item)
+	key, ok := item.(string)
+	if !ok {
+		return delay
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.items[key] = ItemBackoff{
+		Failures:  l.RateLimiter.NumRequeues(item),
+		NotBefore: l.clock.Now().Add(delay),
+	}
+	delete(l.restored, key)
+	l.dirty = true
+	return delay
+}
+
+func (l *persistentRateLimiter) Forget(item interface{}) {
+	l.RateLimiter.Forget(item)
+	key, ok := item.(string)
+	if !ok {
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if _, ok := l.items[key]; ok {
+		delete(l.items, key)
+		l.dirty = true
+	}
+	delete(l.restored, key)
+}
+
+// restore restores the backoff of items. The number of failures of each
+// item is replayed on the underlying rate limiter, so that the next
+// failure of the item continues the backoff where it left off.
+func (l *persistentRateLimiter) restore(items map[string]ItemBackoff) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	now := l.clock.Now()
+	for key, backoff := range items {
+		for i := 0; i < backoff.Failures; i++ {
+			l.RateLimiter.When(key)
+		}
+		l.items[key] = backoff
+		if backoff.NotBefore.After(now) {
+			l.restored[key] = backoff.NotBefore
+		}
+	}
+}
+
+// restoredDelay returns the remaining backoff of a restored item, or zero if
+// the item has not been restored or its backoff has elapsed. The backoff of
+// a restored item is only returned once.
+func (l *persistentRateLimiter) restoredDelay(item interface{}) time.Duration {
+	key, ok := item.(string)
+	if !ok {
+		return 0
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	notBefore, ok := l.restored[key]
+	if !ok {
+		return 0
+	}
+	delete(l.restored, key)
+	return notBefore.Sub(l.clock.Now())
+}
+
+// snapshot returns the backoff of the items retried first, and whether it
+// has changed since the last snapshot.
+func (l *persistentRateLimiter) snapshot() (map[string]ItemBackoff, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	dirty := l.dirty
+	l.dirty = false
+
+	if len(l.items) <= maxPersistedBackoffItems {
+		items := make(map[string]ItemBackoff, len(l.items))
+		for key, backoff := range l.items {
+			items[key] = backoff
+		}
+		return items, dirty
+	}
+
+	keys := make([]string, 0, len(l.items))
+	for key := range l.items {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return l.items[keys[i]].NotBefore.Before(l.items[keys[j]].NotBefore)
+	})
+	items := make(map[string]ItemBackoff, maxPersistedBackoffItems)
+	for _, key := range keys[:maxPersistedBackoffItems] {
+		items[key] = l.items[key]
+	}
+	return items, dirty
+}
+
+func (l *persistentRateLimiter) markDirty() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.dirty = true
+}
+
+// persistentQueue delays adding restored items to the queue until their
+// restored backoff has elapsed.
+type persistentQueue struct {
+	workqueue.RateLimitingInterface
+	limiter *persistentRateLimiter
+}
+
+func (q *persistentQueue) Add(item interface{}) {
+	if delay := q.limiter.restoredDelay(item); delay > 0 {
+		q.RateLimitingInterface.AddAfter(item, delay)
+		return
+	}
+	q.RateLimitingInterface.Add(item)
+}
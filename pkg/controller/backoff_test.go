@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/workqueue"
+	fakeclock "k8s.io/utils/clock/testing"
+)
+
+func TestPersistentRateLimiter(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	clock := fakeclock.NewFakeClock(now)
+	l := newPersistentRateLimiter(workqueue.NewItemExponentialFailureRateLimiter(time.Second, time.Minute), clock)
+
+	l.When("ns/a")
+	if delay := l.When("ns/a"); delay != 2*time.Second {
+		t.Errorf("expected delay of 2s, got %s", delay)
+	}
+	l.When("ns/b")
+	l.When(42)
+
+	items, dirty := l.snapshot()
+	exp := map[string]ItemBackoff{
+		"ns/a": {Failures: 2, NotBefore: now.Add(2 * time.Second)},
+		"ns/b": {Failures: 1, NotBefore: now.Add(time.Second)},
+	}
+	if !dirty || !reflect.DeepEqual(items, exp) {
+		t.Errorf("expected dirty snapshot %v, got %v (dirty=%t)", exp, items, dirty)
+	}
+	if _, dirty := l.snapshot(); dirty {
+		t.Error("expected snapshot not to be dirty if nothing changed")
+	}
+
+	l.Forget("ns/b")
+	items, dirty = l.snapshot()
+	delete(exp, "ns/b")
+	if !dirty || !reflect.DeepEqual(items, exp) {
+		t.Errorf("expected dirty snapshot %v after Forget, got %v (dirty=%t)", exp, items, dirty)
+	}
+}
+
+func TestPersistentRateLimiterRestore(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	clock := fakeclock.NewFakeClock(now)
+	l := newPersistentRateLimiter(workqueue.NewItemExponentialFailureRateLimiter(time.Second, time.Minute), clock)
+
+	l.restore(map[string]ItemBackoff{
+		"ns/pending": {Failures: 3, NotBefore: now.Add(4 * time.Second)},
+		"ns/elapsed": {Failures: 1, NotBefore: now.Add(-time.Second)},
+	})
+
+	if n := l.NumRequeues("ns/pending"); n != 3 {
+		t.Errorf("expected 3 restored failures, got %d", n)
+	}
+	if delay := l.restoredDelay("ns/pending"); delay != 4*time.Second {
+		t.Errorf("expected restored delay of 4s, got %s", delay)
+	}
+	if delay := l.restoredDelay("ns/pending"); delay != 0 {
+		t.Errorf("expected restored delay to only be returned once, got %s", delay)
+	}
+	if delay := l.restoredDelay("ns/elapsed"); delay != 0 {
+		t.Errorf("expected no restored delay for elapsed backoff, got %s", delay)
+	}
+	if delay := l.When("ns/pending"); delay != 8*time.Second {
+		t.Errorf("expected backoff to continue with a delay of 8s, got %s", delay)
+	}
+}
+
+func TestBackoffPersister(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	clock := fakeclock.NewFakeClock(now)
+	cl := fake.NewSimpleClientset()
+	ctx := context.Background()
+
+	p := NewBackoffPersister(cl, "kube-system", "backoff", clock)
+	if err := p.Load(ctx); err != nil {
+		t.Fatalf("expected a missing ConfigMap not to be an error, got: %v", err)
+	}
+	queue := NewRateLimitingQueue(p, workqueue.NewItemExponentialFailureRateLimiter(time.Second, time.Minute), "test")
+	defer queue.ShutDown()
+	queue.AddRateLimited("ns/a")
+	queue.AddRateLimited("ns/a")
+
+	if err := p.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cm, err := cl.CoreV1().ConfigMaps("kube-system").Get(ctx, "backoff", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected the ConfigMap to be created, got: %v", err)
+	}
+	if _, ok := cm.Data["test"]; !ok {
+		t.Errorf("expected the backoff of controller 'test' to be persisted, got: %v", cm.Data)
+	}
+
+	// simulate a restart
+	restarted := NewBackoffPersister(cl, "kube-system", "backoff", clock)
+	if err := restarted.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	restartedQueue := NewRateLimitingQueue(restarted, workqueue.NewItemExponentialFailureRateLimiter(time.Second, time.Minute), "test")
+	defer restartedQueue.ShutDown()
+	if n := restartedQueue.NumRequeues("ns/a"); n != 2 {
+		t.Errorf("expected 2 restored failures, got %d", n)
+	}
+	restartedQueue.Add("ns/a")
+	if l := restartedQueue.Len(); l != 0 {
+		t.Errorf("expected the restored item not to be queued before its backoff elapsed, got queue length %d", l)
+	}
+
+	restartedQueue.Forget("ns/a")
+	if err := restarted.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cm, err = cl.CoreV1().ConfigMaps("kube-system").Get(ctx, "backoff", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cm.Data) != 0 {
+		t.Errorf("expected forgotten items not to be persisted, got: %v", cm.Data)
+	}
+}
@@ -31,15 +31,19 @@ import (
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
 	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 	cmacmeclientset "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/typed/acme/v1alpha2"
 	cmacmelisters "github.com/jetstack/cert-manager/pkg/client/listers/acme/v1alpha2"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests"
 	crutil "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/util"
+	"github.com/jetstack/cert-manager/pkg/feature"
 	issuerpkg "github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/pkg/util/apply"
 	"github.com/jetstack/cert-manager/pkg/util/errors"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
@@ -53,9 +57,20 @@ type ACME struct {
 	issuerOptions controllerpkg.IssuerOptions
 
 	orderLister cmacmelisters.OrderLister
+	cmClient    cmclient.Interface
 	acmeClientV cmacmeclientset.AcmeV1alpha2Interface
 
 	reporter *crutil.Reporter
+
+	// fieldManager is the field manager used to apply the status of Orders
+	// if the ServerSideApply feature is enabled
+	fieldManager string
+}
+
+// orderStatusFields are the status fields of Orders that are reset by this
+// controller.
+var orderStatusFields = apply.Fields{
+	Status: []string{"certificate"},
 }
 
 func init() {
@@ -75,8 +90,10 @@ func NewACME(ctx *controllerpkg.Context) *ACME {
 		recorder:      ctx.Recorder,
 		issuerOptions: ctx.IssuerOptions,
 		orderLister:   ctx.SharedInformerFactory.Acme().V1alpha2().Orders().Lister(),
+		cmClient:      ctx.CMClient,
 		acmeClientV:   ctx.CMClient.AcmeV1alpha2(),
 		reporter:      crutil.NewReporter(ctx.Clock, ctx.Recorder),
+		fieldManager:  ctx.FieldManagerFor(CRControllerName),
 	}
 }
 
@@ -95,7 +112,9 @@ func (a *ACME) Sign(ctx context.Context, cr *v1alpha2.CertificateRequest, issuer
 	}
 
 	// If the CommonName is also not present in the DNS names of the CSR then hard fail.
-	if len(csr.Subject.CommonName) > 0 && !util.Contains(csr.DNSNames, csr.Subject.CommonName) {
+	// The names are compared in their canonical form, as the DNS names of CSRs
+	// built by cert-manager are canonicalized but the CommonName is not.
+	if len(csr.Subject.CommonName) > 0 && !util.Contains(pki.CanonicalDNSNames(csr.DNSNames), pki.CanonicalDNSName(csr.Subject.CommonName)) {
 		err = fmt.Errorf("%q does not exist in %s", csr.Subject.CommonName, csr.DNSNames)
 		message := "The CSR PEM requests a commonName that is not present in the list of dnsNames. If a commonName is set, ACME requires that the value is also present in the list of dnsNames"
 
@@ -106,6 +125,18 @@ func (a *ACME) Sign(ctx context.Context, cr *v1alpha2.CertificateRequest, issuer
 		return nil, nil
 	}
 
+	// Fail early with a clear message rather than have the ACME server reject
+	// the order.
+	if err := pki.ValidateACMEIdentifierCount(csr.Subject.CommonName, csr.DNSNames); err != nil {
+		message := "The CSR PEM requests more names than ACME servers accept in a single order"
+
+		a.reporter.Failed(cr, err, "TooManyNames", message)
+
+		log.V(4).Info(fmt.Sprintf("%s: %s", message, err))
+
+		return nil, nil
+	}
+
 	// If we fail to build the order we have to hard fail.
 	expectedOrder, err := buildOrder(cr, csr)
 	if err != nil {
@@ -171,6 +202,20 @@ func (a *ACME) Sign(ctx context.Context, cr *v1alpha2.CertificateRequest, issuer
 	if order.Status.State == cmacme.Valid {
 		x509Cert, err := pki.DecodeX509CertificateBytes(order.Status.Certificate)
 		if errors.IsInvalidData(err) {
+			// the certificate is downloaded again from the ACME server if its
+			// URL is known, instead of creating a new order
+			if order.Status.CertificateURL != "" {
+				log.Error(err, "failed to decode x509 certificate data on Order resource, fetching certificate again")
+				existing := order
+				order = order.DeepCopy()
+				order.Status.Certificate = nil
+				if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+					_, err := apply.OrderStatus(context.TODO(), a.cmClient, a.fieldManager, orderStatusFields, existing, order)
+					return nil, err
+				}
+				_, err := a.acmeClientV.Orders(order.Namespace).UpdateStatus(context.TODO(), order, metav1.UpdateOptions{})
+				return nil, err
+			}
 			log.Error(err, "failed to decode x509 certificate data on Order resource")
 			return nil, a.acmeClientV.Orders(order.Namespace).Delete(context.TODO(), order.Name, metav1.DeleteOptions{})
 		}
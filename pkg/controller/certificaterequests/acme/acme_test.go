@@ -376,6 +376,27 @@ func TestSign(t *testing.T) {
 				},
 			},
 		},
+		"if the certificate of a Valid order is invalid and its certificate URL is known then clear it to fetch it again": {
+			certificateRequest: baseCR.DeepCopy(),
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{gen.OrderFrom(baseOrder,
+					gen.SetOrderState(cmacme.Valid),
+					gen.SetOrderCertificate([]byte("invalid")),
+					gen.SetOrderCertificateURL("http://testurl.com/cert"),
+				), baseCR.DeepCopy(), baseIssuer.DeepCopy()},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmacme.SchemeGroupVersion.WithResource("orders"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.OrderFrom(baseOrder,
+							gen.SetOrderState(cmacme.Valid),
+							gen.SetOrderCertificateURL("http://testurl.com/cert"),
+						),
+					)),
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
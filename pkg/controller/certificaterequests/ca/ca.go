@@ -30,6 +30,7 @@ import (
 	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests"
 	crutil "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/util"
 	issuerpkg "github.com/jetstack/cert-manager/pkg/issuer"
+	"github.com/jetstack/cert-manager/pkg/issuer/requestdefaults"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	cmerrors "github.com/jetstack/cert-manager/pkg/util/errors"
 	"github.com/jetstack/cert-manager/pkg/util/kube"
@@ -45,8 +46,7 @@ type templateGenerator func(*cmapi.CertificateRequest) (*x509.Certificate, error
 type CA struct {
 	issuerOptions controllerpkg.IssuerOptions
 	secretsLister corelisters.SecretLister
-
-	reporter *crutil.Reporter
+	reporter      *crutil.Reporter
 
 	// Used for testing to get reproducible resulting certificates
 	templateGenerator templateGenerator
@@ -111,6 +111,14 @@ func (c *CA) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerObj c
 		return nil, nil
 	}
 
+	values := requestdefaults.ValuesForRequest(cr, issuerObj, c.issuerOptions.ClusterDomain, c.issuerOptions.TrustPodServiceAccountAnnotation)
+	if err := requestdefaults.Apply(template, issuerObj.GetSpec().RequestDefaults, values); err != nil {
+		message := "Error applying the request defaults of the issuer"
+		c.reporter.Failed(cr, err, "RequestDefaultsError", message)
+		log.Error(err, message)
+		return nil, nil
+	}
+
 	template.CRLDistributionPoints = issuerObj.GetSpec().CA.CRLDistributionPoints
 
 	certPEM, caPEM, err := pki.SignCSRTemplate(caCerts, caKey, template)
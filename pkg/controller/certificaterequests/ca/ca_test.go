@@ -42,6 +42,7 @@ import (
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/issuer/requestdefaults"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/test/unit/gen"
 	testlisters "github.com/jetstack/cert-manager/test/unit/listers"
@@ -152,6 +153,20 @@ func TestSign(t *testing.T) {
 		t.FailNow()
 	}
 
+	// an issuer whose request defaults need the service account of the
+	// Pod, which is not trusted by default
+	serviceAccountIssuer := gen.IssuerFrom(baseIssuer,
+		gen.SetIssuerRequestDefaults(cmapi.RequestDefaults{
+			URISANs: []string{"spiffe://cluster.local/ns/{{ namespace }}/sa/{{ serviceAccount }}"},
+		}),
+	)
+	_, renderErr := requestdefaults.Render(serviceAccountIssuer.Spec.RequestDefaults.URISANs[0],
+		requestdefaults.ValuesForRequest(baseCR, serviceAccountIssuer, "cluster.local", false))
+	if renderErr == nil {
+		t.Fatal("expected rendering the request defaults to fail")
+	}
+	requestDefaultsMessage := "Error applying the request defaults of the issuer: rendering uriSANs[0]: " + renderErr.Error()
+
 	metaFixedClockStart := metav1.NewTime(fixedClockStart)
 	tests := map[string]testT{
 		"a missing CA key pair should set the condition to pending and wait for a re-sync": {
@@ -305,6 +320,33 @@ func TestSign(t *testing.T) {
 				},
 			},
 		},
+		"request defaults that cannot be rendered should set condition to failed": {
+			certificateRequest: baseCR.DeepCopy(),
+			builder: &testpkg.Builder{
+				KubeObjects:        []runtime.Object{rsaCASecret},
+				CertManagerObjects: []runtime.Object{baseCR.DeepCopy(), serviceAccountIssuer},
+				ExpectedEvents: []string{
+					"Warning RequestDefaultsError " + requestDefaultsMessage,
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR.DeepCopy(),
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             cmapi.CertificateRequestReasonFailed,
+								Message:            requestDefaultsMessage,
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+							gen.SetCertificateRequestFailureTime(metaFixedClockStart),
+						),
+					)),
+				},
+			},
+		},
 		"a successful signing should set condition to Ready": {
 			certificateRequest: baseCR.DeepCopy(),
 			templateGenerator: func(cr *cmapi.CertificateRequest) (*x509.Certificate, error) {
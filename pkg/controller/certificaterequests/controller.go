@@ -33,6 +33,7 @@ import (
 	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests/util"
 	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
 )
 
 const (
@@ -79,6 +80,18 @@ type Controller struct {
 	clock clock.Clock
 
 	reporter *util.Reporter
+
+	// metrics is used to account the calls made to the issuer and the
+	// certificates issued
+	metrics *metrics.Metrics
+
+	// limiter limits the number of CertificateRequests in flight for issuers
+	// that declare a maximum number of concurrent requests
+	limiter *issuerLimiter
+
+	// fieldManager is the field manager used to apply the status of
+	// CertificateRequests if the ServerSideApply feature is enabled
+	fieldManager string
 }
 
 // New will construct a new certificaterequest controller using the given
@@ -96,6 +109,7 @@ func New(issuerType string, issuer Issuer, extraInformers ...cache.SharedIndexIn
 		issuerType:     issuerType,
 		issuer:         issuer,
 		extraInformers: extraInformers,
+		limiter:        newIssuerLimiter(),
 	}
 }
 
@@ -107,7 +121,7 @@ func (c *Controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.log = logf.FromContext(ctx.RootContext, ControllerName)
 
 	// create a queue used to queue up items to be processed
-	c.queue = workqueue.NewNamedRateLimitingQueue(controllerpkg.DefaultItemBasedRateLimiter(), ControllerName)
+	c.queue = controllerpkg.NewRateLimitingQueue(ctx.BackoffPersister, controllerpkg.DefaultItemBasedRateLimiter(), ControllerName+"-"+c.issuerType)
 
 	issuerInformer := ctx.SharedInformerFactory.Certmanager().V1alpha2().Issuers()
 	c.issuerLister = issuerInformer.Lister()
@@ -163,6 +177,8 @@ func (c *Controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.recorder = ctx.Recorder
 	c.reporter = util.NewReporter(c.clock, c.recorder)
 	c.cmClient = ctx.CMClient
+	c.fieldManager = ctx.FieldManagerFor(ControllerName + "-" + c.issuerType)
+	c.metrics = ctx.Metrics
 
 	c.log.Info("new certificate request controller registered",
 		"type", c.issuerType)
@@ -182,6 +198,7 @@ func (c *Controller) ProcessItem(ctx context.Context, key string) error {
 	if err != nil {
 		if k8sErrors.IsNotFound(err) {
 			log.Error(err, "certificate request in work queue no longer exists")
+			c.releaseIssuerSlot(key)
 			return nil
 		}
 
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificaterequests
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+)
+
+// issuerLimiter limits the number of CertificateRequests that are in flight
+// for each issuer, i.e. that have been passed to the issuer to be signed but
+// are not yet issued or failed.
+// CertificateRequests that would exceed the limit of their issuer are
+// recorded as waiting until a slot is released.
+type issuerLimiter struct {
+	lock sync.Mutex
+	// inFlight and waiting are the keys of the CertificateRequests that are
+	// in flight and waiting for each issuer, keyed by keyForIssuer
+	inFlight map[string]sets.String
+	waiting  map[string]sets.String
+}
+
+func newIssuerLimiter() *issuerLimiter {
+	return &issuerLimiter{
+		inFlight: make(map[string]sets.String),
+		waiting:  make(map[string]sets.String),
+	}
+}
+
+// acquire returns true if the CertificateRequest with the given key may be
+// passed to the issuer, i.e. it is already in flight or the issuer has fewer
+// than limit CertificateRequests in flight. Otherwise the CertificateRequest
+// is recorded as waiting and false is returned.
+// A limit of zero or less means the issuer is not limited.
+func (l *issuerLimiter) acquire(issuer, key string, limit int) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	inFlight := l.inFlight[issuer]
+	if inFlight == nil {
+		inFlight = sets.NewString()
+		l.inFlight[issuer] = inFlight
+	}
+	if inFlight.Has(key) {
+		return true
+	}
+	if inFlight.Len() < limit {
+		inFlight.Insert(key)
+		l.removeWaiting(issuer, key)
+		return true
+	}
+
+	if l.waiting[issuer] == nil {
+		l.waiting[issuer] = sets.NewString()
+	}
+	l.waiting[issuer].Insert(key)
+	return false
+}
+
+// release removes the CertificateRequest with the given key from the
+// in-flight and waiting CertificateRequests of all issuers. If a slot was
+// released, it returns the issuer and the keys of the CertificateRequests
+// waiting for it, so that they can be retried.
+func (l *issuerLimiter) release(key string) (string, []string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for issuer := range l.waiting {
+		l.removeWaiting(issuer, key)
+	}
+
+	for issuer, inFlight := range l.inFlight {
+		if !inFlight.Has(key) {
+			continue
+		}
+		inFlight.Delete(key)
+		if inFlight.Len() == 0 {
+			delete(l.inFlight, issuer)
+		}
+		return issuer, l.waiting[issuer].List()
+	}
+
+	return "", nil
+}
+
+// waitingCount returns the number of CertificateRequests waiting for a slot
+// of the issuer.
+func (l *issuerLimiter) waitingCount(issuer string) int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.waiting[issuer].Len()
+}
+
+func (l *issuerLimiter) removeWaiting(issuer, key string) {
+	waiting := l.waiting[issuer]
+	if waiting == nil {
+		return
+	}
+	waiting.Delete(key)
+	if waiting.Len() == 0 {
+		delete(l.waiting, issuer)
+	}
+}
+
+// keyForIssuer returns the key the in-flight CertificateRequests of the given
+// issuer are tracked by.
+func keyForIssuer(issuer v1alpha2.GenericIssuer) string {
+	return issuer.GetObjectMeta().Namespace + "/" + issuer.GetObjectMeta().Name + "/" + issuerKind(issuer)
+}
+
+func issuerKind(issuer v1alpha2.GenericIssuer) string {
+	if _, ok := issuer.(*v1alpha2.ClusterIssuer); ok {
+		return v1alpha2.ClusterIssuerKind
+	}
+	return v1alpha2.IssuerKind
+}
+
+// maxConcurrentRequests returns the maximum number of CertificateRequests the
+// issuer allows to be in flight at once, or zero if it is not limited.
+func maxConcurrentRequests(issuer v1alpha2.GenericIssuer) (int, error) {
+	value, ok := issuer.GetObjectMeta().Annotations[v1alpha2.IssuerMaxConcurrentRequestsAnnotationKey]
+	if !ok {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid value %q for annotation %q, must be a non-negative integer",
+			value, v1alpha2.IssuerMaxConcurrentRequestsAnnotationKey)
+	}
+	return limit, nil
+}
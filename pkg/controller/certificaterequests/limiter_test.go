@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificaterequests
+
+import (
+	"reflect"
+	"testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestIssuerLimiter(t *testing.T) {
+	l := newIssuerLimiter()
+
+	// issuers without a limit never block
+	for _, key := range []string{"ns/a", "ns/b", "ns/c"} {
+		if !l.acquire("unlimited", key, 0) {
+			t.Errorf("expected %s to be admitted for an issuer without a limit", key)
+		}
+	}
+
+	if !l.acquire("tpp", "ns/a", 2) || !l.acquire("tpp", "ns/b", 2) {
+		t.Fatal("expected the first two requests to be admitted")
+	}
+	if !l.acquire("tpp", "ns/a", 2) {
+		t.Error("expected a request in flight to be admitted again")
+	}
+	if l.acquire("tpp", "ns/c", 2) || l.acquire("tpp", "ns/d", 2) {
+		t.Fatal("expected requests over the limit to wait")
+	}
+	if n := l.waitingCount("tpp"); n != 2 {
+		t.Errorf("expected 2 requests waiting, got: %d", n)
+	}
+
+	// releasing a waiting request does not release a slot
+	if issuer, waiting := l.release("ns/d"); issuer != "" || waiting != nil {
+		t.Errorf("expected no slot to be released, got: %s %v", issuer, waiting)
+	}
+
+	issuer, waiting := l.release("ns/a")
+	if issuer != "tpp" || !reflect.DeepEqual(waiting, []string{"ns/c"}) {
+		t.Errorf("expected the slot of tpp to be released for [ns/c], got: %s %v", issuer, waiting)
+	}
+	if !l.acquire("tpp", "ns/c", 2) {
+		t.Error("expected the waiting request to be admitted after a slot was released")
+	}
+	if n := l.waitingCount("tpp"); n != 0 {
+		t.Errorf("expected no requests waiting, got: %d", n)
+	}
+}
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		expLimit    int
+		expErr      bool
+	}{
+		"no annotation": {
+			expLimit: 0,
+		},
+		"valid limit": {
+			annotations: map[string]string{cmapi.IssuerMaxConcurrentRequestsAnnotationKey: "10"},
+			expLimit:    10,
+		},
+		"negative limit": {
+			annotations: map[string]string{cmapi.IssuerMaxConcurrentRequestsAnnotationKey: "-1"},
+			expErr:      true,
+		},
+		"not a number": {
+			annotations: map[string]string{cmapi.IssuerMaxConcurrentRequestsAnnotationKey: "ten"},
+			expErr:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			issuer := gen.Issuer("test")
+			issuer.Annotations = test.annotations
+			limit, err := maxConcurrentRequests(issuer)
+			if (err != nil) != test.expErr {
+				t.Fatalf("expected error: %t, got: %v", test.expErr, err)
+			}
+			if limit != test.expLimit {
+				t.Errorf("expected limit %d, got: %d", test.expLimit, limit)
+			}
+		})
+	}
+}
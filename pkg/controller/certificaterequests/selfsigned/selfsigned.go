@@ -32,6 +32,7 @@ import (
 	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests"
 	crutil "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/util"
 	"github.com/jetstack/cert-manager/pkg/issuer"
+	"github.com/jetstack/cert-manager/pkg/issuer/requestdefaults"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	cmerrors "github.com/jetstack/cert-manager/pkg/util/errors"
 	"github.com/jetstack/cert-manager/pkg/util/kube"
@@ -47,8 +48,7 @@ type signingFn func(*x509.Certificate, *x509.Certificate, crypto.PublicKey, inte
 type SelfSigned struct {
 	issuerOptions controllerpkg.IssuerOptions
 	secretsLister corelisters.SecretLister
-
-	reporter *crutil.Reporter
+	reporter      *crutil.Reporter
 
 	// Used for testing to get reproducible resulting certificates
 	signingFn signingFn
@@ -125,6 +125,14 @@ func (s *SelfSigned) Sign(ctx context.Context, cr *cmapi.CertificateRequest, iss
 		return nil, nil
 	}
 
+	values := requestdefaults.ValuesForRequest(cr, issuerObj, s.issuerOptions.ClusterDomain, s.issuerOptions.TrustPodServiceAccountAnnotation)
+	if err := requestdefaults.Apply(template, issuerObj.GetSpec().RequestDefaults, values); err != nil {
+		message := "Error applying the request defaults of the issuer"
+		s.reporter.Failed(cr, err, "RequestDefaultsError", message)
+		log.Error(err, message)
+		return nil, nil
+	}
+
 	template.CRLDistributionPoints = issuerObj.GetSpec().SelfSigned.CRLDistributionPoints
 
 	// extract the public component of the key
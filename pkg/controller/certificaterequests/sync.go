@@ -21,6 +21,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/kr/pretty"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
@@ -31,16 +33,37 @@ import (
 	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
 	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/feature"
 	internalapi "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
+	"github.com/jetstack/cert-manager/pkg/util/apply"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/pkg/webhook"
 )
 
 var (
+	// statusFields are the status fields and conditions of
+	// CertificateRequests that are managed by the issuer controllers. The
+	// Approved and Denied conditions are set by approvers.
+	statusFields = apply.Fields{
+		Status: []string{"certificate", "ca", "failureTime"},
+		Conditions: []string{
+			string(v1alpha2.CertificateRequestConditionReady),
+			string(v1alpha2.CertificateRequestConditionInvalidRequest),
+		},
+	}
+
 	certificateRequestGvk = v1alpha2.SchemeGroupVersion.WithKind(v1alpha2.CertificateRequestKind)
 )
 
+const (
+	// concurrencyLimitRecheckPeriod is the time after which a
+	// CertificateRequest waiting for a slot of its issuer is retried
+	concurrencyLimitRecheckPeriod = time.Minute
+)
+
 func (c *Controller) Sync(ctx context.Context, cr *v1alpha2.CertificateRequest) (err error) {
 	log := logf.FromContext(ctx)
 	dbg := log.V(logf.DebugLevel)
@@ -50,13 +73,31 @@ func (c *Controller) Sync(ctx context.Context, cr *v1alpha2.CertificateRequest)
 		return nil
 	}
 
+	if apiutil.IsPaused(cr) {
+		dbg.Info("certificate request is paused so skipping processing")
+		return nil
+	}
+
+	if apiutil.IsExternalSigningRequest(cr) {
+		dbg.Info("certificate request is signed externally so skipping processing")
+		return nil
+	}
+
+	key, err := keyFunc(cr)
+	if err != nil {
+		log.Error(err, "failed to construct key for certificate request")
+		return nil
+	}
+
 	switch apiutil.CertificateRequestReadyReason(cr) {
 	case v1alpha2.CertificateRequestReasonFailed:
 		dbg.Info("certificate request Ready condition failed so skipping processing")
+		c.releaseIssuerSlot(key)
 		return
 
 	case v1alpha2.CertificateRequestReasonIssued:
 		dbg.Info("certificate request Ready condition true so skipping processing")
+		c.releaseIssuerSlot(key)
 		return
 	}
 
@@ -100,6 +141,20 @@ func (c *Controller) Sync(ctx context.Context, cr *v1alpha2.CertificateRequest)
 		return nil
 	}
 
+	if denied := apiutil.CertificateRequestDeniedCondition(crCopy); denied != nil {
+		dbg.Info("certificate request has been denied so marking it as failed")
+		c.reporter.Failed(crCopy, fmt.Errorf("%s: %s", denied.Reason, denied.Message), "Denied",
+			"The CertificateRequest was denied")
+		return nil
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(feature.CertificateRequestApproval) && !apiutil.CertificateRequestIsApproved(crCopy) {
+		dbg.Info("certificate request has not been approved yet so skipping processing")
+		c.reporter.Pending(crCopy, nil, "WaitingForApproval",
+			"Waiting for the CertificateRequest to be approved")
+		return nil
+	}
+
 	// check ready condition
 	if !apiutil.IssuerHasCondition(issuerObj, v1alpha2.IssuerCondition{
 		Type:   v1alpha2.IssuerConditionReady,
@@ -119,16 +174,53 @@ func (c *Controller) Sync(ctx context.Context, cr *v1alpha2.CertificateRequest)
 		return nil
 	}
 
+	if len(issuerObj.GetSpec().AllowedDNSSuffixes) > 0 {
+		dbg.Info("checking requested DNS names are allowed by the issuer")
+
+		dnsNames, err := pki.DNSNamesForCSR(crCopy.Spec.CSRPEM)
+		if err != nil {
+			c.reporter.Failed(crCopy, err, "BadConfig",
+				"Failed to decode CSR")
+			return nil
+		}
+		if notAllowed := apiutil.DNSNamesNotAllowedByIssuer(issuerObj.GetSpec(), dnsNames); len(notAllowed) > 0 {
+			c.reporter.Failed(crCopy, fmt.Errorf("%v", notAllowed), "DNSNameNotAllowed",
+				fmt.Sprintf("Referenced %q does not allow issuing certificates for DNS names", apiutil.IssuerKind(crCopy.Spec.IssuerRef)))
+			return nil
+		}
+	}
+
 	if len(crCopy.Status.Certificate) > 0 {
 		dbg.Info("certificate field is already set in status so skipping processing")
 		return nil
 	}
 
+	limit, err := maxConcurrentRequests(issuerObj)
+	if err != nil {
+		log.Error(err, "ignoring concurrency limit of issuer")
+	}
+	limiterKey := keyForIssuer(issuerObj)
+	acquired := c.limiter.acquire(limiterKey, key, limit)
+	if limit > 0 {
+		c.updateQueueDepth(limiterKey)
+	}
+	if !acquired {
+		dbg.Info("issuer has the maximum number of certificate requests in flight, waiting", "limit", limit)
+		c.reporter.Pending(crCopy, nil, "ConcurrencyLimited",
+			fmt.Sprintf("Waiting for one of the %d CertificateRequests in flight for the referenced %q to complete",
+				limit, apiutil.IssuerKind(crCopy.Spec.IssuerRef)))
+		// CertificateRequests waiting are requeued when a slot is released,
+		// this is a fallback in case that is missed.
+		c.queue.AddAfter(key, concurrencyLimitRecheckPeriod)
+		return nil
+	}
+
 	dbg.Info("invoking sign function as existing certificate does not exist")
 
 	// Attempt to call the Sign function on our issuer
 	resp, err := c.issuer.Sign(ctx, crCopy, issuerObj)
 	if err != nil {
+		c.metrics.IncrementSignCallCount(crCopy, issuerType, metrics.SignResultError)
 		log.Error(err, "error issuing certificate request")
 		return err
 	}
@@ -137,8 +229,10 @@ func (c *Controller) Sync(ctx context.Context, cr *v1alpha2.CertificateRequest)
 	// underlying issuer will have set the condition of pending or failed and we
 	// should potentially wait for a re-sync.
 	if resp == nil {
+		c.metrics.IncrementSignCallCount(crCopy, issuerType, metrics.SignResultPending)
 		return nil
 	}
+	c.metrics.IncrementSignCallCount(crCopy, issuerType, metrics.SignResultIssued)
 
 	// Update to status with the new given response.
 	crCopy.Status.Certificate = resp.Certificate
@@ -153,10 +247,35 @@ func (c *Controller) Sync(ctx context.Context, cr *v1alpha2.CertificateRequest)
 
 	// Set condition to Ready.
 	c.reporter.Ready(crCopy)
+	c.metrics.IncrementIssuanceCount(crCopy, issuerType)
 
 	return nil
 }
 
+// releaseIssuerSlot releases the slot of the issuer held by the
+// CertificateRequest with the given key, if any, and requeues the
+// CertificateRequests waiting for a slot of that issuer.
+func (c *Controller) releaseIssuerSlot(key string) {
+	limiterKey, waiting := c.limiter.release(key)
+	if limiterKey == "" {
+		return
+	}
+	for _, k := range waiting {
+		c.queue.Add(k)
+	}
+	c.updateQueueDepth(limiterKey)
+}
+
+// updateQueueDepth updates the metric of the number of CertificateRequests
+// waiting for a slot of the issuer with the given key.
+func (c *Controller) updateQueueDepth(limiterKey string) {
+	parts := strings.SplitN(limiterKey, "/", 3)
+	if len(parts) != 3 {
+		return
+	}
+	c.metrics.SetCertificateRequestQueueDepth(parts[0], parts[1], parts[2], c.limiter.waitingCount(limiterKey))
+}
+
 func (c *Controller) updateCertificateRequestStatusAndAnnotations(ctx context.Context, old, new *v1alpha2.CertificateRequest) (*v1alpha2.CertificateRequest, error) {
 	log := logf.FromContext(ctx, "updateStatus")
 
@@ -173,5 +292,8 @@ func (c *Controller) updateCertificateRequestStatusAndAnnotations(ctx context.Co
 	}
 
 	log.V(logf.DebugLevel).Info("updating resource due to change in status", "diff", pretty.Diff(string(oldBytes), string(newBytes)))
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		return apply.CertificateRequestStatus(ctx, c.cmClient, c.fieldManager, statusFields, old, new)
+	}
 	return c.cmClient.CertmanagerV1alpha2().CertificateRequests(new.Namespace).UpdateStatus(context.TODO(), new, metav1.UpdateOptions{})
 }
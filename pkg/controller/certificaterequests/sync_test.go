@@ -32,6 +32,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	coretesting "k8s.io/client-go/testing"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 	fakeclock "k8s.io/utils/clock/testing"
 
 	"github.com/jetstack/cert-manager/pkg/api/util"
@@ -39,9 +40,11 @@ import (
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests/fake"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/feature"
 	"github.com/jetstack/cert-manager/pkg/issuer"
 	issuerfake "github.com/jetstack/cert-manager/pkg/issuer/fake"
 	_ "github.com/jetstack/cert-manager/pkg/issuer/selfsigned"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/test/unit/gen"
 )
@@ -131,6 +134,25 @@ func TestSync(t *testing.T) {
 		}),
 	)
 
+	deniedCR := gen.CertificateRequestFrom(baseCR,
+		gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+			Type:    cmapi.CertificateRequestConditionDenied,
+			Status:  cmmeta.ConditionTrue,
+			Reason:  "ByOperator",
+			Message: "not requested by the team",
+		}),
+	)
+	approvedCR := gen.CertificateRequestFrom(baseCR,
+		gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+			Type:   cmapi.CertificateRequestConditionApproved,
+			Status: cmmeta.ConditionTrue,
+			Reason: "ByOperator",
+		}),
+	)
+
+	limitedIssuer := baseIssuer.DeepCopy()
+	limitedIssuer.Annotations = map[string]string{cmapi.IssuerMaxConcurrentRequestsAnnotationKey: "1"}
+
 	certRSAPEM := generateSelfSignedCert(t, baseCR, skRSA, fixedClockStart, fixedClockStart.Add(time.Hour*12))
 	certRSAPEMExpired := generateSelfSignedCert(t, baseCR, skRSA, fixedClockStart.Add(-time.Hour*13), fixedClockStart.Add(-time.Hour*12))
 
@@ -294,6 +316,32 @@ func TestSync(t *testing.T) {
 				ExpectedEvents:  []string{},
 			},
 		},
+		"should exit nil and set status pending if the issuer has the maximum number of requests in flight": {
+			certificateRequest: baseCR.DeepCopy(),
+			inFlightRequests:   []string{gen.DefaultTestNamespace + "/other-cr"},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{baseCR, limitedIssuer},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "Pending",
+								Message:            `Waiting for one of the 1 CertificateRequests in flight for the referenced "Issuer" to complete`,
+								LastTransitionTime: &nowMetaTime,
+							}),
+						),
+					)),
+				},
+				ExpectedEvents: []string{
+					`Normal ConcurrencyLimited Waiting for one of the 1 CertificateRequests in flight for the referenced "Issuer" to complete`,
+				},
+			},
+		},
 		"report failure if the CertificateRequest fails validation": {
 			certificateRequest: gen.CertificateRequestFrom(baseCR,
 				gen.SetCertificateRequestCSR([]byte("bad csr")),
@@ -323,6 +371,73 @@ func TestSync(t *testing.T) {
 				},
 			},
 		},
+		"report failure if the CertificateRequest has been denied": {
+			certificateRequest: gen.CertificateRequestFrom(deniedCR),
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{deniedCR, baseIssuer},
+				ExpectedEvents: []string{
+					"Warning Denied The CertificateRequest was denied: ByOperator: not requested by the team",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(deniedCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "Failed",
+								Message:            "The CertificateRequest was denied: ByOperator: not requested by the team",
+								LastTransitionTime: &nowMetaTime,
+							}),
+							gen.SetCertificateRequestFailureTime(nowMetaTime),
+						),
+					)),
+				},
+			},
+		},
+		"should exit nil and set status pending if approval is required and the CertificateRequest has not been approved": {
+			certificateRequest: baseCR.DeepCopy(),
+			requireApproval:    true,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{baseCR, baseIssuer},
+				ExpectedEvents: []string{
+					"Normal WaitingForApproval Waiting for the CertificateRequest to be approved",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "Pending",
+								Message:            "Waiting for the CertificateRequest to be approved",
+								LastTransitionTime: &nowMetaTime,
+							}),
+						),
+					)),
+				},
+			},
+		},
+		"should sign the CertificateRequest if approval is required and it has been approved": {
+			certificateRequest: approvedCR.DeepCopy(),
+			requireApproval:    true,
+			issuerImpl: &fake.Issuer{
+				FakeSign: func(context.Context, *cmapi.CertificateRequest, cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+					return nil, errors.New("sign call returns error")
+				},
+			},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{approvedCR, baseIssuer},
+				ExpectedEvents:     []string{},
+				ExpectedActions:    []testpkg.Action{},
+			},
+			expectedErr: true,
+		},
 		"if the Certificate is already set in the status then return nil and no-op, regardless of condition": {
 			certificateRequest: gen.CertificateRequestFrom(baseCR,
 				gen.SetCertificateRequestCertificate([]byte("a cert")),
@@ -546,9 +661,17 @@ type testT struct {
 	certificateRequest *cmapi.CertificateRequest
 	helper             *issuerfake.Helper
 	expectedErr        bool
+
+	// keys of CertificateRequests in flight for the referenced issuer
+	inFlightRequests []string
+	// requireApproval enables the CertificateRequestApproval feature gate
+	requireApproval bool
 }
 
 func runTest(t *testing.T, test testT) {
+	if test.requireApproval {
+		defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, feature.CertificateRequestApproval, true)()
+	}
 	test.builder.T = t
 	test.builder.Clock = fixedClock
 	test.builder.Init()
@@ -570,6 +693,11 @@ func runTest(t *testing.T, test testT) {
 		c.helper = test.helper
 	}
 
+	for _, key := range test.inFlightRequests {
+		issuerKey := test.certificateRequest.Namespace + "/" + test.certificateRequest.Spec.IssuerRef.Name + "/" + cmapi.IssuerKind
+		c.limiter.acquire(issuerKey, key, len(test.inFlightRequests))
+	}
+
 	test.builder.Start()
 
 	err := c.Sync(context.Background(), test.certificateRequest)
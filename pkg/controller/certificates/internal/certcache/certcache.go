@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certcache caches the X.509 certificates decoded from Secret
+// resources, so that controllers resyncing large numbers of Certificates do
+// not decode the same PEM data over and over again.
+package certcache
+
+import (
+	"crypto/x509"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// Cache holds the leaf certificate decoded from the `tls.crt` key of Secret
+// resources. Entries are keyed by the namespace and name of the Secret and
+// are only valid for the resourceVersion they were decoded from, so a changed
+// Secret is always decoded again.
+// A nil *Cache is valid and decodes the certificate on every call.
+type Cache struct {
+	lock    sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	resourceVersion string
+	cert            *x509.Certificate
+	err             error
+}
+
+// New returns an empty Cache.
+func New() *Cache {
+	return &Cache{entries: make(map[string]entry)}
+}
+
+// Certificate returns the leaf certificate stored in the Secret, or the error
+// decoding it. The returned certificate is shared and must not be modified.
+func (c *Cache) Certificate(secret *corev1.Secret) (*x509.Certificate, error) {
+	// Secrets without a resourceVersion have not been read from the API
+	// server, so there is no way to tell whether they changed.
+	if c == nil || secret.ResourceVersion == "" {
+		return pki.DecodeX509CertificateBytes(secret.Data[corev1.TLSCertKey])
+	}
+
+	key := secret.Namespace + "/" + secret.Name
+
+	c.lock.Lock()
+	e, ok := c.entries[key]
+	c.lock.Unlock()
+	if ok && e.resourceVersion == secret.ResourceVersion {
+		return e.cert, e.err
+	}
+
+	cert, err := pki.DecodeX509CertificateBytes(secret.Data[corev1.TLSCertKey])
+
+	c.lock.Lock()
+	c.entries[key] = entry{resourceVersion: secret.ResourceVersion, cert: cert, err: err}
+	c.lock.Unlock()
+
+	return cert, err
+}
+
+// Forget removes the entry of the given Secret, if any.
+func (c *Cache) Forget(namespace, name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.entries, namespace+"/"+name)
+}
+
+// EventHandler returns an event handler that removes the entries of deleted
+// Secrets, to be registered with a Secret informer.
+func (c *Cache) EventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if secret, ok := obj.(*corev1.Secret); ok {
+				c.Forget(secret.Namespace, secret.Name)
+			}
+		},
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certcache
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+func mustCertificatePEM(t *testing.T, cn string) []byte {
+	key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certPEM, _, err := pki.SignCertificate(template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certPEM
+}
+
+func secret(resourceVersion string, certPEM []byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "tls", ResourceVersion: resourceVersion},
+		Data:       map[string][]byte{corev1.TLSCertKey: certPEM},
+	}
+}
+
+func TestCertificate(t *testing.T) {
+	first := mustCertificatePEM(t, "first")
+	second := mustCertificatePEM(t, "second")
+
+	tests := map[string]struct {
+		cache      *Cache
+		secrets    []*corev1.Secret
+		expSameCN  string
+		expCached  bool
+		expErrored bool
+	}{
+		"unchanged Secret is decoded once": {
+			cache:     New(),
+			secrets:   []*corev1.Secret{secret("1", first), secret("1", first)},
+			expSameCN: "first",
+			expCached: true,
+		},
+		"changed Secret is decoded again": {
+			cache:     New(),
+			secrets:   []*corev1.Secret{secret("1", first), secret("2", second)},
+			expSameCN: "second",
+		},
+		"Secret without resourceVersion is not cached": {
+			cache:     New(),
+			secrets:   []*corev1.Secret{secret("", first), secret("", first)},
+			expSameCN: "first",
+		},
+		"nil cache decodes the certificate": {
+			secrets:   []*corev1.Secret{secret("1", first), secret("1", first)},
+			expSameCN: "first",
+		},
+		"decoding error is cached": {
+			cache:      New(),
+			secrets:    []*corev1.Secret{secret("1", []byte("invalid")), secret("1", []byte("invalid"))},
+			expErrored: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var certs []*x509.Certificate
+			for _, s := range test.secrets {
+				cert, err := test.cache.Certificate(s)
+				if test.expErrored != (err != nil) {
+					t.Fatalf("expected error %t but got: %v", test.expErrored, err)
+				}
+				certs = append(certs, cert)
+			}
+			if test.expErrored {
+				return
+			}
+			last := certs[len(certs)-1]
+			if last.Subject.CommonName != test.expSameCN {
+				t.Errorf("expected certificate %q, got %q", test.expSameCN, last.Subject.CommonName)
+			}
+			if cached := certs[0] == last; cached != test.expCached {
+				t.Errorf("expected cached certificate to be returned %t, got %t", test.expCached, cached)
+			}
+		})
+	}
+}
+
+func TestForget(t *testing.T) {
+	c := New()
+	s := secret("1", mustCertificatePEM(t, "first"))
+	cert, err := c.Certificate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Forget(s.Namespace, s.Name)
+	again, err := c.Certificate(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert == again {
+		t.Errorf("expected certificate to be decoded again after Forget")
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	utilpki "github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// managedSecretAnnotations are the annotations of Secret resources that are
+// set by cert-manager.
+var managedSecretAnnotations = []string{
+	cmapi.CertificateNameKey,
+	cmapi.IssuerNameAnnotationKey,
+	cmapi.IssuerKindAnnotationKey,
+	cmapi.IssuerGroupAnnotationKey,
+	cmapi.DeprecatedIssuerNameAnnotationKey,
+	cmapi.DeprecatedIssuerKindAnnotationKey,
+	cmapi.CommonNameAnnotationKey,
+	cmapi.AltNamesAnnotationKey,
+	cmapi.IPSANAnnotationKey,
+	cmapi.URISANAnnotationKey,
+	cmapi.AttestationAnnotationKey,
+}
+
+// applySecret writes the fields of secret that are managed by cert-manager
+// with server-side apply, leaving all other fields to their field managers.
+// Keys and annotations that are no longer set are removed by the apiserver if
+// they were applied by the same field manager before. Those written before
+// the Secret was first applied are owned by other field managers, so until
+// the Secret has been applied once the keys and annotations of existing that
+// setValues removed are removed explicitly first.
+func (s *SecretsManager) applySecret(ctx context.Context, crt *cmapi.Certificate, existing, secret *corev1.Secret, data SecretData) (*corev1.Secret, error) {
+	if existing != nil && !appliedBy(existing, s.fieldManager) {
+		if err := s.removeStaleFields(ctx, existing, secret); err != nil {
+			return nil, fmt.Errorf("removing stale fields from Secret: %w", err)
+		}
+	}
+
+	body, err := json.Marshal(secretApplyConfiguration(crt, secret, data, s.enableSecretOwnerReferences))
+	if err != nil {
+		return nil, err
+	}
+
+	force := true
+	return s.kubeClient.CoreV1().Secrets(secret.Namespace).Patch(ctx, secret.Name, types.ApplyPatchType, body, metav1.PatchOptions{
+		FieldManager: s.fieldManager,
+		Force:        &force,
+	})
+}
+
+// appliedBy returns true if secret has been written with server-side apply by
+// the given field manager before.
+func appliedBy(secret *corev1.Secret, fieldManager string) bool {
+	for _, entry := range secret.ManagedFields {
+		if entry.Manager == fieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return true
+		}
+	}
+	return false
+}
+
+// removeStaleFields removes the keys and annotations of existing that are no
+// longer set on secret with a JSON patch.
+func (s *SecretsManager) removeStaleFields(ctx context.Context, existing, secret *corev1.Secret) error {
+	var paths []string
+	for key := range existing.Data {
+		if _, ok := secret.Data[key]; !ok {
+			paths = append(paths, "/data/"+escapeJSONPointer(key))
+		}
+	}
+	for key := range existing.Annotations {
+		if _, ok := secret.Annotations[key]; !ok {
+			paths = append(paths, "/metadata/annotations/"+escapeJSONPointer(key))
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	sort.Strings(paths)
+
+	ops := make([]map[string]string, len(paths))
+	for i, path := range paths {
+		ops[i] = map[string]string{"op": "remove", "path": path}
+	}
+	body, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.kubeClient.CoreV1().Secrets(existing.Namespace).Patch(ctx, existing.Name, types.JSONPatchType, body, metav1.PatchOptions{
+		FieldManager: s.fieldManager,
+	})
+	return err
+}
+
+// secretApplyConfiguration returns a Secret containing only the fields of
+// secret that are managed by cert-manager, to be written with server-side
+// apply.
+func secretApplyConfiguration(crt *cmapi.Certificate, secret *corev1.Secret, data SecretData, ownerReferences bool) *corev1.Secret {
+	applied := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        secret.Name,
+			Namespace:   secret.Namespace,
+			Annotations: make(map[string]string),
+		},
+		Type: secret.Type,
+		Data: make(map[string][]byte),
+	}
+	if ownerReferences {
+		applied.OwnerReferences = secret.OwnerReferences
+	}
+
+	for _, key := range managedSecretAnnotations {
+		if value, ok := secret.Annotations[key]; ok {
+			applied.Annotations[key] = value
+		}
+	}
+	for _, key := range managedSecretKeys(crt, data) {
+		if value, ok := secret.Data[key]; ok {
+			applied.Data[key] = value
+		}
+	}
+
+	return applied
+}
+
+// managedSecretKeys returns the keys of the Secret of crt that cert-manager
+// stores the given data under.
+func managedSecretKeys(crt *cmapi.Certificate, data SecretData) []string {
+	keys := []string{
+		corev1.TLSPrivateKeyKey,
+		corev1.TLSCertKey,
+		cmmeta.TLSCAKey,
+		pkcs12SecretKey,
+		jksSecretKey,
+		jksTruststoreKey,
+	}
+	if !crt.Spec.SecretKeysPerName || len(data.Certificate) == 0 {
+		return keys
+	}
+	cert, err := utilpki.DecodeX509CertificateBytes(data.Certificate)
+	if err != nil {
+		return keys
+	}
+	for _, name := range secretKeyNames(cert) {
+		keys = append(keys, name+perNameCertificateKeySuffix, name+perNamePrivateKeyKeySuffix)
+	}
+	return keys
+}
+
+// escapeJSONPointer escapes a key for use as a segment of a JSON pointer.
+func escapeJSONPointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsmanager
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestSecretApplyConfiguration(t *testing.T) {
+	crt := gen.Certificate("test", gen.SetCertificateSecretName("output"))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "output",
+			Namespace: gen.DefaultTestNamespace,
+			Labels:    map[string]string{"app": "web"},
+			Annotations: map[string]string{
+				cmapi.CertificateNameKey: "test",
+				"example.com/gitops":     "true",
+			},
+			OwnerReferences: []metav1.OwnerReference{{Name: "test"}},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("cert"),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+			"user-key":              []byte("value"),
+		},
+	}
+
+	applied := secretApplyConfiguration(crt, secret, SecretData{}, false)
+
+	exp := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "output",
+			Namespace:   gen.DefaultTestNamespace,
+			Annotations: map[string]string{cmapi.CertificateNameKey: "test"},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("cert"),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+		},
+	}
+	if !reflect.DeepEqual(applied, exp) {
+		t.Errorf("unexpected apply configuration:\nexp: %+v\ngot: %+v", exp, applied)
+	}
+
+	if applied := secretApplyConfiguration(crt, secret, SecretData{}, true); len(applied.OwnerReferences) != 1 {
+		t.Errorf("expected the owner references to be applied, got: %v", applied.OwnerReferences)
+	}
+}
+
+func TestRemoveStaleFields(t *testing.T) {
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "output",
+			Namespace: gen.DefaultTestNamespace,
+			Annotations: map[string]string{
+				cmapi.CertificateNameKey:       "test",
+				cmapi.AttestationAnnotationKey: "attestation",
+			},
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey: []byte("cert"),
+			cmmeta.TLSCAKey:   []byte("ca"),
+		},
+	}
+	secret := existing.DeepCopy()
+	delete(secret.Data, cmmeta.TLSCAKey)
+	delete(secret.Annotations, cmapi.AttestationAnnotationKey)
+
+	client := fake.NewSimpleClientset(existing)
+	s := &SecretsManager{kubeClient: client, fieldManager: "cert-manager-test"}
+	if err := s.removeStaleFields(context.Background(), existing, secret); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := client.CoreV1().Secrets(existing.Namespace).Get(context.Background(), existing.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Data, secret.Data) {
+		t.Errorf("expected data %v, got: %v", secret.Data, got.Data)
+	}
+	if !reflect.DeepEqual(got.Annotations, secret.Annotations) {
+		t.Errorf("expected annotations %v, got: %v", secret.Annotations, got.Annotations)
+	}
+}
+
+func TestAppliedBy(t *testing.T) {
+	secret := func(entries ...metav1.ManagedFieldsEntry) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{ManagedFields: entries}}
+	}
+
+	tests := map[string]struct {
+		secret *corev1.Secret
+		exp    bool
+	}{
+		"never written by the field manager": {
+			secret: secret(metav1.ManagedFieldsEntry{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply}),
+		},
+		"only updated by the field manager": {
+			secret: secret(metav1.ManagedFieldsEntry{Manager: "cert-manager-test", Operation: metav1.ManagedFieldsOperationUpdate}),
+		},
+		"applied by the field manager": {
+			secret: secret(
+				metav1.ManagedFieldsEntry{Manager: "cert-manager-test", Operation: metav1.ManagedFieldsOperationUpdate},
+				metav1.ManagedFieldsEntry{Manager: "cert-manager-test", Operation: metav1.ManagedFieldsOperationApply},
+			),
+			exp: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := appliedBy(test.secret, "cert-manager-test"); got != test.exp {
+				t.Errorf("expected %t, got %t", test.exp, got)
+			}
+		})
+	}
+}
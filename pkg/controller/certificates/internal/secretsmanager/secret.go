@@ -19,6 +19,7 @@ package secretsmanager
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"fmt"
 	"strings"
 
@@ -31,6 +32,9 @@ import (
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/feature"
+	"github.com/jetstack/cert-manager/pkg/util/attestation"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 	utilpki "github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
@@ -38,6 +42,14 @@ var (
 	certificateGvk = cmapi.SchemeGroupVersion.WithKind("Certificate")
 )
 
+const (
+	// The suffixes of the keys that the certificate and private key are
+	// stored under for each of the names of the certificate if
+	// `spec.secretKeysPerName` is set.
+	perNameCertificateKeySuffix = ".crt"
+	perNamePrivateKeyKeySuffix  = ".key"
+)
+
 type SecretsManager struct {
 	kubeClient   kubernetes.Interface
 	secretLister corelisters.SecretLister
@@ -47,6 +59,14 @@ type SecretsManager struct {
 	// Secret resource will be automatically deleted.
 	// This option is disabled by default.
 	enableSecretOwnerReferences bool
+
+	// attestor, if not nil, is used to sign an attestation of the
+	// certificate stored in Secret resources
+	attestor attestation.Attestor
+
+	// fieldManager is the field manager used to apply Secret resources if
+	// the ServerSideApply feature is enabled
+	fieldManager string
 }
 
 // SecretData is a structure wrapping private key, Certificate and CA data
@@ -58,11 +78,15 @@ func New(
 	kubeClient kubernetes.Interface,
 	secretLister corelisters.SecretLister,
 	enableSecretOwnerReferences bool,
+	attestor attestation.Attestor,
+	fieldManager string,
 ) *SecretsManager {
 	return &SecretsManager{
 		kubeClient:                  kubeClient,
 		secretLister:                secretLister,
 		enableSecretOwnerReferences: enableSecretOwnerReferences,
+		attestor:                    attestor,
+		fieldManager:                fieldManager,
 	}
 }
 
@@ -70,20 +94,22 @@ func New(
 // data as well as appropriate metadata.
 // If the Secret resource does not exist, it will be created.
 // Otherwise, the existing resource will be updated.
-// The first return argument will be true if the resource was updated/created
-// without error.
+// The created or updated Secret resource is returned if no error occurred.
 // UpdateData will also update deprecated annotations if they exist.
-func (s *SecretsManager) UpdateData(ctx context.Context, crt *cmapi.Certificate, data SecretData) error {
+func (s *SecretsManager) UpdateData(ctx context.Context, crt *cmapi.Certificate, data SecretData) (*corev1.Secret, error) {
 	// Fetch a copy of the existing Secret resource
-	secret, err := s.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	existing, err := s.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
 	if !apierrors.IsNotFound(err) && err != nil {
 		// If secret doesn't exist yet, then don't error
-		return err
+		return nil, err
 	}
-	secretExists := (secret != nil)
+	secretExists := (existing != nil)
 
-	// If the seret does not exist yet, then we need to create one
-	if !secretExists {
+	var secret *corev1.Secret
+	if secretExists {
+		secret = existing.DeepCopy()
+	} else {
+		// If the seret does not exist yet, then we need to create one
 		secret = &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      crt.Spec.SecretName,
@@ -100,19 +126,20 @@ func (s *SecretsManager) UpdateData(ctx context.Context, crt *cmapi.Certificate,
 
 	err = s.setValues(crt, secret, data)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		return s.applySecret(ctx, crt, existing, secret, data)
 	}
 
 	// If secret does not exist then create it
 	if !secretExists {
-
-		_, err = s.kubeClient.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
-		return err
+		return s.kubeClient.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
 	}
 
 	// Currently we are always updating. We should devise a way to not have to call an update if it is not necessary.
-	_, err = s.kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
-	return err
+	return s.kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
 }
 
 // setValues will update the Secret resource 'secret' with the data contained
@@ -190,6 +217,17 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 		}
 	}
 
+	// the per name keys of the previous certificate are always removed, as
+	// its names may differ from the names of the new certificate
+	if len(secret.Data[corev1.TLSCertKey]) > 0 {
+		if previousCert, err := utilpki.DecodeX509CertificateBytes(secret.Data[corev1.TLSCertKey]); err == nil {
+			for _, name := range secretKeyNames(previousCert) {
+				delete(secret.Data, name+perNameCertificateKeySuffix)
+				delete(secret.Data, name+perNamePrivateKeyKeySuffix)
+			}
+		}
+	}
+
 	secret.Data[corev1.TLSPrivateKeyKey] = data.PrivateKey
 	secret.Data[corev1.TLSCertKey] = data.Certificate
 	if len(data.CA) > 0 {
@@ -222,6 +260,7 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 		delete(secret.Annotations, cmapi.AltNamesAnnotationKey)
 		delete(secret.Annotations, cmapi.IPSANAnnotationKey)
 		delete(secret.Annotations, cmapi.URISANAnnotationKey)
+		delete(secret.Annotations, cmapi.AttestationAnnotationKey)
 	} else {
 		x509Cert, err := utilpki.DecodeX509CertificateBytes(data.Certificate)
 		// TODO: handle InvalidData here?
@@ -233,7 +272,48 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 		secret.Annotations[cmapi.AltNamesAnnotationKey] = strings.Join(x509Cert.DNSNames, ",")
 		secret.Annotations[cmapi.IPSANAnnotationKey] = strings.Join(utilpki.IPAddressesToString(x509Cert.IPAddresses), ",")
 		secret.Annotations[cmapi.URISANAnnotationKey] = strings.Join(utilpki.URLsToString(x509Cert.URIs), ",")
+
+		if crt.Spec.SecretKeysPerName {
+			for _, name := range secretKeyNames(x509Cert) {
+				secret.Data[name+perNameCertificateKeySuffix] = data.Certificate
+				secret.Data[name+perNamePrivateKeyKeySuffix] = data.PrivateKey
+			}
+		}
+
+		// an attestation signed for a previous certificate must not be kept,
+		// as it would fail verification
+		if s.attestor != nil {
+			att, err := s.attestor.Attest(crt, data.Certificate)
+			if err != nil {
+				return fmt.Errorf("error signing attestation: %w", err)
+			}
+			secret.Annotations[cmapi.AttestationAnnotationKey] = att
+		} else {
+			delete(secret.Annotations, cmapi.AttestationAnnotationKey)
+		}
 	}
 
 	return nil
 }
+
+// secretKeyNames returns the names of the Secret keys that the given
+// certificate is stored under, derived from its DNS names and IP addresses.
+// Characters that are not valid in Secret keys are replaced, such that
+// '*.example.com' is stored as '_.example.com' and '::1' as '--1'. Names
+// that would overwrite the 'tls.crt', 'tls.key' or 'ca.crt' keys are skipped.
+func secretKeyNames(cert *x509.Certificate) []string {
+	var names []string
+	add := func(name string) {
+		if name == "tls" || name == "ca" {
+			return
+		}
+		names = append(names, name)
+	}
+	for _, dnsName := range cert.DNSNames {
+		add(strings.ReplaceAll(strings.ToLower(dnsName), "*", "_"))
+	}
+	for _, ip := range utilpki.IPAddressesToString(cert.IPAddresses) {
+		add(strings.ReplaceAll(ip, ":", "-"))
+	}
+	return names
+}
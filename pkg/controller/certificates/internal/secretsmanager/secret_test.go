@@ -33,6 +33,7 @@ import (
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	internaltest "github.com/jetstack/cert-manager/pkg/controller/certificates/internal/test"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/util/attestation"
 	utilpki "github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/test/unit/gen"
 )
@@ -42,11 +43,19 @@ var (
 	fixedClock      = fakeclock.NewFakeClock(fixedClockStart)
 )
 
+// fakeAttestor returns itself as the attestation of any certificate
+type fakeAttestor string
+
+func (f fakeAttestor) Attest(*cmapi.Certificate, []byte) (string, error) {
+	return string(f), nil
+}
+
 func TestSecretsManager(t *testing.T) {
 	type testT struct {
 		builder *testpkg.Builder
 
 		certificateOptions controllerpkg.CertificateOptions
+		attestor           attestation.Attestor
 		certificate        *cmapi.Certificate
 		SecretData         SecretData
 
@@ -275,6 +284,100 @@ func TestSecretsManager(t *testing.T) {
 			},
 			expectedErr: false,
 		},
+
+		"if an attestor is configured, annotate the created Secret with the attestation": {
+			certificate: exampleBundle.Certificate,
+			attestor:    fakeAttestor("test-attestation"),
+			SecretData:  SecretData{Certificate: exampleBundle.CertBytes, CA: []byte("test-ca"), PrivateKey: []byte("test-key")},
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						gen.DefaultTestNamespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: gen.DefaultTestNamespace,
+								Name:      "output",
+								Annotations: map[string]string{
+									cmapi.CertificateNameKey:       "test",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
+									cmapi.IssuerKindAnnotationKey:  "Issuer",
+									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+
+									cmapi.CommonNameAnnotationKey: exampleBundle.Cert.Subject.CommonName,
+									cmapi.AltNamesAnnotationKey:   strings.Join(exampleBundle.Cert.DNSNames, ","),
+									cmapi.IPSANAnnotationKey:      strings.Join(utilpki.IPAddressesToString(exampleBundle.Cert.IPAddresses), ","),
+									cmapi.URISANAnnotationKey:     strings.Join(utilpki.URLsToString(exampleBundle.Cert.URIs), ","),
+
+									cmapi.AttestationAnnotationKey: "test-attestation",
+								},
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertBytes,
+								corev1.TLSPrivateKeyKey: []byte("test-key"),
+								cmmeta.TLSCAKey:         []byte("test-ca"),
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if no attestor is configured, remove a stale attestation from the existing Secret": {
+			certificate: exampleBundle.Certificate,
+			SecretData:  SecretData{Certificate: exampleBundle.CertBytes, CA: []byte("test-ca"), PrivateKey: []byte("test-key")},
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: gen.DefaultTestNamespace,
+							Name:      "output",
+							Annotations: map[string]string{
+								cmapi.AttestationAnnotationKey: "stale-attestation",
+							},
+						},
+						Data: map[string][]byte{
+							corev1.TLSCertKey:       []byte("foo"),
+							corev1.TLSPrivateKeyKey: []byte("foo"),
+						},
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						gen.DefaultTestNamespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: gen.DefaultTestNamespace,
+								Name:      "output",
+								Annotations: map[string]string{
+									cmapi.CertificateNameKey:       "test",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
+									cmapi.IssuerKindAnnotationKey:  "Issuer",
+									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+
+									cmapi.CommonNameAnnotationKey: exampleBundle.Cert.Subject.CommonName,
+									cmapi.AltNamesAnnotationKey:   strings.Join(exampleBundle.Cert.DNSNames, ","),
+									cmapi.IPSANAnnotationKey:      strings.Join(utilpki.IPAddressesToString(exampleBundle.Cert.IPAddresses), ","),
+									cmapi.URISANAnnotationKey:     strings.Join(utilpki.URLsToString(exampleBundle.Cert.URIs), ","),
+								},
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertBytes,
+								corev1.TLSPrivateKeyKey: []byte("test-key"),
+								cmmeta.TLSCAKey:         []byte("test-ca"),
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+			},
+			expectedErr: false,
+		},
 	}
 
 	// TODO: add to these tests once the JKS/PKCS12 support is updated
@@ -294,11 +397,13 @@ func TestSecretsManager(t *testing.T) {
 				kubeClient,
 				secretsLister,
 				test.certificateOptions.EnableOwnerRef,
+				test.attestor,
+				"cert-manager-test",
 			)
 
 			test.builder.Start()
 
-			err := testManager.UpdateData(context.Background(), test.certificate, test.SecretData)
+			_, err := testManager.UpdateData(context.Background(), test.certificate, test.SecretData)
 			if err != nil && !test.expectedErr {
 				t.Errorf("expected to not get an error, but got: %v", err)
 			}
@@ -309,3 +414,69 @@ func TestSecretsManager(t *testing.T) {
 		})
 	}
 }
+
+func TestSetValuesSecretKeysPerName(t *testing.T) {
+	baseCert := gen.Certificate("test",
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer"}),
+		gen.SetCertificateSecretName("output"),
+	)
+	previousBundle := internaltest.MustCreateCryptoBundle(t, gen.CertificateFrom(baseCert,
+		gen.SetCertificateDNSNames("old.example.com"),
+	), fixedClock)
+	bundle := internaltest.MustCreateCryptoBundle(t, gen.CertificateFrom(baseCert,
+		gen.SetCertificateDNSNames("example.com", "*.example.com", "tls"),
+		gen.SetCertificateIPs("10.0.0.1", "::1"),
+	), fixedClock)
+
+	tests := map[string]struct {
+		secretKeysPerName bool
+		expectedKeys      []string
+	}{
+		"store the certificate under a key for each name": {
+			secretKeysPerName: true,
+			expectedKeys: []string{
+				corev1.TLSCertKey, corev1.TLSPrivateKeyKey, "custom",
+				"example.com.crt", "example.com.key",
+				"_.example.com.crt", "_.example.com.key",
+				"10.0.0.1.crt", "10.0.0.1.key",
+				"--1.crt", "--1.key",
+			},
+		},
+		"remove the keys of the previous certificate if disabled": {
+			expectedKeys: []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey, "custom"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := gen.CertificateFrom(bundle.Certificate)
+			crt.Spec.SecretKeysPerName = test.secretKeysPerName
+			secret := &corev1.Secret{
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       previousBundle.CertBytes,
+					corev1.TLSPrivateKeyKey: previousBundle.PrivateKeyBytes,
+					"old.example.com.crt":   previousBundle.CertBytes,
+					"old.example.com.key":   previousBundle.PrivateKeyBytes,
+					"custom":                []byte("custom"),
+				},
+			}
+
+			s := &SecretsManager{}
+			if err := s.setValues(crt, secret, SecretData{Certificate: bundle.CertBytes, PrivateKey: bundle.PrivateKeyBytes}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(secret.Data) != len(test.expectedKeys) {
+				t.Errorf("expected keys %v but got %d keys", test.expectedKeys, len(secret.Data))
+			}
+			for _, key := range test.expectedKeys {
+				if _, ok := secret.Data[key]; !ok {
+					t.Errorf("expected key %q to be set", key)
+				}
+			}
+			if test.secretKeysPerName && string(secret.Data["_.example.com.crt"]) != string(bundle.CertBytes) {
+				t.Errorf("expected certificate to be stored under per name key")
+			}
+		})
+	}
+}
@@ -121,6 +121,7 @@ func createCryptoBundle(crt *cmapi.Certificate, fixedClock *fakeclock.FakeClock)
 			Namespace:       crt.Namespace,
 			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(crt, certificateGvk)},
 			Annotations:     annotations,
+			Labels:          map[string]string{cmapi.CertificateNameKey: crt.Name},
 		},
 		Spec: cmapi.CertificateRequestSpec{
 			CSRPEM:    csrPEM,
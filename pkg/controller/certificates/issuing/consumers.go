@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuing
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/consumerpatch"
+	utilpki "github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	// reasonSecretConsumerPatched and reasonSecretConsumerPatchFailed are
+	// the reasons of the Events recorded on a Certificate when the patches
+	// of its cert-manager.io/secret-consumer-patches annotation are applied
+	reasonSecretConsumerPatched     = "SecretConsumerPatched"
+	reasonSecretConsumerPatchFailed = "SecretConsumerPatchFailed"
+)
+
+// patchSecretConsumers applies the patches of the
+// cert-manager.io/secret-consumer-patches annotation of the Certificate once
+// the certificate of the given revision has been stored in the Secret.
+// Failures are recorded as Events on the Certificate but do not fail the
+// issuance, as the certificate has already been stored, and are not retried
+// until the next issuance.
+func (c *controller) patchSecretConsumers(ctx context.Context, crt *cmapi.Certificate, secret *corev1.Secret, revision int, certPEM []byte) {
+	value, ok := crt.Annotations[cmapi.SecretConsumerPatchesAnnotationKey]
+	if !ok || c.patcher == nil {
+		return
+	}
+	log := logf.FromContext(ctx)
+
+	patches, err := consumerpatch.Parse(value)
+	if err != nil {
+		c.recorder.Eventf(crt, corev1.EventTypeWarning, reasonSecretConsumerPatchFailed,
+			"Invalid %s annotation: %v", cmapi.SecretConsumerPatchesAnnotationKey, err)
+		return
+	}
+
+	data := consumerpatch.Data{
+		CertificateName:       crt.Name,
+		SecretName:            secret.Name,
+		SecretResourceVersion: secret.ResourceVersion,
+		Revision:              revision,
+	}
+	if cert, err := utilpki.DecodeX509CertificateBytes(certPEM); err == nil {
+		data.SerialNumber = cert.SerialNumber.Text(16)
+		data.NotAfter = cert.NotAfter.UTC().Format(time.RFC3339)
+	}
+
+	for _, patch := range patches {
+		if err := c.patcher.Apply(ctx, crt.Namespace, patch, data); err != nil {
+			log.Error(err, "failed to patch consumer of Secret", "target", patch.Target.String())
+			c.recorder.Eventf(crt, corev1.EventTypeWarning, reasonSecretConsumerPatchFailed,
+				"Failed to patch %s after storing certificate revision %d: %v", patch.Target, revision, err)
+			continue
+		}
+		c.recorder.Eventf(crt, corev1.EventTypeNormal, reasonSecretConsumerPatched,
+			"Patched %s after storing certificate revision %d", patch.Target, revision)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -44,7 +45,12 @@ import (
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/internal/secretsmanager"
+	"github.com/jetstack/cert-manager/pkg/feature"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/apply"
+	"github.com/jetstack/cert-manager/pkg/util/attestation"
+	"github.com/jetstack/cert-manager/pkg/util/consumerpatch"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 	utilkube "github.com/jetstack/cert-manager/pkg/util/kube"
 	utilpki "github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
@@ -52,6 +58,14 @@ import (
 
 const (
 	ControllerName = "CertificateIssuing"
+
+	// reasonIssued is the reason of the Events recorded on a Secret when
+	// a newly issued certificate has been stored in it
+	reasonIssued = "Issued"
+
+	// reasonSecretWriteFailed is the reason of the Issuing condition while
+	// an issued certificate could not be stored in the Secret yet
+	reasonSecretWriteFailed = "SecretWriteFailed"
 )
 
 type localTemporarySignerFn func(crt *cmapi.Certificate, pk []byte) ([]byte, error)
@@ -72,6 +86,22 @@ type controller struct {
 	secretsManager *secretsmanager.SecretsManager
 	// localTemporarySigner signs a certificate that is stored temporarily
 	localTemporarySigner localTemporarySignerFn
+
+	// patcher applies the patches of the
+	// cert-manager.io/secret-consumer-patches annotation. If nil, the
+	// annotation is ignored.
+	patcher *consumerpatch.Patcher
+
+	// fieldManager is the field manager used to apply Secrets and the status
+	// of Certificates if the ServerSideApply feature is enabled
+	fieldManager string
+}
+
+// statusFields are the status fields and conditions of Certificates that are
+// managed by this controller.
+var statusFields = apply.Fields{
+	Status:     []string{"revision", "lastFailureTime"},
+	Conditions: []string{string(cmapi.CertificateConditionIssuing)},
 }
 
 func NewController(
@@ -83,10 +113,13 @@ func NewController(
 	recorder record.EventRecorder,
 	clock clock.Clock,
 	certificateControllerOptions controllerpkg.CertificateOptions,
+	clusterResourceNamespace string,
+	backoff *controllerpkg.BackoffPersister,
+	fieldManager string,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 
 	// create a queue used to queue up items to be processed
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+	queue := controllerpkg.NewRateLimitingQueue(backoff, workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	certificateInformer := cmFactory.Certmanager().V1alpha2().Certificates()
@@ -117,10 +150,18 @@ func NewController(
 		certificateInformer.Informer().HasSynced,
 	}
 
+	var attestor attestation.Attestor
+	if certificateControllerOptions.AttestationKeySecretName != "" {
+		attestor = attestation.NewSecretKeyAttestor(secretsInformer.Lister(), clusterResourceNamespace,
+			certificateControllerOptions.AttestationKeySecretName, clock)
+	}
+
 	secretsManager := secretsmanager.New(
 		kubeClient,
 		secretsInformer.Lister(),
 		certificateControllerOptions.EnableOwnerRef,
+		attestor,
+		fieldManager,
 	)
 
 	return &controller{
@@ -131,6 +172,7 @@ func NewController(
 		recorder:                 recorder,
 		clock:                    clock,
 		secretsManager:           secretsManager,
+		fieldManager:             fieldManager,
 		localTemporarySigner:     certificates.GenerateLocallySignedTemporaryCertificate,
 	}, queue, mustSync
 }
@@ -159,6 +201,11 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	log = logf.WithResource(log, crt)
 	ctx = logf.NewContext(ctx, log)
 
+	if apiutil.IsPaused(crt) {
+		log.V(logf.DebugLevel).Info("certificate is paused, skipping processing")
+		return nil
+	}
+
 	if !apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
 		Type:   cmapi.CertificateConditionIssuing,
 		Status: cmmeta.ConditionTrue,
@@ -282,9 +329,6 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 
 // failIssueCertificate will mark the condition Issuing of this Certificate as failed, and log an appropriate event
 func (c *controller) failIssueCertificate(ctx context.Context, log logr.Logger, crt *cmapi.Certificate, req *cmapi.CertificateRequest) error {
-	nowTime := metav1.NewTime(c.clock.Now())
-	crt.Status.LastFailureTime = &nowTime
-
 	log.Info("CertificateRequest in failed state so retrying issuance later")
 
 	var reason, message string
@@ -294,11 +338,13 @@ func (c *controller) failIssueCertificate(ctx context.Context, log logr.Logger,
 	message = fmt.Sprintf("The certificate request has failed to complete and will be retried: %s",
 		condition.Message)
 
+	existing := crt
 	crt = crt.DeepCopy()
+	nowTime := metav1.NewTime(c.clock.Now())
+	crt.Status.LastFailureTime = &nowTime
 	apiutil.SetCertificateCondition(crt, cmapi.CertificateConditionIssuing, cmmeta.ConditionFalse, reason, message)
 
-	_, err := c.client.CertmanagerV1alpha2().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
-	if err != nil {
+	if err := c.updateStatus(ctx, existing, crt); err != nil {
 		return err
 	}
 
@@ -321,11 +367,16 @@ func (c *controller) issueCertificate(ctx context.Context, nextRevision int, crt
 		CA:          req.Status.CA,
 	}
 
-	err = c.secretsManager.UpdateData(ctx, crt, secretData)
+	secret, err := c.secretsManager.UpdateData(ctx, crt, secretData)
 	if err != nil {
-		return err
+		return c.secretWriteFailed(ctx, nextRevision, crt, err)
 	}
 
+	// Record the rotation on the Secret too, so that it is visible to anyone
+	// looking at the Secret without having to find its Certificate
+	c.recorder.Eventf(secret, corev1.EventTypeNormal, reasonIssued, "Stored certificate revision %d issued for Certificate %q", nextRevision, crt.Name)
+
+	existing := crt
 	crt = crt.DeepCopy()
 
 	//Set status.revision to revision of the CertificateRequest
@@ -337,17 +388,63 @@ func (c *controller) issueCertificate(ctx context.Context, nextRevision int, crt
 	//Clear status.lastFailureTime (if set)
 	crt.Status.LastFailureTime = nil
 
-	_, err = c.client.CertmanagerV1alpha2().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
-	if err != nil {
+	if err := c.updateStatus(ctx, existing, crt); err != nil {
 		return err
 	}
 
 	message := "The certificate has been successfully issued"
 	c.recorder.Event(crt, corev1.EventTypeNormal, "Issuing", message)
 
+	c.patchSecretConsumers(ctx, crt, secret, nextRevision, req.Status.Certificate)
+
 	return nil
 }
 
+// secretWriteFailed records that the certificate issued for the given
+// revision could not be stored in the Secret, and returns writeErr so that
+// the write is retried with backoff.
+// The issued certificate is kept in the CertificateRequest, which is not
+// replaced while the Certificate is Issuing, so a failed write never leads to
+// a new issuance. The failure is tracked in the Issuing condition so that it
+// is visible on the Certificate until the write succeeds.
+func (c *controller) secretWriteFailed(ctx context.Context, nextRevision int, crt *cmapi.Certificate, writeErr error) error {
+	log := logf.FromContext(ctx)
+	log.Error(writeErr, "failed to store issued certificate in Secret, will retry", "revision", nextRevision)
+
+	message := fmt.Sprintf("Failed to store certificate revision %d in Secret %q, will retry: %v",
+		nextRevision, crt.Spec.SecretName, writeErr)
+
+	// Only update the condition if it changed, to not reset the backoff of
+	// the retries by triggering a resync.
+	if cond := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuing); cond != nil &&
+		cond.Reason == reasonSecretWriteFailed && cond.Message == message {
+		return writeErr
+	}
+
+	existing := crt
+	crt = crt.DeepCopy()
+	apiutil.SetCertificateCondition(crt, cmapi.CertificateConditionIssuing, cmmeta.ConditionTrue, reasonSecretWriteFailed, message)
+	if err := c.updateStatus(ctx, existing, crt); err != nil {
+		return utilerrors.NewAggregate([]error{writeErr, err})
+	}
+
+	c.recorder.Event(crt, corev1.EventTypeWarning, reasonSecretWriteFailed, message)
+
+	return writeErr
+}
+
+// updateStatus writes the status of crt, which was computed from existing,
+// with server-side apply if the ServerSideApply feature is enabled, and with
+// an update otherwise.
+func (c *controller) updateStatus(ctx context.Context, existing, crt *cmapi.Certificate) error {
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		_, err := apply.CertificateStatus(ctx, c.client, c.fieldManager, statusFields, existing, crt)
+		return err
+	}
+	_, err := c.client.CertmanagerV1alpha2().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	return err
+}
+
 // controllerWrapper wraps the `controller` structure to make it implement
 // the controllerpkg.queueingController interface
 type controllerWrapper struct {
@@ -366,7 +463,13 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 		ctx.Recorder,
 		ctx.Clock,
 		ctx.CertificateOptions,
+		ctx.ClusterResourceNamespace,
+		ctx.BackoffPersister,
+		ctx.FieldManagerFor(ControllerName),
 	)
+	if ctx.CertificateOptions.EnableSecretConsumerPatches {
+		ctrl.patcher = consumerpatch.NewPatcher(ctx.RESTConfig, ctx.Client.Discovery())
+	}
 	c.controller = ctrl
 
 	return queue, mustSync, nil
@@ -18,6 +18,7 @@ package issuing
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -53,6 +54,9 @@ func TestIssuingController(t *testing.T) {
 
 		certificate *cmapi.Certificate
 
+		// secretWriteErr is returned when creating or updating Secrets
+		secretWriteErr error
+
 		expectedErr bool
 	}
 
@@ -399,12 +403,82 @@ func TestIssuingController(t *testing.T) {
 					)),
 				},
 				ExpectedEvents: []string{
+					`Normal Issued Stored certificate revision 2 issued for Certificate "test"`,
 					"Normal Issuing The certificate has been successfully issued",
 				},
 			},
 			expectedErr: false,
 		},
 
+		"if certificate is in Issuing state, one CertificateRequests, and is ready, but storing the signed certificate fails, keep Issuing condition with the failure and retry": {
+			certificate:    exampleBundle.Certificate,
+			secretWriteErr: errors.New("etcdserver: request timed out"),
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(issuingCert),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestReady,
+						gen.AddCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
+						}),
+					)},
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      nextPrivateKeySecretName,
+							Namespace: exampleBundle.Certificate.Namespace,
+						},
+						Data: map[string][]byte{
+							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+						},
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						"status",
+						exampleBundle.Certificate.Namespace,
+						gen.CertificateFrom(issuingCert,
+							gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+								Type:    cmapi.CertificateConditionIssuing,
+								Status:  cmmeta.ConditionTrue,
+								Reason:  "SecretWriteFailed",
+								Message: `Failed to store certificate revision 2 in Secret "output", will retry: etcdserver: request timed out`,
+							}),
+						),
+					)),
+					testpkg.NewAction(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						exampleBundle.Certificate.Namespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: exampleBundle.Certificate.Namespace,
+								Name:      "output",
+								Annotations: map[string]string{
+									cmapi.CertificateNameKey:       "test",
+									cmapi.IssuerKindAnnotationKey:  "Issuer",
+									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
+									cmapi.CommonNameAnnotationKey:  "",
+									cmapi.AltNamesAnnotationKey:    "example.com",
+									cmapi.IPSANAnnotationKey:       "",
+									cmapi.URISANAnnotationKey:      "",
+								},
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertificateRequestReady.Status.Certificate,
+								corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+				ExpectedEvents: []string{
+					`Warning SecretWriteFailed Failed to store certificate revision 2 in Secret "output", will retry: etcdserver: request timed out`,
+				},
+			},
+			expectedErr: true,
+		},
+
 		"if certificate is in Issuing state, one CertificateRequests, and is ready, store the signed certificate, ca, and private key to an existing secret, and log an event": {
 			certificate: exampleBundle.Certificate,
 			builder: &testpkg.Builder{
@@ -473,6 +547,7 @@ func TestIssuingController(t *testing.T) {
 					)),
 				},
 				ExpectedEvents: []string{
+					`Normal Issued Stored certificate revision 2 issued for Certificate "test"`,
 					"Normal Issuing The certificate has been successfully issued",
 				},
 			},
@@ -532,6 +607,7 @@ func TestIssuingController(t *testing.T) {
 					)),
 				},
 				ExpectedEvents: []string{
+					`Normal Issued Stored temporary certificate for Certificate "test"`,
 					"Normal Issuing Issued temporary certificate",
 				},
 			},
@@ -603,6 +679,7 @@ func TestIssuingController(t *testing.T) {
 					)),
 				},
 				ExpectedEvents: []string{
+					`Normal Issued Stored temporary certificate for Certificate "test"`,
 					"Normal Issuing Issued temporary certificate",
 				},
 			},
@@ -721,6 +798,7 @@ func TestIssuingController(t *testing.T) {
 					)),
 				},
 				ExpectedEvents: []string{
+					`Normal Issued Stored temporary certificate for Certificate "test"`,
 					"Normal Issuing Issued temporary certificate",
 				},
 			},
@@ -835,6 +913,7 @@ func TestIssuingController(t *testing.T) {
 					)),
 				},
 				ExpectedEvents: []string{
+					`Normal Issued Stored certificate revision 2 issued for Certificate "test"`,
 					"Normal Issuing The certificate has been successfully issued",
 				},
 			},
@@ -907,6 +986,14 @@ func TestIssuingController(t *testing.T) {
 			test.builder.Init()
 			defer test.builder.Stop()
 
+			if test.secretWriteErr != nil {
+				for _, verb := range []string{"create", "update"} {
+					test.builder.FakeKubeClient().PrependReactor(verb, "secrets", func(coretesting.Action) (bool, runtime.Object, error) {
+						return true, nil, test.secretWriteErr
+					})
+				}
+			}
+
 			// Instantiate/setup the controller
 			w := controllerWrapper{}
 			w.Register(test.builder.Context)
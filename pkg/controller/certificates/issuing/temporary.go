@@ -72,10 +72,12 @@ func (c *controller) ensureTemporaryCertificate(ctx context.Context, crt *cmapi.
 		Certificate: certData,
 		PrivateKey:  pkData,
 	}
-	if err := c.secretsManager.UpdateData(ctx, crt, secretData); err != nil {
+	secret, err := c.secretsManager.UpdateData(ctx, crt, secretData)
+	if err != nil {
 		return false, err
 	}
 
+	c.recorder.Eventf(secret, corev1.EventTypeNormal, reasonIssued, "Stored temporary certificate for Certificate %q", crt.Name)
 	c.recorder.Event(crt, corev1.EventTypeNormal, "Issuing", "Issued temporary certificate")
 
 	return true, nil
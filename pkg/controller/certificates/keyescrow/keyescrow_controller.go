@@ -0,0 +1,284 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyescrow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1alpha2"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+	"github.com/jetstack/cert-manager/pkg/issuer/keyescrow"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/pkg/util/predicate"
+)
+
+const (
+	ControllerName = "CertificateKeyEscrow"
+
+	// reasonKeyEscrowed and reasonKeyEscrowFailed are the reasons of the
+	// events fired on a Certificate when the private key stored in its
+	// Secret is delivered to the key escrow service of its issuer
+	reasonKeyEscrowed     = "KeyEscrowed"
+	reasonKeyEscrowFailed = "KeyEscrowFailed"
+)
+
+// This controller delivers the private keys stored in the Secrets of
+// Certificates to the key escrow service configured on their issuer.
+// Keys are delivered once they have been stored in the Secret, so escrow
+// never delays issuance, and failed deliveries are retried with back-off.
+// The fingerprint of the public key of a delivered key is recorded in an
+// annotation of the Secret, so that every key is delivered once.
+type controller struct {
+	certificateLister cmlisters.CertificateLister
+	secretLister      corelisters.SecretLister
+	issuerHelper      issuer.Helper
+	kubeClient        kubernetes.Interface
+	recorder          record.EventRecorder
+	issuerOptions     controllerpkg.IssuerOptions
+
+	// newClient constructs the client of the key escrow service, and can
+	// be replaced in tests
+	newClient func(escrow *cmapi.KeyEscrow, certPEM, keyPEM []byte) (keyescrow.Client, error)
+}
+
+func NewController(
+	log logr.Logger,
+	kubeClient kubernetes.Interface,
+	factory informers.SharedInformerFactory,
+	cmFactory cminformers.SharedInformerFactory,
+	recorder record.EventRecorder,
+	issuerOptions controllerpkg.IssuerOptions,
+	backoff *controllerpkg.BackoffPersister,
+	namespace string,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := controllerpkg.NewRateLimitingQueue(backoff, workqueue.NewItemExponentialFailureRateLimiter(time.Second*5, time.Minute*30), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	certificateInformer := cmFactory.Certmanager().V1alpha2().Certificates()
+	secretsInformer := factory.Core().V1().Secrets()
+	issuerInformer := cmFactory.Certmanager().V1alpha2().Issuers()
+
+	certificateInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+	// When a Secret resource changes, enqueue any Certificate resources that name it as spec.secretName.
+	secretsInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(),
+			predicate.ExtractResourceName(predicate.CertificateSecretName)),
+	})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		certificateInformer.Informer().HasSynced,
+		secretsInformer.Informer().HasSynced,
+		issuerInformer.Informer().HasSynced,
+	}
+
+	// ClusterIssuers can only be read if we are running in non-namespaced
+	// mode (i.e. --namespace="").
+	var clusterIssuerLister cmlisters.ClusterIssuerLister
+	if namespace == "" {
+		clusterIssuerInformer := cmFactory.Certmanager().V1alpha2().ClusterIssuers()
+		clusterIssuerLister = clusterIssuerInformer.Lister()
+		mustSync = append(mustSync, clusterIssuerInformer.Informer().HasSynced)
+	}
+
+	return &controller{
+		certificateLister: certificateInformer.Lister(),
+		secretLister:      secretsInformer.Lister(),
+		issuerHelper:      issuer.NewHelper(issuerInformer.Lister(), clusterIssuerLister),
+		kubeClient:        kubeClient,
+		recorder:          recorder,
+		issuerOptions:     issuerOptions,
+		newClient:         keyescrow.New,
+	}, queue, mustSync
+}
+
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	crt, err := c.certificateLister.Certificates(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if apiutil.IsPaused(crt) {
+		log.V(logf.DebugLevel).Info("certificate is paused, skipping processing")
+		return nil
+	}
+
+	// only the issuers of the cert-manager.io group can configure key escrow
+	if group := crt.Spec.IssuerRef.Group; group != "" && group != certmanager.GroupName {
+		return nil
+	}
+	iss, err := c.issuerHelper.GetGenericIssuer(crt.Spec.IssuerRef, crt.Namespace)
+	if err != nil {
+		// missing or invalid issuers are reported by the other controllers,
+		// and the Certificate is resynced periodically
+		log.V(logf.DebugLevel).Info("not escrowing private key as the issuer cannot be read", "error", err.Error())
+		return nil
+	}
+	escrow := iss.GetSpec().KeyEscrow
+	if escrow == nil {
+		return nil
+	}
+
+	secret, err := c.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// only escrow keys that cert-manager has stored for this Certificate,
+	// and not keys of Secrets that happen to have the same name
+	if secret.Annotations[cmapi.CertificateNameKey] != crt.Name || len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		return nil
+	}
+	pk, err := pki.DecodePrivateKeyBytes(secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		// invalid private keys are reported by the readiness controller
+		log.V(logf.DebugLevel).Info("not escrowing invalid private key", "error", err.Error())
+		return nil
+	}
+	fingerprint, err := keyescrow.Fingerprint(pk.Public())
+	if err != nil {
+		return err
+	}
+	if secret.Annotations[cmapi.EscrowedKeyFingerprintAnnotationKey] == fingerprint {
+		return nil
+	}
+
+	if err := c.deliver(ctx, iss, escrow, &keyescrow.Key{
+		Namespace:       crt.Namespace,
+		CertificateName: crt.Name,
+		SecretName:      secret.Name,
+		Fingerprint:     fingerprint,
+		PrivateKey:      pk,
+	}); err != nil {
+		log.Error(err, "failed to deliver private key to key escrow service")
+		c.recorder.Eventf(crt, corev1.EventTypeWarning, reasonKeyEscrowFailed, "Failed to deliver private key to the key escrow service of the issuer: %v", err)
+		return err
+	}
+
+	// the annotation is patched so that it does not conflict with the
+	// updates of the Secret by the issuing controller
+	patch, err := fingerprintPatch(fingerprint)
+	if err != nil {
+		return err
+	}
+	if _, err := c.kubeClient.CoreV1().Secrets(secret.Namespace).Patch(ctx, secret.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		// the key is delivered again on the next sync, which escrow
+		// services must tolerate
+		return err
+	}
+
+	log.Info("delivered private key to key escrow service", "fingerprint", fingerprint)
+	c.recorder.Eventf(crt, corev1.EventTypeNormal, reasonKeyEscrowed, "Delivered private key with fingerprint %s to the key escrow service of the issuer", fingerprint)
+	return nil
+}
+
+// fingerprintPatch returns a JSON merge patch that records fingerprint as
+// the fingerprint of the escrowed private key of a Secret.
+func fingerprintPatch(fingerprint string) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				cmapi.EscrowedKeyFingerprintAnnotationKey: fingerprint,
+			},
+		},
+	})
+}
+
+// deliver delivers key to the key escrow service configured on the issuer,
+// authenticating with the client certificate referenced by escrow.
+func (c *controller) deliver(ctx context.Context, iss cmapi.GenericIssuer, escrow *cmapi.KeyEscrow, key *keyescrow.Key) error {
+	ns := c.issuerOptions.ResourceNamespace(iss)
+	clientSecret, err := c.secretLister.Secrets(ns).Get(escrow.ClientCertSecretRef.Name)
+	if err != nil {
+		return fmt.Errorf("error reading client certificate Secret %s/%s: %v", ns, escrow.ClientCertSecretRef.Name, err)
+	}
+
+	client, err := c.newClient(escrow, clientSecret.Data[corev1.TLSCertKey], clientSecret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return err
+	}
+	return client.Deliver(ctx, key)
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log,
+		ctx.Client,
+		ctx.KubeSharedInformerFactory,
+		ctx.SharedInformerFactory,
+		ctx.Recorder,
+		ctx.IssuerOptions,
+		ctx.BackoffPersister,
+		ctx.Namespace,
+	)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
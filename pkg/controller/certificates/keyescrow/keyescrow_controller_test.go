@@ -0,0 +1,244 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyescrow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	coretesting "k8s.io/client-go/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/issuer/keyescrow"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+type fakeEscrowClient struct {
+	err       error
+	delivered []*keyescrow.Key
+}
+
+func (f *fakeEscrowClient) Deliver(ctx context.Context, key *keyescrow.Key) error {
+	f.delivered = append(f.delivered, key)
+	return f.err
+}
+
+func TestProcessItem(t *testing.T) {
+	pk, err := pki.GenerateECPrivateKey(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM, err := pki.EncodeECPrivateKey(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint, err := keyescrow.Fingerprint(pk.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	escrow := cmapi.KeyEscrow{
+		HTTPS:               &cmapi.HTTPSKeyEscrow{URL: "https://escrow.example.com/keys"},
+		ClientCertSecretRef: cmmeta.LocalObjectReference{Name: "escrow-client"},
+	}
+	baseIssuer := gen.Issuer("ca", gen.SetIssuerNamespace("testns"))
+	escrowIssuer := gen.IssuerFrom(baseIssuer, gen.SetIssuerKeyEscrow(escrow))
+	crt := gen.Certificate("test",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateSecretName("test-tls"),
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca", Kind: cmapi.IssuerKind}),
+	)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "testns",
+			Name:        "test-tls",
+			Annotations: map[string]string{cmapi.CertificateNameKey: "test"},
+		},
+		Data: map[string][]byte{corev1.TLSPrivateKeyKey: keyPEM},
+	}
+	escrowedSecret := secret.DeepCopy()
+	escrowedSecret.Annotations[cmapi.EscrowedKeyFingerprintAnnotationKey] = fingerprint
+	clientSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "escrow-client"},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("cert"),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+		},
+	}
+
+	tests := map[string]struct {
+		certificate *cmapi.Certificate
+		issuer      *cmapi.Issuer
+		secrets     []runtime.Object
+		deliveryErr error
+
+		expectedDelivery bool
+		expectedPatch    bool
+		expectedEvents   []string
+		expectedErr      bool
+	}{
+		"do nothing if the issuer does not configure key escrow": {
+			issuer:  baseIssuer,
+			secrets: []runtime.Object{secret, clientSecret},
+		},
+		"do nothing if the Secret does not exist": {
+			issuer:  escrowIssuer,
+			secrets: []runtime.Object{clientSecret},
+		},
+		"do nothing if the Secret does not belong to the Certificate": {
+			issuer: escrowIssuer,
+			secrets: []runtime.Object{
+				func() *corev1.Secret {
+					s := secret.DeepCopy()
+					s.Annotations = nil
+					return s
+				}(),
+				clientSecret,
+			},
+		},
+		"do nothing if the Certificate is paused": {
+			issuer:  escrowIssuer,
+			secrets: []runtime.Object{secret, clientSecret},
+			certificate: gen.CertificateFrom(crt,
+				gen.AddCertificateAnnotations(map[string]string{cmapi.PausedAnnotationKey: "true"}),
+			),
+		},
+		"do nothing if the key has already been escrowed": {
+			issuer:  escrowIssuer,
+			secrets: []runtime.Object{escrowedSecret, clientSecret},
+		},
+		"deliver the key and record its fingerprint on the Secret": {
+			issuer:           escrowIssuer,
+			secrets:          []runtime.Object{secret, clientSecret},
+			expectedDelivery: true,
+			expectedPatch:    true,
+			expectedEvents: []string{
+				"Normal KeyEscrowed Delivered private key with fingerprint " + fingerprint + " to the key escrow service of the issuer",
+			},
+		},
+		"deliver the key again if it has changed since it was escrowed": {
+			issuer: escrowIssuer,
+			secrets: []runtime.Object{
+				func() *corev1.Secret {
+					s := escrowedSecret.DeepCopy()
+					s.Annotations[cmapi.EscrowedKeyFingerprintAnnotationKey] = "previous"
+					return s
+				}(),
+				clientSecret,
+			},
+			expectedDelivery: true,
+			expectedPatch:    true,
+			expectedEvents: []string{
+				"Normal KeyEscrowed Delivered private key with fingerprint " + fingerprint + " to the key escrow service of the issuer",
+			},
+		},
+		"fire an event and retry if the delivery fails": {
+			issuer:           escrowIssuer,
+			secrets:          []runtime.Object{secret, clientSecret},
+			deliveryErr:      errors.New("unavailable"),
+			expectedDelivery: true,
+			expectedEvents: []string{
+				"Warning KeyEscrowFailed Failed to deliver private key to the key escrow service of the issuer: unavailable",
+			},
+			expectedErr: true,
+		},
+		"fire an event and retry if the client certificate Secret does not exist": {
+			issuer:  escrowIssuer,
+			secrets: []runtime.Object{secret},
+			expectedEvents: []string{
+				`Warning KeyEscrowFailed Failed to deliver private key to the key escrow service of the issuer: error reading client certificate Secret testns/escrow-client: secret "escrow-client" not found`,
+			},
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			certificate := crt
+			if test.certificate != nil {
+				certificate = test.certificate
+			}
+			builder := &testpkg.Builder{
+				T:                  t,
+				CertManagerObjects: []runtime.Object{certificate, test.issuer},
+				KubeObjects:        test.secrets,
+				ExpectedEvents:     test.expectedEvents,
+				Context: &controllerpkg.Context{
+					RootContext: context.Background(),
+				},
+			}
+			if test.expectedPatch {
+				builder.ExpectedActions = append(builder.ExpectedActions,
+					testpkg.NewAction(coretesting.NewPatchAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						"testns",
+						"test-tls",
+						types.MergePatchType,
+						[]byte(`{"metadata":{"annotations":{"cert-manager.io/escrowed-key-fingerprint":"`+fingerprint+`"}}}`),
+					)),
+				)
+			}
+			builder.Init()
+			defer builder.Stop()
+
+			w := &controllerWrapper{}
+			if _, _, err := w.Register(builder.Context); err != nil {
+				t.Fatal(err)
+			}
+			client := &fakeEscrowClient{err: test.deliveryErr}
+			w.newClient = func(escrow *cmapi.KeyEscrow, certPEM, keyPEM []byte) (keyescrow.Client, error) {
+				if string(certPEM) != "cert" || string(keyPEM) != "key" {
+					t.Errorf("unexpected client certificate %q and key %q", certPEM, keyPEM)
+				}
+				return client, nil
+			}
+			builder.Start()
+
+			err := w.ProcessItem(context.Background(), "testns/test")
+			if (err != nil) != test.expectedErr {
+				t.Errorf("expected error %t, got: %v", test.expectedErr, err)
+			}
+
+			if !test.expectedDelivery {
+				if len(client.delivered) > 0 {
+					t.Errorf("expected no key to be delivered, got %d", len(client.delivered))
+				}
+			} else if len(client.delivered) != 1 {
+				t.Errorf("expected one key to be delivered, got %d", len(client.delivered))
+			} else {
+				key := client.delivered[0]
+				if key.Namespace != "testns" || key.CertificateName != "test" || key.SecretName != "test-tls" || key.Fingerprint != fingerprint {
+					t.Errorf("unexpected key delivered: %+v", key)
+				}
+				if equal, err := pki.PublicKeysEqual(key.PrivateKey.Public(), pk.Public()); err != nil || !equal {
+					t.Errorf("delivered private key does not match the Secret")
+				}
+			}
+
+			builder.CheckAndFinish()
+		})
+	}
+}
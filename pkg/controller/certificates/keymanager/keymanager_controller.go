@@ -43,7 +43,10 @@ import (
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1alpha2"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/feature"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/apply"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
 )
@@ -62,6 +65,16 @@ type controller struct {
 	client            cmclient.Interface
 	coreClient        kubernetes.Interface
 	recorder          record.EventRecorder
+
+	// fieldManager is the field manager used to apply the status of
+	// Certificates if the ServerSideApply feature is enabled
+	fieldManager string
+}
+
+// statusFields are the status fields of Certificates that are managed by this
+// controller.
+var statusFields = apply.Fields{
+	Status: []string{"nextPrivateKeySecretName"},
 }
 
 func NewController(
@@ -71,9 +84,11 @@ func NewController(
 	factory informers.SharedInformerFactory,
 	cmFactory cminformers.SharedInformerFactory,
 	recorder record.EventRecorder,
+	backoff *controllerpkg.BackoffPersister,
+	fieldManager string,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 	// create a queue used to queue up items to be processed
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+	queue := controllerpkg.NewRateLimitingQueue(backoff, workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	certificateInformer := cmFactory.Certmanager().V1alpha2().Certificates()
@@ -107,6 +122,7 @@ func NewController(
 		client:            client,
 		coreClient:        coreClient,
 		recorder:          recorder,
+		fieldManager:      fieldManager,
 	}, queue, mustSync
 }
 
@@ -141,6 +157,11 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return err
 	}
 
+	if apiutil.IsPaused(crt) {
+		log.V(logf.DebugLevel).Info("certificate is paused, skipping processing")
+		return nil
+	}
+
 	// Discover all 'owned' secrets that have the `next-private-key` label
 	secrets, err := certificates.ListSecretsMatchingPredicates(c.secretLister.Secrets(crt.Namespace), isNextPrivateKeyLabelSelector, predicate.ResourceOwnedBy(crt))
 	if err != nil {
@@ -164,6 +185,10 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		if crt.Spec.PrivateKey != nil && crt.Spec.PrivateKey.RotationPolicy != "" {
 			rotationPolicy = crt.Spec.PrivateKey.RotationPolicy
 		}
+		if apiutil.IsPrivateKeyRotationRequested(crt) {
+			log.V(logf.DebugLevel).Info("Generating new private key as rotation has been requested for this issuance")
+			rotationPolicy = cmapi.RotationPolicyAlways
+		}
 		switch rotationPolicy {
 		case cmapi.RotationPolicyNever:
 			return c.createNextPrivateKeyRotationPolicyNever(ctx, crt)
@@ -299,8 +324,13 @@ func (c *controller) setNextPrivateKeySecretName(ctx context.Context, crt *cmapi
 			return nil
 		}
 	}
+	existing := crt
 	crt = crt.DeepCopy()
 	crt.Status.NextPrivateKeySecretName = name
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		_, err := apply.CertificateStatus(ctx, c.client, c.fieldManager, statusFields, existing, crt)
+		return err
+	}
 	_, err := c.client.CertmanagerV1alpha2().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
 	return err
 }
@@ -326,6 +356,11 @@ func (c *controller) createNewPrivateKeySecret(ctx context.Context, crt *cmapi.C
 			Labels: map[string]string{
 				"cert-manager.io/next-private-key": "true",
 			},
+			// the name of the Certificate is recorded so that the Secret can be
+			// attributed to it even if its owner references have been removed
+			Annotations: map[string]string{
+				cmapi.CertificateNameKey: crt.Name,
+			},
 		},
 		Data: map[string][]byte{
 			corev1.TLSPrivateKeyKey: pkData,
@@ -358,6 +393,8 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 		ctx.KubeSharedInformerFactory,
 		ctx.SharedInformerFactory,
 		ctx.Recorder,
+		ctx.BackoffPersister,
+		ctx.FieldManagerFor(ControllerName),
 	)
 	c.controller = ctrl
 
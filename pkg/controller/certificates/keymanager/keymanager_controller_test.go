@@ -182,6 +182,66 @@ func TestProcessItem(t *testing.T) {
 							Namespace:       "testns",
 							GenerateName:    "test-",
 							Labels:          map[string]string{cmapi.IsNextPrivateKeySecretLabelKey: "true"},
+							Annotations:     map[string]string{cmapi.CertificateNameKey: "test"},
+							OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"}}, certificateGvk)},
+						},
+						Data: map[string][]byte{"tls.key": nil},
+					},
+				), relaxedSecretMatcher),
+			},
+		},
+		"create a secret with a new private key instead of reusing the existing one if rotation is requested": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", Annotations: map[string]string{
+					cmapi.RotatePrivateKeyAnnotationKey: "1",
+				}},
+				Spec: cmapi.CertificateSpec{SecretName: "output"},
+				Status: cmapi.CertificateStatus{
+					Conditions: []cmapi.CertificateCondition{
+						{
+							Type:   cmapi.CertificateConditionIssuing,
+							Status: cmmeta.ConditionTrue,
+						},
+					},
+				},
+			},
+			secrets: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "output"},
+					Data:       map[string][]byte{"tls.key": mustGenerateRSA(t, 2048)},
+				},
+			},
+			expectedEvents: []string{`Normal Generated Stored new private key in temporary Secret resource "test-notrandom"`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+					cmapi.SchemeGroupVersion.WithResource("certificates"),
+					"status",
+					"testns",
+					&cmapi.Certificate{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", Annotations: map[string]string{
+							cmapi.RotatePrivateKeyAnnotationKey: "1",
+						}},
+						Spec: cmapi.CertificateSpec{SecretName: "output"},
+						Status: cmapi.CertificateStatus{
+							NextPrivateKeySecretName: pointer.StringPtr("test-notrandom"),
+							Conditions: []cmapi.CertificateCondition{
+								{
+									Type:   cmapi.CertificateConditionIssuing,
+									Status: cmmeta.ConditionTrue,
+								},
+							},
+						},
+					},
+				)),
+				testpkg.NewCustomMatch(coretesting.NewCreateAction(
+					corev1.SchemeGroupVersion.WithResource("secrets"),
+					"testns",
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace:       "testns",
+							GenerateName:    "test-",
+							Labels:          map[string]string{cmapi.IsNextPrivateKeySecretLabelKey: "true"},
+							Annotations:     map[string]string{cmapi.CertificateNameKey: "test"},
 							OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"}}, certificateGvk)},
 						},
 						Data: map[string][]byte{"tls.key": nil},
@@ -212,6 +272,7 @@ func TestProcessItem(t *testing.T) {
 							Namespace:       "testns",
 							Name:            "fixed-name",
 							Labels:          map[string]string{cmapi.IsNextPrivateKeySecretLabelKey: "true"},
+							Annotations:     map[string]string{cmapi.CertificateNameKey: "test"},
 							OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"}}, certificateGvk)},
 						},
 						Data: map[string][]byte{"tls.key": nil},
@@ -244,6 +305,7 @@ func TestProcessItem(t *testing.T) {
 							Namespace:       "testns",
 							Name:            "fixed-name",
 							Labels:          map[string]string{cmapi.IsNextPrivateKeySecretLabelKey: "true"},
+							Annotations:     map[string]string{cmapi.CertificateNameKey: "test"},
 							OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"}}, certificateGvk)},
 						},
 						Data: map[string][]byte{"tls.key": nil},
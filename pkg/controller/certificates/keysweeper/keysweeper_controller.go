@@ -0,0 +1,270 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keysweeper
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1alpha2"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/scheduler"
+)
+
+const (
+	ControllerName = "CertificateKeySweeper"
+
+	// reasonDeleted is the reason of the event fired on a Certificate when a
+	// temporary private key Secret created for it is swept
+	reasonDeleted = "Deleted"
+)
+
+var certificateGvk = cmapi.SchemeGroupVersion.WithKind("Certificate")
+
+// This controller deletes the temporary 'next private key' Secret resources
+// created by the keymanager controller once they are older than the
+// configured TTL and no longer in use by an issuance in progress.
+// The keymanager controller only cleans up the Secrets of Certificates that
+// it reconciles, so this guarantees that Secrets are not left behind when an
+// issuance is aborted, e.g. because the Certificate was paused or deleted
+// after the owner references of the Secret had been removed.
+type controller struct {
+	certificateLister  cmlisters.CertificateLister
+	secretLister       corelisters.SecretLister
+	kubeClient         kubernetes.Interface
+	recorder           record.EventRecorder
+	clock              clock.Clock
+	scheduledWorkQueue scheduler.ScheduledWorkQueue
+
+	// the amount of time after its creation that a Secret is deleted
+	// unless it is in use
+	ttl time.Duration
+}
+
+func NewController(
+	log logr.Logger,
+	kubeClient kubernetes.Interface,
+	factory informers.SharedInformerFactory,
+	cmFactory cminformers.SharedInformerFactory,
+	recorder record.EventRecorder,
+	clock clock.Clock,
+	ttl time.Duration,
+	backoff *controllerpkg.BackoffPersister,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := controllerpkg.NewRateLimitingQueue(backoff, workqueue.NewItemExponentialFailureRateLimiter(time.Second*5, time.Minute*5), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	certificateInformer := cmFactory.Certmanager().V1alpha2().Certificates()
+	secretsInformer := factory.Core().V1().Secrets()
+
+	secretsInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: func(obj interface{}) {
+			secret, ok := obj.(*corev1.Secret)
+			if !ok || !isNextPrivateKeySecret(secret) {
+				return
+			}
+			key, err := controllerpkg.KeyFunc(secret)
+			if err != nil {
+				log.Error(err, "error computing key for resource")
+				return
+			}
+			queue.Add(key)
+		},
+	})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		certificateInformer.Informer().HasSynced,
+		secretsInformer.Informer().HasSynced,
+	}
+
+	return &controller{
+		certificateLister:  certificateInformer.Lister(),
+		secretLister:       secretsInformer.Lister(),
+		kubeClient:         kubeClient,
+		recorder:           recorder,
+		clock:              clock,
+		scheduledWorkQueue: scheduler.NewScheduledWorkQueue(clock, queue.Add),
+		ttl:                ttl,
+	}, queue, mustSync
+}
+
+func isNextPrivateKeySecret(secret *corev1.Secret) bool {
+	return secret.Labels[cmapi.IsNextPrivateKeySecretLabelKey] == "true"
+}
+
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	secret, err := c.secretLister.Secrets(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		c.scheduledWorkQueue.Forget(key)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !isNextPrivateKeySecret(secret) || secret.DeletionTimestamp != nil {
+		return nil
+	}
+
+	// Secrets are never swept before their TTL has passed, so that a Secret
+	// that has just been created is not deleted before the keymanager
+	// controller has named it on the Certificate.
+	age := c.clock.Since(secret.CreationTimestamp.Time)
+	if age < c.ttl {
+		log.V(logf.DebugLevel).Info("scheduling next private key Secret to be checked once its ttl has passed", "ttl", c.ttl)
+		c.scheduledWorkQueue.Add(key, c.ttl-age)
+		return nil
+	}
+
+	crt, reason, err := c.owningCertificate(secret)
+	if err != nil {
+		return err
+	}
+	if crt != nil && apiutil.IsPaused(crt) {
+		// check the Secret again after another ttl, as the Certificate may
+		// have been resumed by then
+		log.V(logf.DebugLevel).Info("certificate is paused, not sweeping its next private key Secret")
+		c.scheduledWorkQueue.Add(key, c.ttl)
+		return nil
+	}
+	if crt != nil && isInUse(crt, secret) {
+		// check the Secret again after another ttl, in case the issuance
+		// is aborted without the Certificate being reconciled again
+		log.V(logf.DebugLevel).Info("next private key Secret is in use by an issuance in progress")
+		c.scheduledWorkQueue.Add(key, c.ttl)
+		return nil
+	}
+	if crt != nil {
+		reason = "it is not in use by an issuance in progress"
+	}
+
+	if err := c.kubeClient.CoreV1().Secrets(secret.Namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	log.Info("deleted next private key Secret as its ttl has passed", "reason", reason)
+	if crt != nil {
+		c.recorder.Eventf(crt, corev1.EventTypeNormal, reasonDeleted, "Deleted temporary private key Secret %q as %s", secret.Name, reason)
+	}
+
+	return nil
+}
+
+// owningCertificate returns the Certificate that the given Secret was created
+// for. If it cannot be found, it returns the reason why instead.
+// The Certificate is determined by the controller reference of the Secret, or
+// by its certificate name annotation if the owner references have been
+// removed, e.g. because the Secret was restored from a backup.
+func (c *controller) owningCertificate(secret *corev1.Secret) (*cmapi.Certificate, string, error) {
+	name := secret.Annotations[cmapi.CertificateNameKey]
+	ref := metav1.GetControllerOf(secret)
+	if ref != nil {
+		if ref.APIVersion != certificateGvk.GroupVersion().String() || ref.Kind != certificateGvk.Kind {
+			return nil, "it is not owned by a Certificate", nil
+		}
+		name = ref.Name
+	}
+	if name == "" {
+		return nil, "it is not owned by a Certificate", nil
+	}
+
+	crt, err := c.certificateLister.Certificates(secret.Namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil, "its Certificate has been deleted", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if ref != nil && ref.UID != crt.UID {
+		// the Certificate has been re-created with the same name
+		return nil, "its Certificate has been deleted", nil
+	}
+
+	return crt, "", nil
+}
+
+// isInUse returns true if the given Secret is named as the next private key
+// of the Certificate and an issuance is in progress.
+func isInUse(crt *cmapi.Certificate, secret *corev1.Secret) bool {
+	if crt.Status.NextPrivateKeySecretName == nil || *crt.Status.NextPrivateKeySecretName != secret.Name {
+		return false
+	}
+	return apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
+		Type:   cmapi.CertificateConditionIssuing,
+		Status: cmmeta.ConditionTrue,
+	})
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log,
+		ctx.Client,
+		ctx.KubeSharedInformerFactory,
+		ctx.SharedInformerFactory,
+		ctx.Recorder,
+		ctx.Clock,
+		ctx.CertificateOptions.NextPrivateKeySecretTTL,
+		ctx.BackoffPersister,
+	)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keysweeper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestProcessItem(t *testing.T) {
+	now := time.Now()
+	ttl := time.Hour
+
+	crt := gen.Certificate("test",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateUID("uid"),
+		gen.SetCertificateNextPrivateKeySecretName("test-abcde"),
+	)
+	issuing := gen.CertificateFrom(crt,
+		gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue}),
+	)
+
+	secret := func(age time.Duration, owner *cmapi.Certificate, annotations map[string]string) *corev1.Secret {
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "testns",
+				Name:              "test-abcde",
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+				Labels:            map[string]string{cmapi.IsNextPrivateKeySecretLabelKey: "true"},
+				Annotations:       annotations,
+			},
+		}
+		if owner != nil {
+			s.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(owner, certificateGvk)}
+		}
+		return s
+	}
+	annotated := map[string]string{cmapi.CertificateNameKey: "test"}
+
+	tests := map[string]struct {
+		secret      *corev1.Secret
+		certificate *cmapi.Certificate
+
+		expectDelete   bool
+		expectedEvents []string
+	}{
+		"do not delete a Secret before its ttl has passed": {
+			secret:      secret(time.Minute, crt, annotated),
+			certificate: crt,
+		},
+		"do not delete a Secret that is not labelled as a next private key": {
+			secret: func() *corev1.Secret {
+				s := secret(2*ttl, nil, nil)
+				s.Labels = nil
+				return s
+			}(),
+		},
+		"do not delete a Secret in use by an issuance in progress": {
+			secret:      secret(2*ttl, crt, annotated),
+			certificate: issuing,
+		},
+		"do not delete a Secret of a paused Certificate": {
+			secret:      secret(2*ttl, crt, annotated),
+			certificate: gen.CertificateFrom(crt, gen.AddCertificateAnnotations(map[string]string{cmapi.PausedAnnotationKey: "true"})),
+		},
+		"delete a Secret that is not in use once its ttl has passed": {
+			secret:         secret(2*ttl, crt, annotated),
+			certificate:    crt,
+			expectDelete:   true,
+			expectedEvents: []string{`Normal Deleted Deleted temporary private key Secret "test-abcde" as it is not in use by an issuance in progress`},
+		},
+		"delete a Secret not named on an issuing Certificate": {
+			secret:         secret(2*ttl, crt, annotated),
+			certificate:    gen.CertificateFrom(issuing, gen.SetCertificateNextPrivateKeySecretName("test-fghij")),
+			expectDelete:   true,
+			expectedEvents: []string{`Normal Deleted Deleted temporary private key Secret "test-abcde" as it is not in use by an issuance in progress`},
+		},
+		"delete a Secret of a Certificate that has been deleted": {
+			secret:       secret(2*ttl, crt, annotated),
+			expectDelete: true,
+		},
+		"delete a Secret of a Certificate that has been re-created": {
+			secret:       secret(2*ttl, crt, annotated),
+			certificate:  gen.CertificateFrom(issuing, gen.SetCertificateUID("other-uid")),
+			expectDelete: true,
+		},
+		"use the certificate name annotation if the owner references have been removed": {
+			secret:      secret(2*ttl, nil, annotated),
+			certificate: issuing,
+		},
+		"delete a Secret without owner references or certificate name annotation": {
+			secret:       secret(2*ttl, nil, nil),
+			certificate:  issuing,
+			expectDelete: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:              t,
+				Clock:          fakeclock.NewFakeClock(now),
+				KubeObjects:    []runtime.Object{test.secret},
+				ExpectedEvents: test.expectedEvents,
+				Context: &controllerpkg.Context{
+					RootContext: context.Background(),
+					CertificateOptions: controllerpkg.CertificateOptions{
+						NextPrivateKeySecretTTL: ttl,
+					},
+				},
+			}
+			if test.certificate != nil {
+				builder.CertManagerObjects = append(builder.CertManagerObjects, test.certificate)
+			}
+			if test.expectDelete {
+				builder.ExpectedActions = append(builder.ExpectedActions,
+					testpkg.NewAction(coretesting.NewDeleteAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						test.secret.Namespace,
+						test.secret.Name,
+					)),
+				)
+			}
+			builder.Init()
+			defer builder.Stop()
+
+			w := &controllerWrapper{}
+			if _, _, err := w.Register(builder.Context); err != nil {
+				t.Fatal(err)
+			}
+			builder.Start()
+
+			if err := w.ProcessItem(context.Background(), "testns/test-abcde"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			builder.CheckAndFinish()
+		})
+	}
+}
@@ -18,10 +18,10 @@ package readiness
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
-	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -38,9 +38,12 @@ import (
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1alpha2"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/internal/certcache"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
+	"github.com/jetstack/cert-manager/pkg/feature"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
-	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/pkg/util/apply"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
 )
 
@@ -56,6 +59,13 @@ var PolicyChain = policies.Chain{
 	policies.CurrentCertificateHasExpired,
 }
 
+// statusFields are the status fields and conditions of Certificates that are
+// managed by this controller.
+var statusFields = apply.Fields{
+	Status:     []string{"notBefore", "notAfter", "renewalTime"},
+	Conditions: []string{string(cmapi.CertificateConditionReady), string(cmapi.CertificateConditionDeprecated)},
+}
+
 type controller struct {
 	// the policies to use to define readiness - named here to make testing simpler
 	policyChain              policies.Chain
@@ -64,6 +74,22 @@ type controller struct {
 	secretLister             corelisters.SecretLister
 	client                   cmclient.Interface
 	gatherer                 *policies.Gatherer
+
+	// renewalJitterPercent and renewalJitterMax bound the jitter applied to
+	// the renewal time of certificates
+	renewalJitterPercent int
+	renewalJitterMax     time.Duration
+
+	// if true, invalid Certificates are reported as not Ready
+	validateCertificates bool
+
+	// fieldManager is the field manager of the controller, whose changes
+	// are not reported as use of deprecated APIs
+	fieldManager string
+
+	// statusFieldManager is the field manager used to apply the status of
+	// Certificates if the ServerSideApply feature is enabled
+	statusFieldManager string
 }
 
 func NewController(
@@ -72,9 +98,13 @@ func NewController(
 	factory informers.SharedInformerFactory,
 	cmFactory cminformers.SharedInformerFactory,
 	chain policies.Chain,
+	certificateControllerOptions controllerpkg.CertificateOptions,
+	backoff *controllerpkg.BackoffPersister,
+	fieldManager string,
+	statusFieldManager string,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 	// create a queue used to queue up items to be processed
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+	queue := controllerpkg.NewRateLimitingQueue(backoff, workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	certificateInformer := cmFactory.Certmanager().V1alpha2().Certificates()
@@ -93,6 +123,10 @@ func NewController(
 		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(),
 			predicate.ExtractResourceName(predicate.CertificateSecretName)),
 	})
+	// Decoded certificates are cached per Secret resourceVersion, and
+	// dropped again when the Secret is deleted.
+	certCache := certcache.New()
+	secretsInformer.Informer().AddEventHandler(certCache.EventHandler())
 
 	// build a list of InformerSynced functions that will be returned by the Register method.
 	// the controller will only begin processing items once all of these informers have synced.
@@ -111,7 +145,13 @@ func NewController(
 		gatherer: &policies.Gatherer{
 			CertificateRequestLister: certificateRequestInformer.Lister(),
 			SecretLister:             secretsInformer.Lister(),
+			CertificateCache:         certCache,
 		},
+		renewalJitterPercent: certificateControllerOptions.RenewalJitterPercent,
+		renewalJitterMax:     certificateControllerOptions.RenewalJitterMax,
+		validateCertificates: certificateControllerOptions.ValidateCertificates,
+		fieldManager:         fieldManager,
+		statusFieldManager:   statusFieldManager,
 	}, queue, mustSync
 }
 
@@ -134,19 +174,36 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return err
 	}
 
+	if apiutil.IsPaused(crt) {
+		log.V(logf.DebugLevel).Info("certificate is paused, skipping processing")
+		return nil
+	}
+
 	input, err := c.gatherer.DataForCertificate(ctx, crt)
 	if err != nil {
 		return err
 	}
 
 	condition := readyCondition(c.policyChain, input)
+	if c.validateCertificates {
+		if el := certificates.ValidateCertificate(crt); len(el) > 0 {
+			condition = cmapi.CertificateCondition{
+				Type:    cmapi.CertificateConditionReady,
+				Status:  cmmeta.ConditionFalse,
+				Reason:  certificates.ReasonBadConfig,
+				Message: fmt.Sprintf("Resource validation failed: %v", el.ToAggregate()),
+			}
+		}
+	}
 
+	existing := crt
 	crt = crt.DeepCopy()
 	apiutil.SetCertificateCondition(crt, condition.Type, condition.Status, condition.Reason, condition.Message)
+	apiutil.SetCertificateDeprecatedCondition(crt, c.fieldManager)
 
 	switch {
 	case input.Secret != nil && input.Secret.Data != nil:
-		x509cert, err := pki.DecodeX509CertificateBytes(input.Secret.Data[corev1.TLSCertKey])
+		x509cert, err := c.gatherer.CertificateCache.Certificate(input.Secret)
 		if err != nil {
 			// clear status fields if we cannot decode the certificate bytes
 			crt.Status.NotAfter = nil
@@ -162,7 +219,13 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		// calculate how long before the certificate expiry time the certificate
 		// should be renewed
 		renewBefore := certificates.RenewBeforeExpiryDuration(crt.Status.NotBefore.Time, crt.Status.NotAfter.Time, crt.Spec.RenewBefore)
-		renewalTime := metav1.NewTime(notAfter.Add(-1 * renewBefore))
+		// bring the renewal forward by a jitter derived from the Certificate
+		// and the issued certificate, but never before the certificate is valid
+		jitter := certificates.RenewalJitter(string(crt.UID)+"/"+x509cert.SerialNumber.String(), renewBefore, c.renewalJitterPercent, c.renewalJitterMax)
+		renewalTime := metav1.NewTime(notAfter.Add(-1 * (renewBefore + jitter)))
+		if renewalTime.Before(&notBefore) {
+			renewalTime = notBefore
+		}
 		crt.Status.RenewalTime = &renewalTime
 	default:
 		// clear status fields if the secret does not have any data
@@ -171,6 +234,11 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		crt.Status.RenewalTime = nil
 	}
 
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		_, err = apply.CertificateStatus(ctx, c.client, c.statusFieldManager, statusFields, existing, crt)
+		return err
+	}
+
 	_, err = c.client.CertmanagerV1alpha2().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
 	if err != nil {
 		return err
@@ -212,6 +280,10 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 		ctx.KubeSharedInformerFactory,
 		ctx.SharedInformerFactory,
 		PolicyChain,
+		ctx.CertificateOptions,
+		ctx.BackoffPersister,
+		ctx.FieldManager(),
+		ctx.FieldManagerFor(ControllerName),
 	)
 	c.controller = ctrl
 
@@ -30,6 +30,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -38,6 +39,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
@@ -45,7 +47,10 @@ import (
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1alpha2"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/feature"
+	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	utilpkg "github.com/jetstack/cert-manager/pkg/util"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
 )
@@ -62,6 +67,7 @@ type controller struct {
 	certificateLister        cmlisters.CertificateLister
 	certificateRequestLister cmlisters.CertificateRequestLister
 	secretLister             corelisters.SecretLister
+	issuerHelper             issuer.Helper
 	client                   cmclient.Interface
 	recorder                 record.EventRecorder
 }
@@ -72,14 +78,17 @@ func NewController(
 	factory informers.SharedInformerFactory,
 	cmFactory cminformers.SharedInformerFactory,
 	recorder record.EventRecorder,
+	backoff *controllerpkg.BackoffPersister,
+	namespace string,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 	// create a queue used to queue up items to be processed
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+	queue := controllerpkg.NewRateLimitingQueue(backoff, workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	certificateInformer := cmFactory.Certmanager().V1alpha2().Certificates()
 	certificateRequestInformer := cmFactory.Certmanager().V1alpha2().CertificateRequests()
 	secretsInformer := factory.Core().V1().Secrets()
+	issuerInformer := cmFactory.Certmanager().V1alpha2().Issuers()
 
 	certificateInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
 	certificateRequestInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
@@ -101,12 +110,24 @@ func NewController(
 		secretsInformer.Informer().HasSynced,
 		certificateRequestInformer.Informer().HasSynced,
 		certificateInformer.Informer().HasSynced,
+		issuerInformer.Informer().HasSynced,
+	}
+
+	// issuers are only read to check whether they are able to honour the
+	// subjectAltNamesPolicy of a Certificate. ClusterIssuers can only be read
+	// if we are running in non-namespaced mode (i.e. --namespace="").
+	var clusterIssuerLister cmlisters.ClusterIssuerLister
+	if namespace == "" {
+		clusterIssuerInformer := cmFactory.Certmanager().V1alpha2().ClusterIssuers()
+		clusterIssuerLister = clusterIssuerInformer.Lister()
+		mustSync = append(mustSync, clusterIssuerInformer.Informer().HasSynced)
 	}
 
 	return &controller{
 		certificateLister:        certificateInformer.Lister(),
 		certificateRequestLister: certificateRequestInformer.Lister(),
 		secretLister:             secretsInformer.Lister(),
+		issuerHelper:             issuer.NewHelper(issuerInformer.Lister(), clusterIssuerLister),
 		client:                   client,
 		recorder:                 recorder,
 	}, queue, mustSync
@@ -131,6 +152,11 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return err
 	}
 
+	if apiutil.IsPaused(crt) {
+		log.V(logf.DebugLevel).Info("certificate is paused, skipping processing")
+		return nil
+	}
+
 	if !apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
 		Type:   cmapi.CertificateConditionIssuing,
 		Status: cmmeta.ConditionTrue,
@@ -295,6 +321,30 @@ func (c *controller) deleteRequestsNotMatchingSpec(ctx context.Context, crt *cma
 
 func (c *controller) createNewCertificateRequest(ctx context.Context, crt *cmapi.Certificate, pk crypto.Signer, nextRevision int, nextPrivateKeySecretName string) error {
 	log := logf.FromContext(ctx)
+
+	// The issuer is only consulted on a best effort basis; if it cannot be
+	// read, the CertificateRequest is created and its controller will report
+	// the problem with the issuer.
+	iss, err := c.issuerHelper.GetGenericIssuer(crt.Spec.IssuerRef, crt.Namespace)
+	// The lookup ignores the group of the issuerRef, so the type of the
+	// issuer is only recorded for issuers of the cert-manager.io group.
+	var issuerType string
+	if err == nil && (crt.Spec.IssuerRef.Group == "" || crt.Spec.IssuerRef.Group == certmanager.GroupName) {
+		issuerType, _ = apiutil.NameForIssuer(iss)
+	}
+	if err == nil && !crt.Spec.ExternalSigning {
+		if err := pki.ValidateSubjectAltNamesPolicyForIssuer(&crt.Spec, iss); err != nil {
+			log.Error(err, "Issuer does not support the subjectAltNamesPolicy of the certificate - will not retry")
+			c.recorder.Eventf(crt, corev1.EventTypeWarning, "UnsupportedSubjectAltNamesPolicy", "Not creating CertificateRequest: %v", err)
+			return nil
+		}
+		if err := pki.ValidateNameCountForIssuer(&crt.Spec, iss); err != nil {
+			log.Error(err, "Certificate requests more names than the issuer supports - will not retry")
+			c.recorder.Eventf(crt, corev1.EventTypeWarning, "TooManyNames", "Not creating CertificateRequest: %v", err)
+			return nil
+		}
+	}
+
 	x509CSR, err := pki.GenerateCSR(crt)
 	if err != nil {
 		log.Error(err, "Failed to generate CSR - will not retry")
@@ -318,13 +368,31 @@ func (c *controller) createNewCertificateRequest(ctx context.Context, crt *cmapi
 	annotations[cmapi.CertificateRequestRevisionAnnotationKey] = strconv.Itoa(nextRevision)
 	annotations[cmapi.CertificateRequestPrivateKeyAnnotationKey] = nextPrivateKeySecretName
 	annotations[cmapi.CertificateNameKey] = crt.Name
+	if crt.Spec.ExternalSigning {
+		annotations[cmapi.CertificateRequestExternalSigningAnnotationKey] = "true"
+	}
+	for k, v := range pipelineAnnotations(issuerType) {
+		annotations[k] = v
+	}
+
+	// Copy the labels so that the labels of the Certificate in the lister
+	// cache are not modified.
+	crLabels := make(map[string]string)
+	for k, v := range crt.Labels {
+		crLabels[k] = v
+	}
+	crLabels[cmapi.CertificateRequestRevisionLabelKey] = strconv.Itoa(nextRevision)
+	// Label values are limited to 63 characters, unlike resource names.
+	if len(validation.IsValidLabelValue(crt.Name)) == 0 {
+		crLabels[cmapi.CertificateNameKey] = crt.Name
+	}
 
 	cr := &cmapi.CertificateRequest{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:       crt.Namespace,
 			GenerateName:    crt.Name + "-",
 			Annotations:     annotations,
-			Labels:          crt.Labels,
+			Labels:          crLabels,
 			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(crt, certificateGvk)},
 		},
 		Spec: cmapi.CertificateRequestSpec{
@@ -341,12 +409,36 @@ func (c *controller) createNewCertificateRequest(ctx context.Context, crt *cmapi
 		return err
 	}
 	c.recorder.Eventf(crt, corev1.EventTypeNormal, "Requested", "Created new CertificateRequest resource %q", cr.Name)
+	if crt.Spec.ExternalSigning {
+		c.recorder.Eventf(crt, corev1.EventTypeNormal, "AwaitingExternalSigning", "CertificateRequest %q has to be signed externally and the signed certificate imported", cr.Name)
+	}
 	if err := c.waitForCertificateRequestToExist(cr.Namespace, cr.Name); err != nil {
 		return fmt.Errorf("failed whilst waiting for CertificateRequest to exist - this may indicate an apiserver running slowly. Request will be retried")
 	}
 	return nil
 }
 
+// pipelineAnnotations returns the annotations recording the version of the
+// controller, the type of the issuer and the state of the feature gates on
+// the CertificateRequests created by the controller. The issuer type is not
+// recorded if it is empty, e.g. because the issuer could not be read.
+func pipelineAnnotations(issuerType string) map[string]string {
+	v := utilpkg.VersionInfo()
+	version := v.GitVersion
+	if v.GitCommit != "" {
+		version += "+" + v.GitCommit
+	}
+
+	annotations := map[string]string{
+		cmapi.CertificateRequestControllerVersionAnnotationKey: version,
+		cmapi.CertificateRequestFeatureGatesAnnotationKey:      feature.Summary(),
+	}
+	if issuerType != "" {
+		annotations[cmapi.CertificateRequestIssuerTypeAnnotationKey] = issuerType
+	}
+	return annotations
+}
+
 func (c *controller) waitForCertificateRequestToExist(namespace, name string) error {
 	return wait.Poll(time.Millisecond*100, time.Second*5, func() (bool, error) {
 		_, err := c.certificateRequestLister.CertificateRequests(namespace).Get(name)
@@ -375,6 +467,8 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 		ctx.KubeSharedInformerFactory,
 		ctx.SharedInformerFactory,
 		ctx.Recorder,
+		ctx.BackoffPersister,
+		ctx.Namespace,
 	)
 	c.controller = ctrl
 
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/kr/pretty"
@@ -27,11 +28,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	coretesting "k8s.io/client-go/testing"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/feature"
+	utilpkg "github.com/jetstack/cert-manager/pkg/util"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/test/unit/gen"
 )
@@ -166,6 +172,9 @@ func TestProcessItem(t *testing.T) {
 							cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
 							cmapi.CertificateRequestRevisionAnnotationKey:   "1",
 						}),
+						gen.AddCertificateRequestLabels(map[string]string{
+							cmapi.CertificateRequestRevisionLabelKey: "1",
+						}),
 					)), relaxedCertificateRequestMatcher),
 			},
 		},
@@ -197,6 +206,9 @@ func TestProcessItem(t *testing.T) {
 							cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
 							cmapi.CertificateRequestRevisionAnnotationKey:   "1",
 						}),
+						gen.AddCertificateRequestLabels(map[string]string{
+							cmapi.CertificateRequestRevisionLabelKey: "1",
+						}),
 					)), relaxedCertificateRequestMatcher),
 			},
 		},
@@ -228,6 +240,9 @@ func TestProcessItem(t *testing.T) {
 							cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
 							cmapi.CertificateRequestRevisionAnnotationKey:   "1",
 						}),
+						gen.AddCertificateRequestLabels(map[string]string{
+							cmapi.CertificateRequestRevisionLabelKey: "1",
+						}),
 					)), relaxedCertificateRequestMatcher),
 			},
 		},
@@ -280,6 +295,9 @@ func TestProcessItem(t *testing.T) {
 							cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
 							cmapi.CertificateRequestRevisionAnnotationKey:   "1",
 						}),
+						gen.AddCertificateRequestLabels(map[string]string{
+							cmapi.CertificateRequestRevisionLabelKey: "1",
+						}),
 					)), relaxedCertificateRequestMatcher),
 			},
 		},
@@ -317,6 +335,9 @@ func TestProcessItem(t *testing.T) {
 							cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
 							cmapi.CertificateRequestRevisionAnnotationKey:   "1",
 						}),
+						gen.AddCertificateRequestLabels(map[string]string{
+							cmapi.CertificateRequestRevisionLabelKey: "1",
+						}),
 					)), relaxedCertificateRequestMatcher),
 			},
 		},
@@ -357,6 +378,9 @@ func TestProcessItem(t *testing.T) {
 							cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
 							cmapi.CertificateRequestRevisionAnnotationKey:   "1",
 						}),
+						gen.AddCertificateRequestLabels(map[string]string{
+							cmapi.CertificateRequestRevisionLabelKey: "1",
+						}),
 					)), relaxedCertificateRequestMatcher),
 			},
 		},
@@ -398,6 +422,9 @@ func TestProcessItem(t *testing.T) {
 							cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
 							cmapi.CertificateRequestRevisionAnnotationKey:   "6",
 						}),
+						gen.AddCertificateRequestLabels(map[string]string{
+							cmapi.CertificateRequestRevisionLabelKey: "6",
+						}),
 					)), relaxedCertificateRequestMatcher),
 			},
 		},
@@ -430,6 +457,9 @@ func TestProcessItem(t *testing.T) {
 							cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
 							cmapi.CertificateRequestRevisionAnnotationKey:   "6",
 						}),
+						gen.AddCertificateRequestLabels(map[string]string{
+							cmapi.CertificateRequestRevisionLabelKey: "6",
+						}),
 					)), relaxedCertificateRequestMatcher),
 			},
 		},
@@ -463,6 +493,9 @@ func TestProcessItem(t *testing.T) {
 							cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
 							cmapi.CertificateRequestRevisionAnnotationKey:   "6",
 						}),
+						gen.AddCertificateRequestLabels(map[string]string{
+							cmapi.CertificateRequestRevisionLabelKey: "6",
+						}),
 					)), relaxedCertificateRequestMatcher),
 			},
 		},
@@ -579,3 +612,31 @@ func TestProcessItem(t *testing.T) {
 		})
 	}
 }
+
+func TestPipelineAnnotations(t *testing.T) {
+	defer func(version, commit string) {
+		utilpkg.AppVersion, utilpkg.AppGitCommit = version, commit
+	}(utilpkg.AppVersion, utilpkg.AppGitCommit)
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, feature.ValidateCAA, true)()
+
+	utilpkg.AppVersion, utilpkg.AppGitCommit = "v0.16.0", "abcdef"
+	annotations := pipelineAnnotations(apiutil.IssuerACME)
+	if v := annotations[cmapi.CertificateRequestControllerVersionAnnotationKey]; v != "v0.16.0+abcdef" {
+		t.Errorf("unexpected controller version %q", v)
+	}
+	if v := annotations[cmapi.CertificateRequestIssuerTypeAnnotationKey]; v != apiutil.IssuerACME {
+		t.Errorf("unexpected issuer type %q", v)
+	}
+	if v := annotations[cmapi.CertificateRequestFeatureGatesAnnotationKey]; !strings.Contains(v, "ValidateCAA=true") {
+		t.Errorf("expected feature gates %q to contain ValidateCAA=true", v)
+	}
+
+	utilpkg.AppGitCommit = ""
+	annotations = pipelineAnnotations("")
+	if v := annotations[cmapi.CertificateRequestControllerVersionAnnotationKey]; v != "v0.16.0" {
+		t.Errorf("unexpected controller version without commit %q", v)
+	}
+	if _, ok := annotations[cmapi.CertificateRequestIssuerTypeAnnotationKey]; ok {
+		t.Errorf("expected no issuer type to be recorded if it is unknown")
+	}
+}
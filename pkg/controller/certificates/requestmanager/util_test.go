@@ -106,6 +106,10 @@ func createCryptoBundle(crt *cmapi.Certificate) (*cryptoBundle, error) {
 
 	annotations[cmapi.CertificateRequestPrivateKeyAnnotationKey] = crt.Spec.SecretName
 	annotations[cmapi.CertificateNameKey] = crt.Name
+	// No issuers exist in the tests, so the issuer type is not recorded
+	for k, v := range pipelineAnnotations("") {
+		annotations[k] = v
+	}
 	if crt.Status.NextPrivateKeySecretName != nil {
 		annotations[cmapi.CertificateRequestPrivateKeyAnnotationKey] = *crt.Status.NextPrivateKeySecretName
 	}
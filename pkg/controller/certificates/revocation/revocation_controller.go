@@ -0,0 +1,414 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revocation
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/crypto/acme"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/jetstack/cert-manager/pkg/acme/accounts"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1alpha2"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/issuer"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/pkg/util/predicate"
+)
+
+const (
+	ControllerName = "CertificateRevocation"
+
+	reasonRevoked                = "Revoked"
+	reasonRevocationFailed       = "RevocationFailed"
+	reasonRevocationNotSupported = "RevocationNotSupported"
+	reasonInvalidRequest         = "InvalidRevocationRequest"
+)
+
+// crlReasons maps the values of the revocation reason annotation to the CRL
+// reason codes of RFC 5280.
+var crlReasons = map[string]acme.CRLReasonCode{
+	"unspecified":          acme.CRLReasonUnspecified,
+	"keyCompromise":        acme.CRLReasonKeyCompromise,
+	"affiliationChanged":   acme.CRLReasonAffiliationChanged,
+	"superseded":           acme.CRLReasonSuperseded,
+	"cessationOfOperation": acme.CRLReasonCessationOfOperation,
+}
+
+// IsValidReason returns true if the given revocation reason is supported.
+func IsValidReason(reason string) bool {
+	_, ok := crlReasons[reason]
+	return ok
+}
+
+// This controller revokes the certificates stored in Secret resources that
+// are labelled as revocation requests, once the Certificate they were issued
+// for has been re-issued. The Secret is annotated with the outcome, so that
+// it may be kept as a record of the revocation.
+// As anyone able to create Secrets can create a revocation request, a
+// certificate is only revoked if it was issued by a CertificateRequest of the
+// Certificate named by the request, in the same namespace, and it is revoked
+// by the issuer of that Certificate.
+// Only ACME issuers support revocation.
+type controller struct {
+	certificateLister        cmlisters.CertificateLister
+	certificateRequestLister cmlisters.CertificateRequestLister
+	secretLister             corelisters.SecretLister
+	kubeClient               kubernetes.Interface
+	recorder                 record.EventRecorder
+
+	// helper is used to obtain references to the issuers of revocation
+	// requests
+	helper issuer.Helper
+	// accountRegistry is used to obtain the ACME clients of issuers
+	accountRegistry accounts.Getter
+}
+
+func NewController(
+	log logr.Logger,
+	kubeClient kubernetes.Interface,
+	factory informers.SharedInformerFactory,
+	cmFactory cminformers.SharedInformerFactory,
+	recorder record.EventRecorder,
+	accountRegistry accounts.Getter,
+	namespace string,
+	backoff *controllerpkg.BackoffPersister,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := controllerpkg.NewRateLimitingQueue(backoff, workqueue.NewItemExponentialFailureRateLimiter(time.Second*5, time.Minute*30), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	certificateInformer := cmFactory.Certmanager().V1alpha2().Certificates()
+	certificateRequestInformer := cmFactory.Certmanager().V1alpha2().CertificateRequests()
+	issuerInformer := cmFactory.Certmanager().V1alpha2().Issuers()
+	secretsInformer := factory.Core().V1().Secrets()
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		certificateInformer.Informer().HasSynced,
+		certificateRequestInformer.Informer().HasSynced,
+		issuerInformer.Informer().HasSynced,
+		secretsInformer.Informer().HasSynced,
+	}
+
+	// ClusterIssuers are only available if the controller is not restricted
+	// to a single namespace.
+	var clusterIssuerLister cmlisters.ClusterIssuerLister
+	if namespace == "" {
+		clusterIssuerInformer := cmFactory.Certmanager().V1alpha2().ClusterIssuers()
+		mustSync = append(mustSync, clusterIssuerInformer.Informer().HasSynced)
+		clusterIssuerLister = clusterIssuerInformer.Lister()
+	}
+
+	c := &controller{
+		certificateLister:        certificateInformer.Lister(),
+		certificateRequestLister: certificateRequestInformer.Lister(),
+		secretLister:             secretsInformer.Lister(),
+		kubeClient:               kubeClient,
+		recorder:                 recorder,
+		helper:                   issuer.NewHelper(issuerInformer.Lister(), clusterIssuerLister),
+		accountRegistry:          accountRegistry,
+	}
+
+	secretsInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: func(obj interface{}) {
+			secret, ok := obj.(*corev1.Secret)
+			if !ok || !isRevocationRequest(secret) {
+				return
+			}
+			enqueue(log, queue, secret)
+		},
+	})
+	// When a Certificate is re-issued, enqueue the revocation requests that
+	// were waiting for it.
+	certificateInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: func(obj interface{}) {
+			crt, ok := obj.(*cmapi.Certificate)
+			if !ok {
+				return
+			}
+			for _, secret := range c.revocationRequestsForCertificate(log, crt) {
+				enqueue(log, queue, secret)
+			}
+		},
+	})
+
+	return c, queue, mustSync
+}
+
+func enqueue(log logr.Logger, queue workqueue.RateLimitingInterface, secret *corev1.Secret) {
+	key, err := controllerpkg.KeyFunc(secret)
+	if err != nil {
+		log.Error(err, "error computing key for resource")
+		return
+	}
+	queue.Add(key)
+}
+
+func isRevocationRequest(secret *corev1.Secret) bool {
+	return secret.Labels[cmapi.RevocationRequestLabelKey] == "true"
+}
+
+func (c *controller) revocationRequestsForCertificate(log logr.Logger, crt *cmapi.Certificate) []*corev1.Secret {
+	secrets, err := c.secretLister.Secrets(crt.Namespace).List(labels.SelectorFromSet(labels.Set{cmapi.RevocationRequestLabelKey: "true"}))
+	if err != nil {
+		log.Error(err, "failed to list revocation requests")
+		return nil
+	}
+	var requests []*corev1.Secret
+	for _, secret := range secrets {
+		if secret.Annotations[cmapi.CertificateNameKey] == crt.Name {
+			requests = append(requests, secret)
+		}
+	}
+	return requests
+}
+
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	secret, err := c.secretLister.Secrets(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !isRevocationRequest(secret) || secret.Annotations[cmapi.RevocationStatusAnnotationKey] != "" {
+		return nil
+	}
+
+	crtName := secret.Annotations[cmapi.CertificateNameKey]
+	if crtName == "" {
+		c.recorder.Eventf(secret, corev1.EventTypeWarning, reasonInvalidRequest, "Revocation request does not name a Certificate in the %s annotation, the certificate is not revoked", cmapi.CertificateNameKey)
+		return nil
+	}
+	crt, err := c.certificateLister.Certificates(secret.Namespace).Get(crtName)
+	if apierrors.IsNotFound(err) {
+		c.recorder.Eventf(secret, corev1.EventTypeWarning, reasonInvalidRequest, "Certificate %q named by the revocation request does not exist, the certificate is not revoked", crtName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if apiutil.IsPaused(crt) {
+		log.V(logf.DebugLevel).Info("certificate is paused, deferring revocation")
+		return nil
+	}
+
+	if c.replacementPending(secret, crt) {
+		log.V(logf.DebugLevel).Info("certificate has not been re-issued yet, deferring revocation")
+		return nil
+	}
+
+	reasonName := secret.Annotations[cmapi.RevocationReasonAnnotationKey]
+	if reasonName == "" {
+		reasonName = "unspecified"
+	}
+	reason, ok := crlReasons[reasonName]
+	if !ok {
+		c.recorder.Eventf(secret, corev1.EventTypeWarning, reasonInvalidRequest, "Unsupported revocation reason %q", reasonName)
+		return nil
+	}
+
+	cert, err := pki.DecodeX509CertificateBytes(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		c.recorder.Eventf(secret, corev1.EventTypeWarning, reasonInvalidRequest, "Failed to decode certificate to be revoked: %v", err)
+		return nil
+	}
+
+	req, err := c.issuingRequest(crt, cert)
+	if err != nil {
+		return err
+	}
+	if req == nil {
+		c.recorder.Eventf(secret, corev1.EventTypeWarning, reasonInvalidRequest,
+			"Certificate with serial number %s was not issued by a CertificateRequest of Certificate %q, the certificate is not revoked", cert.SerialNumber, crt.Name)
+		return nil
+	}
+	issuerRef := crt.Spec.IssuerRef
+	if !sameIssuer(req.Spec.IssuerRef, issuerRef) {
+		c.recorder.Eventf(secret, corev1.EventTypeWarning, reasonInvalidRequest,
+			"Certificate with serial number %s was issued by issuer %q, which Certificate %q no longer references, the certificate is not revoked", cert.SerialNumber, req.Spec.IssuerRef.Name, crt.Name)
+		return nil
+	}
+	genericIssuer, err := c.helper.GetGenericIssuer(issuerRef, secret.Namespace)
+	if err != nil {
+		return fmt.Errorf("error reading (cluster)issuer %q: %v", issuerRef.Name, err)
+	}
+
+	if genericIssuer.GetSpec().ACME == nil {
+		c.recorder.Eventf(secret, corev1.EventTypeWarning, reasonRevocationNotSupported,
+			"Certificate with serial number %s has not been revoked as issuer %q does not support revocation", cert.SerialNumber, issuerRef.Name)
+		return c.setStatus(ctx, secret, cmapi.RevocationStatusNotSupported)
+	}
+
+	cl, err := c.accountRegistry.GetClient(string(genericIssuer.GetUID()))
+	if err != nil {
+		return err
+	}
+
+	// The certificate is revoked with the key of the ACME account that
+	// issued it.
+	if err := cl.RevokeCert(ctx, nil, cert.Raw, reason); err != nil && !isAlreadyRevoked(err) {
+		c.recorder.Eventf(secret, corev1.EventTypeWarning, reasonRevocationFailed, "Failed to revoke certificate with serial number %s: %v", cert.SerialNumber, err)
+		return err
+	}
+
+	log.Info("revoked certificate", "serial_number", cert.SerialNumber.String(), "reason", reasonName)
+	c.recorder.Eventf(secret, corev1.EventTypeNormal, reasonRevoked, "Revoked certificate with serial number %s for reason %s", cert.SerialNumber, reasonName)
+
+	return c.setStatus(ctx, secret, cmapi.RevocationStatusRevoked)
+}
+
+// replacementPending returns true if the revocation request is to be deferred
+// until the Certificate it names has been issued with a later revision.
+func (c *controller) replacementPending(secret *corev1.Secret, crt *cmapi.Certificate) bool {
+	value, ok := secret.Annotations[cmapi.RevokeAfterRevisionAnnotationKey]
+	if !ok {
+		return false
+	}
+	revision, err := strconv.Atoi(value)
+	if err != nil {
+		c.recorder.Eventf(secret, corev1.EventTypeWarning, reasonInvalidRequest, "Invalid value %q of annotation %s, the certificate is not revoked until it is corrected", value, cmapi.RevokeAfterRevisionAnnotationKey)
+		return true
+	}
+
+	return crt.Status.Revision == nil || *crt.Status.Revision < revision
+}
+
+// issuingRequest returns the CertificateRequest owned by crt that issued
+// cert, or nil if cert was not issued for crt.
+func (c *controller) issuingRequest(crt *cmapi.Certificate, cert *x509.Certificate) (*cmapi.CertificateRequest, error) {
+	reqs, err := certificates.ListCertificateRequestsMatchingPredicates(c.certificateRequestLister.CertificateRequests(crt.Namespace),
+		labels.Everything(), predicate.ResourceOwnedBy(crt))
+	if err != nil {
+		return nil, err
+	}
+	for _, req := range reqs {
+		if len(req.Status.Certificate) == 0 {
+			continue
+		}
+		issued, err := pki.DecodeX509CertificateBytes(req.Status.Certificate)
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(issued.Raw, cert.Raw) {
+			return req, nil
+		}
+	}
+	return nil, nil
+}
+
+// sameIssuer returns true if both references refer to the same issuer.
+func sameIssuer(a, b cmmeta.ObjectReference) bool {
+	kind := func(ref cmmeta.ObjectReference) string {
+		if ref.Kind == "" {
+			return cmapi.IssuerKind
+		}
+		return ref.Kind
+	}
+	group := func(ref cmmeta.ObjectReference) string {
+		if ref.Group == "" {
+			return certmanager.GroupName
+		}
+		return ref.Group
+	}
+	return a.Name == b.Name && kind(a) == kind(b) && group(a) == group(b)
+}
+
+func (c *controller) setStatus(ctx context.Context, secret *corev1.Secret, status string) error {
+	secret = secret.DeepCopy()
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[cmapi.RevocationStatusAnnotationKey] = status
+	_, err := c.kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// isAlreadyRevoked returns true if the ACME server rejected the revocation
+// as the certificate has already been revoked, e.g. because a previous
+// attempt succeeded but the Secret could not be updated.
+func isAlreadyRevoked(err error) bool {
+	acmeErr, ok := err.(*acme.Error)
+	return ok && strings.HasSuffix(acmeErr.ProblemType, ":alreadyRevoked")
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log,
+		ctx.Client,
+		ctx.KubeSharedInformerFactory,
+		ctx.SharedInformerFactory,
+		ctx.Recorder,
+		ctx.ACMEOptions.AccountRegistry,
+		ctx.Namespace,
+		ctx.BackoffPersister,
+	)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
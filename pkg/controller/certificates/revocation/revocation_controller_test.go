@@ -0,0 +1,282 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revocation
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/acme"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+
+	accountstest "github.com/jetstack/cert-manager/pkg/acme/accounts/test"
+	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	internaltest "github.com/jetstack/cert-manager/pkg/controller/certificates/internal/test"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestProcessItem(t *testing.T) {
+	acmeIssuer := gen.Issuer("acme-issuer",
+		gen.SetIssuerNamespace("testns"),
+		gen.SetIssuerACME(cmacme.ACMEIssuer{}),
+	)
+	caIssuer := gen.Issuer("ca-issuer",
+		gen.SetIssuerNamespace("testns"),
+		gen.SetIssuerCA(cmapi.CAIssuer{}),
+	)
+	crt := gen.Certificate("test",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateCommonName("example.com"),
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "acme-issuer"}),
+		gen.SetCertificateSecretName("test-tls"),
+		gen.SetCertificateRevision(1),
+	)
+	bundle := internaltest.MustCreateCryptoBundle(t, crt, nil)
+	serial := bundle.Cert.SerialNumber.String()
+
+	caCrt := gen.CertificateFrom(crt, gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer"}))
+	caBundle := internaltest.MustCreateCryptoBundle(t, caCrt, nil)
+	caSerial := caBundle.Cert.SerialNumber.String()
+
+	// otherBundle holds a certificate that was not issued for the Certificate
+	otherBundle := internaltest.MustCreateCryptoBundle(t, gen.CertificateFrom(crt, gen.SetCertificateCommonName("other.example.com")), nil)
+	otherSerial := otherBundle.Cert.SerialNumber.String()
+
+	request := func(certBytes []byte, annotations map[string]string) *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "testns",
+				Name:      "test-revocation",
+				Labels:    map[string]string{cmapi.RevocationRequestLabelKey: "true"},
+				Annotations: map[string]string{
+					cmapi.CertificateNameKey:            "test",
+					cmapi.RevocationReasonAnnotationKey: "keyCompromise",
+				},
+			},
+			Data: map[string][]byte{corev1.TLSCertKey: certBytes},
+		}
+		for k, v := range annotations {
+			secret.Annotations[k] = v
+		}
+		return secret
+	}
+	withStatus := func(secret *corev1.Secret, status string) *corev1.Secret {
+		secret = secret.DeepCopy()
+		secret.Annotations[cmapi.RevocationStatusAnnotationKey] = status
+		return secret
+	}
+	// issuerAnnotations name an issuer other than the one of the Certificate
+	issuerAnnotations := map[string]string{
+		cmapi.IssuerNameAnnotationKey: "ca-issuer",
+		cmapi.IssuerKindAnnotationKey: cmapi.IssuerKind,
+	}
+
+	tests := map[string]struct {
+		secret      *corev1.Secret
+		certificate *cmapi.Certificate
+		requests    []runtime.Object
+
+		// revokeErr is returned by the fake ACME client
+		revokeErr error
+
+		expectRevoked  bool
+		expectedSecret *corev1.Secret
+		expectedEvents []string
+		expectErr      bool
+	}{
+		"revoke the certificate of a revocation request": {
+			secret:         request(bundle.CertBytes, nil),
+			certificate:    crt,
+			requests:       []runtime.Object{bundle.CertificateRequestReady},
+			expectRevoked:  true,
+			expectedSecret: withStatus(request(bundle.CertBytes, nil), cmapi.RevocationStatusRevoked),
+			expectedEvents: []string{"Normal Revoked Revoked certificate with serial number " + serial + " for reason keyCompromise"},
+		},
+		"do nothing if the Secret is not a revocation request": {
+			secret: func() *corev1.Secret {
+				s := request(bundle.CertBytes, nil)
+				s.Labels = nil
+				return s
+			}(),
+			certificate: crt,
+			requests:    []runtime.Object{bundle.CertificateRequestReady},
+		},
+		"do nothing if the revocation request has already been processed": {
+			secret:      withStatus(request(bundle.CertBytes, nil), cmapi.RevocationStatusRevoked),
+			certificate: crt,
+			requests:    []runtime.Object{bundle.CertificateRequestReady},
+		},
+		"defer revocation until the Certificate has been re-issued": {
+			secret:      request(bundle.CertBytes, map[string]string{cmapi.RevokeAfterRevisionAnnotationKey: "2"}),
+			certificate: crt,
+			requests:    []runtime.Object{bundle.CertificateRequestReady},
+		},
+		"defer revocation while the Certificate is paused": {
+			secret:      request(bundle.CertBytes, nil),
+			certificate: gen.CertificateFrom(crt, gen.AddCertificateAnnotations(map[string]string{cmapi.PausedAnnotationKey: "true"})),
+			requests:    []runtime.Object{bundle.CertificateRequestReady},
+		},
+		"revoke once the Certificate has been re-issued": {
+			secret:         request(bundle.CertBytes, map[string]string{cmapi.RevokeAfterRevisionAnnotationKey: "2"}),
+			certificate:    gen.CertificateFrom(crt, gen.SetCertificateRevision(2)),
+			requests:       []runtime.Object{bundle.CertificateRequestReady},
+			expectRevoked:  true,
+			expectedSecret: withStatus(request(bundle.CertBytes, map[string]string{cmapi.RevokeAfterRevisionAnnotationKey: "2"}), cmapi.RevocationStatusRevoked),
+			expectedEvents: []string{"Normal Revoked Revoked certificate with serial number " + serial + " for reason keyCompromise"},
+		},
+		"do not revoke if the Certificate does not exist": {
+			secret:         request(bundle.CertBytes, map[string]string{cmapi.RevokeAfterRevisionAnnotationKey: "2"}),
+			requests:       []runtime.Object{bundle.CertificateRequestReady},
+			expectedEvents: []string{`Warning InvalidRevocationRequest Certificate "test" named by the revocation request does not exist, the certificate is not revoked`},
+		},
+		"do not revoke if the revocation request does not name a Certificate": {
+			secret: func() *corev1.Secret {
+				s := request(bundle.CertBytes, nil)
+				delete(s.Annotations, cmapi.CertificateNameKey)
+				return s
+			}(),
+			certificate:    crt,
+			requests:       []runtime.Object{bundle.CertificateRequestReady},
+			expectedEvents: []string{"Warning InvalidRevocationRequest Revocation request does not name a Certificate in the cert-manager.io/certificate-name annotation, the certificate is not revoked"},
+		},
+		"do not revoke a certificate that was not issued for the Certificate": {
+			secret:         request(otherBundle.CertBytes, nil),
+			certificate:    crt,
+			requests:       []runtime.Object{bundle.CertificateRequestReady},
+			expectedEvents: []string{"Warning InvalidRevocationRequest Certificate with serial number " + otherSerial + ` was not issued by a CertificateRequest of Certificate "test", the certificate is not revoked`},
+		},
+		"do not revoke if the CertificateRequest that issued the certificate no longer exists": {
+			secret:         request(bundle.CertBytes, nil),
+			certificate:    crt,
+			expectedEvents: []string{"Warning InvalidRevocationRequest Certificate with serial number " + serial + ` was not issued by a CertificateRequest of Certificate "test", the certificate is not revoked`},
+		},
+		"do not revoke if the Certificate references another issuer than the one that issued the certificate": {
+			secret:         request(bundle.CertBytes, nil),
+			certificate:    caCrt,
+			requests:       []runtime.Object{bundle.CertificateRequestReady},
+			expectedEvents: []string{"Warning InvalidRevocationRequest Certificate with serial number " + serial + ` was issued by issuer "acme-issuer", which Certificate "test" no longer references, the certificate is not revoked`},
+		},
+		"revoke with the issuer of the Certificate, ignoring issuer annotations": {
+			secret:         request(bundle.CertBytes, issuerAnnotations),
+			certificate:    crt,
+			requests:       []runtime.Object{bundle.CertificateRequestReady},
+			expectRevoked:  true,
+			expectedSecret: withStatus(request(bundle.CertBytes, issuerAnnotations), cmapi.RevocationStatusRevoked),
+			expectedEvents: []string{"Normal Revoked Revoked certificate with serial number " + serial + " for reason keyCompromise"},
+		},
+		"treat a certificate that has already been revoked as revoked": {
+			secret:         request(bundle.CertBytes, nil),
+			certificate:    crt,
+			requests:       []runtime.Object{bundle.CertificateRequestReady},
+			revokeErr:      &acme.Error{StatusCode: 400, ProblemType: "urn:ietf:params:acme:error:alreadyRevoked"},
+			expectRevoked:  true,
+			expectedSecret: withStatus(request(bundle.CertBytes, nil), cmapi.RevocationStatusRevoked),
+			expectedEvents: []string{"Normal Revoked Revoked certificate with serial number " + serial + " for reason keyCompromise"},
+		},
+		"return an error if the revocation fails": {
+			secret:         request(bundle.CertBytes, nil),
+			certificate:    crt,
+			requests:       []runtime.Object{bundle.CertificateRequestReady},
+			revokeErr:      errors.New("this is a network error"),
+			expectRevoked:  true,
+			expectedEvents: []string{"Warning RevocationFailed Failed to revoke certificate with serial number " + serial + ": this is a network error"},
+			expectErr:      true,
+		},
+		"mark the revocation request as not supported for issuers other than ACME": {
+			secret:         request(caBundle.CertBytes, nil),
+			certificate:    caCrt,
+			requests:       []runtime.Object{caBundle.CertificateRequestReady},
+			expectedSecret: withStatus(request(caBundle.CertBytes, nil), cmapi.RevocationStatusNotSupported),
+			expectedEvents: []string{`Warning RevocationNotSupported Certificate with serial number ` + caSerial + ` has not been revoked as issuer "ca-issuer" does not support revocation`},
+		},
+		"do not revoke if the revocation reason is not supported": {
+			secret:         request(bundle.CertBytes, map[string]string{cmapi.RevocationReasonAnnotationKey: "whoops"}),
+			certificate:    crt,
+			requests:       []runtime.Object{bundle.CertificateRequestReady},
+			expectedEvents: []string{`Warning InvalidRevocationRequest Unsupported revocation reason "whoops"`},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:                  t,
+				KubeObjects:        []runtime.Object{test.secret},
+				CertManagerObjects: []runtime.Object{acmeIssuer, caIssuer},
+				ExpectedEvents:     test.expectedEvents,
+			}
+			if test.certificate != nil {
+				builder.CertManagerObjects = append(builder.CertManagerObjects, test.certificate)
+			}
+			builder.CertManagerObjects = append(builder.CertManagerObjects, test.requests...)
+			if test.expectedSecret != nil {
+				builder.ExpectedActions = append(builder.ExpectedActions,
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						test.expectedSecret.Namespace,
+						test.expectedSecret,
+					)),
+				)
+			}
+			builder.Init()
+			defer builder.Stop()
+
+			w := &controllerWrapper{}
+			if _, _, err := w.Register(builder.Context); err != nil {
+				t.Fatal(err)
+			}
+
+			revoked := false
+			w.accountRegistry = &accountstest.FakeRegistry{
+				GetClientFunc: func(_ string) (acmecl.Interface, error) {
+					return &acmecl.FakeACME{
+						FakeRevokeCert: func(_ context.Context, key crypto.Signer, cert []byte, reason acme.CRLReasonCode) error {
+							revoked = true
+							if key != nil {
+								t.Errorf("expected the certificate to be revoked with the account key")
+							}
+							if reason != acme.CRLReasonKeyCompromise {
+								t.Errorf("expected reason %v but got %v", acme.CRLReasonKeyCompromise, reason)
+							}
+							return test.revokeErr
+						},
+					}, nil
+				},
+			}
+			builder.Start()
+
+			err := w.ProcessItem(context.Background(), "testns/test-revocation")
+			if test.expectErr != (err != nil) {
+				t.Errorf("expected error=%t but got: %v", test.expectErr, err)
+			}
+			if revoked != test.expectRevoked {
+				t.Errorf("expected revoked=%t but got %t", test.expectRevoked, revoked)
+			}
+
+			builder.CheckAndFinish()
+		})
+	}
+}
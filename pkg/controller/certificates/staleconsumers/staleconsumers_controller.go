@@ -0,0 +1,358 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package staleconsumers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1alpha2"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/feature"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
+	"github.com/jetstack/cert-manager/pkg/util/apply"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/pkg/util/predicate"
+)
+
+const (
+	ControllerName = "CertificateStaleConsumers"
+
+	// reasonPodsNotReloaded is the reason of the StaleConsumers condition
+	// when running Pods were started before the certificate was issued
+	reasonPodsNotReloaded = "PodsNotReloaded"
+	// reasonPodsUpToDate is the reason of the StaleConsumers condition once
+	// all running Pods were started after the certificate was issued
+	reasonPodsUpToDate = "PodsUpToDate"
+
+	// maxPodsInMessage is the maximum number of Pods named in the message of
+	// the StaleConsumers condition
+	maxPodsInMessage = 5
+)
+
+// This controller correlates the volumes of running Pods with the Secrets of
+// Certificates, and reports Pods that were started before the certificate
+// currently stored in the Secret was issued. Such Pods have most likely not
+// reloaded the renewed certificate and still serve the previous one, which
+// will eventually expire.
+// The number of these Pods is exposed as a metric and summarised in the
+// StaleConsumers condition of the Certificate.
+// The controller is not enabled by default as it needs to watch all Pods.
+type controller struct {
+	certificateLister cmlisters.CertificateLister
+	secretLister      corelisters.SecretLister
+	podLister         corelisters.PodLister
+	client            cmclient.Interface
+	metrics           *metrics.Metrics
+
+	// fieldManager is the field manager used to apply the status of
+	// Certificates if the ServerSideApply feature is enabled
+	fieldManager string
+}
+
+// statusFields are the status conditions of Certificates that are managed by
+// this controller.
+var statusFields = apply.Fields{
+	Conditions: []string{string(cmapi.CertificateConditionStaleConsumers)},
+}
+
+func NewController(
+	log logr.Logger,
+	client cmclient.Interface,
+	factory informers.SharedInformerFactory,
+	cmFactory cminformers.SharedInformerFactory,
+	metrics *metrics.Metrics,
+	backoff *controllerpkg.BackoffPersister,
+	fieldManager string,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := controllerpkg.NewRateLimitingQueue(backoff, workqueue.NewItemExponentialFailureRateLimiter(time.Second*5, time.Minute*5), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	certificateInformer := cmFactory.Certmanager().V1alpha2().Certificates()
+	secretsInformer := factory.Core().V1().Secrets()
+	podsInformer := factory.Core().V1().Pods()
+
+	certificateInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+	// When a Secret resource changes, enqueue any Certificate resources that name it as spec.secretName.
+	secretsInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(),
+			predicate.ExtractResourceName(predicate.CertificateSecretName)),
+	})
+	// When a Pod changes, enqueue the Certificates whose Secret it mounts.
+	podsInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: enqueueCertificatesMountedByPod(log, queue, certificateInformer.Lister()),
+	})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		certificateInformer.Informer().HasSynced,
+		secretsInformer.Informer().HasSynced,
+		podsInformer.Informer().HasSynced,
+	}
+
+	return &controller{
+		certificateLister: certificateInformer.Lister(),
+		secretLister:      secretsInformer.Lister(),
+		podLister:         podsInformer.Lister(),
+		client:            client,
+		metrics:           metrics,
+		fieldManager:      fieldManager,
+	}, queue, mustSync
+}
+
+func enqueueCertificatesMountedByPod(log logr.Logger, queue workqueue.Interface, lister cmlisters.CertificateLister) func(obj interface{}) {
+	return func(obj interface{}) {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			log.Info("Non-Pod type resource passed to the Pod event handler")
+			return
+		}
+
+		crts, err := lister.Certificates(pod.Namespace).List(labels.Everything())
+		if err != nil {
+			log.Error(err, "failed listing Certificate resources")
+			return
+		}
+		for _, crt := range crts {
+			if !mountsSecret(pod, crt.Spec.SecretName) {
+				continue
+			}
+			key, err := controllerpkg.KeyFunc(crt)
+			if err != nil {
+				log.Error(err, "error computing key for resource")
+				continue
+			}
+			queue.Add(key)
+		}
+	}
+}
+
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	crt, err := c.certificateLister.Certificates(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		// the metrics of deleted Certificates are removed by the
+		// certificate metrics controller
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if apiutil.IsPaused(crt) {
+		log.V(logf.DebugLevel).Info("certificate is paused, skipping processing")
+		return nil
+	}
+
+	issued, err := c.issuedAt(crt)
+	if err != nil {
+		return err
+	}
+
+	var stale []string
+	if issued != nil {
+		pods, err := c.podLister.Pods(namespace).List(labels.Everything())
+		if err != nil {
+			return err
+		}
+		stale = stalePods(pods, crt.Spec.SecretName, *issued)
+	}
+	c.metrics.SetCertificateStaleConsumers(crt, len(stale))
+
+	updated := crt.DeepCopy()
+	switch {
+	case len(stale) > 0:
+		apiutil.SetCertificateCondition(updated, cmapi.CertificateConditionStaleConsumers, cmmeta.ConditionTrue, reasonPodsNotReloaded, staleMessage(stale))
+	case apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionStaleConsumers) != nil:
+		// the condition is only added to Certificates once stale Pods have
+		// been found, so that the status of other Certificates is unchanged
+		apiutil.SetCertificateCondition(updated, cmapi.CertificateConditionStaleConsumers, cmmeta.ConditionFalse, reasonPodsUpToDate,
+			"All running Pods mounting the Secret were started after the certificate was issued")
+	}
+	if apiequality.Semantic.DeepEqual(crt.Status, updated.Status) {
+		return nil
+	}
+
+	log.V(logf.DebugLevel).Info("updating stale consumers condition", "pods", len(stale))
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		_, err = apply.CertificateStatus(ctx, c.client, c.fieldManager, statusFields, crt, updated)
+		return err
+	}
+	_, err = c.client.CertmanagerV1alpha2().Certificates(namespace).UpdateStatus(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+// issuedAt returns the time at which the certificate stored in the Secret of
+// the Certificate was issued, i.e. its NotBefore time, or nil if the Secret
+// does not hold a certificate.
+// NotBefore is used rather than the time that the Secret was updated, as the
+// Secret may have been restored from a backup or re-created. Issuers that
+// backdate certificates cause Pods started shortly before the issuance not to
+// be reported, but never cause up to date Pods to be reported.
+func (c *controller) issuedAt(crt *cmapi.Certificate) (*time.Time, error) {
+	secret, err := c.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	certBytes := secret.Data[corev1.TLSCertKey]
+	if len(certBytes) == 0 {
+		return nil, nil
+	}
+	x509Cert, err := pki.DecodeX509CertificateBytes(certBytes)
+	if err != nil {
+		// the readiness controller reports invalid certificates
+		return nil, nil
+	}
+	return &x509Cert.NotBefore, nil
+}
+
+// stalePods returns the sorted names of the running Pods that mount the
+// Secret with the given name and were started before the given time.
+func stalePods(pods []*corev1.Pod, secretName string, issued time.Time) []string {
+	var names []string
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil || !mountsSecret(pod, secretName) {
+			continue
+		}
+		started := podStartTime(pod)
+		if started == nil || !started.Before(issued) {
+			continue
+		}
+		names = append(names, pod.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// podStartTime returns the time at which the longest running container of
+// the Pod was started, as containers read the Secret volume when they start.
+// If no container is running, the time at which the Pod was started is
+// returned instead.
+func podStartTime(pod *corev1.Pod) *time.Time {
+	var started *time.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Running == nil {
+			continue
+		}
+		t := status.State.Running.StartedAt.Time
+		if started == nil || t.Before(*started) {
+			started = &t
+		}
+	}
+	if started == nil && pod.Status.StartTime != nil {
+		started = &pod.Status.StartTime.Time
+	}
+	return started
+}
+
+// mountsSecret returns true if the Pod mounts the Secret with the given name
+// as a volume, directly or projected.
+func mountsSecret(pod *corev1.Pod, secretName string) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Secret != nil && volume.Secret.SecretName == secretName {
+			return true
+		}
+		if volume.Projected == nil {
+			continue
+		}
+		for _, source := range volume.Projected.Sources {
+			if source.Secret != nil && source.Secret.Name == secretName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func staleMessage(pods []string) string {
+	names := pods
+	if len(names) > maxPodsInMessage {
+		names = names[:maxPodsInMessage]
+	}
+	msg := fmt.Sprintf("%d running Pod(s) were started before the certificate was issued and may still serve the previous certificate: %s",
+		len(pods), strings.Join(names, ", "))
+	if len(pods) > len(names) {
+		msg += fmt.Sprintf(" and %d more", len(pods)-len(names))
+	}
+	return msg
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log,
+		ctx.CMClient,
+		ctx.KubeSharedInformerFactory,
+		ctx.SharedInformerFactory,
+		ctx.Metrics,
+		ctx.BackoffPersister,
+		ctx.FieldManagerFor(ControllerName),
+	)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
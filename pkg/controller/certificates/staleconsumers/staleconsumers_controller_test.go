@@ -0,0 +1,230 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package staleconsumers
+
+import (
+	"context"
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestProcessItem(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	metaNow := metav1.NewTime(now)
+	issued := now.Add(-time.Hour)
+
+	crt := gen.Certificate("test",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateSecretName("test-tls"),
+	)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-tls"},
+		Data:       map[string][]byte{corev1.TLSCertKey: mustCertificate(t, issued)},
+	}
+
+	pod := func(name string, started time.Time, volume corev1.VolumeSource) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: name},
+			Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{{Name: "tls", VolumeSource: volume}},
+			},
+			Status: corev1.PodStatus{
+				Phase:     corev1.PodRunning,
+				StartTime: &metav1.Time{Time: started},
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "app",
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(started)}},
+				}},
+			},
+		}
+	}
+	secretVolume := corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "test-tls"}}
+	projectedVolume := corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+		Sources: []corev1.VolumeProjection{{Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "test-tls"}}}},
+	}}
+	otherVolume := corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "other-tls"}}
+
+	staleCondition := cmapi.CertificateCondition{
+		Type:               cmapi.CertificateConditionStaleConsumers,
+		Status:             cmmeta.ConditionTrue,
+		Reason:             reasonPodsNotReloaded,
+		LastTransitionTime: &metaNow,
+		Message:            "2 running Pod(s) were started before the certificate was issued and may still serve the previous certificate: app-1, app-2",
+	}
+	upToDateCondition := cmapi.CertificateCondition{
+		Type:               cmapi.CertificateConditionStaleConsumers,
+		Status:             cmmeta.ConditionFalse,
+		Reason:             reasonPodsUpToDate,
+		LastTransitionTime: &metaNow,
+		Message:            "All running Pods mounting the Secret were started after the certificate was issued",
+	}
+
+	tests := map[string]struct {
+		certificate *cmapi.Certificate
+		secret      *corev1.Secret
+		pods        []runtime.Object
+
+		expectedCondition *cmapi.CertificateCondition
+	}{
+		"do nothing if no Pods mount the Secret": {
+			certificate: crt,
+			secret:      secret,
+			pods:        []runtime.Object{pod("app-1", issued.Add(-time.Hour), otherVolume)},
+		},
+		"do nothing if all Pods were started after the certificate was issued": {
+			certificate: crt,
+			secret:      secret,
+			pods:        []runtime.Object{pod("app-1", issued.Add(time.Minute), secretVolume)},
+		},
+		"do nothing if the Certificate is paused": {
+			certificate: gen.CertificateFrom(crt, gen.AddCertificateAnnotations(map[string]string{cmapi.PausedAnnotationKey: "true"})),
+			secret:      secret,
+			pods:        []runtime.Object{pod("app-1", issued.Add(-time.Hour), secretVolume)},
+		},
+		"do nothing if the Secret does not exist": {
+			certificate: crt,
+			pods:        []runtime.Object{pod("app-1", issued.Add(-time.Hour), secretVolume)},
+		},
+		"report Pods mounting the Secret directly or projected that were started before the certificate was issued": {
+			certificate: crt,
+			secret:      secret,
+			pods: []runtime.Object{
+				pod("app-2", issued.Add(-time.Hour), projectedVolume),
+				pod("app-1", issued.Add(-time.Minute), secretVolume),
+				pod("app-3", issued.Add(time.Minute), secretVolume),
+			},
+			expectedCondition: &staleCondition,
+		},
+		"ignore Pods that are not running": {
+			certificate: crt,
+			secret:      secret,
+			pods: []runtime.Object{
+				func() *corev1.Pod {
+					p := pod("app-1", issued.Add(-time.Hour), secretVolume)
+					p.Status.Phase = corev1.PodSucceeded
+					return p
+				}(),
+			},
+		},
+		"use the start time of restarted containers": {
+			certificate: crt,
+			secret:      secret,
+			pods: []runtime.Object{
+				func() *corev1.Pod {
+					p := pod("app-1", issued.Add(time.Minute), secretVolume)
+					p.Status.StartTime = &metav1.Time{Time: issued.Add(-time.Hour)}
+					return p
+				}(),
+			},
+		},
+		"set the condition to False once all Pods have been restarted": {
+			certificate:       gen.CertificateFrom(crt, gen.SetCertificateStatusCondition(staleCondition)),
+			secret:            secret,
+			pods:              []runtime.Object{pod("app-1", issued.Add(time.Minute), secretVolume)},
+			expectedCondition: &upToDateCondition,
+		},
+		"do nothing if the condition is up to date": {
+			certificate: gen.CertificateFrom(crt, gen.SetCertificateStatusCondition(upToDateCondition)),
+			secret:      secret,
+			pods:        []runtime.Object{pod("app-1", issued.Add(time.Minute), secretVolume)},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fakeclock.NewFakeClock(now),
+				CertManagerObjects: []runtime.Object{test.certificate},
+				KubeObjects:        test.pods,
+				Context: &controllerpkg.Context{
+					RootContext: context.Background(),
+				},
+			}
+			if test.secret != nil {
+				builder.KubeObjects = append(builder.KubeObjects, test.secret)
+			}
+			if test.expectedCondition != nil {
+				expected := gen.CertificateFrom(test.certificate, gen.SetCertificateStatusCondition(*test.expectedCondition))
+				builder.ExpectedActions = append(builder.ExpectedActions,
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						"status",
+						expected.Namespace,
+						expected,
+					)),
+				)
+			}
+			builder.Init()
+			defer builder.Stop()
+
+			w := &controllerWrapper{}
+			if _, _, err := w.Register(builder.Context); err != nil {
+				t.Fatal(err)
+			}
+			builder.Start()
+
+			if err := w.ProcessItem(context.Background(), "testns/test"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			builder.CheckAndFinish()
+		})
+	}
+}
+
+func TestStaleMessage(t *testing.T) {
+	pods := []string{"app-1", "app-2", "app-3", "app-4", "app-5", "app-6", "app-7"}
+	exp := "7 running Pod(s) were started before the certificate was issued and may still serve the previous certificate: " +
+		"app-1, app-2, app-3, app-4, app-5 and 2 more"
+	if msg := staleMessage(pods); msg != exp {
+		t.Errorf("expected message %q, got: %q", exp, msg)
+	}
+}
+
+func mustCertificate(t *testing.T, notBefore time.Time) []byte {
+	pk, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.Add(90 * 24 * time.Hour),
+		DNSNames:     []string{"example.com"},
+	}
+	certPEM, _, err := pki.SignCertificate(template, template, pk.Public(), pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certPEM
+}
@@ -27,6 +27,7 @@ import (
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1alpha2"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/internal/certcache"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
 )
@@ -36,6 +37,10 @@ import (
 type Gatherer struct {
 	CertificateRequestLister cmlisters.CertificateRequestLister
 	SecretLister             corelisters.SecretLister
+
+	// CertificateCache, if set, is passed on to policy functions so that the
+	// certificate stored in a Secret is only decoded when the Secret changes.
+	CertificateCache *certcache.Cache
 }
 
 func (g *Gatherer) DataForCertificate(ctx context.Context, crt *cmapi.Certificate) (Input, error) {
@@ -71,5 +76,6 @@ func (g *Gatherer) DataForCertificate(ctx context.Context, crt *cmapi.Certificat
 		Certificate:            crt,
 		CurrentRevisionRequest: req,
 		Secret:                 secret,
+		CertificateCache:       g.CertificateCache,
 	}, nil
 }
@@ -26,13 +26,22 @@ import (
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/internal/certcache"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
+// ReasonRenewing is the reason returned when a re-issuance is required
+// because the certificate is due for renewal.
+const ReasonRenewing = "Renewing"
+
 type Input struct {
 	Certificate            *cmapi.Certificate
 	CurrentRevisionRequest *cmapi.CertificateRequest
 	Secret                 *corev1.Secret
+
+	// CertificateCache is used to decode the certificate stored in Secret.
+	// It may be nil, in which case the certificate is decoded on every use.
+	CertificateCache *certcache.Cache
 }
 
 // A Func evaluates the given input data and decides whether a
@@ -163,7 +172,7 @@ func CurrentCertificateRequestValidForSpec(input Input) (string, string, bool) {
 // and is instead called by currentCertificateRequestValidForSpec if no there
 // is no existing CertificateRequest resource.
 func currentSecretValidForSpec(input Input) (string, string, bool) {
-	violations, err := certificates.SecretDataAltNamesMatchSpec(input.Secret, input.Certificate.Spec)
+	x509cert, err := input.CertificateCache.Certificate(input.Secret)
 	if err != nil {
 		// This case should never be reached as we already check the certificate data can
 		// be parsed in an earlier policy check, but handle it anyway.
@@ -171,6 +180,7 @@ func currentSecretValidForSpec(input Input) (string, string, bool) {
 		return "", "", false
 	}
 
+	violations := certificates.CertificateAltNamesMatchSpec(x509cert, input.Certificate.Spec)
 	if len(violations) > 0 {
 		return "SecretMismatch", fmt.Sprintf("Existing issued Secret is not up to date for spec: %v", violations), true
 	}
@@ -189,17 +199,16 @@ func CurrentCertificateNearingExpiry(c clock.Clock) Func {
 			return "", "", false
 		}
 
-		return "Renewing", fmt.Sprintf("Renewing certificate as renewal was scheduled at %s", input.Certificate.Status.RenewalTime), true
+		return ReasonRenewing, fmt.Sprintf("Renewing certificate as renewal was scheduled at %s", input.Certificate.Status.RenewalTime), true
 	}
 }
 
 // CurrentCertificateHasExpired is used exclusively to check if the current
 // issued certificate has actually expired rather than just nearing expiry.
 func CurrentCertificateHasExpired(input Input) (string, string, bool) {
-	certData := input.Secret.Data[corev1.TLSCertKey]
 	// TODO: replace this with a generic decoder that can handle different
 	//  formats such as JKS, P12 etc (i.e. add proper support for keystores)
-	cert, err := pki.DecodeX509CertificateBytes(certData)
+	cert, err := input.CertificateCache.Certificate(input.Secret)
 	if err != nil {
 		// This case should never happen as it should always be caught by the
 		// secretPublicKeysMatch function beforehand, but handle it just in case.
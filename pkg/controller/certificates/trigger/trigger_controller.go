@@ -25,7 +25,9 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
@@ -40,9 +42,15 @@ import (
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1alpha2"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/internal/certcache"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
+	"github.com/jetstack/cert-manager/pkg/feature"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/scheduler"
+	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/pkg/util/apply"
+	"github.com/jetstack/cert-manager/pkg/util/cron"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
 )
 
@@ -54,8 +62,28 @@ const (
 	// In future this should be replaced with a more dynamic exponential
 	// back-off algorithm.
 	retryAfterLastFailure = time.Hour
+
+	// reasonReused is the reason of the event fired when the certificate
+	// stored in the Secret of a previous Certificate resource with the same
+	// name is re-used
+	reasonReused = "Reused"
+
+	// reasonRenewalDeferred is the reason of the event fired when the
+	// renewal of a certificate is deferred as a renewal freeze window is
+	// open
+	reasonRenewalDeferred = "RenewalDeferred"
+
+	// orphanedSecretRetention is how long a Secret orphaned by the deletion
+	// of its Certificate is kept for a re-created Certificate to re-use it
+	orphanedSecretRetention = time.Hour
+
+	// orphanedSecretsCheckInterval is how often Secrets orphaned for longer
+	// than orphanedSecretRetention are deleted
+	orphanedSecretsCheckInterval = 5 * time.Minute
 )
 
+var certificateGvk = cmapi.SchemeGroupVersion.WithKind("Certificate")
+
 // This controller observes the state of the certificate's currently
 // issued `spec.secretName` and the rest of the `certificate.spec` fields to
 // determine whether a re-issuance is required.
@@ -67,24 +95,51 @@ type controller struct {
 	certificateLister        cmlisters.CertificateLister
 	certificateRequestLister cmlisters.CertificateRequestLister
 	secretLister             corelisters.SecretLister
+	kubeClient               kubernetes.Interface
 	client                   cmclient.Interface
 	recorder                 record.EventRecorder
 	clock                    clock.Clock
 	scheduledWorkQueue       scheduler.ScheduledWorkQueue
 	gatherer                 *policies.Gatherer
+
+	// if true, Secrets re-used from a previous Certificate resource are
+	// owned by the new Certificate resource
+	enableSecretOwnerReferences bool
+
+	// if true, issuance is not triggered for invalid Certificates
+	validateCertificates bool
+
+	// renewals are deferred whilst any of these windows is open, unless the
+	// certificate expires within renewalFreezeExpiryThreshold
+	renewalFreezeWindows         cron.Windows
+	renewalFreezeExpiryThreshold time.Duration
+
+	// fieldManager is the field manager used to apply the status of
+	// Certificates if the ServerSideApply feature is enabled
+	fieldManager string
+}
+
+// statusFields are the status fields and conditions of Certificates that are
+// managed by this controller.
+var statusFields = apply.Fields{
+	Conditions: []string{string(cmapi.CertificateConditionIssuing)},
 }
 
 func NewController(
 	log logr.Logger,
+	kubeClient kubernetes.Interface,
 	client cmclient.Interface,
 	factory informers.SharedInformerFactory,
 	cmFactory cminformers.SharedInformerFactory,
 	recorder record.EventRecorder,
 	clock clock.Clock,
 	chain policies.Chain,
+	certificateControllerOptions controllerpkg.CertificateOptions,
+	backoff *controllerpkg.BackoffPersister,
+	fieldManager string,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 	// create a queue used to queue up items to be processed
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+	queue := controllerpkg.NewRateLimitingQueue(backoff, workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	certificateInformer := cmFactory.Certmanager().V1alpha2().Certificates()
@@ -103,6 +158,10 @@ func NewController(
 		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(),
 			predicate.ExtractResourceName(predicate.CertificateSecretName)),
 	})
+	// Decoded certificates are cached per Secret resourceVersion, and
+	// dropped again when the Secret is deleted.
+	certCache := certcache.New()
+	secretsInformer.Informer().AddEventHandler(certCache.EventHandler())
 
 	// build a list of InformerSynced functions that will be returned by the Register method.
 	// the controller will only begin processing items once all of these informers have synced.
@@ -117,6 +176,7 @@ func NewController(
 		certificateLister:        certificateInformer.Lister(),
 		certificateRequestLister: certificateRequestInformer.Lister(),
 		secretLister:             secretsInformer.Lister(),
+		kubeClient:               kubeClient,
 		client:                   client,
 		recorder:                 recorder,
 		clock:                    clock,
@@ -124,7 +184,13 @@ func NewController(
 		gatherer: &policies.Gatherer{
 			CertificateRequestLister: certificateRequestInformer.Lister(),
 			SecretLister:             secretsInformer.Lister(),
+			CertificateCache:         certCache,
 		},
+		enableSecretOwnerReferences:  certificateControllerOptions.EnableOwnerRef,
+		validateCertificates:         certificateControllerOptions.ValidateCertificates,
+		renewalFreezeWindows:         certificateControllerOptions.RenewalFreezeWindows,
+		renewalFreezeExpiryThreshold: certificateControllerOptions.RenewalFreezeExpiryThreshold,
+		fieldManager:                 fieldManager,
 	}, queue, mustSync
 }
 
@@ -145,6 +211,27 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	if err != nil {
 		return err
 	}
+
+	if crt.DeletionTimestamp != nil {
+		if util.Contains(crt.Finalizers, cmapi.SecretRetentionFinalizer) {
+			return c.finalize(ctx, crt)
+		}
+		return nil
+	}
+
+	if c.enableSecretOwnerReferences && !util.Contains(crt.Finalizers, cmapi.SecretRetentionFinalizer) {
+		// the Certificate is processed again once the update is observed
+		crt = crt.DeepCopy()
+		crt.Finalizers = append(crt.Finalizers, cmapi.SecretRetentionFinalizer)
+		_, err := c.client.CertmanagerV1alpha2().Certificates(crt.Namespace).Update(ctx, crt, metav1.UpdateOptions{})
+		return err
+	}
+
+	if apiutil.IsPaused(crt) {
+		log.V(logf.DebugLevel).Info("certificate is paused, skipping processing")
+		return nil
+	}
+
 	if apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
 		Type:   cmapi.CertificateConditionIssuing,
 		Status: cmmeta.ConditionTrue,
@@ -153,6 +240,14 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return nil
 	}
 
+	if c.validateCertificates {
+		if el := certificates.ValidateCertificate(crt); len(el) > 0 {
+			log.Info("Not issuing certificate as it failed validation", "errors", el.ToAggregate().Error())
+			c.recorder.Eventf(crt, corev1.EventTypeWarning, certificates.ReasonBadConfig, "Resource validation failed: %v", el.ToAggregate())
+			return nil
+		}
+	}
+
 	// check if we have had a recent failure, and if so do not trigger a
 	// re-issuance immediately
 	if crt.Status.LastFailureTime != nil {
@@ -178,13 +273,29 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 
 	reason, message, reissue := c.policyChain.Evaluate(input)
 	if !reissue {
-		// no re-issuance required, return early
-		return nil
+		// no re-issuance required, but the Secret may still be owned by, or
+		// have been orphaned by, a previous Certificate resource with the
+		// same name
+		return c.adoptSecret(ctx, crt, input.Secret)
 	}
 
+	if reason == policies.ReasonRenewing {
+		if until, deferred := c.renewalDeferredUntil(crt); deferred {
+			log.Info("Not renewing certificate as a renewal freeze window is open", "deferred_until", until)
+			c.recorder.Eventf(crt, corev1.EventTypeNormal, reasonRenewalDeferred, "Renewal deferred until %s as a renewal freeze window is open", until.Format(time.RFC3339))
+			c.scheduleRecheckOfCertificateIfRequired(log, key, until.Sub(c.clock.Now()))
+			return nil
+		}
+	}
+
+	existing := crt
 	crt = crt.DeepCopy()
 	apiutil.SetCertificateCondition(crt, cmapi.CertificateConditionIssuing, cmmeta.ConditionTrue, reason, message)
-	_, err = c.client.CertmanagerV1alpha2().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		_, err = apply.CertificateStatus(ctx, c.client, c.fieldManager, statusFields, existing, crt)
+	} else {
+		_, err = c.client.CertmanagerV1alpha2().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	}
 	if err != nil {
 		return err
 	}
@@ -193,6 +304,217 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	return nil
 }
 
+// renewalDeferredUntil returns whether the renewal of the given Certificate
+// should be deferred as a renewal freeze window is open and, if so, the time
+// until which it is deferred. Renewals are only deferred until the
+// certificate expires within the renewal freeze expiry threshold.
+func (c *controller) renewalDeferredUntil(crt *cmapi.Certificate) (time.Time, bool) {
+	if crt.Status.NotAfter == nil {
+		return time.Time{}, false
+	}
+
+	now := c.clock.Now()
+	until, open := c.renewalFreezeWindows.OpenUntil(now)
+	if !open {
+		return time.Time{}, false
+	}
+
+	deadline := crt.Status.NotAfter.Add(-c.renewalFreezeExpiryThreshold)
+	if !now.Before(deadline) {
+		return time.Time{}, false
+	}
+	if deadline.Before(until) {
+		until = deadline
+	}
+
+	return until, true
+}
+
+// adoptSecret re-uses the up to date certificate stored in the given Secret
+// if the Secret is still owned by, or was orphaned by, a previous Certificate
+// resource with the same name, e.g. because the Certificate was deleted and
+// re-created with the same spec. The stale owner reference is replaced so that
+// the Secret is not garbage collected along with the previous Certificate
+// resource, and the orphaned annotation is removed so that the Secret is not
+// deleted once it has been orphaned for too long, either of which would
+// otherwise cause the certificate to be issued again.
+func (c *controller) adoptSecret(ctx context.Context, crt *cmapi.Certificate, secret *corev1.Secret) error {
+	if secret == nil {
+		return nil
+	}
+
+	_, orphaned := secret.Annotations[cmapi.SecretOrphanedAtAnnotationKey]
+	orphaned = orphaned && secret.Annotations[cmapi.CertificateNameKey] == crt.Name
+
+	var ownerRefs []metav1.OwnerReference
+	stale := false
+	for _, ref := range secret.OwnerReferences {
+		if isPreviousCertificateRef(ref, crt) {
+			stale = true
+			continue
+		}
+		ownerRefs = append(ownerRefs, ref)
+	}
+	if !stale && !orphaned {
+		return nil
+	}
+	if c.enableSecretOwnerReferences && !isCurrentCertificateOwner(ownerRefs, crt) {
+		ownerRefs = append(ownerRefs, *metav1.NewControllerRef(crt, certificateGvk))
+	}
+
+	secret = secret.DeepCopy()
+	secret.OwnerReferences = ownerRefs
+	delete(secret.Annotations, cmapi.SecretOrphanedAtAnnotationKey)
+	_, err := c.kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	c.recorder.Eventf(crt, corev1.EventTypeNormal, reasonReused, "Re-using the certificate stored in Secret %q issued for a previous Certificate resource", secret.Name)
+
+	return nil
+}
+
+// finalize removes the SecretRetentionFinalizer from a Certificate resource
+// that is being deleted. If owner references are enabled, the Secret owned by
+// the Certificate is orphaned first, so that it is not garbage collected
+// before a Certificate resource with the same name that is re-created, e.g. by
+// a GitOps tool, can re-use the certificate stored in it.
+func (c *controller) finalize(ctx context.Context, crt *cmapi.Certificate) error {
+	if c.enableSecretOwnerReferences {
+		if err := c.orphanSecret(ctx, crt); err != nil {
+			return err
+		}
+	}
+
+	crt = crt.DeepCopy()
+	var finalizers []string
+	for _, f := range crt.Finalizers {
+		if f != cmapi.SecretRetentionFinalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	crt.Finalizers = finalizers
+	_, err := c.client.CertmanagerV1alpha2().Certificates(crt.Namespace).Update(ctx, crt, metav1.UpdateOptions{})
+	return err
+}
+
+// orphanSecret removes the owner reference of the given Certificate resource
+// from its Secret, and records the time at which it was orphaned.
+func (c *controller) orphanSecret(ctx context.Context, crt *cmapi.Certificate) error {
+	secret, err := c.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var ownerRefs []metav1.OwnerReference
+	for _, ref := range secret.OwnerReferences {
+		if ref.UID != crt.UID {
+			ownerRefs = append(ownerRefs, ref)
+		}
+	}
+	if len(ownerRefs) == len(secret.OwnerReferences) {
+		// the Secret is not garbage collected along with the Certificate
+		return nil
+	}
+
+	secret = secret.DeepCopy()
+	secret.OwnerReferences = ownerRefs
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[cmapi.SecretOrphanedAtAnnotationKey] = c.clock.Now().UTC().Format(time.RFC3339)
+	_, err = c.kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// deleteOrphanedSecrets deletes the Secrets that were orphaned by the deletion
+// of their Certificate resource more than orphanedSecretRetention ago, and
+// that have neither been re-used nor are referenced by a Certificate since.
+func (c *controller) deleteOrphanedSecrets(ctx context.Context) {
+	log := logf.FromContext(ctx, "orphanedSecrets")
+	secrets, err := c.secretLister.List(labels.Everything())
+	if err != nil {
+		log.Error(err, "error listing secrets")
+		return
+	}
+
+	for _, secret := range secrets {
+		orphanedAt, ok := secret.Annotations[cmapi.SecretOrphanedAtAnnotationKey]
+		if !ok || len(secret.OwnerReferences) > 0 {
+			continue
+		}
+		log := logf.WithResource(log, secret)
+		t, err := time.Parse(time.RFC3339, orphanedAt)
+		if err != nil {
+			log.V(logf.DebugLevel).Info("ignoring invalid orphaned-at annotation", "value", orphanedAt)
+			continue
+		}
+		if c.clock.Since(t) < orphanedSecretRetention {
+			continue
+		}
+		referenced, err := c.secretReferenced(secret)
+		if err != nil {
+			log.Error(err, "error listing certificates")
+			continue
+		}
+		if referenced {
+			continue
+		}
+
+		// the preconditions ensure that a Secret re-used in the meantime
+		// is not deleted
+		uid, resourceVersion := secret.UID, secret.ResourceVersion
+		err = c.kubeClient.CoreV1().Secrets(secret.Namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &uid, ResourceVersion: &resourceVersion},
+		})
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "error deleting orphaned secret")
+			continue
+		}
+		log.Info("deleted secret orphaned by the deletion of its certificate", "orphaned_at", orphanedAt)
+	}
+}
+
+// secretReferenced returns true if a Certificate resource names the given
+// Secret as its spec.secretName.
+func (c *controller) secretReferenced(secret *corev1.Secret) (bool, error) {
+	crts, err := c.certificateLister.Certificates(secret.Namespace).List(labels.Everything())
+	if err != nil {
+		return false, err
+	}
+	for _, crt := range crts {
+		if crt.Spec.SecretName == secret.Name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isCurrentCertificateOwner returns true if refs contains a reference to the
+// given Certificate resource.
+func isCurrentCertificateOwner(refs []metav1.OwnerReference, crt *cmapi.Certificate) bool {
+	for _, ref := range refs {
+		if ref.UID == crt.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// isPreviousCertificateRef returns true if ref refers to a Certificate
+// resource with the same name as crt but a different UID.
+func isPreviousCertificateRef(ref metav1.OwnerReference, crt *cmapi.Certificate) bool {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return false
+	}
+	return gv.Group == certificateGvk.Group && ref.Kind == certificateGvk.Kind &&
+		ref.Name == crt.Name && ref.UID != crt.UID
+}
+
 // scheduleRecheckOfCertificateIfRequired will schedule the resource with the
 // given key to be re-queued for processing after the given amount of time
 // has elapsed.
@@ -225,12 +547,16 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 	log := logf.FromContext(ctx.RootContext, ControllerName)
 
 	ctrl, queue, mustSync := NewController(log,
+		ctx.Client,
 		ctx.CMClient,
 		ctx.KubeSharedInformerFactory,
 		ctx.SharedInformerFactory,
 		ctx.Recorder,
 		ctx.Clock,
 		policies.NewTriggerPolicyChain(ctx.Clock),
+		ctx.CertificateOptions,
+		ctx.BackoffPersister,
+		ctx.FieldManagerFor(ControllerName),
 	)
 	c.controller = ctrl
 
@@ -239,8 +565,12 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 
 func init() {
 	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		c := &controllerWrapper{}
 		return controllerpkg.NewBuilder(ctx, ControllerName).
-			For(&controllerWrapper{}).
+			For(c).
+			// the controller is only constructed once registered, so it is
+			// resolved when the function runs
+			With(func(ctx context.Context) { c.deleteOrphanedSecrets(ctx) }, orphanedSecretsCheckInterval).
 			Complete()
 	})
 }
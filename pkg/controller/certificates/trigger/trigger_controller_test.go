@@ -23,6 +23,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	coretesting "k8s.io/client-go/testing"
 	fakeclock "k8s.io/utils/clock/testing"
 
@@ -31,6 +32,7 @@ import (
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/util/cron"
 )
 
 // policyFuncBuilder wraps a policies.Func to allow injecting a testing.T
@@ -42,6 +44,22 @@ func TestProcessItem(t *testing.T) {
 	metaNow := metav1.NewTime(now)
 	forceTriggeredReason := "ForceTriggered"
 	forceTriggeredMessage := "Re-issuance forced by unit test case"
+	previousCertificateRef := *metav1.NewControllerRef(&cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "previous-uid"}}, cmapi.SchemeGroupVersion.WithKind("Certificate"))
+	currentCertificateRef := *metav1.NewControllerRef(&cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "current-uid"}}, cmapi.SchemeGroupVersion.WithKind("Certificate"))
+	otherOwnerRef := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "test", UID: "configmap-uid"}
+	retentionFinalizers := []string{cmapi.SecretRetentionFinalizer}
+	orphanedAt := now.UTC().Format(time.RFC3339)
+	renewingMessage := "Renewing certificate as renewal was scheduled"
+	renewingPolicy := func(t *testing.T) policies.Func {
+		return func(_ policies.Input) (string, string, bool) {
+			return policies.ReasonRenewing, renewingMessage, true
+		}
+	}
+	// a freeze window that is always open
+	alwaysFrozen := []string{"* * * * * 1h"}
+	renewalFreezeExpiryThreshold := time.Hour * 24 * 7
+	notAfterInTenDays := metav1.NewTime(now.Add(time.Hour * 24 * 10))
+	notAfterInOneDay := metav1.NewTime(now.Add(time.Hour * 24))
 	tests := map[string]struct {
 		// key that should be passed to ProcessItem.
 		// if not set, the 'namespace/name' of the 'Certificate' field will be used.
@@ -84,6 +102,22 @@ func TestProcessItem(t *testing.T) {
 		// If empty, an update to the empty set/nil is expected.
 		expectedConditions []cmapi.CertificateCondition
 
+		// enableOwnerRef sets the EnableOwnerRef certificate option of the
+		// controller.
+		enableOwnerRef bool
+
+		// renewalFreezeWindows sets the RenewalFreezeWindows certificate
+		// option of the controller, with an expiry threshold of 7 days.
+		renewalFreezeWindows []string
+
+		// expectedSecret, if set, is the Secret that is expected to be
+		// updated.
+		expectedSecret *corev1.Secret
+
+		// expectedCertificate, if set, is the Certificate that is expected to
+		// be updated, e.g. to add or remove a finalizer.
+		expectedCertificate *cmapi.Certificate
+
 		// err is the expected error text returned by the controller, if any.
 		err string
 	}{
@@ -312,6 +346,197 @@ func TestProcessItem(t *testing.T) {
 				},
 			},
 		},
+		"re-use Secret owned by a previous Certificate resource with the same name": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "current-uid", Finalizers: retentionFinalizers},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-secret", OwnerReferences: []metav1.OwnerReference{otherOwnerRef, previousCertificateRef}},
+			},
+			chainShouldEvaluate: true,
+			enableOwnerRef:      true,
+			expectedSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-secret", OwnerReferences: []metav1.OwnerReference{otherOwnerRef, currentCertificateRef}},
+			},
+			expectedEvent: `Normal Reused Re-using the certificate stored in Secret "test-secret" issued for a previous Certificate resource`,
+		},
+		"remove owner reference of a previous Certificate resource if owner references are disabled": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "current-uid"},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-secret", OwnerReferences: []metav1.OwnerReference{previousCertificateRef}},
+			},
+			chainShouldEvaluate: true,
+			expectedSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-secret"},
+			},
+			expectedEvent: `Normal Reused Re-using the certificate stored in Secret "test-secret" issued for a previous Certificate resource`,
+		},
+		"do not update Secret owned by the current Certificate resource": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "current-uid", Finalizers: retentionFinalizers},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-secret", OwnerReferences: []metav1.OwnerReference{currentCertificateRef}},
+			},
+			chainShouldEvaluate: true,
+			enableOwnerRef:      true,
+		},
+		"re-use Secret orphaned by a previous Certificate resource that has already been deleted": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "current-uid", Finalizers: retentionFinalizers},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-secret", Annotations: map[string]string{
+					cmapi.CertificateNameKey:            "test",
+					cmapi.SecretOrphanedAtAnnotationKey: orphanedAt,
+				}},
+			},
+			chainShouldEvaluate: true,
+			enableOwnerRef:      true,
+			expectedSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-secret", Annotations: map[string]string{
+					cmapi.CertificateNameKey: "test",
+				}, OwnerReferences: []metav1.OwnerReference{currentCertificateRef}},
+			},
+			expectedEvent: `Normal Reused Re-using the certificate stored in Secret "test-secret" issued for a previous Certificate resource`,
+		},
+		"do not re-use Secret orphaned by a Certificate resource with a different name": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "current-uid", Finalizers: retentionFinalizers},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-secret", Annotations: map[string]string{
+					cmapi.CertificateNameKey:            "other",
+					cmapi.SecretOrphanedAtAnnotationKey: orphanedAt,
+				}},
+			},
+			chainShouldEvaluate: true,
+			enableOwnerRef:      true,
+		},
+		"add the secret retention finalizer if owner references are enabled": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "current-uid"},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+			},
+			enableOwnerRef: true,
+			expectedCertificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "current-uid", Finalizers: retentionFinalizers},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+			},
+		},
+		"orphan the Secret of a Certificate resource that is being deleted": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "current-uid", Finalizers: retentionFinalizers, DeletionTimestamp: &metaNow},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-secret", OwnerReferences: []metav1.OwnerReference{otherOwnerRef, currentCertificateRef}},
+			},
+			enableOwnerRef: true,
+			expectedSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-secret", Annotations: map[string]string{
+					cmapi.SecretOrphanedAtAnnotationKey: orphanedAt,
+				}, OwnerReferences: []metav1.OwnerReference{otherOwnerRef}},
+			},
+			expectedCertificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "current-uid", DeletionTimestamp: &metaNow},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+			},
+		},
+		"only remove the secret retention finalizer if owner references have been disabled": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "current-uid", Finalizers: retentionFinalizers, DeletionTimestamp: &metaNow},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-secret", OwnerReferences: []metav1.OwnerReference{currentCertificateRef}},
+			},
+			expectedCertificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "current-uid", DeletionTimestamp: &metaNow},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+			},
+		},
+		"do not re-use Secret owned by a previous Certificate resource if re-issuance is required": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test", UID: "current-uid", Finalizers: retentionFinalizers},
+				Spec:       cmapi.CertificateSpec{SecretName: "test-secret"},
+			},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test-secret", OwnerReferences: []metav1.OwnerReference{previousCertificateRef}},
+			},
+			chainShouldEvaluate:        true,
+			chainShouldTriggerIssuance: true,
+			enableOwnerRef:             true,
+			expectedEvent:              "Normal Issuing Re-issuance forced by unit test case",
+			expectedConditions: []cmapi.CertificateCondition{
+				{
+					Type:               cmapi.CertificateConditionIssuing,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             forceTriggeredReason,
+					Message:            forceTriggeredMessage,
+					LastTransitionTime: &metaNow,
+				},
+			},
+		},
+		"defer renewal whilst a renewal freeze window is open": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Status:     cmapi.CertificateStatus{NotAfter: &notAfterInTenDays},
+			},
+			chainShouldEvaluate:  true,
+			policyFuncs:          []policyFuncBuilder{renewingPolicy},
+			renewalFreezeWindows: alwaysFrozen,
+			// renewal is only deferred until the certificate expires within
+			// the expiry threshold
+			expectedEvent: "Normal RenewalDeferred Renewal deferred until " +
+				notAfterInTenDays.Add(-renewalFreezeExpiryThreshold).Format(time.RFC3339) +
+				" as a renewal freeze window is open",
+		},
+		"renew whilst a renewal freeze window is open if the certificate expires within the threshold": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Status:     cmapi.CertificateStatus{NotAfter: &notAfterInOneDay},
+			},
+			chainShouldEvaluate:  true,
+			policyFuncs:          []policyFuncBuilder{renewingPolicy},
+			renewalFreezeWindows: alwaysFrozen,
+			expectedEvent:        "Normal Issuing " + renewingMessage,
+			expectedConditions: []cmapi.CertificateCondition{
+				{
+					Type:               cmapi.CertificateConditionIssuing,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             policies.ReasonRenewing,
+					Message:            renewingMessage,
+					LastTransitionTime: &metaNow,
+				},
+			},
+		},
+		"issue whilst a renewal freeze window is open if re-issuance is required for a reason other than renewal": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Status:     cmapi.CertificateStatus{NotAfter: &notAfterInTenDays},
+			},
+			chainShouldEvaluate:        true,
+			chainShouldTriggerIssuance: true,
+			renewalFreezeWindows:       alwaysFrozen,
+			expectedEvent:              "Normal Issuing Re-issuance forced by unit test case",
+			expectedConditions: []cmapi.CertificateCondition{
+				{
+					Type:               cmapi.CertificateConditionIssuing,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             forceTriggeredReason,
+					Message:            forceTriggeredMessage,
+					LastTransitionTime: &metaNow,
+				},
+			},
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -330,10 +555,17 @@ func TestProcessItem(t *testing.T) {
 				builder.CertManagerObjects = append(builder.CertManagerObjects, req)
 			}
 			builder.Init()
+			builder.Context.CertificateOptions.EnableOwnerRef = test.enableOwnerRef
+			windows, err := cron.ParseWindows(test.renewalFreezeWindows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			builder.Context.CertificateOptions.RenewalFreezeWindows = windows
+			builder.Context.CertificateOptions.RenewalFreezeExpiryThreshold = renewalFreezeExpiryThreshold
 
 			// Register informers used by the controller using the registration wrapper
 			w := &controllerWrapper{}
-			_, _, err := w.Register(builder.Context)
+			_, _, err = w.Register(builder.Context)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -369,6 +601,24 @@ func TestProcessItem(t *testing.T) {
 					)),
 				)
 			}
+			if test.expectedSecret != nil {
+				builder.ExpectedActions = append(builder.ExpectedActions,
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						test.expectedSecret.Namespace,
+						test.expectedSecret,
+					)),
+				)
+			}
+			if test.expectedCertificate != nil {
+				builder.ExpectedActions = append(builder.ExpectedActions,
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						test.expectedCertificate.Namespace,
+						test.expectedCertificate,
+					)),
+				)
+			}
 			if test.expectedEvent != "" {
 				builder.ExpectedEvents = []string{test.expectedEvent}
 			}
@@ -417,6 +667,53 @@ func TestProcessItem(t *testing.T) {
 	}
 }
 
+func TestDeleteOrphanedSecrets(t *testing.T) {
+	now := time.Now()
+	orphanedSecret := func(name string, orphanedAt time.Time, ownerRefs ...metav1.OwnerReference) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: name, OwnerReferences: ownerRefs, Annotations: map[string]string{
+				cmapi.SecretOrphanedAtAnnotationKey: orphanedAt.UTC().Format(time.RFC3339),
+			}},
+		}
+	}
+	expired := now.Add(-2 * orphanedSecretRetention)
+
+	builder := &testpkg.Builder{
+		T:     t,
+		Clock: fakeclock.NewFakeClock(now),
+		KubeObjects: []runtime.Object{
+			orphanedSecret("expired", expired),
+			orphanedSecret("recent", now.Add(-time.Minute)),
+			orphanedSecret("referenced", expired),
+			orphanedSecret("owned", expired, metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "test", UID: "configmap-uid"}),
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "not-orphaned"}},
+		},
+		CertManagerObjects: []runtime.Object{
+			&cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Spec:       cmapi.CertificateSpec{SecretName: "referenced"},
+			},
+		},
+		ExpectedActions: []testpkg.Action{
+			testpkg.NewAction(coretesting.NewDeleteAction(corev1.SchemeGroupVersion.WithResource("secrets"), "testns", "expired")),
+		},
+	}
+	builder.Init()
+
+	w := &controllerWrapper{}
+	if _, _, err := w.Register(builder.Context); err != nil {
+		t.Fatal(err)
+	}
+	builder.Start()
+	defer builder.Stop()
+
+	w.deleteOrphanedSecrets(context.Background())
+
+	if err := builder.AllActionsExecuted(); err != nil {
+		t.Error(err)
+	}
+}
+
 func buildTestPolicyChain(t *testing.T, funcs ...policyFuncBuilder) policies.Chain {
 	c := policies.Chain{}
 	for _, f := range funcs {
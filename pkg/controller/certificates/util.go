@@ -20,19 +20,36 @@ import (
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/rsa"
+	"crypto/x509"
 	"fmt"
+	"hash/fnv"
 	"reflect"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	internalapi "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
 	"github.com/jetstack/cert-manager/pkg/util"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/pkg/webhook"
 )
 
+// ReasonBadConfig is the reason used when a Certificate fails the validations
+// that are otherwise enforced by the webhook.
+const ReasonBadConfig = "BadConfig"
+
+// ValidateCertificate runs the validations enforced by the webhook against
+// the given Certificate. It is used to validate Certificates at reconcile time
+// when the webhook is not installed.
+func ValidateCertificate(crt *cmapi.Certificate) field.ErrorList {
+	return webhook.ValidationRegistry.Validate(crt, internalapi.SchemeGroupVersion.WithKind("Certificate"))
+}
+
 func PrivateKeyMatchesSpec(pk crypto.PrivateKey, spec cmapi.CertificateSpec) ([]string, error) {
 	switch spec.KeyAlgorithm {
 	case "", cmapi.RSAKeyAlgorithm:
@@ -103,16 +120,16 @@ func RequestMatchesSpec(req *cmapi.CertificateRequest, spec cmapi.CertificateSpe
 	}
 
 	var violations []string
-	if x509req.Subject.CommonName != spec.CommonName {
+	if x509req.Subject.CommonName != pki.CommonNameForCertificateSpec(&spec) {
 		violations = append(violations, "spec.commonName")
 	}
-	if !util.EqualUnsorted(x509req.DNSNames, spec.DNSNames) {
+	if !pki.DNSNamesEqual(x509req.DNSNames, spec.DNSNames) {
 		violations = append(violations, "spec.dnsNames")
 	}
-	if !util.EqualUnsorted(pki.IPAddressesToString(x509req.IPAddresses), spec.IPAddresses) {
+	if !pki.IPAddressesEqual(pki.IPAddressesToString(x509req.IPAddresses), spec.IPAddresses) {
 		violations = append(violations, "spec.ipAddresses")
 	}
-	if !util.EqualUnsorted(pki.URLsToString(x509req.URIs), spec.URISANs) {
+	if !pki.URIsEqual(pki.URLsToString(x509req.URIs), spec.URISANs) {
 		violations = append(violations, "spec.uriSANs")
 	}
 	if x509req.Subject.SerialNumber != spec.Subject.SerialNumber {
@@ -152,6 +169,9 @@ func RequestMatchesSpec(req *cmapi.CertificateRequest, spec cmapi.CertificateSpe
 	if !reflect.DeepEqual(spec.IssuerRef, req.Spec.IssuerRef) {
 		violations = append(violations, "spec.issuerRef")
 	}
+	if apiutil.IsExternalSigningRequest(req) != spec.ExternalSigning {
+		violations = append(violations, "spec.externalSigning")
+	}
 
 	return violations, nil
 }
@@ -166,7 +186,12 @@ func SecretDataAltNamesMatchSpec(secret *corev1.Secret, spec cmapi.CertificateSp
 	if err != nil {
 		return nil, err
 	}
+	return CertificateAltNamesMatchSpec(x509cert, spec), nil
+}
 
+// CertificateAltNamesMatchSpec performs the same checks as
+// SecretDataAltNamesMatchSpec against an already decoded certificate.
+func CertificateAltNamesMatchSpec(x509cert *x509.Certificate, spec cmapi.CertificateSpec) []string {
 	var violations []string
 
 	// Perform a 'loose' check on the x509 certificate to determine if the
@@ -174,36 +199,42 @@ func SecretDataAltNamesMatchSpec(secret *corev1.Secret, spec cmapi.CertificateSp
 	// This check allows names to move between the DNSNames and CommonName
 	// field freely in order to account for CAs behaviour of promoting DNSNames
 	// to be CommonNames or vice-versa.
-	expectedDNSNames := sets.NewString(spec.DNSNames...)
-	if spec.CommonName != "" {
-		expectedDNSNames.Insert(spec.CommonName)
-	}
-	allDNSNames := sets.NewString(x509cert.DNSNames...)
-	if x509cert.Subject.CommonName != "" {
-		allDNSNames.Insert(x509cert.Subject.CommonName)
+	// Names are compared in their canonical form, so that a difference in
+	// case alone does not cause a violation.
+	specDNSNames := pki.CanonicalDNSNames(spec.DNSNames)
+	specCommonName := pki.CanonicalDNSName(pki.CommonNameForCertificateSpec(&spec))
+	certDNSNames := pki.CanonicalDNSNames(x509cert.DNSNames)
+	certCommonName := pki.CanonicalDNSName(x509cert.Subject.CommonName)
+	expectedDNSNames := sets.NewString(specDNSNames...)
+	if specCommonName != "" {
+		expectedDNSNames.Insert(specCommonName)
+	}
+	allDNSNames := sets.NewString(certDNSNames...)
+	if certCommonName != "" {
+		allDNSNames.Insert(certCommonName)
 	}
 	if !allDNSNames.Equal(expectedDNSNames) {
 		// We know a mismatch occurred, so now determine which fields mismatched.
-		if (spec.CommonName != "" && !allDNSNames.Has(spec.CommonName)) || (x509cert.Subject.CommonName != "" && !expectedDNSNames.Has(x509cert.Subject.CommonName)) {
+		if (specCommonName != "" && !allDNSNames.Has(specCommonName)) || (certCommonName != "" && !expectedDNSNames.Has(certCommonName)) {
 			violations = append(violations, "spec.commonName")
 		}
 
-		if !allDNSNames.HasAll(spec.DNSNames...) || !expectedDNSNames.HasAll(x509cert.DNSNames...) {
+		if !allDNSNames.HasAll(specDNSNames...) || !expectedDNSNames.HasAll(certDNSNames...) {
 			violations = append(violations, "spec.dnsNames")
 		}
 	}
 
-	if !util.EqualUnsorted(pki.IPAddressesToString(x509cert.IPAddresses), spec.IPAddresses) {
+	if !pki.IPAddressesEqual(pki.IPAddressesToString(x509cert.IPAddresses), spec.IPAddresses) {
 		violations = append(violations, "spec.ipAddresses")
 	}
-	if !util.EqualUnsorted(pki.URLsToString(x509cert.URIs), spec.URISANs) {
+	if !pki.URIsEqual(pki.URLsToString(x509cert.URIs), spec.URISANs) {
 		violations = append(violations, "spec.uriSANs")
 	}
 	if !util.EqualUnsorted(x509cert.EmailAddresses, spec.EmailSANs) {
 		violations = append(violations, "spec.emailSANs")
 	}
 
-	return violations, nil
+	return violations
 }
 
 // staticTemporarySerialNumber is a fixed serial number we use for temporary certificates
@@ -268,3 +299,26 @@ func RenewBeforeExpiryDuration(notBefore, notAfter time.Time, specRenewBefore *m
 	}
 	return renewBefore
 }
+
+// RenewalJitter will return the amount of time the renewal of a certificate
+// should be brought forward by, so that certificates issued at the same time
+// are not all renewed at the same time.
+// The jitter is less than percent% of renewBefore and less than max, either
+// limit being ignored if zero. It is derived from seed rather than chosen at
+// random, so that it is stable across reconciles of the same certificate.
+func RenewalJitter(seed string, renewBefore time.Duration, percent int, max time.Duration) time.Duration {
+	window := max
+	if percent > 0 {
+		window = renewBefore * time.Duration(percent) / 100
+		if max > 0 && max < window {
+			window = max
+		}
+	}
+	if window <= 0 {
+		return 0
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(seed))
+	return time.Duration(h.Sum64() % uint64(window))
+}
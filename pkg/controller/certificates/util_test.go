@@ -18,8 +18,10 @@ package certificates
 
 import (
 	"crypto"
+	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -221,6 +223,24 @@ func TestSecretDataAltNamesMatchSpec(t *testing.T) {
 			}),
 			violations: []string{"spec.ipAddresses"},
 		},
+		"should match if dnsNames only differ in case, order and duplicates": {
+			spec: cmapi.CertificateSpec{
+				CommonName: "CN.example.com",
+				DNSNames:   []string{"Least.example.com", "at.example.com", "at.example.com"},
+			},
+			data: selfSignCertificate(t, cmapi.CertificateSpec{
+				CommonName: "cn.example.com",
+				DNSNames:   []string{"at.example.com", "least.example.com"},
+			}),
+		},
+		"should match if ipAddresses only differ in notation": {
+			spec: cmapi.CertificateSpec{
+				IPAddresses: []string{"2001:0DB8:0000:0000:0000:0000:0000:0001"},
+			},
+			data: selfSignCertificate(t, cmapi.CertificateSpec{
+				IPAddresses: []string{"2001:db8::1"},
+			}),
+		},
 		"should not match if ipAddresses has been made the commonName": {
 			spec: cmapi.CertificateSpec{
 				IPAddresses: []string{"127.0.0.1"},
@@ -252,6 +272,57 @@ func TestSecretDataAltNamesMatchSpec(t *testing.T) {
 	}
 }
 
+func TestRenewalJitter(t *testing.T) {
+	const renewBefore = 30 * 24 * time.Hour
+	tests := map[string]struct {
+		percent   int
+		max       time.Duration
+		expWindow time.Duration
+	}{
+		"jitter is disabled by default": {
+			expWindow: 0,
+		},
+		"jitter is a percentage of the renew before duration": {
+			percent:   10,
+			expWindow: 3 * 24 * time.Hour,
+		},
+		"jitter is an absolute duration": {
+			max:       time.Hour,
+			expWindow: time.Hour,
+		},
+		"smaller absolute duration is used if both are set": {
+			percent:   10,
+			max:       time.Hour,
+			expWindow: time.Hour,
+		},
+		"smaller percentage is used if both are set": {
+			percent:   1,
+			max:       24 * time.Hour,
+			expWindow: renewBefore / 100,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			seen := make(map[time.Duration]bool)
+			for i := 0; i < 100; i++ {
+				seed := fmt.Sprintf("uid/%d", i)
+				jitter := RenewalJitter(seed, renewBefore, test.percent, test.max)
+				if jitter < 0 || (jitter >= test.expWindow && jitter != 0) {
+					t.Fatalf("expected jitter in [0, %s), got: %s", test.expWindow, jitter)
+				}
+				if again := RenewalJitter(seed, renewBefore, test.percent, test.max); again != jitter {
+					t.Fatalf("expected jitter to be stable for the same seed, got: %s and %s", jitter, again)
+				}
+				seen[jitter] = true
+			}
+			if test.expWindow > 0 && len(seen) < 50 {
+				t.Errorf("expected jitter to be spread over the window, got %d distinct values", len(seen))
+			}
+		})
+	}
+}
+
 func selfSignCertificate(t *testing.T, spec cmapi.CertificateSpec) []byte {
 	pk, err := pki.GenerateRSAPrivateKey(2048)
 	if err != nil {
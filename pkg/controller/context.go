@@ -18,6 +18,7 @@ package controller
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -31,6 +32,7 @@ import (
 	clientset "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 	informers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
 	"github.com/jetstack/cert-manager/pkg/metrics"
+	"github.com/jetstack/cert-manager/pkg/util/cron"
 )
 
 // Context contains various types that are used by controller implementations.
@@ -71,11 +73,39 @@ type Context struct {
 	// Metrics is used for exposing Prometheus metrics across the controllers
 	Metrics *metrics.Metrics
 
+	// BackoffPersister persists the rate limiting backoff of work queue items
+	// across restarts. If nil, backoff is not persisted.
+	BackoffPersister *BackoffPersister
+
+	// RuntimeConfig holds the configuration that may change whilst the
+	// controller is running. If nil, it never changes.
+	RuntimeConfig *RuntimeConfig
+
 	IssuerOptions
 	ACMEOptions
 	IngressShimOptions
 	CertificateOptions
 	SchedulerOptions
+	SelfStatusOptions
+}
+
+// FieldManager returns the name of the field manager that the API server
+// records in the managed fields of resources written by the controllers. It
+// is derived from the user agent of the RESTConfig like the API server does.
+func (c *Context) FieldManager() string {
+	userAgent := rest.DefaultKubernetesUserAgent()
+	if c.RESTConfig != nil && c.RESTConfig.UserAgent != "" {
+		userAgent = c.RESTConfig.UserAgent
+	}
+	return strings.SplitN(userAgent, "/", 2)[0]
+}
+
+// FieldManagerFor returns the name of the field manager used by the named
+// controller for resources it writes with server-side apply. Each controller
+// applies with its own field manager, so that the fields applied by one
+// controller are not removed by the applies of another.
+func (c *Context) FieldManagerFor(controllerName string) string {
+	return c.FieldManager() + "-" + strings.ToLower(controllerName)
 }
 
 type IssuerOptions struct {
@@ -96,6 +126,17 @@ type IssuerOptions struct {
 	// Once a certificate is within this duration until expiry, a new Certificate
 	// will be attempted to be issued.
 	RenewBeforeExpiryDuration time.Duration
+
+	// ClusterDomain is the domain of the cluster, returned by the
+	// clusterDomain function in the request defaults of issuers.
+	ClusterDomain string
+
+	// TrustPodServiceAccountAnnotation enables returning the service account
+	// in the cert-manager.io/pod-service-account annotation of a
+	// CertificateRequest from the serviceAccount function in the request
+	// defaults of issuers. It must only be set if the webhook verifies the
+	// annotation against the identity of the Pod at admission.
+	TrustPodServiceAccountAnnotation bool
 }
 
 type ACMEOptions struct {
@@ -115,6 +156,21 @@ type ACMEOptions struct {
 	// HTTP01SolverResourceLimitsMemory defines the ACME pod's resource limits Memory size
 	HTTP01SolverResourceLimitsMemory resource.Quantity
 
+	// HTTP01SolverPropagatedLabels is a list of label keys that are copied
+	// from Challenges onto the HTTP01 solver pods, services and ingresses
+	// created for them.
+	HTTP01SolverPropagatedLabels []string
+
+	// HTTP01SolverPriorityClassName and HTTP01SolverRuntimeClassName are the
+	// priority and runtime class of HTTP01 solver pods, unless set in the
+	// pod template of the solver.
+	HTTP01SolverPriorityClassName string
+	HTTP01SolverRuntimeClassName  string
+
+	// HTTP01SolverTopologySpreadKeys are the node label keys across whose
+	// values HTTP01 solver pods are spread.
+	HTTP01SolverTopologySpreadKeys []string
+
 	// DNS01CheckAuthoritative is a flag for controlling if auth nss are used
 	// for checking propagation of an RR. This is the ideal scenario
 	DNS01CheckAuthoritative bool
@@ -123,9 +179,35 @@ type ACMEOptions struct {
 	// for ACME DNS01 validations.
 	DNS01Nameservers []string
 
+	// DNS01ExternalDNSOwnerID is the owner ID of the external-dns ownership
+	// TXT records written alongside DNS01 challenge records. If empty, no
+	// ownership records are written.
+	DNS01ExternalDNSOwnerID string
+
+	// DNS01ExternalDNSTXTPrefix is the prefix of the names of external-dns
+	// ownership TXT records, matching the --txt-prefix of external-dns.
+	DNS01ExternalDNSTXTPrefix string
+
+	// DNS01ProviderOutageThreshold is the number of consecutive requests to
+	// a DNS01 provider failing due to an outage of the provider after which
+	// all Challenges using it are paused. If zero, Challenges are never
+	// paused.
+	DNS01ProviderOutageThreshold int
+
+	// DNS01ProviderOutageProbeInterval is how often an unavailable DNS01
+	// provider is probed for recovery.
+	DNS01ProviderOutageProbeInterval time.Duration
+
+	// DNS01VerifyCredentials controls whether the credentials of the DNS01
+	// providers of ACME issuers are verified when the issuers are set up.
+	DNS01VerifyCredentials bool
+
 	// AccountRegistry is used as a cache of ACME accounts between various
 	// components of cert-manager
 	AccountRegistry accounts.Registry
+
+	// ClientOptions configures retries and circuit breaking of ACME clients
+	ClientOptions accounts.ClientOptions
 }
 
 type IngressShimOptions struct {
@@ -140,6 +222,39 @@ type CertificateOptions struct {
 	// EnableOwnerRef controls whether the certificate is configured as an owner of
 	// secret where the effective TLS certificate is stored.
 	EnableOwnerRef bool
+
+	// AttestationKeySecretName is the name of the Secret in the cluster
+	// resource namespace holding the private key used to sign attestations
+	// of issued certificates. Attestations are disabled if empty.
+	AttestationKeySecretName string
+
+	// RenewalJitterPercent and RenewalJitterMax bound the amount of time the
+	// renewal of a certificate is brought forward by, as a percentage of its
+	// renew before duration and as an absolute duration respectively.
+	// Jitter is disabled if both are zero.
+	RenewalJitterPercent int
+	RenewalJitterMax     time.Duration
+
+	// ValidateCertificates controls whether Certificates are validated when
+	// they are reconciled, so that invalid Certificates are not issued when
+	// the webhook is not installed to reject them.
+	ValidateCertificates bool
+
+	// RenewalFreezeWindows are the windows of time during which the
+	// automatic renewal of certificates is deferred until the window closes,
+	// unless the certificate expires within RenewalFreezeExpiryThreshold.
+	RenewalFreezeWindows         cron.Windows
+	RenewalFreezeExpiryThreshold time.Duration
+
+	// NextPrivateKeySecretTTL is the amount of time after which temporary
+	// 'next private key' Secrets are deleted if they are not in use by an
+	// issuance in progress.
+	NextPrivateKeySecretTTL time.Duration
+
+	// EnableSecretConsumerPatches controls whether the patches of the
+	// cert-manager.io/secret-consumer-patches annotation of Certificates are
+	// applied to the resources consuming their Secret.
+	EnableSecretConsumerPatches bool
 }
 
 type SchedulerOptions struct {
@@ -147,3 +262,22 @@ type SchedulerOptions struct {
 	// scheduled as 'processing' at once.
 	MaxConcurrentChallenges int
 }
+
+type SelfStatusOptions struct {
+	// WebhookCASecretNamespace and WebhookCASecretName identify the Secret
+	// holding the CA that signs the serving certificates of the webhook.
+	WebhookCASecretNamespace string
+	WebhookCASecretName      string
+
+	// WebhookAddress is the host:port the webhook serves on, which is
+	// connected to in order to inspect the certificate it serves.
+	WebhookAddress string
+
+	// StatusAPICertFile is the serving certificate of the status API of the
+	// controller.
+	StatusAPICertFile string
+
+	// CheckInterval is how often the certificates of cert-manager components
+	// are checked.
+	CheckInterval time.Duration
+}
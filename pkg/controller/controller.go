@@ -19,7 +19,6 @@ package controller
 import (
 	"context"
 	"fmt"
-	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -50,6 +49,7 @@ func NewController(
 	mustSync []cache.InformerSynced,
 	runDurationFuncs []runDurationFunc,
 	queue workqueue.RateLimitingInterface,
+	runtimeConfig *RuntimeConfig,
 ) Interface {
 	return &controller{
 		ctx:              ctx,
@@ -59,6 +59,7 @@ func NewController(
 		mustSync:         mustSync,
 		runDurationFuncs: runDurationFuncs,
 		queue:            queue,
+		runtimeConfig:    runtimeConfig,
 	}
 }
 
@@ -89,6 +90,9 @@ type controller struct {
 
 	// metrics is used to expose Prometheus, shared by all controllers
 	metrics *metrics.Metrics
+
+	// runtimeConfig, if set, is watched for changes to the number of workers
+	runtimeConfig *RuntimeConfig
 }
 
 // Run starts the controller loop
@@ -103,14 +107,22 @@ func (c *controller) Run(workers int, stopCh <-chan struct{}) error {
 		return fmt.Errorf("error waiting for informer caches to sync")
 	}
 
-	var wg sync.WaitGroup
-	for i := 0; i < workers; i++ {
-		wg.Add(1)
-		// TODO (@munnerz): make time.Second duration configurable
-		go wait.Until(func() {
-			defer wg.Done()
-			c.worker(ctx)
-		}, time.Second, stopCh)
+	pool := &workerPool{
+		run: func(workerStopCh <-chan struct{}) {
+			// TODO (@munnerz): make time.Second duration configurable
+			wait.Until(func() {
+				c.worker(ctx, workerStopCh)
+			}, time.Second, workerStopCh)
+		},
+	}
+	pool.resize(workers)
+	if c.runtimeConfig != nil {
+		c.runtimeConfig.OnChange(func(values RuntimeConfigValues) {
+			if values.NumberOfConcurrentWorkers != pool.size() {
+				log.Info("changing number of workers", "workers", values.NumberOfConcurrentWorkers)
+				pool.resize(values.NumberOfConcurrentWorkers)
+			}
+		})
 	}
 
 	for _, f := range c.runFirstFuncs {
@@ -125,12 +137,15 @@ func (c *controller) Run(workers int, stopCh <-chan struct{}) error {
 	log.Info("shutting down queue as workqueue signaled shutdown")
 	c.queue.ShutDown()
 	log.V(logf.DebugLevel).Info("waiting for workers to exit...")
-	wg.Wait()
+	pool.stop()
 	log.V(logf.DebugLevel).Info("workers exited")
 	return nil
 }
 
-func (b *controller) worker(ctx context.Context) {
+// worker processes items from the queue until it is shut down or stopCh is
+// closed. A worker blocked waiting for an item only observes stopCh once it
+// has processed the next item.
+func (b *controller) worker(ctx context.Context, stopCh <-chan struct{}) {
 	log := logf.FromContext(b.ctx)
 
 	log.V(logf.DebugLevel).Info("starting worker")
@@ -162,6 +177,13 @@ func (b *controller) worker(ctx context.Context) {
 			log.Info("finished processing work item")
 			b.queue.Forget(obj)
 		}()
+
+		select {
+		case <-stopCh:
+			log.V(logf.DebugLevel).Info("stopping worker")
+			return
+		default:
+		}
 	}
 	log.V(logf.DebugLevel).Info("exiting worker loop")
 }
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+)
+
+const (
+	// EventsSinkKubernetes records events as Event resources in the
+	// Kubernetes API server.
+	EventsSinkKubernetes = "kubernetes"
+	// EventsSinkLog only writes events to the log of the controller.
+	EventsSinkLog = "log"
+)
+
+// verbosityRecorder is an EventRecorder that drops events for objects that
+// have turned down their event verbosity with the
+// cert-manager.io/event-verbosity annotation.
+type verbosityRecorder struct {
+	record.EventRecorder
+}
+
+// NewVerbosityRecorder returns an EventRecorder that records events with
+// recorder, unless the event verbosity annotation of the object the event is
+// recorded for rules the event out.
+func NewVerbosityRecorder(recorder record.EventRecorder) record.EventRecorder {
+	return &verbosityRecorder{EventRecorder: recorder}
+}
+
+func (r *verbosityRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if !shouldRecordEvent(object, eventtype) {
+		return
+	}
+	r.EventRecorder.Event(object, eventtype, reason, message)
+}
+
+func (r *verbosityRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if !shouldRecordEvent(object, eventtype) {
+		return
+	}
+	r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+func (r *verbosityRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if !shouldRecordEvent(object, eventtype) {
+		return
+	}
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+}
+
+// shouldRecordEvent returns false if the event verbosity annotation of the
+// object rules out events of the given type. Events are always recorded for
+// objects without metadata, e.g. object references.
+func shouldRecordEvent(object runtime.Object, eventtype string) bool {
+	obj, err := meta.Accessor(object)
+	if err != nil {
+		return true
+	}
+	switch obj.GetAnnotations()[cmapi.EventVerbosityAnnotationKey] {
+	case cmapi.EventVerbosityNone:
+		return false
+	case cmapi.EventVerbosityQuiet:
+		return eventtype == corev1.EventTypeWarning
+	}
+	return true
+}
+
+// namespacedEventSink is an EventSink that writes all events into a single
+// namespace, rather than into the namespace of the object they are about.
+type namespacedEventSink struct {
+	sink      record.EventSink
+	namespace string
+}
+
+// NewNamespacedEventSink returns an EventSink that writes events to sink in
+// the given namespace. This keeps the volume of events recorded by
+// cert-manager from exhausting the event rate limits of the namespaces of
+// the resources it manages.
+func NewNamespacedEventSink(sink record.EventSink, namespace string) record.EventSink {
+	return &namespacedEventSink{sink: sink, namespace: namespace}
+}
+
+func (s *namespacedEventSink) Create(event *corev1.Event) (*corev1.Event, error) {
+	return s.sink.Create(s.inNamespace(event))
+}
+
+func (s *namespacedEventSink) Update(event *corev1.Event) (*corev1.Event, error) {
+	return s.sink.Update(s.inNamespace(event))
+}
+
+func (s *namespacedEventSink) Patch(event *corev1.Event, data []byte) (*corev1.Event, error) {
+	return s.sink.Patch(s.inNamespace(event), data)
+}
+
+func (s *namespacedEventSink) inNamespace(event *corev1.Event) *corev1.Event {
+	event = event.DeepCopy()
+	event.Namespace = s.namespace
+	return event
+}
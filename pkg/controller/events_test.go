@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+)
+
+func TestVerbosityRecorder(t *testing.T) {
+	tests := map[string]struct {
+		verbosity string
+		expEvents int
+	}{
+		"all events are recorded by default": {
+			expEvents: 2,
+		},
+		"all events are recorded with normal verbosity": {
+			verbosity: cmapi.EventVerbosityNormal,
+			expEvents: 2,
+		},
+		"only warnings are recorded with quiet verbosity": {
+			verbosity: cmapi.EventVerbosityQuiet,
+			expEvents: 1,
+		},
+		"no events are recorded with verbosity none": {
+			verbosity: cmapi.EventVerbosityNone,
+			expEvents: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := &cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+			if test.verbosity != "" {
+				crt.Annotations = map[string]string{cmapi.EventVerbosityAnnotationKey: test.verbosity}
+			}
+
+			fake := record.NewFakeRecorder(10)
+			recorder := NewVerbosityRecorder(fake)
+			recorder.Event(crt, corev1.EventTypeNormal, "Issued", "Certificate issued successfully")
+			recorder.Eventf(crt, corev1.EventTypeWarning, "Failed", "Failed to issue certificate: %s", "some error")
+
+			if len(fake.Events) != test.expEvents {
+				t.Errorf("expected %d events to be recorded, got %d", test.expEvents, len(fake.Events))
+			}
+		})
+	}
+}
+
+type fakeEventSink struct {
+	created []*corev1.Event
+}
+
+func (f *fakeEventSink) Create(event *corev1.Event) (*corev1.Event, error) {
+	f.created = append(f.created, event)
+	return event, nil
+}
+
+func (f *fakeEventSink) Update(event *corev1.Event) (*corev1.Event, error) {
+	return event, nil
+}
+
+func (f *fakeEventSink) Patch(event *corev1.Event, data []byte) (*corev1.Event, error) {
+	return event, nil
+}
+
+func TestNamespacedEventSink(t *testing.T) {
+	fake := &fakeEventSink{}
+	sink := NewNamespacedEventSink(fake, "cert-manager-events")
+
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "test.1", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: cmapi.CertificateKind, Name: "test", Namespace: "default"},
+	}
+	if _, err := sink.Create(event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fake.created) != 1 || fake.created[0].Namespace != "cert-manager-events" {
+		t.Fatalf("expected event to be created in the events namespace, got: %v", fake.created)
+	}
+	if fake.created[0].InvolvedObject.Namespace != "default" {
+		t.Errorf("expected the involved object to be unchanged, got: %v", fake.created[0].InvolvedObject)
+	}
+	if event.Namespace != "default" {
+		t.Errorf("expected the given event not to be modified")
+	}
+}
@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/go-logr/logr"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
@@ -58,13 +59,19 @@ type controller struct {
 	cmClient clientset.Interface
 	recorder record.EventRecorder
 
+	// fieldManager is the field manager used to apply Certificates if the
+	// ServerSideApply feature is enabled
+	fieldManager string
+
 	ingressLister       extlisters.IngressLister
 	certificateLister   cmlisters.CertificateLister
 	issuerLister        cmlisters.IssuerLister
 	clusterIssuerLister cmlisters.ClusterIssuerLister
 
-	helper   issuer.Helper
-	defaults defaults
+	helper issuer.Helper
+
+	defaultsLock sync.RWMutex
+	defaults     defaults
 }
 
 // Register registers and constructs the controller using the provided context.
@@ -75,7 +82,7 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.log = logf.FromContext(ctx.RootContext, ControllerName)
 
 	// create a queue used to queue up items to be processed
-	c.queue = workqueue.NewNamedRateLimitingQueue(controllerpkg.DefaultItemBasedRateLimiter(), ControllerName)
+	c.queue = controllerpkg.NewRateLimitingQueue(ctx.BackoffPersister, controllerpkg.DefaultItemBasedRateLimiter(), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	ingressInformer := ctx.KubeSharedInformerFactory.Extensions().V1beta1().Ingresses()
@@ -111,16 +118,34 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.kClient = ctx.Client
 	c.cmClient = ctx.CMClient
 	c.recorder = ctx.Recorder
-	c.defaults = defaults{
-		ctx.DefaultAutoCertificateAnnotations,
-		ctx.DefaultIssuerName,
-		ctx.DefaultIssuerKind,
-		ctx.DefaultIssuerGroup,
+	c.fieldManager = ctx.FieldManagerFor(ControllerName)
+	c.setDefaults(ctx.IngressShimOptions)
+	if ctx.RuntimeConfig != nil {
+		ctx.RuntimeConfig.OnChange(func(values controllerpkg.RuntimeConfigValues) {
+			c.setDefaults(values.IngressShimOptions)
+		})
 	}
 
 	return c.queue, mustSync, nil
 }
 
+func (c *controller) setDefaults(opts controllerpkg.IngressShimOptions) {
+	c.defaultsLock.Lock()
+	defer c.defaultsLock.Unlock()
+	c.defaults = defaults{
+		opts.DefaultAutoCertificateAnnotations,
+		opts.DefaultIssuerName,
+		opts.DefaultIssuerKind,
+		opts.DefaultIssuerGroup,
+	}
+}
+
+func (c *controller) getDefaults() defaults {
+	c.defaultsLock.RLock()
+	defer c.defaultsLock.RUnlock()
+	return c.defaults
+}
+
 func (c *controller) certificateDeleted(obj interface{}) {
 	crt, ok := obj.(*cmv1alpha1.Certificate)
 	if !ok {
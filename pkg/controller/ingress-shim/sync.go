@@ -18,9 +18,11 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -29,11 +31,15 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/feature"
 	"github.com/jetstack/cert-manager/pkg/logs"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
@@ -43,7 +49,7 @@ func (c *controller) Sync(ctx context.Context, ing *extv1beta1.Ingress) error {
 	log := logs.WithResource(logs.FromContext(ctx), ing)
 	ctx = logs.NewContext(ctx, log)
 
-	if !shouldSync(ing, c.defaults.autoCertificateAnnotations) {
+	if !shouldSync(ing, c.getDefaults().autoCertificateAnnotations) {
 		log.Info(fmt.Sprintf("not syncing ingress resource as it does not contain a %q or %q annotation",
 			cmapi.IngressIssuerNameAnnotationKey, cmapi.IngressClusterIssuerNameAnnotationKey))
 		return nil
@@ -72,8 +78,14 @@ func (c *controller) Sync(ctx context.Context, ing *extv1beta1.Ingress) error {
 		return err
 	}
 
+	serverSideApply := utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply)
+
 	for _, crt := range newCrts {
-		_, err := c.cmClient.CertmanagerV1alpha2().Certificates(crt.Namespace).Create(context.TODO(), crt, metav1.CreateOptions{})
+		if serverSideApply {
+			err = c.applyCertificate(ctx, ing, nil, crt)
+		} else {
+			_, err = c.cmClient.CertmanagerV1alpha2().Certificates(crt.Namespace).Create(context.TODO(), crt, metav1.CreateOptions{})
+		}
 		if err != nil {
 			return err
 		}
@@ -81,7 +93,15 @@ func (c *controller) Sync(ctx context.Context, ing *extv1beta1.Ingress) error {
 	}
 
 	for _, crt := range updateCrts {
-		_, err := c.cmClient.CertmanagerV1alpha2().Certificates(crt.Namespace).Update(context.TODO(), crt, metav1.UpdateOptions{})
+		if serverSideApply {
+			var existing *cmapi.Certificate
+			existing, err = c.certificateLister.Certificates(crt.Namespace).Get(crt.Name)
+			if err == nil {
+				err = c.applyCertificate(ctx, ing, existing, crt)
+			}
+		} else {
+			_, err = c.cmClient.CertmanagerV1alpha2().Certificates(crt.Namespace).Update(context.TODO(), crt, metav1.UpdateOptions{})
+		}
 		if err != nil {
 			return err
 		}
@@ -104,8 +124,119 @@ func (c *controller) Sync(ctx context.Context, ing *extv1beta1.Ingress) error {
 	return nil
 }
 
+// managedCertificateAnnotations are the annotations of Certificates that are
+// set by this controller.
+var managedCertificateAnnotations = []string{
+	cmacme.ACMECertificateHTTP01IngressNameOverride,
+	cmacme.ACMECertificateHTTP01IngressClassOverride,
+	cmapi.IssueTemporaryCertificateAnnotation,
+}
+
+// applyCertificate writes the fields of crt that are set by this controller
+// with server-side apply, leaving all other fields, e.g. those added by
+// users, to their field managers. existing is the current Certificate, or nil
+// if it does not exist yet.
+// Labels and the common name written with an update before the Certificate
+// was first applied are owned by another field manager, and are not removed
+// by the apiserver when they are left out of the apply, so those that are no
+// longer set are removed explicitly first.
+func (c *controller) applyCertificate(ctx context.Context, ing *extv1beta1.Ingress, existing, crt *cmapi.Certificate) error {
+	if existing != nil && !appliedBy(existing, c.fieldManager) {
+		if err := c.removeStaleFields(ctx, existing, crt); err != nil {
+			return fmt.Errorf("removing stale fields from Certificate: %w", err)
+		}
+	}
+
+	applied := &cmapi.Certificate{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: cmapi.SchemeGroupVersion.String(),
+			Kind:       cmapi.CertificateKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            crt.Name,
+			Namespace:       crt.Namespace,
+			Labels:          crt.Labels,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ing, ingressGVK)},
+		},
+		Spec: crt.Spec,
+	}
+	for _, key := range managedCertificateAnnotations {
+		if value, ok := crt.Annotations[key]; ok {
+			if applied.Annotations == nil {
+				applied.Annotations = make(map[string]string)
+			}
+			applied.Annotations[key] = value
+		}
+	}
+	body, err := json.Marshal(applied)
+	if err != nil {
+		return err
+	}
+
+	force := true
+	_, err = c.cmClient.CertmanagerV1alpha2().Certificates(crt.Namespace).Patch(ctx, crt.Name, types.ApplyPatchType, body, metav1.PatchOptions{
+		FieldManager: c.fieldManager,
+		Force:        &force,
+	})
+	return err
+}
+
+// appliedBy returns true if crt has been written with server-side apply by
+// the given field manager before.
+func appliedBy(crt *cmapi.Certificate, fieldManager string) bool {
+	for _, entry := range crt.ManagedFields {
+		if entry.Manager == fieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return true
+		}
+	}
+	return false
+}
+
+// removeStaleFields removes the labels and the common name of existing that
+// are no longer set on crt with a JSON patch.
+func (c *controller) removeStaleFields(ctx context.Context, existing, crt *cmapi.Certificate) error {
+	var paths []string
+	for key := range existing.Labels {
+		if _, ok := crt.Labels[key]; !ok {
+			paths = append(paths, "/metadata/labels/"+strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1"))
+		}
+	}
+	if existing.Spec.CommonName != "" && crt.Spec.CommonName == "" {
+		paths = append(paths, "/spec/commonName")
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	sort.Strings(paths)
+
+	ops := make([]map[string]string, len(paths))
+	for i, path := range paths {
+		ops[i] = map[string]string{"op": "remove", "path": path}
+	}
+	body, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.cmClient.CertmanagerV1alpha2().Certificates(existing.Namespace).Patch(ctx, existing.Name, types.JSONPatchType, body, metav1.PatchOptions{
+		FieldManager: c.fieldManager,
+	})
+	return err
+}
+
 func (c *controller) validateIngress(ing *extv1beta1.Ingress) []error {
 	var errs []error
+	// the webhook does not validate Ingresses, so the values of the
+	// cert-manager annotations are validated before they are used
+	annotationErrs := apiutil.ValidateAnnotations(apiutil.IngressKind, ing.Annotations)
+	keys := make([]string, 0, len(annotationErrs))
+	for key := range annotationErrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		errs = append(errs, fmt.Errorf("Invalid value for annotation %q: %v", key, annotationErrs[key]))
+	}
 	namedSecrets := make(map[string]int)
 	for i, tls := range ing.Spec.TLS {
 		namedSecrets[tls.SecretName] += 1
@@ -338,9 +469,10 @@ func shouldSync(ing *extv1beta1.Ingress, autoCertificateAnnotations []string) bo
 func (c *controller) issuerForIngress(ing *extv1beta1.Ingress) (name, kind, group string, err error) {
 	var errs []string
 
-	name = c.defaults.issuerName
-	kind = c.defaults.issuerKind
-	group = c.defaults.issuerGroup
+	defaults := c.getDefaults()
+	name = defaults.issuerName
+	kind = defaults.issuerKind
+	group = defaults.issuerGroup
 	annotations := ing.Annotations
 
 	if annotations == nil {
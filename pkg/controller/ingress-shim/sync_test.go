@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	extv1beta1 "k8s.io/api/extensions/v1beta1"
@@ -560,6 +561,32 @@ func TestSync(t *testing.T) {
 			},
 			IssuerLister: []runtime.Object{acmeIssuer},
 		},
+		{
+			Name:           "should return an error when an annotation has an invalid value",
+			Issuer:         acmeIssuer,
+			Err:            true,
+			ExpectedEvents: []string{`Warning BadConfig Invalid value for annotation "cert-manager.io/common-name": must be no more than 64 characters`},
+			Ingress: &extv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-name",
+					Namespace: gen.DefaultTestNamespace,
+					Annotations: map[string]string{
+						cmapi.IngressIssuerNameAnnotationKey: "issuer-name",
+						cmapi.CommonNameAnnotationKey:        strings.Repeat("a", 65) + ".example.com",
+					},
+					UID: types.UID("ingress-name"),
+				},
+				Spec: extv1beta1.IngressSpec{
+					TLS: []extv1beta1.IngressTLS{
+						{
+							Hosts:      []string{"example.com"},
+							SecretName: "example-com-tls",
+						},
+					},
+				},
+			},
+			IssuerLister: []runtime.Object{acmeIssuer},
+		},
 		{
 			Name: "should error if the specified issuer is not found",
 			Err:  true,
@@ -22,6 +22,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 )
 
@@ -51,6 +52,12 @@ func (c *controller) issuersForSecret(secret *corev1.Secret) ([]*v1alpha2.Issuer
 					continue
 				}
 			}
+			// Secrets of DNS01 providers are checked when their credentials
+			// are verified during setup
+			if apiutil.ACMESolversReferenceSecret(iss.Spec.ACME.Solvers, secret.Name) {
+				affected = append(affected, iss)
+				continue
+			}
 		case iss.Spec.CA != nil:
 			if iss.Spec.CA.SecretName == secret.Name {
 				affected = append(affected, iss)
@@ -51,6 +51,14 @@ type controller struct {
 	// used to record Events about resources to the API
 	recorder record.EventRecorder
 
+	// fieldManager is the field manager of the controller, whose changes
+	// are not reported as use of deprecated APIs
+	fieldManager string
+
+	// statusFieldManager is the field manager used to apply the status of
+	// Issuers if the ServerSideApply feature is enabled
+	statusFieldManager string
+
 	// issuerFactory is used to obtain a reference to the Issuer implementation
 	// for each ClusterIssuer resource
 	issuerFactory issuer.Factory
@@ -64,7 +72,7 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.log = logf.FromContext(ctx.RootContext, ControllerName)
 
 	// create a queue used to queue up items to be processed
-	c.queue = workqueue.NewNamedRateLimitingQueue(controllerpkg.DefaultItemBasedRateLimiter(), ControllerName)
+	c.queue = controllerpkg.NewRateLimitingQueue(ctx.BackoffPersister, controllerpkg.DefaultItemBasedRateLimiter(), ControllerName)
 
 	// obtain references to all the informers used by this controller
 	issuerInformer := ctx.SharedInformerFactory.Certmanager().V1alpha2().Issuers()
@@ -88,6 +96,8 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.issuerFactory = issuer.NewFactory(ctx)
 	c.cmClient = ctx.CMClient
 	c.recorder = ctx.Recorder
+	c.fieldManager = ctx.FieldManager()
+	c.statusFieldManager = ctx.FieldManagerFor(ControllerName)
 
 	return c.queue, mustSync, nil
 }
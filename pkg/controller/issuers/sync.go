@@ -29,8 +29,11 @@ import (
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/feature"
 	internalapi "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/apply"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 	"github.com/jetstack/cert-manager/pkg/webhook"
 )
 
@@ -41,6 +44,16 @@ const (
 	messageErrorInitIssuer = "Error initializing issuer: "
 )
 
+// statusFields are the status fields and conditions of Issuers that are managed
+// by this controller.
+var statusFields = apply.Fields{
+	Status: []string{"acme"},
+	Conditions: []string{
+		string(v1alpha2.IssuerConditionReady),
+		string(v1alpha2.IssuerConditionDeprecated),
+	},
+}
+
 func (c *controller) Sync(ctx context.Context, iss *v1alpha2.Issuer) (err error) {
 	log := logf.FromContext(ctx)
 
@@ -51,6 +64,8 @@ func (c *controller) Sync(ctx context.Context, iss *v1alpha2.Issuer) (err error)
 		}
 	}()
 
+	apiutil.SetIssuerDeprecatedCondition(issuerCopy, c.fieldManager)
+
 	el := webhook.ValidationRegistry.Validate(issuerCopy, internalapi.SchemeGroupVersion.WithKind("Issuer"))
 	if len(el) > 0 {
 		msg := fmt.Sprintf("Resource validation failed: %v", el.ToAggregate())
@@ -92,5 +107,8 @@ func (c *controller) updateIssuerStatus(old, new *v1alpha2.Issuer) (*v1alpha2.Is
 	if reflect.DeepEqual(old.Status, new.Status) {
 		return nil, nil
 	}
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		return apply.IssuerStatus(context.TODO(), c.cmClient, c.statusFieldManager, statusFields, old, new)
+	}
 	return c.cmClient.CertmanagerV1alpha2().Issuers(new.Namespace).UpdateStatus(context.TODO(), new, metav1.UpdateOptions{})
 }
@@ -0,0 +1,343 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package legacymigration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	extlisters "k8s.io/client-go/listers/extensions/v1beta1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+const (
+	ControllerName = "legacymigration"
+
+	// scanInterval is how often the legacy resources are listed. The legacy
+	// API group is not watched, as its CRDs may not be installed.
+	scanInterval = 5 * time.Minute
+
+	ingressKind = "Ingress"
+)
+
+var (
+	legacyGroupVersion   = schema.GroupVersion{Group: "certmanager.k8s.io", Version: "v1alpha1"}
+	legacyCertificates   = legacyGroupVersion.WithResource("certificates")
+	legacyIssuers        = legacyGroupVersion.WithResource("issuers")
+	legacyClusterIssuers = legacyGroupVersion.WithResource("clusterissuers")
+)
+
+// controller migrates Certificates, Issuers and ClusterIssuers of the legacy
+// certmanager.k8s.io API group to the cert-manager.io API group, and copies
+// legacy annotations on Ingresses to their current equivalent.
+// Legacy resources are never modified or deleted, and existing resources of
+// the cert-manager.io API group are never overwritten.
+// The progress of the migration is reported in the status ConfigMap.
+type controller struct {
+	ingressLister extlisters.IngressLister
+
+	// maintain a reference to the workqueue for this controller
+	// so the scan function can enqueue resources
+	queue workqueue.RateLimitingInterface
+
+	// logger to be used by this controller
+	log logr.Logger
+
+	// dynamicClient is used to access the legacy resources, for which no
+	// typed client exists
+	dynamicClient dynamic.Interface
+	cmClient      cmclient.Interface
+	kubeClient    kubernetes.Interface
+
+	// namespace restricts the migration to a single namespace if set
+	namespace string
+	// statusNamespace is the namespace of the status ConfigMap
+	statusNamespace string
+}
+
+// Register registers and constructs the controller using the provided context.
+// It returns the workqueue to be used to enqueue items, a list of
+// InformerSynced functions that must be synced, or an error.
+func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	c.log = logf.FromContext(ctx.RootContext, ControllerName)
+
+	// create a queue used to queue up items to be processed
+	c.queue = controllerpkg.NewRateLimitingQueue(ctx.BackoffPersister, controllerpkg.DefaultItemBasedRateLimiter(), ControllerName)
+
+	ingressInformer := ctx.KubeSharedInformerFactory.Extensions().V1beta1().Ingresses()
+	mustSync := []cache.InformerSynced{
+		ingressInformer.Informer().HasSynced,
+	}
+	c.ingressLister = ingressInformer.Lister()
+	ingressInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{WorkFunc: c.ingressChanged})
+
+	dynamicClient, err := dynamic.NewForConfig(ctx.RESTConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating dynamic client: %v", err)
+	}
+	c.dynamicClient = dynamicClient
+	c.cmClient = ctx.CMClient
+	c.kubeClient = ctx.Client
+	c.namespace = ctx.Namespace
+	c.statusNamespace = ctx.ClusterResourceNamespace
+
+	return c.queue, mustSync, nil
+}
+
+func (c *controller) ingressChanged(obj interface{}) {
+	ing, ok := obj.(*extv1beta1.Ingress)
+	if !ok {
+		c.log.Error(nil, "object was not an ingress object")
+		return
+	}
+	if hasLegacyAnnotations(ing.Annotations) {
+		c.queue.Add(newKey(ingressKind, ing.Namespace, ing.Name))
+	}
+}
+
+// scan lists all legacy resources and queues them to be migrated.
+func (c *controller) scan(ctx context.Context) {
+	resources := map[string]schema.GroupVersionResource{
+		cmapi.CertificateKind: legacyCertificates,
+		cmapi.IssuerKind:      legacyIssuers,
+	}
+	// ClusterIssuers are not migrated if cert-manager is scoped to a single namespace
+	if c.namespace == "" {
+		resources[cmapi.ClusterIssuerKind] = legacyClusterIssuers
+	}
+
+	for kind, gvr := range resources {
+		list, err := c.listLegacy(ctx, gvr)
+		if apierrors.IsNotFound(err) {
+			c.log.V(logf.DebugLevel).Info("legacy resource type not installed, skipping", "resource", gvr.String())
+			continue
+		}
+		if err != nil {
+			c.log.Error(err, "error listing legacy resources", "resource", gvr.String())
+			continue
+		}
+		for _, item := range list.Items {
+			c.queue.Add(newKey(kind, item.GetNamespace(), item.GetName()))
+		}
+	}
+}
+
+func (c *controller) listLegacy(ctx context.Context, gvr schema.GroupVersionResource) (*unstructured.UnstructuredList, error) {
+	if gvr == legacyClusterIssuers {
+		return c.dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+	}
+	return c.dynamicClient.Resource(gvr).Namespace(c.namespace).List(ctx, metav1.ListOptions{})
+}
+
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+	kind, namespace, name := splitKey(key)
+
+	var status string
+	var err error
+	switch kind {
+	case cmapi.CertificateKind:
+		status, err = c.migrateCertificate(ctx, namespace, name)
+	case cmapi.IssuerKind, cmapi.ClusterIssuerKind:
+		status, err = c.migrateIssuer(ctx, kind, namespace, name)
+	case ingressKind:
+		status, err = c.migrateIngress(ctx, namespace, name)
+	default:
+		log.Error(nil, "invalid resource key")
+		return nil
+	}
+	if err != nil {
+		log.Error(err, "error migrating legacy resource")
+		if statusErr := c.setStatus(ctx, key, statusFailedPrefix+err.Error()); statusErr != nil {
+			log.Error(statusErr, "error updating migration status")
+		}
+		return err
+	}
+
+	if status != "" && status != statusMigrated && status != statusExists {
+		log.Info("legacy resource cannot be migrated", "reason", status)
+	}
+	return c.setStatus(ctx, key, status)
+}
+
+// migrateCertificate creates a Certificate for the legacy Certificate with
+// the given name, and returns the status of the migration.
+func (c *controller) migrateCertificate(ctx context.Context, namespace, name string) (string, error) {
+	legacy, err := c.dynamicClient.Resource(legacyCertificates).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	_, err = c.cmClient.CertmanagerV1alpha2().Certificates(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return statusExists, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return "", err
+	}
+
+	crt, err := convertCertificate(legacy)
+	if err != nil {
+		// retrying will not fix an invalid resource, so only report it
+		return statusFailedPrefix + err.Error(), nil
+	}
+
+	_, err = c.cmClient.CertmanagerV1alpha2().Certificates(namespace).Create(ctx, crt, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return statusExists, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return statusMigrated, nil
+}
+
+// migrateIssuer creates an Issuer or ClusterIssuer for the legacy resource of
+// the same kind and name, and returns the status of the migration.
+func (c *controller) migrateIssuer(ctx context.Context, kind, namespace, name string) (string, error) {
+	var legacy *unstructured.Unstructured
+	var err error
+	if kind == cmapi.ClusterIssuerKind {
+		legacy, err = c.dynamicClient.Resource(legacyClusterIssuers).Get(ctx, name, metav1.GetOptions{})
+	} else {
+		legacy, err = c.dynamicClient.Resource(legacyIssuers).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if kind == cmapi.ClusterIssuerKind {
+		_, err = c.cmClient.CertmanagerV1alpha2().ClusterIssuers().Get(ctx, name, metav1.GetOptions{})
+	} else {
+		_, err = c.cmClient.CertmanagerV1alpha2().Issuers(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err == nil {
+		return statusExists, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return "", err
+	}
+
+	// The legacy Certificates are needed to learn which solver was used for
+	// which domain
+	certNamespace := namespace
+	if kind == cmapi.ClusterIssuerKind {
+		certNamespace = c.namespace
+	}
+	certs, err := c.dynamicClient.Resource(legacyCertificates).Namespace(certNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", err
+	}
+	var certItems []unstructured.Unstructured
+	if certs != nil {
+		certItems = certs.Items
+	}
+
+	spec, err := convertIssuerSpec(legacy, certItems)
+	if err != nil {
+		// retrying will not fix an invalid resource, so only report it
+		return statusFailedPrefix + err.Error(), nil
+	}
+
+	meta := convertObjectMeta(legacy)
+	if kind == cmapi.ClusterIssuerKind {
+		_, err = c.cmClient.CertmanagerV1alpha2().ClusterIssuers().Create(ctx, &cmapi.ClusterIssuer{ObjectMeta: meta, Spec: spec}, metav1.CreateOptions{})
+	} else {
+		_, err = c.cmClient.CertmanagerV1alpha2().Issuers(namespace).Create(ctx, &cmapi.Issuer{ObjectMeta: meta, Spec: spec}, metav1.CreateOptions{})
+	}
+	if apierrors.IsAlreadyExists(err) {
+		return statusExists, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return statusMigrated, nil
+}
+
+// migrateIngress copies the legacy annotations of an Ingress to their current
+// equivalent, and returns the status of the migration.
+func (c *controller) migrateIngress(ctx context.Context, namespace, name string) (string, error) {
+	ing, err := c.ingressLister.Ingresses(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	annotations, changed := migrateAnnotations(ing.Annotations)
+	if !changed {
+		return statusMigrated, nil
+	}
+
+	ing = ing.DeepCopy()
+	ing.Annotations = annotations
+	_, err = c.kubeClient.ExtensionsV1beta1().Ingresses(namespace).Update(ctx, ing, metav1.UpdateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return statusMigrated, nil
+}
+
+// newKey returns the work queue key for a resource. Keys include the kind,
+// as resources of different kinds are processed by this controller.
+func newKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// splitKey returns the kind, namespace and name of the resource identified by
+// a key returned by newKey.
+func splitKey(key string) (kind, namespace, name string) {
+	parts := strings.SplitN(key, "/", 3)
+	if len(parts) != 3 {
+		return "", "", ""
+	}
+	return parts[0], parts[1], parts[2]
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		c := &controller{}
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(c).
+			With(c.scan, scanInterval).
+			Complete()
+	})
+}
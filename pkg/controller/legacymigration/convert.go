@@ -0,0 +1,306 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package legacymigration
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+)
+
+// legacyAnnotations maps the annotations of the legacy certmanager.k8s.io API
+// group to their current equivalent. Legacy annotations that have no current
+// equivalent, like 'certmanager.k8s.io/acme-challenge-type', are not listed
+// as they have been replaced by the solvers configured on the Issuer.
+var legacyAnnotations = map[string]string{
+	"certmanager.k8s.io/issuer":                    cmapi.IngressIssuerNameAnnotationKey,
+	"certmanager.k8s.io/cluster-issuer":            cmapi.IngressClusterIssuerNameAnnotationKey,
+	"certmanager.k8s.io/acme-http01-edit-in-place": cmacme.IngressEditInPlaceAnnotationKey,
+	"certmanager.k8s.io/acme-http01-ingress-class": cmapi.IngressACMEIssuerHTTP01IngressClassAnnotationKey,
+	"certmanager.k8s.io/issuer-name":               cmapi.IssuerNameAnnotationKey,
+	"certmanager.k8s.io/issuer-kind":               cmapi.IssuerKindAnnotationKey,
+	"certmanager.k8s.io/alt-names":                 cmapi.AltNamesAnnotationKey,
+	"certmanager.k8s.io/ip-sans":                   cmapi.IPSANAnnotationKey,
+	"certmanager.k8s.io/common-name":               cmapi.CommonNameAnnotationKey,
+	"certmanager.k8s.io/certificate-name":          cmapi.CertificateNameKey,
+}
+
+// hasLegacyAnnotations returns true if any of the given annotations have
+// not been migrated to their current equivalent yet.
+func hasLegacyAnnotations(annotations map[string]string) bool {
+	_, changed := migrateAnnotations(annotations)
+	return changed
+}
+
+// migrateAnnotations returns a copy of annotations where the value of every
+// legacy annotation has been copied to its current equivalent, unless that is
+// already set. Legacy annotations are kept so that older versions of
+// cert-manager continue to work during the upgrade.
+// The returned bool is true if any annotation was added.
+func migrateAnnotations(annotations map[string]string) (map[string]string, bool) {
+	out := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		out[k] = v
+	}
+
+	changed := false
+	for legacy, current := range legacyAnnotations {
+		v, ok := annotations[legacy]
+		if !ok {
+			continue
+		}
+		if _, ok := annotations[current]; ok {
+			continue
+		}
+		out[current] = v
+		changed = true
+	}
+
+	return out, changed
+}
+
+// legacyCertificateSpec holds the fields of a legacy Certificate spec that
+// have no equivalent on the current Certificate spec.
+type legacyCertificateSpec struct {
+	IssuerRef struct {
+		Name string `json:"name"`
+		Kind string `json:"kind"`
+	} `json:"issuerRef"`
+
+	ACME *struct {
+		Config []legacyDomainSolverConfig `json:"config"`
+	} `json:"acme,omitempty"`
+}
+
+// legacyDomainSolverConfig selected the solver used for a list of domains of
+// a legacy Certificate.
+type legacyDomainSolverConfig struct {
+	Domains []string `json:"domains"`
+	HTTP01  *struct {
+		Ingress      string  `json:"ingress,omitempty"`
+		IngressClass *string `json:"ingressClass,omitempty"`
+	} `json:"http01,omitempty"`
+	DNS01 *struct {
+		Provider string `json:"provider"`
+	} `json:"dns01,omitempty"`
+}
+
+// legacyACMEIssuer holds the solver configuration of a legacy ACME issuer,
+// which has been replaced by spec.acme.solvers.
+type legacyACMEIssuer struct {
+	HTTP01 *struct {
+		ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+	} `json:"http01,omitempty"`
+	DNS01 *struct {
+		Providers []legacyDNS01Provider `json:"providers,omitempty"`
+	} `json:"dns01,omitempty"`
+}
+
+// legacyDNS01Provider is a named DNS01 provider of a legacy ACME issuer. Apart
+// from the name, its fields are the same as the ones of a current DNS01
+// solver.
+type legacyDNS01Provider struct {
+	Name string `json:"name"`
+	cmacme.ACMEChallengeSolverDNS01
+}
+
+// convertCertificate converts a legacy certmanager.k8s.io Certificate to a
+// cert-manager.io Certificate.
+func convertCertificate(legacy *unstructured.Unstructured) (*cmapi.Certificate, error) {
+	crt := &cmapi.Certificate{ObjectMeta: convertObjectMeta(legacy)}
+	if err := decodeField(legacy, &crt.Spec, "spec"); err != nil {
+		return nil, err
+	}
+	if crt.Spec.SecretName == "" {
+		return nil, fmt.Errorf("spec.secretName is not set")
+	}
+	if crt.Spec.IssuerRef.Name == "" {
+		return nil, fmt.Errorf("spec.issuerRef.name is not set")
+	}
+	return crt, nil
+}
+
+// convertIssuerSpec converts the spec of a legacy certmanager.k8s.io Issuer or
+// ClusterIssuer to a cert-manager.io IssuerSpec.
+// Legacy ACME issuers defined their solvers on the issuer, but the solver
+// used for each domain was selected by the Certificates. The legacy
+// Certificates referencing the issuer are used to build a list of solvers
+// with selectors that match the domains they were used for.
+func convertIssuerSpec(legacy *unstructured.Unstructured, certs []unstructured.Unstructured) (cmapi.IssuerSpec, error) {
+	var spec cmapi.IssuerSpec
+	if err := decodeField(legacy, &spec, "spec"); err != nil {
+		return spec, err
+	}
+	if spec.ACME == nil || len(spec.ACME.Solvers) > 0 {
+		return spec, nil
+	}
+
+	var legacyACME legacyACMEIssuer
+	if err := decodeField(legacy, &legacyACME, "spec", "acme"); err != nil {
+		return spec, err
+	}
+
+	solvers, err := convertSolvers(legacy.GetKind(), legacy.GetName(), &legacyACME, certs)
+	if err != nil {
+		return spec, err
+	}
+	spec.ACME.Solvers = solvers
+
+	return spec, nil
+}
+
+func convertSolvers(kind, name string, legacy *legacyACMEIssuer, certs []unstructured.Unstructured) ([]cmacme.ACMEChallengeSolver, error) {
+	providers := make(map[string]cmacme.ACMEChallengeSolverDNS01)
+	if legacy.DNS01 != nil {
+		for _, p := range legacy.DNS01.Providers {
+			providers[p.Name] = p.ACMEChallengeSolverDNS01
+		}
+	}
+	var serviceType corev1.ServiceType
+	if legacy.HTTP01 != nil {
+		serviceType = legacy.HTTP01.ServiceType
+	}
+
+	// solvers and the domains they are used for, keyed by a string
+	// identifying the solver configuration
+	solvers := make(map[string]*cmacme.ACMEChallengeSolver)
+	domains := make(map[string]sets.String)
+	for i := range certs {
+		var crtSpec legacyCertificateSpec
+		if err := decodeField(&certs[i], &crtSpec, "spec"); err != nil {
+			return nil, fmt.Errorf("Certificate %s/%s: %v", certs[i].GetNamespace(), certs[i].GetName(), err)
+		}
+		if !referencesIssuer(crtSpec, kind, name) || crtSpec.ACME == nil {
+			continue
+		}
+
+		for _, cfg := range crtSpec.ACME.Config {
+			var key string
+			var solver cmacme.ACMEChallengeSolver
+			switch {
+			case cfg.HTTP01 != nil:
+				key = fmt.Sprintf("http01/%s", cfg.HTTP01.Ingress)
+				if cfg.HTTP01.IngressClass != nil {
+					key += "/" + *cfg.HTTP01.IngressClass
+				}
+				solver.HTTP01 = &cmacme.ACMEChallengeSolverHTTP01{Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+					ServiceType: serviceType,
+					Class:       cfg.HTTP01.IngressClass,
+					Name:        cfg.HTTP01.Ingress,
+				}}
+			case cfg.DNS01 != nil:
+				provider, ok := providers[cfg.DNS01.Provider]
+				if !ok {
+					return nil, fmt.Errorf("Certificate %s/%s references dns01 provider %q which is not configured on the issuer",
+						certs[i].GetNamespace(), certs[i].GetName(), cfg.DNS01.Provider)
+				}
+				key = "dns01/" + cfg.DNS01.Provider
+				solver.DNS01 = provider.DeepCopy()
+			default:
+				continue
+			}
+
+			if _, ok := solvers[key]; !ok {
+				solvers[key] = &solver
+				domains[key] = sets.NewString()
+			}
+			domains[key].Insert(cfg.Domains...)
+		}
+	}
+
+	// Without any Certificates to learn the domains from, all solvers of the
+	// issuer are migrated without selectors
+	if len(solvers) == 0 {
+		var out []cmacme.ACMEChallengeSolver
+		if legacy.HTTP01 != nil {
+			out = append(out, cmacme.ACMEChallengeSolver{HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+				Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{ServiceType: serviceType},
+			}})
+		}
+		if legacy.DNS01 != nil {
+			for _, p := range legacy.DNS01.Providers {
+				out = append(out, cmacme.ACMEChallengeSolver{DNS01: p.ACMEChallengeSolverDNS01.DeepCopy()})
+			}
+		}
+		return out, nil
+	}
+
+	keys := make([]string, 0, len(solvers))
+	for key := range solvers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	out := make([]cmacme.ACMEChallengeSolver, 0, len(keys))
+	for _, key := range keys {
+		solver := solvers[key]
+		solver.Selector = &cmacme.CertificateDNSNameSelector{DNSNames: domains[key].List()}
+		out = append(out, *solver)
+	}
+	return out, nil
+}
+
+// referencesIssuer returns true if the legacy Certificate spec references the
+// issuer with the given kind and name.
+func referencesIssuer(spec legacyCertificateSpec, kind, name string) bool {
+	specKind := spec.IssuerRef.Kind
+	if specKind == "" {
+		specKind = cmapi.IssuerKind
+	}
+	return specKind == kind && spec.IssuerRef.Name == name
+}
+
+// convertObjectMeta returns the metadata to use for the current version of a
+// legacy resource. Annotations are migrated, and the last applied
+// configuration of kubectl is dropped as it refers to the legacy resource.
+func convertObjectMeta(legacy *unstructured.Unstructured) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{
+		Name:      legacy.GetName(),
+		Namespace: legacy.GetNamespace(),
+		Labels:    legacy.GetLabels(),
+	}
+	if annotations := legacy.GetAnnotations(); len(annotations) > 0 {
+		meta.Annotations, _ = migrateAnnotations(annotations)
+		delete(meta.Annotations, corev1.LastAppliedConfigAnnotation)
+	}
+	return meta
+}
+
+// decodeField decodes the field of obj found at the given path into out.
+// Fields that are unknown to out are ignored.
+func decodeField(obj *unstructured.Unstructured, out interface{}, fields ...string) error {
+	field, found, err := unstructured.NestedFieldNoCopy(obj.Object, fields...)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+	data, err := json.Marshal(field)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package legacymigration
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func legacyObject(kind, namespace, name string, spec map[string]interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "certmanager.k8s.io/v1alpha1",
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": spec,
+	}}
+}
+
+func TestMigrateAnnotations(t *testing.T) {
+	annotations := map[string]string{
+		"certmanager.k8s.io/cluster-issuer":      "letsencrypt",
+		"certmanager.k8s.io/acme-challenge-type": "http01",
+		"certmanager.k8s.io/issuer":              "legacy",
+		cmapi.IngressIssuerNameAnnotationKey:     "current",
+		"kubernetes.io/ingress.class":            "nginx",
+	}
+
+	out, changed := migrateAnnotations(annotations)
+	if !changed {
+		t.Errorf("expected annotations to be changed")
+	}
+	exp := map[string]string{
+		"certmanager.k8s.io/cluster-issuer":         "letsencrypt",
+		"certmanager.k8s.io/acme-challenge-type":    "http01",
+		"certmanager.k8s.io/issuer":                 "legacy",
+		cmapi.IngressIssuerNameAnnotationKey:        "current",
+		cmapi.IngressClusterIssuerNameAnnotationKey: "letsencrypt",
+		"kubernetes.io/ingress.class":               "nginx",
+	}
+	if !reflect.DeepEqual(out, exp) {
+		t.Errorf("unexpected annotations; expected: %v, got: %v", exp, out)
+	}
+
+	if _, changed := migrateAnnotations(out); changed {
+		t.Errorf("expected migrated annotations to not be changed again")
+	}
+}
+
+func TestConvertCertificate(t *testing.T) {
+	legacy := legacyObject("Certificate", "default", "example", map[string]interface{}{
+		"secretName": "example-tls",
+		"dnsNames":   []interface{}{"example.com"},
+		"issuerRef":  map[string]interface{}{"name": "letsencrypt", "kind": "ClusterIssuer"},
+		"acme": map[string]interface{}{
+			"config": []interface{}{
+				map[string]interface{}{"domains": []interface{}{"example.com"}, "http01": map[string]interface{}{}},
+			},
+		},
+	})
+	legacy.SetAnnotations(map[string]string{corev1.LastAppliedConfigAnnotation: "{}"})
+
+	crt, err := convertCertificate(&legacy)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expSpec := cmapi.CertificateSpec{
+		SecretName: "example-tls",
+		DNSNames:   []string{"example.com"},
+		IssuerRef:  cmmeta.ObjectReference{Name: "letsencrypt", Kind: "ClusterIssuer"},
+	}
+	if !reflect.DeepEqual(crt.Spec, expSpec) {
+		t.Errorf("unexpected spec; expected: %+v, got: %+v", expSpec, crt.Spec)
+	}
+	if _, ok := crt.Annotations[corev1.LastAppliedConfigAnnotation]; ok {
+		t.Errorf("expected last applied configuration annotation to be removed")
+	}
+
+	invalid := legacyObject("Certificate", "default", "invalid", map[string]interface{}{
+		"issuerRef": map[string]interface{}{"name": "letsencrypt"},
+	})
+	if _, err := convertCertificate(&invalid); err == nil {
+		t.Errorf("expected error for Certificate without secretName")
+	}
+}
+
+func TestConvertIssuerSpec(t *testing.T) {
+	acme := map[string]interface{}{
+		"server":              "https://acme-v02.api.letsencrypt.org/directory",
+		"privateKeySecretRef": map[string]interface{}{"name": "letsencrypt"},
+		"http01":              map[string]interface{}{"serviceType": "NodePort"},
+		"dns01": map[string]interface{}{
+			"providers": []interface{}{
+				map[string]interface{}{
+					"name":       "cf",
+					"cloudflare": map[string]interface{}{"email": "me@example.com"},
+				},
+			},
+		},
+	}
+	issuer := legacyObject("Issuer", "default", "letsencrypt", map[string]interface{}{"acme": acme})
+	nginx := "nginx"
+
+	tests := map[string]struct {
+		certs      []unstructured.Unstructured
+		expSolvers []cmacme.ACMEChallengeSolver
+		expErr     bool
+	}{
+		"without Certificates all solvers are migrated without selectors": {
+			expSolvers: []cmacme.ACMEChallengeSolver{
+				{HTTP01: &cmacme.ACMEChallengeSolverHTTP01{Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{ServiceType: corev1.ServiceTypeNodePort}}},
+				{DNS01: &cmacme.ACMEChallengeSolverDNS01{Cloudflare: &cmacme.ACMEIssuerDNS01ProviderCloudflare{Email: "me@example.com"}}},
+			},
+		},
+		"selectors are built from the domains of the Certificates referencing the issuer": {
+			certs: []unstructured.Unstructured{
+				legacyObject("Certificate", "default", "web", map[string]interface{}{
+					"secretName": "web-tls",
+					"issuerRef":  map[string]interface{}{"name": "letsencrypt"},
+					"acme": map[string]interface{}{"config": []interface{}{
+						map[string]interface{}{"domains": []interface{}{"www.example.com", "example.com"}, "http01": map[string]interface{}{"ingressClass": "nginx"}},
+						map[string]interface{}{"domains": []interface{}{"*.example.com"}, "dns01": map[string]interface{}{"provider": "cf"}},
+					}},
+				}),
+				legacyObject("Certificate", "default", "other-issuer", map[string]interface{}{
+					"secretName": "other-tls",
+					"issuerRef":  map[string]interface{}{"name": "letsencrypt", "kind": "ClusterIssuer"},
+					"acme": map[string]interface{}{"config": []interface{}{
+						map[string]interface{}{"domains": []interface{}{"other.com"}, "dns01": map[string]interface{}{"provider": "cf"}},
+					}},
+				}),
+			},
+			expSolvers: []cmacme.ACMEChallengeSolver{
+				{
+					Selector: &cmacme.CertificateDNSNameSelector{DNSNames: []string{"*.example.com"}},
+					DNS01:    &cmacme.ACMEChallengeSolverDNS01{Cloudflare: &cmacme.ACMEIssuerDNS01ProviderCloudflare{Email: "me@example.com"}},
+				},
+				{
+					Selector: &cmacme.CertificateDNSNameSelector{DNSNames: []string{"example.com", "www.example.com"}},
+					HTTP01:   &cmacme.ACMEChallengeSolverHTTP01{Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{ServiceType: corev1.ServiceTypeNodePort, Class: &nginx}},
+				},
+			},
+		},
+		"Certificate referencing an unknown provider returns an error": {
+			certs: []unstructured.Unstructured{
+				legacyObject("Certificate", "default", "web", map[string]interface{}{
+					"secretName": "web-tls",
+					"issuerRef":  map[string]interface{}{"name": "letsencrypt"},
+					"acme": map[string]interface{}{"config": []interface{}{
+						map[string]interface{}{"domains": []interface{}{"example.com"}, "dns01": map[string]interface{}{"provider": "route53"}},
+					}},
+				}),
+			},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			spec, err := convertIssuerSpec(&issuer, test.certs)
+			if (err != nil) != test.expErr {
+				t.Fatalf("expected error: %t, got: %v", test.expErr, err)
+			}
+			if test.expErr {
+				return
+			}
+			if spec.ACME == nil || spec.ACME.Server != acme["server"] || spec.ACME.PrivateKey.Name != "letsencrypt" {
+				t.Errorf("expected ACME configuration to be converted, got: %+v", spec.ACME)
+			}
+			if !reflect.DeepEqual(spec.ACME.Solvers, test.expSolvers) {
+				t.Errorf("unexpected solvers; expected: %+v, got: %+v", test.expSolvers, spec.ACME.Solvers)
+			}
+		})
+	}
+}
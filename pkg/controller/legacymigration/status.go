@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package legacymigration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// StatusConfigMapName is the name of the ConfigMap in the cluster resource
+	// namespace that the progress of the migration is reported in.
+	// It contains one entry per legacy resource, keyed by
+	// '<kind>.<namespace>.<name>', and a summary entry.
+	StatusConfigMapName = "cert-manager-legacy-migration"
+
+	summaryKey = "summary"
+
+	// statusMigrated means the resource has been migrated
+	statusMigrated = "Migrated"
+	// statusExists means a current resource with the same name already
+	// existed, and has been left unchanged
+	statusExists = "Exists"
+	// statusFailedPrefix is the prefix of the status of resources that
+	// could not be migrated
+	statusFailedPrefix = "Failed: "
+)
+
+// statusKey returns the key of the entry for the resource identified by
+// the given work queue key in the status ConfigMap.
+func statusKey(key string) string {
+	kind, namespace, name := splitKey(key)
+	if namespace == "" {
+		return kind + "." + name
+	}
+	return kind + "." + namespace + "." + name
+}
+
+// setStatus records the migration status of the resource identified by key
+// in the status ConfigMap. An empty status removes the entry.
+func (c *controller) setStatus(ctx context.Context, key, status string) error {
+	dataKey := statusKey(key)
+
+	cm, err := c.kubeClient.CoreV1().ConfigMaps(c.statusNamespace).Get(ctx, StatusConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if status == "" {
+			return nil
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      StatusConfigMapName,
+				Namespace: c.statusNamespace,
+			},
+			Data: map[string]string{dataKey: status},
+		}
+		cm.Data[summaryKey] = summarize(cm.Data)
+		_, err = c.kubeClient.CoreV1().ConfigMaps(c.statusNamespace).Create(ctx, cm, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if current, ok := cm.Data[dataKey]; (ok && current == status) || (!ok && status == "") {
+		return nil
+	}
+
+	cm = cm.DeepCopy()
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	if status == "" {
+		delete(cm.Data, dataKey)
+	} else {
+		cm.Data[dataKey] = status
+	}
+	cm.Data[summaryKey] = summarize(cm.Data)
+
+	_, err = c.kubeClient.CoreV1().ConfigMaps(c.statusNamespace).Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// summarize counts the resources in each state.
+func summarize(data map[string]string) string {
+	var migrated, exists, failed int
+	for k, v := range data {
+		if k == summaryKey {
+			continue
+		}
+		switch {
+		case v == statusMigrated:
+			migrated++
+		case v == statusExists:
+			exists++
+		case strings.HasPrefix(v, statusFailedPrefix):
+			failed++
+		}
+	}
+	return fmt.Sprintf("migrated: %d, already existing: %d, failed: %d", migrated, exists, failed)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+)
+
+// RuntimeConfig holds the configuration of the controller that may change
+// whilst it is running, for example when its configuration file is
+// reloaded. Controllers should register a function with OnChange to be
+// notified of changes rather than copying values out of it once.
+type RuntimeConfig struct {
+	lock      sync.RWMutex
+	values    RuntimeConfigValues
+	listeners []func(RuntimeConfigValues)
+}
+
+// RuntimeConfigValues are the values held by a RuntimeConfig.
+type RuntimeConfigValues struct {
+	// KubernetesAPIQPS and KubernetesAPIBurst limit the rate of requests
+	// made to the Kubernetes apiserver by each clientset.
+	KubernetesAPIQPS   float32
+	KubernetesAPIBurst int
+
+	// NumberOfConcurrentWorkers is the number of items each controller
+	// processes concurrently.
+	NumberOfConcurrentWorkers int
+
+	SchedulerOptions
+	IngressShimOptions
+}
+
+// NewRuntimeConfig returns a RuntimeConfig holding the given values.
+func NewRuntimeConfig(values RuntimeConfigValues) *RuntimeConfig {
+	return &RuntimeConfig{values: values}
+}
+
+// Get returns the current values.
+func (c *RuntimeConfig) Get() RuntimeConfigValues {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.values
+}
+
+// Set replaces the current values and calls each function registered with
+// OnChange with the new values.
+func (c *RuntimeConfig) Set(values RuntimeConfigValues) {
+	c.lock.Lock()
+	c.values = values
+	listeners := append([]func(RuntimeConfigValues){}, c.listeners...)
+	c.lock.Unlock()
+
+	for _, fn := range listeners {
+		fn(values)
+	}
+}
+
+// OnChange registers a function to be called with the new values each time
+// they are changed with Set.
+func (c *RuntimeConfig) OnChange(fn func(RuntimeConfigValues)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.listeners = append(c.listeners, fn)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfstatus
+
+import (
+	"context"
+	"crypto/x509"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
+)
+
+const (
+	ControllerName = "selfstatus"
+
+	// Names of the components whose certificates are checked, used as the
+	// component label of metrics and as the names of the components in the
+	// SelfStatus resource.
+	ComponentWebhookCA = "webhook-ca"
+	ComponentWebhook   = "webhook"
+	ComponentStatusAPI = "status-api"
+
+	defaultCheckInterval = time.Minute
+)
+
+// controller checks the certificates used by the components of cert-manager
+// itself, such as the serving certificate of the webhook, and reports their
+// expiry and rotation state as metrics and as conditions on the SelfStatus
+// resource. Without
+// it, an internal certificate that failed to rotate only surfaces as TLS
+// handshake errors in the API server or clients.
+type controller struct {
+	// maintain a reference to the workqueue for this controller
+	// so the check function can enqueue components
+	queue workqueue.RateLimitingInterface
+
+	// logger to be used by this controller
+	log logr.Logger
+
+	cmClient cmclient.Interface
+	metrics  *metrics.Metrics
+	clock    clock.Clock
+
+	// sources load the certificate of each configured component
+	sources map[string]source
+}
+
+// Register registers and constructs the controller using the provided context.
+// It returns the workqueue to be used to enqueue items, a list of
+// InformerSynced functions that must be synced, or an error.
+func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	c.log = logf.FromContext(ctx.RootContext, ControllerName)
+
+	// create a queue used to queue up items to be processed
+	c.queue = controllerpkg.NewRateLimitingQueue(ctx.BackoffPersister, controllerpkg.DefaultItemBasedRateLimiter(), ControllerName)
+
+	c.cmClient = ctx.CMClient
+	c.metrics = ctx.Metrics
+	c.clock = ctx.Clock
+	c.sources = sourcesFor(ctx.Client, ctx.SelfStatusOptions)
+
+	return c.queue, nil, nil
+}
+
+// sourcesFor returns the sources of the certificates of the components
+// configured in opts.
+func sourcesFor(client kubernetes.Interface, opts controllerpkg.SelfStatusOptions) map[string]source {
+	sources := make(map[string]source)
+	if opts.WebhookCASecretName != "" {
+		sources[ComponentWebhookCA] = &secretSource{
+			client:    client,
+			namespace: opts.WebhookCASecretNamespace,
+			name:      opts.WebhookCASecretName,
+		}
+	}
+	if opts.WebhookAddress != "" {
+		sources[ComponentWebhook] = &endpointSource{address: opts.WebhookAddress}
+	}
+	if opts.StatusAPICertFile != "" {
+		sources[ComponentStatusAPI] = &fileSource{path: opts.StatusAPICertFile}
+	}
+	return sources
+}
+
+// check queues all configured components to be checked.
+func (c *controller) check(ctx context.Context) {
+	for component := range c.sources {
+		c.queue.Add(component)
+	}
+}
+
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("component", key)
+	src, ok := c.sources[key]
+	if !ok {
+		log.Error(nil, "unknown component")
+		return nil
+	}
+
+	cert, loadErr := src.Load(ctx)
+	if loadErr != nil {
+		log.Error(loadErr, "error loading the certificate of component")
+	}
+
+	ss, err := c.getSelfStatus(ctx)
+	if err != nil {
+		return err
+	}
+	status := computeStatus(key, cert, loadErr, c.clock.Now(), componentStatus(ss, key))
+
+	var notAfter time.Time
+	if cert != nil {
+		notAfter = cert.NotAfter
+	}
+	c.metrics.UpdateSelfCertificate(key, notAfter, readyStatus(status))
+
+	logStatus(log, cert, status)
+	return c.setStatus(ctx, ss, status)
+}
+
+// logStatus logs the conditions of a component that need attention.
+func logStatus(log logr.Logger, cert *x509.Certificate, status cmapi.ComponentCertificateStatus) {
+	for _, cond := range status.Conditions {
+		if cond.Type == cmapi.SelfStatusConditionReady && cond.Status != cmmeta.ConditionTrue && cert != nil {
+			log.Info("certificate of component is not valid", "reason", cond.Reason, "message", cond.Message)
+		}
+		if cond.Type == cmapi.SelfStatusConditionRotationOverdue && cond.Status == cmmeta.ConditionTrue {
+			log.Info("certificate of component has not been rotated", "not_after", cert.NotAfter)
+		}
+	}
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		interval := ctx.SelfStatusOptions.CheckInterval
+		if interval <= 0 {
+			interval = defaultCheckInterval
+		}
+		c := &controller{}
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(c).
+			With(c.check, interval).
+			Complete()
+	})
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfstatus
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+func TestComputeStatus(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	cert := func(notBefore, notAfter time.Time) *x509.Certificate {
+		return &x509.Certificate{NotBefore: notBefore, NotAfter: notAfter}
+	}
+
+	tests := map[string]struct {
+		cert     *x509.Certificate
+		loadErr  error
+		previous *cmapi.ComponentCertificateStatus
+
+		expReady, expRotation       cmmeta.ConditionStatus
+		expReadyReason              string
+		expReadyTransitionUnchanged bool
+	}{
+		"a certificate that cannot be loaded is not ready": {
+			loadErr:        errors.New("not found"),
+			expReady:       cmmeta.ConditionFalse,
+			expRotation:    cmmeta.ConditionUnknown,
+			expReadyReason: "LoadFailed",
+		},
+		"a certificate early in its lifetime is ready": {
+			cert:           cert(now.Add(-time.Hour), now.Add(2*time.Hour)),
+			expReady:       cmmeta.ConditionTrue,
+			expRotation:    cmmeta.ConditionFalse,
+			expReadyReason: "Valid",
+		},
+		"a certificate past its rotation time is ready but overdue": {
+			cert:           cert(now.Add(-9*time.Hour), now.Add(time.Hour)),
+			expReady:       cmmeta.ConditionTrue,
+			expRotation:    cmmeta.ConditionTrue,
+			expReadyReason: "Valid",
+		},
+		"an expired certificate is not ready": {
+			cert:           cert(now.Add(-2*time.Hour), now.Add(-time.Hour)),
+			expReady:       cmmeta.ConditionFalse,
+			expRotation:    cmmeta.ConditionTrue,
+			expReadyReason: "Expired",
+		},
+		"a certificate that is not yet valid is not ready": {
+			cert:           cert(now.Add(time.Hour), now.Add(2*time.Hour)),
+			expReady:       cmmeta.ConditionFalse,
+			expRotation:    cmmeta.ConditionFalse,
+			expReadyReason: "NotYetValid",
+		},
+		"the transition time of unchanged conditions is kept": {
+			cert: cert(now.Add(-time.Hour), now.Add(2*time.Hour)),
+			previous: &cmapi.ComponentCertificateStatus{Name: ComponentWebhook, Conditions: []cmapi.SelfStatusCondition{
+				{Type: cmapi.SelfStatusConditionReady, Status: cmmeta.ConditionTrue, LastTransitionTime: &earlier},
+			}},
+			expReady:                    cmmeta.ConditionTrue,
+			expRotation:                 cmmeta.ConditionFalse,
+			expReadyReason:              "Valid",
+			expReadyTransitionUnchanged: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			status := computeStatus(ComponentWebhook, test.cert, test.loadErr, now, test.previous)
+			ready, rotation := findCondition(status, cmapi.SelfStatusConditionReady), findCondition(status, cmapi.SelfStatusConditionRotationOverdue)
+
+			if ready.Status != test.expReady || ready.Reason != test.expReadyReason {
+				t.Errorf("expected Ready condition %s with reason %q, got: %+v", test.expReady, test.expReadyReason, ready)
+			}
+			if rotation.Status != test.expRotation {
+				t.Errorf("expected RotationOverdue condition %s, got: %+v", test.expRotation, rotation)
+			}
+			expTransition := metav1.NewTime(now)
+			if test.expReadyTransitionUnchanged {
+				expTransition = earlier
+			}
+			if ready.LastTransitionTime == nil || !ready.LastTransitionTime.Equal(&expTransition) {
+				t.Errorf("expected Ready transition time %v, got: %v", expTransition, ready.LastTransitionTime)
+			}
+		})
+	}
+}
+
+func TestProcessItem(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	dir, err := ioutil.TempDir("", "selfstatus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "tls.crt")
+	if err := ioutil.WriteFile(certFile, mustCertificate(t, now.Add(-time.Hour), now.Add(2*time.Hour)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := fake.NewSimpleClientset()
+	cmClient := cmfake.NewSimpleClientset()
+	c := &controller{
+		cmClient: cmClient,
+		metrics:  metrics.New(logf.Log),
+		clock:    fakeclock.NewFakeClock(now),
+		sources: sourcesFor(client, controllerpkg.SelfStatusOptions{
+			WebhookCASecretNamespace: "cert-manager",
+			WebhookCASecretName:      "cert-manager-webhook-ca",
+			StatusAPICertFile:        certFile,
+		}),
+	}
+
+	for _, component := range []string{ComponentStatusAPI, ComponentWebhookCA} {
+		if err := c.ProcessItem(context.Background(), component); err != nil {
+			t.Fatalf("unexpected error processing %s: %v", component, err)
+		}
+	}
+
+	ss, err := cmClient.CertmanagerV1alpha2().SelfStatuses().Get(context.Background(), SelfStatusName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ss.Status.Components) != 2 {
+		t.Fatalf("expected the status of 2 components, got: %+v", ss.Status.Components)
+	}
+	expReady := map[string]cmmeta.ConditionStatus{
+		ComponentStatusAPI: cmmeta.ConditionTrue,
+		// the webhook CA Secret does not exist
+		ComponentWebhookCA: cmmeta.ConditionFalse,
+	}
+	for component, exp := range expReady {
+		status := componentStatus(ss, component)
+		if status == nil {
+			t.Fatalf("expected the status of %s to be recorded", component)
+		}
+		if ready := readyStatus(*status); ready != exp {
+			t.Errorf("expected %s to be ready=%s, got: %s", component, exp, ready)
+		}
+	}
+
+	// the Secret appearing is picked up by the next check
+	_, err = client.CoreV1().Secrets("cert-manager").Create(context.Background(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cert-manager", Name: "cert-manager-webhook-ca"},
+		Data:       map[string][]byte{corev1.TLSCertKey: mustCertificate(t, now.Add(-time.Hour), now.Add(2*time.Hour))},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ProcessItem(context.Background(), ComponentWebhookCA); err != nil {
+		t.Fatal(err)
+	}
+	ss, err = cmClient.CertmanagerV1alpha2().SelfStatuses().Get(context.Background(), SelfStatusName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ready := readyStatus(*componentStatus(ss, ComponentWebhookCA)); ready != cmmeta.ConditionTrue {
+		t.Errorf("expected %s to be ready, got: %s", ComponentWebhookCA, ready)
+	}
+}
+
+func findCondition(status cmapi.ComponentCertificateStatus, conditionType cmapi.SelfStatusConditionType) cmapi.SelfStatusCondition {
+	for _, c := range status.Conditions {
+		if c.Type == conditionType {
+			return c
+		}
+	}
+	return cmapi.SelfStatusCondition{Type: conditionType, Status: cmmeta.ConditionUnknown}
+}
+
+func mustCertificate(t *testing.T, notBefore, notAfter time.Time) []byte {
+	pk, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		DNSNames:     []string{"cert-manager-webhook.cert-manager.svc"},
+	}
+	certPEM, _, err := pki.SignCertificate(template, template, pk.Public(), pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certPEM
+}
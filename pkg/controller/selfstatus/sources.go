@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfstatus
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// dialTimeout is the maximum time spent connecting to a component to fetch
+// the certificate it serves
+const dialTimeout = 10 * time.Second
+
+// source loads the certificate of a cert-manager component.
+type source interface {
+	Load(ctx context.Context) (*x509.Certificate, error)
+}
+
+// secretSource loads the certificate stored in a Secret, such as the CA
+// that signs the serving certificates of the webhook.
+type secretSource struct {
+	client          kubernetes.Interface
+	namespace, name string
+}
+
+func (s *secretSource) Load(ctx context.Context) (*x509.Certificate, error) {
+	secret, err := s.client.CoreV1().Secrets(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	certBytes := secret.Data[corev1.TLSCertKey]
+	if len(certBytes) == 0 {
+		return nil, fmt.Errorf("Secret %s/%s has no %q entry", s.namespace, s.name, corev1.TLSCertKey)
+	}
+	return pki.DecodeX509CertificateBytes(certBytes)
+}
+
+// fileSource loads the certificate from a file, such as the serving
+// certificate of the status API of the controller.
+type fileSource struct {
+	path string
+}
+
+func (s *fileSource) Load(context.Context) (*x509.Certificate, error) {
+	certBytes, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	return pki.DecodeX509CertificateBytes(certBytes)
+}
+
+// endpointSource loads the certificate served by a component, such as the
+// webhook, by connecting to it. This reports the certificate that clients
+// actually see, which may differ from the stored one if the component has
+// failed to reload it.
+type endpointSource struct {
+	address string
+}
+
+func (s *endpointSource) Load(ctx context.Context) (*x509.Certificate, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	// the certificate is only inspected, not trusted, so it is not verified
+	// here: an expired or untrusted certificate is exactly what should be
+	// reported rather than failing the connection.
+	conn, err := tls.DialWithDialer(dialer, "tcp", s.address, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("%s did not present a certificate", s.address)
+	}
+	return certs[0], nil
+}
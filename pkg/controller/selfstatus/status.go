@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfstatus
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+// SelfStatusName is the name of the cluster scoped SelfStatus resource that
+// the status of the certificates of cert-manager components is reported in.
+// It holds one entry per component in status.components.
+const SelfStatusName = "cert-manager"
+
+// computeStatus returns the status of a component given the certificate it
+// uses, or the error loading it. The last transition time of conditions that
+// have not changed since the previous status is kept.
+func computeStatus(component string, cert *x509.Certificate, loadErr error, now time.Time, previous *cmapi.ComponentCertificateStatus) cmapi.ComponentCertificateStatus {
+	status := cmapi.ComponentCertificateStatus{Name: component}
+	if loadErr != nil {
+		status.Conditions = []cmapi.SelfStatusCondition{
+			{Type: cmapi.SelfStatusConditionReady, Status: cmmeta.ConditionFalse, Reason: "LoadFailed", Message: fmt.Sprintf("Failed to load the certificate: %v", loadErr)},
+		}
+		return withTransitionTimes(status, previous, now)
+	}
+
+	notBefore, notAfter := metav1.NewTime(cert.NotBefore), metav1.NewTime(cert.NotAfter)
+	status.NotBefore, status.NotAfter = &notBefore, &notAfter
+
+	ready := cmapi.SelfStatusCondition{Type: cmapi.SelfStatusConditionReady, Status: cmmeta.ConditionTrue, Reason: "Valid",
+		Message: fmt.Sprintf("The certificate is valid until %s", cert.NotAfter.UTC().Format(time.RFC3339))}
+	switch {
+	case now.Before(cert.NotBefore):
+		ready.Status, ready.Reason = cmmeta.ConditionFalse, "NotYetValid"
+		ready.Message = fmt.Sprintf("The certificate is not valid before %s", cert.NotBefore.UTC().Format(time.RFC3339))
+	case !now.Before(cert.NotAfter):
+		ready.Status, ready.Reason = cmmeta.ConditionFalse, "Expired"
+		ready.Message = fmt.Sprintf("The certificate expired at %s", cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	rotation := cmapi.SelfStatusCondition{Type: cmapi.SelfStatusConditionRotationOverdue, Status: cmmeta.ConditionFalse, Reason: "RotationNotDue",
+		Message: "The certificate has not reached the end of its rotation period"}
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	if cert.NotAfter.Sub(now) < lifetime/4 {
+		rotation.Status, rotation.Reason = cmmeta.ConditionTrue, "RotationOverdue"
+		rotation.Message = "The certificate should have been rotated once a third of its lifetime remained, " +
+			"check the logs of the component for errors"
+	}
+
+	status.Conditions = []cmapi.SelfStatusCondition{ready, rotation}
+	return withTransitionTimes(status, previous, now)
+}
+
+func withTransitionTimes(status cmapi.ComponentCertificateStatus, previous *cmapi.ComponentCertificateStatus, now time.Time) cmapi.ComponentCertificateStatus {
+	for i := range status.Conditions {
+		c := &status.Conditions[i]
+		transitionTime := metav1.NewTime(now)
+		c.LastTransitionTime = &transitionTime
+		if previous == nil {
+			continue
+		}
+		for _, p := range previous.Conditions {
+			if p.Type == c.Type && p.Status == c.Status && p.LastTransitionTime != nil {
+				c.LastTransitionTime = p.LastTransitionTime
+			}
+		}
+	}
+	return status
+}
+
+// readyStatus returns the status of the Ready condition of a component.
+func readyStatus(status cmapi.ComponentCertificateStatus) cmmeta.ConditionStatus {
+	for _, c := range status.Conditions {
+		if c.Type == cmapi.SelfStatusConditionReady {
+			return c.Status
+		}
+	}
+	return cmmeta.ConditionUnknown
+}
+
+// componentStatus returns the status of the component recorded in the
+// SelfStatus, or nil if none has been recorded.
+func componentStatus(ss *cmapi.SelfStatus, component string) *cmapi.ComponentCertificateStatus {
+	for i := range ss.Status.Components {
+		if ss.Status.Components[i].Name == component {
+			return &ss.Status.Components[i]
+		}
+	}
+	return nil
+}
+
+// getSelfStatus returns the SelfStatus resource, creating it if it does not
+// exist yet. The status of a newly created SelfStatus is empty, as the status
+// subresource ignores status fields on create.
+func (c *controller) getSelfStatus(ctx context.Context) (*cmapi.SelfStatus, error) {
+	ss, err := c.cmClient.CertmanagerV1alpha2().SelfStatuses().Get(ctx, SelfStatusName, metav1.GetOptions{})
+	if !apierrors.IsNotFound(err) {
+		return ss, err
+	}
+	ss, err = c.cmClient.CertmanagerV1alpha2().SelfStatuses().Create(ctx, &cmapi.SelfStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: SelfStatusName},
+	}, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return c.cmClient.CertmanagerV1alpha2().SelfStatuses().Get(ctx, SelfStatusName, metav1.GetOptions{})
+	}
+	return ss, err
+}
+
+// setStatus records the status of a component in the SelfStatus. A conflict
+// with a concurrent update is returned so that the component is requeued.
+func (c *controller) setStatus(ctx context.Context, ss *cmapi.SelfStatus, status cmapi.ComponentCertificateStatus) error {
+	if previous := componentStatus(ss, status.Name); previous != nil && apiequality.Semantic.DeepEqual(*previous, status) {
+		return nil
+	}
+
+	ss = ss.DeepCopy()
+	if previous := componentStatus(ss, status.Name); previous != nil {
+		*previous = status
+	} else {
+		ss.Status.Components = append(ss.Status.Components, status)
+	}
+	_, err := c.cmClient.CertmanagerV1alpha2().SelfStatuses().UpdateStatus(ctx, ss, metav1.UpdateOptions{})
+	return err
+}
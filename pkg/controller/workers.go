@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+)
+
+// workerPool runs a resizable number of workers. Each worker is given a
+// channel that is closed when the worker should exit.
+type workerPool struct {
+	// run runs a single worker until the given channel is closed
+	run func(stopCh <-chan struct{})
+
+	lock    sync.Mutex
+	wg      sync.WaitGroup
+	stopChs []chan struct{}
+	stopped bool
+}
+
+// size returns the number of running workers.
+func (p *workerPool) size() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.stopChs)
+}
+
+// resize starts or stops workers so that n are running. It does nothing
+// once the pool has been stopped.
+func (p *workerPool) resize(n int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.stopped {
+		return
+	}
+
+	for len(p.stopChs) < n {
+		stopCh := make(chan struct{})
+		p.stopChs = append(p.stopChs, stopCh)
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.run(stopCh)
+		}()
+	}
+	for len(p.stopChs) > n {
+		last := len(p.stopChs) - 1
+		close(p.stopChs[last])
+		p.stopChs = p.stopChs[:last]
+	}
+}
+
+// stop stops all workers and waits for them to exit.
+func (p *workerPool) stop() {
+	p.resize(0)
+	p.lock.Lock()
+	p.stopped = true
+	p.lock.Unlock()
+	p.wg.Wait()
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool(t *testing.T) {
+	var lock sync.Mutex
+	running := 0
+	getRunning := func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return running
+	}
+
+	p := &workerPool{
+		run: func(stopCh <-chan struct{}) {
+			lock.Lock()
+			running++
+			lock.Unlock()
+			<-stopCh
+			lock.Lock()
+			running--
+			lock.Unlock()
+		},
+	}
+
+	waitForRunning := func(n int) {
+		t.Helper()
+		if p.size() != n {
+			t.Fatalf("expected pool size %d but got %d", n, p.size())
+		}
+		for i := 0; i < 100; i++ {
+			if getRunning() == n {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("expected %d workers to be running but got %d", n, getRunning())
+	}
+
+	p.resize(3)
+	waitForRunning(3)
+
+	p.resize(5)
+	waitForRunning(5)
+
+	p.resize(1)
+	waitForRunning(1)
+
+	p.stop()
+	if getRunning() != 0 {
+		t.Errorf("expected all workers to have exited after stop but %d are running", getRunning())
+	}
+
+	p.resize(2)
+	if p.size() != 0 {
+		t.Errorf("expected a stopped pool not to be resized but got size %d", p.size())
+	}
+}
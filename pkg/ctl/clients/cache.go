@@ -0,0 +1,223 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clients contains clients shared by the commands of kubectl
+// cert-manager.
+package clients
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/pager"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1alpha2"
+)
+
+// DefaultChunkSize is the default number of resources requested per page
+// when listing resources, matching the default of kubectl.
+const DefaultChunkSize = 500
+
+// involvedObjectUIDIndex indexes Events by the UID of the object they are
+// about.
+const involvedObjectUIDIndex = "involvedObject.uid"
+
+// Cache serves lookups of resources from memory, for commands that look up
+// many resources. The first lookup of a kind of resource in a namespace
+// lists all resources of that kind in the namespace with a single paged
+// LIST, and all subsequent lookups in the namespace are served from the
+// listed resources. Once the resources of all namespaces have been listed,
+// lookups in any namespace are served from memory.
+// Resources are never refreshed, so a Cache should only be used for the
+// duration of a single command. Returned resources are shared with the
+// Cache and must not be modified.
+type Cache struct {
+	kube      kubernetes.Interface
+	cm        cmclient.Interface
+	chunkSize int64
+
+	lock sync.Mutex
+	// listed are the namespaces the resources of each kind have been listed
+	// in. The empty namespace means all namespaces.
+	listed map[string]map[string]bool
+
+	certificates        cache.Indexer
+	certificateRequests cache.Indexer
+	issuers             cache.Indexer
+	clusterIssuers      cache.Indexer
+	secrets             cache.Indexer
+	events              cache.Indexer
+}
+
+// NewCache returns a Cache listing resources with the given clients,
+// requesting chunkSize resources per page. If chunkSize is zero, resources
+// are listed in a single page.
+func NewCache(kube kubernetes.Interface, cm cmclient.Interface, chunkSize int64) *Cache {
+	newIndexer := func() cache.Indexer {
+		return cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	}
+	events := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+		involvedObjectUIDIndex: func(obj interface{}) ([]string, error) {
+			return []string{string(obj.(*corev1.Event).InvolvedObject.UID)}, nil
+		},
+	})
+	return &Cache{
+		kube:                kube,
+		cm:                  cm,
+		chunkSize:           chunkSize,
+		listed:              make(map[string]map[string]bool),
+		certificates:        newIndexer(),
+		certificateRequests: newIndexer(),
+		issuers:             newIndexer(),
+		clusterIssuers:      newIndexer(),
+		secrets:             newIndexer(),
+		events:              events,
+	}
+}
+
+// ListCertificates returns the Certificates in namespace matching selector.
+// The empty namespace means all namespaces.
+func (c *Cache) ListCertificates(ctx context.Context, namespace string, selector labels.Selector) ([]*cmapi.Certificate, error) {
+	err := c.list(ctx, "certificates", namespace, c.certificates, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.cm.CertmanagerV1alpha2().Certificates(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if namespace == "" {
+		return cmlisters.NewCertificateLister(c.certificates).List(selector)
+	}
+	return cmlisters.NewCertificateLister(c.certificates).Certificates(namespace).List(selector)
+}
+
+// GetCertificate returns the Certificate with the given namespace and name.
+func (c *Cache) GetCertificate(ctx context.Context, namespace, name string) (*cmapi.Certificate, error) {
+	err := c.list(ctx, "certificates", namespace, c.certificates, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.cm.CertmanagerV1alpha2().Certificates(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cmlisters.NewCertificateLister(c.certificates).Certificates(namespace).Get(name)
+}
+
+// ListCertificateRequests returns the CertificateRequests in namespace
+// matching selector.
+func (c *Cache) ListCertificateRequests(ctx context.Context, namespace string, selector labels.Selector) ([]*cmapi.CertificateRequest, error) {
+	err := c.list(ctx, "certificaterequests", namespace, c.certificateRequests, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.cm.CertmanagerV1alpha2().CertificateRequests(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cmlisters.NewCertificateRequestLister(c.certificateRequests).CertificateRequests(namespace).List(selector)
+}
+
+// GetIssuer returns the Issuer with the given namespace and name.
+func (c *Cache) GetIssuer(ctx context.Context, namespace, name string) (*cmapi.Issuer, error) {
+	err := c.list(ctx, "issuers", namespace, c.issuers, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.cm.CertmanagerV1alpha2().Issuers(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cmlisters.NewIssuerLister(c.issuers).Issuers(namespace).Get(name)
+}
+
+// GetClusterIssuer returns the ClusterIssuer with the given name.
+func (c *Cache) GetClusterIssuer(ctx context.Context, name string) (*cmapi.ClusterIssuer, error) {
+	err := c.list(ctx, "clusterissuers", "", c.clusterIssuers, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.cm.CertmanagerV1alpha2().ClusterIssuers().List(ctx, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cmlisters.NewClusterIssuerLister(c.clusterIssuers).Get(name)
+}
+
+// GetSecret returns the Secret with the given namespace and name.
+func (c *Cache) GetSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	err := c.list(ctx, "secrets", namespace, c.secrets, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.kube.CoreV1().Secrets(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return corelisters.NewSecretLister(c.secrets).Secrets(namespace).Get(name)
+}
+
+// EventsFor returns the Events about obj.
+func (c *Cache) EventsFor(ctx context.Context, obj metav1.Object) (*corev1.EventList, error) {
+	namespace := obj.GetNamespace()
+	err := c.list(ctx, "events", namespace, c.events, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.kube.CoreV1().Events(namespace).List(ctx, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	objs, err := c.events.ByIndex(involvedObjectUIDIndex, string(obj.GetUID()))
+	if err != nil {
+		return nil, err
+	}
+	events := &corev1.EventList{}
+	for _, obj := range objs {
+		events.Items = append(events.Items, *obj.(*corev1.Event).DeepCopy())
+	}
+	return events, nil
+}
+
+// list lists the resources of the given kind in namespace into indexer
+// using listFunc, unless they have been listed already.
+func (c *Cache) list(ctx context.Context, resource, namespace string, indexer cache.Indexer, listFunc pager.ListPageFunc) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	listed := c.listed[resource]
+	if listed[""] || listed[namespace] {
+		return nil
+	}
+
+	p := pager.New(listFunc)
+	p.PageSize = c.chunkSize
+	list, err := p.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error when listing %s: %v", resource, err)
+	}
+	err = meta.EachListItem(list, func(obj runtime.Object) error {
+		return indexer.Add(obj)
+	})
+	if err != nil {
+		return err
+	}
+
+	if listed == nil {
+		listed = make(map[string]bool)
+		c.listed[resource] = listed
+	}
+	listed[namespace] = true
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	coretesting "k8s.io/client-go/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+)
+
+func countLists(actions []coretesting.Action) int {
+	n := 0
+	for _, action := range actions {
+		if action.GetVerb() == "list" {
+			n++
+		}
+	}
+	return n
+}
+
+func TestCacheListsOnce(t *testing.T) {
+	ctx := context.Background()
+	kube := kubefake.NewSimpleClientset(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "secret-1"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "secret-2"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "secret-3"}},
+	)
+	cm := cmfake.NewSimpleClientset(
+		&cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "crt-1"}},
+		&cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "crt-2"}},
+		&cmapi.Issuer{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "issuer"}},
+	)
+	c := NewCache(kube, cm, DefaultChunkSize)
+
+	for _, name := range []string{"secret-1", "secret-2"} {
+		if _, err := c.GetSecret(ctx, "ns1", name); err != nil {
+			t.Errorf("unexpected error getting Secret %q: %v", name, err)
+		}
+	}
+	if _, err := c.GetSecret(ctx, "ns1", "missing"); !apierrors.IsNotFound(err) {
+		t.Errorf("expected a NotFound error for a missing Secret, got: %v", err)
+	}
+	if n := countLists(kube.Actions()); n != 1 {
+		t.Errorf("expected Secrets of a namespace to be listed once, got %d lists", n)
+	}
+	if _, err := c.GetSecret(ctx, "ns2", "secret-3"); err != nil {
+		t.Errorf("unexpected error getting Secret in another namespace: %v", err)
+	}
+	if n := countLists(kube.Actions()); n != 2 {
+		t.Errorf("expected Secrets of another namespace to be listed, got %d lists", n)
+	}
+
+	crts, err := c.ListCertificates(ctx, metav1.NamespaceAll, labels.Everything())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(crts) != 2 {
+		t.Errorf("expected Certificates of all namespaces, got %d", len(crts))
+	}
+	for _, crt := range crts {
+		if _, err := c.GetCertificate(ctx, crt.Namespace, crt.Name); err != nil {
+			t.Errorf("unexpected error getting Certificate %s/%s: %v", crt.Namespace, crt.Name, err)
+		}
+	}
+	if _, err := c.GetIssuer(ctx, "ns1", "issuer"); err != nil {
+		t.Errorf("unexpected error getting Issuer: %v", err)
+	}
+	if _, err := c.GetClusterIssuer(ctx, "missing"); !apierrors.IsNotFound(err) {
+		t.Errorf("expected a NotFound error for a missing ClusterIssuer, got: %v", err)
+	}
+	if n := countLists(cm.Actions()); n != 3 {
+		t.Errorf("expected one list of Certificates, Issuers and ClusterIssuers each, got %d lists", n)
+	}
+}
+
+func TestCacheEventsFor(t *testing.T) {
+	ctx := context.Background()
+	crt := &cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "crt", UID: "uid-1"}}
+	kube := kubefake.NewSimpleClientset(
+		&corev1.Event{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "event-1"}, InvolvedObject: corev1.ObjectReference{UID: "uid-1"}},
+		&corev1.Event{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "event-2"}, InvolvedObject: corev1.ObjectReference{UID: "uid-2"}},
+	)
+	c := NewCache(kube, cmfake.NewSimpleClientset(), DefaultChunkSize)
+
+	events, err := c.EventsFor(ctx, crt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events.Items) != 1 || events.Items[0].Name != "event-1" {
+		t.Errorf("expected only the Events about the Certificate, got: %v", events.Items)
+	}
+}
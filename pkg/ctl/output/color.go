@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package output contains helpers to render the output of kubectl
+// cert-manager commands consistently: colorized condition states,
+// column-aligned tables and text wrapped to the width of the terminal.
+package output
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"sync/atomic"
+)
+
+// NoColorEnv is the environment variable that disables colored output if it
+// is set to any value, see https://no-color.org.
+const NoColorEnv = "NO_COLOR"
+
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorBold   = "\x1b[1m"
+)
+
+// colorEnabled is 1 if colored output is enabled. Color is disabled by
+// default, so that packages rendering output can be used by other tools
+// without emitting escape sequences.
+var colorEnabled int32
+
+// SetColor enables or disables colored output.
+func SetColor(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&colorEnabled, v)
+}
+
+// ColorEnabled returns true if colored output is enabled.
+func ColorEnabled() bool {
+	return atomic.LoadInt32(&colorEnabled) == 1
+}
+
+// ShouldColor returns true if output written to out should be colored: color
+// is not disabled with noColor or the NO_COLOR environment variable, and out
+// is a terminal.
+func ShouldColor(out io.Writer, noColor bool) bool {
+	if noColor {
+		return false
+	}
+	if _, ok := os.LookupEnv(NoColorEnv); ok {
+		return false
+	}
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Red returns s colored red if color is enabled.
+func Red(s string) string {
+	return colorize(colorRed, s)
+}
+
+// Green returns s colored green if color is enabled.
+func Green(s string) string {
+	return colorize(colorGreen, s)
+}
+
+// Yellow returns s colored yellow if color is enabled.
+func Yellow(s string) string {
+	return colorize(colorYellow, s)
+}
+
+// Bold returns s in bold if color is enabled.
+func Bold(s string) string {
+	return colorize(colorBold, s)
+}
+
+func colorize(color, s string) string {
+	if !ColorEnabled() || s == "" {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// ConditionStatus returns the status of a condition of the given type,
+// colored by whether it indicates a healthy resource: a Ready condition is
+// green if True and red if False, conditions reporting a failure are red if
+// True, and any condition with Unknown status is yellow.
+func ConditionStatus(conditionType, status string) string {
+	switch {
+	case status == "Unknown":
+		return Yellow(status)
+	case conditionType == "Ready" && status == "True":
+		return Green(status)
+	case conditionType == "Ready" && status == "False":
+		return Red(status)
+	case isFailureCondition(conditionType) && status == "True":
+		return Red(status)
+	}
+	return status
+}
+
+// isFailureCondition returns true for the types of conditions of
+// cert-manager resources that report a failure when True.
+func isFailureCondition(conditionType string) bool {
+	switch conditionType {
+	case "Failed", "InvalidRequest", "Denied":
+		return true
+	}
+	return false
+}
+
+var escapeSequence = regexp.MustCompile("\x1b\\[[0-9;]*m")
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestConditionStatus(t *testing.T) {
+	tests := map[string]struct {
+		color         bool
+		conditionType string
+		status        string
+		exp           string
+	}{
+		"color disabled": {
+			conditionType: "Ready",
+			status:        "True",
+			exp:           "True",
+		},
+		"Ready is green": {
+			color:         true,
+			conditionType: "Ready",
+			status:        "True",
+			exp:           colorGreen + "True" + colorReset,
+		},
+		"not Ready is red": {
+			color:         true,
+			conditionType: "Ready",
+			status:        "False",
+			exp:           colorRed + "False" + colorReset,
+		},
+		"Failed is red": {
+			color:         true,
+			conditionType: "Failed",
+			status:        "True",
+			exp:           colorRed + "True" + colorReset,
+		},
+		"Unknown is yellow": {
+			color:         true,
+			conditionType: "Ready",
+			status:        "Unknown",
+			exp:           colorYellow + "Unknown" + colorReset,
+		},
+		"other conditions are not colored": {
+			color:         true,
+			conditionType: "Issuing",
+			status:        "True",
+			exp:           "True",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			SetColor(test.color)
+			defer SetColor(false)
+
+			if actual := ConditionStatus(test.conditionType, test.status); actual != test.exp {
+				t.Errorf("expected %q, got: %q", test.exp, actual)
+			}
+		})
+	}
+}
+
+func TestShouldColor(t *testing.T) {
+	if ShouldColor(&bytes.Buffer{}, false) {
+		t.Errorf("expected no color when not writing to a terminal")
+	}
+
+	os.Setenv(NoColorEnv, "")
+	defer os.Unsetenv(NoColorEnv)
+	if ShouldColor(os.Stdout, false) {
+		t.Errorf("expected no color when %s is set", NoColorEnv)
+	}
+}
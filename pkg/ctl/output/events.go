@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package util
+package output
 
 import (
 	"fmt"
@@ -51,16 +51,10 @@ func DescribeEvents(el *corev1.EventList, w describe.PrefixWriter, baseLevel int
 	w.Write(baseLevel+1, "Type\tReason\tAge\tFrom\tMessage\n")
 	w.Write(baseLevel+1, "----\t------\t----\t----\t-------\n")
 	for _, e := range el.Items {
-		var interval string
-		if e.Count > 1 {
-			interval = fmt.Sprintf("%s (x%d over %s)", translateTimestampSince(e.LastTimestamp), e.Count, translateTimestampSince(e.FirstTimestamp))
-		} else {
-			interval = translateTimestampSince(e.FirstTimestamp)
-		}
 		w.Write(baseLevel+1, "%v\t%v\t%s\t%v\t%v\n",
 			e.Type,
 			e.Reason,
-			interval,
+			eventInterval(e),
 			formatEventSource(e.Source),
 			strings.TrimSpace(e.Message),
 		)
@@ -68,7 +62,31 @@ func DescribeEvents(el *corev1.EventList, w describe.PrefixWriter, baseLevel int
 	w.Flush()
 }
 
-// NewTabWriter returns a *tabwriter.Writer with fixed parameters to be used in the status command
+// EventsTable returns the Events in el as a Table with the same columns as
+// printed by DescribeEvents, e.g. to be rendered as Markdown.
+func EventsTable(el *corev1.EventList) *Table {
+	t := NewTable("Type", "Reason", "Age", "From", "Message")
+	if el == nil {
+		return t
+	}
+	items := append([]corev1.Event(nil), el.Items...)
+	sort.Sort(event.SortableEvents(items))
+	for _, e := range items {
+		t.AddRow(e.Type, e.Reason, eventInterval(e), formatEventSource(e.Source), strings.TrimSpace(e.Message))
+	}
+	return t
+}
+
+// eventInterval returns when the event e was last seen, and how often over
+// what period if it was seen more than once.
+func eventInterval(e corev1.Event) string {
+	if e.Count > 1 {
+		return fmt.Sprintf("%s (x%d over %s)", translateTimestampSince(e.LastTimestamp), e.Count, translateTimestampSince(e.FirstTimestamp))
+	}
+	return translateTimestampSince(e.FirstTimestamp)
+}
+
+// NewTabWriter returns a *tabwriter.Writer with fixed parameters used to render the output of kubectl cert-manager commands
 func NewTabWriter(writer io.Writer) *tabwriter.Writer {
 	return tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
 }
@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"strings"
+)
+
+// columnPadding is the number of spaces between columns of a Table
+const columnPadding = 2
+
+// Table renders rows of cells as column-aligned text. Unlike
+// text/tabwriter, the width of cells is computed without color escape
+// sequences, so that colored cells are aligned correctly.
+type Table struct {
+	// Indent is prepended to every line of the table
+	Indent string
+
+	headers []string
+	rows    [][]string
+}
+
+// NewTable returns a Table with the given column headers. If no headers are
+// given, the table is rendered without a header line.
+func NewTable(headers ...string) *Table {
+	return &Table{headers: headers}
+}
+
+// AddRow adds a row of cells to the table.
+func (t *Table) AddRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// Len returns the number of rows of the table, excluding the header.
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// String renders the table. Every line, including the last, ends with a
+// newline. Trailing whitespace is omitted. If output is wrapped, the cells of
+// the last column, which usually hold messages, are wrapped to the remaining
+// width, with continuation lines aligned to the start of the column.
+func (t *Table) String() string {
+	var lines [][]string
+	if len(t.headers) > 0 {
+		lines = append(lines, t.headers)
+	}
+	lines = append(lines, t.rows...)
+
+	var widths []int
+	for _, line := range lines {
+		for i, cell := range line {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if l := DisplayWidth(cell); l > widths[i] {
+				widths[i] = l
+			}
+		}
+	}
+
+	// the width that the last column is wrapped to, or 0 if it is not
+	lastWidth := 0
+	if w := Width(); w > 0 && len(widths) > 0 {
+		lastWidth = w - DisplayWidth(t.Indent)
+		for _, cw := range widths[:len(widths)-1] {
+			lastWidth -= cw + columnPadding
+		}
+		if lastWidth < minWrapWidth {
+			lastWidth = minWrapWidth
+		}
+	}
+
+	var b strings.Builder
+	for _, line := range lines {
+		var lb strings.Builder
+		lb.WriteString(t.Indent)
+		var continuation []string
+		for i, cell := range line {
+			if i == len(widths)-1 && lastWidth > 0 {
+				wrapped := Wrap(cell, lastWidth)
+				cell, continuation = wrapped[0], wrapped[1:]
+			}
+			lb.WriteString(cell)
+			if i < len(line)-1 {
+				lb.WriteString(strings.Repeat(" ", widths[i]-DisplayWidth(cell)+columnPadding))
+			}
+		}
+		b.WriteString(strings.TrimRight(lb.String(), " "))
+		b.WriteString("\n")
+
+		if len(continuation) > 0 {
+			offset := DisplayWidth(t.Indent)
+			for _, cw := range widths[:len(widths)-1] {
+				offset += cw + columnPadding
+			}
+			for _, c := range continuation {
+				b.WriteString(strings.Repeat(" ", offset))
+				b.WriteString(c)
+				b.WriteString("\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// Markdown renders the table as a GitHub flavored Markdown table, e.g. to be
+// pasted into an issue or a support ticket. Color escape sequences are
+// removed, pipes are escaped and newlines in cells are replaced by <br>. A
+// table without headers is rendered with empty headers, as Markdown tables
+// require a header line.
+func (t *Table) Markdown() string {
+	columns := len(t.headers)
+	for _, row := range t.rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for i := 0; i < columns; i++ {
+			cell := ""
+			if i < len(cells) {
+				cell = MarkdownEscape(cells[i])
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(t.headers)
+	b.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+	for _, row := range t.rows {
+		writeRow(row)
+	}
+	return b.String()
+}
+
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"|", "\\|",
+	"`", "\\`",
+	"*", "\\*",
+	"_", "\\_",
+	"<", "&lt;",
+	">", "&gt;",
+	"\r\n", "<br>",
+	"\n", "<br>",
+)
+
+// MarkdownEscape returns s without color escape sequences and with the
+// characters that have a meaning in Markdown escaped, so that it is
+// rendered verbatim in a Markdown table or list.
+func MarkdownEscape(s string) string {
+	return markdownEscaper.Replace(escapeSequence.ReplaceAllString(s, ""))
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	tests := map[string]struct {
+		indent  string
+		width   int
+		headers []string
+		rows    [][]string
+		exp     string
+	}{
+		"columns are aligned": {
+			headers: []string{"Type", "Status", "Message"},
+			rows: [][]string{
+				{"Ready", "True", "Certificate is up to date"},
+				{"Issuing", "False", ""},
+			},
+			exp: "Type     Status  Message\n" +
+				"Ready    True    Certificate is up to date\n" +
+				"Issuing  False\n",
+		},
+		"indent is prepended to every line": {
+			indent: "  ",
+			rows:   [][]string{{"a", "b"}, {"ccc", "d"}},
+			exp:    "  a    b\n  ccc  d\n",
+		},
+		"colored cells are aligned by their visible width": {
+			headers: []string{"Type", "Status", "Reason"},
+			rows: [][]string{
+				{"Ready", colorRed + "False" + colorReset, "Failed"},
+			},
+			exp: "Type   Status  Reason\n" +
+				"Ready  \x1b[31mFalse\x1b[0m   Failed\n",
+		},
+		"messages are wrapped to the width with continuation lines aligned": {
+			indent:  "  ",
+			width:   50,
+			headers: []string{"Type", "Status", "Message"},
+			rows: [][]string{
+				{"Ready", "False", "Issuing certificate as Secret does not exist"},
+			},
+			exp: "  Type   Status  Message\n" +
+				"  Ready  False   Issuing certificate as Secret\n" +
+				"                 does not exist\n",
+		},
+		"empty table": {
+			exp: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			SetWidth(test.width)
+			defer SetWidth(0)
+
+			table := NewTable(test.headers...)
+			table.Indent = test.indent
+			for _, row := range test.rows {
+				table.AddRow(row...)
+			}
+			if actual := table.String(); actual != test.exp {
+				t.Errorf("expected:\n%q\ngot:\n%q", test.exp, actual)
+			}
+		})
+	}
+}
+
+func TestTableMarkdown(t *testing.T) {
+	table := NewTable("Type", "Status", "Message")
+	table.AddRow("Ready", colorRed+"False"+colorReset, "Secret \"a|b\" does not exist\nretrying")
+	table.AddRow("Issuing")
+
+	exp := "| Type | Status | Message |\n" +
+		"| --- | --- | --- |\n" +
+		"| Ready | False | Secret \"a\\|b\" does not exist<br>retrying |\n" +
+		"| Issuing |  |  |\n"
+	if actual := table.Markdown(); actual != exp {
+		t.Errorf("expected:\n%q\ngot:\n%q", exp, actual)
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	"golang.org/x/crypto/ssh/terminal"
+	"golang.org/x/text/width"
+)
+
+// ColumnsEnv is the environment variable that overrides the width of the
+// terminal that output is wrapped to, like in most shells.
+const ColumnsEnv = "COLUMNS"
+
+// minWrapWidth is the smallest width that text is wrapped to, so that
+// deeply indented text is still readable on a narrow terminal
+const minWrapWidth = 20
+
+// wrapWidth is the number of columns that output is wrapped to, or 0 if
+// output is not wrapped. Wrapping is disabled by default, so that packages
+// rendering output can be used by other tools without changing their output.
+var wrapWidth int32
+
+// SetWidth sets the number of columns that output is wrapped to. A width of
+// 0 or less disables wrapping.
+func SetWidth(w int) {
+	if w < 0 {
+		w = 0
+	}
+	atomic.StoreInt32(&wrapWidth, int32(w))
+}
+
+// Width returns the number of columns that output is wrapped to, or 0 if
+// output is not wrapped.
+func Width() int {
+	return int(atomic.LoadInt32(&wrapWidth))
+}
+
+// TerminalWidth returns the number of columns that output written to out
+// should be wrapped to: the value of the COLUMNS environment variable if it
+// is set to a positive number, or the width of out if it is a terminal.
+// It returns 0 if output should not be wrapped.
+func TerminalWidth(out io.Writer) int {
+	if w, err := strconv.Atoi(os.Getenv(ColumnsEnv)); err == nil && w > 0 {
+		return w
+	}
+	f, ok := out.(*os.File)
+	if !ok || !terminal.IsTerminal(int(f.Fd())) {
+		return 0
+	}
+	w, _, err := terminal.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0
+	}
+	return w
+}
+
+// DisplayWidth returns the number of columns that s takes up when printed
+// to a terminal: color escape sequences take up no space, and East Asian
+// wide and fullwidth characters take up two columns.
+func DisplayWidth(s string) int {
+	n := 0
+	for _, r := range escapeSequence.ReplaceAllString(s, "") {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+func runeWidth(r rune) int {
+	if unicode.Is(unicode.Mn, r) || !unicode.IsPrint(r) && r != ' ' {
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// Wrap splits s into lines of at most w columns, breaking lines at
+// whitespace. Words that are longer than w are broken at w columns, so that
+// long DNS names or URLs do not overflow the terminal. Newlines in s are
+// preserved. If w is 0 or less, s is only split at its newlines.
+func Wrap(s string, w int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		lines = append(lines, wrapParagraph(paragraph, w)...)
+	}
+	return lines
+}
+
+func wrapParagraph(s string, w int) []string {
+	if w <= 0 || DisplayWidth(s) <= w {
+		return []string{s}
+	}
+	if w < minWrapWidth {
+		w = minWrapWidth
+	}
+
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0
+	for _, word := range strings.Fields(s) {
+		wordWidth := DisplayWidth(word)
+		if lineWidth > 0 && lineWidth+1+wordWidth <= w {
+			line.WriteString(" ")
+			line.WriteString(word)
+			lineWidth += 1 + wordWidth
+			continue
+		}
+		if lineWidth > 0 {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineWidth = 0
+		}
+		for wordWidth > w {
+			head, tail := splitAtWidth(word, w)
+			lines = append(lines, head)
+			word, wordWidth = tail, DisplayWidth(tail)
+		}
+		line.WriteString(word)
+		lineWidth = wordWidth
+	}
+	if lineWidth > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// splitAtWidth splits s after the last character that fits into w columns.
+// At least one character is always returned in head.
+func splitAtWidth(s string, w int) (head, tail string) {
+	n := 0
+	for i, r := range s {
+		n += runeWidth(r)
+		if n > w && i > 0 {
+			return s[:i], s[i:]
+		}
+	}
+	return s, ""
+}
+
+// Indent wraps s to the configured Width minus the width of firstIndent, and
+// prefixes the first line with firstIndent and all following lines with
+// indent. Every line, including the last, ends with a newline.
+func Indent(s, firstIndent, indent string) string {
+	w := Width()
+	if w > 0 {
+		w -= DisplayWidth(firstIndent)
+		if w < minWrapWidth {
+			w = minWrapWidth
+		}
+	}
+
+	var b strings.Builder
+	for i, line := range Wrap(strings.TrimRight(s, "\n"), w) {
+		if i == 0 {
+			b.WriteString(firstIndent)
+		} else {
+			b.WriteString(indent)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
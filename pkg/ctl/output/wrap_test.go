@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	tests := map[string]struct {
+		s     string
+		width int
+		exp   []string
+	}{
+		"text is not wrapped if width is 0": {
+			s:   "Failed to wait for order resource to become ready",
+			exp: []string{"Failed to wait for order resource to become ready"},
+		},
+		"text is wrapped at whitespace": {
+			s:     "Failed to wait for order resource to become ready",
+			width: 20,
+			exp:   []string{"Failed to wait for", "order resource to", "become ready"},
+		},
+		"words longer than the width are broken": {
+			s:     "a-very-long-subdomain-name.example.com is invalid",
+			width: 20,
+			exp:   []string{"a-very-long-subdomai", "n-name.example.com", "is invalid"},
+		},
+		"newlines are preserved": {
+			s:     "first line\nsecond line",
+			width: 40,
+			exp:   []string{"first line", "second line"},
+		},
+		"wide characters take up two columns": {
+			s:     "証明書の発行に失敗しました 証明書の発行に失敗しました",
+			width: 26,
+			exp:   []string{"証明書の発行に失敗しました", "証明書の発行に失敗しました"},
+		},
+		"width is at least the minimum": {
+			s:     "Certificate is up to date and has not expired",
+			width: 5,
+			exp:   []string{"Certificate is up to", "date and has not", "expired"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := Wrap(test.s, test.width); !reflect.DeepEqual(actual, test.exp) {
+				t.Errorf("expected %q, got: %q", test.exp, actual)
+			}
+		})
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := map[string]int{
+		"example.com":                   11,
+		colorRed + "False" + colorReset: 5,
+		"証明書":                           6,
+		"ｅｘａｍｐｌｅ":                       14,
+		"e\u0301xample.com":             11,
+		"xn--bcher-kva.example":         21,
+		"bücher.example":                14,
+	}
+	for s, exp := range tests {
+		if actual := DisplayWidth(s); actual != exp {
+			t.Errorf("expected width of %q to be %d, got: %d", s, exp, actual)
+		}
+	}
+}
+
+func TestIndent(t *testing.T) {
+	SetWidth(29)
+	defer SetWidth(0)
+
+	exp := "- a-very-long-subdomain-name.\n  example.com\n"
+	if actual := Indent("a-very-long-subdomain-name.example.com", "- ", "  "); actual != exp {
+		t.Errorf("expected %q, got: %q", exp, actual)
+	}
+}
+
+func TestTerminalWidth(t *testing.T) {
+	if w := TerminalWidth(&bytes.Buffer{}); w != 0 {
+		t.Errorf("expected no wrapping when not writing to a terminal, got width %d", w)
+	}
+
+	os.Setenv(ColumnsEnv, "100")
+	defer os.Unsetenv(ColumnsEnv)
+	if w := TerminalWidth(&bytes.Buffer{}); w != 100 {
+		t.Errorf("expected width of %s to be used, got width %d", ColumnsEnv, w)
+	}
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	acmeapi "golang.org/x/crypto/acme"
+
+	"github.com/jetstack/cert-manager/pkg/acme"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/ctl/output"
+	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// ACMEAccountFetcher fetches the account registered with the ACME server of
+// the issuer for the given private key.
+type ACMEAccountFetcher func(ctx context.Context, issuer *cmacme.ACMEIssuer, key *rsa.PrivateKey) (*acmeapi.Account, error)
+
+// FetchACMEAccount fetches the account registered for key from the ACME
+// server of the issuer.
+func FetchACMEAccount(ctx context.Context, issuer *cmacme.ACMEIssuer, key *rsa.PrivateKey) (*acmeapi.Account, error) {
+	cl := &acmeapi.Client{
+		Key:          key,
+		DirectoryURL: issuer.Server,
+		UserAgent:    util.CertManagerUserAgent,
+		HTTPClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: issuer.SkipTLSVerify},
+			},
+			Timeout: 30 * time.Second,
+		},
+	}
+	return cl.GetReg(ctx, "")
+}
+
+// ACMEAccountStatus is the status of the account of an ACME issuer.
+type ACMEAccountStatus struct {
+	// Server is the directory URL of the ACME server
+	Server string
+	// URI is the URL of the account stored in the status of the issuer
+	URI string
+	// Email is the contact email in the spec of the issuer
+	Email string
+	// LastRegisteredEmail is the email stored in the status of the issuer
+	LastRegisteredEmail string
+
+	// PrivateKeySecret is the name of the Secret the account private key is
+	// stored in
+	PrivateKeySecret string
+	// PrivateKeyError is the reason the account private key cannot be used,
+	// or nil if the Secret exists and contains a valid RSA private key
+	PrivateKeyError error
+
+	// EABKeyID is the key ID of the external account binding, or empty if
+	// no external account binding is configured
+	EABKeyID string
+	// EABKeyError is the reason the external account binding key cannot be
+	// used, if any
+	EABKeyError error
+
+	// Account is the account as registered with the ACME server. If
+	// AccountError is not nil, it could not be fetched.
+	Account      *acmeapi.Account
+	AccountError error
+
+	// Inspected is true if the registered account has been compared with the
+	// state recorded in the issuer by InspectACMEAccount
+	Inspected bool
+	// Mismatches are the differences found by InspectACMEAccount between the
+	// registered account and the state recorded in the issuer
+	Mismatches []string
+}
+
+// CollectStatusForIssuer collects the status of the given Issuer or
+// ClusterIssuer. Secrets referenced by ClusterIssuers are looked up in
+// clusterResourceNamespace.
+// For ACME issuers, the account private key is read from its Secret and,
+// unless fetchAccount is nil, used to fetch the registered account from the
+// ACME server.
+func CollectStatusForIssuer(ctx context.Context, clients Clients, issuer cmapi.GenericIssuer, clusterResourceNamespace string, fetchAccount ACMEAccountFetcher) *IssuerStatus {
+	kind := cmapi.IssuerKind
+	secretNamespace := issuer.GetObjectMeta().Namespace
+	if _, ok := issuer.(*cmapi.ClusterIssuer); ok {
+		kind = cmapi.ClusterIssuerKind
+		secretNamespace = clusterResourceNamespace
+	}
+
+	status := &IssuerStatus{
+		Name:       issuer.GetObjectMeta().Name,
+		Kind:       kind,
+		Conditions: issuer.GetStatus().Conditions,
+	}
+	if issuer.GetSpec().ACME != nil {
+		status.ACMEAccount = collectACMEAccountStatus(ctx, clients, issuer, secretNamespace, fetchAccount)
+	}
+	return status
+}
+
+func collectACMEAccountStatus(ctx context.Context, clients Clients, issuer cmapi.GenericIssuer, secretNamespace string, fetchAccount ACMEAccountFetcher) *ACMEAccountStatus {
+	spec := issuer.GetSpec().ACME
+	status := &ACMEAccountStatus{
+		Server: spec.Server,
+		Email:  spec.Email,
+	}
+	if acmeStatus := issuer.GetStatus().ACME; acmeStatus != nil {
+		status.URI = acmeStatus.URI
+		status.LastRegisteredEmail = acmeStatus.LastRegisteredEmail
+	}
+
+	sel := acme.PrivateKeySelector(spec.PrivateKey)
+	status.PrivateKeySecret = sel.Name
+	var key *rsa.PrivateKey
+	keyBytes, err := secretKeyData(ctx, clients, secretNamespace, sel.Name, sel.Key)
+	if err == nil {
+		key, err = decodeAccountPrivateKey(keyBytes)
+	}
+	status.PrivateKeyError = err
+
+	if eab := spec.ExternalAccountBinding; eab != nil {
+		status.EABKeyID = eab.KeyID
+		_, status.EABKeyError = secretKeyData(ctx, clients, secretNamespace, eab.Key.Name, eab.Key.Key)
+	}
+
+	switch {
+	case fetchAccount == nil:
+	case key == nil:
+		status.AccountError = errors.New("the account private key is not available")
+	default:
+		status.Account, status.AccountError = fetchAccount(ctx, spec, key)
+	}
+
+	return status
+}
+
+// InspectACMEAccount compares the account registered with the ACME server
+// with the state recorded in the issuer, and stores every difference found in
+// the Mismatches of accountStatus. Mismatches are typically caused by
+// restoring the issuer or its private key Secret from a backup of a different
+// cluster, after which the private key no longer belongs to the recorded
+// account.
+func InspectACMEAccount(accountStatus *ACMEAccountStatus) {
+	accountStatus.Inspected = true
+	accountStatus.Mismatches = nil
+	add := func(format string, args ...interface{}) {
+		accountStatus.Mismatches = append(accountStatus.Mismatches, fmt.Sprintf(format, args...))
+	}
+
+	if accountStatus.URI == "" {
+		add("No account URI is recorded in the status of the issuer")
+	} else if u, err := url.Parse(accountStatus.URI); err == nil {
+		if s, err := url.Parse(accountStatus.Server); err == nil && u.Host != s.Host {
+			add("The recorded account URI %s does not belong to the ACME server %s", accountStatus.URI, accountStatus.Server)
+		}
+	}
+	if accountStatus.LastRegisteredEmail != accountStatus.Email {
+		add("The email in the spec of the issuer has changed since the account was last registered")
+	}
+
+	switch {
+	case accountStatus.AccountError == acmeapi.ErrNoAccount:
+		add("The account private key is not registered with the ACME server")
+		return
+	case accountStatus.Account == nil:
+		// Without the registered account nothing else can be compared
+		return
+	}
+
+	account := accountStatus.Account
+	if accountStatus.URI != "" && account.URI != accountStatus.URI {
+		add("The account private key belongs to account %s, but the status of the issuer records %s", account.URI, accountStatus.URI)
+	}
+	if account.Status != acmeapi.StatusValid {
+		add("The account has status %q", account.Status)
+	}
+	var expectedContact []string
+	if accountStatus.Email != "" {
+		expectedContact = []string{"mailto:" + accountStatus.Email}
+	}
+	if !util.EqualUnsorted(account.Contact, expectedContact) {
+		add("The account contact %q does not match the email in the spec of the issuer", strings.Join(account.Contact, ", "))
+	}
+}
+
+// secretKeyData returns the data stored under key in the Secret with the
+// given namespace and name.
+func secretKeyData(ctx context.Context, clients Clients, namespace, name, key string) ([]byte, error) {
+	secret, err := clients.getSecret(ctx, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("error when getting Secret %q: %v", namespace+"/"+name, err)
+	}
+	data, ok := secret.Data[key]
+	if !ok || len(data) == 0 {
+		return nil, fmt.Errorf("no data for %q in Secret %q", key, namespace+"/"+name)
+	}
+	return data, nil
+}
+
+func decodeAccountPrivateKey(keyBytes []byte) (*rsa.PrivateKey, error) {
+	signer, err := pki.DecodePrivateKeyBytes(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("the account private key is invalid: %v", err)
+	}
+	key, ok := signer.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("the account private key is not of type RSA")
+	}
+	return key, nil
+}
+
+// String returns the information about the ACME account as a string to be
+// printed as output
+func (accountStatus *ACMEAccountStatus) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ACME Account:\n")
+	fmt.Fprintf(&b, "  Server: %s\n", accountStatus.Server)
+	fmt.Fprintf(&b, "  URI: %s\n", valueOrNone(accountStatus.URI))
+	fmt.Fprintf(&b, "  Email: %s\n", valueOrNone(accountStatus.Email))
+	if accountStatus.LastRegisteredEmail != accountStatus.Email {
+		fmt.Fprintf(&b, "  Last Registered Email: %s\n", valueOrNone(accountStatus.LastRegisteredEmail))
+	}
+
+	fmt.Fprintf(&b, "  Private Key Secret: %s\n", accountStatus.PrivateKeySecret)
+	if accountStatus.PrivateKeyError != nil {
+		fmt.Fprintf(&b, "    %s: %v\n", output.Red("Error"), accountStatus.PrivateKeyError)
+	} else {
+		fmt.Fprintf(&b, "    %s\n", output.Green("Valid RSA private key"))
+	}
+
+	if accountStatus.EABKeyID == "" {
+		fmt.Fprintf(&b, "  External Account Binding: Not configured\n")
+	} else {
+		fmt.Fprintf(&b, "  External Account Binding:\n")
+		fmt.Fprintf(&b, "    Key ID: %s\n", accountStatus.EABKeyID)
+		if accountStatus.EABKeyError != nil {
+			fmt.Fprintf(&b, "    %s: %v\n", output.Red("Error"), accountStatus.EABKeyError)
+		}
+	}
+
+	switch {
+	case accountStatus.AccountError != nil:
+		fmt.Fprintf(&b, "  Registered Account:\n    %s: %v\n", output.Red("Error"), accountStatus.AccountError)
+	case accountStatus.Account != nil:
+		fmt.Fprintf(&b, "  Registered Account:\n")
+		fmt.Fprintf(&b, "    URI: %s\n", accountStatus.Account.URI)
+		fmt.Fprintf(&b, "    Status: %s\n", valueOrNone(accountStatus.Account.Status))
+		fmt.Fprintf(&b, "    Contact: %s\n", valueOrNone(strings.Join(accountStatus.Account.Contact, ", ")))
+		if accountStatus.Inspected {
+			fmt.Fprintf(&b, "    Orders URL: %s\n", valueOrNone(accountStatus.Account.OrdersURL))
+		}
+		if !accountStatus.Inspected && accountStatus.URI != "" && accountStatus.Account.URI != accountStatus.URI {
+			fmt.Fprintf(&b, "    Warning: the registered account URI does not match the URI in the status of the issuer\n")
+		}
+	}
+
+	if accountStatus.Inspected {
+		if len(accountStatus.Mismatches) == 0 {
+			fmt.Fprintf(&b, "  Account Inspection: %s\n", output.Green("The registered account matches the issuer"))
+		} else {
+			fmt.Fprintf(&b, "  Account Inspection:\n")
+			for _, m := range accountStatus.Mismatches {
+				fmt.Fprintf(&b, "    %s: %s\n", output.Red("Mismatch"), m)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func valueOrNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
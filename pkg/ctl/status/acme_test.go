@@ -0,0 +1,290 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"strings"
+	"testing"
+
+	acmeapi "golang.org/x/crypto/acme"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+func TestCollectStatusForIssuer(t *testing.T) {
+	rsaKey, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := pki.GenerateECPrivateKey(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKeyBytes, err := pki.EncodeECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newSecret := func(namespace, name, key string, data []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Data:       map[string][]byte{key: data},
+		}
+	}
+	acmeSpec := func(eab *cmacme.ACMEExternalAccountBinding) cmapi.IssuerSpec {
+		return cmapi.IssuerSpec{IssuerConfig: cmapi.IssuerConfig{ACME: &cmacme.ACMEIssuer{
+			Server:                 "https://acme.example.com/directory",
+			Email:                  "admin@example.com",
+			PrivateKey:             cmmeta.SecretKeySelector{LocalObjectReference: cmmeta.LocalObjectReference{Name: "account-key"}},
+			ExternalAccountBinding: eab,
+		}}}
+	}
+	acmeStatus := cmapi.IssuerStatus{
+		Conditions: []cmapi.IssuerCondition{{Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionTrue}},
+		ACME:       &cmacme.ACMEIssuerStatus{URI: "https://acme.example.com/acct/1", LastRegisteredEmail: "admin@example.com"},
+	}
+	eab := &cmacme.ACMEExternalAccountBinding{
+		KeyID: "kid-1",
+		Key:   cmmeta.SecretKeySelector{LocalObjectReference: cmmeta.LocalObjectReference{Name: "eab"}, Key: "secret"},
+	}
+	fetchAccount := func(ctx context.Context, issuer *cmacme.ACMEIssuer, key *rsa.PrivateKey) (*acmeapi.Account, error) {
+		if key.N.Cmp(rsaKey.N) != 0 {
+			return nil, errors.New("unexpected key")
+		}
+		return &acmeapi.Account{URI: "https://acme.example.com/acct/1", Status: "valid", Contact: []string{"mailto:admin@example.com"}}, nil
+	}
+
+	tests := map[string]struct {
+		issuer  cmapi.GenericIssuer
+		secrets []runtime.Object
+		fetch   ACMEAccountFetcher
+
+		expKind          string
+		expACME          bool
+		expPrivateKeyErr bool
+		expEABKeyID      string
+		expEABKeyErr     bool
+		expAccountURI    string
+		expAccountErr    bool
+	}{
+		"non-ACME issuer has no account status": {
+			issuer: &cmapi.Issuer{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ca"},
+				Spec:       cmapi.IssuerSpec{IssuerConfig: cmapi.IssuerConfig{CA: &cmapi.CAIssuer{SecretName: "ca"}}},
+			},
+			expKind: "Issuer",
+		},
+		"ACME issuer with valid private key and registered account": {
+			issuer: &cmapi.Issuer{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "acme"},
+				Spec:       acmeSpec(nil),
+				Status:     acmeStatus,
+			},
+			secrets:       []runtime.Object{newSecret("default", "account-key", "tls.key", pki.EncodePKCS1PrivateKey(rsaKey))},
+			fetch:         fetchAccount,
+			expKind:       "Issuer",
+			expACME:       true,
+			expAccountURI: "https://acme.example.com/acct/1",
+		},
+		"ACME issuer with missing private key Secret": {
+			issuer: &cmapi.Issuer{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "acme"},
+				Spec:       acmeSpec(nil),
+			},
+			fetch:            fetchAccount,
+			expKind:          "Issuer",
+			expACME:          true,
+			expPrivateKeyErr: true,
+			expAccountErr:    true,
+		},
+		"ACME issuer with non-RSA private key": {
+			issuer: &cmapi.Issuer{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "acme"},
+				Spec:       acmeSpec(nil),
+			},
+			secrets:          []runtime.Object{newSecret("default", "account-key", "tls.key", ecKeyBytes)},
+			expKind:          "Issuer",
+			expACME:          true,
+			expPrivateKeyErr: true,
+		},
+		"ACME ClusterIssuer reads Secrets from the cluster resource namespace": {
+			issuer: &cmapi.ClusterIssuer{
+				ObjectMeta: metav1.ObjectMeta{Name: "acme"},
+				Spec:       acmeSpec(eab),
+				Status:     acmeStatus,
+			},
+			secrets: []runtime.Object{
+				newSecret("kube-system", "account-key", "tls.key", pki.EncodePKCS1PrivateKey(rsaKey)),
+				newSecret("kube-system", "eab", "secret", []byte("c2VjcmV0")),
+			},
+			expKind:     "ClusterIssuer",
+			expACME:     true,
+			expEABKeyID: "kid-1",
+		},
+		"ACME issuer with missing external account binding key": {
+			issuer: &cmapi.Issuer{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "acme"},
+				Spec:       acmeSpec(eab),
+			},
+			secrets:      []runtime.Object{newSecret("default", "account-key", "tls.key", pki.EncodePKCS1PrivateKey(rsaKey))},
+			expKind:      "Issuer",
+			expACME:      true,
+			expEABKeyID:  "kid-1",
+			expEABKeyErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clients := Clients{Kube: kubefake.NewSimpleClientset(test.secrets...)}
+			status := CollectStatusForIssuer(context.TODO(), clients, test.issuer, "kube-system", test.fetch)
+
+			if status.Kind != test.expKind {
+				t.Errorf("expected kind %q, got: %q", test.expKind, status.Kind)
+			}
+			if len(status.Conditions) != len(test.issuer.GetStatus().Conditions) {
+				t.Errorf("expected conditions %v, got: %v", test.issuer.GetStatus().Conditions, status.Conditions)
+			}
+			if (status.ACMEAccount != nil) != test.expACME {
+				t.Fatalf("expected ACME account status: %t, got: %+v", test.expACME, status.ACMEAccount)
+			}
+			if status.ACMEAccount == nil {
+				return
+			}
+
+			account := status.ACMEAccount
+			if (account.PrivateKeyError != nil) != test.expPrivateKeyErr {
+				t.Errorf("expected private key error: %t, got: %v", test.expPrivateKeyErr, account.PrivateKeyError)
+			}
+			if account.EABKeyID != test.expEABKeyID {
+				t.Errorf("expected EAB key ID %q, got: %q", test.expEABKeyID, account.EABKeyID)
+			}
+			if (account.EABKeyError != nil) != test.expEABKeyErr {
+				t.Errorf("expected EAB key error: %t, got: %v", test.expEABKeyErr, account.EABKeyError)
+			}
+			if (account.AccountError != nil) != test.expAccountErr {
+				t.Errorf("expected account error: %t, got: %v", test.expAccountErr, account.AccountError)
+			}
+			if test.expAccountURI != "" && (account.Account == nil || account.Account.URI != test.expAccountURI) {
+				t.Errorf("expected registered account %q, got: %+v", test.expAccountURI, account.Account)
+			}
+			if !strings.Contains(status.String(), "ACME Account:") {
+				t.Errorf("expected ACME account in output, got:\n%s", status.String())
+			}
+		})
+	}
+}
+
+func TestInspectACMEAccount(t *testing.T) {
+	upToDate := func() *ACMEAccountStatus {
+		return &ACMEAccountStatus{
+			Server:              "https://acme.example.com/directory",
+			URI:                 "https://acme.example.com/acct/1",
+			Email:               "admin@example.com",
+			LastRegisteredEmail: "admin@example.com",
+			Account: &acmeapi.Account{
+				URI:       "https://acme.example.com/acct/1",
+				Status:    acmeapi.StatusValid,
+				Contact:   []string{"mailto:admin@example.com"},
+				OrdersURL: "https://acme.example.com/acct/1/orders",
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		mutate        func(*ACMEAccountStatus)
+		expMismatches int
+	}{
+		"registered account matching the issuer has no mismatches": {},
+		"private key belonging to a different account": {
+			mutate:        func(s *ACMEAccountStatus) { s.Account.URI = "https://acme.example.com/acct/2" },
+			expMismatches: 1,
+		},
+		"private key not registered with the ACME server": {
+			mutate: func(s *ACMEAccountStatus) {
+				s.Account = nil
+				s.AccountError = acmeapi.ErrNoAccount
+			},
+			expMismatches: 1,
+		},
+		"account URI not recorded in the issuer": {
+			mutate:        func(s *ACMEAccountStatus) { s.URI = "" },
+			expMismatches: 1,
+		},
+		"recorded account URI of a different ACME server": {
+			mutate: func(s *ACMEAccountStatus) {
+				s.URI = "https://staging.example.com/acct/1"
+				s.Account.URI = s.URI
+			},
+			expMismatches: 1,
+		},
+		"deactivated account with a different contact": {
+			mutate: func(s *ACMEAccountStatus) {
+				s.Account.Status = acmeapi.StatusDeactivated
+				s.Account.Contact = []string{"mailto:old@example.com"}
+			},
+			expMismatches: 2,
+		},
+		"email changed since the account was last registered": {
+			mutate: func(s *ACMEAccountStatus) {
+				s.Email = "new@example.com"
+				s.Account.Contact = []string{"mailto:new@example.com"}
+			},
+			expMismatches: 1,
+		},
+		"account that could not be fetched is not compared": {
+			mutate: func(s *ACMEAccountStatus) {
+				s.Account = nil
+				s.AccountError = errors.New("connection refused")
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			status := upToDate()
+			if test.mutate != nil {
+				test.mutate(status)
+			}
+			InspectACMEAccount(status)
+
+			if !status.Inspected {
+				t.Errorf("expected account status to be marked as inspected")
+			}
+			if len(status.Mismatches) != test.expMismatches {
+				t.Errorf("expected %d mismatches, got: %v", test.expMismatches, status.Mismatches)
+			}
+			out := status.String()
+			if !strings.Contains(out, "Account Inspection:") {
+				t.Errorf("expected account inspection in output, got:\n%s", out)
+			}
+			if status.Account != nil && !strings.Contains(out, status.Account.OrdersURL) {
+				t.Errorf("expected orders URL in output, got:\n%s", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/jetstack/cert-manager/pkg/acme"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+)
+
+// solverSelector selects the Pods, Services and Ingresses created by the
+// HTTP01 solver.
+const solverSelector = "acme.cert-manager.io/http01-solver=true"
+
+// ingressClassAnnotation is the annotation the HTTP01 solver sets on its
+// Ingresses to select the ingress controller.
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+
+// ChallengeStatus is the status of a pending Challenge of the current
+// issuance of a Certificate.
+type ChallengeStatus struct {
+	// Name of the Challenge resource
+	Name string
+	// Type of the Challenge, e.g. http-01
+	Type string
+	// DNSName the Challenge is for
+	DNSName string
+	// State and Reason of the Challenge resource
+	State  string
+	Reason string
+	// Solvers are the Pods, Services and Ingresses created by the HTTP01
+	// solver of the Challenge
+	Solvers []*SolverResourceStatus
+}
+
+// SolverResourceStatus is the status of a Pod, Service or Ingress created by
+// the HTTP01 solver of a Challenge.
+type SolverResourceStatus struct {
+	// Kind and Name of the resource
+	Kind string
+	Name string
+	// Phase summarises the state of the resource, e.g. the phase of a Pod
+	Phase string
+	// Problems that keep the resource from serving the challenge, e.g. an
+	// image that cannot be pulled
+	Problems []string
+	// Events of the resource
+	Events *corev1.EventList
+	// Logs is the tail of the logs of a solver Pod. It is only collected if
+	// requested in the CollectOptions.
+	Logs string
+	// LogsError is the error when getting the logs of a solver Pod, if any
+	LogsError error
+}
+
+// collectChallenges returns the status of the Challenges of the Orders of
+// req that are not in a final state, including the resources of their
+// HTTP01 solvers.
+func collectChallenges(ctx context.Context, clients Clients, req *cmapi.CertificateRequest, opts CollectOptions) ([]*ChallengeStatus, error) {
+	ns := req.Namespace
+
+	orders, err := clients.CM.AcmeV1alpha2().Orders(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing Order resources: %v", err)
+	}
+	orderUIDs := make(map[types.UID]bool)
+	for i := range orders.Items {
+		if metav1.IsControlledBy(&orders.Items[i], req) {
+			orderUIDs[orders.Items[i].UID] = true
+		}
+	}
+	if len(orderUIDs) == 0 {
+		return nil, nil
+	}
+
+	challenges, err := clients.CM.AcmeV1alpha2().Challenges(ns).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing Challenge resources: %v", err)
+	}
+	var pending []*cmacme.Challenge
+	hasHTTP01 := false
+	for i := range challenges.Items {
+		ch := &challenges.Items[i]
+		ref := metav1.GetControllerOf(ch)
+		if ref == nil || !orderUIDs[ref.UID] || acme.IsFinalState(ch.Status.State) {
+			continue
+		}
+		pending = append(pending, ch)
+		hasHTTP01 = hasHTTP01 || ch.Spec.Type == cmacme.ACMEChallengeTypeHTTP01
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Name < pending[j].Name
+	})
+
+	var solvers map[types.UID][]*SolverResourceStatus
+	if hasHTTP01 {
+		solvers, err = collectSolverResources(ctx, clients, ns, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var statuses []*ChallengeStatus
+	for _, ch := range pending {
+		statuses = append(statuses, &ChallengeStatus{
+			Name:    ch.Name,
+			Type:    string(ch.Spec.Type),
+			DNSName: ch.Spec.DNSName,
+			State:   string(ch.Status.State),
+			Reason:  ch.Status.Reason,
+			Solvers: solvers[ch.UID],
+		})
+	}
+	return statuses, nil
+}
+
+// collectSolverResources returns the status of the Pods, Services and
+// Ingresses of HTTP01 solvers in namespace, by the UID of the Challenge
+// that controls them.
+func collectSolverResources(ctx context.Context, clients Clients, namespace string, opts CollectOptions) (map[types.UID][]*SolverResourceStatus, error) {
+	solvers := make(map[types.UID][]*SolverResourceStatus)
+	add := func(obj metav1.Object, status *SolverResourceStatus) {
+		ref := metav1.GetControllerOf(obj)
+		if ref == nil {
+			return
+		}
+		// Ignore error, since if there was an error, Events would be nil and handled down the line in DescribeEvents
+		status.Events, _ = clients.searchEvents(ctx, obj.(runtime.Object))
+		solvers[ref.UID] = append(solvers[ref.UID], status)
+	}
+
+	listOpts := metav1.ListOptions{LabelSelector: solverSelector}
+	pods, err := clients.Kube.CoreV1().Pods(namespace).List(ctx, listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error when listing solver Pods: %v", err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		status := newSolverPodStatus(pod)
+		if opts.SolverLogTailLines > 0 {
+			logs, err := podLogs(ctx, clients.Kube, pod, opts.SolverLogTailLines)
+			if err != nil {
+				status.LogsError = err
+			} else {
+				status.Logs = string(logs)
+			}
+		}
+		add(pod, status)
+	}
+
+	services, err := clients.Kube.CoreV1().Services(namespace).List(ctx, listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error when listing solver Services: %v", err)
+	}
+	for i := range services.Items {
+		svc := &services.Items[i]
+		add(svc, &SolverResourceStatus{Kind: "Service", Name: svc.Name, Phase: string(svc.Spec.Type)})
+	}
+
+	ingresses, err := clients.Kube.ExtensionsV1beta1().Ingresses(namespace).List(ctx, listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error when listing solver Ingresses: %v", err)
+	}
+	for i := range ingresses.Items {
+		ing := &ingresses.Items[i]
+		status := &SolverResourceStatus{Kind: "Ingress", Name: ing.Name, Phase: "Ready"}
+		if len(ing.Status.LoadBalancer.Ingress) == 0 {
+			class := ing.Annotations[ingressClassAnnotation]
+			if class == "" {
+				class = "<default>"
+			}
+			status.Phase = "NoAddress"
+			status.Problems = append(status.Problems, fmt.Sprintf("no address has been assigned, check that an ingress controller serves the ingress class %q", class))
+		}
+		add(ing, status)
+	}
+
+	for _, statuses := range solvers {
+		sort.Slice(statuses, func(i, j int) bool {
+			if statuses[i].Kind != statuses[j].Kind {
+				return statuses[i].Kind < statuses[j].Kind
+			}
+			return statuses[i].Name < statuses[j].Name
+		})
+	}
+	return solvers, nil
+}
+
+// podLogs returns the last tailLines lines of the logs of pod. It is a
+// variable so that it can be replaced in tests, since the fake clientset
+// does not return logs.
+var podLogs = func(ctx context.Context, kube kubernetes.Interface, pod *corev1.Pod, tailLines int64) ([]byte, error) {
+	return kube.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{TailLines: &tailLines}).DoRaw(ctx)
+}
+
+// newSolverPodStatus returns the status of a solver Pod, with the reasons
+// that keep it from being scheduled or its containers from running as
+// problems.
+func newSolverPodStatus(pod *corev1.Pod) *SolverResourceStatus {
+	status := &SolverResourceStatus{Kind: "Pod", Name: pod.Name, Phase: string(pod.Status.Phase)}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+			status.Problems = append(status.Problems, fmt.Sprintf("not scheduled: %s: %s", cond.Reason, cond.Message))
+		}
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		switch {
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason != "ContainerCreating":
+			status.Problems = append(status.Problems, fmt.Sprintf("container %q is waiting: %s: %s", cs.Name, cs.State.Waiting.Reason, cs.State.Waiting.Message))
+		case cs.State.Terminated != nil && cs.State.Terminated.ExitCode != 0:
+			status.Problems = append(status.Problems, fmt.Sprintf("container %q terminated with exit code %d: %s", cs.Name, cs.State.Terminated.ExitCode, cs.State.Terminated.Reason))
+		}
+	}
+	return status
+}
+
+// String returns the information about the status of a Challenge and its
+// solver resources as a string to be printed as output
+func (challengeStatus *ChallengeStatus) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "- Name: %s\n", challengeStatus.Name)
+	fmt.Fprintf(&b, "  Type: %s\n", challengeStatus.Type)
+	fmt.Fprintf(&b, "  DNS Name: %s\n", challengeStatus.DNSName)
+	fmt.Fprintf(&b, "  State: %s\n", valueOrNone(challengeStatus.State))
+	if challengeStatus.Reason != "" {
+		fmt.Fprintf(&b, "  Reason: %s\n", challengeStatus.Reason)
+	}
+	if len(challengeStatus.Solvers) == 0 {
+		return b.String()
+	}
+	b.WriteString("  Solver Resources:\n")
+	for _, solver := range challengeStatus.Solvers {
+		b.WriteString(solver.String())
+	}
+	return b.String()
+}
+
+// String returns the information about the status of a solver resource as
+// a string to be printed as output
+func (solverStatus *SolverResourceStatus) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "    %s %s: %s\n", solverStatus.Kind, solverStatus.Name, valueOrNone(solverStatus.Phase))
+	for _, problem := range solverStatus.Problems {
+		fmt.Fprintf(&b, "      Problem: %s\n", problem)
+	}
+	if solverStatus.Events != nil && len(solverStatus.Events.Items) > 0 {
+		b.WriteString(describeEvents(solverStatus.Events, 3))
+	}
+	switch {
+	case solverStatus.LogsError != nil:
+		fmt.Fprintf(&b, "      Logs: error when getting logs: %v\n", solverStatus.LogsError)
+	case solverStatus.Logs != "":
+		b.WriteString("      Logs:\n")
+		for _, line := range strings.Split(strings.TrimRight(solverStatus.Logs, "\n"), "\n") {
+			fmt.Fprintf(&b, "        %s\n", line)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+)
+
+func TestCollectChallenges(t *testing.T) {
+	req := &cmapi.CertificateRequest{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-1", UID: "req-uid"}}
+	order := &cmacme.Order{ObjectMeta: metav1.ObjectMeta{
+		Namespace:       "default",
+		Name:            "test-1-1",
+		UID:             "order-uid",
+		OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(req, cmapi.SchemeGroupVersion.WithKind(cmapi.CertificateRequestKind))},
+	}}
+	orderRef := *metav1.NewControllerRef(order, cmacme.SchemeGroupVersion.WithKind("Order"))
+	newChallenge := func(name string, state cmacme.State) *cmacme.Challenge {
+		return &cmacme.Challenge{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "default",
+				Name:            name,
+				UID:             types.UID("uid-" + name),
+				OwnerReferences: []metav1.OwnerReference{orderRef},
+			},
+			Spec:   cmacme.ChallengeSpec{Type: cmacme.ACMEChallengeTypeHTTP01, DNSName: name + ".example.com"},
+			Status: cmacme.ChallengeStatus{State: state, Reason: "Waiting for HTTP-01 challenge propagation"},
+		}
+	}
+	pending := newChallenge("pending", cmacme.Pending)
+	solverMeta := func(name string, owner *cmacme.Challenge) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            name,
+			Labels:          map[string]string{"acme.cert-manager.io/http01-solver": "true"},
+			Annotations:     map[string]string{ingressClassAnnotation: "nginx"},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(owner, schema.GroupVersionKind{Group: "acme.cert-manager.io", Version: "v1alpha2", Kind: "Challenge"})},
+		}
+	}
+
+	tests := map[string]struct {
+		cmObjects   []runtime.Object
+		kubeObjects []runtime.Object
+		opts        CollectOptions
+		exp         []*ChallengeStatus
+	}{
+		"no Order for the CertificateRequest": {
+			cmObjects: []runtime.Object{pending},
+		},
+		"challenges in a final state are omitted": {
+			cmObjects: []runtime.Object{order, newChallenge("valid", cmacme.Valid), newChallenge("invalid", cmacme.Invalid)},
+		},
+		"solver resources of a pending challenge are collected": {
+			cmObjects: []runtime.Object{order, pending, newChallenge("valid", cmacme.Valid)},
+			kubeObjects: []runtime.Object{
+				&corev1.Pod{
+					ObjectMeta: solverMeta("solver-pod", pending),
+					Status: corev1.PodStatus{
+						Phase: corev1.PodPending,
+						ContainerStatuses: []corev1.ContainerStatus{{
+							Name:  "acmesolver",
+							State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}},
+						}},
+					},
+				},
+				&corev1.Service{ObjectMeta: solverMeta("solver-svc", pending), Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort}},
+				&extv1beta1.Ingress{ObjectMeta: solverMeta("solver-ing", pending)},
+			},
+			exp: []*ChallengeStatus{{
+				Name:    "pending",
+				Type:    "http-01",
+				DNSName: "pending.example.com",
+				State:   "pending",
+				Reason:  "Waiting for HTTP-01 challenge propagation",
+				Solvers: []*SolverResourceStatus{
+					{Kind: "Ingress", Name: "solver-ing", Phase: "NoAddress", Problems: []string{`no address has been assigned, check that an ingress controller serves the ingress class "nginx"`}},
+					{Kind: "Pod", Name: "solver-pod", Phase: "Pending", Problems: []string{`container "acmesolver" is waiting: ImagePullBackOff: Back-off pulling image`}},
+					{Kind: "Service", Name: "solver-svc", Phase: "NodePort"},
+				},
+			}},
+		},
+		"logs of solver Pods are collected if requested": {
+			cmObjects:   []runtime.Object{order, pending},
+			kubeObjects: []runtime.Object{&corev1.Pod{ObjectMeta: solverMeta("solver-pod", pending), Status: corev1.PodStatus{Phase: corev1.PodRunning}}},
+			opts:        CollectOptions{SolverLogTailLines: 20},
+			exp: []*ChallengeStatus{{
+				Name:    "pending",
+				Type:    "http-01",
+				DNSName: "pending.example.com",
+				State:   "pending",
+				Reason:  "Waiting for HTTP-01 challenge propagation",
+				Solvers: []*SolverResourceStatus{
+					{Kind: "Pod", Name: "solver-pod", Phase: "Running", Logs: "last 20 lines of solver-pod"},
+				},
+			}},
+		},
+	}
+
+	defer func(orig func(context.Context, kubernetes.Interface, *corev1.Pod, int64) ([]byte, error)) {
+		podLogs = orig
+	}(podLogs)
+	podLogs = func(_ context.Context, _ kubernetes.Interface, pod *corev1.Pod, tailLines int64) ([]byte, error) {
+		return []byte(fmt.Sprintf("last %d lines of %s", tailLines, pod.Name)), nil
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clients := Clients{
+				Kube: kubefake.NewSimpleClientset(test.kubeObjects...),
+				CM:   cmfake.NewSimpleClientset(test.cmObjects...),
+			}
+			statuses, err := collectChallenges(context.TODO(), clients, req, test.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Events are not compared, the fake clientset has none
+			for _, status := range statuses {
+				for _, solver := range status.Solvers {
+					solver.Events = nil
+				}
+			}
+			if !reflect.DeepEqual(statuses, test.exp) {
+				t.Errorf("unexpected challenges; expected: %+v, got: %+v", test.exp, statuses)
+			}
+		})
+	}
+}
+
+func TestChallengeStatusString(t *testing.T) {
+	status := &ChallengeStatus{
+		Name:    "pending",
+		Type:    "http-01",
+		DNSName: "example.com",
+		State:   "pending",
+		Solvers: []*SolverResourceStatus{
+			{Kind: "Pod", Name: "solver-pod", Phase: "Pending", Problems: []string{"not scheduled"}, Logs: "line 1\nline 2\n"},
+		},
+	}
+	exp := `- Name: pending
+  Type: http-01
+  DNS Name: example.com
+  State: pending
+  Solver Resources:
+    Pod solver-pod: Pending
+      Problem: not scheduled
+      Logs:
+        line 1
+        line 2
+`
+	if got := status.String(); got != exp {
+		t.Errorf("unexpected output; expected:\n%s\ngot:\n%s", exp, got)
+	}
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package status collects the status of cert-manager resources and the
+// resources related to them, and renders it in a human readable format.
+// It is used by the 'status' command of kubectl cert-manager, and can be
+// embedded by other tools to print the same information.
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/reference"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	"github.com/jetstack/cert-manager/pkg/ctl"
+	ctlclients "github.com/jetstack/cert-manager/pkg/ctl/clients"
+	"github.com/jetstack/cert-manager/pkg/util/predicate"
+)
+
+// Clients are the clients used to collect the status of cert-manager
+// resources.
+type Clients struct {
+	// Kube is used to get Secrets and Events
+	Kube kubernetes.Interface
+	// CM is used to get cert-manager resources
+	CM cmclient.Interface
+	// Dynamic and RESTMapper are used to get issuers of third party API
+	// groups. If either is nil, an error is reported as the status of those
+	// issuers.
+	Dynamic    dynamic.Interface
+	RESTMapper meta.RESTMapper
+	// Cache is used instead of Kube and CM to look up Secrets, Events and
+	// cert-manager resources, if not nil. It avoids getting resources one
+	// by one when collecting the status of many Certificates.
+	Cache *ctlclients.Cache
+}
+
+func (c Clients) getSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	if c.Cache != nil {
+		return c.Cache.GetSecret(ctx, namespace, name)
+	}
+	return c.Kube.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c Clients) getIssuer(ctx context.Context, namespace, name string) (*cmapi.Issuer, error) {
+	if c.Cache != nil {
+		return c.Cache.GetIssuer(ctx, namespace, name)
+	}
+	return c.CM.CertmanagerV1alpha2().Issuers(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c Clients) getClusterIssuer(ctx context.Context, name string) (*cmapi.ClusterIssuer, error) {
+	if c.Cache != nil {
+		return c.Cache.GetClusterIssuer(ctx, name)
+	}
+	return c.CM.CertmanagerV1alpha2().ClusterIssuers().Get(ctx, name, metav1.GetOptions{})
+}
+
+// searchEvents returns the Events about obj, which must be a Certificate,
+// CertificateRequest or a resource of an HTTP01 solver.
+func (c Clients) searchEvents(ctx context.Context, obj runtime.Object) (*corev1.EventList, error) {
+	if c.Cache != nil {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		return c.Cache.EventsFor(ctx, accessor)
+	}
+	ref, err := reference.GetReference(ctl.Scheme, obj)
+	if err != nil {
+		return nil, err
+	}
+	return c.Kube.CoreV1().Events(ref.Namespace).Search(ctl.Scheme, ref)
+}
+
+// CollectOptions select details that are collected in addition to the
+// status of a Certificate.
+type CollectOptions struct {
+	// SolverLogTailLines is the number of lines of the logs of HTTP01 solver
+	// Pods that are collected. No logs are collected if it is zero.
+	SolverLogTailLines int64
+}
+
+// CollectCertificateStatus gets the Certificate with the given namespace and
+// name and collects its status. See CollectStatusForCertificate.
+func CollectCertificateStatus(ctx context.Context, clients Clients, namespace, name string) (*CertificateStatus, error) {
+	crt, err := clients.CM.CertmanagerV1alpha2().Certificates(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when getting Certificate resource: %v", err)
+	}
+	return CollectStatusForCertificate(ctx, clients, crt)
+}
+
+// CollectStatusForCertificate collects the status of crt, its Events, the
+// certificate stored in its Secret, its issuer, and the CertificateRequest
+// for its current issuance with its Events. While the CertificateRequest is
+// not Ready, the pending ACME Challenges and the resources of their HTTP01
+// solvers are collected too.
+// Errors when getting the related resources are stored in their status, so
+// that the status of the others can still be rendered.
+func CollectStatusForCertificate(ctx context.Context, clients Clients, crt *cmapi.Certificate) (*CertificateStatus, error) {
+	return CollectStatusForCertificateWithOptions(ctx, clients, crt, CollectOptions{})
+}
+
+// CollectStatusForCertificateWithOptions collects the status of crt like
+// CollectStatusForCertificate, including the details selected by opts.
+func CollectStatusForCertificateWithOptions(ctx context.Context, clients Clients, crt *cmapi.Certificate, opts CollectOptions) (*CertificateStatus, error) {
+	if _, err := reference.GetReference(ctl.Scheme, crt); err != nil {
+		return nil, err
+	}
+	// Ignore error, since if there was an error, crtEvents would be nil and handled down the line in DescribeEvents
+	crtEvents, _ := clients.searchEvents(ctx, crt)
+
+	secret, secretErr := clients.getSecret(ctx, crt.Namespace, crt.Spec.SecretName)
+	if secretErr != nil {
+		secretErr = fmt.Errorf("error when finding Secret %q: %w\n", crt.Spec.SecretName, secretErr)
+	}
+
+	// TODO: What about timing issues? When I query condition it's not ready yet, but then looking for cr it's finished and deleted
+	// Try find the CertificateRequest that is owned by crt and has the correct revision
+	req, reqErr := findMatchingCR(ctx, clients, crt)
+	if reqErr != nil {
+		reqErr = fmt.Errorf("error when finding CertificateRequest: %w\n", reqErr)
+	}
+	if req == nil {
+		reqErr = errors.New("No CertificateRequest found for this Certificate\n")
+	}
+
+	var reqEvents *corev1.EventList
+	if req != nil {
+		if _, err := reference.GetReference(ctl.Scheme, req); err != nil {
+			return nil, err
+		}
+		// Ignore error, since if there was an error, reqEvents would be nil and handled down the line in DescribeEvents
+		reqEvents, _ = clients.searchEvents(ctx, req)
+	}
+
+	// Build status of Certificate with data gathered
+	status := newCertificateStatusFromCert(crt).
+		withEvents(crtEvents).
+		withSecret(secret, secretErr).
+		withCR(req, reqEvents, reqErr)
+
+	if req != nil && !apiutil.CertificateRequestHasCondition(req, cmapi.CertificateRequestCondition{
+		Type:   cmapi.CertificateRequestConditionReady,
+		Status: cmmeta.ConditionTrue,
+	}) {
+		challenges, challengesErr := collectChallenges(ctx, clients, req, opts)
+		if challengesErr != nil {
+			challengesErr = fmt.Errorf("error when finding Challenges: %v\n", challengesErr)
+		}
+		status = status.withChallenges(challenges, challengesErr)
+	}
+
+	issuerKind := crt.Spec.IssuerRef.Kind
+	if issuerKind == "" {
+		issuerKind = "Issuer"
+	}
+
+	// Get info on Issuer/ClusterIssuer
+	if crt.Spec.IssuerRef.Group != "cert-manager.io" && crt.Spec.IssuerRef.Group != "" {
+		issuerRef := crt.Spec.IssuerRef
+		issuerRef.Kind = issuerKind
+		var issuer *unstructured.Unstructured
+		var issuerErr error
+		if clients.Dynamic == nil || clients.RESTMapper == nil {
+			issuerErr = errors.New("no dynamic client configured")
+		} else {
+			issuer, issuerErr = getExternalIssuer(ctx, clients.Dynamic, clients.RESTMapper, crt.Namespace, issuerRef)
+		}
+		if issuerErr != nil {
+			issuerErr = fmt.Errorf("error when getting %s.%s %q: %v\n", issuerKind, issuerRef.Group, issuerRef.Name, issuerErr)
+		}
+		status = status.withExternalIssuer(issuer, issuerErr)
+	} else if issuerKind == "Issuer" {
+		issuer, issuerErr := clients.getIssuer(ctx, crt.Namespace, crt.Spec.IssuerRef.Name)
+		if issuerErr != nil {
+			issuerErr = fmt.Errorf("error when getting Issuer: %v\n", issuerErr)
+		}
+		status = status.withIssuer(issuer, issuerErr)
+	} else {
+		// ClusterIssuer
+		clusterIssuer, issuerErr := clients.getClusterIssuer(ctx, crt.Spec.IssuerRef.Name)
+		if issuerErr != nil {
+			issuerErr = fmt.Errorf("error when getting ClusterIssuer: %v\n", issuerErr)
+		}
+		status = status.withClusterIssuer(clusterIssuer, issuerErr)
+	}
+
+	return status, nil
+}
+
+// listPageSize is the number of CertificateRequest resources requested per
+// page when searching for the CertificateRequest of a Certificate.
+const listPageSize = 250
+
+// findMatchingCR tries to find a CertificateRequest that is owned by crt and has the correct revision annotated from reqs.
+// If none found returns nil
+// If one found returns the CR
+// If multiple found or error occurs when listing CRs, returns error
+func findMatchingCR(ctx context.Context, clients Clients, crt *cmapi.Certificate) (*cmapi.CertificateRequest, error) {
+	// CertificateRequest revisions begin from 1.
+	// If no revision is set on the Certificate then assume the revision on the CertificateRequest should be 1.
+	// If revision is set on the Certificate then revision on the CertificateRequest should be crt.Status.Revision + 1.
+	nextRevision := 1
+	if crt.Status.Revision != nil {
+		nextRevision = *crt.Status.Revision + 1
+	}
+
+	// CertificateRequests are labelled with their revision and, if it is a
+	// valid label value, the name of their Certificate, so only those are
+	// requested from the API server.
+	selector := labels.Set{cmapi.CertificateRequestRevisionLabelKey: strconv.Itoa(nextRevision)}
+	if len(validation.IsValidLabelValue(crt.Name)) == 0 {
+		selector[cmapi.CertificateNameKey] = crt.Name
+	}
+	possibleMatches, err := listMatchingCRs(ctx, clients, crt, nextRevision, selector.AsSelector())
+	if err != nil {
+		return nil, err
+	}
+	// CertificateRequests created by older versions of cert-manager are not
+	// labelled, so fall back to checking every CertificateRequest in the
+	// namespace.
+	if len(possibleMatches) < 1 {
+		possibleMatches, err = listMatchingCRs(ctx, clients, crt, nextRevision, labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(possibleMatches) < 1 {
+		return nil, nil
+	} else if len(possibleMatches) == 1 {
+		return possibleMatches[0], nil
+	} else {
+		return nil, errors.New("found multiple certificate requests with expected revision and owner")
+	}
+}
+
+// listMatchingCRs pages through the CertificateRequests in the namespace of
+// crt matching selector, or looks them up in the Cache of clients, and
+// returns those owned by crt with the given revision annotated.
+func listMatchingCRs(ctx context.Context, clients Clients, crt *cmapi.Certificate, revision int, selector labels.Selector) ([]*cmapi.CertificateRequest, error) {
+	var matches []*cmapi.CertificateRequest
+	isMatch := func(req *cmapi.CertificateRequest) bool {
+		return predicate.CertificateRequestRevision(revision)(req) &&
+			predicate.ResourceOwnedBy(crt)(req)
+	}
+
+	if clients.Cache != nil {
+		reqs, err := clients.Cache.ListCertificateRequests(ctx, crt.Namespace, selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, req := range reqs {
+			if isMatch(req) {
+				matches = append(matches, req)
+			}
+		}
+		return matches, nil
+	}
+
+	opts := metav1.ListOptions{LabelSelector: selector.String(), Limit: listPageSize}
+	for {
+		reqs, err := clients.CM.CertmanagerV1alpha2().CertificateRequests(crt.Namespace).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error when listing CertificateRequest resources: %w", err)
+		}
+		for _, req := range reqs.Items {
+			if isMatch(&req) {
+				matches = append(matches, req.DeepCopy())
+			}
+		}
+		if reqs.Continue == "" {
+			return matches, nil
+		}
+		opts.Continue = reqs.Continue
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+)
+
+func TestCollectCertificateStatus(t *testing.T) {
+	newCrt := func(issuerRef cmmeta.ObjectReference) *cmapi.Certificate {
+		return &cmapi.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", UID: "test-uid"},
+			Spec: cmapi.CertificateSpec{
+				SecretName: "test-tls",
+				DNSNames:   []string{"example.com"},
+				IssuerRef:  issuerRef,
+			},
+		}
+	}
+	newCR := func(crt *cmapi.Certificate, name, revision string) *cmapi.CertificateRequest {
+		return &cmapi.CertificateRequest{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       crt.Namespace,
+			Name:            name,
+			Annotations:     map[string]string{cmapi.CertificateRequestRevisionAnnotationKey: revision},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(crt, cmapi.SchemeGroupVersion.WithKind("Certificate"))},
+		}}
+	}
+	newLabelledCR := func(crt *cmapi.Certificate, name, revision string) *cmapi.CertificateRequest {
+		cr := newCR(crt, name, revision)
+		cr.Labels = map[string]string{
+			cmapi.CertificateRequestRevisionLabelKey: revision,
+			cmapi.CertificateNameKey:                 crt.Name,
+		}
+		return cr
+	}
+	readyConditions := []cmapi.IssuerCondition{{Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionTrue}}
+
+	clusterIssuerCrt := newCrt(cmmeta.ObjectReference{Name: "ca", Kind: cmapi.ClusterIssuerKind})
+	externalIssuerCrt := newCrt(cmmeta.ObjectReference{Name: "pca", Kind: "AWSPCAIssuer", Group: "awspca.cert-manager.io"})
+
+	tests := map[string]struct {
+		cmObjects []runtime.Object
+
+		expErr       bool
+		expDNSNames  []string
+		expCRName    string
+		expIssuer    *IssuerStatus
+		expIssuerErr bool
+	}{
+		"Certificate does not exist": {
+			expErr: true,
+		},
+		"Certificate with ClusterIssuer and CertificateRequest for the next revision": {
+			cmObjects: []runtime.Object{
+				clusterIssuerCrt,
+				newCR(clusterIssuerCrt, "test-1", "1"),
+				newCR(clusterIssuerCrt, "test-2", "2"),
+				&cmapi.ClusterIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: "ca"},
+					Status:     cmapi.IssuerStatus{Conditions: readyConditions},
+				},
+			},
+			expDNSNames: []string{"example.com"},
+			expCRName:   "test-1",
+			expIssuer:   &IssuerStatus{Name: "ca", Kind: "ClusterIssuer", Conditions: readyConditions},
+		},
+		"Certificate with labelled CertificateRequest for the next revision": {
+			cmObjects: []runtime.Object{
+				clusterIssuerCrt,
+				newLabelledCR(clusterIssuerCrt, "test-1", "1"),
+				newLabelledCR(clusterIssuerCrt, "test-2", "2"),
+				&cmapi.ClusterIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: "ca"},
+					Status:     cmapi.IssuerStatus{Conditions: readyConditions},
+				},
+			},
+			expDNSNames: []string{"example.com"},
+			expCRName:   "test-1",
+			expIssuer:   &IssuerStatus{Name: "ca", Kind: "ClusterIssuer", Conditions: readyConditions},
+		},
+		"Certificate with issuer of a third party API group without dynamic client": {
+			cmObjects:    []runtime.Object{externalIssuerCrt},
+			expDNSNames:  []string{"example.com"},
+			expIssuerErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clients := Clients{
+				Kube: kubefake.NewSimpleClientset(),
+				CM:   cmfake.NewSimpleClientset(test.cmObjects...),
+			}
+			status, err := CollectCertificateStatus(context.TODO(), clients, "default", "test")
+			if (err != nil) != test.expErr {
+				t.Fatalf("expected error: %t, got: %v", test.expErr, err)
+			}
+			if test.expErr {
+				return
+			}
+
+			if status.Name != "test" || status.Namespace != "default" {
+				t.Errorf("unexpected Certificate %s/%s", status.Namespace, status.Name)
+			}
+			if !reflect.DeepEqual(status.DNSNames, test.expDNSNames) {
+				t.Errorf("unexpected DNS names; expected: %v, got: %v", test.expDNSNames, status.DNSNames)
+			}
+			// The Secret does not exist in any of the test cases
+			if status.SecretStatus == nil || status.SecretStatus.Error == nil {
+				t.Errorf("expected an error in the status of the Secret, got: %+v", status.SecretStatus)
+			}
+
+			if test.expCRName != "" {
+				if status.CRStatus.Error != nil || status.CRStatus.Name != test.expCRName {
+					t.Errorf("expected CertificateRequest %q, got: %+v", test.expCRName, status.CRStatus)
+				}
+			} else if status.CRStatus.Error == nil {
+				t.Errorf("expected an error in the status of the CertificateRequest, got: %+v", status.CRStatus)
+			}
+
+			if test.expIssuerErr {
+				if status.IssuerStatus.Error == nil {
+					t.Errorf("expected an error in the status of the issuer, got: %+v", status.IssuerStatus)
+				}
+			} else if !reflect.DeepEqual(status.IssuerStatus, test.expIssuer) {
+				t.Errorf("unexpected issuer status; expected: %+v, got: %+v", test.expIssuer, status.IssuerStatus)
+			}
+		})
+	}
+}
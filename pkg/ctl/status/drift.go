@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// durationTolerance is how much the validity period of an issued certificate
+// may differ from the requested duration, as some issuers backdate the start
+// of the validity period.
+const durationTolerance = 5 * time.Minute
+
+// specMismatches compares the x509 certificate stored in secret with spec and
+// returns a human readable description of every difference found.
+func specMismatches(spec *cmapiv1alpha2.CertificateSpec, secret *v1.Secret, cert *x509.Certificate, now time.Time) []string {
+	var mismatches []string
+
+	// Names may move between the common name and the DNS names, as some
+	// issuers promote DNS names to be the common name or vice-versa
+	expectedNames := sets.NewString(pki.CanonicalDNSNames(spec.DNSNames)...)
+	if spec.CommonName != "" {
+		expectedNames.Insert(pki.CanonicalDNSName(spec.CommonName))
+	}
+	actualNames := sets.NewString(pki.CanonicalDNSNames(cert.DNSNames)...)
+	if cert.Subject.CommonName != "" {
+		actualNames.Insert(pki.CanonicalDNSName(cert.Subject.CommonName))
+	}
+	if missing := expectedNames.Difference(actualNames); missing.Len() > 0 {
+		mismatches = append(mismatches, fmt.Sprintf("DNS names missing from the certificate: %s", strings.Join(missing.List(), ", ")))
+	}
+	if extra := actualNames.Difference(expectedNames); extra.Len() > 0 {
+		mismatches = append(mismatches, fmt.Sprintf("DNS names not in the spec: %s", strings.Join(extra.List(), ", ")))
+	}
+
+	if m := keyMismatch(spec, cert); m != "" {
+		mismatches = append(mismatches, m)
+	}
+
+	if now.After(cert.NotAfter) {
+		mismatches = append(mismatches, fmt.Sprintf("Certificate expired at %s", cert.NotAfter.Format(time.RFC3339)))
+	}
+	if spec.Duration != nil {
+		actual := cert.NotAfter.Sub(cert.NotBefore)
+		diff := actual - spec.Duration.Duration
+		if diff > durationTolerance || diff < -durationTolerance {
+			mismatches = append(mismatches, fmt.Sprintf("Certificate is valid for %s, but spec.duration is %s", actual, spec.Duration.Duration))
+		}
+	}
+
+	name := secret.Annotations[cmapiv1alpha2.IssuerNameAnnotationKey]
+	kind := orDefault(secret.Annotations[cmapiv1alpha2.IssuerKindAnnotationKey], cmapiv1alpha2.IssuerKind)
+	specKind := orDefault(spec.IssuerRef.Kind, cmapiv1alpha2.IssuerKind)
+	if name != spec.IssuerRef.Name || kind != specKind {
+		mismatches = append(mismatches, fmt.Sprintf("Certificate was issued by %s %q, but spec.issuerRef is %s %q", kind, name, specKind, spec.IssuerRef.Name))
+	}
+
+	if caData := secret.Data[cmmeta.TLSCAKey]; len(caData) > 0 {
+		ca, err := pki.DecodeX509CertificateBytes(caData)
+		if err == nil && !bytes.Equal(cert.RawIssuer, ca.RawSubject) {
+			mismatches = append(mismatches, fmt.Sprintf("Certificate issuer %q differs from the subject of %s %q", cert.Issuer, cmmeta.TLSCAKey, ca.Subject))
+		}
+	}
+
+	return mismatches
+}
+
+// keyMismatch returns a description of the difference between the public
+// key of cert and the key algorithm and size requested in spec, if any.
+func keyMismatch(spec *cmapiv1alpha2.CertificateSpec, cert *x509.Certificate) string {
+	algorithm := spec.KeyAlgorithm
+	if algorithm == "" {
+		algorithm = cmapiv1alpha2.RSAKeyAlgorithm
+	}
+
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if algorithm != cmapiv1alpha2.RSAKeyAlgorithm {
+			return fmt.Sprintf("Key algorithm is %s, but spec.keyAlgorithm is %s", cmapiv1alpha2.RSAKeyAlgorithm, algorithm)
+		}
+		size := spec.KeySize
+		if size == 0 {
+			size = pki.MinRSAKeySize
+		}
+		if pub.N.BitLen() != size {
+			return fmt.Sprintf("Key size is %d, but spec.keySize is %d", pub.N.BitLen(), size)
+		}
+	case *ecdsa.PublicKey:
+		if algorithm != cmapiv1alpha2.ECDSAKeyAlgorithm {
+			return fmt.Sprintf("Key algorithm is %s, but spec.keyAlgorithm is %s", cmapiv1alpha2.ECDSAKeyAlgorithm, algorithm)
+		}
+		size := spec.KeySize
+		if size == 0 {
+			size = pki.ECCurve256
+		}
+		if pub.Curve.Params().BitSize != size {
+			return fmt.Sprintf("Key size is %d, but spec.keySize is %d", pub.Curve.Params().BitSize, size)
+		}
+	default:
+		return fmt.Sprintf("Key algorithm %s is not supported, spec.keyAlgorithm is %s", cert.PublicKeyAlgorithm, algorithm)
+	}
+	return ""
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestSpecMismatches(t *testing.T) {
+	pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notBefore := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com", "www.example.com"},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.Add(cmapi.DefaultCertificateDuration),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pk.Public(), pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			cmapi.IssuerNameAnnotationKey: "ca-issuer",
+			cmapi.IssuerKindAnnotationKey: "Issuer",
+		}},
+	}
+	upToDate := cmapi.CertificateSpec{
+		CommonName:   "example.com",
+		DNSNames:     []string{"www.example.com"},
+		KeyAlgorithm: cmapi.ECDSAKeyAlgorithm,
+		Duration:     &metav1.Duration{Duration: cmapi.DefaultCertificateDuration},
+		IssuerRef:    cmmeta.ObjectReference{Name: "ca-issuer"},
+	}
+
+	tests := map[string]struct {
+		mutateSpec    func(*cmapi.CertificateSpec)
+		now           time.Time
+		expMismatches []string
+	}{
+		"certificate matching the spec has no mismatches": {
+			now: notBefore.Add(time.Hour),
+		},
+		"names, key, duration and issuer not matching the spec are all listed": {
+			mutateSpec: func(spec *cmapi.CertificateSpec) {
+				spec.DNSNames = []string{"api.example.com"}
+				spec.KeyAlgorithm = cmapi.RSAKeyAlgorithm
+				spec.Duration = &metav1.Duration{Duration: time.Hour}
+				spec.IssuerRef = cmmeta.ObjectReference{Name: "vault", Kind: "ClusterIssuer"}
+			},
+			now: notBefore.Add(time.Hour),
+			expMismatches: []string{
+				"DNS names missing from the certificate: api.example.com",
+				"DNS names not in the spec: www.example.com",
+				"Key algorithm is ecdsa, but spec.keyAlgorithm is rsa",
+				"Certificate is valid for 2160h0m0s, but spec.duration is 1h0m0s",
+				`Certificate was issued by Issuer "ca-issuer", but spec.issuerRef is ClusterIssuer "vault"`,
+			},
+		},
+		"wrong key size": {
+			mutateSpec: func(spec *cmapi.CertificateSpec) {
+				spec.KeySize = 384
+			},
+			now:           notBefore.Add(time.Hour),
+			expMismatches: []string{"Key size is 256, but spec.keySize is 384"},
+		},
+		"expired certificate": {
+			now:           notBefore.Add(cmapi.DefaultCertificateDuration + time.Hour),
+			expMismatches: []string{"Certificate expired at 2020-03-31T00:00:00Z"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			spec := upToDate.DeepCopy()
+			if test.mutateSpec != nil {
+				test.mutateSpec(spec)
+			}
+			mismatches := specMismatches(spec, secret, cert, test.now)
+			if !reflect.DeepEqual(mismatches, test.expMismatches) {
+				t.Errorf("unexpected mismatches; expected: %q, actual: %q", test.expMismatches, mismatches)
+			}
+		})
+	}
+}
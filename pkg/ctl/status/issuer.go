@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+// IssuerRenderer renders details about the status of an issuer of a third
+// party API group. They are printed after the conditions of the issuer.
+type IssuerRenderer interface {
+	Render(issuer *unstructured.Unstructured) (string, error)
+}
+
+// IssuerRendererFunc is a function that implements IssuerRenderer.
+type IssuerRendererFunc func(issuer *unstructured.Unstructured) (string, error)
+
+// Render calls f(issuer).
+func (f IssuerRendererFunc) Render(issuer *unstructured.Unstructured) (string, error) {
+	return f(issuer)
+}
+
+var (
+	issuerRenderers = make(map[string]IssuerRenderer)
+)
+
+// RegisterIssuerRenderer registers the renderer used for the issuers of the
+// given API group, like 'awspca.cert-manager.io'.
+func RegisterIssuerRenderer(group string, r IssuerRenderer) {
+	issuerRenderers[group] = r
+}
+
+// getExternalIssuer gets the issuer of a third party API group referenced by
+// ref. The resource the kind of the issuer belongs to, and whether it is
+// namespaced, are looked up using mapper.
+func getExternalIssuer(ctx context.Context, dynamicClient dynamic.Interface, mapper meta.RESTMapper, namespace string, ref cmmeta.ObjectReference) (*unstructured.Unstructured, error) {
+	mapping, err := mapper.RESTMapping(schema.GroupKind{Group: ref.Group, Kind: ref.Kind})
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return dynamicClient.Resource(mapping.Resource).Namespace(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	}
+	return dynamicClient.Resource(mapping.Resource).Get(ctx, ref.Name, metav1.GetOptions{})
+}
+
+func (status *CertificateStatus) withExternalIssuer(issuer *unstructured.Unstructured, err error) *CertificateStatus {
+	if err != nil {
+		status.IssuerStatus = &IssuerStatus{Error: err}
+		return status
+	}
+	if issuer == nil {
+		return status
+	}
+
+	gvk := issuer.GroupVersionKind()
+	issuerStatus := &IssuerStatus{Name: issuer.GetName(), Kind: gvk.Kind + "." + gvk.Group}
+
+	// Third party issuers are expected to use the same conditions as the
+	// cert-manager.io issuers
+	var issuerStatusField struct {
+		Conditions []cmapi.IssuerCondition `json:"conditions"`
+	}
+	if statusField, found, _ := unstructured.NestedMap(issuer.Object, "status"); found {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(statusField, &issuerStatusField); err != nil {
+			status.IssuerStatus = &IssuerStatus{Error: fmt.Errorf("error when reading the conditions of %s %q: %v\n", gvk.Kind, issuer.GetName(), err)}
+			return status
+		}
+	}
+	issuerStatus.Conditions = issuerStatusField.Conditions
+
+	if r, ok := issuerRenderers[gvk.Group]; ok {
+		details, err := r.Render(issuer)
+		if err != nil {
+			details = fmt.Sprintf("error when rendering details: %v", err)
+		}
+		issuerStatus.Details = details
+	}
+
+	status.IssuerStatus = issuerStatus
+	return status
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestExternalIssuerStatus(t *testing.T) {
+	const group = "awspca.cert-manager.io"
+	issuerGVK := schema.GroupVersionKind{Group: group, Version: "v1beta1", Kind: "AWSPCAIssuer"}
+	clusterIssuerGVK := schema.GroupVersionKind{Group: group, Version: "v1beta1", Kind: "AWSPCAClusterIssuer"}
+
+	newIssuer := func(gvk schema.GroupVersionKind, namespace string) *unstructured.Unstructured {
+		issuer := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"arn": "arn:aws:acm-pca:eu-west-1:000000000000:certificate-authority/ca"},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "True", "reason": "Verified", "message": "Issuer verified"},
+				},
+			},
+		}}
+		issuer.SetGroupVersionKind(gvk)
+		issuer.SetName("pca")
+		issuer.SetNamespace(namespace)
+		return issuer
+	}
+
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{issuerGVK.GroupVersion()})
+	mapper.Add(issuerGVK, meta.RESTScopeNamespace)
+	mapper.Add(clusterIssuerGVK, meta.RESTScopeRoot)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		newIssuer(issuerGVK, "default"), newIssuer(clusterIssuerGVK, ""))
+
+	RegisterIssuerRenderer(group, IssuerRendererFunc(func(issuer *unstructured.Unstructured) (string, error) {
+		arn, _, err := unstructured.NestedString(issuer.Object, "spec", "arn")
+		return "ARN: " + arn, err
+	}))
+	defer delete(issuerRenderers, group)
+
+	expConditions := []cmapi.IssuerCondition{{Type: cmapi.IssuerConditionReady, Status: cmmeta.ConditionTrue, Reason: "Verified", Message: "Issuer verified"}}
+	const expDetails = "ARN: arn:aws:acm-pca:eu-west-1:000000000000:certificate-authority/ca"
+
+	tests := map[string]struct {
+		kind    string
+		expKind string
+		expErr  bool
+	}{
+		"namespaced issuer": {
+			kind:    "AWSPCAIssuer",
+			expKind: "AWSPCAIssuer." + group,
+		},
+		"cluster scoped issuer": {
+			kind:    "AWSPCAClusterIssuer",
+			expKind: "AWSPCAClusterIssuer." + group,
+		},
+		"unknown kind": {
+			kind:   "Unknown",
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ref := cmmeta.ObjectReference{Name: "pca", Kind: test.kind, Group: group}
+			issuer, err := getExternalIssuer(context.TODO(), dynamicClient, mapper, "default", ref)
+			if (err != nil) != test.expErr {
+				t.Fatalf("expected error: %t, got: %v", test.expErr, err)
+			}
+			if test.expErr {
+				return
+			}
+
+			status := (&CertificateStatus{}).withExternalIssuer(issuer, nil).IssuerStatus
+			if status.Error != nil {
+				t.Fatalf("unexpected error: %v", status.Error)
+			}
+			if status.Name != "pca" || status.Kind != test.expKind {
+				t.Errorf("expected issuer %s %q, got: %s %q", test.expKind, "pca", status.Kind, status.Name)
+			}
+			if !reflect.DeepEqual(status.Conditions, expConditions) {
+				t.Errorf("unexpected conditions; expected: %+v, got: %+v", expConditions, status.Conditions)
+			}
+			if status.Details != expDetails {
+				t.Errorf("unexpected details; expected: %q, got: %q", expDetails, status.Details)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+)
+
+// The types below are the JSON representation of the status of a
+// Certificate, served by the status API of the controller. Errors are
+// represented by their message, and x509 enums by their name.
+
+type certificateStatusJSON struct {
+	Name              string                               `json:"name"`
+	Namespace         string                               `json:"namespace"`
+	CreationTimestamp metav1.Time                          `json:"creationTimestamp"`
+	Conditions        []cmapiv1alpha2.CertificateCondition `json:"conditions,omitempty"`
+	DNSNames          []string                             `json:"dnsNames,omitempty"`
+	Events            []eventJSON                          `json:"events,omitempty"`
+	NotBefore         *metav1.Time                         `json:"notBefore,omitempty"`
+	NotAfter          *metav1.Time                         `json:"notAfter,omitempty"`
+	RenewalTime       *metav1.Time                         `json:"renewalTime,omitempty"`
+
+	Issuer             *issuerStatusJSON     `json:"issuer,omitempty"`
+	Secret             *secretStatusJSON     `json:"secret,omitempty"`
+	CertificateRequest *crStatusJSON         `json:"certificateRequest,omitempty"`
+	Challenges         []challengeStatusJSON `json:"challenges,omitempty"`
+	ChallengesError    string                `json:"challengesError,omitempty"`
+}
+
+type eventJSON struct {
+	Type          string      `json:"type"`
+	Reason        string      `json:"reason"`
+	Message       string      `json:"message"`
+	Count         int32       `json:"count,omitempty"`
+	LastTimestamp metav1.Time `json:"lastTimestamp,omitempty"`
+}
+
+type issuerStatusJSON struct {
+	Error      string                          `json:"error,omitempty"`
+	Name       string                          `json:"name,omitempty"`
+	Kind       string                          `json:"kind,omitempty"`
+	Conditions []cmapiv1alpha2.IssuerCondition `json:"conditions,omitempty"`
+	Details    string                          `json:"details,omitempty"`
+}
+
+type secretStatusJSON struct {
+	Error              string   `json:"error,omitempty"`
+	Name               string   `json:"name,omitempty"`
+	IssuerCountry      []string `json:"issuerCountry,omitempty"`
+	IssuerOrganisation []string `json:"issuerOrganisation,omitempty"`
+	IssuerCommonName   string   `json:"issuerCommonName,omitempty"`
+	KeyUsages          []string `json:"keyUsages,omitempty"`
+	ExtKeyUsages       []string `json:"extKeyUsages,omitempty"`
+	PublicKeyAlgorithm string   `json:"publicKeyAlgorithm,omitempty"`
+	SignatureAlgorithm string   `json:"signatureAlgorithm,omitempty"`
+	SubjectKeyID       string   `json:"subjectKeyId,omitempty"`
+	AuthorityKeyID     string   `json:"authorityKeyId,omitempty"`
+	SerialNumber       string   `json:"serialNumber,omitempty"`
+	Mismatches         []string `json:"mismatches,omitempty"`
+}
+
+type crStatusJSON struct {
+	Error                   string                                      `json:"error,omitempty"`
+	Name                    string                                      `json:"name,omitempty"`
+	Namespace               string                                      `json:"namespace,omitempty"`
+	Conditions              []cmapiv1alpha2.CertificateRequestCondition `json:"conditions,omitempty"`
+	Events                  []eventJSON                                 `json:"events,omitempty"`
+	AwaitingExternalSigning bool                                        `json:"awaitingExternalSigning,omitempty"`
+}
+
+type challengeStatusJSON struct {
+	Name    string                     `json:"name"`
+	Type    string                     `json:"type"`
+	DNSName string                     `json:"dnsName"`
+	State   string                     `json:"state,omitempty"`
+	Reason  string                     `json:"reason,omitempty"`
+	Solvers []solverResourceStatusJSON `json:"solvers,omitempty"`
+}
+
+type solverResourceStatusJSON struct {
+	Kind     string      `json:"kind"`
+	Name     string      `json:"name"`
+	Phase    string      `json:"phase,omitempty"`
+	Problems []string    `json:"problems,omitempty"`
+	Events   []eventJSON `json:"events,omitempty"`
+	Logs     string      `json:"logs,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (status *CertificateStatus) MarshalJSON() ([]byte, error) {
+	out := certificateStatusJSON{
+		Name:              status.Name,
+		Namespace:         status.Namespace,
+		CreationTimestamp: status.CreationTime,
+		Conditions:        status.Conditions,
+		DNSNames:          status.DNSNames,
+		Events:            eventsJSON(status.Events),
+		NotBefore:         status.NotBefore,
+		NotAfter:          status.NotAfter,
+		RenewalTime:       status.RenewalTime,
+		ChallengesError:   errorJSON(status.ChallengesError),
+	}
+
+	if s := status.IssuerStatus; s != nil {
+		out.Issuer = &issuerStatusJSON{
+			Error:      errorJSON(s.Error),
+			Name:       s.Name,
+			Kind:       s.Kind,
+			Conditions: s.Conditions,
+			Details:    s.Details,
+		}
+	}
+
+	if s := status.SecretStatus; s != nil {
+		out.Secret = &secretStatusJSON{
+			Error:              errorJSON(s.Error),
+			Name:               s.Name,
+			IssuerCountry:      s.IssuerCountry,
+			IssuerOrganisation: s.IssuerOrganisation,
+			IssuerCommonName:   s.IssuerCommonName,
+			Mismatches:         s.Mismatches,
+		}
+		if s.Error == nil {
+			out.Secret.KeyUsages = splitList(keyUsageToString(s.KeyUsage))
+			out.Secret.ExtKeyUsages = extKeyUsagesJSON(s.ExtKeyUsage)
+			out.Secret.PublicKeyAlgorithm = s.PublicKeyAlgorithm.String()
+			out.Secret.SignatureAlgorithm = s.SignatureAlgorithm.String()
+			out.Secret.SubjectKeyID = hex.EncodeToString(s.SubjectKeyId)
+			out.Secret.AuthorityKeyID = hex.EncodeToString(s.AuthorityKeyId)
+			if s.SerialNumber != nil {
+				out.Secret.SerialNumber = s.SerialNumber.Text(16)
+			}
+		}
+	}
+
+	if s := status.CRStatus; s != nil {
+		out.CertificateRequest = &crStatusJSON{
+			Error:                   errorJSON(s.Error),
+			Name:                    s.Name,
+			Namespace:               s.Namespace,
+			Conditions:              s.Conditions,
+			Events:                  eventsJSON(s.Events),
+			AwaitingExternalSigning: s.AwaitingExternalSigning,
+		}
+	}
+
+	for _, ch := range status.Challenges {
+		chJSON := challengeStatusJSON{
+			Name:    ch.Name,
+			Type:    ch.Type,
+			DNSName: ch.DNSName,
+			State:   ch.State,
+			Reason:  ch.Reason,
+		}
+		for _, solver := range ch.Solvers {
+			chJSON.Solvers = append(chJSON.Solvers, solverResourceStatusJSON{
+				Kind:     solver.Kind,
+				Name:     solver.Name,
+				Phase:    solver.Phase,
+				Problems: solver.Problems,
+				Events:   eventsJSON(solver.Events),
+				Logs:     solver.Logs,
+			})
+		}
+		out.Challenges = append(out.Challenges, chJSON)
+	}
+
+	return json.Marshal(out)
+}
+
+func eventsJSON(events *corev1.EventList) []eventJSON {
+	if events == nil {
+		return nil
+	}
+	var out []eventJSON
+	for _, e := range events.Items {
+		out = append(out, eventJSON{
+			Type:          e.Type,
+			Reason:        e.Reason,
+			Message:       e.Message,
+			Count:         e.Count,
+			LastTimestamp: e.LastTimestamp,
+		})
+	}
+	return out
+}
+
+// errorJSON returns the message of err without the trailing newline some of
+// the errors are created with for printing.
+func errorJSON(err error) string {
+	if err == nil {
+		return ""
+	}
+	return strings.TrimSpace(err.Error())
+}
+
+func extKeyUsagesJSON(usages []x509.ExtKeyUsage) []string {
+	var out []string
+	for _, usage := range usages {
+		if usage < 0 || int(usage) >= len(extKeyUsageStringValues) {
+			out = append(out, "Unknown")
+			continue
+		}
+		out = append(out, extKeyUsageStringValues[usage])
+	}
+	return out
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ", ")
+}
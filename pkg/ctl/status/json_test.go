@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestCertificateStatusMarshalJSON(t *testing.T) {
+	created := metav1.Unix(1600000000, 0).Rfc3339Copy()
+
+	tests := map[string]struct {
+		status *CertificateStatus
+		exp    string
+	}{
+		"errors should be represented by their message": {
+			status: &CertificateStatus{
+				Name:            "test",
+				Namespace:       "ns",
+				CreationTime:    created,
+				SecretStatus:    &SecretStatus{Error: errors.New("error when finding Secret \"test\": not found\n")},
+				CRStatus:        &CRStatus{Error: errors.New("No CertificateRequest found for this Certificate\n")},
+				IssuerStatus:    &IssuerStatus{Error: errors.New("issuer not found")},
+				ChallengesError: errors.New("forbidden"),
+			},
+			exp: `{"name":"test","namespace":"ns","creationTimestamp":"2020-09-13T12:26:40Z",` +
+				`"issuer":{"error":"issuer not found"},` +
+				`"secret":{"error":"error when finding Secret \"test\": not found"},` +
+				`"certificateRequest":{"error":"No CertificateRequest found for this Certificate"},` +
+				`"challengesError":"forbidden"}`,
+		},
+		"x509 details, events and challenges should be encoded": {
+			status: &CertificateStatus{
+				Name:         "test",
+				Namespace:    "ns",
+				CreationTime: created,
+				Conditions: []cmapi.CertificateCondition{
+					{Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionFalse, Reason: "Issuing"},
+				},
+				DNSNames: []string{"example.com"},
+				Events: &corev1.EventList{Items: []corev1.Event{
+					{Type: "Normal", Reason: "Issuing", Message: "Issuing certificate", Count: 1, LastTimestamp: created},
+				}},
+				SecretStatus: &SecretStatus{
+					Name:               "test-tls",
+					IssuerCommonName:   "ca",
+					KeyUsage:           x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+					ExtKeyUsage:        []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+					PublicKeyAlgorithm: x509.RSA,
+					SignatureAlgorithm: x509.SHA256WithRSA,
+					SubjectKeyId:       []byte{0x01, 0x02},
+					SerialNumber:       big.NewInt(255),
+				},
+				Challenges: []*ChallengeStatus{
+					{Name: "test-1", Type: "http-01", DNSName: "example.com", State: "pending", Solvers: []*SolverResourceStatus{
+						{Kind: "Pod", Name: "solver", Phase: "Pending", Problems: []string{"image cannot be pulled"}},
+					}},
+				},
+			},
+			exp: `{"name":"test","namespace":"ns","creationTimestamp":"2020-09-13T12:26:40Z",` +
+				`"conditions":[{"type":"Ready","status":"False","reason":"Issuing"}],` +
+				`"dnsNames":["example.com"],` +
+				`"events":[{"type":"Normal","reason":"Issuing","message":"Issuing certificate","count":1,"lastTimestamp":"2020-09-13T12:26:40Z"}],` +
+				`"secret":{"name":"test-tls","issuerCommonName":"ca","keyUsages":["Digital Signature","Key Encipherment"],` +
+				`"extKeyUsages":["Server Authentication"],"publicKeyAlgorithm":"RSA","signatureAlgorithm":"SHA256-RSA",` +
+				`"subjectKeyId":"0102","serialNumber":"ff"},` +
+				`"challenges":[{"name":"test-1","type":"http-01","dnsName":"example.com","state":"pending",` +
+				`"solvers":[{"kind":"Pod","name":"solver","phase":"Pending","problems":["image cannot be pulled"]}]}]}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := json.Marshal(test.status)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != test.exp {
+				t.Errorf("unexpected JSON\nexp=%s\ngot=%s", test.exp, string(data))
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/api/core/v1"
+
+	"github.com/jetstack/cert-manager/pkg/ctl/output"
+)
+
+// Markdown returns the status of the Certificate as GitHub flavored
+// Markdown, e.g. to be pasted into an issue or a support ticket. Every
+// related resource is rendered in a section of its own, with conditions and
+// events as tables. Unlike String, the output is never wrapped or colored,
+// as it is reflowed when the Markdown is rendered.
+func (status *CertificateStatus) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### Certificate `%s/%s`\n\n", status.Namespace, status.Name)
+	writeMarkdownField(&b, "Created at", formatTimeString(&status.CreationTime))
+	writeMarkdownField(&b, "Not Before", formatTimeString(status.NotBefore))
+	writeMarkdownField(&b, "Not After", formatTimeString(status.NotAfter))
+	writeMarkdownField(&b, "Renewal Time", formatTimeString(status.RenewalTime))
+
+	conditions := newConditionsTable("")
+	for _, con := range status.Conditions {
+		conditions.addCondition(string(con.Type), string(con.Status), con.Reason, con.Message)
+	}
+	b.WriteString("\n#### Conditions\n\n")
+	b.WriteString(conditions.Markdown())
+
+	b.WriteString("\n#### DNS Names\n\n")
+	writeMarkdownList(&b, status.DNSNames)
+
+	b.WriteString("\n#### Events\n\n")
+	writeMarkdownEvents(&b, status.Events)
+
+	if status.IssuerStatus != nil {
+		b.WriteString("\n")
+		b.WriteString(status.IssuerStatus.Markdown())
+	}
+	if status.SecretStatus != nil {
+		b.WriteString("\n")
+		b.WriteString(status.SecretStatus.Markdown())
+	}
+	if status.CRStatus != nil {
+		b.WriteString("\n")
+		b.WriteString(status.CRStatus.Markdown())
+	}
+
+	switch {
+	case status.ChallengesError != nil:
+		b.WriteString("\n#### Challenges\n\n")
+		writeMarkdownError(&b, status.ChallengesError)
+	case len(status.Challenges) > 0:
+		b.WriteString("\n#### Challenges\n\n```\n")
+		for _, ch := range status.Challenges {
+			b.WriteString(ch.String())
+		}
+		b.WriteString("```\n")
+	}
+
+	return b.String()
+}
+
+// Markdown returns the status of the Issuer/ClusterIssuer as a Markdown
+// section
+func (issuerStatus *IssuerStatus) Markdown() string {
+	var b strings.Builder
+	b.WriteString("#### Issuer\n\n")
+	if issuerStatus.Error != nil {
+		writeMarkdownError(&b, issuerStatus.Error)
+		return b.String()
+	}
+
+	writeMarkdownField(&b, "Name", issuerStatus.Name)
+	writeMarkdownField(&b, "Kind", issuerStatus.Kind)
+	b.WriteString("\n")
+	conditions := newConditionsTable("")
+	for _, con := range issuerStatus.Conditions {
+		conditions.addCondition(string(con.Type), string(con.Status), con.Reason, con.Message)
+	}
+	b.WriteString(conditions.Markdown())
+	if issuerStatus.Details != "" {
+		writeMarkdownCodeBlock(&b, issuerStatus.Details)
+	}
+	if issuerStatus.ACMEAccount != nil {
+		writeMarkdownCodeBlock(&b, issuerStatus.ACMEAccount.String())
+	}
+	return b.String()
+}
+
+// Markdown returns the status of the Secret as a Markdown section
+func (secretStatus *SecretStatus) Markdown() string {
+	var b strings.Builder
+	b.WriteString("#### Secret\n\n")
+	if secretStatus.Error != nil {
+		writeMarkdownError(&b, secretStatus.Error)
+		return b.String()
+	}
+
+	writeMarkdownField(&b, "Name", secretStatus.Name)
+	for _, line := range strings.Split(strings.TrimRight(secretStatus.describeX509(""), "\n"), "\n") {
+		parts := strings.SplitN(line, ": ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		writeMarkdownField(&b, parts[0], parts[1])
+	}
+
+	if len(secretStatus.Mismatches) > 0 {
+		b.WriteString("\nNot up to date:\n\n")
+		for _, m := range secretStatus.Mismatches {
+			fmt.Fprintf(&b, "- %s\n", output.MarkdownEscape(m))
+		}
+	}
+	return b.String()
+}
+
+// Markdown returns the status of the CertificateRequest as a Markdown
+// section
+func (crStatus *CRStatus) Markdown() string {
+	var b strings.Builder
+	b.WriteString("#### CertificateRequest\n\n")
+	if crStatus.Error != nil {
+		writeMarkdownError(&b, crStatus.Error)
+		return b.String()
+	}
+
+	writeMarkdownField(&b, "Name", crStatus.Name)
+	writeMarkdownField(&b, "Namespace", crStatus.Namespace)
+	b.WriteString("\n")
+	conditions := newConditionsTable("")
+	for _, con := range crStatus.Conditions {
+		conditions.addCondition(string(con.Type), string(con.Status), con.Reason, con.Message)
+	}
+	b.WriteString(conditions.Markdown())
+	if crStatus.AwaitingExternalSigning {
+		fmt.Fprintf(&b, "\n%s\n", output.MarkdownEscape(awaitingExternalSigningMessage))
+	}
+
+	b.WriteString("\nEvents:\n\n")
+	writeMarkdownEvents(&b, crStatus.Events)
+	return b.String()
+}
+
+// Markdown returns the conditions as a Markdown table, or a note that no
+// conditions are set if the table is empty.
+func (t conditionsTable) Markdown() string {
+	if t.Len() == 0 {
+		return "No Conditions set\n"
+	}
+	return t.Table.Markdown()
+}
+
+func writeMarkdownField(b *strings.Builder, name, value string) {
+	fmt.Fprintf(b, "- **%s:** %s\n", name, output.MarkdownEscape(value))
+}
+
+// writeMarkdownList writes items, e.g. DNS names, as a list of code spans,
+// so that they are neither escaped nor turned into links
+func writeMarkdownList(b *strings.Builder, items []string) {
+	if len(items) == 0 {
+		b.WriteString("None\n")
+		return
+	}
+	for _, item := range items {
+		fmt.Fprintf(b, "- `%s`\n", strings.Replace(item, "`", "'", -1))
+	}
+}
+
+func writeMarkdownEvents(b *strings.Builder, events *v1.EventList) {
+	if events == nil || len(events.Items) == 0 {
+		b.WriteString("None\n")
+		return
+	}
+	b.WriteString(output.EventsTable(events).Markdown())
+}
+
+func writeMarkdownError(b *strings.Builder, err error) {
+	fmt.Fprintf(b, "**Error:** %s\n", output.MarkdownEscape(strings.TrimSpace(err.Error())))
+}
+
+// writeMarkdownCodeBlock writes s as a fenced code block, which keeps the
+// indentation of multi-line details
+func writeMarkdownCodeBlock(b *strings.Builder, s string) {
+	fmt.Fprintf(b, "\n```\n%s\n```\n", strings.TrimRight(s, "\n"))
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestCertificateStatusMarkdown(t *testing.T) {
+	created := metav1.NewTime(time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC))
+	status := &CertificateStatus{
+		Name:         "my-crt",
+		Namespace:    "default",
+		CreationTime: created,
+		Conditions: []cmapi.CertificateCondition{{
+			Type:    cmapi.CertificateConditionReady,
+			Status:  cmmeta.ConditionFalse,
+			Reason:  "DoesNotExist",
+			Message: "Issuing certificate as Secret does not exist",
+		}},
+		DNSNames:     []string{"example.com", "*.example.com"},
+		IssuerStatus: &IssuerStatus{Error: errors.New(`issuer "ca-issuer" not found`)},
+		CRStatus: &CRStatus{
+			Name:                    "my-crt-1234",
+			Namespace:               "default",
+			AwaitingExternalSigning: true,
+		},
+	}
+
+	exp := "### Certificate `default/my-crt`\n\n" +
+		"- **Created at:** 2020-07-01T12:00:00Z\n" +
+		"- **Not Before:** &lt;none&gt;\n" +
+		"- **Not After:** &lt;none&gt;\n" +
+		"- **Renewal Time:** &lt;none&gt;\n" +
+		"\n#### Conditions\n\n" +
+		"| Type | Status | Reason | Message |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| Ready | False | DoesNotExist | Issuing certificate as Secret does not exist |\n" +
+		"\n#### DNS Names\n\n" +
+		"- `example.com`\n" +
+		"- `*.example.com`\n" +
+		"\n#### Events\n\n" +
+		"None\n" +
+		"\n#### Issuer\n\n" +
+		"**Error:** issuer \"ca-issuer\" not found\n" +
+		"\n#### CertificateRequest\n\n" +
+		"- **Name:** my-crt-1234\n" +
+		"- **Namespace:** default\n" +
+		"\nNo Conditions set\n" +
+		"\nAwaiting External Signing: export the CSR with 'kubectl cert-manager external-signing export-csr' and import the signed certificate with 'kubectl cert-manager external-signing import'\n" +
+		"\nEvents:\n\n" +
+		"None\n"
+	if actual := status.Markdown(); actual != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, actual)
+	}
+}
@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package certificate
+package status
 
 import (
 	"bytes"
@@ -23,16 +23,22 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"time"
 
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kubectl/pkg/describe"
 
-	"github.com/jetstack/cert-manager/cmd/ctl/pkg/status/util"
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/ctl/output"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
+// awaitingExternalSigningMessage is printed for CertificateRequests that are
+// signed outside of cert-manager and have not been signed yet
+const awaitingExternalSigningMessage = "Awaiting External Signing: export the CSR with 'kubectl cert-manager external-signing export-csr' and import the signed certificate with 'kubectl cert-manager external-signing import'"
+
 type CertificateStatus struct {
 	// Name of the Certificate resource
 	Name string
@@ -58,6 +64,16 @@ type CertificateStatus struct {
 	SecretStatus *SecretStatus
 
 	CRStatus *CRStatus
+
+	// Challenges are the pending ACME Challenges of the CertificateRequest,
+	// if it is not Ready yet. If ChallengesError is not nil, there was a
+	// problem getting them.
+	Challenges      []*ChallengeStatus
+	ChallengesError error
+
+	// spec of the Certificate resource, which the certificate stored in the
+	// Secret is compared against
+	spec *cmapiv1alpha2.CertificateSpec
 }
 
 type IssuerStatus struct {
@@ -66,10 +82,17 @@ type IssuerStatus struct {
 	Error error
 	// Name of the Issuer/ClusterIssuer resource
 	Name string
-	// Kind of the resource, can be Issuer or ClusterIssuer
+	// Kind of the resource, can be Issuer or ClusterIssuer, or the kind and
+	// group of an issuer of a third party API group
 	Kind string
 	// Conditions of Issuer/ClusterIssuer resource
 	Conditions []cmapiv1alpha2.IssuerCondition
+	// Details about issuers of third party API groups, rendered by the
+	// IssuerRenderer registered for the group
+	Details string
+	// ACMEAccount is the status of the account of ACME issuers. It is only
+	// collected when getting the status of the issuer itself.
+	ACMEAccount *ACMEAccountStatus
 }
 
 type SecretStatus struct {
@@ -98,6 +121,9 @@ type SecretStatus struct {
 	AuthorityKeyId []byte
 	// Serial Number of the x509 certificate in the Secret
 	SerialNumber *big.Int
+	// Mismatches between the x509 certificate in the Secret and the spec of
+	// the Certificate resource, which will cause it to be re-issued
+	Mismatches []string
 }
 
 type CRStatus struct {
@@ -112,6 +138,10 @@ type CRStatus struct {
 	Conditions []cmapiv1alpha2.CertificateRequestCondition
 	// Events of CertificateRequest resource
 	Events *v1.EventList
+	// AwaitingExternalSigning is true if the CertificateRequest is signed
+	// outside of cert-manager and the signed certificate has not been
+	// imported yet
+	AwaitingExternalSigning bool
 }
 
 func newCertificateStatusFromCert(crt *cmapiv1alpha2.Certificate) *CertificateStatus {
@@ -121,7 +151,8 @@ func newCertificateStatusFromCert(crt *cmapiv1alpha2.Certificate) *CertificateSt
 	return &CertificateStatus{
 		Name: crt.Name, Namespace: crt.Namespace, CreationTime: crt.CreationTimestamp,
 		Conditions: crt.Status.Conditions, DNSNames: crt.Spec.DNSNames,
-		NotBefore: crt.Status.NotBefore, NotAfter: crt.Status.NotAfter, RenewalTime: crt.Status.RenewalTime}
+		NotBefore: crt.Status.NotBefore, NotAfter: crt.Status.NotAfter, RenewalTime: crt.Status.RenewalTime,
+		spec: &crt.Spec}
 }
 
 func (status *CertificateStatus) withEvents(events *v1.EventList) *CertificateStatus {
@@ -174,14 +205,23 @@ func (status *CertificateStatus) withSecret(secret *v1.Secret, err error) *Certi
 		return status
 	}
 
-	status.SecretStatus = &SecretStatus{Error: nil, Name: secret.Name, IssuerCountry: x509Cert.Issuer.Country,
+	status.SecretStatus = newSecretStatus(secret.Name, x509Cert)
+	if status.spec != nil {
+		status.SecretStatus.Mismatches = specMismatches(status.spec, secret, x509Cert, time.Now())
+	}
+	return status
+}
+
+// newSecretStatus returns the SecretStatus of the x509 certificate stored in
+// the Secret with the given name
+func newSecretStatus(name string, x509Cert *x509.Certificate) *SecretStatus {
+	return &SecretStatus{Error: nil, Name: name, IssuerCountry: x509Cert.Issuer.Country,
 		IssuerOrganisation: x509Cert.Issuer.Organization,
 		IssuerCommonName:   x509Cert.Issuer.CommonName, KeyUsage: x509Cert.KeyUsage,
 		ExtKeyUsage: x509Cert.ExtKeyUsage, PublicKeyAlgorithm: x509Cert.PublicKeyAlgorithm,
 		SignatureAlgorithm: x509Cert.SignatureAlgorithm,
 		SubjectKeyId:       x509Cert.SubjectKeyId, AuthorityKeyId: x509Cert.AuthorityKeyId,
 		SerialNumber: x509Cert.SerialNumber}
-	return status
 }
 
 func (status *CertificateStatus) withCR(req *cmapiv1alpha2.CertificateRequest, events *v1.EventList, err error) *CertificateStatus {
@@ -193,7 +233,14 @@ func (status *CertificateStatus) withCR(req *cmapiv1alpha2.CertificateRequest, e
 		return status
 	}
 	status.Events = events
-	status.CRStatus = &CRStatus{Name: req.Name, Namespace: req.Namespace, Conditions: req.Status.Conditions}
+	status.CRStatus = &CRStatus{Name: req.Name, Namespace: req.Namespace, Conditions: req.Status.Conditions,
+		AwaitingExternalSigning: apiutil.IsExternalSigningRequest(req) && len(req.Status.Certificate) == 0}
+	return status
+}
+
+func (status *CertificateStatus) withChallenges(challenges []*ChallengeStatus, err error) *CertificateStatus {
+	status.Challenges = challenges
+	status.ChallengesError = err
 	return status
 }
 
@@ -203,26 +250,17 @@ func (status *CertificateStatus) String() string {
 	output += fmt.Sprintf("Namespace: %s\n", status.Namespace)
 	output += fmt.Sprintf("Created at: %s\n", formatTimeString(&status.CreationTime))
 
-	// Output one line about each type of Condition that is set.
+	// Output one row about each type of Condition that is set.
 	// Certificate can have multiple Conditions of different types set, e.g. "Ready" or "Issuing"
-	conditionMsg := ""
+	conditions := newConditionsTable("  ")
 	for _, con := range status.Conditions {
-		conditionMsg += fmt.Sprintf("  %s: %s, Reason: %s, Message: %s\n", con.Type, con.Status, con.Reason, con.Message)
+		conditions.addCondition(string(con.Type), string(con.Status), con.Reason, con.Message)
 	}
-	if conditionMsg == "" {
-		conditionMsg = "  No Conditions set\n"
-	}
-	output += fmt.Sprintf("Conditions:\n%s", conditionMsg)
+	output += fmt.Sprintf("Conditions:\n%s", conditions)
 
 	output += fmt.Sprintf("DNS Names:\n%s", formatStringSlice(status.DNSNames))
 
-	var buf bytes.Buffer
-	tabWriter := util.NewTabWriter(&buf)
-	prefixWriter := describe.NewPrefixWriter(tabWriter)
-	util.DescribeEvents(status.Events, prefixWriter, 0)
-	tabWriter.Flush()
-	output += buf.String()
-	buf.Reset()
+	output += describeEvents(status.Events, 0)
 
 	if status.IssuerStatus == nil {
 	}
@@ -235,55 +273,87 @@ func (status *CertificateStatus) String() string {
 
 	output += status.CRStatus.String()
 
+	switch {
+	case status.ChallengesError != nil:
+		output += wrapError(status.ChallengesError)
+	case len(status.Challenges) > 0:
+		output += "Challenges:\n"
+		for _, ch := range status.Challenges {
+			output += ch.String()
+		}
+	}
+
 	return output
 }
 
 // String returns the information about the status of a Issuer/ClusterIssuer as a string to be printed as output
 func (issuerStatus *IssuerStatus) String() string {
 	if issuerStatus.Error != nil {
-		return issuerStatus.Error.Error()
+		return wrapError(issuerStatus.Error)
 	}
 
 	issuerFormat := `Issuer:
   Name: %s
   Kind: %s
   Conditions:
-  %s`
-	conditionMsg := ""
+%s`
+	conditions := newConditionsTable("    ")
 	for _, con := range issuerStatus.Conditions {
-		conditionMsg += fmt.Sprintf("  %s: %s, Reason: %s, Message: %s\n", con.Type, con.Status, con.Reason, con.Message)
+		conditions.addCondition(string(con.Type), string(con.Status), con.Reason, con.Message)
 	}
-	if conditionMsg == "" {
-		conditionMsg = "  No Conditions set\n"
+	output := fmt.Sprintf(issuerFormat, issuerStatus.Name, issuerStatus.Kind, conditions)
+	if issuerStatus.Details != "" {
+		output += "  Details:\n"
+		for _, line := range strings.Split(strings.TrimRight(issuerStatus.Details, "\n"), "\n") {
+			output += "    " + line + "\n"
+		}
+	}
+	if issuerStatus.ACMEAccount != nil {
+		for _, line := range strings.Split(strings.TrimRight(issuerStatus.ACMEAccount.String(), "\n"), "\n") {
+			output += "  " + line + "\n"
+		}
 	}
-	return fmt.Sprintf(issuerFormat, issuerStatus.Name, issuerStatus.Kind, conditionMsg)
+	return output
 }
 
 // String returns the information about the status of a Secret as a string to be printed as output
 func (secretStatus *SecretStatus) String() string {
 	if secretStatus.Error != nil {
-		return secretStatus.Error.Error()
+		return wrapError(secretStatus.Error)
 	}
 
-	secretFormat := `Secret:
-  Name: %s
-  Issuer Country: %s
-  Issuer Organisation: %s
-  Issuer Common Name: %s
-  Key Usage: %s
-  Extended Key Usages: %s
-  Public Key Algorithm: %s
-  Signature Algorithm: %s
-  Subject Key ID: %s
-  Authority Key ID: %s
-  Serial Number: %s
+	infos := fmt.Sprintf("Secret:\n  Name: %s\n", secretStatus.Name)
+	infos += secretStatus.describeX509("  ")
+
+	if len(secretStatus.Mismatches) > 0 {
+		infos += "  Not up to date:\n"
+		for _, m := range secretStatus.Mismatches {
+			infos += output.Indent(m, "    - ", "      ")
+		}
+	}
+	return infos
+}
+
+// describeX509 returns the details of the x509 certificate in the Secret,
+// each line prefixed with indent
+func (secretStatus *SecretStatus) describeX509(indent string) string {
+	x509Format := `%[1]sIssuer Country: %[2]s
+%[1]sIssuer Organisation: %[3]s
+%[1]sIssuer Common Name: %[4]s
+%[1]sKey Usage: %[5]s
+%[1]sExtended Key Usages: %[6]s
+%[1]sPublic Key Algorithm: %[7]s
+%[1]sSignature Algorithm: %[8]s
+%[1]sSubject Key ID: %[9]s
+%[1]sAuthority Key ID: %[10]s
+%[1]sSerial Number: %[11]s
 `
 
 	extKeyUsageString, err := extKeyUsageToString(secretStatus.ExtKeyUsage)
 	if err != nil {
 		extKeyUsageString = err.Error()
 	}
-	return fmt.Sprintf(secretFormat, secretStatus.Name, strings.Join(secretStatus.IssuerCountry, ", "),
+	return fmt.Sprintf(x509Format, indent, strings.Join(secretStatus.IssuerCountry, ", "),
 		strings.Join(secretStatus.IssuerOrganisation, ", "),
 		secretStatus.IssuerCommonName, keyUsageToString(secretStatus.KeyUsage),
 		extKeyUsageString, secretStatus.PublicKeyAlgorithm, secretStatus.SignatureAlgorithm,
@@ -291,6 +361,13 @@ func (secretStatus *SecretStatus) String() string {
 		hex.EncodeToString(secretStatus.SerialNumber.Bytes()))
 }
 
+// DescribeX509Certificate returns the details of cert in the same format as
+// the certificate stored in the Secret of a Certificate is printed by the
+// status certificate command, each line prefixed with indent
+func DescribeX509Certificate(cert *x509.Certificate, indent string) string {
+	return newSecretStatus("", cert).describeX509(indent)
+}
+
 var (
 	keyUsageToStringMap = map[int]string{
 		1:   "Digital Signature",
@@ -344,31 +421,86 @@ func extKeyUsageToString(extUsages []x509.ExtKeyUsage) (string, error) {
 // String returns the information about the status of a CR as a string to be printed as output
 func (crStatus *CRStatus) String() string {
 	if crStatus.Error != nil {
-		return crStatus.Error.Error()
+		return wrapError(crStatus.Error)
 	}
 
 	crFormat := `
   Name: %s
   Namespace: %s
   Conditions:
-  %s`
-	conditionMsg := ""
+%s`
+	conditions := newConditionsTable("    ")
 	for _, con := range crStatus.Conditions {
-		conditionMsg += fmt.Sprintf("  %s: %s, Reason: %s, Message: %s\n", con.Type, con.Status, con.Reason, con.Message)
+		conditions.addCondition(string(con.Type), string(con.Status), con.Reason, con.Message)
 	}
-	if conditionMsg == "" {
-		conditionMsg = "  No Conditions set\n"
-	}
-	infos := fmt.Sprintf(crFormat, crStatus.Name, crStatus.Namespace, conditionMsg)
+	infos := fmt.Sprintf(crFormat, crStatus.Name, crStatus.Namespace, conditions)
 	infos = fmt.Sprintf("CertificateRequest:%s", infos)
+	if crStatus.AwaitingExternalSigning {
+		infos += output.Indent(awaitingExternalSigningMessage, "  ", "    ")
+	}
+
+	infos += describeEvents(crStatus.Events, 1)
+	return infos
+}
 
+// describeEvents renders events as a table indented by baseLevel.
+func describeEvents(events *v1.EventList, baseLevel int) string {
 	var buf bytes.Buffer
-	tabWriter := util.NewTabWriter(&buf)
+	tabWriter := output.NewTabWriter(&buf)
 	prefixWriter := describe.NewPrefixWriter(tabWriter)
-	util.DescribeEvents(crStatus.Events, prefixWriter, 1)
+	output.DescribeEvents(events, prefixWriter, baseLevel)
 	tabWriter.Flush()
-	fmt.Println(buf.Bytes())
-	infos += buf.String()
-	buf.Reset()
-	return infos
+	return buf.String()
+}
+
+// conditionsTable renders the conditions of a resource as a table, with the
+// status of each condition colored by whether it is healthy.
+type conditionsTable struct {
+	*output.Table
+}
+
+func newConditionsTable(indent string) conditionsTable {
+	t := output.NewTable("Type", "Status", "Reason", "Message")
+	t.Indent = indent
+	return conditionsTable{t}
+}
+
+func (t conditionsTable) addCondition(conditionType, status, reason, message string) {
+	t.AddRow(conditionType, output.ConditionStatus(conditionType, status), reason, message)
+}
+
+// String returns the table, or a note that no conditions are set if the
+// table is empty.
+func (t conditionsTable) String() string {
+	if t.Len() == 0 {
+		return t.Indent + "No Conditions set\n"
+	}
+	return t.Table.String()
+}
+
+// formatStringSlice takes in a string slice and formats the contents of the slice
+// into a single string where each element of the slice is prefixed with "- " and on a new line.
+// Elements that are longer than the width of the output are wrapped, with the
+// continuation lines indented to align with the first line of the element.
+func formatStringSlice(strings []string) string {
+	result := ""
+	for _, str := range strings {
+		result += output.Indent(str, "- ", "  ")
+	}
+	return result
+}
+
+// wrapError returns the message of err wrapped to the width of the output.
+// Unlike output.Indent, no newline is appended to the message.
+func wrapError(err error) string {
+	return strings.Join(output.Wrap(err.Error(), output.Width()), "\n")
+}
+
+// formatTimeString returns the time as a string
+// If nil, return "<none>"
+func formatTimeString(t *metav1.Time) string {
+	if t == nil {
+		return "<none>"
+	}
+	return t.Time.Format(time.RFC3339)
 }
@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package certificate
+package status
 
 import (
 	"crypto/x509"
@@ -22,13 +22,17 @@ import (
 	"strings"
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/ctl/output"
 )
 
 func TestFormatStringSlice(t *testing.T) {
 	tests := map[string]struct {
 		slice     []string
+		width     int
 		expOutput string
 	}{
 		// Newlines are part of the expected output
@@ -46,12 +50,23 @@ func TestFormatStringSlice(t *testing.T) {
 			expOutput: `- hello
 - World
 - another line
+`,
+		},
+		"Elements longer than the width are wrapped": {
+			slice: []string{"a-very-long-subdomain-name.example.com", "example.com"},
+			width: 29,
+			expOutput: `- a-very-long-subdomain-name.
+  example.com
+- example.com
 `,
 		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
+			output.SetWidth(test.width)
+			defer output.SetWidth(0)
+
 			if actualOutput := formatStringSlice(test.slice); actualOutput != test.expOutput {
 				t.Errorf("Unexpected output; expected: \n%s\nactual: \n%s", test.expOutput, actualOutput)
 			}
@@ -92,7 +107,26 @@ func TestCRInfoString(t *testing.T) {
   Name: 
   Namespace: 
   Conditions:
-    Ready: True, Reason: , Message: example
+    Type   Status  Reason  Message
+    Ready  True            example
+  Events:  <none>
+`,
+		},
+		"CR awaiting external signing output correct": {
+			cr: &cmapi.CertificateRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ca-1",
+					Namespace:   "default",
+					Annotations: map[string]string{cmapi.CertificateRequestExternalSigningAnnotationKey: "true"},
+				},
+				Status: cmapi.CertificateRequestStatus{Conditions: []cmapi.CertificateRequestCondition{}},
+			},
+			expOutput: `CertificateRequest:
+  Name: ca-1
+  Namespace: default
+  Conditions:
+    No Conditions set
+  Awaiting External Signing: export the CSR with 'kubectl cert-manager external-signing export-csr' and import the signed certificate with 'kubectl cert-manager external-signing import'
   Events:  <none>
 `,
 		},
@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ctl
+
+import (
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	cmapiv1alpha2 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cminternal "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+	"github.com/jetstack/cert-manager/pkg/internal/apis/certmanager/validation"
+)
+
+// ValidateCertificate validates crt with the same rules as the webhook
+// applies when it is created, by converting it to the internal API version.
+func ValidateCertificate(crt *cmapiv1alpha2.Certificate) (field.ErrorList, error) {
+	internalCrt := &cminternal.Certificate{}
+	if err := Scheme.Convert(crt, internalCrt, nil); err != nil {
+		return nil, fmt.Errorf("error converting Certificate: %w", err)
+	}
+	errs := validation.ValidateCertificate(internalCrt)
+	errs = append(errs, validation.ValidateCertificateCreate(internalCrt, authenticationv1.UserInfo{})...)
+	return errs, nil
+}
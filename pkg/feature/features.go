@@ -17,6 +17,10 @@ limitations under the License.
 package feature
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/component-base/featuregate"
 
@@ -28,6 +32,22 @@ const (
 	//
 	// ValidateCAA enables CAA checking when issuing certificates
 	ValidateCAA featuregate.Feature = "ValidateCAA"
+
+	// alpha: v0.16.0
+	//
+	// ServerSideApply makes the controllers write the Secrets of Certificates,
+	// the status of cert-manager resources, the Certificates of ingress-shim
+	// and the challenge solver Ingresses with server-side apply, so that only
+	// the fields managed by each controller are owned by its field manager.
+	ServerSideApply featuregate.Feature = "ServerSideApply"
+
+	// alpha: v0.16.0
+	//
+	// CertificateRequestApproval makes the controllers wait for
+	// CertificateRequests to be approved, e.g. with 'kubectl cert-manager
+	// approve', before signing them. Denied CertificateRequests are never
+	// signed, whether the feature is enabled or not.
+	CertificateRequestApproval featuregate.Feature = "CertificateRequestApproval"
 )
 
 func init() {
@@ -38,5 +58,19 @@ func init() {
 // To add a new feature, define a key for it above and add it here. The features will be
 // available throughout Kubernetes binaries.
 var defaultKubernetesFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
-	ValidateCAA: {Default: false, PreRelease: featuregate.Alpha},
+	ValidateCAA:                {Default: false, PreRelease: featuregate.Alpha},
+	ServerSideApply:            {Default: false, PreRelease: featuregate.Alpha},
+	CertificateRequestApproval: {Default: false, PreRelease: featuregate.Alpha},
+}
+
+// Summary returns the state of all cert-manager feature gates as a comma
+// separated list of name=enabled pairs sorted by name, e.g.
+// 'ServerSideApply=false,ValidateCAA=true'.
+func Summary() string {
+	pairs := make([]string, 0, len(defaultKubernetesFeatureGates))
+	for f := range defaultKubernetesFeatureGates {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", f, utilfeature.DefaultFeatureGate.Enabled(f)))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
 }
@@ -21,6 +21,7 @@ limitations under the License.
 package validation
 
 import (
+	authenticationv1 "k8s.io/api/authentication/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -32,17 +33,23 @@ type Registry struct {
 	scheme                 *runtime.Scheme
 	validateRegister       map[schema.GroupVersionKind]ValidateFunc
 	validateUpdateRegister map[schema.GroupVersionKind]ValidateUpdateFunc
+	validateCreateRegister map[schema.GroupVersionKind]ValidateCreateFunc
 }
 
 type ValidateFunc func(obj runtime.Object) field.ErrorList
 type ValidateUpdateFunc func(oldObj, obj runtime.Object) field.ErrorList
 
+// ValidateCreateFunc validates an object being created by the user described
+// by userInfo.
+type ValidateCreateFunc func(obj runtime.Object, userInfo authenticationv1.UserInfo) field.ErrorList
+
 // NewRegistry creates a new empty registry, backed by the provided Scheme.
 func NewRegistry(scheme *runtime.Scheme) *Registry {
 	return &Registry{
 		scheme:                 scheme,
 		validateRegister:       make(map[schema.GroupVersionKind]ValidateFunc),
 		validateUpdateRegister: make(map[schema.GroupVersionKind]ValidateUpdateFunc),
+		validateCreateRegister: make(map[schema.GroupVersionKind]ValidateCreateFunc),
 	}
 }
 
@@ -86,6 +93,27 @@ func (r *Registry) AddValidateUpdateFunc(obj runtime.Object, fn ValidateUpdateFu
 	return nil
 }
 
+// AddValidateCreateFunc will add a new create validation function to the
+// register.
+// The function will be run whenever ValidateCreate is called with a
+// requestVersion set to any recognised GroupVersionKinds for this object.
+// If obj is part of an internal API version, the validation function will be
+// called on all calls to ValidateCreate regardless of version.
+// If obj cannot be recognised using the registry's scheme, an error will be
+// returned.
+func (r *Registry) AddValidateCreateFunc(obj runtime.Object, fn ValidateCreateFunc) error {
+	gvks, _, err := r.scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+
+	for _, gvk := range gvks {
+		r.appendValidateCreate(gvk, fn)
+	}
+
+	return nil
+}
+
 // Validate will run all validation functions registered for the given object.
 // If the passed obj is *not* of the same version as the provided
 // requestVersion, the registry will attempt to convert the object before
@@ -148,6 +176,35 @@ func (r *Registry) ValidateUpdate(oldObj, obj runtime.Object, requestVersion sch
 	return el
 }
 
+// ValidateCreate will run all create validation functions registered for the
+// given object, which is being created by the user described by userInfo.
+// If the passed obj is *not* of the same version as the provided
+// requestVersion, the registry will attempt to convert the object before
+// calling the validation functions.
+// Any validation functions registered for the objects internal API version
+// will be run against the object regardless of version.
+func (r *Registry) ValidateCreate(obj runtime.Object, userInfo authenticationv1.UserInfo, requestVersion schema.GroupVersionKind) field.ErrorList {
+	versioned, internal := r.lookupValidateCreateFuncs(requestVersion)
+	if versioned == nil && internal == nil {
+		return nil
+	}
+
+	targetObj, internalObj, err := r.convert(obj, requestVersion)
+	if err != nil {
+		return internalError(err)
+	}
+
+	el := field.ErrorList{}
+	if versioned != nil {
+		el = append(el, versioned(targetObj, userInfo)...)
+	}
+	if internal != nil {
+		el = append(el, internal(internalObj, userInfo)...)
+	}
+
+	return el
+}
+
 func (r *Registry) lookupValidateFuncs(gvk schema.GroupVersionKind) (versioned ValidateFunc, internal ValidateFunc) {
 	versioned = r.validateRegister[gvk]
 	gvk.Version = runtime.APIVersionInternal
@@ -162,6 +219,13 @@ func (r *Registry) lookupValidateUpdateFuncs(gvk schema.GroupVersionKind) (versi
 	return versioned, internal
 }
 
+func (r *Registry) lookupValidateCreateFuncs(gvk schema.GroupVersionKind) (versioned ValidateCreateFunc, internal ValidateCreateFunc) {
+	versioned = r.validateCreateRegister[gvk]
+	gvk.Version = runtime.APIVersionInternal
+	internal = r.validateCreateRegister[gvk]
+	return versioned, internal
+}
+
 func (r *Registry) appendValidate(gvk schema.GroupVersionKind, fn ValidateFunc) {
 	existing, ok := r.validateRegister[gvk]
 	if !ok {
@@ -186,6 +250,18 @@ func (r *Registry) appendValidateUpdate(gvk schema.GroupVersionKind, fn Validate
 	}
 }
 
+func (r *Registry) appendValidateCreate(gvk schema.GroupVersionKind, fn ValidateCreateFunc) {
+	existing, ok := r.validateCreateRegister[gvk]
+	if !ok {
+		r.validateCreateRegister[gvk] = fn
+		return
+	}
+
+	r.validateCreateRegister[gvk] = func(obj runtime.Object, userInfo authenticationv1.UserInfo) field.ErrorList {
+		return append(existing(obj, userInfo), fn(obj, userInfo)...)
+	}
+}
+
 // convert will convert the given obj into the requestVersion as well as
 // returning the internal representation of the object.
 func (r *Registry) convert(obj runtime.Object, requestVersion schema.GroupVersionKind) (targetObj, internalObj runtime.Object, err error) {
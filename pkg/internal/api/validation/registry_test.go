@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"testing"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -127,6 +128,31 @@ func TestValidateUpdateType(t *testing.T) {
 	}
 }
 
+func TestValidateCreateTypeMultiple(t *testing.T) {
+	reg := validation.NewRegistry(scheme)
+	userInfo := authenticationv1.UserInfo{Username: "system:serviceaccount:default:app"}
+	called := false
+	calledInternal := false
+	utilruntime.Must(reg.AddValidateCreateFunc(&cmapi.CertificateRequest{}, func(_ runtime.Object, u authenticationv1.UserInfo) field.ErrorList {
+		called = u.Username == userInfo.Username
+		return nil
+	}))
+	utilruntime.Must(reg.AddValidateCreateFunc(&cmapiinternal.CertificateRequest{}, func(_ runtime.Object, u authenticationv1.UserInfo) field.ErrorList {
+		calledInternal = u.Username == userInfo.Username
+		return nil
+	}))
+	errs := reg.ValidateCreate(&cmapi.CertificateRequest{}, userInfo, cmapi.SchemeGroupVersion.WithKind("CertificateRequest"))
+	if len(errs) > 0 {
+		t.Errorf("expected to not get an error but got: %v", errs.ToAggregate())
+	}
+	if !called {
+		t.Errorf("expected registered validation function to run with the user info but it did not")
+	}
+	if !calledInternal {
+		t.Errorf("expected registered internal validation function to run against external type but it did not")
+	}
+}
+
 func TestValidateTypeReturnsErrors(t *testing.T) {
 	reg := validation.NewRegistry(scheme)
 	called := false
@@ -125,7 +125,48 @@ type ChallengeStatus struct {
 	// current state.
 	Reason string
 
+	// FailureType categorises the ACME error that caused this Challenge to
+	// fail, if the failure was reported by the ACME server.
+	FailureType ACMEErrorType
+
 	// State contains the current 'state' of the challenge.
 	// If not set, the state of the challenge is unknown.
 	State State
+
+	// List of status conditions to indicate the status of the Challenge.
+	// Known condition types are `ProviderUnavailable`.
+	Conditions []ChallengeCondition
 }
+
+// ChallengeCondition contains condition information for a Challenge.
+type ChallengeCondition struct {
+	// Type of the condition, known values are (`ProviderUnavailable`).
+	Type ChallengeConditionType
+
+	// Status of the condition, one of ('True', 'False', 'Unknown').
+	Status cmmeta.ConditionStatus
+
+	// LastTransitionTime is the timestamp corresponding to the last status
+	// change of this condition.
+	LastTransitionTime *metav1.Time
+
+	// Reason is a brief machine readable explanation for the condition's last
+	// transition.
+	Reason string
+
+	// Message is a human readable description of the details of the last
+	// transition, complementing reason.
+	Message string
+}
+
+// ChallengeConditionType represents a Challenge condition value.
+type ChallengeConditionType string
+
+const (
+	// ChallengeConditionProviderUnavailable indicates that the DNS01 provider
+	// used to solve the Challenge is failing systematically, e.g. because it
+	// rejects the configured credentials or keeps returning server errors.
+	// Challenges with this condition set to True are not presented until a
+	// probe shows that the provider has recovered.
+	ChallengeConditionProviderUnavailable ChallengeConditionType = "ProviderUnavailable"
+)
@@ -61,6 +61,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.ACMEChallengeHook)(nil), (*acme.ACMEChallengeHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ACMEChallengeHook_To_acme_ACMEChallengeHook(a.(*v1alpha3.ACMEChallengeHook), b.(*acme.ACMEChallengeHook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEChallengeHook)(nil), (*v1alpha3.ACMEChallengeHook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEChallengeHook_To_v1alpha3_ACMEChallengeHook(a.(*acme.ACMEChallengeHook), b.(*v1alpha3.ACMEChallengeHook), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.ACMEChallengeSolver)(nil), (*acme.ACMEChallengeSolver)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_ACMEChallengeSolver_To_acme_ACMEChallengeSolver(a.(*v1alpha3.ACMEChallengeSolver), b.(*acme.ACMEChallengeSolver), scope)
 	}); err != nil {
@@ -151,6 +161,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.ACMEChallengeSolverHooks)(nil), (*acme.ACMEChallengeSolverHooks)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ACMEChallengeSolverHooks_To_acme_ACMEChallengeSolverHooks(a.(*v1alpha3.ACMEChallengeSolverHooks), b.(*acme.ACMEChallengeSolverHooks), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEChallengeSolverHooks)(nil), (*v1alpha3.ACMEChallengeSolverHooks)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEChallengeSolverHooks_To_v1alpha3_ACMEChallengeSolverHooks(a.(*acme.ACMEChallengeSolverHooks), b.(*v1alpha3.ACMEChallengeSolverHooks), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.ACMEExternalAccountBinding)(nil), (*acme.ACMEExternalAccountBinding)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_ACMEExternalAccountBinding_To_acme_ACMEExternalAccountBinding(a.(*v1alpha3.ACMEExternalAccountBinding), b.(*acme.ACMEExternalAccountBinding), scope)
 	}); err != nil {
@@ -291,6 +311,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.ChallengeCondition)(nil), (*acme.ChallengeCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ChallengeCondition_To_acme_ChallengeCondition(a.(*v1alpha3.ChallengeCondition), b.(*acme.ChallengeCondition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ChallengeCondition)(nil), (*v1alpha3.ChallengeCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ChallengeCondition_To_v1alpha3_ChallengeCondition(a.(*acme.ChallengeCondition), b.(*v1alpha3.ChallengeCondition), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.ChallengeList)(nil), (*acme.ChallengeList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_ChallengeList_To_acme_ChallengeList(a.(*v1alpha3.ChallengeList), b.(*acme.ChallengeList), scope)
 	}); err != nil {
@@ -416,10 +446,37 @@ func Convert_acme_ACMEChallenge_To_v1alpha3_ACMEChallenge(in *acme.ACMEChallenge
 	return autoConvert_acme_ACMEChallenge_To_v1alpha3_ACMEChallenge(in, out, s)
 }
 
+func autoConvert_v1alpha3_ACMEChallengeHook_To_acme_ACMEChallengeHook(in *v1alpha3.ACMEChallengeHook, out *acme.ACMEChallengeHook, s conversion.Scope) error {
+	out.URL = in.URL
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	out.Timeout = (*apismetav1.Duration)(unsafe.Pointer(in.Timeout))
+	out.Retries = (*int)(unsafe.Pointer(in.Retries))
+	return nil
+}
+
+// Convert_v1alpha3_ACMEChallengeHook_To_acme_ACMEChallengeHook is an autogenerated conversion function.
+func Convert_v1alpha3_ACMEChallengeHook_To_acme_ACMEChallengeHook(in *v1alpha3.ACMEChallengeHook, out *acme.ACMEChallengeHook, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ACMEChallengeHook_To_acme_ACMEChallengeHook(in, out, s)
+}
+
+func autoConvert_acme_ACMEChallengeHook_To_v1alpha3_ACMEChallengeHook(in *acme.ACMEChallengeHook, out *v1alpha3.ACMEChallengeHook, s conversion.Scope) error {
+	out.URL = in.URL
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	out.Timeout = (*apismetav1.Duration)(unsafe.Pointer(in.Timeout))
+	out.Retries = (*int)(unsafe.Pointer(in.Retries))
+	return nil
+}
+
+// Convert_acme_ACMEChallengeHook_To_v1alpha3_ACMEChallengeHook is an autogenerated conversion function.
+func Convert_acme_ACMEChallengeHook_To_v1alpha3_ACMEChallengeHook(in *acme.ACMEChallengeHook, out *v1alpha3.ACMEChallengeHook, s conversion.Scope) error {
+	return autoConvert_acme_ACMEChallengeHook_To_v1alpha3_ACMEChallengeHook(in, out, s)
+}
+
 func autoConvert_v1alpha3_ACMEChallengeSolver_To_acme_ACMEChallengeSolver(in *v1alpha3.ACMEChallengeSolver, out *acme.ACMEChallengeSolver, s conversion.Scope) error {
 	out.Selector = (*acme.CertificateDNSNameSelector)(unsafe.Pointer(in.Selector))
 	out.HTTP01 = (*acme.ACMEChallengeSolverHTTP01)(unsafe.Pointer(in.HTTP01))
 	out.DNS01 = (*acme.ACMEChallengeSolverDNS01)(unsafe.Pointer(in.DNS01))
+	out.Hooks = (*acme.ACMEChallengeSolverHooks)(unsafe.Pointer(in.Hooks))
 	return nil
 }
 
@@ -432,6 +489,7 @@ func autoConvert_acme_ACMEChallengeSolver_To_v1alpha3_ACMEChallengeSolver(in *ac
 	out.Selector = (*v1alpha3.CertificateDNSNameSelector)(unsafe.Pointer(in.Selector))
 	out.HTTP01 = (*v1alpha3.ACMEChallengeSolverHTTP01)(unsafe.Pointer(in.HTTP01))
 	out.DNS01 = (*v1alpha3.ACMEChallengeSolverDNS01)(unsafe.Pointer(in.DNS01))
+	out.Hooks = (*v1alpha3.ACMEChallengeSolverHooks)(unsafe.Pointer(in.Hooks))
 	return nil
 }
 
@@ -575,6 +633,8 @@ func autoConvert_v1alpha3_ACMEChallengeSolverHTTP01IngressPodSpec_To_acme_ACMECh
 	out.Affinity = (*v1.Affinity)(unsafe.Pointer(in.Affinity))
 	out.Tolerations = *(*[]v1.Toleration)(unsafe.Pointer(&in.Tolerations))
 	out.PriorityClassName = in.PriorityClassName
+	out.RuntimeClassName = (*string)(unsafe.Pointer(in.RuntimeClassName))
+	out.TopologySpreadConstraints = *(*[]v1.TopologySpreadConstraint)(unsafe.Pointer(&in.TopologySpreadConstraints))
 	out.ServiceAccountName = in.ServiceAccountName
 	return nil
 }
@@ -589,6 +649,8 @@ func autoConvert_acme_ACMEChallengeSolverHTTP01IngressPodSpec_To_v1alpha3_ACMECh
 	out.Affinity = (*v1.Affinity)(unsafe.Pointer(in.Affinity))
 	out.Tolerations = *(*[]v1.Toleration)(unsafe.Pointer(&in.Tolerations))
 	out.PriorityClassName = in.PriorityClassName
+	out.RuntimeClassName = (*string)(unsafe.Pointer(in.RuntimeClassName))
+	out.TopologySpreadConstraints = *(*[]v1.TopologySpreadConstraint)(unsafe.Pointer(&in.TopologySpreadConstraints))
 	out.ServiceAccountName = in.ServiceAccountName
 	return nil
 }
@@ -652,6 +714,28 @@ func Convert_acme_ACMEChallengeSolverHTTP01IngressTemplate_To_v1alpha3_ACMEChall
 	return autoConvert_acme_ACMEChallengeSolverHTTP01IngressTemplate_To_v1alpha3_ACMEChallengeSolverHTTP01IngressTemplate(in, out, s)
 }
 
+func autoConvert_v1alpha3_ACMEChallengeSolverHooks_To_acme_ACMEChallengeSolverHooks(in *v1alpha3.ACMEChallengeSolverHooks, out *acme.ACMEChallengeSolverHooks, s conversion.Scope) error {
+	out.PrePresent = (*acme.ACMEChallengeHook)(unsafe.Pointer(in.PrePresent))
+	out.PostCleanUp = (*acme.ACMEChallengeHook)(unsafe.Pointer(in.PostCleanUp))
+	return nil
+}
+
+// Convert_v1alpha3_ACMEChallengeSolverHooks_To_acme_ACMEChallengeSolverHooks is an autogenerated conversion function.
+func Convert_v1alpha3_ACMEChallengeSolverHooks_To_acme_ACMEChallengeSolverHooks(in *v1alpha3.ACMEChallengeSolverHooks, out *acme.ACMEChallengeSolverHooks, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ACMEChallengeSolverHooks_To_acme_ACMEChallengeSolverHooks(in, out, s)
+}
+
+func autoConvert_acme_ACMEChallengeSolverHooks_To_v1alpha3_ACMEChallengeSolverHooks(in *acme.ACMEChallengeSolverHooks, out *v1alpha3.ACMEChallengeSolverHooks, s conversion.Scope) error {
+	out.PrePresent = (*v1alpha3.ACMEChallengeHook)(unsafe.Pointer(in.PrePresent))
+	out.PostCleanUp = (*v1alpha3.ACMEChallengeHook)(unsafe.Pointer(in.PostCleanUp))
+	return nil
+}
+
+// Convert_acme_ACMEChallengeSolverHooks_To_v1alpha3_ACMEChallengeSolverHooks is an autogenerated conversion function.
+func Convert_acme_ACMEChallengeSolverHooks_To_v1alpha3_ACMEChallengeSolverHooks(in *acme.ACMEChallengeSolverHooks, out *v1alpha3.ACMEChallengeSolverHooks, s conversion.Scope) error {
+	return autoConvert_acme_ACMEChallengeSolverHooks_To_v1alpha3_ACMEChallengeSolverHooks(in, out, s)
+}
+
 func autoConvert_v1alpha3_ACMEExternalAccountBinding_To_acme_ACMEExternalAccountBinding(in *v1alpha3.ACMEExternalAccountBinding, out *acme.ACMEExternalAccountBinding, s conversion.Scope) error {
 	out.KeyID = in.KeyID
 	// TODO: Inefficient conversion - can we improve it?
@@ -1064,6 +1148,34 @@ func Convert_acme_Challenge_To_v1alpha3_Challenge(in *acme.Challenge, out *v1alp
 	return autoConvert_acme_Challenge_To_v1alpha3_Challenge(in, out, s)
 }
 
+func autoConvert_v1alpha3_ChallengeCondition_To_acme_ChallengeCondition(in *v1alpha3.ChallengeCondition, out *acme.ChallengeCondition, s conversion.Scope) error {
+	out.Type = acme.ChallengeConditionType(in.Type)
+	out.Status = meta.ConditionStatus(in.Status)
+	out.LastTransitionTime = (*apismetav1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1alpha3_ChallengeCondition_To_acme_ChallengeCondition is an autogenerated conversion function.
+func Convert_v1alpha3_ChallengeCondition_To_acme_ChallengeCondition(in *v1alpha3.ChallengeCondition, out *acme.ChallengeCondition, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ChallengeCondition_To_acme_ChallengeCondition(in, out, s)
+}
+
+func autoConvert_acme_ChallengeCondition_To_v1alpha3_ChallengeCondition(in *acme.ChallengeCondition, out *v1alpha3.ChallengeCondition, s conversion.Scope) error {
+	out.Type = v1alpha3.ChallengeConditionType(in.Type)
+	out.Status = metav1.ConditionStatus(in.Status)
+	out.LastTransitionTime = (*apismetav1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_acme_ChallengeCondition_To_v1alpha3_ChallengeCondition is an autogenerated conversion function.
+func Convert_acme_ChallengeCondition_To_v1alpha3_ChallengeCondition(in *acme.ChallengeCondition, out *v1alpha3.ChallengeCondition, s conversion.Scope) error {
+	return autoConvert_acme_ChallengeCondition_To_v1alpha3_ChallengeCondition(in, out, s)
+}
+
 func autoConvert_v1alpha3_ChallengeList_To_acme_ChallengeList(in *v1alpha3.ChallengeList, out *acme.ChallengeList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
@@ -1146,7 +1258,9 @@ func autoConvert_v1alpha3_ChallengeStatus_To_acme_ChallengeStatus(in *v1alpha3.C
 	out.Processing = in.Processing
 	out.Presented = in.Presented
 	out.Reason = in.Reason
+	out.FailureType = acme.ACMEErrorType(in.FailureType)
 	out.State = acme.State(in.State)
+	out.Conditions = *(*[]acme.ChallengeCondition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -1159,7 +1273,9 @@ func autoConvert_acme_ChallengeStatus_To_v1alpha3_ChallengeStatus(in *acme.Chall
 	out.Processing = in.Processing
 	out.Presented = in.Presented
 	out.Reason = in.Reason
+	out.FailureType = v1alpha3.ACMEErrorType(in.FailureType)
 	out.State = v1alpha3.State(in.State)
+	out.Conditions = *(*[]v1alpha3.ChallengeCondition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -1269,8 +1385,10 @@ func autoConvert_v1alpha3_OrderStatus_To_acme_OrderStatus(in *v1alpha3.OrderStat
 	out.FinalizeURL = in.FinalizeURL
 	out.Authorizations = *(*[]acme.ACMEAuthorization)(unsafe.Pointer(&in.Authorizations))
 	out.Certificate = *(*[]byte)(unsafe.Pointer(&in.Certificate))
+	out.CertificateURL = in.CertificateURL
 	out.State = acme.State(in.State)
 	out.Reason = in.Reason
+	out.FailureType = acme.ACMEErrorType(in.FailureType)
 	out.FailureTime = (*apismetav1.Time)(unsafe.Pointer(in.FailureTime))
 	return nil
 }
@@ -1284,8 +1402,10 @@ func autoConvert_acme_OrderStatus_To_v1alpha3_OrderStatus(in *acme.OrderStatus,
 	out.URL = in.URL
 	out.FinalizeURL = in.FinalizeURL
 	out.Certificate = *(*[]byte)(unsafe.Pointer(&in.Certificate))
+	out.CertificateURL = in.CertificateURL
 	out.State = v1alpha3.State(in.State)
 	out.Reason = in.Reason
+	out.FailureType = v1alpha3.ACMEErrorType(in.FailureType)
 	out.Authorizations = *(*[]v1alpha3.ACMEAuthorization)(unsafe.Pointer(&in.Authorizations))
 	out.FailureTime = (*apismetav1.Time)(unsafe.Pointer(in.FailureTime))
 	return nil
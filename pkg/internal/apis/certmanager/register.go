@@ -47,6 +47,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ClusterIssuerList{},
 		&CertificateRequest{},
 		&CertificateRequestList{},
+		&SelfStatus{},
+		&SelfStatusList{},
 	)
 	return nil
 }
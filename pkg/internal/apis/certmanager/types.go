@@ -45,6 +45,19 @@ const (
 	// Annotation key used to denote whether a Secret is named on a Certificate
 	// as a 'next private key' Secret resource.
 	IsNextPrivateKeySecretLabelKey = "cert-manager.io/next-private-key"
+
+	// Label key for the team that a Certificate or CertificateRequest is
+	// attributed to in usage metrics and reports.
+	TeamLabelKey = "cert-manager.io/team"
+
+	// Annotation key for the signed attestation binding the certificate
+	// stored in a Secret to the Certificate it was issued for.
+	AttestationAnnotationKey = "cert-manager.io/attestation"
+
+	// Annotation key for the SHA-256 fingerprint of the public key of the
+	// private key stored in a Secret, set once the private key has been
+	// delivered to the escrow service configured on the issuer.
+	EscrowedKeyFingerprintAnnotationKey = "cert-manager.io/escrowed-key-fingerprint"
 )
 
 // Deprecated annotation names for Secrets
@@ -83,6 +96,18 @@ const (
 
 	// Annotation to declare the CertificateRequest "revision", belonging to a Certificate Resource
 	CertificateRequestRevisionAnnotationKey = "cert-manager.io/certificate-revision"
+
+	// Label added to CertificateRequest resources to denote the "revision" of
+	// the Certificate resource it belongs to, so that it can be selected for
+	// without listing all CertificateRequest resources in the namespace.
+	// The Certificate resource name is set using the CertificateNameKey label.
+	CertificateRequestRevisionLabelKey = "cert-manager.io/certificate-revision"
+
+	// Annotation that can be added to Issuer and ClusterIssuer resources to
+	// limit the number of CertificateRequests that are being signed by the
+	// issuer at once, e.g. to honour the rate limits of the upstream CA.
+	// Further CertificateRequests are kept pending until a slot is released.
+	IssuerMaxConcurrentRequestsAnnotationKey = "cert-manager.io/max-concurrent-requests"
 )
 
 const (
@@ -92,6 +117,17 @@ const (
 	// stored in the target Secret resource whilst the real Issuer is processing
 	// the certificate request.
 	IssueTemporaryCertificateAnnotation = "cert-manager.io/issue-temporary-certificate"
+
+	// PausedAnnotationKey is an annotation that can be added to Certificate,
+	// CertificateRequest, Order and Challenge resources.
+	// If it is set to "true", cert-manager will stop reconciling the resource
+	// until the annotation is removed or set to any other value.
+	// Pausing a Certificate stops all of the certificates controllers acting
+	// on it, including the revocation of its certificates, and the sweeping
+	// of its next private key Secrets. It does not pause the
+	// CertificateRequests of the Certificate. Pausing an Order also pauses
+	// its Challenges.
+	PausedAnnotationKey = "cert-manager.io/paused"
 )
 
 // Common/known resource kinds.
@@ -100,6 +136,7 @@ const (
 	IssuerKind             = "Issuer"
 	CertificateKind        = "Certificate"
 	CertificateRequestKind = "CertificateRequest"
+	SelfStatusKind         = "SelfStatus"
 )
 
 const (
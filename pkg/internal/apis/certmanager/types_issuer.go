@@ -82,6 +82,66 @@ type IssuerList struct {
 // configuration required for the issuer.
 type IssuerSpec struct {
 	IssuerConfig
+
+	// AllowedDNSSuffixes restricts the DNS names this issuer will sign
+	// certificates for. If set, CertificateRequests containing a DNS name or
+	// common name that is not equal to, or a subdomain of, one of the listed
+	// suffixes are failed before the issuer is contacted.
+	// If not set, any DNS name is allowed.
+	AllowedDNSSuffixes []string
+
+	// RequestDefaults are names that this issuer adds to the certificates it
+	// signs if they are not already requested. They are only applied by
+	// issuers that build the certificate themselves, i.e. CA and SelfSigned
+	// issuers.
+	RequestDefaults *RequestDefaults
+
+	// KeyEscrow configures the delivery of the private keys of Certificates
+	// using this issuer to an escrow service.
+	KeyEscrow *KeyEscrow
+}
+
+// RequestDefaults are the subject and subject alternative names added by an
+// issuer to the certificates it signs. Every value is a Go template.
+type RequestDefaults struct {
+	// CommonName is the common name of certificates that do not request one.
+	CommonName string
+
+	// DNSNames are DNS subject alternative names added to every certificate.
+	DNSNames []string
+
+	// URISANs are URI subject alternative names added to every certificate.
+	URISANs []string
+}
+
+// KeyEscrow configures the escrow service that private keys are delivered
+// to. Exactly one of HTTPS or KMIP must be set.
+type KeyEscrow struct {
+	// HTTPS delivers keys to an HTTPS endpoint.
+	HTTPS *HTTPSKeyEscrow
+
+	// KMIP registers keys as private key objects with a KMIP server.
+	KMIP *KMIPKeyEscrow
+
+	// CABundle is a PEM encoded bundle of CA certificates used to verify the
+	// certificate of the escrow service.
+	CABundle []byte
+
+	// ClientCertSecretRef references a Secret holding the client certificate
+	// and private key used to authenticate to the escrow service.
+	ClientCertSecretRef cmmeta.LocalObjectReference
+}
+
+// HTTPSKeyEscrow delivers private keys to an HTTPS endpoint.
+type HTTPSKeyEscrow struct {
+	// URL of the endpoint, which must use the https scheme.
+	URL string
+}
+
+// KMIPKeyEscrow registers private keys with a KMIP server.
+type KMIPKeyEscrow struct {
+	// Server is the host and port of the KMIP server.
+	Server string
 }
 
 type IssuerConfig struct {
@@ -186,8 +246,42 @@ type VaultIssuer struct {
 	// plain HTTP protocol connection. If not set the system root certificates
 	// are used to validate the TLS connection.
 	CABundle []byte
+
+	// SigningMode selects the endpoint of the Vault PKI backend used to sign
+	// certificates. If set to `Role`, the `sign` endpoint of the role in
+	// `path` is used, so that the role's constraints and defaults apply. If
+	// set to `Verbatim`, the `sign-verbatim` endpoint is used, which signs
+	// the CSR with its subject and subject alternative names as is.
+	// `path` must have the form `<mount>/sign/<role>` or
+	// `<mount>/sign-verbatim[/<role>]` if this field is set.
+	// If not set, `path` is used as given.
+	SigningMode VaultSigningMode
+
+	// IssuerRef selects the issuer of a Vault PKI mount with multiple
+	// issuers that certificates are signed by, by name or ID. If not set,
+	// the default issuer of the mount is used.
+	IssuerRef string
+
+	// ExcludeCNFromSANs controls whether Vault excludes the common name of
+	// the CSR from the DNS and email subject alternative names of signed
+	// certificates. Defaults to true.
+	ExcludeCNFromSANs *bool
 }
 
+// VaultSigningMode denotes the endpoint of the Vault PKI backend used to sign
+// certificates.
+type VaultSigningMode string
+
+const (
+	// VaultSigningModeRole signs certificates using the `sign` endpoint of a
+	// Vault PKI role.
+	VaultSigningModeRole VaultSigningMode = "Role"
+
+	// VaultSigningModeVerbatim signs certificates using the `sign-verbatim`
+	// endpoint of a Vault PKI mount.
+	VaultSigningModeVerbatim VaultSigningMode = "Verbatim"
+)
+
 // Configuration used to authenticate with a Vault server.
 // Only one of `tokenSecretRef`, `appRole` or `kubernetes` may be specified.
 type VaultAuth struct {
@@ -293,4 +387,10 @@ const (
 	// If the `status` of this condition is `False`, CertificateRequest controllers
 	// should prevent attempts to sign certificates.
 	IssuerConditionReady IssuerConditionType = "Ready"
+
+	// IssuerConditionDeprecated is set to True when the spec of the issuer
+	// was written using a deprecated apiVersion. The message of the condition
+	// explains how to migrate.
+	// It is removed once the issuer no longer uses deprecated APIs.
+	IssuerConditionDeprecated IssuerConditionType = "Deprecated"
 )
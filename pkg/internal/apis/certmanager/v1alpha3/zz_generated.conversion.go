@@ -166,6 +166,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.ComponentCertificateStatus)(nil), (*certmanager.ComponentCertificateStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ComponentCertificateStatus_To_certmanager_ComponentCertificateStatus(a.(*v1alpha3.ComponentCertificateStatus), b.(*certmanager.ComponentCertificateStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.ComponentCertificateStatus)(nil), (*v1alpha3.ComponentCertificateStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_ComponentCertificateStatus_To_v1alpha3_ComponentCertificateStatus(a.(*certmanager.ComponentCertificateStatus), b.(*v1alpha3.ComponentCertificateStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.HTTPSKeyEscrow)(nil), (*certmanager.HTTPSKeyEscrow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_HTTPSKeyEscrow_To_certmanager_HTTPSKeyEscrow(a.(*v1alpha3.HTTPSKeyEscrow), b.(*certmanager.HTTPSKeyEscrow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.HTTPSKeyEscrow)(nil), (*v1alpha3.HTTPSKeyEscrow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_HTTPSKeyEscrow_To_v1alpha3_HTTPSKeyEscrow(a.(*certmanager.HTTPSKeyEscrow), b.(*v1alpha3.HTTPSKeyEscrow), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.Issuer)(nil), (*certmanager.Issuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_Issuer_To_certmanager_Issuer(a.(*v1alpha3.Issuer), b.(*certmanager.Issuer), scope)
 	}); err != nil {
@@ -236,6 +256,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.KMIPKeyEscrow)(nil), (*certmanager.KMIPKeyEscrow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_KMIPKeyEscrow_To_certmanager_KMIPKeyEscrow(a.(*v1alpha3.KMIPKeyEscrow), b.(*certmanager.KMIPKeyEscrow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.KMIPKeyEscrow)(nil), (*v1alpha3.KMIPKeyEscrow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_KMIPKeyEscrow_To_v1alpha3_KMIPKeyEscrow(a.(*certmanager.KMIPKeyEscrow), b.(*v1alpha3.KMIPKeyEscrow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.KeyEscrow)(nil), (*certmanager.KeyEscrow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_KeyEscrow_To_certmanager_KeyEscrow(a.(*v1alpha3.KeyEscrow), b.(*certmanager.KeyEscrow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.KeyEscrow)(nil), (*v1alpha3.KeyEscrow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_KeyEscrow_To_v1alpha3_KeyEscrow(a.(*certmanager.KeyEscrow), b.(*v1alpha3.KeyEscrow), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.PKCS12Keystore)(nil), (*certmanager.PKCS12Keystore)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_PKCS12Keystore_To_certmanager_PKCS12Keystore(a.(*v1alpha3.PKCS12Keystore), b.(*certmanager.PKCS12Keystore), scope)
 	}); err != nil {
@@ -246,6 +286,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.RequestDefaults)(nil), (*certmanager.RequestDefaults)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_RequestDefaults_To_certmanager_RequestDefaults(a.(*v1alpha3.RequestDefaults), b.(*certmanager.RequestDefaults), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.RequestDefaults)(nil), (*v1alpha3.RequestDefaults)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_RequestDefaults_To_v1alpha3_RequestDefaults(a.(*certmanager.RequestDefaults), b.(*v1alpha3.RequestDefaults), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.SelfSignedIssuer)(nil), (*certmanager.SelfSignedIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_SelfSignedIssuer_To_certmanager_SelfSignedIssuer(a.(*v1alpha3.SelfSignedIssuer), b.(*certmanager.SelfSignedIssuer), scope)
 	}); err != nil {
@@ -256,6 +306,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.SelfStatus)(nil), (*certmanager.SelfStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_SelfStatus_To_certmanager_SelfStatus(a.(*v1alpha3.SelfStatus), b.(*certmanager.SelfStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.SelfStatus)(nil), (*v1alpha3.SelfStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_SelfStatus_To_v1alpha3_SelfStatus(a.(*certmanager.SelfStatus), b.(*v1alpha3.SelfStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.SelfStatusCondition)(nil), (*certmanager.SelfStatusCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_SelfStatusCondition_To_certmanager_SelfStatusCondition(a.(*v1alpha3.SelfStatusCondition), b.(*certmanager.SelfStatusCondition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.SelfStatusCondition)(nil), (*v1alpha3.SelfStatusCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_SelfStatusCondition_To_v1alpha3_SelfStatusCondition(a.(*certmanager.SelfStatusCondition), b.(*v1alpha3.SelfStatusCondition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.SelfStatusList)(nil), (*certmanager.SelfStatusList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_SelfStatusList_To_certmanager_SelfStatusList(a.(*v1alpha3.SelfStatusList), b.(*certmanager.SelfStatusList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.SelfStatusList)(nil), (*v1alpha3.SelfStatusList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_SelfStatusList_To_v1alpha3_SelfStatusList(a.(*certmanager.SelfStatusList), b.(*v1alpha3.SelfStatusList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha3.SelfStatusStatus)(nil), (*certmanager.SelfStatusStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_SelfStatusStatus_To_certmanager_SelfStatusStatus(a.(*v1alpha3.SelfStatusStatus), b.(*certmanager.SelfStatusStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.SelfStatusStatus)(nil), (*v1alpha3.SelfStatusStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_SelfStatusStatus_To_v1alpha3_SelfStatusStatus(a.(*certmanager.SelfStatusStatus), b.(*v1alpha3.SelfStatusStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha3.VaultAppRole)(nil), (*certmanager.VaultAppRole)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VaultAppRole_To_certmanager_VaultAppRole(a.(*v1alpha3.VaultAppRole), b.(*certmanager.VaultAppRole), scope)
 	}); err != nil {
@@ -691,6 +781,7 @@ func autoConvert_v1alpha3_CertificateSpec_To_certmanager_CertificateSpec(in *v1a
 		out.Subject = nil
 	}
 	out.CommonName = in.CommonName
+	out.SubjectAltNamesPolicy = certmanager.SubjectAltNamesPolicy(in.SubjectAltNamesPolicy)
 	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
 	out.RenewBefore = (*v1.Duration)(unsafe.Pointer(in.RenewBefore))
 	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
@@ -699,11 +790,13 @@ func autoConvert_v1alpha3_CertificateSpec_To_certmanager_CertificateSpec(in *v1a
 	out.EmailSANs = *(*[]string)(unsafe.Pointer(&in.EmailSANs))
 	out.SecretName = in.SecretName
 	out.Keystores = (*certmanager.CertificateKeystores)(unsafe.Pointer(in.Keystores))
+	out.SecretKeysPerName = in.SecretKeysPerName
 	// TODO: Inefficient conversion - can we improve it?
 	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
 		return err
 	}
 	out.IsCA = in.IsCA
+	out.ExternalSigning = in.ExternalSigning
 	out.Usages = *(*[]certmanager.KeyUsage)(unsafe.Pointer(&in.Usages))
 	// WARNING: in.KeySize requires manual conversion: does not exist in peer-type
 	// WARNING: in.KeyAlgorithm requires manual conversion: does not exist in peer-type
@@ -731,6 +824,7 @@ func autoConvert_certmanager_CertificateSpec_To_v1alpha3_CertificateSpec(in *cer
 		out.Subject = nil
 	}
 	out.CommonName = in.CommonName
+	out.SubjectAltNamesPolicy = v1alpha3.SubjectAltNamesPolicy(in.SubjectAltNamesPolicy)
 	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
 	out.RenewBefore = (*v1.Duration)(unsafe.Pointer(in.RenewBefore))
 	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
@@ -739,11 +833,13 @@ func autoConvert_certmanager_CertificateSpec_To_v1alpha3_CertificateSpec(in *cer
 	out.EmailSANs = *(*[]string)(unsafe.Pointer(&in.EmailSANs))
 	out.SecretName = in.SecretName
 	out.Keystores = (*v1alpha3.CertificateKeystores)(unsafe.Pointer(in.Keystores))
+	out.SecretKeysPerName = in.SecretKeysPerName
 	// TODO: Inefficient conversion - can we improve it?
 	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
 		return err
 	}
 	out.IsCA = in.IsCA
+	out.ExternalSigning = in.ExternalSigning
 	out.Usages = *(*[]v1alpha3.KeyUsage)(unsafe.Pointer(&in.Usages))
 	if in.PrivateKey != nil {
 		in, out := &in.PrivateKey, &out.PrivateKey
@@ -843,6 +939,52 @@ func Convert_certmanager_ClusterIssuerList_To_v1alpha3_ClusterIssuerList(in *cer
 	return autoConvert_certmanager_ClusterIssuerList_To_v1alpha3_ClusterIssuerList(in, out, s)
 }
 
+func autoConvert_v1alpha3_ComponentCertificateStatus_To_certmanager_ComponentCertificateStatus(in *v1alpha3.ComponentCertificateStatus, out *certmanager.ComponentCertificateStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.NotBefore = (*v1.Time)(unsafe.Pointer(in.NotBefore))
+	out.NotAfter = (*v1.Time)(unsafe.Pointer(in.NotAfter))
+	out.Conditions = *(*[]certmanager.SelfStatusCondition)(unsafe.Pointer(&in.Conditions))
+	return nil
+}
+
+// Convert_v1alpha3_ComponentCertificateStatus_To_certmanager_ComponentCertificateStatus is an autogenerated conversion function.
+func Convert_v1alpha3_ComponentCertificateStatus_To_certmanager_ComponentCertificateStatus(in *v1alpha3.ComponentCertificateStatus, out *certmanager.ComponentCertificateStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ComponentCertificateStatus_To_certmanager_ComponentCertificateStatus(in, out, s)
+}
+
+func autoConvert_certmanager_ComponentCertificateStatus_To_v1alpha3_ComponentCertificateStatus(in *certmanager.ComponentCertificateStatus, out *v1alpha3.ComponentCertificateStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.NotBefore = (*v1.Time)(unsafe.Pointer(in.NotBefore))
+	out.NotAfter = (*v1.Time)(unsafe.Pointer(in.NotAfter))
+	out.Conditions = *(*[]v1alpha3.SelfStatusCondition)(unsafe.Pointer(&in.Conditions))
+	return nil
+}
+
+// Convert_certmanager_ComponentCertificateStatus_To_v1alpha3_ComponentCertificateStatus is an autogenerated conversion function.
+func Convert_certmanager_ComponentCertificateStatus_To_v1alpha3_ComponentCertificateStatus(in *certmanager.ComponentCertificateStatus, out *v1alpha3.ComponentCertificateStatus, s conversion.Scope) error {
+	return autoConvert_certmanager_ComponentCertificateStatus_To_v1alpha3_ComponentCertificateStatus(in, out, s)
+}
+
+func autoConvert_v1alpha3_HTTPSKeyEscrow_To_certmanager_HTTPSKeyEscrow(in *v1alpha3.HTTPSKeyEscrow, out *certmanager.HTTPSKeyEscrow, s conversion.Scope) error {
+	out.URL = in.URL
+	return nil
+}
+
+// Convert_v1alpha3_HTTPSKeyEscrow_To_certmanager_HTTPSKeyEscrow is an autogenerated conversion function.
+func Convert_v1alpha3_HTTPSKeyEscrow_To_certmanager_HTTPSKeyEscrow(in *v1alpha3.HTTPSKeyEscrow, out *certmanager.HTTPSKeyEscrow, s conversion.Scope) error {
+	return autoConvert_v1alpha3_HTTPSKeyEscrow_To_certmanager_HTTPSKeyEscrow(in, out, s)
+}
+
+func autoConvert_certmanager_HTTPSKeyEscrow_To_v1alpha3_HTTPSKeyEscrow(in *certmanager.HTTPSKeyEscrow, out *v1alpha3.HTTPSKeyEscrow, s conversion.Scope) error {
+	out.URL = in.URL
+	return nil
+}
+
+// Convert_certmanager_HTTPSKeyEscrow_To_v1alpha3_HTTPSKeyEscrow is an autogenerated conversion function.
+func Convert_certmanager_HTTPSKeyEscrow_To_v1alpha3_HTTPSKeyEscrow(in *certmanager.HTTPSKeyEscrow, out *v1alpha3.HTTPSKeyEscrow, s conversion.Scope) error {
+	return autoConvert_certmanager_HTTPSKeyEscrow_To_v1alpha3_HTTPSKeyEscrow(in, out, s)
+}
+
 func autoConvert_v1alpha3_Issuer_To_certmanager_Issuer(in *v1alpha3.Issuer, out *certmanager.Issuer, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_IssuerSpec_To_certmanager_IssuerSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -957,6 +1099,9 @@ func autoConvert_v1alpha3_IssuerSpec_To_certmanager_IssuerSpec(in *v1alpha3.Issu
 	if err := Convert_v1alpha3_IssuerConfig_To_certmanager_IssuerConfig(&in.IssuerConfig, &out.IssuerConfig, s); err != nil {
 		return err
 	}
+	out.AllowedDNSSuffixes = *(*[]string)(unsafe.Pointer(&in.AllowedDNSSuffixes))
+	out.RequestDefaults = (*certmanager.RequestDefaults)(unsafe.Pointer(in.RequestDefaults))
+	out.KeyEscrow = (*certmanager.KeyEscrow)(unsafe.Pointer(in.KeyEscrow))
 	return nil
 }
 
@@ -969,6 +1114,9 @@ func autoConvert_certmanager_IssuerSpec_To_v1alpha3_IssuerSpec(in *certmanager.I
 	if err := Convert_certmanager_IssuerConfig_To_v1alpha3_IssuerConfig(&in.IssuerConfig, &out.IssuerConfig, s); err != nil {
 		return err
 	}
+	out.AllowedDNSSuffixes = *(*[]string)(unsafe.Pointer(&in.AllowedDNSSuffixes))
+	out.RequestDefaults = (*v1alpha3.RequestDefaults)(unsafe.Pointer(in.RequestDefaults))
+	out.KeyEscrow = (*v1alpha3.KeyEscrow)(unsafe.Pointer(in.KeyEscrow))
 	return nil
 }
 
@@ -1027,6 +1175,58 @@ func Convert_certmanager_JKSKeystore_To_v1alpha3_JKSKeystore(in *certmanager.JKS
 	return autoConvert_certmanager_JKSKeystore_To_v1alpha3_JKSKeystore(in, out, s)
 }
 
+func autoConvert_v1alpha3_KMIPKeyEscrow_To_certmanager_KMIPKeyEscrow(in *v1alpha3.KMIPKeyEscrow, out *certmanager.KMIPKeyEscrow, s conversion.Scope) error {
+	out.Server = in.Server
+	return nil
+}
+
+// Convert_v1alpha3_KMIPKeyEscrow_To_certmanager_KMIPKeyEscrow is an autogenerated conversion function.
+func Convert_v1alpha3_KMIPKeyEscrow_To_certmanager_KMIPKeyEscrow(in *v1alpha3.KMIPKeyEscrow, out *certmanager.KMIPKeyEscrow, s conversion.Scope) error {
+	return autoConvert_v1alpha3_KMIPKeyEscrow_To_certmanager_KMIPKeyEscrow(in, out, s)
+}
+
+func autoConvert_certmanager_KMIPKeyEscrow_To_v1alpha3_KMIPKeyEscrow(in *certmanager.KMIPKeyEscrow, out *v1alpha3.KMIPKeyEscrow, s conversion.Scope) error {
+	out.Server = in.Server
+	return nil
+}
+
+// Convert_certmanager_KMIPKeyEscrow_To_v1alpha3_KMIPKeyEscrow is an autogenerated conversion function.
+func Convert_certmanager_KMIPKeyEscrow_To_v1alpha3_KMIPKeyEscrow(in *certmanager.KMIPKeyEscrow, out *v1alpha3.KMIPKeyEscrow, s conversion.Scope) error {
+	return autoConvert_certmanager_KMIPKeyEscrow_To_v1alpha3_KMIPKeyEscrow(in, out, s)
+}
+
+func autoConvert_v1alpha3_KeyEscrow_To_certmanager_KeyEscrow(in *v1alpha3.KeyEscrow, out *certmanager.KeyEscrow, s conversion.Scope) error {
+	out.HTTPS = (*certmanager.HTTPSKeyEscrow)(unsafe.Pointer(in.HTTPS))
+	out.KMIP = (*certmanager.KMIPKeyEscrow)(unsafe.Pointer(in.KMIP))
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.ClientCertSecretRef, &out.ClientCertSecretRef, 0); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha3_KeyEscrow_To_certmanager_KeyEscrow is an autogenerated conversion function.
+func Convert_v1alpha3_KeyEscrow_To_certmanager_KeyEscrow(in *v1alpha3.KeyEscrow, out *certmanager.KeyEscrow, s conversion.Scope) error {
+	return autoConvert_v1alpha3_KeyEscrow_To_certmanager_KeyEscrow(in, out, s)
+}
+
+func autoConvert_certmanager_KeyEscrow_To_v1alpha3_KeyEscrow(in *certmanager.KeyEscrow, out *v1alpha3.KeyEscrow, s conversion.Scope) error {
+	out.HTTPS = (*v1alpha3.HTTPSKeyEscrow)(unsafe.Pointer(in.HTTPS))
+	out.KMIP = (*v1alpha3.KMIPKeyEscrow)(unsafe.Pointer(in.KMIP))
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.ClientCertSecretRef, &out.ClientCertSecretRef, 0); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_certmanager_KeyEscrow_To_v1alpha3_KeyEscrow is an autogenerated conversion function.
+func Convert_certmanager_KeyEscrow_To_v1alpha3_KeyEscrow(in *certmanager.KeyEscrow, out *v1alpha3.KeyEscrow, s conversion.Scope) error {
+	return autoConvert_certmanager_KeyEscrow_To_v1alpha3_KeyEscrow(in, out, s)
+}
+
 func autoConvert_v1alpha3_PKCS12Keystore_To_certmanager_PKCS12Keystore(in *v1alpha3.PKCS12Keystore, out *certmanager.PKCS12Keystore, s conversion.Scope) error {
 	out.Create = in.Create
 	// TODO: Inefficient conversion - can we improve it?
@@ -1055,6 +1255,30 @@ func Convert_certmanager_PKCS12Keystore_To_v1alpha3_PKCS12Keystore(in *certmanag
 	return autoConvert_certmanager_PKCS12Keystore_To_v1alpha3_PKCS12Keystore(in, out, s)
 }
 
+func autoConvert_v1alpha3_RequestDefaults_To_certmanager_RequestDefaults(in *v1alpha3.RequestDefaults, out *certmanager.RequestDefaults, s conversion.Scope) error {
+	out.CommonName = in.CommonName
+	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
+	out.URISANs = *(*[]string)(unsafe.Pointer(&in.URISANs))
+	return nil
+}
+
+// Convert_v1alpha3_RequestDefaults_To_certmanager_RequestDefaults is an autogenerated conversion function.
+func Convert_v1alpha3_RequestDefaults_To_certmanager_RequestDefaults(in *v1alpha3.RequestDefaults, out *certmanager.RequestDefaults, s conversion.Scope) error {
+	return autoConvert_v1alpha3_RequestDefaults_To_certmanager_RequestDefaults(in, out, s)
+}
+
+func autoConvert_certmanager_RequestDefaults_To_v1alpha3_RequestDefaults(in *certmanager.RequestDefaults, out *v1alpha3.RequestDefaults, s conversion.Scope) error {
+	out.CommonName = in.CommonName
+	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
+	out.URISANs = *(*[]string)(unsafe.Pointer(&in.URISANs))
+	return nil
+}
+
+// Convert_certmanager_RequestDefaults_To_v1alpha3_RequestDefaults is an autogenerated conversion function.
+func Convert_certmanager_RequestDefaults_To_v1alpha3_RequestDefaults(in *certmanager.RequestDefaults, out *v1alpha3.RequestDefaults, s conversion.Scope) error {
+	return autoConvert_certmanager_RequestDefaults_To_v1alpha3_RequestDefaults(in, out, s)
+}
+
 func autoConvert_v1alpha3_SelfSignedIssuer_To_certmanager_SelfSignedIssuer(in *v1alpha3.SelfSignedIssuer, out *certmanager.SelfSignedIssuer, s conversion.Scope) error {
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	return nil
@@ -1075,6 +1299,102 @@ func Convert_certmanager_SelfSignedIssuer_To_v1alpha3_SelfSignedIssuer(in *certm
 	return autoConvert_certmanager_SelfSignedIssuer_To_v1alpha3_SelfSignedIssuer(in, out, s)
 }
 
+func autoConvert_v1alpha3_SelfStatus_To_certmanager_SelfStatus(in *v1alpha3.SelfStatus, out *certmanager.SelfStatus, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1alpha3_SelfStatusStatus_To_certmanager_SelfStatusStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha3_SelfStatus_To_certmanager_SelfStatus is an autogenerated conversion function.
+func Convert_v1alpha3_SelfStatus_To_certmanager_SelfStatus(in *v1alpha3.SelfStatus, out *certmanager.SelfStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha3_SelfStatus_To_certmanager_SelfStatus(in, out, s)
+}
+
+func autoConvert_certmanager_SelfStatus_To_v1alpha3_SelfStatus(in *certmanager.SelfStatus, out *v1alpha3.SelfStatus, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_certmanager_SelfStatusStatus_To_v1alpha3_SelfStatusStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_certmanager_SelfStatus_To_v1alpha3_SelfStatus is an autogenerated conversion function.
+func Convert_certmanager_SelfStatus_To_v1alpha3_SelfStatus(in *certmanager.SelfStatus, out *v1alpha3.SelfStatus, s conversion.Scope) error {
+	return autoConvert_certmanager_SelfStatus_To_v1alpha3_SelfStatus(in, out, s)
+}
+
+func autoConvert_v1alpha3_SelfStatusCondition_To_certmanager_SelfStatusCondition(in *v1alpha3.SelfStatusCondition, out *certmanager.SelfStatusCondition, s conversion.Scope) error {
+	out.Type = certmanager.SelfStatusConditionType(in.Type)
+	out.Status = meta.ConditionStatus(in.Status)
+	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1alpha3_SelfStatusCondition_To_certmanager_SelfStatusCondition is an autogenerated conversion function.
+func Convert_v1alpha3_SelfStatusCondition_To_certmanager_SelfStatusCondition(in *v1alpha3.SelfStatusCondition, out *certmanager.SelfStatusCondition, s conversion.Scope) error {
+	return autoConvert_v1alpha3_SelfStatusCondition_To_certmanager_SelfStatusCondition(in, out, s)
+}
+
+func autoConvert_certmanager_SelfStatusCondition_To_v1alpha3_SelfStatusCondition(in *certmanager.SelfStatusCondition, out *v1alpha3.SelfStatusCondition, s conversion.Scope) error {
+	out.Type = v1alpha3.SelfStatusConditionType(in.Type)
+	out.Status = metav1.ConditionStatus(in.Status)
+	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_certmanager_SelfStatusCondition_To_v1alpha3_SelfStatusCondition is an autogenerated conversion function.
+func Convert_certmanager_SelfStatusCondition_To_v1alpha3_SelfStatusCondition(in *certmanager.SelfStatusCondition, out *v1alpha3.SelfStatusCondition, s conversion.Scope) error {
+	return autoConvert_certmanager_SelfStatusCondition_To_v1alpha3_SelfStatusCondition(in, out, s)
+}
+
+func autoConvert_v1alpha3_SelfStatusList_To_certmanager_SelfStatusList(in *v1alpha3.SelfStatusList, out *certmanager.SelfStatusList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]certmanager.SelfStatus)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1alpha3_SelfStatusList_To_certmanager_SelfStatusList is an autogenerated conversion function.
+func Convert_v1alpha3_SelfStatusList_To_certmanager_SelfStatusList(in *v1alpha3.SelfStatusList, out *certmanager.SelfStatusList, s conversion.Scope) error {
+	return autoConvert_v1alpha3_SelfStatusList_To_certmanager_SelfStatusList(in, out, s)
+}
+
+func autoConvert_certmanager_SelfStatusList_To_v1alpha3_SelfStatusList(in *certmanager.SelfStatusList, out *v1alpha3.SelfStatusList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]v1alpha3.SelfStatus)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_certmanager_SelfStatusList_To_v1alpha3_SelfStatusList is an autogenerated conversion function.
+func Convert_certmanager_SelfStatusList_To_v1alpha3_SelfStatusList(in *certmanager.SelfStatusList, out *v1alpha3.SelfStatusList, s conversion.Scope) error {
+	return autoConvert_certmanager_SelfStatusList_To_v1alpha3_SelfStatusList(in, out, s)
+}
+
+func autoConvert_v1alpha3_SelfStatusStatus_To_certmanager_SelfStatusStatus(in *v1alpha3.SelfStatusStatus, out *certmanager.SelfStatusStatus, s conversion.Scope) error {
+	out.Components = *(*[]certmanager.ComponentCertificateStatus)(unsafe.Pointer(&in.Components))
+	return nil
+}
+
+// Convert_v1alpha3_SelfStatusStatus_To_certmanager_SelfStatusStatus is an autogenerated conversion function.
+func Convert_v1alpha3_SelfStatusStatus_To_certmanager_SelfStatusStatus(in *v1alpha3.SelfStatusStatus, out *certmanager.SelfStatusStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha3_SelfStatusStatus_To_certmanager_SelfStatusStatus(in, out, s)
+}
+
+func autoConvert_certmanager_SelfStatusStatus_To_v1alpha3_SelfStatusStatus(in *certmanager.SelfStatusStatus, out *v1alpha3.SelfStatusStatus, s conversion.Scope) error {
+	out.Components = *(*[]v1alpha3.ComponentCertificateStatus)(unsafe.Pointer(&in.Components))
+	return nil
+}
+
+// Convert_certmanager_SelfStatusStatus_To_v1alpha3_SelfStatusStatus is an autogenerated conversion function.
+func Convert_certmanager_SelfStatusStatus_To_v1alpha3_SelfStatusStatus(in *certmanager.SelfStatusStatus, out *v1alpha3.SelfStatusStatus, s conversion.Scope) error {
+	return autoConvert_certmanager_SelfStatusStatus_To_v1alpha3_SelfStatusStatus(in, out, s)
+}
+
 func autoConvert_v1alpha3_VaultAppRole_To_certmanager_VaultAppRole(in *v1alpha3.VaultAppRole, out *certmanager.VaultAppRole, s conversion.Scope) error {
 	out.Path = in.Path
 	out.RoleId = in.RoleId
@@ -1137,6 +1457,9 @@ func autoConvert_v1alpha3_VaultIssuer_To_certmanager_VaultIssuer(in *v1alpha3.Va
 	out.Path = in.Path
 	out.Namespace = in.Namespace
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	out.SigningMode = certmanager.VaultSigningMode(in.SigningMode)
+	out.IssuerRef = in.IssuerRef
+	out.ExcludeCNFromSANs = (*bool)(unsafe.Pointer(in.ExcludeCNFromSANs))
 	return nil
 }
 
@@ -1153,6 +1476,9 @@ func autoConvert_certmanager_VaultIssuer_To_v1alpha3_VaultIssuer(in *certmanager
 	out.Path = in.Path
 	out.Namespace = in.Namespace
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	out.SigningMode = v1alpha3.VaultSigningMode(in.SigningMode)
+	out.IssuerRef = in.IssuerRef
+	out.ExcludeCNFromSANs = (*bool)(unsafe.Pointer(in.ExcludeCNFromSANs))
 	return nil
 }
 
@@ -191,6 +191,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ComponentCertificateStatus)(nil), (*certmanager.ComponentCertificateStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ComponentCertificateStatus_To_certmanager_ComponentCertificateStatus(a.(*v1beta1.ComponentCertificateStatus), b.(*certmanager.ComponentCertificateStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.ComponentCertificateStatus)(nil), (*v1beta1.ComponentCertificateStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_ComponentCertificateStatus_To_v1beta1_ComponentCertificateStatus(a.(*certmanager.ComponentCertificateStatus), b.(*v1beta1.ComponentCertificateStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.HTTPSKeyEscrow)(nil), (*certmanager.HTTPSKeyEscrow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_HTTPSKeyEscrow_To_certmanager_HTTPSKeyEscrow(a.(*v1beta1.HTTPSKeyEscrow), b.(*certmanager.HTTPSKeyEscrow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.HTTPSKeyEscrow)(nil), (*v1beta1.HTTPSKeyEscrow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_HTTPSKeyEscrow_To_v1beta1_HTTPSKeyEscrow(a.(*certmanager.HTTPSKeyEscrow), b.(*v1beta1.HTTPSKeyEscrow), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.Issuer)(nil), (*certmanager.Issuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_Issuer_To_certmanager_Issuer(a.(*v1beta1.Issuer), b.(*certmanager.Issuer), scope)
 	}); err != nil {
@@ -261,6 +281,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.KMIPKeyEscrow)(nil), (*certmanager.KMIPKeyEscrow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_KMIPKeyEscrow_To_certmanager_KMIPKeyEscrow(a.(*v1beta1.KMIPKeyEscrow), b.(*certmanager.KMIPKeyEscrow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.KMIPKeyEscrow)(nil), (*v1beta1.KMIPKeyEscrow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_KMIPKeyEscrow_To_v1beta1_KMIPKeyEscrow(a.(*certmanager.KMIPKeyEscrow), b.(*v1beta1.KMIPKeyEscrow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.KeyEscrow)(nil), (*certmanager.KeyEscrow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_KeyEscrow_To_certmanager_KeyEscrow(a.(*v1beta1.KeyEscrow), b.(*certmanager.KeyEscrow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.KeyEscrow)(nil), (*v1beta1.KeyEscrow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_KeyEscrow_To_v1beta1_KeyEscrow(a.(*certmanager.KeyEscrow), b.(*v1beta1.KeyEscrow), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.PKCS12Keystore)(nil), (*certmanager.PKCS12Keystore)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_PKCS12Keystore_To_certmanager_PKCS12Keystore(a.(*v1beta1.PKCS12Keystore), b.(*certmanager.PKCS12Keystore), scope)
 	}); err != nil {
@@ -271,6 +311,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.RequestDefaults)(nil), (*certmanager.RequestDefaults)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RequestDefaults_To_certmanager_RequestDefaults(a.(*v1beta1.RequestDefaults), b.(*certmanager.RequestDefaults), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.RequestDefaults)(nil), (*v1beta1.RequestDefaults)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_RequestDefaults_To_v1beta1_RequestDefaults(a.(*certmanager.RequestDefaults), b.(*v1beta1.RequestDefaults), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.SelfSignedIssuer)(nil), (*certmanager.SelfSignedIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_SelfSignedIssuer_To_certmanager_SelfSignedIssuer(a.(*v1beta1.SelfSignedIssuer), b.(*certmanager.SelfSignedIssuer), scope)
 	}); err != nil {
@@ -281,6 +331,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.SelfStatus)(nil), (*certmanager.SelfStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_SelfStatus_To_certmanager_SelfStatus(a.(*v1beta1.SelfStatus), b.(*certmanager.SelfStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.SelfStatus)(nil), (*v1beta1.SelfStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_SelfStatus_To_v1beta1_SelfStatus(a.(*certmanager.SelfStatus), b.(*v1beta1.SelfStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.SelfStatusCondition)(nil), (*certmanager.SelfStatusCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_SelfStatusCondition_To_certmanager_SelfStatusCondition(a.(*v1beta1.SelfStatusCondition), b.(*certmanager.SelfStatusCondition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.SelfStatusCondition)(nil), (*v1beta1.SelfStatusCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_SelfStatusCondition_To_v1beta1_SelfStatusCondition(a.(*certmanager.SelfStatusCondition), b.(*v1beta1.SelfStatusCondition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.SelfStatusList)(nil), (*certmanager.SelfStatusList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_SelfStatusList_To_certmanager_SelfStatusList(a.(*v1beta1.SelfStatusList), b.(*certmanager.SelfStatusList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.SelfStatusList)(nil), (*v1beta1.SelfStatusList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_SelfStatusList_To_v1beta1_SelfStatusList(a.(*certmanager.SelfStatusList), b.(*v1beta1.SelfStatusList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.SelfStatusStatus)(nil), (*certmanager.SelfStatusStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_SelfStatusStatus_To_certmanager_SelfStatusStatus(a.(*v1beta1.SelfStatusStatus), b.(*certmanager.SelfStatusStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.SelfStatusStatus)(nil), (*v1beta1.SelfStatusStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_SelfStatusStatus_To_v1beta1_SelfStatusStatus(a.(*certmanager.SelfStatusStatus), b.(*v1beta1.SelfStatusStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.VaultAppRole)(nil), (*certmanager.VaultAppRole)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_VaultAppRole_To_certmanager_VaultAppRole(a.(*v1beta1.VaultAppRole), b.(*certmanager.VaultAppRole), scope)
 	}); err != nil {
@@ -661,6 +751,7 @@ func Convert_certmanager_CertificateRequestStatus_To_v1beta1_CertificateRequestS
 func autoConvert_v1beta1_CertificateSpec_To_certmanager_CertificateSpec(in *v1beta1.CertificateSpec, out *certmanager.CertificateSpec, s conversion.Scope) error {
 	out.Subject = (*certmanager.X509Subject)(unsafe.Pointer(in.Subject))
 	out.CommonName = in.CommonName
+	out.SubjectAltNamesPolicy = certmanager.SubjectAltNamesPolicy(in.SubjectAltNamesPolicy)
 	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
 	out.RenewBefore = (*v1.Duration)(unsafe.Pointer(in.RenewBefore))
 	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
@@ -669,11 +760,13 @@ func autoConvert_v1beta1_CertificateSpec_To_certmanager_CertificateSpec(in *v1be
 	out.EmailSANs = *(*[]string)(unsafe.Pointer(&in.EmailSANs))
 	out.SecretName = in.SecretName
 	out.Keystores = (*certmanager.CertificateKeystores)(unsafe.Pointer(in.Keystores))
+	out.SecretKeysPerName = in.SecretKeysPerName
 	// TODO: Inefficient conversion - can we improve it?
 	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
 		return err
 	}
 	out.IsCA = in.IsCA
+	out.ExternalSigning = in.ExternalSigning
 	out.Usages = *(*[]certmanager.KeyUsage)(unsafe.Pointer(&in.Usages))
 	out.PrivateKey = (*certmanager.CertificatePrivateKey)(unsafe.Pointer(in.PrivateKey))
 	return nil
@@ -687,6 +780,7 @@ func Convert_v1beta1_CertificateSpec_To_certmanager_CertificateSpec(in *v1beta1.
 func autoConvert_certmanager_CertificateSpec_To_v1beta1_CertificateSpec(in *certmanager.CertificateSpec, out *v1beta1.CertificateSpec, s conversion.Scope) error {
 	out.Subject = (*v1beta1.X509Subject)(unsafe.Pointer(in.Subject))
 	out.CommonName = in.CommonName
+	out.SubjectAltNamesPolicy = v1beta1.SubjectAltNamesPolicy(in.SubjectAltNamesPolicy)
 	out.Duration = (*v1.Duration)(unsafe.Pointer(in.Duration))
 	out.RenewBefore = (*v1.Duration)(unsafe.Pointer(in.RenewBefore))
 	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
@@ -695,11 +789,13 @@ func autoConvert_certmanager_CertificateSpec_To_v1beta1_CertificateSpec(in *cert
 	out.EmailSANs = *(*[]string)(unsafe.Pointer(&in.EmailSANs))
 	out.SecretName = in.SecretName
 	out.Keystores = (*v1beta1.CertificateKeystores)(unsafe.Pointer(in.Keystores))
+	out.SecretKeysPerName = in.SecretKeysPerName
 	// TODO: Inefficient conversion - can we improve it?
 	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
 		return err
 	}
 	out.IsCA = in.IsCA
+	out.ExternalSigning = in.ExternalSigning
 	out.Usages = *(*[]v1beta1.KeyUsage)(unsafe.Pointer(&in.Usages))
 	out.PrivateKey = (*v1beta1.CertificatePrivateKey)(unsafe.Pointer(in.PrivateKey))
 	return nil
@@ -796,6 +892,52 @@ func Convert_certmanager_ClusterIssuerList_To_v1beta1_ClusterIssuerList(in *cert
 	return autoConvert_certmanager_ClusterIssuerList_To_v1beta1_ClusterIssuerList(in, out, s)
 }
 
+func autoConvert_v1beta1_ComponentCertificateStatus_To_certmanager_ComponentCertificateStatus(in *v1beta1.ComponentCertificateStatus, out *certmanager.ComponentCertificateStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.NotBefore = (*v1.Time)(unsafe.Pointer(in.NotBefore))
+	out.NotAfter = (*v1.Time)(unsafe.Pointer(in.NotAfter))
+	out.Conditions = *(*[]certmanager.SelfStatusCondition)(unsafe.Pointer(&in.Conditions))
+	return nil
+}
+
+// Convert_v1beta1_ComponentCertificateStatus_To_certmanager_ComponentCertificateStatus is an autogenerated conversion function.
+func Convert_v1beta1_ComponentCertificateStatus_To_certmanager_ComponentCertificateStatus(in *v1beta1.ComponentCertificateStatus, out *certmanager.ComponentCertificateStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_ComponentCertificateStatus_To_certmanager_ComponentCertificateStatus(in, out, s)
+}
+
+func autoConvert_certmanager_ComponentCertificateStatus_To_v1beta1_ComponentCertificateStatus(in *certmanager.ComponentCertificateStatus, out *v1beta1.ComponentCertificateStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.NotBefore = (*v1.Time)(unsafe.Pointer(in.NotBefore))
+	out.NotAfter = (*v1.Time)(unsafe.Pointer(in.NotAfter))
+	out.Conditions = *(*[]v1beta1.SelfStatusCondition)(unsafe.Pointer(&in.Conditions))
+	return nil
+}
+
+// Convert_certmanager_ComponentCertificateStatus_To_v1beta1_ComponentCertificateStatus is an autogenerated conversion function.
+func Convert_certmanager_ComponentCertificateStatus_To_v1beta1_ComponentCertificateStatus(in *certmanager.ComponentCertificateStatus, out *v1beta1.ComponentCertificateStatus, s conversion.Scope) error {
+	return autoConvert_certmanager_ComponentCertificateStatus_To_v1beta1_ComponentCertificateStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_HTTPSKeyEscrow_To_certmanager_HTTPSKeyEscrow(in *v1beta1.HTTPSKeyEscrow, out *certmanager.HTTPSKeyEscrow, s conversion.Scope) error {
+	out.URL = in.URL
+	return nil
+}
+
+// Convert_v1beta1_HTTPSKeyEscrow_To_certmanager_HTTPSKeyEscrow is an autogenerated conversion function.
+func Convert_v1beta1_HTTPSKeyEscrow_To_certmanager_HTTPSKeyEscrow(in *v1beta1.HTTPSKeyEscrow, out *certmanager.HTTPSKeyEscrow, s conversion.Scope) error {
+	return autoConvert_v1beta1_HTTPSKeyEscrow_To_certmanager_HTTPSKeyEscrow(in, out, s)
+}
+
+func autoConvert_certmanager_HTTPSKeyEscrow_To_v1beta1_HTTPSKeyEscrow(in *certmanager.HTTPSKeyEscrow, out *v1beta1.HTTPSKeyEscrow, s conversion.Scope) error {
+	out.URL = in.URL
+	return nil
+}
+
+// Convert_certmanager_HTTPSKeyEscrow_To_v1beta1_HTTPSKeyEscrow is an autogenerated conversion function.
+func Convert_certmanager_HTTPSKeyEscrow_To_v1beta1_HTTPSKeyEscrow(in *certmanager.HTTPSKeyEscrow, out *v1beta1.HTTPSKeyEscrow, s conversion.Scope) error {
+	return autoConvert_certmanager_HTTPSKeyEscrow_To_v1beta1_HTTPSKeyEscrow(in, out, s)
+}
+
 func autoConvert_v1beta1_Issuer_To_certmanager_Issuer(in *v1beta1.Issuer, out *certmanager.Issuer, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1beta1_IssuerSpec_To_certmanager_IssuerSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -910,6 +1052,9 @@ func autoConvert_v1beta1_IssuerSpec_To_certmanager_IssuerSpec(in *v1beta1.Issuer
 	if err := Convert_v1beta1_IssuerConfig_To_certmanager_IssuerConfig(&in.IssuerConfig, &out.IssuerConfig, s); err != nil {
 		return err
 	}
+	out.AllowedDNSSuffixes = *(*[]string)(unsafe.Pointer(&in.AllowedDNSSuffixes))
+	out.RequestDefaults = (*certmanager.RequestDefaults)(unsafe.Pointer(in.RequestDefaults))
+	out.KeyEscrow = (*certmanager.KeyEscrow)(unsafe.Pointer(in.KeyEscrow))
 	return nil
 }
 
@@ -922,6 +1067,9 @@ func autoConvert_certmanager_IssuerSpec_To_v1beta1_IssuerSpec(in *certmanager.Is
 	if err := Convert_certmanager_IssuerConfig_To_v1beta1_IssuerConfig(&in.IssuerConfig, &out.IssuerConfig, s); err != nil {
 		return err
 	}
+	out.AllowedDNSSuffixes = *(*[]string)(unsafe.Pointer(&in.AllowedDNSSuffixes))
+	out.RequestDefaults = (*v1beta1.RequestDefaults)(unsafe.Pointer(in.RequestDefaults))
+	out.KeyEscrow = (*v1beta1.KeyEscrow)(unsafe.Pointer(in.KeyEscrow))
 	return nil
 }
 
@@ -980,6 +1128,58 @@ func Convert_certmanager_JKSKeystore_To_v1beta1_JKSKeystore(in *certmanager.JKSK
 	return autoConvert_certmanager_JKSKeystore_To_v1beta1_JKSKeystore(in, out, s)
 }
 
+func autoConvert_v1beta1_KMIPKeyEscrow_To_certmanager_KMIPKeyEscrow(in *v1beta1.KMIPKeyEscrow, out *certmanager.KMIPKeyEscrow, s conversion.Scope) error {
+	out.Server = in.Server
+	return nil
+}
+
+// Convert_v1beta1_KMIPKeyEscrow_To_certmanager_KMIPKeyEscrow is an autogenerated conversion function.
+func Convert_v1beta1_KMIPKeyEscrow_To_certmanager_KMIPKeyEscrow(in *v1beta1.KMIPKeyEscrow, out *certmanager.KMIPKeyEscrow, s conversion.Scope) error {
+	return autoConvert_v1beta1_KMIPKeyEscrow_To_certmanager_KMIPKeyEscrow(in, out, s)
+}
+
+func autoConvert_certmanager_KMIPKeyEscrow_To_v1beta1_KMIPKeyEscrow(in *certmanager.KMIPKeyEscrow, out *v1beta1.KMIPKeyEscrow, s conversion.Scope) error {
+	out.Server = in.Server
+	return nil
+}
+
+// Convert_certmanager_KMIPKeyEscrow_To_v1beta1_KMIPKeyEscrow is an autogenerated conversion function.
+func Convert_certmanager_KMIPKeyEscrow_To_v1beta1_KMIPKeyEscrow(in *certmanager.KMIPKeyEscrow, out *v1beta1.KMIPKeyEscrow, s conversion.Scope) error {
+	return autoConvert_certmanager_KMIPKeyEscrow_To_v1beta1_KMIPKeyEscrow(in, out, s)
+}
+
+func autoConvert_v1beta1_KeyEscrow_To_certmanager_KeyEscrow(in *v1beta1.KeyEscrow, out *certmanager.KeyEscrow, s conversion.Scope) error {
+	out.HTTPS = (*certmanager.HTTPSKeyEscrow)(unsafe.Pointer(in.HTTPS))
+	out.KMIP = (*certmanager.KMIPKeyEscrow)(unsafe.Pointer(in.KMIP))
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.ClientCertSecretRef, &out.ClientCertSecretRef, 0); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_KeyEscrow_To_certmanager_KeyEscrow is an autogenerated conversion function.
+func Convert_v1beta1_KeyEscrow_To_certmanager_KeyEscrow(in *v1beta1.KeyEscrow, out *certmanager.KeyEscrow, s conversion.Scope) error {
+	return autoConvert_v1beta1_KeyEscrow_To_certmanager_KeyEscrow(in, out, s)
+}
+
+func autoConvert_certmanager_KeyEscrow_To_v1beta1_KeyEscrow(in *certmanager.KeyEscrow, out *v1beta1.KeyEscrow, s conversion.Scope) error {
+	out.HTTPS = (*v1beta1.HTTPSKeyEscrow)(unsafe.Pointer(in.HTTPS))
+	out.KMIP = (*v1beta1.KMIPKeyEscrow)(unsafe.Pointer(in.KMIP))
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.ClientCertSecretRef, &out.ClientCertSecretRef, 0); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_certmanager_KeyEscrow_To_v1beta1_KeyEscrow is an autogenerated conversion function.
+func Convert_certmanager_KeyEscrow_To_v1beta1_KeyEscrow(in *certmanager.KeyEscrow, out *v1beta1.KeyEscrow, s conversion.Scope) error {
+	return autoConvert_certmanager_KeyEscrow_To_v1beta1_KeyEscrow(in, out, s)
+}
+
 func autoConvert_v1beta1_PKCS12Keystore_To_certmanager_PKCS12Keystore(in *v1beta1.PKCS12Keystore, out *certmanager.PKCS12Keystore, s conversion.Scope) error {
 	out.Create = in.Create
 	// TODO: Inefficient conversion - can we improve it?
@@ -1008,6 +1208,30 @@ func Convert_certmanager_PKCS12Keystore_To_v1beta1_PKCS12Keystore(in *certmanage
 	return autoConvert_certmanager_PKCS12Keystore_To_v1beta1_PKCS12Keystore(in, out, s)
 }
 
+func autoConvert_v1beta1_RequestDefaults_To_certmanager_RequestDefaults(in *v1beta1.RequestDefaults, out *certmanager.RequestDefaults, s conversion.Scope) error {
+	out.CommonName = in.CommonName
+	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
+	out.URISANs = *(*[]string)(unsafe.Pointer(&in.URISANs))
+	return nil
+}
+
+// Convert_v1beta1_RequestDefaults_To_certmanager_RequestDefaults is an autogenerated conversion function.
+func Convert_v1beta1_RequestDefaults_To_certmanager_RequestDefaults(in *v1beta1.RequestDefaults, out *certmanager.RequestDefaults, s conversion.Scope) error {
+	return autoConvert_v1beta1_RequestDefaults_To_certmanager_RequestDefaults(in, out, s)
+}
+
+func autoConvert_certmanager_RequestDefaults_To_v1beta1_RequestDefaults(in *certmanager.RequestDefaults, out *v1beta1.RequestDefaults, s conversion.Scope) error {
+	out.CommonName = in.CommonName
+	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
+	out.URISANs = *(*[]string)(unsafe.Pointer(&in.URISANs))
+	return nil
+}
+
+// Convert_certmanager_RequestDefaults_To_v1beta1_RequestDefaults is an autogenerated conversion function.
+func Convert_certmanager_RequestDefaults_To_v1beta1_RequestDefaults(in *certmanager.RequestDefaults, out *v1beta1.RequestDefaults, s conversion.Scope) error {
+	return autoConvert_certmanager_RequestDefaults_To_v1beta1_RequestDefaults(in, out, s)
+}
+
 func autoConvert_v1beta1_SelfSignedIssuer_To_certmanager_SelfSignedIssuer(in *v1beta1.SelfSignedIssuer, out *certmanager.SelfSignedIssuer, s conversion.Scope) error {
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	return nil
@@ -1028,6 +1252,102 @@ func Convert_certmanager_SelfSignedIssuer_To_v1beta1_SelfSignedIssuer(in *certma
 	return autoConvert_certmanager_SelfSignedIssuer_To_v1beta1_SelfSignedIssuer(in, out, s)
 }
 
+func autoConvert_v1beta1_SelfStatus_To_certmanager_SelfStatus(in *v1beta1.SelfStatus, out *certmanager.SelfStatus, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_SelfStatusStatus_To_certmanager_SelfStatusStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_SelfStatus_To_certmanager_SelfStatus is an autogenerated conversion function.
+func Convert_v1beta1_SelfStatus_To_certmanager_SelfStatus(in *v1beta1.SelfStatus, out *certmanager.SelfStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_SelfStatus_To_certmanager_SelfStatus(in, out, s)
+}
+
+func autoConvert_certmanager_SelfStatus_To_v1beta1_SelfStatus(in *certmanager.SelfStatus, out *v1beta1.SelfStatus, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_certmanager_SelfStatusStatus_To_v1beta1_SelfStatusStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_certmanager_SelfStatus_To_v1beta1_SelfStatus is an autogenerated conversion function.
+func Convert_certmanager_SelfStatus_To_v1beta1_SelfStatus(in *certmanager.SelfStatus, out *v1beta1.SelfStatus, s conversion.Scope) error {
+	return autoConvert_certmanager_SelfStatus_To_v1beta1_SelfStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_SelfStatusCondition_To_certmanager_SelfStatusCondition(in *v1beta1.SelfStatusCondition, out *certmanager.SelfStatusCondition, s conversion.Scope) error {
+	out.Type = certmanager.SelfStatusConditionType(in.Type)
+	out.Status = meta.ConditionStatus(in.Status)
+	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1beta1_SelfStatusCondition_To_certmanager_SelfStatusCondition is an autogenerated conversion function.
+func Convert_v1beta1_SelfStatusCondition_To_certmanager_SelfStatusCondition(in *v1beta1.SelfStatusCondition, out *certmanager.SelfStatusCondition, s conversion.Scope) error {
+	return autoConvert_v1beta1_SelfStatusCondition_To_certmanager_SelfStatusCondition(in, out, s)
+}
+
+func autoConvert_certmanager_SelfStatusCondition_To_v1beta1_SelfStatusCondition(in *certmanager.SelfStatusCondition, out *v1beta1.SelfStatusCondition, s conversion.Scope) error {
+	out.Type = v1beta1.SelfStatusConditionType(in.Type)
+	out.Status = metav1.ConditionStatus(in.Status)
+	out.LastTransitionTime = (*v1.Time)(unsafe.Pointer(in.LastTransitionTime))
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_certmanager_SelfStatusCondition_To_v1beta1_SelfStatusCondition is an autogenerated conversion function.
+func Convert_certmanager_SelfStatusCondition_To_v1beta1_SelfStatusCondition(in *certmanager.SelfStatusCondition, out *v1beta1.SelfStatusCondition, s conversion.Scope) error {
+	return autoConvert_certmanager_SelfStatusCondition_To_v1beta1_SelfStatusCondition(in, out, s)
+}
+
+func autoConvert_v1beta1_SelfStatusList_To_certmanager_SelfStatusList(in *v1beta1.SelfStatusList, out *certmanager.SelfStatusList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]certmanager.SelfStatus)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1beta1_SelfStatusList_To_certmanager_SelfStatusList is an autogenerated conversion function.
+func Convert_v1beta1_SelfStatusList_To_certmanager_SelfStatusList(in *v1beta1.SelfStatusList, out *certmanager.SelfStatusList, s conversion.Scope) error {
+	return autoConvert_v1beta1_SelfStatusList_To_certmanager_SelfStatusList(in, out, s)
+}
+
+func autoConvert_certmanager_SelfStatusList_To_v1beta1_SelfStatusList(in *certmanager.SelfStatusList, out *v1beta1.SelfStatusList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]v1beta1.SelfStatus)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_certmanager_SelfStatusList_To_v1beta1_SelfStatusList is an autogenerated conversion function.
+func Convert_certmanager_SelfStatusList_To_v1beta1_SelfStatusList(in *certmanager.SelfStatusList, out *v1beta1.SelfStatusList, s conversion.Scope) error {
+	return autoConvert_certmanager_SelfStatusList_To_v1beta1_SelfStatusList(in, out, s)
+}
+
+func autoConvert_v1beta1_SelfStatusStatus_To_certmanager_SelfStatusStatus(in *v1beta1.SelfStatusStatus, out *certmanager.SelfStatusStatus, s conversion.Scope) error {
+	out.Components = *(*[]certmanager.ComponentCertificateStatus)(unsafe.Pointer(&in.Components))
+	return nil
+}
+
+// Convert_v1beta1_SelfStatusStatus_To_certmanager_SelfStatusStatus is an autogenerated conversion function.
+func Convert_v1beta1_SelfStatusStatus_To_certmanager_SelfStatusStatus(in *v1beta1.SelfStatusStatus, out *certmanager.SelfStatusStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_SelfStatusStatus_To_certmanager_SelfStatusStatus(in, out, s)
+}
+
+func autoConvert_certmanager_SelfStatusStatus_To_v1beta1_SelfStatusStatus(in *certmanager.SelfStatusStatus, out *v1beta1.SelfStatusStatus, s conversion.Scope) error {
+	out.Components = *(*[]v1beta1.ComponentCertificateStatus)(unsafe.Pointer(&in.Components))
+	return nil
+}
+
+// Convert_certmanager_SelfStatusStatus_To_v1beta1_SelfStatusStatus is an autogenerated conversion function.
+func Convert_certmanager_SelfStatusStatus_To_v1beta1_SelfStatusStatus(in *certmanager.SelfStatusStatus, out *v1beta1.SelfStatusStatus, s conversion.Scope) error {
+	return autoConvert_certmanager_SelfStatusStatus_To_v1beta1_SelfStatusStatus(in, out, s)
+}
+
 func autoConvert_v1beta1_VaultAppRole_To_certmanager_VaultAppRole(in *v1beta1.VaultAppRole, out *certmanager.VaultAppRole, s conversion.Scope) error {
 	out.Path = in.Path
 	out.RoleId = in.RoleId
@@ -1090,6 +1410,9 @@ func autoConvert_v1beta1_VaultIssuer_To_certmanager_VaultIssuer(in *v1beta1.Vaul
 	out.Path = in.Path
 	out.Namespace = in.Namespace
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	out.SigningMode = certmanager.VaultSigningMode(in.SigningMode)
+	out.IssuerRef = in.IssuerRef
+	out.ExcludeCNFromSANs = (*bool)(unsafe.Pointer(in.ExcludeCNFromSANs))
 	return nil
 }
 
@@ -1106,6 +1429,9 @@ func autoConvert_certmanager_VaultIssuer_To_v1beta1_VaultIssuer(in *certmanager.
 	out.Path = in.Path
 	out.Namespace = in.Namespace
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	out.SigningMode = v1beta1.VaultSigningMode(in.SigningMode)
+	out.IssuerRef = in.IssuerRef
+	out.ExcludeCNFromSANs = (*bool)(unsafe.Pointer(in.ExcludeCNFromSANs))
 	return nil
 }
 
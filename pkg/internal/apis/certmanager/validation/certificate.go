@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"net"
 	"net/mail"
+	"sort"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -49,6 +51,8 @@ func ValidateCertificateSpec(crt *cmapi.CertificateSpec, fldPath *field.Path) fi
 		el = append(el, field.TooLong(fldPath.Child("commonName"), crt.CommonName, 64))
 	}
 
+	el = append(el, validateSubjectAltNamesPolicy(crt, fldPath)...)
+
 	if len(crt.IPAddresses) > 0 {
 		el = append(el, validateIPAddresses(crt, fldPath)...)
 	}
@@ -72,6 +76,10 @@ func ValidateCertificateSpec(crt *cmapi.CertificateSpec, fldPath *field.Path) fi
 		}
 	}
 
+	if crt.ExternalSigning && !crt.IsCA {
+		el = append(el, field.Invalid(fldPath.Child("externalSigning"), crt.ExternalSigning, "is only supported if isCA is true"))
+	}
+
 	if crt.Duration != nil || crt.RenewBefore != nil {
 		el = append(el, ValidateDuration(crt, fldPath)...)
 	}
@@ -87,6 +95,46 @@ func ValidateCertificate(obj runtime.Object) field.ErrorList {
 	return allErrs
 }
 
+// ValidateCertificateCreate validates the annotations of a Certificate being
+// created.
+func ValidateCertificateCreate(obj runtime.Object, _ authenticationv1.UserInfo) field.ErrorList {
+	crt := obj.(*cmapi.Certificate)
+	return validateAnnotations(cmapi.CertificateKind, nil, crt.Annotations, field.NewPath("metadata", "annotations"))
+}
+
+// ValidateCertificateUpdate validates the annotations of a Certificate whose
+// values are changed by an update.
+func ValidateCertificateUpdate(oldObj, newObj runtime.Object) field.ErrorList {
+	old, ok := oldObj.(*cmapi.Certificate)
+	new := newObj.(*cmapi.Certificate)
+	// if oldObj is not set, the Update operation is always valid.
+	if !ok || old == nil {
+		return nil
+	}
+	return validateAnnotations(cmapi.CertificateKind, old.Annotations, new.Annotations, field.NewPath("metadata", "annotations"))
+}
+
+// validateAnnotations validates the values of all known cert-manager
+// annotations that apply to resources of the given kind. Only values that
+// differ from those in oldAnnotations are validated, so that resources that
+// carry a value accepted by an earlier release can still be updated.
+func validateAnnotations(kind string, oldAnnotations, annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	errs := util.ValidateAnnotations(kind, annotations)
+	keys := make([]string, 0, len(errs))
+	for k := range errs {
+		if oldValue, ok := oldAnnotations[k]; ok && oldValue == annotations[k] {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		el = append(el, field.Invalid(fldPath.Key(k), annotations[k], errs[k].Error()))
+	}
+	return el
+}
+
 func validateIssuerRef(issuerRef cmmeta.ObjectReference, fldPath *field.Path) field.ErrorList {
 	el := field.ErrorList{}
 
@@ -105,6 +153,41 @@ func validateIssuerRef(issuerRef cmmeta.ObjectReference, fldPath *field.Path) fi
 	return el
 }
 
+func validateSubjectAltNamesPolicy(crt *cmapi.CertificateSpec, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	policyPath := fldPath.Child("subjectAltNamesPolicy")
+	switch crt.SubjectAltNamesPolicy {
+	case "":
+	case cmapi.UseCommonNamePolicy:
+		if len(crt.CommonName) == 0 {
+			el = append(el, field.Required(fldPath.Child("commonName"), fmt.Sprintf("must be specified when subjectAltNamesPolicy is %s", cmapi.UseCommonNamePolicy)))
+		}
+	case cmapi.OmitCommonNamePolicy:
+		if len(crt.CommonName) > 0 {
+			el = append(el, field.Forbidden(fldPath.Child("commonName"), fmt.Sprintf("must not be specified when subjectAltNamesPolicy is %s", cmapi.OmitCommonNamePolicy)))
+		}
+	case cmapi.CopyFirstDNSNamePolicy:
+		if len(crt.CommonName) > 0 {
+			el = append(el, field.Forbidden(fldPath.Child("commonName"), fmt.Sprintf("must not be specified when subjectAltNamesPolicy is %s", cmapi.CopyFirstDNSNamePolicy)))
+		}
+		if len(crt.DNSNames) == 0 {
+			el = append(el, field.Required(fldPath.Child("dnsNames"), fmt.Sprintf("must be specified when subjectAltNamesPolicy is %s", cmapi.CopyFirstDNSNamePolicy)))
+		} else if len(crt.DNSNames[0]) > 64 {
+			// the first DNS name becomes the common name, so it is subject to
+			// the same length limit
+			el = append(el, field.TooLong(fldPath.Child("dnsNames").Index(0), crt.DNSNames[0], 64))
+		}
+	default:
+		el = append(el, field.NotSupported(policyPath, crt.SubjectAltNamesPolicy, []string{
+			string(cmapi.UseCommonNamePolicy),
+			string(cmapi.OmitCommonNamePolicy),
+			string(cmapi.CopyFirstDNSNamePolicy),
+		}))
+	}
+	return el
+}
+
 func validateIPAddresses(a *cmapi.CertificateSpec, fldPath *field.Path) field.ErrorList {
 	if len(a.IPAddresses) <= 0 {
 		return nil
@@ -19,9 +19,11 @@ package validation
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -484,6 +486,126 @@ func TestValidateCertificate(t *testing.T) {
 				field.Invalid(fldPath.Child("emailSANs").Index(0), "mailto:alice@example.com", "invalid email address: mail: expected comma"),
 			},
 		},
+		"valid certificate with UseCommonName subjectAltNamesPolicy": {
+			cfg: &cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					CommonName:            "testcn",
+					SubjectAltNamesPolicy: cmapi.UseCommonNamePolicy,
+					SecretName:            "abc",
+					IssuerRef:             validIssuerRef,
+				},
+			},
+		},
+		"invalid certificate with UseCommonName subjectAltNamesPolicy and no commonName": {
+			cfg: &cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					DNSNames:              []string{"example.com"},
+					SubjectAltNamesPolicy: cmapi.UseCommonNamePolicy,
+					SecretName:            "abc",
+					IssuerRef:             validIssuerRef,
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("commonName"), "must be specified when subjectAltNamesPolicy is UseCommonName"),
+			},
+		},
+		"valid certificate with OmitCommonName subjectAltNamesPolicy": {
+			cfg: &cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					DNSNames:              []string{"example.com"},
+					SubjectAltNamesPolicy: cmapi.OmitCommonNamePolicy,
+					SecretName:            "abc",
+					IssuerRef:             validIssuerRef,
+				},
+			},
+		},
+		"invalid certificate with OmitCommonName subjectAltNamesPolicy and a commonName": {
+			cfg: &cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					CommonName:            "testcn",
+					DNSNames:              []string{"example.com"},
+					SubjectAltNamesPolicy: cmapi.OmitCommonNamePolicy,
+					SecretName:            "abc",
+					IssuerRef:             validIssuerRef,
+				},
+			},
+			errs: []*field.Error{
+				field.Forbidden(fldPath.Child("commonName"), "must not be specified when subjectAltNamesPolicy is OmitCommonName"),
+			},
+		},
+		"valid certificate with CopyFirstDNSName subjectAltNamesPolicy": {
+			cfg: &cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					DNSNames:              []string{"example.com", "www.example.com"},
+					SubjectAltNamesPolicy: cmapi.CopyFirstDNSNamePolicy,
+					SecretName:            "abc",
+					IssuerRef:             validIssuerRef,
+				},
+			},
+		},
+		"invalid certificate with CopyFirstDNSName subjectAltNamesPolicy and no dnsNames": {
+			cfg: &cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					URISANs:               []string{"spiffe://example.com/workload"},
+					SubjectAltNamesPolicy: cmapi.CopyFirstDNSNamePolicy,
+					SecretName:            "abc",
+					IssuerRef:             validIssuerRef,
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("dnsNames"), "must be specified when subjectAltNamesPolicy is CopyFirstDNSName"),
+			},
+		},
+		"invalid certificate with CopyFirstDNSName subjectAltNamesPolicy and a too long first dnsName": {
+			cfg: &cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					DNSNames:              []string{strings.Repeat("a", 61) + ".com"},
+					SubjectAltNamesPolicy: cmapi.CopyFirstDNSNamePolicy,
+					SecretName:            "abc",
+					IssuerRef:             validIssuerRef,
+				},
+			},
+			errs: []*field.Error{
+				field.TooLong(fldPath.Child("dnsNames").Index(0), strings.Repeat("a", 61)+".com", 64),
+			},
+		},
+		"valid CA certificate with externalSigning": {
+			cfg: &cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					CommonName:      "intermediate-ca",
+					IsCA:            true,
+					ExternalSigning: true,
+					SecretName:      "abc",
+					IssuerRef:       validIssuerRef,
+				},
+			},
+		},
+		"invalid certificate with externalSigning that is not a CA": {
+			cfg: &cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					CommonName:      "testcn",
+					ExternalSigning: true,
+					SecretName:      "abc",
+					IssuerRef:       validIssuerRef,
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("externalSigning"), true, "is only supported if isCA is true"),
+			},
+		},
+		"invalid certificate with unknown subjectAltNamesPolicy": {
+			cfg: &cmapi.Certificate{
+				Spec: cmapi.CertificateSpec{
+					CommonName:            "testcn",
+					SubjectAltNamesPolicy: "Unknown",
+					SecretName:            "abc",
+					IssuerRef:             validIssuerRef,
+				},
+			},
+			errs: []*field.Error{
+				field.NotSupported(fldPath.Child("subjectAltNamesPolicy"), cmapi.SubjectAltNamesPolicy("Unknown"), []string{"UseCommonName", "OmitCommonName", "CopyFirstDNSName"}),
+			},
+		},
 	}
 	for n, s := range scenarios {
 		t.Run(n, func(t *testing.T) {
@@ -633,3 +755,49 @@ func TestValidateDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCertificateAnnotations(t *testing.T) {
+	withAnnotations := func(annotations map[string]string) *cmapi.Certificate {
+		return &cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+	valid := map[string]string{cmapi.PausedAnnotationKey: "true"}
+	invalid := map[string]string{cmapi.PausedAnnotationKey: "yes please"}
+
+	if errs := ValidateCertificateCreate(withAnnotations(valid), authenticationv1.UserInfo{}); len(errs) > 0 {
+		t.Errorf("expected no errors creating a Certificate with valid annotations, got: %v", errs)
+	}
+	if errs := ValidateCertificateCreate(withAnnotations(invalid), authenticationv1.UserInfo{}); len(errs) != 1 {
+		t.Errorf("expected an error creating a Certificate with an invalid annotation, got: %v", errs)
+	}
+
+	tests := map[string]struct {
+		old, new map[string]string
+		expErr   bool
+	}{
+		"updating a Certificate that carries a previously accepted value": {
+			old: invalid,
+			new: invalid,
+		},
+		"changing an annotation to an invalid value": {
+			old:    valid,
+			new:    invalid,
+			expErr: true,
+		},
+		"adding an invalid annotation": {
+			new:    invalid,
+			expErr: true,
+		},
+		"fixing an invalid annotation": {
+			old: invalid,
+			new: valid,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs := ValidateCertificateUpdate(withAnnotations(test.old), withAnnotations(test.new))
+			if test.expErr != (len(errs) > 0) {
+				t.Errorf("expected error=%t, got: %v", test.expErr, errs)
+			}
+		})
+	}
+}
@@ -19,19 +19,81 @@ package validation
 import (
 	"fmt"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+	cmmeta "github.com/jetstack/cert-manager/pkg/internal/apis/meta"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
 func ValidateCertificateRequest(obj runtime.Object) field.ErrorList {
 	cr := obj.(*cmapi.CertificateRequest)
 	allErrs := ValidateCertificateRequestSpec(&cr.Spec, field.NewPath("spec"))
+	allErrs = append(allErrs, validateApprovalConditions(cr.Status.Conditions, field.NewPath("status", "conditions"))...)
 	return allErrs
 }
 
+// ValidateCertificateRequestCreate validates the annotations of a
+// CertificateRequest being created.
+func ValidateCertificateRequestCreate(obj runtime.Object, _ authenticationv1.UserInfo) field.ErrorList {
+	cr := obj.(*cmapi.CertificateRequest)
+	return validateAnnotations(cmapi.CertificateRequestKind, nil, cr.Annotations, field.NewPath("metadata", "annotations"))
+}
+
+// ValidateCertificateRequestUpdate validates the annotations of a
+// CertificateRequest whose values are changed by an update, and that the
+// approval of a CertificateRequest is final: once it has been approved or
+// denied, the Approved or Denied condition cannot be removed or changed.
+func ValidateCertificateRequestUpdate(oldObj, newObj runtime.Object) field.ErrorList {
+	old, ok := oldObj.(*cmapi.CertificateRequest)
+	new := newObj.(*cmapi.CertificateRequest)
+	// if oldObj is not set, the Update operation is always valid.
+	if !ok || old == nil {
+		return nil
+	}
+
+	el := validateAnnotations(cmapi.CertificateRequestKind, old.Annotations, new.Annotations, field.NewPath("metadata", "annotations"))
+	fldPath := field.NewPath("status", "conditions")
+	for _, conditionType := range []cmapi.CertificateRequestConditionType{
+		cmapi.CertificateRequestConditionApproved,
+		cmapi.CertificateRequestConditionDenied,
+	} {
+		oldCond := certificateRequestCondition(old.Status.Conditions, conditionType)
+		if oldCond == nil || oldCond.Status != cmmeta.ConditionTrue {
+			continue
+		}
+		newCond := certificateRequestCondition(new.Status.Conditions, conditionType)
+		if newCond == nil || newCond.Status != oldCond.Status || newCond.Reason != oldCond.Reason || newCond.Message != oldCond.Message {
+			el = append(el, field.Forbidden(fldPath, fmt.Sprintf("the %s condition cannot be changed once set", conditionType)))
+		}
+	}
+	return el
+}
+
+// validateApprovalConditions validates that a CertificateRequest is not both
+// approved and denied.
+func validateApprovalConditions(conditions []cmapi.CertificateRequestCondition, fldPath *field.Path) field.ErrorList {
+	approved := certificateRequestCondition(conditions, cmapi.CertificateRequestConditionApproved)
+	denied := certificateRequestCondition(conditions, cmapi.CertificateRequestConditionDenied)
+	if approved != nil && denied != nil && approved.Status == cmmeta.ConditionTrue && denied.Status == cmmeta.ConditionTrue {
+		return field.ErrorList{field.Forbidden(fldPath, "a CertificateRequest cannot be both approved and denied")}
+	}
+	return nil
+}
+
+// certificateRequestCondition returns the condition of the given type, or
+// nil if there is none.
+func certificateRequestCondition(conditions []cmapi.CertificateRequestCondition, conditionType cmapi.CertificateRequestConditionType) *cmapi.CertificateRequestCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
 func ValidateCertificateRequestSpec(crSpec *cmapi.CertificateRequestSpec, fldPath *field.Path) field.ErrorList {
 	el := field.ErrorList{}
 
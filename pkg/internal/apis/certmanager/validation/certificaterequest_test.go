@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+	cmmeta "github.com/jetstack/cert-manager/pkg/internal/apis/meta"
+)
+
+func crWithConditions(conditions ...cmapi.CertificateRequestCondition) *cmapi.CertificateRequest {
+	return &cmapi.CertificateRequest{
+		Status: cmapi.CertificateRequestStatus{Conditions: conditions},
+	}
+}
+
+var (
+	approvedCondition = cmapi.CertificateRequestCondition{
+		Type:   cmapi.CertificateRequestConditionApproved,
+		Status: cmmeta.ConditionTrue,
+		Reason: "ByOperator",
+	}
+	deniedCondition = cmapi.CertificateRequestCondition{
+		Type:   cmapi.CertificateRequestConditionDenied,
+		Status: cmmeta.ConditionTrue,
+		Reason: "ByOperator",
+	}
+	readyCondition = cmapi.CertificateRequestCondition{
+		Type:   cmapi.CertificateRequestConditionReady,
+		Status: cmmeta.ConditionTrue,
+		Reason: "Issued",
+	}
+)
+
+func TestValidateApprovalConditions(t *testing.T) {
+	tests := map[string]struct {
+		cr     *cmapi.CertificateRequest
+		expErr bool
+	}{
+		"no approval conditions": {
+			cr: crWithConditions(readyCondition),
+		},
+		"approved": {
+			cr: crWithConditions(approvedCondition, readyCondition),
+		},
+		"denied": {
+			cr: crWithConditions(deniedCondition),
+		},
+		"approved and denied": {
+			cr:     crWithConditions(approvedCondition, deniedCondition),
+			expErr: true,
+		},
+		"approved and not denied": {
+			cr: crWithConditions(approvedCondition, cmapi.CertificateRequestCondition{
+				Type:   cmapi.CertificateRequestConditionDenied,
+				Status: cmmeta.ConditionFalse,
+			}),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs := validateApprovalConditions(test.cr.Status.Conditions, nil)
+			if test.expErr != (len(errs) > 0) {
+				t.Errorf("expected error=%t, got: %v", test.expErr, errs)
+			}
+		})
+	}
+}
+
+func TestValidateCertificateRequestUpdate(t *testing.T) {
+	withMessage := func(c cmapi.CertificateRequestCondition, message string) cmapi.CertificateRequestCondition {
+		c.Message = message
+		return c
+	}
+
+	withAnnotation := func(cr *cmapi.CertificateRequest, key, value string) *cmapi.CertificateRequest {
+		cr.Annotations = map[string]string{key: value}
+		return cr
+	}
+
+	tests := map[string]struct {
+		old, new *cmapi.CertificateRequest
+		expErr   bool
+	}{
+		"approving a request": {
+			old: crWithConditions(),
+			new: crWithConditions(approvedCondition),
+		},
+		"denying a request": {
+			old: crWithConditions(),
+			new: crWithConditions(deniedCondition),
+		},
+		"updating other conditions of an approved request": {
+			old: crWithConditions(approvedCondition),
+			new: crWithConditions(approvedCondition, readyCondition),
+		},
+		"removing the Approved condition": {
+			old:    crWithConditions(approvedCondition),
+			new:    crWithConditions(),
+			expErr: true,
+		},
+		"changing the message of the Denied condition": {
+			old:    crWithConditions(deniedCondition),
+			new:    crWithConditions(withMessage(deniedCondition, "changed")),
+			expErr: true,
+		},
+		"updating a request that carries a previously accepted annotation value": {
+			old: withAnnotation(crWithConditions(), cmapi.PausedAnnotationKey, "yes please"),
+			new: withAnnotation(crWithConditions(readyCondition), cmapi.PausedAnnotationKey, "yes please"),
+		},
+		"changing an annotation to an invalid value": {
+			old:    withAnnotation(crWithConditions(), cmapi.PausedAnnotationKey, "true"),
+			new:    withAnnotation(crWithConditions(), cmapi.PausedAnnotationKey, "yes please"),
+			expErr: true,
+		},
+		"denying an approved request": {
+			old: crWithConditions(approvedCondition),
+			new: crWithConditions(cmapi.CertificateRequestCondition{
+				Type:   cmapi.CertificateRequestConditionApproved,
+				Status: cmmeta.ConditionFalse,
+			}, deniedCondition),
+			expErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs := ValidateCertificateRequestUpdate(test.old, test.new)
+			if test.expErr != (len(errs) > 0) {
+				t.Errorf("expected error=%t, got: %v", test.expErr, errs)
+			}
+		})
+	}
+}
@@ -19,16 +19,22 @@ package validation
 import (
 	"crypto/x509"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	cmacme "github.com/jetstack/cert-manager/pkg/internal/apis/acme"
 	"github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
 	"github.com/jetstack/cert-manager/pkg/internal/apis/certmanager/validation/util"
 	cmmeta "github.com/jetstack/cert-manager/pkg/internal/apis/meta"
+	"github.com/jetstack/cert-manager/pkg/issuer/requestdefaults"
 )
 
 // Validation functions for cert-manager v1alpha2 Issuer types
@@ -40,7 +46,90 @@ func ValidateIssuer(obj runtime.Object) field.ErrorList {
 }
 
 func ValidateIssuerSpec(iss *certmanager.IssuerSpec, fldPath *field.Path) field.ErrorList {
-	return ValidateIssuerConfig(&iss.IssuerConfig, fldPath)
+	el := ValidateIssuerConfig(&iss.IssuerConfig, fldPath)
+	el = append(el, validateAllowedDNSSuffixes(iss.AllowedDNSSuffixes, fldPath.Child("allowedDNSSuffixes"))...)
+	if iss.RequestDefaults != nil {
+		el = append(el, validateRequestDefaults(&iss.IssuerConfig, iss.RequestDefaults, fldPath.Child("requestDefaults"))...)
+	}
+	if iss.KeyEscrow != nil {
+		el = append(el, validateKeyEscrow(iss.KeyEscrow, fldPath.Child("keyEscrow"))...)
+	}
+	return el
+}
+
+func validateKeyEscrow(escrow *certmanager.KeyEscrow, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	switch {
+	case escrow.HTTPS != nil && escrow.KMIP != nil:
+		el = append(el, field.Forbidden(fldPath.Child("kmip"), "may not specify more than one key escrow service"))
+	case escrow.HTTPS != nil:
+		urlPath := fldPath.Child("https", "url")
+		if len(escrow.HTTPS.URL) == 0 {
+			el = append(el, field.Required(urlPath, ""))
+		} else if u, err := url.Parse(escrow.HTTPS.URL); err != nil {
+			el = append(el, field.Invalid(urlPath, escrow.HTTPS.URL, err.Error()))
+		} else if u.Scheme != "https" || u.Host == "" {
+			el = append(el, field.Invalid(urlPath, escrow.HTTPS.URL, "must be an absolute https URL"))
+		}
+	case escrow.KMIP != nil:
+		serverPath := fldPath.Child("kmip", "server")
+		if len(escrow.KMIP.Server) == 0 {
+			el = append(el, field.Required(serverPath, ""))
+		} else if _, port, err := net.SplitHostPort(escrow.KMIP.Server); err != nil {
+			el = append(el, field.Invalid(serverPath, escrow.KMIP.Server, err.Error()))
+		} else if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			el = append(el, field.Invalid(serverPath, escrow.KMIP.Server, "invalid port"))
+		}
+	default:
+		el = append(el, field.Required(fldPath, "no key escrow service configured"))
+	}
+
+	if len(escrow.CABundle) > 0 && !x509.NewCertPool().AppendCertsFromPEM(escrow.CABundle) {
+		el = append(el, field.Invalid(fldPath.Child("caBundle"), "", "Specified CA bundle is invalid"))
+	}
+	if len(escrow.ClientCertSecretRef.Name) == 0 {
+		el = append(el, field.Required(fldPath.Child("clientCertSecretRef", "name"), "a client certificate is required to authenticate to the key escrow service"))
+	}
+
+	return el
+}
+
+func validateRequestDefaults(cfg *certmanager.IssuerConfig, defaults *certmanager.RequestDefaults, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if cfg.CA == nil && cfg.SelfSigned == nil {
+		el = append(el, field.Forbidden(fldPath, "request defaults are only supported by CA and SelfSigned issuers"))
+	}
+	if defaults.CommonName != "" {
+		if err := requestdefaults.Parse(defaults.CommonName); err != nil {
+			el = append(el, field.Invalid(fldPath.Child("commonName"), defaults.CommonName, err.Error()))
+		}
+	}
+	for i, t := range defaults.DNSNames {
+		if err := requestdefaults.Parse(t); err != nil {
+			el = append(el, field.Invalid(fldPath.Child("dnsNames").Index(i), t, err.Error()))
+		}
+	}
+	for i, t := range defaults.URISANs {
+		if err := requestdefaults.Parse(t); err != nil {
+			el = append(el, field.Invalid(fldPath.Child("uriSANs").Index(i), t, err.Error()))
+		}
+	}
+	return el
+}
+
+func validateAllowedDNSSuffixes(suffixes []string, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	for i, suffix := range suffixes {
+		if strings.Contains(suffix, "*") {
+			el = append(el, field.Invalid(fldPath.Index(i), suffix, "wildcards are not permitted, all subdomains of a suffix are allowed"))
+			continue
+		}
+		for _, msg := range utilvalidation.IsDNS1123Subdomain(strings.TrimPrefix(suffix, ".")) {
+			el = append(el, field.Invalid(fldPath.Index(i), suffix, msg))
+		}
+	}
+	return el
 }
 
 func ValidateIssuerConfig(iss *certmanager.IssuerConfig, fldPath *field.Path) field.ErrorList {
@@ -141,6 +230,57 @@ func ValidateACMEIssuerChallengeSolverConfig(sol *cmacme.ACMEChallengeSolver, fl
 	if numProviders == 0 {
 		el = append(el, field.Required(fldPath, "no solver type configured"))
 	}
+	if sol.Hooks != nil {
+		el = append(el, ValidateACMEChallengeSolverHooks(sol.Hooks, fldPath.Child("hooks"))...)
+	}
+
+	return el
+}
+
+const (
+	// maxACMEChallengeHookTimeout and maxACMEChallengeHookRetries limit the
+	// timeout of a single request to a hook and the number of retries. The
+	// total time a hook can block a worker of the challenges controller is
+	// bounded separately by the controller.
+	maxACMEChallengeHookTimeout = time.Minute
+	maxACMEChallengeHookRetries = 5
+)
+
+func ValidateACMEChallengeSolverHooks(hooks *cmacme.ACMEChallengeSolverHooks, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if hooks.PrePresent != nil {
+		el = append(el, validateACMEChallengeHook(hooks.PrePresent, fldPath.Child("prePresent"))...)
+	}
+	if hooks.PostCleanUp != nil {
+		el = append(el, validateACMEChallengeHook(hooks.PostCleanUp, fldPath.Child("postCleanUp"))...)
+	}
+	return el
+}
+
+func validateACMEChallengeHook(hook *cmacme.ACMEChallengeHook, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	if len(hook.URL) == 0 {
+		el = append(el, field.Required(fldPath.Child("url"), ""))
+	} else if u, err := url.Parse(hook.URL); err != nil {
+		el = append(el, field.Invalid(fldPath.Child("url"), hook.URL, err.Error()))
+	} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		el = append(el, field.Invalid(fldPath.Child("url"), hook.URL, "must be an absolute http or https URL"))
+	}
+
+	if len(hook.CABundle) > 0 && !x509.NewCertPool().AppendCertsFromPEM(hook.CABundle) {
+		el = append(el, field.Invalid(fldPath.Child("caBundle"), "", "Specified CA bundle is invalid"))
+	}
+
+	if hook.Timeout != nil && (hook.Timeout.Duration <= 0 || hook.Timeout.Duration > maxACMEChallengeHookTimeout) {
+		el = append(el, field.Invalid(fldPath.Child("timeout"), hook.Timeout.Duration.String(),
+			fmt.Sprintf("must be greater than zero and not exceed %s", maxACMEChallengeHookTimeout)))
+	}
+
+	if hook.Retries != nil && (*hook.Retries < 0 || *hook.Retries > maxACMEChallengeHookRetries) {
+		el = append(el, field.Invalid(fldPath.Child("retries"), *hook.Retries,
+			fmt.Sprintf("must be between 0 and %d", maxACMEChallengeHookRetries)))
+	}
 
 	return el
 }
@@ -196,6 +336,13 @@ func ValidateVaultIssuerConfig(iss *certmanager.VaultIssuer, fldPath *field.Path
 		el = append(el, field.Required(fldPath.Child("path"), ""))
 	}
 
+	switch iss.SigningMode {
+	case "", certmanager.VaultSigningModeRole, certmanager.VaultSigningModeVerbatim:
+	default:
+		el = append(el, field.NotSupported(fldPath.Child("signingMode"), iss.SigningMode,
+			[]string{string(certmanager.VaultSigningModeRole), string(certmanager.VaultSigningModeVerbatim)}))
+	}
+
 	// check if caBundle is valid
 	certs := iss.CABundle
 	if len(certs) > 0 {
@@ -19,8 +19,10 @@ package validation
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	cmacme "github.com/jetstack/cert-manager/pkg/internal/apis/acme"
@@ -83,6 +85,23 @@ func TestValidateVaultIssuerConfig(t *testing.T) {
 				field.Invalid(fldPath.Child("caBundle"), "", "Specified CA bundle is invalid"),
 			},
 		},
+		"vault issuer with verbatim signing mode": {
+			spec: &cmapi.VaultIssuer{
+				Server:      "something",
+				Path:        "pki/sign-verbatim",
+				SigningMode: cmapi.VaultSigningModeVerbatim,
+			},
+		},
+		"vault issuer with unsupported signing mode": {
+			spec: &cmapi.VaultIssuer{
+				Server:      "something",
+				Path:        "pki/sign/role",
+				SigningMode: "Unknown",
+			},
+			errs: []*field.Error{
+				field.NotSupported(fldPath.Child("signingMode"), cmapi.VaultSigningMode("Unknown"), []string{"Role", "Verbatim"}),
+			},
+		},
 	}
 	for n, s := range scenarios {
 		t.Run(n, func(t *testing.T) {
@@ -358,6 +377,142 @@ func TestValidateIssuerSpec(t *testing.T) {
 				field.Forbidden(fldPath.Child("selfSigned"), "may not specify more than one issuer type"),
 			},
 		},
+		"valid allowed DNS suffixes": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					SelfSigned: &cmapi.SelfSignedIssuer{},
+				},
+				AllowedDNSSuffixes: []string{"example.com", ".team-a.example.org"},
+			},
+		},
+		"wildcard allowed DNS suffix": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					SelfSigned: &cmapi.SelfSignedIssuer{},
+				},
+				AllowedDNSSuffixes: []string{"*.example.com"},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("allowedDNSSuffixes").Index(0), "*.example.com", "wildcards are not permitted, all subdomains of a suffix are allowed"),
+			},
+		},
+		"valid request defaults": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{SecretName: "valid"},
+				},
+				RequestDefaults: &cmapi.RequestDefaults{
+					CommonName: "{{ serviceAccount }}.{{ namespace }}",
+					DNSNames:   []string{"{{ serviceAccount }}.{{ namespace }}.svc.{{ clusterDomain }}"},
+					URISANs:    []string{"spiffe://{{ clusterDomain }}/ns/{{ namespace }}/sa/{{ serviceAccount }}"},
+				},
+			},
+		},
+		"request defaults using unknown functions": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					SelfSigned: &cmapi.SelfSignedIssuer{},
+				},
+				RequestDefaults: &cmapi.RequestDefaults{
+					URISANs: []string{"spiffe://cluster.local/ns/{{ namespace }}/pod/{{ pod }}"},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("requestDefaults", "uriSANs").Index(0), "spiffe://cluster.local/ns/{{ namespace }}/pod/{{ pod }}", `template: :1: function "pod" not defined`),
+			},
+		},
+		"request defaults on an issuer that does not support them": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					Vault: &validVaultIssuer,
+				},
+				RequestDefaults: &cmapi.RequestDefaults{
+					CommonName: "{{ namespace }}",
+				},
+			},
+			errs: []*field.Error{
+				field.Forbidden(fldPath.Child("requestDefaults"), "request defaults are only supported by CA and SelfSigned issuers"),
+			},
+		},
+		"valid https key escrow": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{SecretName: "valid"},
+				},
+				KeyEscrow: &cmapi.KeyEscrow{
+					HTTPS:               &cmapi.HTTPSKeyEscrow{URL: "https://escrow.example.com/keys"},
+					ClientCertSecretRef: cmmeta.LocalObjectReference{Name: "escrow-client"},
+				},
+			},
+		},
+		"valid kmip key escrow": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{SecretName: "valid"},
+				},
+				KeyEscrow: &cmapi.KeyEscrow{
+					KMIP:                &cmapi.KMIPKeyEscrow{Server: "kmip.example.com:5696"},
+					ClientCertSecretRef: cmmeta.LocalObjectReference{Name: "escrow-client"},
+				},
+			},
+		},
+		"key escrow without a service or client certificate": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{SecretName: "valid"},
+				},
+				KeyEscrow: &cmapi.KeyEscrow{},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("keyEscrow"), "no key escrow service configured"),
+				field.Required(fldPath.Child("keyEscrow", "clientCertSecretRef", "name"), "a client certificate is required to authenticate to the key escrow service"),
+			},
+		},
+		"key escrow with more than one service": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{SecretName: "valid"},
+				},
+				KeyEscrow: &cmapi.KeyEscrow{
+					HTTPS:               &cmapi.HTTPSKeyEscrow{URL: "https://escrow.example.com/keys"},
+					KMIP:                &cmapi.KMIPKeyEscrow{Server: "kmip.example.com:5696"},
+					ClientCertSecretRef: cmmeta.LocalObjectReference{Name: "escrow-client"},
+				},
+			},
+			errs: []*field.Error{
+				field.Forbidden(fldPath.Child("keyEscrow", "kmip"), "may not specify more than one key escrow service"),
+			},
+		},
+		"key escrow with invalid fields": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{SecretName: "valid"},
+				},
+				KeyEscrow: &cmapi.KeyEscrow{
+					HTTPS:               &cmapi.HTTPSKeyEscrow{URL: "http://escrow.example.com/keys"},
+					CABundle:            []byte("invalid"),
+					ClientCertSecretRef: cmmeta.LocalObjectReference{Name: "escrow-client"},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("keyEscrow", "https", "url"), "http://escrow.example.com/keys", "must be an absolute https URL"),
+				field.Invalid(fldPath.Child("keyEscrow", "caBundle"), "", "Specified CA bundle is invalid"),
+			},
+		},
+		"kmip key escrow without a port": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{SecretName: "valid"},
+				},
+				KeyEscrow: &cmapi.KeyEscrow{
+					KMIP:                &cmapi.KMIPKeyEscrow{Server: "kmip.example.com"},
+					ClientCertSecretRef: cmmeta.LocalObjectReference{Name: "escrow-client"},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("keyEscrow", "kmip", "server"), "kmip.example.com", "address kmip.example.com: missing port in address"),
+			},
+		},
 	}
 	for n, s := range scenarios {
 		t.Run(n, func(t *testing.T) {
@@ -965,3 +1120,96 @@ func TestValidateSecretKeySelector(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateACMEChallengeSolverHooks(t *testing.T) {
+	fldPath := field.NewPath("")
+	retries := func(i int) *int { return &i }
+	scenarios := map[string]struct {
+		hooks *cmacme.ACMEChallengeSolverHooks
+		errs  []*field.Error
+	}{
+		"valid hooks": {
+			hooks: &cmacme.ACMEChallengeSolverHooks{
+				PrePresent: &cmacme.ACMEChallengeHook{
+					URL:     "https://hooks.example.com/pre-present",
+					Timeout: &metav1.Duration{Duration: 30 * time.Second},
+					Retries: retries(5),
+				},
+				PostCleanUp: &cmacme.ACMEChallengeHook{
+					URL:     "http://hooks.example.com/post-cleanup",
+					Retries: retries(0),
+				},
+			},
+		},
+		"no hooks specified": {
+			hooks: &cmacme.ACMEChallengeSolverHooks{},
+		},
+		"missing url": {
+			hooks: &cmacme.ACMEChallengeSolverHooks{
+				PrePresent: &cmacme.ACMEChallengeHook{},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("prePresent", "url"), ""),
+			},
+		},
+		"relative url": {
+			hooks: &cmacme.ACMEChallengeSolverHooks{
+				PostCleanUp: &cmacme.ACMEChallengeHook{URL: "/post-cleanup"},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("postCleanUp", "url"), "/post-cleanup", "must be an absolute http or https URL"),
+			},
+		},
+		"unsupported url scheme": {
+			hooks: &cmacme.ACMEChallengeSolverHooks{
+				PostCleanUp: &cmacme.ACMEChallengeHook{URL: "ftp://hooks.example.com"},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("postCleanUp", "url"), "ftp://hooks.example.com", "must be an absolute http or https URL"),
+			},
+		},
+		"invalid fields": {
+			hooks: &cmacme.ACMEChallengeSolverHooks{
+				PrePresent: &cmacme.ACMEChallengeHook{
+					URL:      "https://hooks.example.com",
+					CABundle: []byte("invalid"),
+					Timeout:  &metav1.Duration{Duration: 2 * time.Minute},
+					Retries:  retries(6),
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("prePresent", "caBundle"), "", "Specified CA bundle is invalid"),
+				field.Invalid(fldPath.Child("prePresent", "timeout"), "2m0s", "must be greater than zero and not exceed 1m0s"),
+				field.Invalid(fldPath.Child("prePresent", "retries"), 6, "must be between 0 and 5"),
+			},
+		},
+		"zero timeout and negative retries": {
+			hooks: &cmacme.ACMEChallengeSolverHooks{
+				PrePresent: &cmacme.ACMEChallengeHook{
+					URL:     "https://hooks.example.com",
+					Timeout: &metav1.Duration{},
+					Retries: retries(-1),
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("prePresent", "timeout"), "0s", "must be greater than zero and not exceed 1m0s"),
+				field.Invalid(fldPath.Child("prePresent", "retries"), -1, "must be between 0 and 5"),
+			},
+		},
+	}
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			errs := ValidateACMEChallengeSolverHooks(s.hooks, fldPath)
+			if len(errs) != len(s.errs) {
+				t.Errorf("Expected %v but got %v", s.errs, errs)
+				return
+			}
+			for i, e := range errs {
+				expectedErr := s.errs[i]
+				if !reflect.DeepEqual(e, expectedErr) {
+					t.Errorf("Expected %v but got %v", expectedErr, e)
+				}
+			}
+		})
+	}
+}
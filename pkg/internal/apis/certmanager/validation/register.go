@@ -25,9 +25,21 @@ func AddToValidationRegistry(reg *validation.Registry) error {
 	if err := reg.AddValidateFunc(&cmapi.Certificate{}, ValidateCertificate); err != nil {
 		return err
 	}
+	if err := reg.AddValidateCreateFunc(&cmapi.Certificate{}, ValidateCertificateCreate); err != nil {
+		return err
+	}
+	if err := reg.AddValidateUpdateFunc(&cmapi.Certificate{}, ValidateCertificateUpdate); err != nil {
+		return err
+	}
 	if err := reg.AddValidateFunc(&cmapi.CertificateRequest{}, ValidateCertificateRequest); err != nil {
 		return err
 	}
+	if err := reg.AddValidateCreateFunc(&cmapi.CertificateRequest{}, ValidateCertificateRequestCreate); err != nil {
+		return err
+	}
+	if err := reg.AddValidateUpdateFunc(&cmapi.CertificateRequest{}, ValidateCertificateRequestUpdate); err != nil {
+		return err
+	}
 	if err := reg.AddValidateFunc(&cmapi.ClusterIssuer{}, ValidateClusterIssuer); err != nil {
 		return err
 	}
@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -100,12 +101,19 @@ func (v *Vault) Sign(csrPEM []byte, duration time.Duration) (cert []byte, ca []b
 		"uri_sans":    strings.Join(pki.URLsToString(csr.URIs), ","),
 		"ttl":         duration.String(),
 		"csr":         string(csrPEM),
-
-		"exclude_cn_from_sans": "true",
 	}
 
 	vaultIssuer := v.issuer.GetSpec().Vault
-	url := path.Join("/v1", vaultIssuer.Path)
+	excludeCNFromSANs := true
+	if vaultIssuer.ExcludeCNFromSANs != nil {
+		excludeCNFromSANs = *vaultIssuer.ExcludeCNFromSANs
+	}
+	parameters["exclude_cn_from_sans"] = strconv.FormatBool(excludeCNFromSANs)
+
+	url, err := SignPath(vaultIssuer)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	request := v.client.NewRequest("POST", url)
 
@@ -150,6 +158,50 @@ func (v *Vault) Sign(csrPEM []byte, duration time.Duration) (cert []byte, ca []b
 	return []byte(bundle.ToPEMBundle()), caPem, nil
 }
 
+// SignPath returns the path of the Vault API endpoint used to sign
+// certificates for the given issuer. If neither a signing mode nor an issuer
+// reference is set, the path of the issuer is used as given. Otherwise the
+// endpoint and issuer of the path are replaced according to the issuer.
+func SignPath(vaultIssuer *v1alpha2.VaultIssuer) (string, error) {
+	if vaultIssuer.SigningMode == "" && vaultIssuer.IssuerRef == "" {
+		return path.Join("/v1", vaultIssuer.Path), nil
+	}
+
+	// Find the sign or sign-verbatim segment in paths of the form
+	// <mount>/sign/<role> or <mount>/sign-verbatim[/<role>]
+	segments := strings.Split(strings.Trim(vaultIssuer.Path, "/"), "/")
+	i := len(segments) - 1
+	for ; i >= 0; i-- {
+		if segments[i] == "sign" || segments[i] == "sign-verbatim" {
+			break
+		}
+	}
+	if i < 1 || len(segments)-i > 2 {
+		return "", fmt.Errorf("vault path %q must have the form <mount>/sign/<role> or <mount>/sign-verbatim[/<role>] to set signingMode or issuerRef", vaultIssuer.Path)
+	}
+	mount, endpoint, role := segments[:i], segments[i], strings.Join(segments[i+1:], "")
+
+	switch vaultIssuer.SigningMode {
+	case v1alpha2.VaultSigningModeRole:
+		endpoint = "sign"
+	case v1alpha2.VaultSigningModeVerbatim:
+		endpoint = "sign-verbatim"
+	}
+	if endpoint == "sign" && role == "" {
+		return "", fmt.Errorf("vault path %q must name the role to sign certificates with", vaultIssuer.Path)
+	}
+
+	if vaultIssuer.IssuerRef != "" {
+		// An issuer selected in the path is replaced by the issuerRef
+		if len(mount) > 2 && mount[len(mount)-2] == "issuer" {
+			mount = mount[:len(mount)-2]
+		}
+		mount = append(mount, "issuer", vaultIssuer.IssuerRef)
+	}
+
+	return path.Join(append(append([]string{"/v1"}, mount...), endpoint, role)...), nil
+}
+
 func (v *Vault) setToken(client Client) error {
 	tokenRef := v.issuer.GetSpec().Vault.Auth.TokenSecretRef
 	if tokenRef != nil {
@@ -195,6 +195,105 @@ func TestSign(t *testing.T) {
 	}
 }
 
+func TestSignExcludeCNFromSANs(t *testing.T) {
+	csrPEM := generateCSR(t, generateRSAPrivateKey(t))
+	exclude, include := true, false
+
+	tests := map[string]struct {
+		excludeCNFromSANs *bool
+		expParameter      string
+	}{
+		"common name is excluded by default": {
+			expParameter: "true",
+		},
+		"common name is excluded if set to true": {
+			excludeCNFromSANs: &exclude,
+			expParameter:      "true",
+		},
+		"common name is included if set to false": {
+			excludeCNFromSANs: &include,
+			expParameter:      "false",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var parameters map[string]string
+			client := vaultfake.NewFakeClient()
+			client.RawRequestFn = func(r *vault.Request) (*vault.Response, error) {
+				parameters = r.Obj.(map[string]string)
+				return nil, errors.New("request failed")
+			}
+			v := &Vault{
+				issuer: gen.Issuer("vault-issuer",
+					gen.SetIssuerVault(v1alpha2.VaultIssuer{Path: "pki/sign/role", ExcludeCNFromSANs: test.excludeCNFromSANs}),
+				),
+				client: client,
+			}
+
+			if _, _, err := v.Sign(csrPEM, time.Minute); err == nil {
+				t.Fatalf("expected request to fail")
+			}
+			if parameters["exclude_cn_from_sans"] != test.expParameter {
+				t.Errorf("expected exclude_cn_from_sans %q, got: %q", test.expParameter, parameters["exclude_cn_from_sans"])
+			}
+		})
+	}
+}
+
+func TestSignPath(t *testing.T) {
+	tests := map[string]struct {
+		issuer  v1alpha2.VaultIssuer
+		expPath string
+		expErr  bool
+	}{
+		"path is used as given without signing mode or issuer ref": {
+			issuer:  v1alpha2.VaultIssuer{Path: "my/custom/endpoint"},
+			expPath: "/v1/my/custom/endpoint",
+		},
+		"role signing mode uses the sign endpoint": {
+			issuer:  v1alpha2.VaultIssuer{Path: "pki/sign-verbatim/web", SigningMode: v1alpha2.VaultSigningModeRole},
+			expPath: "/v1/pki/sign/web",
+		},
+		"verbatim signing mode uses the sign-verbatim endpoint": {
+			issuer:  v1alpha2.VaultIssuer{Path: "pki/sign/web", SigningMode: v1alpha2.VaultSigningModeVerbatim},
+			expPath: "/v1/pki/sign-verbatim/web",
+		},
+		"verbatim signing mode does not require a role": {
+			issuer:  v1alpha2.VaultIssuer{Path: "pki/sign-verbatim", SigningMode: v1alpha2.VaultSigningModeVerbatim},
+			expPath: "/v1/pki/sign-verbatim",
+		},
+		"role signing mode requires a role": {
+			issuer: v1alpha2.VaultIssuer{Path: "pki/sign-verbatim", SigningMode: v1alpha2.VaultSigningModeRole},
+			expErr: true,
+		},
+		"issuer ref selects the issuer of the mount": {
+			issuer:  v1alpha2.VaultIssuer{Path: "nested/pki/sign/web", IssuerRef: "intermediate-2020"},
+			expPath: "/v1/nested/pki/issuer/intermediate-2020/sign/web",
+		},
+		"issuer ref replaces the issuer in the path": {
+			issuer:  v1alpha2.VaultIssuer{Path: "pki/issuer/default/sign/web", IssuerRef: "intermediate-2020", SigningMode: v1alpha2.VaultSigningModeVerbatim},
+			expPath: "/v1/pki/issuer/intermediate-2020/sign-verbatim/web",
+		},
+		"path without sign endpoint cannot be used with a signing mode": {
+			issuer: v1alpha2.VaultIssuer{Path: "my/custom/endpoint", SigningMode: v1alpha2.VaultSigningModeRole},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path, err := SignPath(&test.issuer)
+			if test.expErr != (err != nil) {
+				t.Fatalf("expected error %t but got: %v", test.expErr, err)
+			}
+			if path != test.expPath {
+				t.Errorf("expected path %q, got: %q", test.expPath, path)
+			}
+		})
+	}
+}
+
 type testSetTokenT struct {
 	expectedToken string
 	expectedErr   error
@@ -28,6 +28,7 @@ import (
 	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	"github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/issuer"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns"
 	"github.com/jetstack/cert-manager/pkg/metrics"
 )
 
@@ -48,6 +49,13 @@ type Acme struct {
 
 	// metrics is used to create instrumented ACME clients
 	metrics *metrics.Metrics
+
+	// clientOptions configures retries and circuit breaking of ACME clients
+	clientOptions accounts.ClientOptions
+
+	// dns01Verifier verifies the credentials of the DNS01 providers of the
+	// issuer once its account is ready. It is nil if verification is disabled.
+	dns01Verifier *dns.CredentialVerifier
 }
 
 // New returns a new ACME issuer interface for the given issuer.
@@ -66,6 +74,10 @@ func New(ctx *controller.Context, issuer v1alpha2.GenericIssuer) (issuer.Interfa
 		clusterResourceNamespace: ctx.IssuerOptions.ClusterResourceNamespace,
 		accountRegistry:          ctx.ACMEOptions.AccountRegistry,
 		metrics:                  ctx.Metrics,
+		clientOptions:            ctx.ACMEOptions.ClientOptions,
+	}
+	if ctx.ACMEOptions.DNS01VerifyCredentials {
+		a.dns01Verifier = dns.NewCredentialVerifier(ctx)
 	}
 
 	return a, nil
@@ -19,6 +19,7 @@ import (
 	"golang.org/x/net/context"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/dns/v1"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
@@ -205,6 +206,26 @@ func (c *DNSProvider) CleanUp(domain, fqdn, value string) error {
 	return nil
 }
 
+// Verify checks that the credentials of the provider are accepted by Google
+// Cloud DNS by getting the configured managed zone, or listing a single
+// managed zone of the project if none is configured.
+func (c *DNSProvider) Verify() error {
+	var err error
+	if c.hostedZoneName != "" {
+		_, err = c.client.ManagedZones.Get(c.project, c.hostedZoneName).Do()
+	} else {
+		_, err = c.client.ManagedZones.List(c.project).MaxResults(1).Do()
+	}
+	if err == nil {
+		return nil
+	}
+	providerErr := &util.ProviderError{Err: fmt.Errorf("Failed to verify GoogleCloud credentials: %v", err)}
+	if apiErr, ok := err.(*googleapi.Error); ok {
+		providerErr.StatusCode = apiErr.Code
+	}
+	return providerErr
+}
+
 // getHostedZone returns the managed-zone
 func (c *DNSProvider) getHostedZone(domain string) (string, error) {
 	if c.hostedZoneName != "" {
@@ -169,6 +169,13 @@ func (c *DNSProvider) getHostedZoneID(fqdn string) (string, error) {
 	return hostedZone[0].ID, nil
 }
 
+// Verify checks that the credentials of the provider are accepted by
+// Cloudflare by listing a single zone.
+func (c *DNSProvider) Verify() error {
+	_, err := c.makeRequest("GET", "/zones?per_page=1", nil)
+	return err
+}
+
 var errNoExistingRecord = errors.New("No existing record found")
 
 func (c *DNSProvider) findTxtRecord(fqdn string) (*cloudFlareRecord, error) {
@@ -236,7 +243,7 @@ func (c *DNSProvider) makeRequest(method, uri string, body io.Reader) (json.RawM
 	}
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("Error querying Cloudflare API for %s %q -> %v", method, uri, err)
+		return nil, &util.ProviderError{Err: fmt.Errorf("Error querying Cloudflare API for %s %q -> %v", method, uri, err)}
 	}
 
 	defer resp.Body.Close()
@@ -256,9 +263,9 @@ func (c *DNSProvider) makeRequest(method, uri string, body io.Reader) (json.RawM
 					errStr += fmt.Sprintf("<- %d: %s", chainErr.Code, chainErr.Message)
 				}
 			}
-			return nil, fmt.Errorf("Cloudflare API Error for %s %q \n%s", method, uri, errStr)
+			return nil, &util.ProviderError{StatusCode: resp.StatusCode, Err: fmt.Errorf("Cloudflare API Error for %s %q \n%s", method, uri, errStr)}
 		}
-		return nil, fmt.Errorf("Cloudflare API error for %s %q", method, uri)
+		return nil, &util.ProviderError{StatusCode: resp.StatusCode, Err: fmt.Errorf("Cloudflare API error for %s %q", method, uri)}
 	}
 
 	return r.Result, nil
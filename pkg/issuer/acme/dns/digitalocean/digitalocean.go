@@ -124,6 +124,20 @@ func (c *DNSProvider) CleanUp(domain, fqdn, value string) error {
 	return nil
 }
 
+// Verify checks that the token of the provider is accepted by DigitalOcean
+// by listing a single domain.
+func (c *DNSProvider) Verify() error {
+	_, resp, err := c.client.Domains.List(context.Background(), &godo.ListOptions{PerPage: 1})
+	if err == nil {
+		return nil
+	}
+	providerErr := &util.ProviderError{Err: fmt.Errorf("Failed to verify DigitalOcean token: %v", err)}
+	if resp != nil && resp.Response != nil {
+		providerErr.StatusCode = resp.StatusCode
+	}
+	return providerErr
+}
+
 func (c *DNSProvider) findTxtRecord(fqdn string) ([]godo.DomainRecord, error) {
 
 	zoneName, err := util.FindZoneByFqdn(fqdn, c.dns01Nameservers)
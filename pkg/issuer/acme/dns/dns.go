@@ -86,7 +86,15 @@ func (s *Solver) Present(ctx context.Context, issuer v1alpha2.GenericIssuer, ch
 	}
 	if err == nil {
 		log.Info("presenting DNS01 challenge for domain")
-		return webhookSolver.Present(req)
+		if err := webhookSolver.Present(req); err != nil {
+			return err
+		}
+		if ownershipReq := s.externalDNSOwnershipRequest(ch, req); ownershipReq != nil {
+			if err := webhookSolver.Present(ownershipReq); err != nil {
+				return fmt.Errorf("error presenting external-dns ownership record %q: %v", ownershipReq.ResolvedFQDN, err)
+			}
+		}
+		return nil
 	}
 
 	slv, providerConfig, err := s.solverForChallenge(ctx, issuer, ch)
@@ -101,7 +109,10 @@ func (s *Solver) Present(ctx context.Context, issuer v1alpha2.GenericIssuer, ch
 
 	log.Info("presenting DNS01 challenge for domain")
 
-	return slv.Present(ch.Spec.DNSName, fqdn, ch.Spec.Key)
+	if err := slv.Present(ch.Spec.DNSName, fqdn, ch.Spec.Key); err != nil {
+		return err
+	}
+	return s.presentExternalDNSOwnership(slv, ch, fqdn)
 }
 
 // Check verifies that the DNS records for the ACME challenge have propagated.
@@ -144,7 +155,15 @@ func (s *Solver) CleanUp(ctx context.Context, issuer v1alpha2.GenericIssuer, ch
 	}
 	if err == nil {
 		log.Info("cleaning up DNS01 challenge")
-		return webhookSolver.CleanUp(req)
+		if err := webhookSolver.CleanUp(req); err != nil {
+			return err
+		}
+		if ownershipReq := s.externalDNSOwnershipRequest(ch, req); ownershipReq != nil {
+			if err := webhookSolver.CleanUp(ownershipReq); err != nil {
+				return fmt.Errorf("error cleaning up external-dns ownership record %q: %v", ownershipReq.ResolvedFQDN, err)
+			}
+		}
+		return nil
 	}
 
 	slv, providerConfig, err := s.solverForChallenge(ctx, issuer, ch)
@@ -157,7 +176,10 @@ func (s *Solver) CleanUp(ctx context.Context, issuer v1alpha2.GenericIssuer, ch
 		return err
 	}
 
-	return slv.CleanUp(ch.Spec.DNSName, fqdn, ch.Spec.Key)
+	if err := slv.CleanUp(ch.Spec.DNSName, fqdn, ch.Spec.Key); err != nil {
+		return err
+	}
+	return s.cleanUpExternalDNSOwnership(slv, ch, fqdn)
 }
 
 func followCNAME(strategy cmacme.CNAMEStrategy) bool {
@@ -179,17 +201,25 @@ func extractChallengeSolverConfig(ch *cmacme.Challenge) (*cmacme.ACMEChallengeSo
 // The providerName is the name of an ACME DNS-01 challenge provider as
 // specified on the Issuer resource for the Solver.
 func (s *Solver) solverForChallenge(ctx context.Context, issuer v1alpha2.GenericIssuer, ch *cmacme.Challenge) (solver, *cmacme.ACMEChallengeSolverDNS01, error) {
+	providerConfig, err := extractChallengeSolverConfig(ch)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.solverForConfig(ctx, issuer, providerConfig)
+}
+
+// solverForConfig returns a solver for the DNS01 provider configured in
+// providerConfig, loading its credentials from the resource namespace of the
+// issuer.
+func (s *Solver) solverForConfig(ctx context.Context, issuer v1alpha2.GenericIssuer, providerConfig *cmacme.ACMEChallengeSolverDNS01) (solver, *cmacme.ACMEChallengeSolverDNS01, error) {
 	log := logs.FromContext(ctx, "solverForChallenge")
 	dbg := log.V(logs.DebugLevel)
 
 	resourceNamespace := s.ResourceNamespace(issuer)
 	canUseAmbientCredentials := s.CanUseAmbientCredentials(issuer)
 
-	providerConfig, err := extractChallengeSolverConfig(ch)
-	if err != nil {
-		return nil, nil, err
-	}
-
+	var err error
 	var impl solver
 	switch {
 	case providerConfig.Akamai != nil:
@@ -472,20 +502,24 @@ func NewSolver(ctx *controller.Context) (*Solver, error) {
 	}
 
 	return &Solver{
-		Context:      ctx,
-		secretLister: ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
-		dnsProviderConstructors: dnsProviderConstructors{
-			clouddns.NewDNSProvider,
-			cloudflare.NewDNSProviderCredentials,
-			route53.NewDNSProvider,
-			azuredns.NewDNSProviderCredentials,
-			acmedns.NewDNSProviderHostBytes,
-			digitalocean.NewDNSProviderCredentials,
-		},
-		webhookSolvers: initialized,
+		Context:                 ctx,
+		secretLister:            ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
+		dnsProviderConstructors: defaultDNSProviderConstructors,
+		webhookSolvers:          initialized,
 	}, nil
 }
 
+// defaultDNSProviderConstructors construct the DNS providers built into
+// cert-manager.
+var defaultDNSProviderConstructors = dnsProviderConstructors{
+	clouddns.NewDNSProvider,
+	cloudflare.NewDNSProviderCredentials,
+	route53.NewDNSProvider,
+	azuredns.NewDNSProviderCredentials,
+	acmedns.NewDNSProviderHostBytes,
+	digitalocean.NewDNSProviderCredentials,
+}
+
 func (s *Solver) loadSecretData(selector *cmmeta.SecretKeySelector, ns string) ([]byte, error) {
 	secret, err := s.secretLister.Secrets(ns).Get(selector.Name)
 	if err != nil {
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"fmt"
+
+	whapi "github.com/jetstack/cert-manager/pkg/acme/webhook/apis/acme/v1alpha1"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+)
+
+// external-dns records which records it manages in a registry of TXT records,
+// one for each managed record, named after the managed record with a
+// configurable prefix. It never modifies or deletes records owned by a
+// different owner ID, and does not create records for names already claimed
+// by another owner.
+// If enabled, cert-manager writes such an ownership record alongside each
+// DNS01 challenge record, so that an external-dns instance managing the same
+// zone does not treat the challenge records as its own and garbage collect
+// them.
+
+// externalDNSOwnershipRecord returns the name and value of the external-dns
+// ownership TXT record for the challenge record fqdn of ch, and false if
+// ownership records are disabled.
+func (s *Solver) externalDNSOwnershipRecord(ch *cmacme.Challenge, fqdn string) (string, string, bool) {
+	if s.DNS01ExternalDNSOwnerID == "" {
+		return "", "", false
+	}
+	name, value := externalDNSOwnershipRecord(s.DNS01ExternalDNSOwnerID, s.DNS01ExternalDNSTXTPrefix, ch, fqdn)
+	return name, value, true
+}
+
+func externalDNSOwnershipRecord(ownerID, prefix string, ch *cmacme.Challenge, fqdn string) (string, string) {
+	value := fmt.Sprintf("heritage=external-dns,external-dns/owner=%s,external-dns/resource=challenge/%s/%s",
+		ownerID, ch.Namespace, ch.Name)
+	return prefix + fqdn, value
+}
+
+// presentExternalDNSOwnership presents the external-dns ownership record of
+// the challenge record fqdn using slv, if enabled.
+func (s *Solver) presentExternalDNSOwnership(slv solver, ch *cmacme.Challenge, fqdn string) error {
+	name, value, ok := s.externalDNSOwnershipRecord(ch, fqdn)
+	if !ok {
+		return nil
+	}
+	if err := slv.Present(ch.Spec.DNSName, name, value); err != nil {
+		return fmt.Errorf("error presenting external-dns ownership record %q: %v", name, err)
+	}
+	return nil
+}
+
+// cleanUpExternalDNSOwnership cleans up the external-dns ownership record of
+// the challenge record fqdn using slv, if enabled.
+func (s *Solver) cleanUpExternalDNSOwnership(slv solver, ch *cmacme.Challenge, fqdn string) error {
+	name, value, ok := s.externalDNSOwnershipRecord(ch, fqdn)
+	if !ok {
+		return nil
+	}
+	if err := slv.CleanUp(ch.Spec.DNSName, name, value); err != nil {
+		return fmt.Errorf("error cleaning up external-dns ownership record %q: %v", name, err)
+	}
+	return nil
+}
+
+// externalDNSOwnershipRequest returns a copy of the webhook ChallengeRequest
+// req for the external-dns ownership record of its challenge record, or nil
+// if ownership records are disabled.
+func (s *Solver) externalDNSOwnershipRequest(ch *cmacme.Challenge, req *whapi.ChallengeRequest) *whapi.ChallengeRequest {
+	name, value, ok := s.externalDNSOwnershipRecord(ch, req.ResolvedFQDN)
+	if !ok {
+		return nil
+	}
+	ownershipReq := req.DeepCopy()
+	ownershipReq.ResolvedFQDN = name
+	ownershipReq.Key = value
+	return ownershipReq
+}
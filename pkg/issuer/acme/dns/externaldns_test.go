@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	whapi "github.com/jetstack/cert-manager/pkg/acme/webhook/apis/acme/v1alpha1"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/controller"
+)
+
+type recordingSolver struct {
+	presented []string
+	cleanedUp []string
+}
+
+func (r *recordingSolver) Present(domain, fqdn, value string) error {
+	r.presented = append(r.presented, fqdn+" "+value)
+	return nil
+}
+
+func (r *recordingSolver) CleanUp(domain, fqdn, value string) error {
+	r.cleanedUp = append(r.cleanedUp, fqdn+" "+value)
+	return nil
+}
+
+func TestExternalDNSOwnership(t *testing.T) {
+	ch := &cmacme.Challenge{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "example-com-1234"},
+		Spec:       cmacme.ChallengeSpec{DNSName: "example.com", Key: "key"},
+	}
+	const fqdn = "_acme-challenge.example.com."
+
+	tests := map[string]struct {
+		ownerID string
+		prefix  string
+		exp     []string
+	}{
+		"ownership records are disabled by default": {},
+		"ownership record is written with the prefix": {
+			ownerID: "cert-manager",
+			prefix:  "owner.",
+			exp: []string{
+				"owner._acme-challenge.example.com. heritage=external-dns,external-dns/owner=cert-manager,external-dns/resource=challenge/default/example-com-1234",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := &Solver{Context: &controller.Context{ACMEOptions: controller.ACMEOptions{
+				DNS01ExternalDNSOwnerID:   test.ownerID,
+				DNS01ExternalDNSTXTPrefix: test.prefix,
+			}}}
+
+			slv := &recordingSolver{}
+			if err := s.presentExternalDNSOwnership(slv, ch, fqdn); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.cleanUpExternalDNSOwnership(slv, ch, fqdn); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(slv.presented, test.exp) {
+				t.Errorf("expected presented records %q, got: %q", test.exp, slv.presented)
+			}
+			if !reflect.DeepEqual(slv.cleanedUp, test.exp) {
+				t.Errorf("expected cleaned up records %q, got: %q", test.exp, slv.cleanedUp)
+			}
+
+			req := &whapi.ChallengeRequest{ResolvedFQDN: fqdn, Key: ch.Spec.Key, DNSName: ch.Spec.DNSName}
+			ownershipReq := s.externalDNSOwnershipRequest(ch, req)
+			switch {
+			case len(test.exp) == 0 && ownershipReq != nil:
+				t.Errorf("expected no ownership request, got: %+v", ownershipReq)
+			case len(test.exp) > 0 && (ownershipReq == nil || ownershipReq.ResolvedFQDN+" "+ownershipReq.Key != test.exp[0]):
+				t.Errorf("expected ownership request for %q, got: %+v", test.exp[0], ownershipReq)
+			}
+			if req.ResolvedFQDN != fqdn || req.Key != ch.Spec.Key {
+				t.Errorf("expected the challenge request not to be modified, got: %+v", req)
+			}
+		})
+	}
+}
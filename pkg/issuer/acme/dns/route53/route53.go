@@ -198,6 +198,9 @@ func (r *DNSProvider) changeRecord(action, fqdn, value string, ttl int) error {
 				return nil
 			}
 		}
+		if reqErr, ok := err.(awserr.RequestFailure); ok {
+			return &util.ProviderError{StatusCode: reqErr.StatusCode(), Err: fmt.Errorf("Failed to change Route 53 record set: %v", err)}
+		}
 		return fmt.Errorf("Failed to change Route 53 record set: %v", err)
 
 	}
@@ -258,6 +261,25 @@ func (r *DNSProvider) getHostedZoneID(fqdn string) (string, error) {
 	return hostedZoneID, nil
 }
 
+// Verify checks that the credentials of the provider are accepted by Route 53
+// by getting the configured hosted zone, or listing a single hosted zone if
+// none is configured.
+func (r *DNSProvider) Verify() error {
+	var err error
+	if r.hostedZoneID != "" {
+		_, err = r.client.GetHostedZone(&route53.GetHostedZoneInput{Id: aws.String(r.hostedZoneID)})
+	} else {
+		_, err = r.client.ListHostedZones(&route53.ListHostedZonesInput{MaxItems: aws.String("1")})
+	}
+	if err == nil {
+		return nil
+	}
+	if reqErr, ok := err.(awserr.RequestFailure); ok {
+		return &util.ProviderError{StatusCode: reqErr.StatusCode(), Err: fmt.Errorf("Failed to verify Route 53 credentials: %v", err)}
+	}
+	return fmt.Errorf("Failed to verify Route 53 credentials: %v", err)
+}
+
 func newTXTRecordSet(fqdn, value string, ttl int) *route53.ResourceRecordSet {
 	return &route53.ResourceRecordSet{
 		Name: aws.String(fqdn),
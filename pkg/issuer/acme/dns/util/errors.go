@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ProviderError is returned by DNS providers for failed requests to the API
+// of the provider, so that failures of the provider itself can be told apart
+// from problems with a single challenge, like a missing hosted zone.
+type ProviderError struct {
+	// StatusCode is the HTTP status code of the response of the provider's
+	// API, or 0 if the API could not be reached.
+	StatusCode int
+	// Err is the error returned for the request
+	Err error
+}
+
+func (e *ProviderError) Error() string {
+	if e.StatusCode == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v (HTTP status %d)", e.Err, e.StatusCode)
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// statusCoder is implemented by the errors of provider SDKs that expose the
+// HTTP status code of the failed request, e.g. awserr.RequestFailure.
+type statusCoder interface {
+	StatusCode() int
+}
+
+// IsProviderUnavailable returns true if err indicates that the DNS provider
+// cannot be used at all rather than that a single request failed: its API
+// cannot be reached, it rejects the configured credentials, or it fails with
+// a server error. Providers report that their API cannot be reached by
+// returning a ProviderError without a status code.
+// Any other errors, including client errors caused by the request, are not
+// considered an outage as they show that the provider is processing requests.
+func IsProviderUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		return providerErr.StatusCode == 0 || isUnavailableStatus(providerErr.StatusCode)
+	}
+
+	var sc statusCoder
+	if errors.As(err, &sc) {
+		return isUnavailableStatus(sc.StatusCode())
+	}
+
+	// other network errors are not considered, as they may as well be caused
+	// by the nameservers used to look up the zone of the challenge
+	return false
+}
+
+func isUnavailableStatus(code int) bool {
+	return code == http.StatusUnauthorized || code == http.StatusForbidden || code >= http.StatusInternalServerError
+}
+
+// IsTransientProviderError returns true if err indicates that the API of the
+// DNS provider could not be reached or failed with a server error, so that
+// the request may succeed when it is retried. Unlike IsProviderUnavailable,
+// rejected credentials are not considered transient.
+func IsTransientProviderError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		return providerErr.StatusCode == 0 || providerErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var sc statusCoder
+	if errors.As(err, &sc) {
+		return sc.StatusCode() >= http.StatusInternalServerError
+	}
+
+	return false
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+type fakeRequestFailure struct {
+	statusCode int
+}
+
+func (f fakeRequestFailure) Error() string {
+	return fmt.Sprintf("request failed with status %d", f.statusCode)
+}
+
+func (f fakeRequestFailure) StatusCode() int {
+	return f.statusCode
+}
+
+func TestIsProviderUnavailable(t *testing.T) {
+	tests := map[string]struct {
+		err error
+		exp bool
+	}{
+		"nil error": {
+			err: nil,
+			exp: false,
+		},
+		"generic error": {
+			err: errors.New("no hosted zone found"),
+			exp: false,
+		},
+		"provider could not be reached": {
+			err: &ProviderError{Err: errors.New("connection refused")},
+			exp: true,
+		},
+		"credentials rejected": {
+			err: &ProviderError{StatusCode: http.StatusForbidden, Err: errors.New("forbidden")},
+			exp: true,
+		},
+		"server error": {
+			err: &ProviderError{StatusCode: http.StatusBadGateway, Err: errors.New("bad gateway")},
+			exp: true,
+		},
+		"client error": {
+			err: &ProviderError{StatusCode: http.StatusBadRequest, Err: errors.New("invalid record")},
+			exp: false,
+		},
+		"wrapped provider error": {
+			err: fmt.Errorf("error presenting challenge: %w", &ProviderError{StatusCode: http.StatusUnauthorized, Err: errors.New("unauthorized")}),
+			exp: true,
+		},
+		"SDK error with server error status": {
+			err: fakeRequestFailure{statusCode: http.StatusServiceUnavailable},
+			exp: true,
+		},
+		"SDK error with client error status": {
+			err: fakeRequestFailure{statusCode: http.StatusNotFound},
+			exp: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := IsProviderUnavailable(test.err); actual != test.exp {
+				t.Errorf("expected %t, got: %t", test.exp, actual)
+			}
+		})
+	}
+}
+
+func TestIsTransientProviderError(t *testing.T) {
+	tests := map[string]struct {
+		err error
+		exp bool
+	}{
+		"nil error": {
+			err: nil,
+			exp: false,
+		},
+		"generic error": {
+			err: errors.New("invalid service account"),
+			exp: false,
+		},
+		"provider could not be reached": {
+			err: &ProviderError{Err: errors.New("connection refused")},
+			exp: true,
+		},
+		"credentials rejected": {
+			err: &ProviderError{StatusCode: http.StatusUnauthorized, Err: errors.New("unauthorized")},
+			exp: false,
+		},
+		"server error": {
+			err: &ProviderError{StatusCode: http.StatusInternalServerError, Err: errors.New("internal error")},
+			exp: true,
+		},
+		"SDK error with server error status": {
+			err: fakeRequestFailure{statusCode: http.StatusServiceUnavailable},
+			exp: true,
+		},
+		"SDK error with client error status": {
+			err: fakeRequestFailure{statusCode: http.StatusForbidden},
+			exp: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := IsTransientProviderError(test.err); actual != test.exp {
+				t.Errorf("expected %t, got: %t", test.exp, actual)
+			}
+		})
+	}
+}
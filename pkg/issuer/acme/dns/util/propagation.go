@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// NameserverTXTRecords are the TXT records served by a single nameserver for
+// a fqdn.
+type NameserverTXTRecords struct {
+	// Nameserver is the address of the nameserver, including the port
+	Nameserver string
+	// Authoritative is true if the nameserver is authoritative for the zone
+	// of the fqdn, and false if it is a recursive nameserver
+	Authoritative bool
+	// Rcode is the response code returned by the nameserver
+	Rcode int
+	// Values are the values of the TXT records returned by the nameserver
+	Values []string
+	// Err is the error that occurred querying the nameserver, if any
+	Err error
+}
+
+// LookupTXTRecordsByNameserver queries each of the authoritative nameservers
+// of fqdn, and each of the given recursive nameservers, for the TXT records of
+// fqdn, following a CNAME in the same way as the DNS01 propagation check.
+// It returns the fqdn that was queried after following any CNAME.
+// If the authoritative nameservers cannot be determined, an error is returned
+// along with the records served by the recursive nameservers.
+func LookupTXTRecordsByNameserver(fqdn string, nameservers []string) (string, []NameserverTXTRecords, error) {
+	r, err := DNSQuery(fqdn, dns.TypeTXT, nameservers, true)
+	if err == nil && r.Rcode == dns.RcodeSuccess {
+		fqdn = updateDomainWithCName(r, fqdn)
+	}
+
+	var records []NameserverTXTRecords
+	authoritativeNss, authErr := lookupNameservers(fqdn, nameservers)
+	for _, ns := range authoritativeNss {
+		records = append(records, lookupTXTRecords(fqdn, net.JoinHostPort(ns, "53"), true))
+	}
+	for _, ns := range nameservers {
+		records = append(records, lookupTXTRecords(fqdn, ns, false))
+	}
+
+	return fqdn, records, authErr
+}
+
+func lookupTXTRecords(fqdn, nameserver string, authoritative bool) NameserverTXTRecords {
+	records := NameserverTXTRecords{Nameserver: nameserver, Authoritative: authoritative}
+	r, err := DNSQuery(fqdn, dns.TypeTXT, []string{nameserver}, true)
+	if err != nil {
+		records.Err = err
+		return records
+	}
+
+	records.Rcode = r.Rcode
+	for _, rr := range r.Answer {
+		if txt, ok := rr.(*dns.TXT); ok {
+			records.Values = append(records.Values, strings.Join(txt.Txt, ""))
+		}
+	}
+	return records
+}
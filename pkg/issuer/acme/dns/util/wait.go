@@ -12,7 +12,6 @@ import (
 	"fmt"
 	"net"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/miekg/dns"
@@ -27,8 +26,9 @@ var (
 	// the DNS challenge is ready.
 	PreCheckDNS preCheckDNSFunc = checkDNSPropagation
 
-	fqdnToZoneLock sync.RWMutex
-	fqdnToZone     = map[string]string{}
+	// zoneCache caches the zones found by FindZoneByFqdn, which is used by
+	// all DNS01 providers
+	zoneCache = NewZoneCache()
 )
 
 const defaultResolvConf = "/etc/resolv.conf"
@@ -300,28 +300,26 @@ func lookupNameservers(fqdn string, nameservers []string) ([]string, error) {
 
 // FindZoneByFqdn determines the zone apex for the given fqdn by recursing up the
 // domain labels until the nameserver returns a SOA record in the answer section.
+// Zones, and fqdns for which no zone was found, are cached, see ZoneCache.
 func FindZoneByFqdn(fqdn string, nameservers []string) (string, error) {
-	fqdnToZoneLock.RLock()
-	// Do we have it cached?
-	if zone, ok := fqdnToZone[fqdn]; ok {
-		fqdnToZoneLock.RUnlock()
-		klog.V(6).Infof("Returning cached zone record %q for fqdn %q", zone, fqdn)
-		return zone, nil
-	}
-	fqdnToZoneLock.RUnlock()
+	return zoneCache.FindZone(fqdn, nameservers)
+}
 
+// findZoneByFqdn is FindZoneByFqdn without caching. It also returns the TTL
+// of the SOA record of the zone.
+func findZoneByFqdn(fqdn string, nameservers []string) (string, time.Duration, error) {
 	labelIndexes := dns.Split(fqdn)
 	for _, index := range labelIndexes {
 		domain := fqdn[index:]
 
 		in, err := DNSQuery(domain, dns.TypeSOA, nameservers, true)
 		if err != nil {
-			return "", err
+			return "", 0, err
 		}
 
 		// Any response code other than NOERROR and NXDOMAIN is treated as error
 		if in.Rcode != dns.RcodeNameError && in.Rcode != dns.RcodeSuccess {
-			return "", fmt.Errorf("Unexpected response code '%s' for %s",
+			return "", 0, fmt.Errorf("Unexpected response code '%s' for %s",
 				dns.RcodeToString[in.Rcode], domain)
 		}
 
@@ -336,19 +334,15 @@ func FindZoneByFqdn(fqdn string, nameservers []string) (string, error) {
 
 			for _, ans := range in.Answer {
 				if soa, ok := ans.(*dns.SOA); ok {
-					fqdnToZoneLock.Lock()
-					defer fqdnToZoneLock.Unlock()
-
 					zone := soa.Hdr.Name
-					fqdnToZone[fqdn] = zone
 					klog.V(6).Infof("Returning discovered zone record %q for fqdn %q", zone, fqdn)
-					return zone, nil
+					return zone, time.Duration(soa.Hdr.Ttl) * time.Second, nil
 				}
 			}
 		}
 	}
 
-	return "", fmt.Errorf("Could not find the start of authority")
+	return "", 0, errNoStartOfAuthority
 }
 
 // dnsMsgContainsCNAME checks for a CNAME answer in msg
@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// DefaultZoneCacheMinTTL is the minimum time a zone is cached for,
+	// even if the TTL of its SOA record is lower.
+	DefaultZoneCacheMinTTL = time.Minute
+	// DefaultZoneCacheMaxTTL is the maximum time a zone is cached for,
+	// even if the TTL of its SOA record is higher.
+	DefaultZoneCacheMaxTTL = time.Hour
+	// DefaultZoneCacheNegativeTTL is the time it is cached that no zone
+	// could be found for a fqdn.
+	DefaultZoneCacheNegativeTTL = time.Minute
+)
+
+// errNoStartOfAuthority is returned if no SOA record was found for any of
+// the domains of a fqdn. It is cached by a ZoneCache, unlike other errors
+// like timeouts, which are not expected to persist.
+var errNoStartOfAuthority = errors.New("Could not find the start of authority")
+
+// zoneLookupFunc finds the zone of fqdn using nameservers, and returns it
+// with the TTL of its SOA record.
+type zoneLookupFunc func(fqdn string, nameservers []string) (string, time.Duration, error)
+
+// ZoneCache caches the zones of fqdns, which are found by querying SOA
+// records, so that the zone does not have to be discovered again for every
+// DNS01 challenge. Zones are cached for the TTL of their SOA record, bounded
+// by MinTTL and MaxTTL, and it is cached for NegativeTTL if no zone could be
+// found. Other errors are not cached.
+// A ZoneCache is safe for concurrent use.
+type ZoneCache struct {
+	MinTTL      time.Duration
+	MaxTTL      time.Duration
+	NegativeTTL time.Duration
+
+	lookup zoneLookupFunc
+	clock  clock.Clock
+
+	lock    sync.Mutex
+	entries map[string]zoneCacheEntry
+}
+
+type zoneCacheEntry struct {
+	zone    string
+	err     error
+	expires time.Time
+}
+
+// NewZoneCache returns a ZoneCache with the default TTLs.
+func NewZoneCache() *ZoneCache {
+	return newZoneCache(findZoneByFqdn, clock.RealClock{})
+}
+
+func newZoneCache(lookup zoneLookupFunc, clock clock.Clock) *ZoneCache {
+	return &ZoneCache{
+		MinTTL:      DefaultZoneCacheMinTTL,
+		MaxTTL:      DefaultZoneCacheMaxTTL,
+		NegativeTTL: DefaultZoneCacheNegativeTTL,
+		lookup:      lookup,
+		clock:       clock,
+		entries:     make(map[string]zoneCacheEntry),
+	}
+}
+
+// FindZone returns the zone of fqdn, looking it up using nameservers if it
+// is not cached.
+func (c *ZoneCache) FindZone(fqdn string, nameservers []string) (string, error) {
+	// the zone may differ between nameservers, e.g. with split-horizon DNS
+	key := fqdn + "@" + strings.Join(nameservers, ",")
+	now := c.clock.Now()
+
+	c.lock.Lock()
+	entry, ok := c.entries[key]
+	c.lock.Unlock()
+	if ok && now.Before(entry.expires) {
+		if entry.err != nil {
+			klog.V(6).Infof("Returning cached error for fqdn %q: %v", fqdn, entry.err)
+		} else {
+			klog.V(6).Infof("Returning cached zone record %q for fqdn %q", entry.zone, fqdn)
+		}
+		return entry.zone, entry.err
+	}
+
+	zone, ttl, err := c.lookup(fqdn, nameservers)
+	switch {
+	case err == errNoStartOfAuthority:
+		ttl = c.NegativeTTL
+	case err != nil:
+		return "", err
+	case ttl < c.MinTTL:
+		ttl = c.MinTTL
+	case ttl > c.MaxTTL:
+		ttl = c.MaxTTL
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.deleteExpired(now)
+	c.entries[key] = zoneCacheEntry{zone: zone, err: err, expires: now.Add(ttl)}
+	return zone, err
+}
+
+// deleteExpired deletes the expired entries, so that the cache does not grow
+// with the fqdns of challenges that have been solved long ago. It must be
+// called with the lock held.
+func (c *ZoneCache) deleteExpired(now time.Time) {
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	fakeclock "k8s.io/utils/clock/testing"
+)
+
+func TestZoneCache(t *testing.T) {
+	type lookupResult struct {
+		zone string
+		ttl  time.Duration
+		err  error
+	}
+	nameservers := []string{"8.8.8.8:53"}
+
+	tests := map[string]struct {
+		results []lookupResult
+		// steps are the times the zone is looked up at, relative to the
+		// first lookup
+		steps []time.Duration
+
+		expZones   []string
+		expErrs    []bool
+		expLookups int
+	}{
+		"zone is cached for the TTL of its SOA record": {
+			results:    []lookupResult{{zone: "example.com.", ttl: 10 * time.Minute}, {zone: "example.com.", ttl: 10 * time.Minute}},
+			steps:      []time.Duration{0, 9 * time.Minute, 10 * time.Minute},
+			expZones:   []string{"example.com.", "example.com.", "example.com."},
+			expErrs:    []bool{false, false, false},
+			expLookups: 2,
+		},
+		"TTL is raised to the minimum": {
+			results:    []lookupResult{{zone: "example.com.", ttl: time.Second}},
+			steps:      []time.Duration{0, 59 * time.Second},
+			expZones:   []string{"example.com.", "example.com."},
+			expErrs:    []bool{false, false},
+			expLookups: 1,
+		},
+		"TTL is lowered to the maximum": {
+			results:    []lookupResult{{zone: "example.com.", ttl: 24 * time.Hour}, {zone: "example.org.", ttl: 24 * time.Hour}},
+			steps:      []time.Duration{0, time.Hour},
+			expZones:   []string{"example.com.", "example.org."},
+			expErrs:    []bool{false, false},
+			expLookups: 2,
+		},
+		"missing zone is cached for the negative TTL": {
+			results:    []lookupResult{{err: errNoStartOfAuthority}, {zone: "example.com.", ttl: time.Hour}},
+			steps:      []time.Duration{0, 30 * time.Second, time.Minute},
+			expZones:   []string{"", "", "example.com."},
+			expErrs:    []bool{true, true, false},
+			expLookups: 2,
+		},
+		"other errors are not cached": {
+			results:    []lookupResult{{err: errors.New("i/o timeout")}, {zone: "example.com.", ttl: time.Hour}},
+			steps:      []time.Duration{0, time.Second},
+			expZones:   []string{"", "example.com."},
+			expErrs:    []bool{true, false},
+			expLookups: 2,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now()
+			clock := fakeclock.NewFakeClock(start)
+			lookups := 0
+			c := newZoneCache(func(fqdn string, ns []string) (string, time.Duration, error) {
+				if lookups >= len(test.results) {
+					t.Fatalf("unexpected lookup of %q", fqdn)
+				}
+				r := test.results[lookups]
+				lookups++
+				return r.zone, r.ttl, r.err
+			}, clock)
+
+			for i, step := range test.steps {
+				clock.SetTime(start.Add(step))
+				zone, err := c.FindZone("_acme-challenge.www.example.com.", nameservers)
+				if zone != test.expZones[i] {
+					t.Errorf("lookup %d: expected zone %q, got %q", i, test.expZones[i], zone)
+				}
+				if (err != nil) != test.expErrs[i] {
+					t.Errorf("lookup %d: expected error %t, got %v", i, test.expErrs[i], err)
+				}
+			}
+			if lookups != test.expLookups {
+				t.Errorf("expected %d lookups, got %d", test.expLookups, lookups)
+			}
+		})
+	}
+}
+
+func TestZoneCacheKeyedByNameservers(t *testing.T) {
+	lookups := 0
+	c := newZoneCache(func(fqdn string, ns []string) (string, time.Duration, error) {
+		lookups++
+		return ns[0] + ".", time.Hour, nil
+	}, fakeclock.NewFakeClock(time.Now()))
+
+	for _, ns := range []string{"internal", "external", "internal"} {
+		zone, err := c.FindZone("example.com.", []string{ns})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if zone != ns+"." {
+			t.Errorf("expected zone %q for nameserver %q, got %q", ns+".", ns, zone)
+		}
+	}
+	if lookups != 2 {
+		t.Errorf("expected 2 lookups, got %d", lookups)
+	}
+}
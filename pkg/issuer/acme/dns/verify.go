@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/controller"
+)
+
+// verifier is implemented by DNS providers that can check that their
+// credentials are accepted by the API of the provider without changing any
+// records, e.g. by listing zones.
+type verifier interface {
+	Verify() error
+}
+
+// CredentialVerifier checks the credentials of the DNS01 providers
+// configured on issuers, so that invalid credentials are reported on the
+// issuer before they are first used to solve a challenge.
+type CredentialVerifier struct {
+	solver *Solver
+}
+
+// NewCredentialVerifier returns a CredentialVerifier loading the credentials
+// of providers with the listers and options of the given context.
+func NewCredentialVerifier(ctx *controller.Context) *CredentialVerifier {
+	return &CredentialVerifier{
+		solver: &Solver{
+			Context:                 ctx,
+			secretLister:            ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
+			dnsProviderConstructors: defaultDNSProviderConstructors,
+		},
+	}
+}
+
+// Verify constructs the DNS01 provider configured in config with the
+// credentials it references, and checks them against the API of the
+// provider if it supports it. It returns false if the provider cannot be
+// verified, which is the case for webhook providers and providers that
+// cannot check their credentials without changing records.
+func (v *CredentialVerifier) Verify(ctx context.Context, issuer v1alpha2.GenericIssuer, config *cmacme.ACMEChallengeSolverDNS01) (bool, error) {
+	if config.Webhook != nil || config.RFC2136 != nil {
+		return false, nil
+	}
+
+	slv, _, err := v.solver.solverForConfig(ctx, issuer, config)
+	if err != nil {
+		return false, err
+	}
+
+	ver, ok := slv.(verifier)
+	if !ok {
+		return false, nil
+	}
+	return true, ver.Verify()
+}
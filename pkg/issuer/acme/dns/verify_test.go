@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+	"testing"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestCredentialVerifier(t *testing.T) {
+	tests := map[string]struct {
+		config      *cmacme.ACMEChallengeSolverDNS01
+		expVerified bool
+		expErr      bool
+	}{
+		"webhook providers are not verified": {
+			config: &cmacme.ACMEChallengeSolverDNS01{
+				Webhook: &cmacme.ACMEIssuerDNS01ProviderWebhook{GroupName: "example.com", SolverName: "test"},
+			},
+		},
+		"missing credentials Secret is an error": {
+			config: &cmacme.ACMEChallengeSolverDNS01{
+				DigitalOcean: &cmacme.ACMEIssuerDNS01ProviderDigitalOcean{
+					Token: cmmeta.SecretKeySelector{
+						LocalObjectReference: cmmeta.LocalObjectReference{Name: "does-not-exist"},
+						Key:                  "token",
+					},
+				},
+			},
+			expErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := &solverFixture{Issuer: newIssuer("test", "default")}
+			f.Setup(t)
+			defer f.Finish(t)
+
+			v := &CredentialVerifier{solver: f.Solver}
+			verified, err := v.Verify(context.Background(), f.Issuer, test.config)
+			if (err != nil) != test.expErr {
+				t.Errorf("expected error=%t, got: %v", test.expErr, err)
+			}
+			if verified != test.expVerified {
+				t.Errorf("expected verified=%t, got: %t", test.expVerified, verified)
+			}
+			if len(f.dnsProviders.calls) != 0 {
+				t.Errorf("expected no provider to be constructed, got: %v", f.dnsProviders.calls)
+			}
+		})
+	}
+}
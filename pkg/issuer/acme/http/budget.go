@@ -0,0 +1,264 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+// ResourceBudgetError is returned by Present if the solver resources for a
+// challenge would be rejected by a ResourceQuota or LimitRange of the
+// namespace of the challenge. Retrying does not help until the ResourceQuota
+// or LimitRange is changed, or other resources are deleted.
+type ResourceBudgetError struct {
+	// Reasons are the reasons the solver resources would be rejected
+	Reasons []string
+}
+
+func (e *ResourceBudgetError) Error() string {
+	return "the HTTP01 solver resources would be rejected: " + strings.Join(e.Reasons, "; ")
+}
+
+// checkResourceBudget checks that the solver resources that do not exist yet
+// for ch are compatible with the LimitRanges of its namespace and fit into
+// the remaining budget of its ResourceQuotas, and returns a
+// ResourceBudgetError if they are not.
+// The check is best effort: ResourceQuotas with scopes are ignored, as are
+// errors listing ResourceQuotas and LimitRanges, in which case the API
+// server still enforces them when the resources are created.
+func (s *Solver) checkResourceBudget(ctx context.Context, ch *cmacme.Challenge) error {
+	log := logf.FromContext(ctx).WithName("checkResourceBudget")
+
+	var pod *corev1.Pod
+	required := corev1.ResourceList{}
+	add := func(name corev1.ResourceName, q resource.Quantity) {
+		sum := required[name]
+		sum.Add(q)
+		required[name] = sum
+	}
+	one := *resource.NewQuantity(1, resource.DecimalSI)
+
+	pods, err := s.getPodsForChallenge(ctx, ch)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		pod = s.buildPod(ch)
+		for name, q := range podUsage(pod) {
+			add(name, q)
+		}
+	}
+
+	svcs, err := s.getServicesForChallenge(ctx, ch)
+	if err != nil {
+		return err
+	}
+	if len(svcs) == 0 {
+		svc, err := buildService(ch)
+		if err != nil {
+			return err
+		}
+		add(corev1.ResourceServices, one)
+		add("count/services", one)
+		switch svc.Spec.Type {
+		case corev1.ServiceTypeNodePort:
+			add(corev1.ResourceServicesNodePorts, one)
+		case corev1.ServiceTypeLoadBalancer:
+			add(corev1.ResourceServicesNodePorts, one)
+			add(corev1.ResourceServicesLoadBalancers, one)
+		}
+	}
+
+	httpDomainCfg, err := httpDomainCfgForChallenge(ch)
+	if err != nil {
+		return err
+	}
+	if httpDomainCfg.Name == "" {
+		ings, err := s.getIngressesForChallenge(ctx, ch)
+		if err != nil {
+			return err
+		}
+		if len(ings) == 0 {
+			add("count/ingresses.extensions", one)
+			add("count/ingresses.networking.k8s.io", one)
+		}
+	}
+
+	if len(required) == 0 {
+		return nil
+	}
+
+	var reasons []string
+	if pod != nil {
+		limitRanges, err := s.Client.CoreV1().LimitRanges(ch.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			log.V(logf.DebugLevel).Info("skipping LimitRange check of solver pod", "error", err.Error())
+		} else {
+			reasons = append(reasons, checkLimitRanges(pod, limitRanges.Items)...)
+		}
+	}
+	quotas, err := s.Client.CoreV1().ResourceQuotas(ch.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.V(logf.DebugLevel).Info("skipping ResourceQuota check of solver resources", "error", err.Error())
+	} else {
+		reasons = append(reasons, checkResourceQuotas(required, quotas.Items)...)
+	}
+
+	if len(reasons) > 0 {
+		return &ResourceBudgetError{Reasons: reasons}
+	}
+	return nil
+}
+
+// podUsage returns the usage of pod charged to ResourceQuotas
+func podUsage(pod *corev1.Pod) corev1.ResourceList {
+	usage := corev1.ResourceList{
+		corev1.ResourcePods: *resource.NewQuantity(1, resource.DecimalSI),
+		"count/pods":        *resource.NewQuantity(1, resource.DecimalSI),
+	}
+	add := func(name corev1.ResourceName, q resource.Quantity) {
+		sum := usage[name]
+		sum.Add(q)
+		usage[name] = sum
+	}
+	for _, c := range pod.Spec.Containers {
+		if q, ok := c.Resources.Requests[corev1.ResourceCPU]; ok {
+			add(corev1.ResourceCPU, q)
+			add(corev1.ResourceRequestsCPU, q)
+		}
+		if q, ok := c.Resources.Requests[corev1.ResourceMemory]; ok {
+			add(corev1.ResourceMemory, q)
+			add(corev1.ResourceRequestsMemory, q)
+		}
+		if q, ok := c.Resources.Limits[corev1.ResourceCPU]; ok {
+			add(corev1.ResourceLimitsCPU, q)
+		}
+		if q, ok := c.Resources.Limits[corev1.ResourceMemory]; ok {
+			add(corev1.ResourceLimitsMemory, q)
+		}
+	}
+	return usage
+}
+
+// checkResourceQuotas returns the reasons the required resources exceed the
+// remaining budget of quotas. Quotas with scopes are ignored.
+func checkResourceQuotas(required corev1.ResourceList, quotas []corev1.ResourceQuota) []string {
+	var reasons []string
+	for _, quota := range quotas {
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+		for _, name := range sortedResourceNames(required) {
+			hard, ok := quota.Status.Hard[name]
+			if !ok {
+				continue
+			}
+			used := quota.Status.Used[name]
+			total := used.DeepCopy()
+			total.Add(required[name])
+			if total.Cmp(hard) > 0 {
+				req := required[name]
+				reasons = append(reasons, fmt.Sprintf("exceeded ResourceQuota %q: requested %s=%s, used %s, limited %s",
+					quota.Name, name, req.String(), used.String(), hard.String()))
+			}
+		}
+	}
+	return reasons
+}
+
+// checkLimitRanges returns the reasons the containers of pod violate the
+// container and pod limits of limitRanges.
+func checkLimitRanges(pod *corev1.Pod, limitRanges []corev1.LimitRange) []string {
+	var reasons []string
+	for _, lr := range limitRanges {
+		for _, item := range lr.Spec.Limits {
+			switch item.Type {
+			case corev1.LimitTypeContainer:
+				for _, c := range pod.Spec.Containers {
+					reasons = append(reasons, checkLimitRangeItem(lr.Name, "container "+c.Name, item, c.Resources)...)
+				}
+			case corev1.LimitTypePod:
+				podResources := corev1.ResourceRequirements{Requests: corev1.ResourceList{}, Limits: corev1.ResourceList{}}
+				for _, c := range pod.Spec.Containers {
+					for name, q := range c.Resources.Requests {
+						sum := podResources.Requests[name]
+						sum.Add(q)
+						podResources.Requests[name] = sum
+					}
+					for name, q := range c.Resources.Limits {
+						sum := podResources.Limits[name]
+						sum.Add(q)
+						podResources.Limits[name] = sum
+					}
+				}
+				reasons = append(reasons, checkLimitRangeItem(lr.Name, "pod", item, podResources)...)
+			}
+		}
+	}
+	return reasons
+}
+
+func checkLimitRangeItem(limitRange, subject string, item corev1.LimitRangeItem, resources corev1.ResourceRequirements) []string {
+	var reasons []string
+	for _, name := range sortedResourceNames(item.Min) {
+		min := item.Min[name]
+		if q, ok := resources.Requests[name]; ok && q.Cmp(min) < 0 {
+			reasons = append(reasons, fmt.Sprintf("LimitRange %q: %s request of %s %s is below the minimum of %s",
+				limitRange, name, subject, q.String(), min.String()))
+		}
+	}
+	for _, name := range sortedResourceNames(item.Max) {
+		max := item.Max[name]
+		if q, ok := resources.Limits[name]; ok && q.Cmp(max) > 0 {
+			reasons = append(reasons, fmt.Sprintf("LimitRange %q: %s limit of %s %s is above the maximum of %s",
+				limitRange, name, subject, q.String(), max.String()))
+		}
+	}
+	for _, name := range sortedResourceNames(item.MaxLimitRequestRatio) {
+		maxRatio := item.MaxLimitRequestRatio[name]
+		req, reqOK := resources.Requests[name]
+		limit, limitOK := resources.Limits[name]
+		if !reqOK || !limitOK || req.IsZero() {
+			continue
+		}
+		ratio := float64(limit.MilliValue()) / float64(req.MilliValue())
+		if ratio > float64(maxRatio.MilliValue())/1000 {
+			reasons = append(reasons, fmt.Sprintf("LimitRange %q: %s limit to request ratio of %s is %.2f, above the maximum of %s",
+				limitRange, name, subject, ratio, maxRatio.String()))
+		}
+	}
+	return reasons
+}
+
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
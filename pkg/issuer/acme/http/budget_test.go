@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func solverPod(requestCPU, limitCPU string) *corev1.Pod {
+	return &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "acmesolver",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse(requestCPU),
+							corev1.ResourceMemory: resource.MustParse("64Mi"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse(limitCPU),
+							corev1.ResourceMemory: resource.MustParse("64Mi"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCheckResourceQuotas(t *testing.T) {
+	quota := func(name string, hard, used corev1.ResourceList, scopes ...corev1.ResourceQuotaScope) corev1.ResourceQuota {
+		return corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.ResourceQuotaSpec{Scopes: scopes},
+			Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+		}
+	}
+	required := podUsage(solverPod("10m", "100m"))
+	required[corev1.ResourceServices] = resource.MustParse("1")
+
+	tests := map[string]struct {
+		quotas  []corev1.ResourceQuota
+		reasons []string
+	}{
+		"no quotas": {},
+		"quota with remaining budget": {
+			quotas: []corev1.ResourceQuota{
+				quota("q", corev1.ResourceList{
+					corev1.ResourcePods:        resource.MustParse("2"),
+					corev1.ResourceRequestsCPU: resource.MustParse("1"),
+				}, corev1.ResourceList{
+					corev1.ResourcePods:        resource.MustParse("1"),
+					corev1.ResourceRequestsCPU: resource.MustParse("990m"),
+				}),
+			},
+		},
+		"quota without remaining budget": {
+			quotas: []corev1.ResourceQuota{
+				quota("q", corev1.ResourceList{
+					corev1.ResourcePods:      resource.MustParse("2"),
+					corev1.ResourceServices:  resource.MustParse("1"),
+					corev1.ResourceLimitsCPU: resource.MustParse("1"),
+				}, corev1.ResourceList{
+					corev1.ResourcePods:      resource.MustParse("2"),
+					corev1.ResourceServices:  resource.MustParse("0"),
+					corev1.ResourceLimitsCPU: resource.MustParse("950m"),
+				}),
+			},
+			reasons: []string{
+				`exceeded ResourceQuota "q": requested limits.cpu=100m, used 950m, limited 1`,
+				`exceeded ResourceQuota "q": requested pods=1, used 2, limited 2`,
+			},
+		},
+		"scoped quotas are ignored": {
+			quotas: []corev1.ResourceQuota{
+				quota("q", corev1.ResourceList{
+					corev1.ResourcePods: resource.MustParse("0"),
+				}, nil, corev1.ResourceQuotaScopeBestEffort),
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			reasons := checkResourceQuotas(required, test.quotas)
+			if !reflect.DeepEqual(reasons, test.reasons) {
+				t.Errorf("expected reasons %q, got %q", test.reasons, reasons)
+			}
+		})
+	}
+}
+
+func TestCheckLimitRanges(t *testing.T) {
+	limitRange := func(items ...corev1.LimitRangeItem) []corev1.LimitRange {
+		return []corev1.LimitRange{{
+			ObjectMeta: metav1.ObjectMeta{Name: "lr"},
+			Spec:       corev1.LimitRangeSpec{Limits: items},
+		}}
+	}
+
+	tests := map[string]struct {
+		limitRanges []corev1.LimitRange
+		reasons     []string
+	}{
+		"no limit ranges": {},
+		"compatible limit range": {
+			limitRanges: limitRange(corev1.LimitRangeItem{
+				Type: corev1.LimitTypeContainer,
+				Min:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+				Max:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				MaxLimitRequestRatio: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("10"),
+				},
+			}),
+		},
+		"container limits violated": {
+			limitRanges: limitRange(corev1.LimitRangeItem{
+				Type: corev1.LimitTypeContainer,
+				Min:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+				Max:  corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("32Mi")},
+				MaxLimitRequestRatio: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("2"),
+				},
+			}),
+			reasons: []string{
+				`LimitRange "lr": cpu request of container acmesolver 10m is below the minimum of 50m`,
+				`LimitRange "lr": memory limit of container acmesolver 64Mi is above the maximum of 32Mi`,
+				`LimitRange "lr": cpu limit to request ratio of container acmesolver is 10.00, above the maximum of 2`,
+			},
+		},
+		"pod limits violated": {
+			limitRanges: limitRange(corev1.LimitRangeItem{
+				Type: corev1.LimitTypePod,
+				Max:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+			}),
+			reasons: []string{
+				`LimitRange "lr": cpu limit of pod 100m is above the maximum of 50m`,
+			},
+		},
+		"other limit types are ignored": {
+			limitRanges: limitRange(corev1.LimitRangeItem{
+				Type: corev1.LimitTypePersistentVolumeClaim,
+				Max:  corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+			}),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			reasons := checkLimitRanges(solverPod("10m", "100m"), test.limitRanges)
+			if !reflect.DeepEqual(reasons, test.reasons) {
+				t.Errorf("expected reasons %q, got %q", test.reasons, reasons)
+			}
+		})
+	}
+}
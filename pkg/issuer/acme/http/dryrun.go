@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/controller"
+)
+
+// dryRunServiceName is the name the solver Ingress is built with if the
+// dry-run create of the solver Service does not return a generated name.
+const dryRunServiceName = "cm-acme-http-solver-dry-run"
+
+// DryRunResult is the outcome of the server-side dry-run of a single solver
+// resource.
+type DryRunResult struct {
+	// Kind is the kind of the solver resource, e.g. Pod
+	Kind string
+	// Name is the name of the resource returned by the API server, or of the
+	// existing Ingress the solver is configured to use
+	Name string
+	// Err is the error returned by the API server, if any
+	Err error
+}
+
+// DryRun performs a server-side dry-run of the requests the HTTP01 solver
+// makes to the API server to present ch, using the given solver pod options.
+// This verifies that the solver resources would be admitted, e.g. that RBAC,
+// resource quotas and pod security policies allow them, without persisting
+// anything.
+// An error is returned only if the solver resources cannot be built for ch.
+func DryRun(ctx context.Context, client kubernetes.Interface, ch *cmacme.Challenge, opts controller.ACMEOptions) ([]DryRunResult, error) {
+	httpDomainCfg, err := httpDomainCfgForChallenge(ch)
+	if err != nil {
+		return nil, err
+	}
+	s := &Solver{Context: &controller.Context{Client: client, ACMEOptions: opts}}
+	createOpts := metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}
+
+	var results []DryRunResult
+
+	pod, err := client.CoreV1().Pods(ch.Namespace).Create(ctx, s.buildPod(ch), createOpts)
+	results = append(results, dryRunResult("Pod", pod, err))
+
+	svc, err := buildService(ch)
+	if err != nil {
+		return nil, err
+	}
+	svcName := dryRunServiceName
+	svc, err = client.CoreV1().Services(ch.Namespace).Create(ctx, svc, createOpts)
+	if err == nil && svc.Name != "" {
+		svcName = svc.Name
+	}
+	results = append(results, dryRunResult("Service", svc, err))
+
+	if httpDomainCfg.Name == "" {
+		ing, err := s.buildIngress(ch, svcName)
+		if err != nil {
+			return nil, err
+		}
+		ing, err = client.ExtensionsV1beta1().Ingresses(ch.Namespace).Create(ctx, ing, createOpts)
+		results = append(results, dryRunResult("Ingress", ing, err))
+		return results, nil
+	}
+
+	// The solver adds the challenge path to the existing Ingress instead of
+	// creating one.
+	result := DryRunResult{Kind: "Ingress", Name: httpDomainCfg.Name}
+	ing, err := client.ExtensionsV1beta1().Ingresses(ch.Namespace).Get(ctx, httpDomainCfg.Name, metav1.GetOptions{})
+	if err == nil && addChallengePath(ing, ch, svcName) {
+		_, err = client.ExtensionsV1beta1().Ingresses(ch.Namespace).Update(ctx, ing, metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}})
+	}
+	result.Err = err
+	return append(results, result), nil
+}
+
+func dryRunResult(kind string, obj metav1.Object, err error) DryRunResult {
+	result := DryRunResult{Kind: kind, Err: err}
+	if err == nil {
+		result.Name = obj.GetName()
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	coretesting "k8s.io/client-go/testing"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/controller"
+)
+
+func TestDryRun(t *testing.T) {
+	newChallenge := func(ingressName string) *cmacme.Challenge {
+		return &cmacme.Challenge{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+			Spec: cmacme.ChallengeSpec{
+				DNSName: "example.com",
+				Token:   "token",
+				Key:     "key",
+				Solver: cmacme.ACMEChallengeSolver{
+					HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+						Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{Name: ingressName},
+					},
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		challenge *cmacme.Challenge
+		objects   []runtime.Object
+		forbidden string
+
+		expErr      bool
+		expKinds    []string
+		expFailures []string
+	}{
+		"all solver resources are admitted": {
+			challenge: newChallenge(""),
+			expKinds:  []string{"Pod", "Service", "Ingress"},
+		},
+		"solver pods are forbidden": {
+			challenge:   newChallenge(""),
+			forbidden:   "pods",
+			expKinds:    []string{"Pod", "Service", "Ingress"},
+			expFailures: []string{"Pod"},
+		},
+		"existing ingress is updated": {
+			challenge: newChallenge("existing"),
+			objects: []runtime.Object{&extv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "existing"},
+			}},
+			expKinds: []string{"Pod", "Service", "Ingress"},
+		},
+		"existing ingress does not exist": {
+			challenge:   newChallenge("existing"),
+			expKinds:    []string{"Pod", "Service", "Ingress"},
+			expFailures: []string{"Ingress"},
+		},
+		"challenge without HTTP01 ingress config": {
+			challenge: &cmacme.Challenge{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+			},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(test.objects...)
+			if test.forbidden != "" {
+				client.PrependReactor("create", test.forbidden, func(action coretesting.Action) (bool, runtime.Object, error) {
+					return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: test.forbidden}, "", errors.New("denied by policy"))
+				})
+			}
+
+			results, err := DryRun(context.TODO(), client, test.challenge, controller.ACMEOptions{HTTP01SolverImage: "acmesolver"})
+			if (err != nil) != test.expErr {
+				t.Fatalf("expected error: %t, got: %v", test.expErr, err)
+			}
+
+			if len(results) != len(test.expKinds) {
+				t.Fatalf("expected %d results, got: %+v", len(test.expKinds), results)
+			}
+			failures := map[string]bool{}
+			for _, kind := range test.expFailures {
+				failures[kind] = true
+			}
+			for i, result := range results {
+				if result.Kind != test.expKinds[i] {
+					t.Errorf("expected result %d to be for a %s, got: %s", i, test.expKinds[i], result.Kind)
+				}
+				if (result.Err != nil) != failures[result.Kind] {
+					t.Errorf("unexpected error for %s: %v", result.Kind, result.Err)
+				}
+			}
+		})
+	}
+}
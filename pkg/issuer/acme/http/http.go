@@ -48,6 +48,10 @@ const (
 	domainLabelKey               = "acme.cert-manager.io/http-domain"
 	tokenLabelKey                = "acme.cert-manager.io/http-token"
 	solverIdentificationLabelKey = "acme.cert-manager.io/http01-solver"
+
+	// fieldManagerController is the name of the controller the solver is run
+	// by, which the field manager of the resources it writes is derived from
+	fieldManagerController = "challenges"
 )
 
 var (
@@ -99,6 +103,12 @@ func httpDomainCfgForChallenge(ch *cmacme.Challenge) (*cmacme.ACMEChallengeSolve
 func (s *Solver) Present(ctx context.Context, issuer v1alpha2.GenericIssuer, ch *cmacme.Challenge) error {
 	ctx = http01LogCtx(ctx)
 
+	// return early, rather than retrying until the ResourceQuota or
+	// LimitRange of the namespace is changed
+	if err := s.checkResourceBudget(ctx, ch); err != nil {
+		return err
+	}
+
 	_, podErr := s.ensurePod(ctx, ch)
 	svc, svcErr := s.ensureService(ctx, ch)
 	if svcErr != nil {
@@ -18,20 +18,25 @@ package http
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
 
 	extv1beta1 "k8s.io/api/extensions/v1beta1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/feature"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/http/solver"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
 )
 
 // getIngressesForChallenge returns a list of Ingresses that were created to solve
@@ -119,10 +124,56 @@ func ingressServiceName(ing *extv1beta1.Ingress) string {
 // createIngress will create a challenge solving ingress for the given certificate,
 // domain, token and key.
 func (s *Solver) createIngress(ch *cmacme.Challenge, svcName string) (*extv1beta1.Ingress, error) {
+	ing, err := s.buildIngress(ch, svcName)
+	if err != nil {
+		return nil, err
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		return s.applyIngress(ch, ing)
+	}
+	return s.Client.ExtensionsV1beta1().Ingresses(ch.Namespace).Create(context.TODO(), ing, metav1.CreateOptions{FieldManager: s.FieldManagerFor(fieldManagerController)})
+}
+
+// applyIngress writes the challenge solving ingress ing with server-side
+// apply. Applied resources cannot have a generated name, so the ingress is
+// named after the challenge instead.
+func (s *Solver) applyIngress(ch *cmacme.Challenge, ing *extv1beta1.Ingress) (*extv1beta1.Ingress, error) {
+	ing.TypeMeta = metav1.TypeMeta{
+		APIVersion: extv1beta1.SchemeGroupVersion.String(),
+		Kind:       "Ingress",
+	}
+	ing.GenerateName = ""
+	ing.Name = solverIngressName(ch)
+
+	body, err := json.Marshal(ing)
+	if err != nil {
+		return nil, err
+	}
+
+	force := true
+	return s.Client.ExtensionsV1beta1().Ingresses(ing.Namespace).Patch(context.TODO(), ing.Name, types.ApplyPatchType, body, metav1.PatchOptions{
+		FieldManager: s.FieldManagerFor(fieldManagerController),
+		Force:        &force,
+	})
+}
+
+// solverIngressName returns the name of the challenge solving ingress of ch
+// when it is written with server-side apply.
+func solverIngressName(ch *cmacme.Challenge) string {
+	hashF := fnv.New64a()
+	hashF.Write([]byte(ch.Name))
+	hashF.Write([]byte(ch.UID))
+	return fmt.Sprintf("cm-acme-http-solver-%x", hashF.Sum64())
+}
+
+// buildIngress will build a challenge solving ingress for the given
+// certificate, domain, token and key. It will not create it in the API server
+func (s *Solver) buildIngress(ch *cmacme.Challenge, svcName string) (*extv1beta1.Ingress, error) {
 	ing, err := buildIngressResource(ch, svcName)
 	if err != nil {
 		return nil, err
 	}
+	s.propagateChallengeLabels(ch, &ing.ObjectMeta)
 
 	// Override the defaults if they have changed in the ingress template.
 	if ch.Spec.Solver.HTTP01 != nil &&
@@ -130,7 +181,7 @@ func (s *Solver) createIngress(ch *cmacme.Challenge, svcName string) (*extv1beta
 		ing = s.mergeIngressObjectMetaWithIngressResourceTemplate(ing, ch.Spec.Solver.HTTP01.Ingress.IngressTemplate)
 	}
 
-	return s.Client.ExtensionsV1beta1().Ingresses(ch.Namespace).Create(context.TODO(), ing, metav1.CreateOptions{})
+	return ing, nil
 }
 
 func buildIngressResource(ch *cmacme.Challenge, svcName string) (*extv1beta1.Ingress, error) {
@@ -216,6 +267,21 @@ func (s *Solver) addChallengePathToIngress(ctx context.Context, ch *cmacme.Chall
 		return nil, err
 	}
 
+	ing = ing.DeepCopy()
+	if !addChallengePath(ing, ch, svcName) {
+		// ingress resource is already up to date
+		return ing, nil
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		return s.patchIngressRules(ctx, ing)
+	}
+	return s.Client.ExtensionsV1beta1().Ingresses(ing.Namespace).Update(context.TODO(), ing, metav1.UpdateOptions{FieldManager: s.FieldManagerFor(fieldManagerController)})
+}
+
+// addChallengePath adds the path of the challenge to the rule for its domain
+// on ing, adding a new rule if none exists. It returns false if ing already
+// routes the challenge path to svcName, and so was not modified.
+func addChallengePath(ing *extv1beta1.Ingress, ch *cmacme.Challenge, svcName string) bool {
 	ingPathToAdd := ingressPath(ch.Spec.Token, svcName)
 	// check for an existing Rule for the given domain on the ingress resource
 	for _, rule := range ing.Spec.Rules {
@@ -227,17 +293,16 @@ func (s *Solver) addChallengePathToIngress(ctx context.Context, ch *cmacme.Chall
 				// if an existing path exists on this rule for the challenge path,
 				// we overwrite it else we'll confuse ingress controllers
 				if p.Path == ingPathToAdd.Path {
-					// ingress resource is already up to date
 					if p.Backend.ServiceName == ingPathToAdd.Backend.ServiceName &&
 						p.Backend.ServicePort == ingPathToAdd.Backend.ServicePort {
-						return ing, nil
+						return false
 					}
 					rule.HTTP.Paths[i] = ingPathToAdd
-					return s.Client.ExtensionsV1beta1().Ingresses(ing.Namespace).Update(context.TODO(), ing, metav1.UpdateOptions{})
+					return true
 				}
 			}
 			rule.HTTP.Paths = append([]extv1beta1.HTTPIngressPath{ingPathToAdd}, rule.HTTP.Paths...)
-			return s.Client.ExtensionsV1beta1().Ingresses(ing.Namespace).Update(context.TODO(), ing, metav1.UpdateOptions{})
+			return true
 		}
 	}
 
@@ -250,7 +315,26 @@ func (s *Solver) addChallengePathToIngress(ctx context.Context, ch *cmacme.Chall
 			},
 		},
 	})
-	return s.Client.ExtensionsV1beta1().Ingresses(ing.Namespace).Update(context.TODO(), ing, metav1.UpdateOptions{})
+	return true
+}
+
+// patchIngressRules writes the rules of an existing ingress that is not
+// managed by cert-manager with a JSON patch, leaving all of its other fields
+// untouched. The rules are an atomic list, so they are not applied, which
+// would make cert-manager the owner of all rules of the ingress. The patch
+// fails if the ingress has been changed since it was read.
+func (s *Solver) patchIngressRules(ctx context.Context, ing *extv1beta1.Ingress) (*extv1beta1.Ingress, error) {
+	body, err := json.Marshal([]map[string]interface{}{
+		{"op": "test", "path": "/metadata/resourceVersion", "value": ing.ResourceVersion},
+		{"op": "add", "path": "/spec/rules", "value": ing.Spec.Rules},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Client.ExtensionsV1beta1().Ingresses(ing.Namespace).Patch(ctx, ing.Name, types.JSONPatchType, body, metav1.PatchOptions{
+		FieldManager: s.FieldManagerFor(fieldManagerController),
+	})
 }
 
 // cleanupIngresses will remove the rules added by cert-manager to an existing
@@ -332,7 +416,11 @@ func (s *Solver) cleanupIngresses(ctx context.Context, ch *cmacme.Challenge) err
 
 	ing.Spec.Rules = ingRules
 
-	_, err = s.Client.ExtensionsV1beta1().Ingresses(ing.Namespace).Update(context.TODO(), ing, metav1.UpdateOptions{})
+	if utilfeature.DefaultFeatureGate.Enabled(feature.ServerSideApply) {
+		_, err = s.patchIngressRules(ctx, ing)
+	} else {
+		_, err = s.Client.ExtensionsV1beta1().Ingresses(ing.Namespace).Update(context.TODO(), ing, metav1.UpdateOptions{FieldManager: s.FieldManagerFor(fieldManagerController)})
+	}
 	if err != nil {
 		return err
 	}
@@ -514,3 +514,20 @@ func TestMergeIngressObjectMetaWithIngressResourceTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestSolverIngressName(t *testing.T) {
+	ch := &cmacme.Challenge{ObjectMeta: metav1.ObjectMeta{Name: "test", UID: "uid-1"}}
+	name := solverIngressName(ch)
+	if len(name) > 63 || name[:len("cm-acme-http-solver-")] != "cm-acme-http-solver-" {
+		t.Errorf("unexpected ingress name %q", name)
+	}
+	if solverIngressName(ch.DeepCopy()) != name {
+		t.Errorf("expected the ingress name to be deterministic")
+	}
+
+	recreated := ch.DeepCopy()
+	recreated.UID = "uid-2"
+	if solverIngressName(recreated) == name {
+		t.Errorf("expected a re-created challenge to use a different ingress name")
+	}
+}
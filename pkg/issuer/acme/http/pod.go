@@ -46,6 +46,34 @@ func podLabels(ch *cmacme.Challenge) map[string]string {
 	}
 }
 
+// propagateChallengeLabels copies the labels of the given Challenge that are
+// named in the HTTP01SolverPropagatedLabels option onto the metadata of a
+// solver resource. Labels already set on the resource, such as those used to
+// select solver resources, are never overwritten.
+func (s *Solver) propagateChallengeLabels(ch *cmacme.Challenge, meta *metav1.ObjectMeta) {
+	if len(s.HTTP01SolverPropagatedLabels) == 0 {
+		return
+	}
+
+	// copy the existing labels, as the map may be shared with other fields
+	// such as a Service's selector
+	lbls := make(map[string]string, len(meta.Labels))
+	for k, v := range meta.Labels {
+		lbls[k] = v
+	}
+	for _, key := range s.HTTP01SolverPropagatedLabels {
+		value, ok := ch.Labels[key]
+		if !ok {
+			continue
+		}
+		if _, exists := lbls[key]; exists {
+			continue
+		}
+		lbls[key] = value
+	}
+	meta.Labels = lbls
+}
+
 func (s *Solver) ensurePod(ctx context.Context, ch *cmacme.Challenge) (*corev1.Pod, error) {
 	log := logf.FromContext(ctx).WithName("ensurePod")
 
@@ -135,13 +163,14 @@ func (s *Solver) createPod(ch *cmacme.Challenge) (*corev1.Pod, error) {
 	return s.Client.CoreV1().Pods(ch.Namespace).Create(
 		context.TODO(),
 		s.buildPod(ch),
-		metav1.CreateOptions{})
+		metav1.CreateOptions{FieldManager: s.FieldManagerFor(fieldManagerController)})
 }
 
 // buildPod will build a challenge solving pod for the given certificate,
 // domain, token and key. It will not create it in the API server
 func (s *Solver) buildPod(ch *cmacme.Challenge) *corev1.Pod {
 	pod := s.buildDefaultPod(ch)
+	s.propagateChallengeLabels(ch, &pod.ObjectMeta)
 
 	// Override defaults if they have changed in the pod template.
 	if ch.Spec.Solver.HTTP01 != nil &&
@@ -156,6 +185,11 @@ func (s *Solver) buildPod(ch *cmacme.Challenge) *corev1.Pod {
 func (s *Solver) buildDefaultPod(ch *cmacme.Challenge) *corev1.Pod {
 	podLabels := podLabels(ch)
 
+	var runtimeClassName *string
+	if s.ACMEOptions.HTTP01SolverRuntimeClassName != "" {
+		runtimeClassName = &s.ACMEOptions.HTTP01SolverRuntimeClassName
+	}
+
 	return &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "cm-acme-http-solver-",
@@ -167,7 +201,10 @@ func (s *Solver) buildDefaultPod(ch *cmacme.Challenge) *corev1.Pod {
 			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ch, challengeGvk)},
 		},
 		Spec: corev1.PodSpec{
-			RestartPolicy: corev1.RestartPolicyOnFailure,
+			RestartPolicy:             corev1.RestartPolicyOnFailure,
+			PriorityClassName:         s.ACMEOptions.HTTP01SolverPriorityClassName,
+			RuntimeClassName:          runtimeClassName,
+			TopologySpreadConstraints: s.topologySpreadConstraints(),
 			Containers: []corev1.Container{
 				{
 					Name: "acmesolver",
@@ -249,9 +286,34 @@ func (s *Solver) mergePodObjectMetaWithPodTemplate(pod *corev1.Pod, podTempl *cm
 		pod.Spec.PriorityClassName = podTempl.Spec.PriorityClassName
 	}
 
+	if podTempl.Spec.RuntimeClassName != nil {
+		pod.Spec.RuntimeClassName = podTempl.Spec.RuntimeClassName
+	}
+
+	pod.Spec.TopologySpreadConstraints = append(pod.Spec.TopologySpreadConstraints, podTempl.Spec.TopologySpreadConstraints...)
+
 	if podTempl.Spec.ServiceAccountName != "" {
 		pod.Spec.ServiceAccountName = podTempl.Spec.ServiceAccountName
 	}
 
 	return pod
 }
+
+// topologySpreadConstraints returns the constraints spreading solver pods
+// across the values of the configured node label keys. The pods are spread
+// on a best-effort basis, as a solver pod that cannot be scheduled fails its
+// challenge.
+func (s *Solver) topologySpreadConstraints() []corev1.TopologySpreadConstraint {
+	var constraints []corev1.TopologySpreadConstraint
+	for _, key := range s.ACMEOptions.HTTP01SolverTopologySpreadKeys {
+		constraints = append(constraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       key,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{solverIdentificationLabelKey: "true"},
+			},
+		})
+	}
+	return constraints
+}
@@ -22,11 +22,13 @@ import (
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	coretesting "k8s.io/client-go/testing"
 
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/controller"
 )
 
 func TestEnsurePod(t *testing.T) {
@@ -412,3 +414,114 @@ func TestMergePodObjectMetaWithPodTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestPropagateChallengeLabels(t *testing.T) {
+	tests := map[string]struct {
+		propagatedLabels []string
+		challengeLabels  map[string]string
+		labels           map[string]string
+		expected         map[string]string
+	}{
+		"no labels are propagated if none are configured": {
+			challengeLabels: map[string]string{"team": "a"},
+			labels:          map[string]string{domainLabelKey: "1"},
+			expected:        map[string]string{domainLabelKey: "1"},
+		},
+		"only configured labels are propagated": {
+			propagatedLabels: []string{"team", "cost-center"},
+			challengeLabels:  map[string]string{"team": "a", "cost-center": "b", "app": "c"},
+			labels:           map[string]string{domainLabelKey: "1"},
+			expected:         map[string]string{domainLabelKey: "1", "team": "a", "cost-center": "b"},
+		},
+		"configured labels missing from the challenge are ignored": {
+			propagatedLabels: []string{"team"},
+			challengeLabels:  map[string]string{"app": "c"},
+			labels:           map[string]string{domainLabelKey: "1"},
+			expected:         map[string]string{domainLabelKey: "1"},
+		},
+		"existing labels are not overwritten": {
+			propagatedLabels: []string{domainLabelKey},
+			challengeLabels:  map[string]string{domainLabelKey: "2"},
+			labels:           map[string]string{domainLabelKey: "1"},
+			expected:         map[string]string{domainLabelKey: "1"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := &Solver{Context: &controller.Context{
+				ACMEOptions: controller.ACMEOptions{HTTP01SolverPropagatedLabels: test.propagatedLabels},
+			}}
+			ch := &cmacme.Challenge{ObjectMeta: metav1.ObjectMeta{Labels: test.challengeLabels}}
+			original := make(map[string]string)
+			for k, v := range test.labels {
+				original[k] = v
+			}
+			meta := &metav1.ObjectMeta{Labels: test.labels}
+
+			s.propagateChallengeLabels(ch, meta)
+
+			if !reflect.DeepEqual(meta.Labels, test.expected) {
+				t.Errorf("expected labels %v but got %v", test.expected, meta.Labels)
+			}
+			if !reflect.DeepEqual(test.labels, original) {
+				t.Errorf("expected original labels map to be left unmodified, got %v", test.labels)
+			}
+		})
+	}
+}
+
+func TestSolverPodScheduling(t *testing.T) {
+	runtimeClass := "gvisor"
+	templateConstraint := v1.TopologySpreadConstraint{
+		MaxSkew:           2,
+		TopologyKey:       "kubernetes.io/hostname",
+		WhenUnsatisfiable: v1.DoNotSchedule,
+	}
+
+	s := &Solver{Context: &controller.Context{
+		ACMEOptions: controller.ACMEOptions{
+			HTTP01SolverPriorityClassName:  "system-cluster-critical",
+			HTTP01SolverRuntimeClassName:   "runc",
+			HTTP01SolverTopologySpreadKeys: []string{"topology.kubernetes.io/zone"},
+		},
+	}}
+	ch := &cmacme.Challenge{
+		Spec: cmacme.ChallengeSpec{
+			Solver: cmacme.ACMEChallengeSolver{
+				HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+					Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+						PodTemplate: &cmacme.ACMEChallengeSolverHTTP01IngressPodTemplate{
+							Spec: cmacme.ACMEChallengeSolverHTTP01IngressPodSpec{
+								RuntimeClassName:          &runtimeClass,
+								TopologySpreadConstraints: []v1.TopologySpreadConstraint{templateConstraint},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	pod := s.buildPod(ch)
+
+	if pod.Spec.PriorityClassName != "system-cluster-critical" {
+		t.Errorf("expected the configured priority class, got: %q", pod.Spec.PriorityClassName)
+	}
+	if pod.Spec.RuntimeClassName == nil || *pod.Spec.RuntimeClassName != runtimeClass {
+		t.Errorf("expected the runtime class of the pod template, got: %v", pod.Spec.RuntimeClassName)
+	}
+	expConstraints := []v1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       "topology.kubernetes.io/zone",
+			WhenUnsatisfiable: v1.ScheduleAnyway,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{solverIdentificationLabelKey: "true"},
+			},
+		},
+		templateConstraint,
+	}
+	if !reflect.DeepEqual(pod.Spec.TopologySpreadConstraints, expConstraints) {
+		t.Errorf("unexpected topology spread constraints:\nexp: %v\ngot: %v", expConstraints, pod.Spec.TopologySpreadConstraints)
+	}
+}
@@ -96,7 +96,8 @@ func (s *Solver) createService(ch *cmacme.Challenge) (*corev1.Service, error) {
 	if err != nil {
 		return nil, err
 	}
-	return s.Client.CoreV1().Services(ch.Namespace).Create(context.TODO(), svc, metav1.CreateOptions{})
+	s.propagateChallengeLabels(ch, &svc.ObjectMeta)
+	return s.Client.CoreV1().Services(ch.Namespace).Create(context.TODO(), svc, metav1.CreateOptions{FieldManager: s.FieldManagerFor(fieldManagerController)})
 }
 
 func buildService(ch *cmacme.Challenge) (*corev1.Service, error) {
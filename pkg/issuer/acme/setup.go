@@ -35,6 +35,7 @@ import (
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	dnsutil "github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/util/errors"
 	"github.com/jetstack/cert-manager/pkg/util/kube"
@@ -45,6 +46,7 @@ const (
 	errorAccountRegistrationFailed = "ErrRegisterACMEAccount"
 	errorAccountVerificationFailed = "ErrVerifyACMEAccount"
 	errorAccountUpdateFailed       = "ErrUpdateACMEAccount"
+	errorDNS01CredentialsInvalid   = "ErrVerifyDNS01Credentials"
 
 	successAccountRegistered = "ACMEAccountRegistered"
 	successAccountVerified   = "ACMEAccountVerified"
@@ -116,8 +118,8 @@ func (a *Acme) Setup(ctx context.Context) error {
 	//  In future we should intelligently manage items in the account cache
 	//  and remove them when the corresponding issuer is updated/deleted.
 	a.accountRegistry.RemoveClient(string(a.issuer.GetUID()))
-	httpClient := accounts.BuildHTTPClient(a.metrics, a.issuer.GetSpec().ACME.SkipTLSVerify)
-	cl := accounts.NewClient(httpClient, *a.issuer.GetSpec().ACME, rsaPk)
+	httpClient := accounts.BuildHTTPClient(a.metrics, a.issuer.GetSpec().ACME.SkipTLSVerify, a.clientOptions)
+	cl := accounts.NewClient(httpClient, *a.issuer.GetSpec().ACME, rsaPk, a.clientOptions)
 
 	// TODO: perform a complex check to determine whether we need to verify
 	// the existing registration with the ACME server.
@@ -166,7 +168,7 @@ func (a *Acme) Setup(ctx context.Context) error {
 			"details look sufficient")
 		// ensure the cached client in the account registry is up to date
 		a.accountRegistry.AddClient(httpClient, string(a.issuer.GetUID()), *a.issuer.GetSpec().ACME, rsaPk)
-		return nil
+		return a.verifyDNS01Credentials(ctx)
 	}
 
 	if parsedAccountURL.Host != parsedServerURL.Host {
@@ -269,6 +271,42 @@ func (a *Acme) Setup(ctx context.Context) error {
 	// ensure the cached client in the account registry is up to date
 	a.accountRegistry.AddClient(httpClient, string(a.issuer.GetUID()), *a.issuer.GetSpec().ACME, rsaPk)
 
+	return a.verifyDNS01Credentials(ctx)
+}
+
+// verifyDNS01Credentials checks the credentials of the DNS01 providers of the
+// issuer's solvers against the APIs of the providers, so that credentials
+// that are invalid are reported on the issuer instead of when the first
+// challenge is solved. If any are rejected, the Ready condition is set to
+// False. Errors are only returned for failures that may resolve on retry, as
+// Setup is called again when a referenced Secret is updated.
+func (a *Acme) verifyDNS01Credentials(ctx context.Context) error {
+	if a.dns01Verifier == nil {
+		return nil
+	}
+	log := logf.FromContext(ctx)
+
+	for i, slv := range a.issuer.GetSpec().ACME.Solvers {
+		if slv.DNS01 == nil {
+			continue
+		}
+
+		verified, err := a.dns01Verifier.Verify(ctx, a.issuer, slv.DNS01)
+		if err != nil {
+			s := fmt.Sprintf("Failed to verify the credentials of the DNS01 provider of solver %d: %v", i, err)
+			log.Error(err, "failed to verify DNS01 provider credentials", "solver", i)
+			a.recorder.Event(a.issuer, corev1.EventTypeWarning, errorDNS01CredentialsInvalid, s)
+			apiutil.SetIssuerCondition(a.issuer, v1alpha2.IssuerConditionReady, cmmeta.ConditionFalse, errorDNS01CredentialsInvalid, s)
+			if dnsutil.IsTransientProviderError(err) {
+				return err
+			}
+			return nil
+		}
+		if verified {
+			log.V(logf.DebugLevel).Info("verified DNS01 provider credentials", "solver", i)
+		}
+	}
+
 	return nil
 }
 
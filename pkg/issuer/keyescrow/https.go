@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyescrow
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// httpsTimeout is the timeout of requests to HTTPS escrow services
+const httpsTimeout = 30 * time.Second
+
+// httpsPayload is the JSON body POSTed to HTTPS escrow services.
+type httpsPayload struct {
+	Namespace       string `json:"namespace"`
+	CertificateName string `json:"certificateName"`
+	SecretName      string `json:"secretName"`
+	Fingerprint     string `json:"fingerprint"`
+	// PrivateKey is the PKCS#8 PEM encoded private key
+	PrivateKey string `json:"privateKey"`
+}
+
+type httpsClient struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPSClient(url string, tlsConfig *tls.Config) *httpsClient {
+	return &httpsClient{
+		url: url,
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+			Timeout: httpsTimeout,
+		},
+	}
+}
+
+func (c *httpsClient) Deliver(ctx context.Context, key *Key) error {
+	keyPEM, err := pki.EncodePKCS8PrivateKey(key.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(httpsPayload{
+		Namespace:       key.Namespace,
+		CertificateName: key.CertificateName,
+		SecretName:      key.SecretName,
+		Fingerprint:     key.Fingerprint,
+		PrivateKey:      string(keyPEM),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	// the transport is only used for a single request
+	defer c.client.CloseIdleConnections()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error delivering key to %q: %v", c.url, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("error delivering key to %q: unexpected response status %q", c.url, resp.Status)
+	}
+	return nil
+}
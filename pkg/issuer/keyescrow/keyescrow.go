@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package keyescrow delivers the private keys of Certificates to the escrow
+// service configured on their issuer.
+package keyescrow
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+)
+
+// Key is a private key delivered to an escrow service, together with the
+// Certificate and Secret it belongs to.
+type Key struct {
+	Namespace       string
+	CertificateName string
+	SecretName      string
+
+	// Fingerprint is the fingerprint of the public key of PrivateKey, as
+	// returned by Fingerprint.
+	Fingerprint string
+	PrivateKey  crypto.Signer
+}
+
+// Client delivers private keys to an escrow service.
+type Client interface {
+	// Deliver delivers the key to the escrow service. Delivering the same
+	// key more than once must be safe, as keys are delivered again if
+	// recording their delivery fails.
+	Deliver(ctx context.Context, key *Key) error
+}
+
+// New returns a Client for the escrow service configured by escrow, which
+// authenticates with the given PEM encoded client certificate and key.
+func New(escrow *cmapi.KeyEscrow, certPEM, keyPEM []byte) (Client, error) {
+	tlsConfig, err := clientTLSConfig(escrow.CABundle, certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case escrow.HTTPS != nil:
+		return newHTTPSClient(escrow.HTTPS.URL, tlsConfig), nil
+	case escrow.KMIP != nil:
+		return newKMIPClient(escrow.KMIP.Server, tlsConfig), nil
+	default:
+		return nil, fmt.Errorf("no key escrow service configured")
+	}
+}
+
+// Fingerprint returns the hex encoded SHA-256 digest of the DER encoded
+// PKIX form of pub, which identifies a key without revealing it.
+func Fingerprint(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func clientTLSConfig(caBundle, certPEM, keyPEM []byte) (*tls.Config, error) {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate: %v", err)
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if len(caBundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("no valid certificates found in the CA bundle")
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
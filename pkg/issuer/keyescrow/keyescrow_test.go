@@ -0,0 +1,445 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyescrow
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// testPKI holds a CA and the server and client certificates it signed.
+type testPKI struct {
+	caPEM         []byte
+	caPool        *x509.CertPool
+	serverCert    tls.Certificate
+	clientCertPEM []byte
+	clientKeyPEM  []byte
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	caKey := mustGenerateKey(t)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "escrow-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, caKey.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue := func(serial int64, tmpl *x509.Certificate) ([]byte, []byte) {
+		key := mustGenerateKey(t)
+		tmpl.SerialNumber = big.NewInt(serial)
+		tmpl.NotBefore = caTmpl.NotBefore
+		tmpl.NotAfter = caTmpl.NotAfter
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, key.Public(), caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyPEM, err := pki.EncodePKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM
+	}
+
+	serverCertPEM, serverKeyPEM := issue(2, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "escrow"},
+		DNSNames:    []string{"kmip.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	serverCert, err := tls.X509KeyPair(serverCertPEM, serverKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCertPEM, clientKeyPEM := issue(3, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "cert-manager"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	return &testPKI{
+		caPEM:         pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		caPool:        pool,
+		serverCert:    serverCert,
+		clientCertPEM: clientCertPEM,
+		clientKeyPEM:  clientKeyPEM,
+	}
+}
+
+// serverTLSConfig requires clients to present a certificate signed by the CA.
+func (p *testPKI) serverTLSConfig() *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{p.serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    p.caPool,
+	}
+}
+
+func mustGenerateKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func testKey(t *testing.T) *Key {
+	pk := mustGenerateKey(t)
+	fingerprint, err := Fingerprint(pk.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Key{
+		Namespace:       "default",
+		CertificateName: "web",
+		SecretName:      "web-tls",
+		Fingerprint:     fingerprint,
+		PrivateKey:      pk,
+	}
+}
+
+func TestHTTPSDeliver(t *testing.T) {
+	p := newTestPKI(t)
+	key := testKey(t)
+
+	var payload httpsPayload
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		if strings.HasSuffix(r.URL.Path, "/reject") {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	srv.TLS = p.serverTLSConfig()
+	srv.StartTLS()
+	defer srv.Close()
+
+	escrow := &cmapi.KeyEscrow{
+		HTTPS:    &cmapi.HTTPSKeyEscrow{URL: srv.URL + "/keys"},
+		CABundle: p.caPEM,
+	}
+	client, err := New(escrow, p.clientCertPEM, p.clientKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Deliver(context.Background(), key); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if payload.Namespace != "default" || payload.CertificateName != "web" || payload.SecretName != "web-tls" || payload.Fingerprint != key.Fingerprint {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+	delivered, err := pki.DecodePrivateKeyBytes([]byte(payload.PrivateKey))
+	if err != nil {
+		t.Fatalf("failed to decode delivered key: %v", err)
+	}
+	if ok, err := pki.PublicKeysEqual(key.PrivateKey.Public(), delivered.Public()); err != nil || !ok {
+		t.Errorf("delivered key does not match")
+	}
+
+	escrow.HTTPS.URL = srv.URL + "/reject"
+	client, err = New(escrow, p.clientCertPEM, p.clientKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Deliver(context.Background(), key); err == nil {
+		t.Errorf("expected an error when the escrow service rejects the key")
+	}
+}
+
+func TestNewInvalidClientCertificate(t *testing.T) {
+	escrow := &cmapi.KeyEscrow{HTTPS: &cmapi.HTTPSKeyEscrow{URL: "https://escrow.example.com"}}
+	if _, err := New(escrow, []byte("invalid"), []byte("invalid")); err == nil {
+		t.Errorf("expected an error for an invalid client certificate")
+	}
+}
+
+// fakeKMIPServer serves a single request on conn, sending the decoded
+// request to requests and replying with resp.
+func fakeKMIPServer(t *testing.T, conn net.Conn, cfg *tls.Config, resp ttlv, requests chan<- ttlv) {
+	defer conn.Close()
+	srv := tls.Server(conn, cfg)
+
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(srv, header); err != nil {
+		t.Errorf("failed to read request: %v", err)
+		close(requests)
+		return
+	}
+	msg := make([]byte, 8+int(binary.BigEndian.Uint32(header[4:])))
+	copy(msg, header)
+	if _, err := io.ReadFull(srv, msg[8:]); err != nil {
+		t.Errorf("failed to read request: %v", err)
+		close(requests)
+		return
+	}
+	req, _, err := decodeTTLV(msg)
+	if err != nil {
+		t.Errorf("failed to decode request: %v", err)
+	}
+	requests <- req
+
+	var buf bytes.Buffer
+	resp.encode(&buf)
+	srv.Write(buf.Bytes())
+}
+
+func kmipResponse(status uint32, items ...ttlv) ttlv {
+	return ttlvStruct(tagResponseMessage,
+		ttlvStruct(tagBatchItem,
+			append([]ttlv{
+				ttlvEnum(tagOperation, operationRegister),
+				ttlvEnum(tagResultStatus, status),
+			}, items...)...,
+		),
+	)
+}
+
+func TestKMIPDeliver(t *testing.T) {
+	tests := map[string]struct {
+		resp      ttlv
+		expectErr string
+	}{
+		"key is registered": {
+			resp: kmipResponse(resultStatusSuccess,
+				ttlvStruct(tagResponsePayload, ttlvText(tagUniqueIdentifier, "42")),
+			),
+		},
+		"server fails to register the key": {
+			resp: kmipResponse(0x01,
+				ttlvEnum(tagResultReason, 0x07),
+				ttlvText(tagResultMessage, "permission denied"),
+			),
+			expectErr: "KMIP server failed to register the key (status 1, reason 7): permission denied",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := newTestPKI(t)
+			key := testKey(t)
+
+			escrow := &cmapi.KeyEscrow{
+				KMIP:     &cmapi.KMIPKeyEscrow{Server: "kmip.example.com:5696"},
+				CABundle: p.caPEM,
+			}
+			client, err := New(escrow, p.clientCertPEM, p.clientKeyPEM)
+			if err != nil {
+				t.Fatal(err)
+			}
+			requests := make(chan ttlv, 1)
+			client.(*kmipClient).dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+				if address != "kmip.example.com:5696" {
+					t.Errorf("unexpected address %q", address)
+				}
+				clientConn, serverConn := net.Pipe()
+				go fakeKMIPServer(t, serverConn, p.serverTLSConfig(), test.resp, requests)
+				return clientConn, nil
+			}
+
+			err = client.Deliver(context.Background(), key)
+			switch {
+			case test.expectErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case test.expectErr != "" && (err == nil || err.Error() != test.expectErr):
+				t.Fatalf("expected error %q, got: %v", test.expectErr, err)
+			}
+
+			req, ok := <-requests
+			if !ok {
+				t.FailNow()
+			}
+			checkRegisterRequest(t, req, key)
+		})
+	}
+}
+
+func checkRegisterRequest(t *testing.T, req ttlv, key *Key) {
+	item, _ := req.child(tagBatchItem)
+	if op, _ := item.child(tagOperation); op.uint32Value() != operationRegister {
+		t.Errorf("unexpected operation %d", op.uint32Value())
+	}
+	payload, _ := item.child(tagRequestPayload)
+	if typ, _ := payload.child(tagObjectType); typ.uint32Value() != objectTypePrivateKey {
+		t.Errorf("unexpected object type %d", typ.uint32Value())
+	}
+
+	attrs, _ := payload.child(tagTemplateAttribute)
+	nameAttr, _ := attrs.child(tagAttribute)
+	nameValue, _ := nameAttr.child(tagAttributeValue)
+	name, _ := nameValue.child(tagNameValue)
+	if expected := "default/web/" + key.Fingerprint; string(name.value) != expected {
+		t.Errorf("expected key to be named %q, got %q", expected, name.value)
+	}
+
+	privateKey, _ := payload.child(tagPrivateKey)
+	block, _ := privateKey.child(tagKeyBlock)
+	if alg, _ := block.child(tagCryptographicAlgorithm); alg.uint32Value() != algorithmECDSA {
+		t.Errorf("unexpected algorithm %d", alg.uint32Value())
+	}
+	if length, _ := block.child(tagCryptographicLength); length.uint32Value() != 256 {
+		t.Errorf("unexpected length %d", length.uint32Value())
+	}
+	keyValue, _ := block.child(tagKeyValue)
+	material, _ := keyValue.child(tagKeyMaterial)
+	delivered, err := x509.ParsePKCS8PrivateKey(material.value)
+	if err != nil {
+		t.Fatalf("failed to parse key material: %v", err)
+	}
+	signer, ok := delivered.(crypto.Signer)
+	if !ok {
+		t.Fatalf("unexpected key type %T", delivered)
+	}
+	if ok, err := pki.PublicKeysEqual(key.PrivateKey.Public(), signer.Public()); err != nil || !ok {
+		t.Errorf("registered key does not match")
+	}
+}
+
+func TestTTLVRoundTrip(t *testing.T) {
+	in := ttlvStruct(tagRequestMessage,
+		ttlvInt(tagBatchCount, 3),
+		ttlvEnum(tagOperation, operationRegister),
+		ttlvText(tagNameValue, "odd length"),
+		ttlvBytes(tagKeyMaterial, []byte{1, 2, 3}),
+	)
+	var buf bytes.Buffer
+	in.encode(&buf)
+	if buf.Len()%8 != 0 {
+		t.Fatalf("encoding of %d bytes is not padded", buf.Len())
+	}
+
+	out, rest, err := decodeTTLV(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 0 {
+		t.Errorf("unexpected trailing bytes: %v", rest)
+	}
+	if len(out.items) != 4 {
+		t.Fatalf("expected 4 items, got %d", len(out.items))
+	}
+	if count, _ := out.child(tagBatchCount); count.uint32Value() != 3 {
+		t.Errorf("unexpected integer value %d", count.uint32Value())
+	}
+	if text, _ := out.child(tagNameValue); string(text.value) != "odd length" {
+		t.Errorf("unexpected text value %q", text.value)
+	}
+	if b, _ := out.child(tagKeyMaterial); !bytes.Equal(b.value, []byte{1, 2, 3}) {
+		t.Errorf("unexpected byte string value %v", b.value)
+	}
+
+	if _, _, err := decodeTTLV(buf.Bytes()[:buf.Len()-8]); err == nil {
+		t.Errorf("expected an error decoding a truncated item")
+	}
+}
+
+// TestTTLVSpecVectors checks the encoding and decoding of the examples of
+// section 9.1.2 of the KMIP 1.2 specification. The vectors are written as in
+// the specification, with the tag, type, length and value separated by '|'.
+func TestTTLVSpecVectors(t *testing.T) {
+	tests := map[string]struct {
+		item   ttlv
+		vector string
+	}{
+		"Integer 8": {
+			item:   ttlvInt(0x420020, 8),
+			vector: "42 00 20 | 02 | 00 00 00 04 | 00 00 00 08 00 00 00 00",
+		},
+		"Enumeration 255": {
+			item:   ttlvEnum(0x420020, 255),
+			vector: "42 00 20 | 05 | 00 00 00 04 | 00 00 00 FF 00 00 00 00",
+		},
+		"Text String 'Hello World'": {
+			item:   ttlvText(0x420020, "Hello World"),
+			vector: "42 00 20 | 07 | 00 00 00 0B | 48 65 6C 6C 6F 20 57 6F 72 6C 64 00 00 00 00 00",
+		},
+		"Byte String 0x010203": {
+			item:   ttlvBytes(0x420020, []byte{0x01, 0x02, 0x03}),
+			vector: "42 00 20 | 08 | 00 00 00 03 | 01 02 03 00 00 00 00 00",
+		},
+		"Structure of Enumeration 254 and Integer 255": {
+			item: ttlvStruct(0x420020,
+				ttlvEnum(0x420004, 254),
+				ttlvInt(0x420005, 255),
+			),
+			vector: "42 00 20 | 01 | 00 00 00 20 | " +
+				"42 00 04 | 05 | 00 00 00 04 | 00 00 00 FE 00 00 00 00 | " +
+				"42 00 05 | 02 | 00 00 00 04 | 00 00 00 FF 00 00 00 00",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			expected, err := hex.DecodeString(strings.NewReplacer(" ", "", "|", "").Replace(test.vector))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			test.item.encode(&buf)
+			if !bytes.Equal(buf.Bytes(), expected) {
+				t.Errorf("unexpected encoding\nexp: % X\ngot: % X", expected, buf.Bytes())
+			}
+
+			decoded, rest, err := decodeTTLV(expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rest) != 0 {
+				t.Errorf("unexpected trailing bytes: %v", rest)
+			}
+			if !reflect.DeepEqual(decoded, test.item) {
+				t.Errorf("unexpected decoded item\nexp: %+v\ngot: %+v", test.item, decoded)
+			}
+		})
+	}
+}
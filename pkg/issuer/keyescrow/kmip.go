@@ -0,0 +1,387 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyescrow
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// This file implements the subset of the KMIP 1.2 protocol needed to
+// register private keys: requests and responses are encoded as TTLV (tag,
+// type, length, value) items and exchanged over a mutually authenticated TLS
+// connection.
+
+// kmipTimeout is the timeout of the exchange with KMIP servers, if the
+// context has no earlier deadline
+const kmipTimeout = 30 * time.Second
+
+// maxKMIPResponseSize limits the size of the responses read from KMIP servers
+const maxKMIPResponseSize = 1 << 20
+
+// TTLV item types
+const (
+	ttlvStructure   byte = 0x01
+	ttlvInteger     byte = 0x02
+	ttlvEnumeration byte = 0x05
+	ttlvTextString  byte = 0x07
+	ttlvByteString  byte = 0x08
+)
+
+// KMIP tags
+const (
+	tagAttribute              uint32 = 0x420008
+	tagAttributeName          uint32 = 0x42000A
+	tagAttributeValue         uint32 = 0x42000B
+	tagBatchCount             uint32 = 0x42000D
+	tagBatchItem              uint32 = 0x42000F
+	tagCryptographicAlgorithm uint32 = 0x420028
+	tagCryptographicLength    uint32 = 0x42002A
+	tagKeyBlock               uint32 = 0x420040
+	tagKeyFormatType          uint32 = 0x420042
+	tagKeyMaterial            uint32 = 0x420043
+	tagKeyValue               uint32 = 0x420045
+	tagNameType               uint32 = 0x420054
+	tagNameValue              uint32 = 0x420055
+	tagObjectType             uint32 = 0x420057
+	tagOperation              uint32 = 0x42005C
+	tagPrivateKey             uint32 = 0x420064
+	tagProtocolVersion        uint32 = 0x420069
+	tagProtocolVersionMajor   uint32 = 0x42006A
+	tagProtocolVersionMinor   uint32 = 0x42006B
+	tagRequestHeader          uint32 = 0x420077
+	tagRequestMessage         uint32 = 0x420078
+	tagRequestPayload         uint32 = 0x420079
+	tagResponseMessage        uint32 = 0x42007B
+	tagResponsePayload        uint32 = 0x42007C
+	tagResultMessage          uint32 = 0x42007D
+	tagResultReason           uint32 = 0x42007E
+	tagResultStatus           uint32 = 0x42007F
+	tagTemplateAttribute      uint32 = 0x420091
+	tagUniqueIdentifier       uint32 = 0x420094
+)
+
+// KMIP enumeration and integer values
+const (
+	operationRegister          uint32 = 0x03
+	objectTypePrivateKey       uint32 = 0x04
+	keyFormatTypePKCS8         uint32 = 0x04
+	algorithmRSA               uint32 = 0x04
+	algorithmECDSA             uint32 = 0x06
+	nameTypeUninterpretedText  uint32 = 0x01
+	resultStatusSuccess        uint32 = 0x00
+	cryptographicUsageMaskSign int32  = 0x01
+	kmipProtocolVersionMajor   int32  = 1
+	kmipProtocolVersionMinor   int32  = 2
+)
+
+// KMIP attribute names
+const (
+	attributeNameName                   = "Name"
+	attributeNameCryptographicUsageMask = "Cryptographic Usage Mask"
+)
+
+// ttlv is a TTLV item. Structures hold their child items, all other types
+// hold their encoded value.
+type ttlv struct {
+	tag   uint32
+	typ   byte
+	value []byte
+	items []ttlv
+}
+
+func ttlvStruct(tag uint32, items ...ttlv) ttlv {
+	return ttlv{tag: tag, typ: ttlvStructure, items: items}
+}
+
+func ttlvInt(tag uint32, v int32) ttlv {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(v))
+	return ttlv{tag: tag, typ: ttlvInteger, value: b}
+}
+
+func ttlvEnum(tag uint32, v uint32) ttlv {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return ttlv{tag: tag, typ: ttlvEnumeration, value: b}
+}
+
+func ttlvText(tag uint32, v string) ttlv {
+	return ttlv{tag: tag, typ: ttlvTextString, value: []byte(v)}
+}
+
+func ttlvBytes(tag uint32, v []byte) ttlv {
+	return ttlv{tag: tag, typ: ttlvByteString, value: v}
+}
+
+// encode appends the encoding of t to buf. Values are padded to a multiple
+// of eight bytes.
+func (t ttlv) encode(buf *bytes.Buffer) {
+	value := t.value
+	if t.typ == ttlvStructure {
+		var inner bytes.Buffer
+		for _, item := range t.items {
+			item.encode(&inner)
+		}
+		value = inner.Bytes()
+	}
+
+	var header [8]byte
+	header[0] = byte(t.tag >> 16)
+	header[1] = byte(t.tag >> 8)
+	header[2] = byte(t.tag)
+	header[3] = t.typ
+	binary.BigEndian.PutUint32(header[4:], uint32(len(value)))
+	buf.Write(header[:])
+	buf.Write(value)
+	if pad := (8 - len(value)%8) % 8; pad > 0 {
+		buf.Write(make([]byte, pad))
+	}
+}
+
+// decodeTTLV decodes the TTLV item at the start of b, returning it and the
+// remaining bytes.
+func decodeTTLV(b []byte) (ttlv, []byte, error) {
+	if len(b) < 8 {
+		return ttlv{}, nil, fmt.Errorf("truncated TTLV header")
+	}
+	t := ttlv{
+		tag: uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2]),
+		typ: b[3],
+	}
+	length := int(binary.BigEndian.Uint32(b[4:8]))
+	padded := (length + 7) / 8 * 8
+	if length < 0 || len(b)-8 < padded {
+		return ttlv{}, nil, fmt.Errorf("truncated TTLV value of tag %06x", t.tag)
+	}
+	value := b[8 : 8+length]
+
+	if t.typ != ttlvStructure {
+		t.value = value
+		return t, b[8+padded:], nil
+	}
+	for len(value) > 0 {
+		item, rest, err := decodeTTLV(value)
+		if err != nil {
+			return ttlv{}, nil, err
+		}
+		t.items = append(t.items, item)
+		value = rest
+	}
+	return t, b[8+padded:], nil
+}
+
+// child returns the first child item of t with the given tag.
+func (t ttlv) child(tag uint32) (ttlv, bool) {
+	for _, item := range t.items {
+		if item.tag == tag {
+			return item, true
+		}
+	}
+	return ttlv{}, false
+}
+
+// uint32Value returns the value of Integer and Enumeration items.
+func (t ttlv) uint32Value() uint32 {
+	if len(t.value) != 4 {
+		return 0
+	}
+	return binary.BigEndian.Uint32(t.value)
+}
+
+type kmipClient struct {
+	server    string
+	tlsConfig *tls.Config
+
+	// dial opens the connection to the server, and can be replaced in tests
+	dial func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+func newKMIPClient(server string, tlsConfig *tls.Config) *kmipClient {
+	return &kmipClient{
+		server:    server,
+		tlsConfig: tlsConfig,
+		dial:      (&net.Dialer{}).DialContext,
+	}
+}
+
+// Deliver registers the key as a private key object named after its
+// Certificate and fingerprint.
+func (c *kmipClient) Deliver(ctx context.Context, key *Key) error {
+	req, err := registerRequest(key)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.roundTrip(ctx, req)
+	if err != nil {
+		return fmt.Errorf("error delivering key to KMIP server %q: %v", c.server, err)
+	}
+	return registerResult(resp)
+}
+
+// registerRequest builds the request message registering key.
+func registerRequest(key *Key) (ttlv, error) {
+	var algorithm uint32
+	var length int
+	switch k := key.PrivateKey.(type) {
+	case *rsa.PrivateKey:
+		algorithm, length = algorithmRSA, k.N.BitLen()
+	case *ecdsa.PrivateKey:
+		algorithm, length = algorithmECDSA, k.Curve.Params().BitSize
+	default:
+		return ttlv{}, fmt.Errorf("KMIP key escrow does not support keys of type %T", key.PrivateKey)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key.PrivateKey)
+	if err != nil {
+		return ttlv{}, err
+	}
+
+	name := fmt.Sprintf("%s/%s/%s", key.Namespace, key.CertificateName, key.Fingerprint)
+	return ttlvStruct(tagRequestMessage,
+		ttlvStruct(tagRequestHeader,
+			ttlvStruct(tagProtocolVersion,
+				ttlvInt(tagProtocolVersionMajor, kmipProtocolVersionMajor),
+				ttlvInt(tagProtocolVersionMinor, kmipProtocolVersionMinor),
+			),
+			ttlvInt(tagBatchCount, 1),
+		),
+		ttlvStruct(tagBatchItem,
+			ttlvEnum(tagOperation, operationRegister),
+			ttlvStruct(tagRequestPayload,
+				ttlvEnum(tagObjectType, objectTypePrivateKey),
+				ttlvStruct(tagTemplateAttribute,
+					ttlvStruct(tagAttribute,
+						ttlvText(tagAttributeName, attributeNameName),
+						ttlvStruct(tagAttributeValue,
+							ttlvText(tagNameValue, name),
+							ttlvEnum(tagNameType, nameTypeUninterpretedText),
+						),
+					),
+					ttlvStruct(tagAttribute,
+						ttlvText(tagAttributeName, attributeNameCryptographicUsageMask),
+						ttlvInt(tagAttributeValue, cryptographicUsageMaskSign),
+					),
+				),
+				ttlvStruct(tagPrivateKey,
+					ttlvStruct(tagKeyBlock,
+						ttlvEnum(tagKeyFormatType, keyFormatTypePKCS8),
+						ttlvStruct(tagKeyValue,
+							ttlvBytes(tagKeyMaterial, der),
+						),
+						ttlvEnum(tagCryptographicAlgorithm, algorithm),
+						ttlvInt(tagCryptographicLength, int32(length)),
+					),
+				),
+			),
+		),
+	), nil
+}
+
+// registerResult returns an error if the response message reports that the
+// key could not be registered.
+func registerResult(resp ttlv) error {
+	if resp.tag != tagResponseMessage {
+		return fmt.Errorf("unexpected KMIP response with tag %06x", resp.tag)
+	}
+	item, ok := resp.child(tagBatchItem)
+	if !ok {
+		return fmt.Errorf("KMIP response contains no batch item")
+	}
+	status, ok := item.child(tagResultStatus)
+	if !ok {
+		return fmt.Errorf("KMIP response contains no result status")
+	}
+	if status.uint32Value() != resultStatusSuccess {
+		msg := ""
+		if m, ok := item.child(tagResultMessage); ok {
+			msg = string(m.value)
+		}
+		reason := uint32(0)
+		if r, ok := item.child(tagResultReason); ok {
+			reason = r.uint32Value()
+		}
+		return fmt.Errorf("KMIP server failed to register the key (status %d, reason %d): %s", status.uint32Value(), reason, msg)
+	}
+	if payload, ok := item.child(tagResponsePayload); ok {
+		if _, ok := payload.child(tagUniqueIdentifier); ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("KMIP response contains no unique identifier for the registered key")
+}
+
+// roundTrip sends req to the server and reads its response.
+func (c *kmipClient) roundTrip(ctx context.Context, req ttlv) (ttlv, error) {
+	ctx, cancel := context.WithTimeout(ctx, kmipTimeout)
+	defer cancel()
+
+	rawConn, err := c.dial(ctx, "tcp", c.server)
+	if err != nil {
+		return ttlv{}, err
+	}
+	defer rawConn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		rawConn.SetDeadline(deadline)
+	}
+
+	cfg := c.tlsConfig.Clone()
+	if cfg.ServerName == "" {
+		host, _, err := net.SplitHostPort(c.server)
+		if err != nil {
+			return ttlv{}, err
+		}
+		cfg.ServerName = host
+	}
+	conn := tls.Client(rawConn, cfg)
+	if err := conn.Handshake(); err != nil {
+		return ttlv{}, err
+	}
+
+	var buf bytes.Buffer
+	req.encode(&buf)
+	if _, err := conn.Write(buf.Bytes()); err != nil {
+		return ttlv{}, err
+	}
+
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return ttlv{}, err
+	}
+	length := binary.BigEndian.Uint32(header[4:])
+	if length > maxKMIPResponseSize {
+		return ttlv{}, fmt.Errorf("KMIP response of %d bytes exceeds the maximum size", length)
+	}
+	msg := make([]byte, 8+int(length))
+	copy(msg, header)
+	if _, err := io.ReadFull(conn, msg[8:]); err != nil {
+		return ttlv{}, err
+	}
+
+	resp, _, err := decodeTTLV(msg)
+	return resp, err
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package requestdefaults renders the request defaults of issuers and adds
+// them to the certificates they sign.
+// Request defaults are Go templates that can only use a restricted set of
+// functions describing the identity of the request: its namespace, the
+// service account of the Pod it was created for, the cluster domain and the
+// name of the issuer. Templates have no data, so they cannot access anything
+// else about the request or the issuer.
+package requestdefaults
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"text/template"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// Values are the values returned by the template functions.
+type Values struct {
+	Namespace     string
+	ClusterDomain string
+	IssuerName    string
+	// ServiceAccount returns the service account of the Pod the request was
+	// created for. It is only called by templates using serviceAccount, so
+	// that requests that are not created for a Pod can be signed by issuers
+	// not using it.
+	ServiceAccount func() (string, error)
+}
+
+func (v Values) funcs() template.FuncMap {
+	return template.FuncMap{
+		"namespace":     func() string { return v.Namespace },
+		"clusterDomain": func() string { return v.ClusterDomain },
+		"issuerName":    func() string { return v.IssuerName },
+		"serviceAccount": func() (string, error) {
+			if v.ServiceAccount == nil {
+				return "", errors.New("the service account of the request is not known")
+			}
+			return v.ServiceAccount()
+		},
+	}
+}
+
+// Parse parses text as a request default template, returning an error if it
+// is invalid or uses functions that are not available.
+func Parse(text string) error {
+	_, err := parse(text, Values{})
+	return err
+}
+
+func parse(text string, values Values) (*template.Template, error) {
+	return template.New("").Option("missingkey=error").Funcs(values.funcs()).Parse(text)
+}
+
+// Render renders the template text with the given values.
+func Render(text string, values Values) (string, error) {
+	t, err := parse(text, values)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, nil); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Apply adds the rendered request defaults to the given certificate
+// template: the common name is only set if the certificate has none, and DNS
+// names and URIs are added if they are not already present.
+func Apply(cert *x509.Certificate, defaults *cmapi.RequestDefaults, values Values) error {
+	if defaults == nil {
+		return nil
+	}
+
+	if defaults.CommonName != "" && cert.Subject.CommonName == "" {
+		cn, err := Render(defaults.CommonName, values)
+		if err != nil {
+			return fmt.Errorf("rendering commonName: %w", err)
+		}
+		cert.Subject.CommonName = cn
+	}
+
+	for i, t := range defaults.DNSNames {
+		name, err := Render(t, values)
+		if err != nil {
+			return fmt.Errorf("rendering dnsNames[%d]: %w", i, err)
+		}
+		if !containsDNSName(cert.DNSNames, name) {
+			cert.DNSNames = append(cert.DNSNames, name)
+		}
+	}
+
+	for i, t := range defaults.URISANs {
+		rendered, err := Render(t, values)
+		if err != nil {
+			return fmt.Errorf("rendering uriSANs[%d]: %w", i, err)
+		}
+		uri, err := url.Parse(rendered)
+		if err != nil {
+			return fmt.Errorf("uriSANs[%d] is not a valid URI: %w", i, err)
+		}
+		if !containsURI(cert.URIs, uri) {
+			cert.URIs = append(cert.URIs, uri)
+		}
+	}
+
+	return nil
+}
+
+func containsDNSName(names []string, name string) bool {
+	name = pki.CanonicalDNSName(name)
+	for _, n := range names {
+		if pki.CanonicalDNSName(n) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsURI(uris []*url.URL, uri *url.URL) bool {
+	for _, u := range uris {
+		if u.String() == uri.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// ValuesForRequest returns the values of the template functions for the
+// given CertificateRequest and issuer. The service account is that in the
+// cert-manager.io/pod-service-account annotation of the request, which is only
+// returned if trustServiceAccount is set, as the annotation can only be trusted
+// if the webhook verifies it against the identity of the Pod the request was
+// created for at admission.
+func ValuesForRequest(cr *cmapi.CertificateRequest, issuer cmapi.GenericIssuer, clusterDomain string, trustServiceAccount bool) Values {
+	return Values{
+		Namespace:     cr.Namespace,
+		ClusterDomain: clusterDomain,
+		IssuerName:    issuer.GetObjectMeta().Name,
+		ServiceAccount: func() (string, error) {
+			if !trustServiceAccount {
+				return "", errors.New("the service account of the request cannot be verified, as the controller is not configured to trust the pod identity verified by the webhook")
+			}
+			serviceAccount := cr.Annotations[cmapi.CertificateRequestPodServiceAccountAnnotationKey]
+			if serviceAccount == "" {
+				return "", fmt.Errorf("the request does not record the service account of the Pod it was created for in the %q annotation", cmapi.CertificateRequestPodServiceAccountAnnotationKey)
+			}
+			return serviceAccount, nil
+		},
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requestdefaults
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"reflect"
+	"testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]bool{
+		"spiffe://{{ clusterDomain }}/ns/{{ namespace }}/sa/{{ serviceAccount }}": true,
+		"{{ issuerName }}.example.com":                                            true,
+		"example.com":                                                             true,
+		"{{ pod }}.example.com":                                                   false,
+		"{{ namespace ":                                                           false,
+	}
+	for text, valid := range tests {
+		if err := Parse(text); (err == nil) != valid {
+			t.Errorf("expected %q to be valid=%t, got error: %v", text, valid, err)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	values := Values{
+		Namespace:      "team-a",
+		ClusterDomain:  "cluster.local",
+		IssuerName:     "ca-issuer",
+		ServiceAccount: func() (string, error) { return "app", nil },
+	}
+
+	out, err := Render("spiffe://{{ clusterDomain }}/ns/{{ namespace }}/sa/{{ serviceAccount }}", values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "spiffe://cluster.local/ns/team-a/sa/app"; out != exp {
+		t.Errorf("expected %q, got: %q", exp, out)
+	}
+
+	if _, err := Render("{{ .Spec }}", values); err == nil {
+		t.Errorf("expected templates to have no access to data")
+	}
+	if _, err := Render("{{ serviceAccount }}", Values{}); err == nil {
+		t.Errorf("expected an error if the service account is not known")
+	}
+}
+
+func TestApply(t *testing.T) {
+	values := Values{
+		Namespace:      "team-a",
+		ClusterDomain:  "cluster.local",
+		IssuerName:     "ca-issuer",
+		ServiceAccount: func() (string, error) { return "app", nil },
+	}
+	defaults := &cmapi.RequestDefaults{
+		CommonName: "{{ serviceAccount }}.{{ namespace }}",
+		DNSNames:   []string{"{{ serviceAccount }}.{{ namespace }}.svc.{{ clusterDomain }}"},
+		URISANs:    []string{"spiffe://{{ clusterDomain }}/ns/{{ namespace }}/sa/{{ serviceAccount }}"},
+	}
+	spiffeID, _ := url.Parse("spiffe://cluster.local/ns/team-a/sa/app")
+
+	tests := map[string]struct {
+		cert     *x509.Certificate
+		defaults *cmapi.RequestDefaults
+		exp      *x509.Certificate
+	}{
+		"nothing is changed without defaults": {
+			cert: &x509.Certificate{DNSNames: []string{"example.com"}},
+			exp:  &x509.Certificate{DNSNames: []string{"example.com"}},
+		},
+		"defaults are added to an empty certificate": {
+			cert:     &x509.Certificate{},
+			defaults: defaults,
+			exp: &x509.Certificate{
+				Subject:  pkix.Name{CommonName: "app.team-a"},
+				DNSNames: []string{"app.team-a.svc.cluster.local"},
+				URIs:     []*url.URL{spiffeID},
+			},
+		},
+		"requested names are kept and not duplicated": {
+			cert: &x509.Certificate{
+				Subject:  pkix.Name{CommonName: "example.com"},
+				DNSNames: []string{"example.com", "App.team-a.svc.cluster.local"},
+				URIs:     []*url.URL{spiffeID},
+			},
+			defaults: defaults,
+			exp: &x509.Certificate{
+				Subject:  pkix.Name{CommonName: "example.com"},
+				DNSNames: []string{"example.com", "App.team-a.svc.cluster.local"},
+				URIs:     []*url.URL{spiffeID},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := Apply(test.cert, test.defaults, values); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(test.cert, test.exp) {
+				t.Errorf("expected %+v, got: %+v", test.exp, test.cert)
+			}
+		})
+	}
+}
+
+func TestValuesForRequest(t *testing.T) {
+	issuer := gen.Issuer("ca-issuer")
+	cr := gen.CertificateRequest("test",
+		gen.SetCertificateRequestNamespace("team-a"),
+		gen.SetCertificateRequestAnnotations(map[string]string{
+			cmapi.CertificateRequestPodNameAnnotationKey:           "app-1",
+			cmapi.CertificateRequestPodServiceAccountAnnotationKey: "app",
+		}),
+	)
+
+	values := ValuesForRequest(cr, issuer, "cluster.local", true)
+	if values.Namespace != "team-a" || values.IssuerName != "ca-issuer" || values.ClusterDomain != "cluster.local" {
+		t.Errorf("unexpected values: %+v", values)
+	}
+	if sa, err := values.ServiceAccount(); err != nil || sa != "app" {
+		t.Errorf("expected service account %q, got: %q, %v", "app", sa, err)
+	}
+
+	values = ValuesForRequest(cr, issuer, "cluster.local", false)
+	if _, err := values.ServiceAccount(); err == nil {
+		t.Errorf("expected an error when the service account annotation is not trusted")
+	}
+
+	// the Pod named by the request is not used to look up the service
+	// account, as the annotation naming it is not verified by the controller
+	cr = gen.CertificateRequestFrom(cr, gen.SetCertificateRequestAnnotations(map[string]string{
+		cmapi.CertificateRequestPodNameAnnotationKey: "app-1",
+	}))
+	values = ValuesForRequest(cr, issuer, "cluster.local", true)
+	if _, err := values.ServiceAccount(); err == nil {
+		t.Errorf("expected an error for a request that does not record its service account")
+	}
+}
@@ -41,6 +41,7 @@ const (
 	messageServerAndPathRequired         = "Vault server and path are required fields"
 	messageAuthFieldsRequired            = "Vault tokenSecretRef, appRole, or kubernetes is required"
 	messageAuthFieldRequired             = "Multiple auth methods cannot be set on the same Vault issuer"
+	messageInvalidSigningConfig          = "Invalid Vault signing configuration: "
 )
 
 func (v *Vault) Setup(ctx context.Context) error {
@@ -58,6 +59,14 @@ func (v *Vault) Setup(ctx context.Context) error {
 		return nil
 	}
 
+	// check the path can be used with the signing mode and issuer ref.
+	if _, err := vaultinternal.SignPath(v.issuer.GetSpec().Vault); err != nil {
+		s := messageInvalidSigningConfig + err.Error()
+		klog.Infof("%s: %s", v.issuer.GetObjectMeta().Name, s)
+		apiutil.SetIssuerCondition(v.issuer, v1alpha2.IssuerConditionReady, cmmeta.ConditionFalse, errorVault, s)
+		return nil
+	}
+
 	tokenAuth := v.issuer.GetSpec().Vault.Auth.TokenSecretRef
 	appRoleAuth := v.issuer.GetSpec().Vault.Auth.AppRole
 	kubeAuth := v.issuer.GetSpec().Vault.Auth.Kubernetes
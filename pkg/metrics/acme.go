@@ -20,11 +20,16 @@ limitations under the License.
 // certificate_ready_status{name, namespace, condition}
 // acme_client_request_count{"scheme", "host", "path", "method", "status"}
 // acme_client_request_duration_seconds{"scheme", "host", "path", "method", "status"}
+// acme_error_count{"kind", "type"}
 // controller_sync_call_count{"controller"}
+// certificaterequest_sign_call_count{"namespace", "team", "issuer_type", "result"}
+// certificate_issuance_count{"namespace", "team", "issuer_type"}
 package metrics
 
 import (
 	"time"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
 )
 
 // ObserveACMERequestDuration increases bucket counters for that ACME client duration.
@@ -36,3 +41,9 @@ func (m *Metrics) ObserveACMERequestDuration(duration time.Duration, labels ...s
 func (m *Metrics) IncrementACMERequestCount(labels ...string) {
 	m.acmeClientRequestCount.WithLabelValues(labels...).Inc()
 }
+
+// IncrementACMEErrorCount increases the counter of ACME errors that caused a
+// resource of the given kind, e.g. 'Order' or 'Challenge', to fail.
+func (m *Metrics) IncrementACMEErrorCount(kind string, errorType cmacme.ACMEErrorType) {
+	m.acmeErrorCount.WithLabelValues(kind, string(errorType)).Inc()
+}
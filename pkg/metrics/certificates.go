@@ -21,6 +21,8 @@ limitations under the License.
 // acme_client_request_count{"scheme", "host", "path", "method", "status"}
 // acme_client_request_duration_seconds{"scheme", "host", "path", "method", "status"}
 // controller_sync_call_count{"controller"}
+// certificaterequest_sign_call_count{"namespace", "team", "issuer_type", "result"}
+// certificate_issuance_count{"namespace", "team", "issuer_type"}
 package metrics
 
 import (
@@ -100,7 +102,17 @@ func (m *Metrics) RemoveCertificate(key string) {
 	}
 
 	m.certificateExpiryTimeSeconds.DeleteLabelValues(name, namespace)
+	m.certificateStaleConsumers.DeleteLabelValues(name, namespace)
 	for _, condition := range readyConditionStatuses {
 		m.certificateReadyStatus.DeleteLabelValues(name, namespace, string(condition))
 	}
 }
+
+// SetCertificateStaleConsumers sets the number of running Pods mounting the
+// Secret of the Certificate that were started before the certificate in the
+// Secret was issued.
+func (m *Metrics) SetCertificateStaleConsumers(crt *cmapi.Certificate, count int) {
+	m.certificateStaleConsumers.With(prometheus.Labels{
+		"name":      crt.Name,
+		"namespace": crt.Namespace}).Set(float64(count))
+}
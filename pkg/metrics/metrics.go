@@ -20,7 +20,14 @@ limitations under the License.
 // certificate_ready_status{name, namespace, condition}
 // acme_client_request_count{"scheme", "host", "path", "method", "status"}
 // acme_client_request_duration_seconds{"scheme", "host", "path", "method", "status"}
+// acme_error_count{"kind", "type"}
 // controller_sync_call_count{"controller"}
+// certificaterequest_sign_call_count{"namespace", "team", "issuer_type", "result"}
+// certificate_issuance_count{"namespace", "team", "issuer_type"}
+// certificaterequest_queue_depth{"issuer_namespace", "issuer_name", "issuer_kind"}
+// certificate_stale_consumers{name, namespace}
+// self_certificate_expiration_timestamp_seconds{component}
+// self_certificate_ready_status{component, condition}
 package metrics
 
 import (
@@ -56,7 +63,14 @@ type Metrics struct {
 	certificateReadyStatus           *prometheus.GaugeVec
 	acmeClientRequestDurationSeconds *prometheus.SummaryVec
 	acmeClientRequestCount           *prometheus.CounterVec
+	acmeErrorCount                   *prometheus.CounterVec
 	controllerSyncCallCount          *prometheus.CounterVec
+	certificateRequestSignCallCount  *prometheus.CounterVec
+	certificateIssuanceCount         *prometheus.CounterVec
+	certificateRequestQueueDepth     *prometheus.GaugeVec
+	certificateStaleConsumers        *prometheus.GaugeVec
+	selfCertificateExpiryTimeSeconds *prometheus.GaugeVec
+	selfCertificateReadyStatus       *prometheus.GaugeVec
 }
 
 var readyConditionStatuses = [...]cmmeta.ConditionStatus{cmmeta.ConditionTrue, cmmeta.ConditionFalse, cmmeta.ConditionUnknown}
@@ -106,6 +120,17 @@ func New(log logr.Logger) *Metrics {
 			[]string{"scheme", "host", "path", "method", "status"},
 		)
 
+		// acmeErrorCount is a Prometheus counter to collect the number of ACME
+		// errors that caused an Order or Challenge to fail, by type of error.
+		acmeErrorCount = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "acme_error_count",
+				Help:      "The number of Orders and Challenges that failed due to an error returned by an ACME server.",
+			},
+			[]string{"kind", "type"},
+		)
+
 		controllerSyncCallCount = prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
@@ -114,6 +139,60 @@ func New(log logr.Logger) *Metrics {
 			},
 			[]string{"controller"},
 		)
+
+		certificateRequestSignCallCount = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "certificaterequest_sign_call_count",
+				Help:      "The number of calls made to issuers to sign CertificateRequests.",
+			},
+			[]string{"namespace", "team", "issuer_type", "result"},
+		)
+
+		certificateIssuanceCount = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "certificate_issuance_count",
+				Help:      "The number of certificates issued for CertificateRequests.",
+			},
+			[]string{"namespace", "team", "issuer_type"},
+		)
+
+		certificateRequestQueueDepth = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "certificaterequest_queue_depth",
+				Help:      "The number of CertificateRequests waiting for an issuer that limits the number of concurrent requests.",
+			},
+			[]string{"issuer_namespace", "issuer_name", "issuer_kind"},
+		)
+
+		certificateStaleConsumers = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "certificate_stale_consumers",
+				Help:      "The number of running Pods mounting the Secret of the certificate that were started before the certificate in the Secret was issued.",
+			},
+			[]string{"name", "namespace"},
+		)
+
+		selfCertificateExpiryTimeSeconds = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "self_certificate_expiration_timestamp_seconds",
+				Help:      "The date after which the serving certificate of a cert-manager component expires. Expressed as a Unix Epoch Time.",
+			},
+			[]string{"component"},
+		)
+
+		selfCertificateReadyStatus = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "self_certificate_ready_status",
+				Help:      "The ready status of the serving certificate of a cert-manager component.",
+			},
+			[]string{"component", "condition"},
+		)
 	)
 
 	// Create server and register Prometheus metrics handler
@@ -125,7 +204,14 @@ func New(log logr.Logger) *Metrics {
 		certificateReadyStatus:           certificateReadyStatus,
 		acmeClientRequestCount:           acmeClientRequestCount,
 		acmeClientRequestDurationSeconds: acmeClientRequestDurationSeconds,
+		acmeErrorCount:                   acmeErrorCount,
 		controllerSyncCallCount:          controllerSyncCallCount,
+		certificateRequestSignCallCount:  certificateRequestSignCallCount,
+		certificateIssuanceCount:         certificateIssuanceCount,
+		certificateRequestQueueDepth:     certificateRequestQueueDepth,
+		certificateStaleConsumers:        certificateStaleConsumers,
+		selfCertificateExpiryTimeSeconds: selfCertificateExpiryTimeSeconds,
+		selfCertificateReadyStatus:       selfCertificateReadyStatus,
 	}
 
 	return m
@@ -137,7 +223,14 @@ func (m *Metrics) Start(listenAddress string) (*http.Server, error) {
 	m.registry.MustRegister(m.certificateReadyStatus)
 	m.registry.MustRegister(m.acmeClientRequestDurationSeconds)
 	m.registry.MustRegister(m.acmeClientRequestCount)
+	m.registry.MustRegister(m.acmeErrorCount)
 	m.registry.MustRegister(m.controllerSyncCallCount)
+	m.registry.MustRegister(m.certificateRequestSignCallCount)
+	m.registry.MustRegister(m.certificateIssuanceCount)
+	m.registry.MustRegister(m.certificateRequestQueueDepth)
+	m.registry.MustRegister(m.certificateStaleConsumers)
+	m.registry.MustRegister(m.selfCertificateExpiryTimeSeconds)
+	m.registry.MustRegister(m.selfCertificateReadyStatus)
 
 	router := mux.NewRouter()
 	router.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
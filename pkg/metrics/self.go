@@ -0,0 +1,48 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+// UpdateSelfCertificate updates the metrics of the serving certificate of a
+// cert-manager component, such as the webhook. A zero notAfter time means
+// that the certificate could not be loaded.
+func (m *Metrics) UpdateSelfCertificate(component string, notAfter time.Time, ready cmmeta.ConditionStatus) {
+	expiryTime := 0.0
+	if !notAfter.IsZero() {
+		expiryTime = float64(notAfter.Unix())
+	}
+	m.selfCertificateExpiryTimeSeconds.With(prometheus.Labels{
+		"component": component}).Set(expiryTime)
+
+	for _, condition := range readyConditionStatuses {
+		value := 0.0
+		if ready == condition {
+			value = 1.0
+		}
+		m.selfCertificateReadyStatus.With(prometheus.Labels{
+			"component": component,
+			"condition": string(condition),
+		}).Set(value)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+)
+
+const (
+	// SignResultIssued is the result of a sign call that returned a
+	// certificate
+	SignResultIssued = "issued"
+	// SignResultPending is the result of a sign call that did not return a
+	// certificate yet
+	SignResultPending = "pending"
+	// SignResultError is the result of a sign call that failed
+	SignResultError = "error"
+)
+
+// IncrementSignCallCount increases the counter of calls made to the issuer
+// of the given type to sign the CertificateRequest. For most issuers, each
+// call results in a request to the API of the external CA.
+func (m *Metrics) IncrementSignCallCount(cr *cmapi.CertificateRequest, issuerType, result string) {
+	m.certificateRequestSignCallCount.WithLabelValues(cr.Namespace, cr.Labels[cmapi.TeamLabelKey], issuerType, result).Inc()
+}
+
+// IncrementIssuanceCount increases the counter of certificates issued by the
+// issuer of the given type for the CertificateRequest.
+func (m *Metrics) IncrementIssuanceCount(cr *cmapi.CertificateRequest, issuerType string) {
+	m.certificateIssuanceCount.WithLabelValues(cr.Namespace, cr.Labels[cmapi.TeamLabelKey], issuerType).Inc()
+}
+
+// SetCertificateRequestQueueDepth sets the number of CertificateRequests
+// waiting for the given issuer to have fewer requests in flight.
+func (m *Metrics) SetCertificateRequestQueueDepth(issuerNamespace, issuerName, issuerKind string, depth int) {
+	m.certificateRequestQueueDepth.WithLabelValues(issuerNamespace, issuerName, issuerKind).Set(float64(depth))
+}
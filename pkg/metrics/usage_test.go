@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	logtesting "github.com/jetstack/cert-manager/pkg/logs/testing"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+const signCallMetadata = `
+	# HELP certmanager_certificaterequest_sign_call_count The number of calls made to issuers to sign CertificateRequests.
+	# TYPE certmanager_certificaterequest_sign_call_count counter
+`
+
+const queueDepthMetadata = `
+	# HELP certmanager_certificaterequest_queue_depth The number of CertificateRequests waiting for an issuer that limits the number of concurrent requests.
+	# TYPE certmanager_certificaterequest_queue_depth gauge
+`
+
+const issuanceMetadata = `
+	# HELP certmanager_certificate_issuance_count The number of certificates issued for CertificateRequests.
+	# TYPE certmanager_certificate_issuance_count counter
+`
+
+func TestUsageMetrics(t *testing.T) {
+	m := New(logtesting.TestLogger{T: t})
+
+	withTeam := gen.CertificateRequest("web",
+		gen.SetCertificateRequestNamespace("team-a-ns"),
+	)
+	withTeam.Labels = map[string]string{cmapi.TeamLabelKey: "team-a"}
+	withoutTeam := gen.CertificateRequest("other",
+		gen.SetCertificateRequestNamespace("default"),
+	)
+
+	m.IncrementSignCallCount(withTeam, "venafi", SignResultPending)
+	m.IncrementSignCallCount(withTeam, "venafi", SignResultIssued)
+	m.IncrementIssuanceCount(withTeam, "venafi")
+	m.IncrementSignCallCount(withoutTeam, "acme", SignResultError)
+
+	if err := testutil.CollectAndCompare(m.certificateRequestSignCallCount,
+		strings.NewReader(signCallMetadata+`
+	certmanager_certificaterequest_sign_call_count{issuer_type="acme",namespace="default",result="error",team=""} 1
+	certmanager_certificaterequest_sign_call_count{issuer_type="venafi",namespace="team-a-ns",result="issued",team="team-a"} 1
+	certmanager_certificaterequest_sign_call_count{issuer_type="venafi",namespace="team-a-ns",result="pending",team="team-a"} 1
+`),
+		"certmanager_certificaterequest_sign_call_count",
+	); err != nil {
+		t.Errorf("unexpected collecting result:\n%s", err)
+	}
+
+	if err := testutil.CollectAndCompare(m.certificateIssuanceCount,
+		strings.NewReader(issuanceMetadata+`
+	certmanager_certificate_issuance_count{issuer_type="venafi",namespace="team-a-ns",team="team-a"} 1
+`),
+		"certmanager_certificate_issuance_count",
+	); err != nil {
+		t.Errorf("unexpected collecting result:\n%s", err)
+	}
+
+	m.SetCertificateRequestQueueDepth("default", "tpp", "Issuer", 3)
+	m.SetCertificateRequestQueueDepth("", "tpp", "ClusterIssuer", 1)
+	m.SetCertificateRequestQueueDepth("default", "tpp", "Issuer", 2)
+
+	if err := testutil.CollectAndCompare(m.certificateRequestQueueDepth,
+		strings.NewReader(queueDepthMetadata+`
+	certmanager_certificaterequest_queue_depth{issuer_kind="ClusterIssuer",issuer_name="tpp",issuer_namespace=""} 1
+	certmanager_certificaterequest_queue_depth{issuer_kind="Issuer",issuer_name="tpp",issuer_namespace="default"} 2
+`),
+		"certmanager_certificaterequest_queue_depth",
+	); err != nil {
+		t.Errorf("unexpected collecting result:\n%s", err)
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statusapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	ctlstatus "github.com/jetstack/cert-manager/pkg/ctl/status"
+)
+
+// userAccess decides which of the resources read to collect the status of
+// Certificates may be returned to the user making a request. The status is
+// collected with the privileges of the controller, so every part of it is
+// only returned if the user may read the resources it was collected from.
+// Decisions are remembered for the duration of the request, as many
+// Certificates share the same issuers and namespaces.
+type userAccess struct {
+	authorizer Authorizer
+	user       *authenticationv1.UserInfo
+	mapper     meta.RESTMapper
+
+	decisions map[authorizationv1.ResourceAttributes]bool
+}
+
+func newUserAccess(authorizer Authorizer, user *authenticationv1.UserInfo, mapper meta.RESTMapper) *userAccess {
+	return &userAccess{
+		authorizer: authorizer,
+		user:       user,
+		mapper:     mapper,
+		decisions:  make(map[authorizationv1.ResourceAttributes]bool),
+	}
+}
+
+// allowed returns whether the user may perform the action described by
+// attrs, and the reason for denying it if known.
+func (a *userAccess) allowed(ctx context.Context, attrs authorizationv1.ResourceAttributes) (bool, string, error) {
+	if allowed, ok := a.decisions[attrs]; ok {
+		return allowed, "", nil
+	}
+	decision, reason, err := a.authorizer.Authorize(ctx, a.user, attrs)
+	if err != nil {
+		return false, "", err
+	}
+	a.decisions[attrs] = decision == DecisionAllow
+	return decision == DecisionAllow, reason, nil
+}
+
+// allowedAll returns whether the user may perform all of the actions
+// described by attrs.
+func (a *userAccess) allowedAll(ctx context.Context, attrs ...authorizationv1.ResourceAttributes) (bool, error) {
+	for _, attr := range attrs {
+		allowed, _, err := a.allowed(ctx, attr)
+		if err != nil || !allowed {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// filter removes the parts of status that were collected from resources
+// the user may not read. Removed parts are replaced by an error naming the
+// missing permission, so that users can tell them apart from missing
+// resources.
+func (a *userAccess) filter(ctx context.Context, crt *cmapi.Certificate, status *ctlstatus.CertificateStatus) error {
+	ns := crt.Namespace
+
+	events, err := a.allowedAll(ctx, authorizationv1.ResourceAttributes{Verb: "list", Namespace: ns, Resource: "events"})
+	if err != nil {
+		return err
+	}
+	if !events {
+		status.Events = nil
+		if status.CRStatus != nil {
+			status.CRStatus.Events = nil
+		}
+	}
+
+	if status.SecretStatus != nil {
+		attrs := authorizationv1.ResourceAttributes{Verb: "get", Namespace: ns, Resource: "secrets", Name: crt.Spec.SecretName}
+		if ok, err := a.allowedAll(ctx, attrs); err != nil {
+			return err
+		} else if !ok {
+			status.SecretStatus = &ctlstatus.SecretStatus{Error: forbidden(attrs)}
+		}
+	}
+
+	if status.IssuerStatus != nil {
+		attrs, err := a.issuerAttributes(crt)
+		if err != nil {
+			status.IssuerStatus = &ctlstatus.IssuerStatus{Error: err}
+		} else if ok, err := a.allowedAll(ctx, attrs); err != nil {
+			return err
+		} else if !ok {
+			status.IssuerStatus = &ctlstatus.IssuerStatus{Error: forbidden(attrs)}
+		}
+	}
+
+	if status.CRStatus != nil {
+		// the CertificateRequest is found by listing the CertificateRequests
+		// in the namespace
+		attrs := authorizationv1.ResourceAttributes{Verb: "list", Namespace: ns, Group: cmapi.SchemeGroupVersion.Group, Resource: "certificaterequests"}
+		if ok, err := a.allowedAll(ctx, attrs); err != nil {
+			return err
+		} else if !ok {
+			status.CRStatus = &ctlstatus.CRStatus{Error: forbidden(attrs)}
+			status.Challenges, status.ChallengesError = nil, nil
+		}
+	}
+
+	if status.Challenges != nil || status.ChallengesError != nil {
+		// the Challenges are found by listing the Orders and Challenges in
+		// the namespace
+		orders := authorizationv1.ResourceAttributes{Verb: "list", Namespace: ns, Group: cmacme.SchemeGroupVersion.Group, Resource: "orders"}
+		challenges := authorizationv1.ResourceAttributes{Verb: "list", Namespace: ns, Group: cmacme.SchemeGroupVersion.Group, Resource: "challenges"}
+		if ok, err := a.allowedAll(ctx, orders, challenges); err != nil {
+			return err
+		} else if !ok {
+			status.Challenges, status.ChallengesError = nil, forbidden(challenges)
+		}
+	}
+
+	if len(status.Challenges) > 0 {
+		solvers, err := a.allowedAll(ctx,
+			authorizationv1.ResourceAttributes{Verb: "list", Namespace: ns, Resource: "pods"},
+			authorizationv1.ResourceAttributes{Verb: "list", Namespace: ns, Resource: "services"},
+			authorizationv1.ResourceAttributes{Verb: "list", Namespace: ns, Group: "extensions", Resource: "ingresses"},
+		)
+		if err != nil {
+			return err
+		}
+		for _, ch := range status.Challenges {
+			if !solvers {
+				ch.Solvers = nil
+				continue
+			}
+			if !events {
+				for _, solver := range ch.Solvers {
+					solver.Events = nil
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// issuerAttributes returns the attributes of getting the issuer referenced
+// by crt.
+func (a *userAccess) issuerAttributes(crt *cmapi.Certificate) (authorizationv1.ResourceAttributes, error) {
+	ref := crt.Spec.IssuerRef
+	attrs := authorizationv1.ResourceAttributes{Verb: "get", Group: ref.Group, Name: ref.Name}
+	if attrs.Group == "" {
+		attrs.Group = cmapi.SchemeGroupVersion.Group
+	}
+	kind := ref.Kind
+	if kind == "" {
+		kind = cmapi.IssuerKind
+	}
+
+	if attrs.Group == cmapi.SchemeGroupVersion.Group {
+		switch kind {
+		case cmapi.IssuerKind:
+			attrs.Resource, attrs.Namespace = "issuers", crt.Namespace
+			return attrs, nil
+		case cmapi.ClusterIssuerKind:
+			attrs.Resource = "clusterissuers"
+			return attrs, nil
+		}
+	}
+
+	if a.mapper == nil {
+		return attrs, fmt.Errorf("the status of %s.%s %q is not served", kind, attrs.Group, ref.Name)
+	}
+	mapping, err := a.mapper.RESTMapping(schema.GroupKind{Group: attrs.Group, Kind: kind})
+	if err != nil {
+		return attrs, fmt.Errorf("the status of %s.%s %q is not served: %v", kind, attrs.Group, ref.Name, err)
+	}
+	attrs.Resource = mapping.Resource.Resource
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		attrs.Namespace = crt.Namespace
+	}
+	return attrs, nil
+}
+
+// forbidden returns the error reported in place of the status of a
+// resource that the user may not read.
+func forbidden(attrs authorizationv1.ResourceAttributes) error {
+	resource := attrs.Resource
+	if attrs.Group != "" {
+		resource += "." + attrs.Group
+	}
+	msg := fmt.Sprintf("user is not allowed to %s %s", attrs.Verb, resource)
+	if attrs.Name != "" {
+		msg += fmt.Sprintf(" %q", attrs.Name)
+	}
+	if attrs.Namespace != "" {
+		msg += fmt.Sprintf(" in namespace %q", attrs.Namespace)
+	}
+	return errors.New(msg)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statusapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Decision is the result of authorizing an action of a user.
+type Decision int
+
+const (
+	// DecisionAllow means the user may perform the action.
+	DecisionAllow Decision = iota
+	// DecisionDeny means the user may not perform the action.
+	DecisionDeny
+)
+
+// Authorizer authenticates the user making a request and decides whether
+// they may perform the actions described by resource attributes.
+type Authorizer interface {
+	// Authenticate returns the user making the request, or nil if the
+	// user could not be authenticated. An error is returned if the
+	// request could not be authenticated.
+	Authenticate(r *http.Request) (*authenticationv1.UserInfo, error)
+	// Authorize returns the decision whether user may perform the action
+	// described by attrs, and the reason for denying it if known. An error
+	// is returned if no decision could be made.
+	Authorize(ctx context.Context, user *authenticationv1.UserInfo, attrs authorizationv1.ResourceAttributes) (Decision, string, error)
+}
+
+// kubeAuthorizer authenticates the bearer token of a request with a
+// TokenReview, and authorizes the user with a SubjectAccessReview, so that
+// users may read the status of the Certificates they may get from the
+// apiserver.
+type kubeAuthorizer struct {
+	client kubernetes.Interface
+}
+
+// NewKubeAuthorizer returns an Authorizer delegating authentication and
+// authorization to the apiserver using client.
+func NewKubeAuthorizer(client kubernetes.Interface) Authorizer {
+	return &kubeAuthorizer{client: client}
+}
+
+func (k *kubeAuthorizer) Authenticate(r *http.Request) (*authenticationv1.UserInfo, error) {
+	token := bearerToken(r)
+	if token == "" {
+		return nil, nil
+	}
+
+	review, err := k.client.AuthenticationV1().TokenReviews().Create(r.Context(), &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error creating TokenReview: %v", err)
+	}
+	if !review.Status.Authenticated {
+		return nil, nil
+	}
+	return &review.Status.User, nil
+}
+
+func (k *kubeAuthorizer) Authorize(ctx context.Context, user *authenticationv1.UserInfo, attrs authorizationv1.ResourceAttributes) (Decision, string, error) {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	sar, err := k.client.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &attrs,
+			User:               user.Username,
+			Groups:             user.Groups,
+			Extra:              extra,
+			UID:                user.UID,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return DecisionDeny, "", fmt.Errorf("error creating SubjectAccessReview: %v", err)
+	}
+	if !sar.Status.Allowed {
+		return DecisionDeny, sar.Status.Reason, nil
+	}
+
+	return DecisionAllow, "", nil
+}
+
+// bearerToken returns the bearer token in the Authorization header of r, or
+// an empty string if there is none.
+func bearerToken(r *http.Request) string {
+	parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
+		return ""
+	}
+	return strings.TrimSpace(parts[1])
+}
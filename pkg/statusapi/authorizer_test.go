@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statusapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	coretesting "k8s.io/client-go/testing"
+)
+
+func TestKubeAuthorizer(t *testing.T) {
+	tests := map[string]struct {
+		header        string
+		authenticated bool
+		allowed       bool
+
+		expUnauthenticated bool
+		expDecision        Decision
+		expSAR             bool
+	}{
+		"requests without a bearer token are unauthenticated": {
+			header:             "",
+			expUnauthenticated: true,
+		},
+		"requests with an invalid token are unauthenticated": {
+			header:             "Bearer invalid",
+			expUnauthenticated: true,
+		},
+		"authenticated users without access are denied": {
+			header:        "Bearer token",
+			authenticated: true,
+			expDecision:   DecisionDeny,
+			expSAR:        true,
+		},
+		"authenticated users with access are allowed": {
+			header:        "bearer token",
+			authenticated: true,
+			allowed:       true,
+			expDecision:   DecisionAllow,
+			expSAR:        true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var sar *authorizationv1.SubjectAccessReview
+			client := kubefake.NewSimpleClientset()
+			client.PrependReactor("create", "tokenreviews", func(action coretesting.Action) (bool, runtime.Object, error) {
+				review := action.(coretesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+				review.Status.Authenticated = test.authenticated && review.Spec.Token == "token"
+				if review.Status.Authenticated {
+					review.Status.User = authenticationv1.UserInfo{Username: "alice", Groups: []string{"dev"}}
+				}
+				return true, review, nil
+			})
+			client.PrependReactor("create", "subjectaccessreviews", func(action coretesting.Action) (bool, runtime.Object, error) {
+				sar = action.(coretesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				sar.Status.Allowed = test.allowed
+				return true, sar, nil
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.header != "" {
+				req.Header.Set("Authorization", test.header)
+			}
+			authorizer := NewKubeAuthorizer(client)
+			user, err := authorizer.Authenticate(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if test.expUnauthenticated != (user == nil) {
+				t.Fatalf("expected unauthenticated=%t, got user %v", test.expUnauthenticated, user)
+			}
+			if user == nil {
+				return
+			}
+
+			decision, _, err := authorizer.Authorize(req.Context(), user, authorizationv1.ResourceAttributes{
+				Verb:      "get",
+				Group:     "cert-manager.io",
+				Resource:  "certificates",
+				Namespace: "default",
+				Name:      "web",
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decision != test.expDecision {
+				t.Errorf("expected decision %d, got %d", test.expDecision, decision)
+			}
+			if test.expSAR != (sar != nil) {
+				t.Fatalf("expected SubjectAccessReview to be created=%t", test.expSAR)
+			}
+			if sar != nil {
+				if sar.Spec.User != "alice" || len(sar.Spec.Groups) != 1 || sar.Spec.ResourceAttributes.Name != "web" {
+					t.Errorf("unexpected SubjectAccessReview spec: %+v", sar.Spec)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statusapi implements a read-only HTTP API serving the status of
+// Certificates as JSON. The status is collected by the same code as the
+// 'status certificate' command of kubectl cert-manager, so that dashboards
+// don't have to traverse CertificateRequests, Orders and Challenges
+// themselves.
+package statusapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/mux"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	ctlclients "github.com/jetstack/cert-manager/pkg/ctl/clients"
+	ctlstatus "github.com/jetstack/cert-manager/pkg/ctl/status"
+)
+
+// PathPrefix is the prefix of the paths served by the API.
+const PathPrefix = "/apis/status.cert-manager.io/v1alpha1"
+
+// maxListLimit is the maximum number of Certificates whose status is
+// returned by a single list request, and the number returned if the request
+// does not set a limit. Further pages are requested with the continue token
+// of the response.
+const maxListLimit = 100
+
+// CertificateStatusList is the response to a request listing the status of
+// Certificates.
+type CertificateStatusList struct {
+	Items []*ctlstatus.CertificateStatus `json:"items"`
+	// Continue is set if there are more Certificates, and is passed as the
+	// continue query parameter to get the next page.
+	Continue string `json:"continue,omitempty"`
+}
+
+// errorResponse is the response to a request that failed.
+type errorResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Server serves the status API. It only handles GET and HEAD requests, and
+// never modifies resources. Each request is authorized as if the user made
+// it to get or list Certificates from the apiserver, and each part of the
+// status is only returned if the user may also read the Secret, Events,
+// issuer, CertificateRequests and Challenges it was collected from.
+type Server struct {
+	clients ctlstatus.Clients
+	// namespace is the namespace the status of Certificates is served
+	// from, or metav1.NamespaceAll
+	namespace  string
+	chunkSize  int64
+	authorizer Authorizer
+	log        logr.Logger
+
+	router *mux.Router
+}
+
+// NewServer returns a Server serving the status of Certificates in
+// namespace, or in all namespaces if namespace is metav1.NamespaceAll, using
+// clients. Resources other than Certificates are listed in pages of
+// chunkSize resources.
+func NewServer(log logr.Logger, clients ctlstatus.Clients, namespace string, chunkSize int64, authorizer Authorizer) *Server {
+	s := &Server{
+		clients:    clients,
+		namespace:  namespace,
+		chunkSize:  chunkSize,
+		authorizer: authorizer,
+		log:        log,
+		router:     mux.NewRouter(),
+	}
+	s.router.HandleFunc(PathPrefix+"/certificates", s.handleList).Methods(http.MethodGet, http.MethodHead)
+	s.router.HandleFunc(PathPrefix+"/namespaces/{namespace}/certificates", s.handleList).Methods(http.MethodGet, http.MethodHead)
+	s.router.HandleFunc(PathPrefix+"/namespaces/{namespace}/certificates/{name}", s.handleCertificate).Methods(http.MethodGet, http.MethodHead)
+	s.router.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok"))
+	}).Methods(http.MethodGet, http.MethodHead)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	namespace := mux.Vars(r)["namespace"]
+	if s.namespace != metav1.NamespaceAll && namespace != s.namespace {
+		s.error(w, http.StatusNotFound, "the status of Certificates in namespace %q is not served", namespace)
+		return
+	}
+	limit := int64(maxListLimit)
+	if v := r.URL.Query().Get("limit"); v != "" {
+		l, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || l <= 0 {
+			s.error(w, http.StatusBadRequest, "invalid limit %q, must be a positive integer", v)
+			return
+		}
+		if l < limit {
+			limit = l
+		}
+	}
+	access, ok := s.authorize(w, r, authorizationv1.ResourceAttributes{
+		Verb:      "list",
+		Namespace: namespace,
+	})
+	if !ok {
+		return
+	}
+
+	crtList, err := s.clients.CM.CertmanagerV1alpha2().Certificates(namespace).List(r.Context(), metav1.ListOptions{
+		Limit:    limit,
+		Continue: r.URL.Query().Get("continue"),
+	})
+	if apierrors.IsResourceExpired(err) {
+		s.error(w, http.StatusGone, "the continue token has expired, list again without it: %v", err)
+		return
+	}
+	if apierrors.IsBadRequest(err) {
+		s.error(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	if err != nil {
+		s.serverError(w, "error listing Certificates", err)
+		return
+	}
+	crts := crtList.Items
+	sort.Slice(crts, func(i, j int) bool {
+		if crts[i].Namespace != crts[j].Namespace {
+			return crts[i].Namespace < crts[j].Namespace
+		}
+		return crts[i].Name < crts[j].Name
+	})
+
+	clients := s.clients
+	clients.Cache = ctlclients.NewCache(s.clients.Kube, s.clients.CM, s.chunkSize)
+	list := CertificateStatusList{Items: []*ctlstatus.CertificateStatus{}, Continue: crtList.Continue}
+	for i := range crts {
+		crt := &crts[i]
+		status, err := s.collect(r, clients, access, crt)
+		if err != nil {
+			s.serverError(w, "error collecting status of Certificate "+crt.Namespace+"/"+crt.Name, err)
+			return
+		}
+		list.Items = append(list.Items, status)
+	}
+
+	s.write(w, http.StatusOK, list)
+}
+
+func (s *Server) handleCertificate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace, name := vars["namespace"], vars["name"]
+	if s.namespace != metav1.NamespaceAll && namespace != s.namespace {
+		s.error(w, http.StatusNotFound, "the status of Certificates in namespace %q is not served", namespace)
+		return
+	}
+	access, ok := s.authorize(w, r, authorizationv1.ResourceAttributes{
+		Verb:      "get",
+		Namespace: namespace,
+		Name:      name,
+	})
+	if !ok {
+		return
+	}
+
+	crt, err := s.clients.CM.CertmanagerV1alpha2().Certificates(namespace).Get(r.Context(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		s.error(w, http.StatusNotFound, "Certificate %s/%s not found", namespace, name)
+		return
+	}
+	if err != nil {
+		s.serverError(w, "error getting Certificate", err)
+		return
+	}
+	status, err := s.collect(r, s.clients, access, crt)
+	if err != nil {
+		s.serverError(w, "error collecting status of Certificate", err)
+		return
+	}
+
+	s.write(w, http.StatusOK, status)
+}
+
+// collect collects the status of crt, leaving out the parts the user may
+// not read.
+func (s *Server) collect(r *http.Request, clients ctlstatus.Clients, access *userAccess, crt *cmapi.Certificate) (*ctlstatus.CertificateStatus, error) {
+	status, err := ctlstatus.CollectStatusForCertificate(r.Context(), clients, crt)
+	if err != nil {
+		return nil, err
+	}
+	if err := access.filter(r.Context(), crt, status); err != nil {
+		return nil, fmt.Errorf("error authorizing request: %v", err)
+	}
+	return status, nil
+}
+
+// authorize authenticates the user making the request and checks that
+// they may perform the given action on Certificates, writing an error
+// response if not. It returns the access of the user to the resources the
+// status is collected from.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, attrs authorizationv1.ResourceAttributes) (*userAccess, bool) {
+	user, err := s.authorizer.Authenticate(r)
+	if err != nil {
+		s.serverError(w, "error authenticating request", err)
+		return nil, false
+	}
+	if user == nil {
+		s.error(w, http.StatusUnauthorized, "unauthorized")
+		return nil, false
+	}
+
+	access := newUserAccess(s.authorizer, user, s.clients.RESTMapper)
+	attrs.Group = cmapi.SchemeGroupVersion.Group
+	attrs.Resource = "certificates"
+	allowed, reason, err := access.allowed(r.Context(), attrs)
+	if err != nil {
+		s.serverError(w, "error authorizing request", err)
+		return nil, false
+	}
+	if !allowed {
+		msg := "user is not allowed to " + attrs.Verb + " certificates"
+		if reason != "" {
+			msg += ": " + reason
+		}
+		s.error(w, http.StatusForbidden, "%s", msg)
+		return nil, false
+	}
+	return access, true
+}
+
+func (s *Server) write(w http.ResponseWriter, code int, obj interface{}) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		s.serverError(w, "error encoding response", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+func (s *Server) serverError(w http.ResponseWriter, msg string, err error) {
+	s.log.Error(err, msg)
+	s.error(w, http.StatusInternalServerError, "%s: %v", msg, err)
+}
+
+func (s *Server) error(w http.ResponseWriter, code int, format string, args ...interface{}) {
+	data, _ := json.Marshal(errorResponse{Code: code, Message: fmt.Sprintf(format, args...)})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statusapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	coretesting "k8s.io/client-go/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+	ctlstatus "github.com/jetstack/cert-manager/pkg/ctl/status"
+	logtesting "github.com/jetstack/cert-manager/pkg/logs/testing"
+)
+
+// fakeAuthorizer allows the actions in allowed, keyed by verb, resource and
+// namespace, and denies everything else. Requests without an Authorization
+// header are unauthenticated.
+type fakeAuthorizer struct {
+	allowed map[string]bool
+}
+
+func (f fakeAuthorizer) Authenticate(r *http.Request) (*authenticationv1.UserInfo, error) {
+	if r.Header.Get("Authorization") == "" {
+		return nil, nil
+	}
+	return &authenticationv1.UserInfo{Username: "alice"}, nil
+}
+
+func (f fakeAuthorizer) Authorize(_ context.Context, _ *authenticationv1.UserInfo, attrs authorizationv1.ResourceAttributes) (Decision, string, error) {
+	if f.allowed[attrs.Verb+" "+attrs.Resource+" "+attrs.Namespace] {
+		return DecisionAllow, "", nil
+	}
+	return DecisionDeny, "no RBAC policy matched", nil
+}
+
+func TestServer(t *testing.T) {
+	newCrt := func(namespace, name string) *cmapi.Certificate {
+		return &cmapi.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       cmapi.CertificateSpec{SecretName: name + "-tls"},
+		}
+	}
+	clients := ctlstatus.Clients{
+		Kube: kubefake.NewSimpleClientset(),
+		CM: cmfake.NewSimpleClientset(
+			newCrt("default", "web"),
+			newCrt("default", "api"),
+			newCrt("other", "db"),
+		),
+	}
+	authorizer := fakeAuthorizer{allowed: map[string]bool{
+		"list certificates ":        true,
+		"list certificates default": true,
+		"get certificates default":  true,
+	}}
+
+	tests := map[string]struct {
+		namespace     string
+		path          string
+		unauthorized  bool
+		expCode       int
+		expSingle     bool
+		expCertsNames []string
+	}{
+		"list the status of Certificates in all namespaces": {
+			path:          PathPrefix + "/certificates",
+			expCode:       http.StatusOK,
+			expCertsNames: []string{"default/api", "default/web", "other/db"},
+		},
+		"list the status of Certificates in a namespace": {
+			path:          PathPrefix + "/namespaces/default/certificates",
+			expCode:       http.StatusOK,
+			expCertsNames: []string{"default/api", "default/web"},
+		},
+		"get the status of a Certificate": {
+			path:          PathPrefix + "/namespaces/default/certificates/web",
+			expCode:       http.StatusOK,
+			expSingle:     true,
+			expCertsNames: []string{"default/web"},
+		},
+		"Certificate does not exist": {
+			path:    PathPrefix + "/namespaces/default/certificates/missing",
+			expCode: http.StatusNotFound,
+		},
+		"requests without credentials are rejected": {
+			path:         PathPrefix + "/namespaces/default/certificates/web",
+			unauthorized: true,
+			expCode:      http.StatusUnauthorized,
+		},
+		"users that may not get the Certificate are forbidden": {
+			path:    PathPrefix + "/namespaces/other/certificates/db",
+			expCode: http.StatusForbidden,
+		},
+		"users that may not list Certificates are forbidden": {
+			path:    PathPrefix + "/namespaces/other/certificates",
+			expCode: http.StatusForbidden,
+		},
+		"invalid limits are rejected": {
+			path:    PathPrefix + "/certificates?limit=-1",
+			expCode: http.StatusBadRequest,
+		},
+		"Certificates outside of the namespace are not served": {
+			namespace: "other",
+			path:      PathPrefix + "/namespaces/default/certificates/web",
+			expCode:   http.StatusNotFound,
+		},
+		"listing all namespaces is not served if scoped to a namespace": {
+			namespace: "default",
+			path:      PathPrefix + "/certificates",
+			expCode:   http.StatusNotFound,
+		},
+		"health checks do not require credentials": {
+			path:         "/healthz",
+			unauthorized: true,
+			expCode:      http.StatusOK,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(logtesting.TestLogger{T: t}, clients, test.namespace, 0, authorizer)
+
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			if !test.unauthorized {
+				req.Header.Set("Authorization", "Bearer token")
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+
+			if rec.Code != test.expCode {
+				t.Fatalf("expected status code %d, got %d: %s", test.expCode, rec.Code, rec.Body.String())
+			}
+			if test.expCertsNames == nil {
+				return
+			}
+
+			var list struct {
+				Items []struct {
+					Name      string `json:"name"`
+					Namespace string `json:"namespace"`
+				} `json:"items"`
+			}
+			if test.expSingle {
+				var item struct {
+					Name      string `json:"name"`
+					Namespace string `json:"namespace"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &item); err != nil {
+					t.Fatalf("error decoding response: %v", err)
+				}
+				list.Items = append(list.Items, item)
+			} else if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+				t.Fatalf("error decoding response: %v", err)
+			}
+
+			var names []string
+			for _, item := range list.Items {
+				names = append(names, item.Namespace+"/"+item.Name)
+			}
+			if len(names) != len(test.expCertsNames) {
+				t.Fatalf("expected %v, got %v", test.expCertsNames, names)
+			}
+			for i := range names {
+				if names[i] != test.expCertsNames[i] {
+					t.Errorf("expected %v, got %v", test.expCertsNames, names)
+				}
+			}
+		})
+	}
+}
+
+func TestServerPagination(t *testing.T) {
+	cm := cmfake.NewSimpleClientset()
+	cm.PrependReactor("list", "certificates", func(action coretesting.Action) (bool, runtime.Object, error) {
+		return true, &cmapi.CertificateList{
+			ListMeta: metav1.ListMeta{Continue: "next"},
+			Items: []cmapi.Certificate{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"}},
+			},
+		}, nil
+	})
+	clients := ctlstatus.Clients{Kube: kubefake.NewSimpleClientset(), CM: cm}
+	authorizer := fakeAuthorizer{allowed: map[string]bool{"list certificates ": true}}
+
+	for _, query := range []string{"", "?limit=10&continue=token", "?limit=100000"} {
+		t.Run(query, func(t *testing.T) {
+			s := NewServer(logtesting.TestLogger{T: t}, clients, "", 0, authorizer)
+			req := httptest.NewRequest(http.MethodGet, PathPrefix+"/certificates"+query, nil)
+			req.Header.Set("Authorization", "Bearer token")
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+			}
+			var list struct {
+				Items    []json.RawMessage `json:"items"`
+				Continue string            `json:"continue"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+				t.Fatalf("error decoding response: %v", err)
+			}
+			if len(list.Items) != 1 || list.Continue != "next" {
+				t.Errorf("expected one item and continue token \"next\", got %d items and %q", len(list.Items), list.Continue)
+			}
+		})
+	}
+}
+
+func TestServerFiltersStatus(t *testing.T) {
+	clients := ctlstatus.Clients{
+		Kube: kubefake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-tls"},
+		}),
+		CM: cmfake.NewSimpleClientset(
+			&cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+				Spec: cmapi.CertificateSpec{
+					SecretName: "web-tls",
+					IssuerRef:  cmmeta.ObjectReference{Name: "ca"},
+				},
+			},
+			&cmapi.Issuer{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ca"}},
+		),
+	}
+
+	type section struct {
+		Name  string `json:"name"`
+		Error string `json:"error"`
+	}
+	tests := map[string]struct {
+		allowed map[string]bool
+
+		expSecretErr string
+		expIssuer    section
+		expCRErr     string
+	}{
+		"only the Certificate is returned to users that may only get Certificates": {
+			allowed: map[string]bool{
+				"get certificates default": true,
+			},
+			expSecretErr: `user is not allowed to get secrets "web-tls" in namespace "default"`,
+			expIssuer:    section{Error: `user is not allowed to get issuers.cert-manager.io "ca" in namespace "default"`},
+			expCRErr:     `user is not allowed to list certificaterequests.cert-manager.io in namespace "default"`,
+		},
+		"the status of all resources is returned to users that may read them": {
+			allowed: map[string]bool{
+				"get certificates default":         true,
+				"get secrets default":              true,
+				"get issuers default":              true,
+				"list certificaterequests default": true,
+			},
+			expSecretErr: `error: 'tls.crt' of Secret "web-tls" is not set`,
+			expIssuer:    section{Name: "ca"},
+			expCRErr:     "No CertificateRequest found for this Certificate",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(logtesting.TestLogger{T: t}, clients, "", 0, fakeAuthorizer{allowed: test.allowed})
+			req := httptest.NewRequest(http.MethodGet, PathPrefix+"/namespaces/default/certificates/web", nil)
+			req.Header.Set("Authorization", "Bearer token")
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+			}
+			var status struct {
+				Secret             section `json:"secret"`
+				Issuer             section `json:"issuer"`
+				CertificateRequest section `json:"certificateRequest"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+				t.Fatalf("error decoding response: %v", err)
+			}
+			if got := strings.TrimSpace(status.Secret.Error); got != test.expSecretErr {
+				t.Errorf("expected Secret error %q, got %q", test.expSecretErr, got)
+			}
+			if status.Issuer.Name != test.expIssuer.Name || strings.TrimSpace(status.Issuer.Error) != test.expIssuer.Error {
+				t.Errorf("expected issuer status %+v, got %+v", test.expIssuer, status.Issuer)
+			}
+			if got := strings.TrimSpace(status.CertificateRequest.Error); got != test.expCRErr {
+				t.Errorf("expected CertificateRequest error %q, got %q", test.expCRErr, got)
+			}
+		})
+	}
+}
+
+func TestServerIsReadOnly(t *testing.T) {
+	s := NewServer(logtesting.TestLogger{T: t}, ctlstatus.Clients{}, "", 0, fakeAuthorizer{})
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(method, PathPrefix+"/namespaces/default/certificates/web", nil))
+		if rec.Code < 400 {
+			t.Errorf("expected %s request to be rejected, got status code %d", method, rec.Code)
+		}
+	}
+}
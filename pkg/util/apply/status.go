@@ -0,0 +1,256 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apply writes the status of cert-manager resources with server-side
+// apply.
+// Every controller applies only the status fields and conditions it manages,
+// with its own field manager, so that it never overwrites the status written
+// by another controller from a stale copy of the resource. Conditions are
+// lists keyed by their type, so that each condition can be owned by a
+// different controller.
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+)
+
+// Fields are the status fields and conditions of a resource that are managed
+// by a controller.
+type Fields struct {
+	// Status are the JSON names of the top-level status fields, other than
+	// the conditions, e.g. 'notAfter'.
+	Status []string
+	// Conditions are the types of the conditions.
+	Conditions []string
+}
+
+// patchFunc patches the status subresource of a resource.
+type patchFunc func(pt types.PatchType, data []byte, opts metav1.PatchOptions) error
+
+// CertificateStatus writes the fields of the status of crt that are managed
+// by the controller with server-side apply, and returns the updated
+// Certificate. existing is the Certificate the status was computed from.
+func CertificateStatus(ctx context.Context, cl cmclient.Interface, fieldManager string, fields Fields, existing, crt *cmapi.Certificate) (*cmapi.Certificate, error) {
+	var updated *cmapi.Certificate
+	err := applyStatus(cmapi.SchemeGroupVersion.WithKind(cmapi.CertificateKind), crt.Namespace, crt.Name, fieldManager, fields, existing.Status, crt.Status,
+		func(pt types.PatchType, data []byte, opts metav1.PatchOptions) (err error) {
+			updated, err = cl.CertmanagerV1alpha2().Certificates(crt.Namespace).Patch(ctx, crt.Name, pt, data, opts, "status")
+			return err
+		})
+	return updated, err
+}
+
+// CertificateRequestStatus writes the fields of the status of cr that are
+// managed by the controller with server-side apply, and returns the updated
+// CertificateRequest. See CertificateStatus.
+func CertificateRequestStatus(ctx context.Context, cl cmclient.Interface, fieldManager string, fields Fields, existing, cr *cmapi.CertificateRequest) (*cmapi.CertificateRequest, error) {
+	var updated *cmapi.CertificateRequest
+	err := applyStatus(cmapi.SchemeGroupVersion.WithKind(cmapi.CertificateRequestKind), cr.Namespace, cr.Name, fieldManager, fields, existing.Status, cr.Status,
+		func(pt types.PatchType, data []byte, opts metav1.PatchOptions) (err error) {
+			updated, err = cl.CertmanagerV1alpha2().CertificateRequests(cr.Namespace).Patch(ctx, cr.Name, pt, data, opts, "status")
+			return err
+		})
+	return updated, err
+}
+
+// IssuerStatus writes the fields of the status of issuer that are managed by
+// the controller with server-side apply, and returns the updated Issuer. See
+// CertificateStatus.
+func IssuerStatus(ctx context.Context, cl cmclient.Interface, fieldManager string, fields Fields, existing, issuer *cmapi.Issuer) (*cmapi.Issuer, error) {
+	var updated *cmapi.Issuer
+	err := applyStatus(cmapi.SchemeGroupVersion.WithKind(cmapi.IssuerKind), issuer.Namespace, issuer.Name, fieldManager, fields, existing.Status, issuer.Status,
+		func(pt types.PatchType, data []byte, opts metav1.PatchOptions) (err error) {
+			updated, err = cl.CertmanagerV1alpha2().Issuers(issuer.Namespace).Patch(ctx, issuer.Name, pt, data, opts, "status")
+			return err
+		})
+	return updated, err
+}
+
+// ClusterIssuerStatus writes the fields of the status of issuer that are
+// managed by the controller with server-side apply, and returns the updated
+// ClusterIssuer. See CertificateStatus.
+func ClusterIssuerStatus(ctx context.Context, cl cmclient.Interface, fieldManager string, fields Fields, existing, issuer *cmapi.ClusterIssuer) (*cmapi.ClusterIssuer, error) {
+	var updated *cmapi.ClusterIssuer
+	err := applyStatus(cmapi.SchemeGroupVersion.WithKind(cmapi.ClusterIssuerKind), "", issuer.Name, fieldManager, fields, existing.Status, issuer.Status,
+		func(pt types.PatchType, data []byte, opts metav1.PatchOptions) (err error) {
+			updated, err = cl.CertmanagerV1alpha2().ClusterIssuers().Patch(ctx, issuer.Name, pt, data, opts, "status")
+			return err
+		})
+	return updated, err
+}
+
+// OrderStatus writes the fields of the status of order that are managed by
+// the controller with server-side apply, and returns the updated Order. See
+// CertificateStatus.
+func OrderStatus(ctx context.Context, cl cmclient.Interface, fieldManager string, fields Fields, existing, order *cmacme.Order) (*cmacme.Order, error) {
+	var updated *cmacme.Order
+	err := applyStatus(cmacme.SchemeGroupVersion.WithKind("Order"), order.Namespace, order.Name, fieldManager, fields, existing.Status, order.Status,
+		func(pt types.PatchType, data []byte, opts metav1.PatchOptions) (err error) {
+			updated, err = cl.AcmeV1alpha2().Orders(order.Namespace).Patch(ctx, order.Name, pt, data, opts, "status")
+			return err
+		})
+	return updated, err
+}
+
+// ChallengeStatus writes the fields of the status of ch that are managed by
+// the controller with server-side apply, and returns the updated Challenge.
+// See CertificateStatus.
+func ChallengeStatus(ctx context.Context, cl cmclient.Interface, fieldManager string, fields Fields, existing, ch *cmacme.Challenge) (*cmacme.Challenge, error) {
+	var updated *cmacme.Challenge
+	err := applyStatus(cmacme.SchemeGroupVersion.WithKind("Challenge"), ch.Namespace, ch.Name, fieldManager, fields, existing.Status, ch.Status,
+		func(pt types.PatchType, data []byte, opts metav1.PatchOptions) (err error) {
+			updated, err = cl.AcmeV1alpha2().Challenges(ch.Namespace).Patch(ctx, ch.Name, pt, data, opts, "status")
+			return err
+		})
+	return updated, err
+}
+
+// applyStatus removes the managed fields and conditions that are set in
+// existing but no longer in status, and then applies those that are set in
+// status.
+// Fields that were written by other field managers, including the fields
+// written with Update before server-side apply was enabled, are not removed
+// by the apiserver when they are left out of an apply, so they are always
+// removed explicitly with a JSON patch.
+func applyStatus(gvk schema.GroupVersionKind, namespace, name, fieldManager string, fields Fields, existing, status interface{}, patch patchFunc) error {
+	remove, applied, err := statusPatches(gvk, namespace, name, fields, existing, status)
+	if err != nil {
+		return err
+	}
+
+	if remove != nil {
+		if err := patch(types.JSONPatchType, remove, metav1.PatchOptions{FieldManager: fieldManager}); err != nil {
+			return fmt.Errorf("removing status fields: %w", err)
+		}
+	}
+
+	force := true
+	return patch(types.ApplyPatchType, applied, metav1.PatchOptions{FieldManager: fieldManager, Force: &force})
+}
+
+// statusPatches returns the JSON patch removing the managed fields and
+// conditions that are set in existing but not in status, or nil if there are
+// none, and the apply configuration of the managed fields and conditions
+// that are set in status.
+func statusPatches(gvk schema.GroupVersionKind, namespace, name string, fields Fields, existing, status interface{}) (remove, applied []byte, err error) {
+	existingFields, err := toMap(existing)
+	if err != nil {
+		return nil, nil, err
+	}
+	statusFields, err := toMap(status)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	appliedStatus := make(map[string]interface{})
+	var ops []map[string]interface{}
+
+	for _, field := range fields.Status {
+		if value, ok := statusFields[field]; ok {
+			appliedStatus[field] = value
+		} else if _, ok := existingFields[field]; ok {
+			ops = append(ops, map[string]interface{}{"op": "remove", "path": "/status/" + field})
+		}
+	}
+
+	managed := sets.NewString(fields.Conditions...)
+	set := sets.NewString()
+	var conditions []interface{}
+	for _, condition := range conditionsOf(statusFields) {
+		if conditionType := conditionTypeOf(condition); managed.Has(conditionType) {
+			conditions = append(conditions, condition)
+			set.Insert(conditionType)
+		}
+	}
+	if len(conditions) > 0 {
+		appliedStatus["conditions"] = conditions
+	}
+
+	// conditions are removed by their index, last to first so that the
+	// indices of the conditions that are still to be removed do not change.
+	// The type of each condition is tested first, so that the patch fails
+	// instead of removing another condition if the conditions have changed.
+	existingConditions := conditionsOf(existingFields)
+	for i := len(existingConditions) - 1; i >= 0; i-- {
+		conditionType := conditionTypeOf(existingConditions[i])
+		if !managed.Has(conditionType) || set.Has(conditionType) {
+			continue
+		}
+		path := fmt.Sprintf("/status/conditions/%d", i)
+		ops = append(ops,
+			map[string]interface{}{"op": "test", "path": path + "/type", "value": conditionType},
+			map[string]interface{}{"op": "remove", "path": path},
+		)
+	}
+
+	if len(ops) > 0 {
+		remove, err = json.Marshal(ops)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	metadata := map[string]interface{}{"name": name}
+	if namespace != "" {
+		metadata["namespace"] = namespace
+	}
+	applied, err = json.Marshal(map[string]interface{}{
+		"apiVersion": gvk.GroupVersion().String(),
+		"kind":       gvk.Kind,
+		"metadata":   metadata,
+		"status":     appliedStatus,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return remove, applied, nil
+}
+
+// toMap returns the JSON representation of a status as a map.
+func toMap(status interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func conditionsOf(status map[string]interface{}) []interface{} {
+	conditions, _ := status["conditions"].([]interface{})
+	return conditions
+}
+
+func conditionTypeOf(condition interface{}) string {
+	m, _ := condition.(map[string]interface{})
+	conditionType, _ := m["type"].(string)
+	return conditionType
+}
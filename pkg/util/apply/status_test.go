@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestStatusPatches(t *testing.T) {
+	gvk := cmapi.SchemeGroupVersion.WithKind(cmapi.CertificateKind)
+	notAfter := metav1.NewTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	revision := 2
+	condition := func(conditionType cmapi.CertificateConditionType, status cmmeta.ConditionStatus) cmapi.CertificateCondition {
+		return cmapi.CertificateCondition{Type: conditionType, Status: status}
+	}
+
+	tests := map[string]struct {
+		fields            Fields
+		existing, status  cmapi.CertificateStatus
+		expRemove, expApp string
+	}{
+		"only the managed fields and conditions are applied": {
+			fields: Fields{Status: []string{"notAfter"}, Conditions: []string{"Ready"}},
+			status: cmapi.CertificateStatus{
+				NotAfter:   &notAfter,
+				Revision:   &revision,
+				Conditions: []cmapi.CertificateCondition{condition("Issuing", cmmeta.ConditionTrue), condition("Ready", cmmeta.ConditionTrue)},
+			},
+			expApp: `{"apiVersion":"cert-manager.io/v1alpha2","kind":"Certificate","metadata":{"name":"test","namespace":"default"},` +
+				`"status":{"conditions":[{"status":"True","type":"Ready"}],"notAfter":"2020-01-01T00:00:00Z"}}`,
+		},
+		"managed fields and conditions removed from the status are removed with a JSON patch": {
+			fields: Fields{Status: []string{"notAfter", "revision"}, Conditions: []string{"Issuing"}},
+			existing: cmapi.CertificateStatus{
+				NotAfter:   &notAfter,
+				Revision:   &revision,
+				Conditions: []cmapi.CertificateCondition{condition("Issuing", cmmeta.ConditionTrue), condition("Ready", cmmeta.ConditionTrue)},
+			},
+			status: cmapi.CertificateStatus{
+				Revision:   &revision,
+				Conditions: []cmapi.CertificateCondition{condition("Ready", cmmeta.ConditionTrue)},
+			},
+			expRemove: `[{"op":"remove","path":"/status/notAfter"},` +
+				`{"op":"test","path":"/status/conditions/0/type","value":"Issuing"},{"op":"remove","path":"/status/conditions/0"}]`,
+			expApp: `{"apiVersion":"cert-manager.io/v1alpha2","kind":"Certificate","metadata":{"name":"test","namespace":"default"},` +
+				`"status":{"revision":2}}`,
+		},
+		"conditions are removed last to first": {
+			fields: Fields{Conditions: []string{"Issuing", "Ready"}},
+			existing: cmapi.CertificateStatus{
+				Conditions: []cmapi.CertificateCondition{condition("Issuing", cmmeta.ConditionTrue), condition("Deprecated", cmmeta.ConditionTrue), condition("Ready", cmmeta.ConditionTrue)},
+			},
+			expRemove: `[{"op":"test","path":"/status/conditions/2/type","value":"Ready"},{"op":"remove","path":"/status/conditions/2"},` +
+				`{"op":"test","path":"/status/conditions/0/type","value":"Issuing"},{"op":"remove","path":"/status/conditions/0"}]`,
+			expApp: `{"apiVersion":"cert-manager.io/v1alpha2","kind":"Certificate","metadata":{"name":"test","namespace":"default"},"status":{}}`,
+		},
+		"fields that are not managed are never removed": {
+			fields: Fields{Conditions: []string{"Ready"}},
+			existing: cmapi.CertificateStatus{
+				NotAfter:   &notAfter,
+				Conditions: []cmapi.CertificateCondition{condition("Issuing", cmmeta.ConditionTrue)},
+			},
+			expApp: `{"apiVersion":"cert-manager.io/v1alpha2","kind":"Certificate","metadata":{"name":"test","namespace":"default"},"status":{}}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			remove, applied, err := statusPatches(gvk, "default", "test", test.fields, test.existing, test.status)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(remove) != test.expRemove {
+				t.Errorf("unexpected JSON patch:\nexp: %s\ngot: %s", test.expRemove, remove)
+			}
+			if string(applied) != test.expApp {
+				t.Errorf("unexpected apply configuration:\nexp: %s\ngot: %s", test.expApp, applied)
+			}
+		})
+	}
+}
+
+func TestStatusPatchesClusterScoped(t *testing.T) {
+	_, applied, err := statusPatches(cmapi.SchemeGroupVersion.WithKind(cmapi.ClusterIssuerKind), "", "test", Fields{}, cmapi.IssuerStatus{}, cmapi.IssuerStatus{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `{"apiVersion":"cert-manager.io/v1alpha2","kind":"ClusterIssuer","metadata":{"name":"test"},"status":{}}`
+	if string(applied) != exp {
+		t.Errorf("unexpected apply configuration:\nexp: %s\ngot: %s", exp, applied)
+	}
+}
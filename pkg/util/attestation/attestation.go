@@ -0,0 +1,247 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package attestation signs and verifies attestations binding a certificate
+// stored in a Secret to the Certificate resource and issuer it was issued
+// for, and to the version of the controller that stored it.
+//
+// Attestations are compact JWS signed with ES256, stored in the
+// 'cert-manager.io/attestation' annotation of the Secret.
+package attestation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+const (
+	// algorithm is the JWS algorithm attestations are signed with
+	algorithm = "ES256"
+	// mediaType is the JWS 'typ' header of attestations
+	mediaType = "cert-manager-attestation+jws"
+
+	// es256KeySize is the size in bytes of each of the two integers of an
+	// ES256 signature
+	es256KeySize = 32
+)
+
+// Statement is the payload of an attestation.
+type Statement struct {
+	// Certificate is the Certificate resource the certificate was issued for
+	Certificate CertificateReference `json:"certificate"`
+	// SpecSHA256 is the hex encoded SHA-256 hash of the JSON encoded spec of
+	// the Certificate resource the certificate was issued for
+	SpecSHA256 string `json:"specSha256"`
+	// Issuer is the issuer that issued the certificate
+	Issuer cmmeta.ObjectReference `json:"issuer"`
+	// CertificateSHA256 is the hex encoded SHA-256 hash of the PEM encoded
+	// certificate stored in the Secret
+	CertificateSHA256 string `json:"certificateSha256"`
+	// ControllerVersion is the version of the controller that stored the
+	// certificate
+	ControllerVersion string `json:"controllerVersion"`
+	// IssuedAt is the Unix time the attestation was signed at
+	IssuedAt int64 `json:"iat"`
+}
+
+// CertificateReference identifies a Certificate resource.
+type CertificateReference struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid"`
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Type      string `json:"typ"`
+}
+
+// NewStatement returns the Statement binding the PEM encoded certificate
+// stored in the Secret of crt to crt, its issuer and the given version of
+// the controller.
+func NewStatement(crt *cmapi.Certificate, certificate []byte, controllerVersion string, now time.Time) (Statement, error) {
+	specHash, err := SpecHash(&crt.Spec)
+	if err != nil {
+		return Statement{}, err
+	}
+	issuer := crt.Spec.IssuerRef
+	issuer.Kind = apiutil.IssuerKind(issuer)
+	return Statement{
+		Certificate:       CertificateReference{Namespace: crt.Namespace, Name: crt.Name, UID: crt.UID},
+		SpecSHA256:        specHash,
+		Issuer:            issuer,
+		CertificateSHA256: CertificateHash(certificate),
+		ControllerVersion: controllerVersion,
+		IssuedAt:          now.Unix(),
+	}, nil
+}
+
+// SpecHash returns the hex encoded SHA-256 hash of the JSON encoded spec.
+func SpecHash(spec *cmapi.CertificateSpec) (string, error) {
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("error encoding Certificate spec: %v", err)
+	}
+	return hashHex(specJSON), nil
+}
+
+// CertificateHash returns the hex encoded SHA-256 hash of the PEM encoded
+// certificate.
+func CertificateHash(certificate []byte) string {
+	return hashHex(certificate)
+}
+
+// KeyID returns the identifier of the public key in the 'kid' header of
+// attestations signed by the corresponding private key, the hex encoded
+// SHA-256 hash of its DER encoded PKIX form.
+func KeyID(pub *ecdsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	return hashHex(der), nil
+}
+
+// ParsePublicKey parses the PEM encoded PKIX public key that attestations
+// are verified with.
+func ParsePublicKey(pemBytes []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("error decoding public key PEM block")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing public key: %v", err)
+	}
+	ecdsaPub, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not an ECDSA key")
+	}
+	return ecdsaPub, nil
+}
+
+// Sign returns the statement signed with key as a compact JWS. Only keys on
+// the P-256 curve are supported.
+func Sign(key *ecdsa.PrivateKey, statement Statement) (string, error) {
+	if key.Curve != elliptic.P256() {
+		return "", fmt.Errorf("unsupported curve %s, attestations must be signed with a P-256 key", key.Curve.Params().Name)
+	}
+	kid, err := KeyID(&key.PublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	headerJSON, err := json.Marshal(header{Algorithm: algorithm, KeyID: kid, Type: mediaType})
+	if err != nil {
+		return "", err
+	}
+	payloadJSON, err := json.Marshal(statement)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encode(headerJSON) + "." + encode(payloadJSON)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	// The signature is the concatenation of r and s, each left-padded to
+	// the size of the curve
+	sig := make([]byte, 2*es256KeySize)
+	rBytes, sBytes := r.Bytes(), s.Bytes()
+	copy(sig[es256KeySize-len(rBytes):es256KeySize], rBytes)
+	copy(sig[2*es256KeySize-len(sBytes):], sBytes)
+
+	return signingInput + "." + encode(sig), nil
+}
+
+// Verify verifies that the compact JWS attestation was signed by the private
+// key of pub, and returns its Statement.
+func Verify(attestation string, pub *ecdsa.PublicKey) (*Statement, error) {
+	parts := strings.Split(attestation, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("attestation is not a compact JWS")
+	}
+
+	headerJSON, err := decode(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding attestation header: %v", err)
+	}
+	var h header
+	if err := json.Unmarshal(headerJSON, &h); err != nil {
+		return nil, fmt.Errorf("error decoding attestation header: %v", err)
+	}
+	if h.Algorithm != algorithm {
+		return nil, fmt.Errorf("unsupported attestation algorithm %q", h.Algorithm)
+	}
+
+	sig, err := decode(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding attestation signature: %v", err)
+	}
+	if len(sig) != 2*es256KeySize {
+		return nil, errors.New("attestation signature has an invalid length")
+	}
+	r := new(big.Int).SetBytes(sig[:es256KeySize])
+	s := new(big.Int).SetBytes(sig[es256KeySize:])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !ecdsa.Verify(pub, digest[:], r, s) {
+		return nil, errors.New("attestation signature is not valid for the public key")
+	}
+
+	payloadJSON, err := decode(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding attestation payload: %v", err)
+	}
+	var statement Statement
+	if err := json.Unmarshal(payloadJSON, &statement); err != nil {
+		return nil, fmt.Errorf("error decoding attestation payload: %v", err)
+	}
+	return &statement, nil
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+func hashHex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
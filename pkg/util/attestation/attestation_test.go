@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attestation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestSignVerify(t *testing.T) {
+	newKey := func(curve elliptic.Curve) *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	key, otherKey := newKey(elliptic.P256()), newKey(elliptic.P256())
+
+	crt := &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", UID: "test-uid"},
+		Spec: cmapi.CertificateSpec{
+			SecretName: "test-tls",
+			DNSNames:   []string{"example.com"},
+			IssuerRef:  cmmeta.ObjectReference{Name: "ca"},
+		},
+	}
+	statement, err := NewStatement(crt, []byte("certificate"), "v0.16.0", time.Unix(1600000000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statement.Issuer.Kind != cmapi.IssuerKind {
+		t.Errorf("expected issuer kind to default to %q, got: %q", cmapi.IssuerKind, statement.Issuer.Kind)
+	}
+
+	jws, err := Sign(key, statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(jws, ".")
+
+	tests := map[string]struct {
+		attestation string
+		key         *ecdsa.PrivateKey
+		expErr      bool
+	}{
+		"attestation verified with the signing key": {
+			attestation: jws,
+			key:         key,
+		},
+		"attestation verified with another key": {
+			attestation: jws,
+			key:         otherKey,
+			expErr:      true,
+		},
+		"attestation with a modified payload": {
+			attestation: parts[0] + "." + encode([]byte(`{"certificateSha256":"modified"}`)) + "." + parts[2],
+			key:         key,
+			expErr:      true,
+		},
+		"attestation with a different algorithm": {
+			attestation: encode([]byte(`{"alg":"none"}`)) + "." + parts[1] + ".",
+			key:         key,
+			expErr:      true,
+		},
+		"attestation that is not a JWS": {
+			attestation: "not-a-jws",
+			key:         key,
+			expErr:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Verify(test.attestation, &test.key.PublicKey)
+			if (err != nil) != test.expErr {
+				t.Fatalf("expected error: %t, got: %v", test.expErr, err)
+			}
+			if !test.expErr && !reflect.DeepEqual(*got, statement) {
+				t.Errorf("unexpected statement; expected: %+v, got: %+v", statement, *got)
+			}
+		})
+	}
+
+	if _, err := Sign(newKey(elliptic.P384()), statement); err == nil {
+		t.Errorf("expected an error signing with a P-384 key")
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub.Curve != key.Curve || pub.X.Cmp(key.X) != 0 || pub.Y.Cmp(key.Y) != 0 {
+		t.Errorf("parsed public key does not match")
+	}
+
+	if _, err := ParsePublicKey([]byte("not a PEM block")); err == nil {
+		t.Errorf("expected an error parsing data that is not PEM encoded")
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attestation
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/utils/clock"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// Attestor attests certificates stored in the Secret of a Certificate.
+type Attestor interface {
+	// Attest returns the signed attestation of the PEM encoded certificate
+	// stored in the Secret of crt.
+	Attest(crt *cmapi.Certificate, certificate []byte) (string, error)
+}
+
+type secretKeyAttestor struct {
+	secretLister corelisters.SecretLister
+	namespace    string
+	name         string
+	clock        clock.Clock
+}
+
+// NewSecretKeyAttestor returns an Attestor signing attestations with the
+// P-256 private key stored in the 'tls.key' entry of the Secret with the
+// given namespace and name. The Secret is read on every attestation, so that
+// the key can be rotated without restarting the controller.
+func NewSecretKeyAttestor(secretLister corelisters.SecretLister, namespace, name string, clock clock.Clock) Attestor {
+	return &secretKeyAttestor{
+		secretLister: secretLister,
+		namespace:    namespace,
+		name:         name,
+		clock:        clock,
+	}
+}
+
+func (a *secretKeyAttestor) Attest(crt *cmapi.Certificate, certificate []byte) (string, error) {
+	secret, err := a.secretLister.Secrets(a.namespace).Get(a.name)
+	if err != nil {
+		return "", fmt.Errorf("error getting attestation key Secret %s/%s: %w", a.namespace, a.name, err)
+	}
+	signer, err := pki.DecodePrivateKeyBytes(secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return "", fmt.Errorf("error decoding attestation key in Secret %s/%s: %w", a.namespace, a.name, err)
+	}
+	key, ok := signer.(*ecdsa.PrivateKey)
+	if !ok {
+		return "", fmt.Errorf("attestation key in Secret %s/%s is not an ECDSA key", a.namespace, a.name)
+	}
+
+	statement, err := NewStatement(crt, certificate, util.VersionInfo().GitVersion, a.clock.Now())
+	if err != nil {
+		return "", err
+	}
+	return Sign(key, statement)
+}
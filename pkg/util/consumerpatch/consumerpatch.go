@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package consumerpatch parses, renders and applies the JSON patches of the
+// cert-manager.io/secret-consumer-patches annotation, which are applied to
+// the resources consuming the Secret of a Certificate after a new
+// certificate has been stored in it. This covers cases such as bumping an
+// annotation on the Pod template of a Deployment so that it is rolled,
+// without a dedicated integration for each type of workload.
+// As patches are applied with the credentials of cert-manager, only the
+// workload kinds in AllowedTargets can be patched, and only in the namespace
+// of the Certificate.
+package consumerpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// AllowedTargets are the kinds of resources that patches can be applied to.
+var AllowedTargets = map[schema.GroupVersionKind]bool{
+	{Group: "apps", Version: "v1", Kind: "Deployment"}:  true,
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"}: true,
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"}:   true,
+}
+
+// Patch is a JSON patch applied to a resource consuming the Secret of a
+// Certificate.
+type Patch struct {
+	// Target is the resource that is patched, in the namespace of the
+	// Certificate.
+	Target Target `json:"target"`
+	// Patch is the JSON patch (RFC 6902) applied to the target. String values
+	// are Go templates that are rendered with Data.
+	Patch []Operation `json:"patch"`
+}
+
+// Target identifies the resource a Patch is applied to.
+type Target struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+}
+
+func (t Target) String() string {
+	return fmt.Sprintf("%s %q", t.Kind, t.Name)
+}
+
+// GroupVersionKind returns the kind of the target, or an error if it is not
+// one of AllowedTargets.
+func (t Target) GroupVersionKind() (schema.GroupVersionKind, error) {
+	gv, err := schema.ParseGroupVersion(t.APIVersion)
+	if err != nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("invalid apiVersion %q: %w", t.APIVersion, err)
+	}
+	gvk := gv.WithKind(t.Kind)
+	if !AllowedTargets[gvk] {
+		return schema.GroupVersionKind{}, fmt.Errorf("%s %s cannot be patched, only apps/v1 Deployments, StatefulSets and DaemonSets can be patched", t.APIVersion, t.Kind)
+	}
+	return gvk, nil
+}
+
+// Operation is a single operation of a JSON patch.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Data are the values available to the templates of a patch.
+type Data struct {
+	// CertificateName is the name of the Certificate
+	CertificateName string
+	// SecretName and SecretResourceVersion identify the version of the
+	// Secret the certificate was stored in. The resource version changes
+	// with every write to the Secret, so it can be used to roll workloads
+	// whenever the Secret changes.
+	SecretName            string
+	SecretResourceVersion string
+	// Revision is the revision of the Certificate the certificate was issued
+	// for
+	Revision int
+	// SerialNumber is the serial number of the certificate in hex
+	SerialNumber string
+	// NotAfter is the expiry time of the certificate in RFC 3339 format
+	NotAfter string
+}
+
+var validOps = map[string]bool{
+	"add": true, "remove": true, "replace": true, "move": true, "copy": true, "test": true,
+}
+
+// Parse parses and validates the value of the
+// cert-manager.io/secret-consumer-patches annotation.
+func Parse(value string) ([]Patch, error) {
+	var patches []Patch
+	// unknown fields are rejected so that a target cannot appear to be in
+	// another namespace
+	dec := json.NewDecoder(bytes.NewReader([]byte(value)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&patches); err != nil {
+		return nil, fmt.Errorf("must be a JSON encoded list of patches: %v", err)
+	}
+
+	for i, p := range patches {
+		if p.Target.APIVersion == "" || p.Target.Kind == "" || p.Target.Name == "" {
+			return nil, fmt.Errorf("[%d].target: apiVersion, kind and name must be set", i)
+		}
+		if _, err := p.Target.GroupVersionKind(); err != nil {
+			return nil, fmt.Errorf("[%d].target: %v", i, err)
+		}
+		if len(p.Patch) == 0 {
+			return nil, fmt.Errorf("[%d].patch: must contain at least one operation", i)
+		}
+		for j, op := range p.Patch {
+			if !validOps[op.Op] {
+				return nil, fmt.Errorf("[%d].patch[%d].op: invalid operation %q", i, j, op.Op)
+			}
+			if !strings.HasPrefix(op.Path, "/") {
+				return nil, fmt.Errorf("[%d].patch[%d].path: must be a JSON pointer starting with '/'", i, j)
+			}
+			// the templates are checked by rendering them with empty data,
+			// which fails if they refer to fields that do not exist
+			if _, err := renderValue(op.Value, Data{}); err != nil {
+				return nil, fmt.Errorf("[%d].patch[%d].value: %v", i, j, err)
+			}
+		}
+	}
+
+	return patches, nil
+}
+
+// Render returns the JSON patch of p with all string values rendered with
+// the given data.
+func (p Patch) Render(data Data) ([]byte, error) {
+	ops := make([]Operation, len(p.Patch))
+	for i, op := range p.Patch {
+		value, err := renderValue(op.Value, data)
+		if err != nil {
+			return nil, fmt.Errorf("rendering patch[%d].value: %w", i, err)
+		}
+		op.Value = value
+		ops[i] = op
+	}
+	return json.Marshal(ops)
+}
+
+// renderValue renders the value of an operation if it is a string, and
+// returns any other value unchanged.
+func renderValue(value json.RawMessage, data Data) (json.RawMessage, error) {
+	var text string
+	if len(value) == 0 || json.Unmarshal(value, &text) != nil {
+		return value, nil
+	}
+
+	t, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return nil, err
+	}
+	rendered, err := json.Marshal(b.String())
+	if err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consumerpatch
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	coretesting "k8s.io/client-go/testing"
+)
+
+const rollDeployment = `[{"target": {"apiVersion": "apps/v1", "kind": "Deployment", "name": "web"},
+  "patch": [{"op": "add", "path": "/spec/template/metadata/annotations/example.com~1tls-version", "value": "{{ .SecretResourceVersion }}"}]}]`
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		value string
+		valid bool
+	}{
+		"valid patch": {
+			value: rollDeployment,
+			valid: true,
+		},
+		"non-string values are not templated": {
+			value: `[{"target": {"apiVersion": "apps/v1", "kind": "StatefulSet", "name": "web"}, "patch": [{"op": "add", "path": "/spec/template/metadata/annotations", "value": {"a": "{{"}}]}]`,
+			valid: true,
+		},
+		"remove operations have no value": {
+			value: `[{"target": {"apiVersion": "apps/v1", "kind": "DaemonSet", "name": "web"}, "patch": [{"op": "remove", "path": "/spec/template/metadata/annotations/a"}]}]`,
+			valid: true,
+		},
+		"Secrets cannot be patched": {
+			value: `[{"target": {"apiVersion": "v1", "kind": "Secret", "name": "web-tls"}, "patch": [{"op": "remove", "path": "/data/tls.key"}]}]`,
+		},
+		"cluster scoped resources cannot be patched": {
+			value: `[{"target": {"apiVersion": "rbac.authorization.k8s.io/v1", "kind": "ClusterRoleBinding", "name": "admin"}, "patch": [{"op": "remove", "path": "/subjects"}]}]`,
+		},
+		"other versions of allowed kinds cannot be patched": {
+			value: `[{"target": {"apiVersion": "extensions/v1beta1", "kind": "Deployment", "name": "web"}, "patch": [{"op": "remove", "path": "/a"}]}]`,
+		},
+		"target in another namespace": {
+			value: `[{"target": {"apiVersion": "apps/v1", "kind": "Deployment", "name": "web", "namespace": "kube-system"}, "patch": [{"op": "remove", "path": "/a"}]}]`,
+		},
+		"invalid JSON": {
+			value: `{"target"`,
+		},
+		"target without a name": {
+			value: `[{"target": {"apiVersion": "apps/v1", "kind": "Deployment"}, "patch": [{"op": "remove", "path": "/a"}]}]`,
+		},
+		"empty patch": {
+			value: `[{"target": {"apiVersion": "apps/v1", "kind": "Deployment", "name": "web"}, "patch": []}]`,
+		},
+		"invalid operation": {
+			value: `[{"target": {"apiVersion": "apps/v1", "kind": "Deployment", "name": "web"}, "patch": [{"op": "merge", "path": "/a"}]}]`,
+		},
+		"path is not a JSON pointer": {
+			value: `[{"target": {"apiVersion": "apps/v1", "kind": "Deployment", "name": "web"}, "patch": [{"op": "remove", "path": "a"}]}]`,
+		},
+		"template refers to an unknown field": {
+			value: `[{"target": {"apiVersion": "apps/v1", "kind": "Deployment", "name": "web"}, "patch": [{"op": "add", "path": "/a", "value": "{{ .Secret.Data }}"}]}]`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(test.value)
+			if (err == nil) != test.valid {
+				t.Errorf("expected valid=%t, got error: %v", test.valid, err)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	patches, err := Parse(rollDeployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := patches[0].Render(Data{SecretResourceVersion: "42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `[{"op":"add","path":"/spec/template/metadata/annotations/example.com~1tls-version","value":"42"}]`
+	if string(body) != exp {
+		t.Errorf("expected patch %s, got: %s", exp, body)
+	}
+}
+
+type staticMapper struct {
+	meta.RESTMapper
+}
+
+func (staticMapper) Reset() {}
+
+func TestApply(t *testing.T) {
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(deployments.GroupVersion().WithKind("Deployment"), meta.RESTScopeNamespace)
+
+	deployment := &unstructured.Unstructured{}
+	deployment.SetAPIVersion("apps/v1")
+	deployment.SetKind("Deployment")
+	deployment.SetNamespace("testns")
+	deployment.SetName("web")
+
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), deployment)
+	p := &Patcher{dynamicClient: client, mapper: staticMapper{mapper}}
+
+	patches, err := Parse(rollDeployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the fake client cannot apply JSON patches, so only the request is
+	// checked
+	_ = p.Apply(context.Background(), "testns", patches[0], Data{SecretResourceVersion: "42"})
+
+	var patch coretesting.PatchAction
+	for _, action := range client.Actions() {
+		if a, ok := action.(coretesting.PatchAction); ok {
+			patch = a
+		}
+	}
+	if patch == nil {
+		t.Fatalf("expected the Deployment to be patched, got actions: %v", client.Actions())
+	}
+	if patch.GetResource() != deployments || patch.GetNamespace() != "testns" || patch.GetName() != "web" {
+		t.Errorf("unexpected patch target: %v %s/%s", patch.GetResource(), patch.GetNamespace(), patch.GetName())
+	}
+	if patch.GetPatchType() != types.JSONPatchType {
+		t.Errorf("expected a JSON patch, got: %s", patch.GetPatchType())
+	}
+}
+
+func TestApplyRejectsTargets(t *testing.T) {
+	deploymentPatch := Patch{
+		Target: Target{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+		Patch:  []Operation{{Op: "remove", Path: "/spec/template/metadata/annotations/a"}},
+	}
+	secretPatch := Patch{
+		Target: Target{APIVersion: "v1", Kind: "Secret", Name: "web-tls"},
+		Patch:  []Operation{{Op: "remove", Path: "/data/tls.key"}},
+	}
+
+	tests := map[string]struct {
+		scope     meta.RESTScope
+		namespace string
+		patch     Patch
+	}{
+		"a Secret is rejected": {
+			scope:     meta.RESTScopeNamespace,
+			namespace: "testns",
+			patch:     secretPatch,
+		},
+		"a cluster scoped mapping is rejected": {
+			scope:     meta.RESTScopeRoot,
+			namespace: "testns",
+			patch:     deploymentPatch,
+		},
+		"a patch without the namespace of the Certificate is rejected": {
+			scope: meta.RESTScopeNamespace,
+			patch: deploymentPatch,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, test.scope)
+			mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, test.scope)
+
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+			p := &Patcher{dynamicClient: client, mapper: staticMapper{mapper}}
+
+			if err := p.Apply(context.Background(), test.namespace, test.patch, Data{}); err == nil {
+				t.Errorf("expected the patch to be rejected")
+			}
+			if len(client.Actions()) > 0 {
+				t.Errorf("expected no request to be made, got: %v", client.Actions())
+			}
+		})
+	}
+}
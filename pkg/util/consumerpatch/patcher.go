@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consumerpatch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// Patcher applies patches to the resources they target.
+type Patcher struct {
+	restConfig *rest.Config
+	discovery  discovery.DiscoveryInterface
+
+	// the clients are only created once the first patch is applied, as
+	// most Certificates do not use patches
+	once          sync.Once
+	initErr       error
+	dynamicClient dynamic.Interface
+	mapper        resettableRESTMapper
+}
+
+// resettableRESTMapper is a RESTMapper caching the kinds known to the API
+// server that can be reset when a kind is not found.
+type resettableRESTMapper interface {
+	meta.RESTMapper
+	Reset()
+}
+
+// NewPatcher returns a Patcher that accesses the targets of patches with the
+// given config, resolving their kinds with the given discovery client.
+func NewPatcher(restConfig *rest.Config, discoveryClient discovery.DiscoveryInterface) *Patcher {
+	return &Patcher{restConfig: restConfig, discovery: discoveryClient}
+}
+
+func (p *Patcher) init() error {
+	p.once.Do(func() {
+		if p.dynamicClient == nil {
+			p.dynamicClient, p.initErr = dynamic.NewForConfig(p.restConfig)
+		}
+		if p.mapper == nil {
+			p.mapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(p.discovery))
+		}
+	})
+	return p.initErr
+}
+
+// Apply renders the patch with the given data and applies it to its target
+// in the given namespace, which must be the namespace of the Certificate.
+// Targets that are not one of AllowedTargets or that are not namespaced are
+// rejected.
+func (p *Patcher) Apply(ctx context.Context, namespace string, patch Patch, data Data) error {
+	if namespace == "" {
+		return fmt.Errorf("the namespace of the target must be set")
+	}
+	gvk, err := patch.Target.GroupVersionKind()
+	if err != nil {
+		return err
+	}
+	if err := p.init(); err != nil {
+		return err
+	}
+
+	body, err := patch.Render(data)
+	if err != nil {
+		return err
+	}
+
+	mapping, err := p.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		// the kind may have been installed since the mapper was last
+		// refreshed
+		p.mapper.Reset()
+		mapping, err = p.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("resolving kind %s: %w", gvk, err)
+	}
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return fmt.Errorf("%s is not namespaced and cannot be patched", gvk)
+	}
+	_, err = p.dynamicClient.Resource(mapping.Resource).Namespace(namespace).Patch(ctx, patch.Target.Name, types.JSONPatchType, body, metav1.PatchOptions{})
+	return err
+}
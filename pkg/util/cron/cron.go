@@ -0,0 +1,221 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cron implements parsing of standard 5 field cron schedules and of
+// recurring time windows that open according to such a schedule.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tzPrefix may be used to evaluate a schedule in a time zone other than UTC,
+// e.g. "CRON_TZ=Europe/London 0 0 * * *".
+const tzPrefix = "CRON_TZ="
+
+// Schedule is a parsed cron schedule.
+type Schedule struct {
+	minute, hour, dom, month, dow bits
+
+	// domStar and dowStar are true if the day of month and day of week
+	// fields are unrestricted. If both fields are restricted, a day matches
+	// if either field matches.
+	domStar, dowStar bool
+
+	loc *time.Location
+}
+
+type bits uint64
+
+func (b bits) has(i int) bool {
+	return b&(1<<uint(i)) != 0
+}
+
+type bounds struct {
+	min, max int
+}
+
+var (
+	minuteBounds = bounds{0, 59}
+	hourBounds   = bounds{0, 23}
+	domBounds    = bounds{1, 31}
+	monthBounds  = bounds{1, 12}
+	// 7 is accepted as an alias of Sunday.
+	dowBounds = bounds{0, 7}
+)
+
+// ParseSchedule parses a cron schedule with the fields minute, hour, day of
+// month, month and day of week. Each field is a comma separated list of
+// '*', single values and ranges such as '1-5', optionally followed by a
+// step such as '*/15'. Schedules are evaluated in UTC unless prefixed with
+// 'CRON_TZ=<zone>'.
+func ParseSchedule(spec string) (*Schedule, error) {
+	loc := time.UTC
+	fields := strings.Fields(spec)
+	if len(fields) > 0 && strings.HasPrefix(fields[0], tzPrefix) {
+		var err error
+		loc, err = time.LoadLocation(strings.TrimPrefix(fields[0], tzPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone in schedule %q: %v", spec, err)
+		}
+		fields = fields[1:]
+	}
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields but got %d", spec, len(fields))
+	}
+
+	s := &Schedule{loc: loc}
+	var err error
+	if s.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, fmt.Errorf("invalid minute field in schedule %q: %v", spec, err)
+	}
+	if s.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, fmt.Errorf("invalid hour field in schedule %q: %v", spec, err)
+	}
+	if s.dom, err = parseField(fields[2], domBounds); err != nil {
+		return nil, fmt.Errorf("invalid day of month field in schedule %q: %v", spec, err)
+	}
+	if s.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, fmt.Errorf("invalid month field in schedule %q: %v", spec, err)
+	}
+	if s.dow, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, fmt.Errorf("invalid day of week field in schedule %q: %v", spec, err)
+	}
+	if s.dow.has(7) {
+		s.dow |= 1
+	}
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+
+	return s, nil
+}
+
+func parseField(field string, b bounds) (bits, error) {
+	var result bits
+	for _, part := range strings.Split(field, ",") {
+		r, err := parseRange(part, b)
+		if err != nil {
+			return 0, err
+		}
+		result |= r
+	}
+	return result, nil
+}
+
+func parseRange(expr string, b bounds) (bits, error) {
+	rangeExpr, step := expr, 1
+	if i := strings.Index(expr, "/"); i >= 0 {
+		var err error
+		rangeExpr = expr[:i]
+		step, err = strconv.Atoi(expr[i+1:])
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step in %q", expr)
+		}
+	}
+
+	start, end := b.min, b.max
+	if rangeExpr != "*" {
+		lowHigh := strings.SplitN(rangeExpr, "-", 2)
+		var err error
+		if start, err = parseValue(lowHigh[0], b); err != nil {
+			return 0, err
+		}
+		end = start
+		if len(lowHigh) == 2 {
+			if end, err = parseValue(lowHigh[1], b); err != nil {
+				return 0, err
+			}
+		} else if step > 1 {
+			// a single value with a step, e.g. '5/15', runs to the end of
+			// the range
+			end = b.max
+		}
+		if start > end {
+			return 0, fmt.Errorf("invalid range %q", rangeExpr)
+		}
+	}
+
+	var result bits
+	for i := start; i <= end; i += step {
+		result |= 1 << uint(i)
+	}
+	return result, nil
+}
+
+func parseValue(s string, b bounds) (int, error) {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if i < b.min || i > b.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", i, b.min, b.max)
+	}
+	return i, nil
+}
+
+// maxSearchYears bounds the search for the next activation of a schedule, so
+// that schedules that never match, such as '0 0 30 2 *', terminate.
+const maxSearchYears = 5
+
+// Next returns the first time strictly after t that matches the schedule, or
+// the zero time if there is none within the next few years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.In(s.loc).Truncate(time.Minute).Add(time.Minute)
+	yearLimit := t.Year() + maxSearchYears
+
+wrap:
+	for t.Year() <= yearLimit {
+		for !s.month.has(int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.loc)
+			if t.Month() == time.January {
+				continue wrap
+			}
+		}
+		for !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.loc)
+			if t.Day() == 1 {
+				continue wrap
+			}
+		}
+		for !s.hour.has(t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.loc)
+			if t.Hour() == 0 {
+				continue wrap
+			}
+		}
+		for !s.minute.has(t.Minute()) {
+			t = t.Add(time.Minute)
+			if t.Minute() == 0 {
+				continue wrap
+			}
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom.has(t.Day())
+	dowMatch := s.dow.has(int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
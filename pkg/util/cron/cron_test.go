@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func mustTime(t *testing.T, s string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestParseSchedule(t *testing.T) {
+	tests := map[string]struct {
+		spec   string
+		expErr bool
+	}{
+		"every minute":                    {spec: "* * * * *"},
+		"lists, ranges and steps":         {spec: "0,30 9-17/2 1-7 */3 1-5"},
+		"sunday as 7":                     {spec: "0 0 * * 7"},
+		"with time zone":                  {spec: "CRON_TZ=Europe/London 0 0 * * *"},
+		"too few fields":                  {spec: "0 0 * *", expErr: true},
+		"too many fields":                 {spec: "0 0 * * * *", expErr: true},
+		"minute out of range":             {spec: "60 0 * * *", expErr: true},
+		"day of month out of range":       {spec: "0 0 0 * *", expErr: true},
+		"inverted range":                  {spec: "0 0 * 12-1 *", expErr: true},
+		"zero step":                       {spec: "*/0 * * * *", expErr: true},
+		"not a number":                    {spec: "a * * * *", expErr: true},
+		"unknown time zone":               {spec: "CRON_TZ=Nowhere/Special 0 0 * * *", expErr: true},
+		"time zone without other fields":  {spec: "CRON_TZ=UTC", expErr: true},
+		"empty":                           {spec: "", expErr: true},
+		"single value with step":          {spec: "5/15 * * * *"},
+		"range with step and list":        {spec: "1-10/3,20 * * * *"},
+		"day of week out of range":        {spec: "0 0 * * 8", expErr: true},
+		"month out of range":              {spec: "0 0 * 13 *", expErr: true},
+		"hour out of range":               {spec: "0 24 * * *", expErr: true},
+		"negative value":                  {spec: "-1 * * * *", expErr: true},
+		"step without range or wildcard":  {spec: "/5 * * * *", expErr: true},
+		"list with empty element":         {spec: "1,,2 * * * *", expErr: true},
+		"surrounding whitespace is valid": {spec: "  0 0 * * *  "},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseSchedule(test.spec)
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error=%t but got: %v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	tests := map[string]struct {
+		spec string
+		from string
+		exp  string
+	}{
+		"next minute": {
+			spec: "* * * * *",
+			from: "2020-06-01T10:00:30Z",
+			exp:  "2020-06-01T10:01:00Z",
+		},
+		"strictly after the given time": {
+			spec: "0 * * * *",
+			from: "2020-06-01T10:00:00Z",
+			exp:  "2020-06-01T11:00:00Z",
+		},
+		"wraps to the next day": {
+			spec: "30 9 * * *",
+			from: "2020-06-01T10:00:00Z",
+			exp:  "2020-06-02T09:30:00Z",
+		},
+		"wraps to the next year": {
+			spec: "0 0 20 12 *",
+			from: "2020-12-21T00:00:00Z",
+			exp:  "2021-12-20T00:00:00Z",
+		},
+		"day of week": {
+			// 2020-06-01 is a Monday
+			spec: "0 0 * * 6",
+			from: "2020-06-01T00:00:00Z",
+			exp:  "2020-06-06T00:00:00Z",
+		},
+		"sunday as 7": {
+			spec: "0 0 * * 7",
+			from: "2020-06-01T00:00:00Z",
+			exp:  "2020-06-07T00:00:00Z",
+		},
+		"either day of month or day of week if both are restricted": {
+			spec: "0 0 15 * 6",
+			from: "2020-06-01T00:00:00Z",
+			exp:  "2020-06-06T00:00:00Z",
+		},
+		"leap day": {
+			spec: "0 0 29 2 *",
+			from: "2021-01-01T00:00:00Z",
+			exp:  "2024-02-29T00:00:00Z",
+		},
+		"never matches": {
+			spec: "0 0 30 2 *",
+			from: "2020-01-01T00:00:00Z",
+			exp:  "",
+		},
+		"time zone": {
+			spec: "CRON_TZ=America/New_York 0 9 * * *",
+			from: "2020-06-01T00:00:00Z",
+			exp:  "2020-06-01T13:00:00Z",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := ParseSchedule(test.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			next := s.Next(mustTime(t, test.from))
+			if test.exp == "" {
+				if !next.IsZero() {
+					t.Errorf("expected no next time but got %s", next)
+				}
+				return
+			}
+			if exp := mustTime(t, test.exp); !next.Equal(exp) {
+				t.Errorf("expected next time %s but got %s", exp, next)
+			}
+		})
+	}
+}
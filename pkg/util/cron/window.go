@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a recurring window of time that opens whenever its schedule
+// matches and stays open for its duration.
+type Window struct {
+	spec     string
+	schedule *Schedule
+	duration time.Duration
+}
+
+// ParseWindow parses a window from a cron schedule followed by the duration
+// of the window, e.g. '0 0 20 12 * 336h' for a window from the 20th of
+// December until the 3rd of January.
+func ParseWindow(spec string) (*Window, error) {
+	spec = strings.TrimSpace(spec)
+	i := strings.LastIndexAny(spec, " \t")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid window %q: expected a schedule followed by a duration", spec)
+	}
+
+	duration, err := time.ParseDuration(spec[i+1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration in window %q: %v", spec, err)
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("invalid duration in window %q: must be greater than zero", spec)
+	}
+
+	schedule, err := ParseSchedule(spec[:i])
+	if err != nil {
+		return nil, err
+	}
+
+	return &Window{spec: spec, schedule: schedule, duration: duration}, nil
+}
+
+func (w *Window) String() string {
+	return w.spec
+}
+
+// openUntil returns the time the window closes if it is open at t.
+func (w *Window) openUntil(t time.Time) (time.Time, bool) {
+	// The window is open if it was last opened less than its duration ago.
+	opened := w.schedule.Next(t.Add(-w.duration))
+	if opened.IsZero() || opened.After(t) {
+		return time.Time{}, false
+	}
+
+	// Later activations within the same window extend it.
+	end := opened.Add(w.duration)
+	for {
+		next := w.schedule.Next(opened)
+		if next.IsZero() || next.After(t) {
+			break
+		}
+		opened, end = next, next.Add(w.duration)
+	}
+
+	return end, true
+}
+
+// Windows is a list of recurring windows.
+type Windows []*Window
+
+// ParseWindows parses each of the given window specs.
+func ParseWindows(specs []string) (Windows, error) {
+	var ws Windows
+	for _, spec := range specs {
+		w, err := ParseWindow(spec)
+		if err != nil {
+			return nil, err
+		}
+		ws = append(ws, w)
+	}
+	return ws, nil
+}
+
+// maxChainedWindows bounds the number of overlapping windows that are
+// followed to determine when a run of windows closes.
+const maxChainedWindows = 100
+
+// OpenUntil returns whether any of the windows is open at t and, if so, the
+// time at which t is no longer covered by any window. Windows that overlap
+// or directly follow one another are treated as a single window.
+func (ws Windows) OpenUntil(t time.Time) (time.Time, bool) {
+	var end time.Time
+	open := false
+	for i := 0; i < maxChainedWindows; i++ {
+		extended := false
+		for _, w := range ws {
+			at := t
+			if open {
+				at = end
+			}
+			if e, ok := w.openUntil(at); ok && e.After(end) {
+				end, open, extended = e, true, true
+			}
+		}
+		if !extended {
+			break
+		}
+	}
+	return end, open
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import "testing"
+
+func TestParseWindow(t *testing.T) {
+	tests := map[string]struct {
+		spec   string
+		expErr bool
+	}{
+		"schedule and duration":   {spec: "0 0 20 12 * 336h"},
+		"with time zone":          {spec: "CRON_TZ=Europe/Berlin 0 18 * * 5 60h"},
+		"missing duration":        {spec: "0 0 20 12 *", expErr: true},
+		"missing schedule":        {spec: "336h", expErr: true},
+		"zero duration":           {spec: "0 0 20 12 * 0s", expErr: true},
+		"negative duration":       {spec: "0 0 20 12 * -1h", expErr: true},
+		"invalid schedule":        {spec: "0 0 32 12 * 1h", expErr: true},
+		"duration without a unit": {spec: "0 0 20 12 * 10", expErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseWindow(test.spec)
+			if test.expErr != (err != nil) {
+				t.Errorf("expected error=%t but got: %v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestWindowsOpenUntil(t *testing.T) {
+	tests := map[string]struct {
+		specs   []string
+		at      string
+		expOpen bool
+		expEnd  string
+	}{
+		"no windows": {
+			at: "2020-12-24T00:00:00Z",
+		},
+		"inside a window": {
+			specs:   []string{"0 0 20 12 * 336h"},
+			at:      "2020-12-24T00:00:00Z",
+			expOpen: true,
+			expEnd:  "2021-01-03T00:00:00Z",
+		},
+		"inside a window that opened in the previous year": {
+			specs:   []string{"0 0 20 12 * 336h"},
+			at:      "2021-01-02T23:59:00Z",
+			expOpen: true,
+			expEnd:  "2021-01-03T00:00:00Z",
+		},
+		"at the time a window opens": {
+			specs:   []string{"0 0 20 12 * 336h"},
+			at:      "2020-12-20T00:00:00Z",
+			expOpen: true,
+			expEnd:  "2021-01-03T00:00:00Z",
+		},
+		"at the time a window closes": {
+			specs: []string{"0 0 20 12 * 336h"},
+			at:    "2021-01-03T00:00:00Z",
+		},
+		"before a window": {
+			specs: []string{"0 0 20 12 * 336h"},
+			at:    "2020-12-19T23:59:59Z",
+		},
+		"windows directly following one another are joined": {
+			specs:   []string{"0 0 * * 6 24h", "0 0 * * 0 24h"},
+			at:      "2020-06-06T12:00:00Z",
+			expOpen: true,
+			expEnd:  "2020-06-08T00:00:00Z",
+		},
+		"overlapping activations of the same window are joined": {
+			specs:   []string{"0 12,13 * * * 90m"},
+			at:      "2020-06-06T12:30:00Z",
+			expOpen: true,
+			expEnd:  "2020-06-06T14:30:00Z",
+		},
+		"windows that are always open terminate": {
+			specs:   []string{"0 * * * * 90m"},
+			at:      "2020-06-06T12:30:00Z",
+			expOpen: true,
+			expEnd:  "2020-06-10T16:30:00Z",
+		},
+		"the longest of several open windows is used": {
+			specs:   []string{"0 0 * * 6 48h", "0 0 * * 6 24h"},
+			at:      "2020-06-06T12:00:00Z",
+			expOpen: true,
+			expEnd:  "2020-06-08T00:00:00Z",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ws, err := ParseWindows(test.specs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			end, open := ws.OpenUntil(mustTime(t, test.at))
+			if open != test.expOpen {
+				t.Fatalf("expected open=%t but got %t", test.expOpen, open)
+			}
+			if !open {
+				return
+			}
+			if exp := mustTime(t, test.expEnd); !end.Equal(exp) {
+				t.Errorf("expected window to close at %s but got %s", exp, end)
+			}
+		})
+	}
+}
+
+func TestWindowString(t *testing.T) {
+	w, err := ParseWindow(" 0 0 20 12 * 336h ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "0 0 20 12 * 336h"; w.String() != exp {
+		t.Errorf("expected %q but got %q", exp, w.String())
+	}
+}
@@ -35,10 +35,13 @@ import (
 	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 )
 
+// IPAddressesForCertificate returns the canonical IP addresses requested by
+// the Certificate, see CanonicalIPAddresses. Addresses that cannot be parsed
+// are skipped.
 func IPAddressesForCertificate(crt *v1alpha2.Certificate) []net.IP {
 	var ipAddresses []net.IP
 	var ip net.IP
-	for _, ipName := range crt.Spec.IPAddresses {
+	for _, ipName := range CanonicalIPAddresses(crt.Spec.IPAddresses) {
 		ip = net.ParseIP(ipName)
 		if ip != nil {
 			ipAddresses = append(ipAddresses, ip)
@@ -47,8 +50,10 @@ func IPAddressesForCertificate(crt *v1alpha2.Certificate) []net.IP {
 	return ipAddresses
 }
 
+// URIsForCertificate returns the canonical URIs requested by the Certificate,
+// see CanonicalURIs.
 func URIsForCertificate(crt *v1alpha2.Certificate) ([]*url.URL, error) {
-	uris, err := URLsFromStrings(crt.Spec.URISANs)
+	uris, err := URLsFromStrings(CanonicalURIs(crt.Spec.URISANs))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse URIs: %s", err)
 	}
@@ -56,13 +61,69 @@ func URIsForCertificate(crt *v1alpha2.Certificate) ([]*url.URL, error) {
 	return uris, nil
 }
 
+// DNSNamesForCertificate returns the canonical DNS names requested by the
+// Certificate, see CanonicalDNSNames.
 func DNSNamesForCertificate(crt *v1alpha2.Certificate) ([]string, error) {
 	_, err := URLsFromStrings(crt.Spec.DNSNames)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse DNSNames: %s", err)
 	}
 
-	return crt.Spec.DNSNames, nil
+	return CanonicalDNSNames(crt.Spec.DNSNames), nil
+}
+
+// CommonNameForCertificateSpec returns the common name to be requested for a
+// Certificate with the given spec, according to its subjectAltNamesPolicy.
+func CommonNameForCertificateSpec(spec *v1alpha2.CertificateSpec) string {
+	switch spec.SubjectAltNamesPolicy {
+	case v1alpha2.OmitCommonNamePolicy:
+		return ""
+	case v1alpha2.CopyFirstDNSNamePolicy:
+		if len(spec.DNSNames) == 0 {
+			return ""
+		}
+		return CanonicalDNSName(spec.DNSNames[0])
+	default:
+		return spec.CommonName
+	}
+}
+
+// ValidateSubjectAltNamesPolicyForIssuer returns an error if the given issuer
+// is not able to issue a certificate that honours the subjectAltNamesPolicy
+// of the Certificate spec.
+func ValidateSubjectAltNamesPolicyForIssuer(spec *v1alpha2.CertificateSpec, issuer v1alpha2.GenericIssuer) error {
+	switch spec.SubjectAltNamesPolicy {
+	case v1alpha2.UseCommonNamePolicy:
+		// ACME servers only issue certificates for authorized identifiers, so
+		// a literal common name must also be requested as a DNS name.
+		if issuer.GetSpec().ACME == nil {
+			return nil
+		}
+		commonName := CanonicalDNSName(spec.CommonName)
+		for _, name := range CanonicalDNSNames(spec.DNSNames) {
+			if name == commonName {
+				return nil
+			}
+		}
+		return fmt.Errorf("ACME issuers require the commonName %q to also be present in dnsNames when using the %s subjectAltNamesPolicy", spec.CommonName, v1alpha2.UseCommonNamePolicy)
+	}
+	return nil
+}
+
+// DNSNamesForCSR returns the DNS names requested by the PEM encoded CSR,
+// including its common name if set.
+func DNSNamesForCSR(csrPEM []byte) ([]string, error) {
+	csr, err := DecodeX509CertificateRequestBytes(csrPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	names := csr.DNSNames
+	if csr.Subject.CommonName != "" {
+		names = append([]string{csr.Subject.CommonName}, names...)
+	}
+
+	return removeDuplicates(names), nil
 }
 
 func URLsFromStrings(urlStrs []string) ([]*url.URL, error) {
@@ -169,7 +230,10 @@ func BuildKeyUsages(usages []v1alpha2.KeyUsage, isCA bool) (ku x509.KeyUsage, ek
 // The CSR will not be signed, and should be passed to either EncodeCSR or
 // to the x509.CreateCertificateRequest function.
 func GenerateCSR(crt *v1alpha2.Certificate) (*x509.CertificateRequest, error) {
-	commonName := crt.Spec.CommonName
+	commonName, err := commonNameForCertificate(crt)
+	if err != nil {
+		return nil, err
+	}
 	iPAddresses := IPAddressesForCertificate(crt)
 	organization := OrganizationForCertificate(crt)
 	subject := SubjectForCertificate(crt)
@@ -222,12 +286,15 @@ func GenerateCSR(crt *v1alpha2.Certificate) (*x509.CertificateRequest, error) {
 // generated by GenerateCSR.
 // The PublicKey field must be populated by the caller.
 func GenerateTemplate(crt *v1alpha2.Certificate) (*x509.Certificate, error) {
-	commonName := crt.Spec.CommonName
-	dnsNames := crt.Spec.DNSNames
+	commonName, err := commonNameForCertificate(crt)
+	if err != nil {
+		return nil, err
+	}
+	dnsNames := CanonicalDNSNames(crt.Spec.DNSNames)
 	ipAddresses := IPAddressesForCertificate(crt)
 	organization := OrganizationForCertificate(crt)
 	subject := SubjectForCertificate(crt)
-	uris, err := URLsFromStrings(crt.Spec.URISANs)
+	uris, err := URLsFromStrings(CanonicalURIs(crt.Spec.URISANs))
 	if err != nil {
 		return nil, err
 	}
@@ -281,6 +348,23 @@ func GenerateTemplate(crt *v1alpha2.Certificate) (*x509.Certificate, error) {
 	}, nil
 }
 
+// commonNameForCertificate returns the common name to be requested for the
+// Certificate, or an error if its subjectAltNamesPolicy cannot be honoured.
+func commonNameForCertificate(crt *v1alpha2.Certificate) (string, error) {
+	commonName := CommonNameForCertificateSpec(&crt.Spec)
+	switch crt.Spec.SubjectAltNamesPolicy {
+	case v1alpha2.UseCommonNamePolicy:
+		if len(commonName) == 0 {
+			return "", fmt.Errorf("no common name specified on certificate with the %s subjectAltNamesPolicy", v1alpha2.UseCommonNamePolicy)
+		}
+	case v1alpha2.CopyFirstDNSNamePolicy:
+		if len(commonName) == 0 {
+			return "", fmt.Errorf("no DNS name specified on certificate with the %s subjectAltNamesPolicy", v1alpha2.CopyFirstDNSNamePolicy)
+		}
+	}
+	return commonName, nil
+}
+
 // GenerateTemplate will create a x509.Certificate for the given
 // CertificateRequest resource
 func GenerateTemplateFromCertificateRequest(cr *v1alpha2.CertificateRequest) (*x509.Certificate, error) {
@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"testing"
 
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
 	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
 	"github.com/jetstack/cert-manager/pkg/util"
 )
@@ -114,6 +115,7 @@ func TestCommonNameForCertificate(t *testing.T) {
 		name        string
 		crtCN       string
 		crtDNSNames []string
+		policy      v1alpha2.SubjectAltNamesPolicy
 		expectedCN  string
 	}
 	tests := []testT{
@@ -138,10 +140,38 @@ func TestCommonNameForCertificate(t *testing.T) {
 			crtDNSNames: []string{"dnsname1", "dnsname2"},
 			expectedCN:  "",
 		},
+		{
+			name:        "certificate with UseCommonName policy",
+			crtCN:       "cn",
+			crtDNSNames: []string{"dnsname"},
+			policy:      v1alpha2.UseCommonNamePolicy,
+			expectedCN:  "cn",
+		},
+		{
+			name:        "certificate with OmitCommonName policy",
+			crtCN:       "cn",
+			crtDNSNames: []string{"dnsname"},
+			policy:      v1alpha2.OmitCommonNamePolicy,
+			expectedCN:  "",
+		},
+		{
+			name:        "certificate with CopyFirstDNSName policy",
+			crtDNSNames: []string{"DNSName2.", "dnsname1"},
+			policy:      v1alpha2.CopyFirstDNSNamePolicy,
+			expectedCN:  "dnsname2",
+		},
+		{
+			name:       "certificate with CopyFirstDNSName policy and no dns names",
+			crtCN:      "cn",
+			policy:     v1alpha2.CopyFirstDNSNamePolicy,
+			expectedCN: "",
+		},
 	}
 	testFn := func(test testT) func(*testing.T) {
 		return func(t *testing.T) {
-			actualCN := buildCertificate(test.crtCN, test.crtDNSNames...).Spec.CommonName
+			crt := buildCertificate(test.crtCN, test.crtDNSNames...)
+			crt.Spec.SubjectAltNamesPolicy = test.policy
+			actualCN := CommonNameForCertificateSpec(&crt.Spec)
 			if actualCN != test.expectedCN {
 				t.Errorf("expected %q but got %q", test.expectedCN, actualCN)
 				return
@@ -153,6 +183,60 @@ func TestCommonNameForCertificate(t *testing.T) {
 	}
 }
 
+func TestValidateSubjectAltNamesPolicyForIssuer(t *testing.T) {
+	acmeIssuer := &v1alpha2.Issuer{
+		Spec: v1alpha2.IssuerSpec{
+			IssuerConfig: v1alpha2.IssuerConfig{ACME: &cmacme.ACMEIssuer{}},
+		},
+	}
+	caIssuer := &v1alpha2.Issuer{
+		Spec: v1alpha2.IssuerSpec{
+			IssuerConfig: v1alpha2.IssuerConfig{CA: &v1alpha2.CAIssuer{}},
+		},
+	}
+	tests := map[string]struct {
+		crt       *v1alpha2.Certificate
+		policy    v1alpha2.SubjectAltNamesPolicy
+		issuer    v1alpha2.GenericIssuer
+		expectErr bool
+	}{
+		"ACME issuer with UseCommonName policy and commonName in dnsNames": {
+			crt:    buildCertificate("Example.com", "example.com"),
+			policy: v1alpha2.UseCommonNamePolicy,
+			issuer: acmeIssuer,
+		},
+		"ACME issuer with UseCommonName policy and commonName not in dnsNames": {
+			crt:       buildCertificate("cn", "example.com"),
+			policy:    v1alpha2.UseCommonNamePolicy,
+			issuer:    acmeIssuer,
+			expectErr: true,
+		},
+		"ACME issuer with OmitCommonName policy": {
+			crt:    buildCertificate("", "example.com"),
+			policy: v1alpha2.OmitCommonNamePolicy,
+			issuer: acmeIssuer,
+		},
+		"ACME issuer without a policy leaves validation to the issuer": {
+			crt:    buildCertificate("cn", "example.com"),
+			issuer: acmeIssuer,
+		},
+		"CA issuer with UseCommonName policy and commonName not in dnsNames": {
+			crt:    buildCertificate("cn", "example.com"),
+			policy: v1alpha2.UseCommonNamePolicy,
+			issuer: caIssuer,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.crt.Spec.SubjectAltNamesPolicy = test.policy
+			err := ValidateSubjectAltNamesPolicyForIssuer(&test.crt.Spec, test.issuer)
+			if test.expectErr != (err != nil) {
+				t.Errorf("expected error %t but got: %v", test.expectErr, err)
+			}
+		})
+	}
+}
+
 func TestDNSNamesForCertificate(t *testing.T) {
 	type testT struct {
 		name           string
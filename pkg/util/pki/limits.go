@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/x509"
+	"fmt"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+)
+
+// ACMEMaxIdentifiers is the maximum number of identifiers accepted in a
+// single order by common ACME CAs, e.g. Let's Encrypt.
+const ACMEMaxIdentifiers = 100
+
+// SANCountForCSR returns the number of subject alternative names requested by
+// the CSR.
+func SANCountForCSR(csr *x509.CertificateRequest) int {
+	return len(csr.DNSNames) + len(csr.IPAddresses) + len(csr.URIs) + len(csr.EmailAddresses)
+}
+
+// ValidateACMEIdentifierCount returns an error if an ACME order for the given
+// common name and DNS names would contain more than ACMEMaxIdentifiers
+// identifiers. Names are counted in their canonical form, as an order
+// contains each identifier only once.
+func ValidateACMEIdentifierCount(commonName string, dnsNames []string) error {
+	names := dnsNames
+	if commonName != "" {
+		names = append([]string{commonName}, dnsNames...)
+	}
+	if count := len(CanonicalDNSNames(names)); count > ACMEMaxIdentifiers {
+		return fmt.Errorf("%d DNS names requested, but ACME orders are limited to %d names; split the names across multiple Certificates", count, ACMEMaxIdentifiers)
+	}
+	return nil
+}
+
+// ValidateNameCountForIssuer returns an error if the Certificate spec requests
+// more names than the given issuer is known to support in a single
+// certificate.
+func ValidateNameCountForIssuer(spec *v1alpha2.CertificateSpec, issuer v1alpha2.GenericIssuer) error {
+	if issuer.GetSpec().ACME == nil {
+		return nil
+	}
+	return ValidateACMEIdentifierCount(CommonNameForCertificateSpec(spec), spec.DNSNames)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"fmt"
+	"testing"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+)
+
+func TestValidateNameCountForIssuer(t *testing.T) {
+	names := func(n int) []string {
+		var out []string
+		for i := 0; i < n; i++ {
+			out = append(out, fmt.Sprintf("host-%d.example.com", i))
+		}
+		return out
+	}
+	acmeIssuer := &v1alpha2.Issuer{
+		Spec: v1alpha2.IssuerSpec{
+			IssuerConfig: v1alpha2.IssuerConfig{ACME: &cmacme.ACMEIssuer{}},
+		},
+	}
+	caIssuer := &v1alpha2.Issuer{
+		Spec: v1alpha2.IssuerSpec{
+			IssuerConfig: v1alpha2.IssuerConfig{CA: &v1alpha2.CAIssuer{}},
+		},
+	}
+
+	tests := map[string]struct {
+		crt       *v1alpha2.Certificate
+		issuer    v1alpha2.GenericIssuer
+		expectErr bool
+	}{
+		"ACME issuer with the maximum number of names": {
+			crt:    buildCertificate("", names(ACMEMaxIdentifiers)...),
+			issuer: acmeIssuer,
+		},
+		"ACME issuer with a common name that is also a DNS name": {
+			crt:    buildCertificate("HOST-0.example.com.", names(ACMEMaxIdentifiers)...),
+			issuer: acmeIssuer,
+		},
+		"ACME issuer with a common name exceeding the maximum number of names": {
+			crt:       buildCertificate("cn.example.com", names(ACMEMaxIdentifiers)...),
+			issuer:    acmeIssuer,
+			expectErr: true,
+		},
+		"ACME issuer with too many DNS names": {
+			crt:       buildCertificate("", names(ACMEMaxIdentifiers+1)...),
+			issuer:    acmeIssuer,
+			expectErr: true,
+		},
+		"CA issuer with many DNS names": {
+			crt:    buildCertificate("", names(ACMEMaxIdentifiers+1)...),
+			issuer: caIssuer,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateNameCountForIssuer(&test.crt.Spec, test.issuer)
+			if test.expectErr != (err != nil) {
+				t.Errorf("expected error %t but got: %v", test.expectErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// The functions in this file canonicalize subject alternative names, so that
+// names that only differ in their representation, e.g. in case or in the
+// notation of an IPv6 address, are treated as equal.
+// The same canonical form is used when building CSRs and when comparing
+// issued certificates to the spec of a Certificate, so that a certificate is
+// never considered out of date because of a difference in representation.
+
+// CanonicalDNSName returns the canonical form of a DNS name: lowercase and
+// without a trailing dot.
+func CanonicalDNSName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
+
+// CanonicalDNSNames returns the sorted canonical forms of the given DNS names,
+// without duplicates.
+func CanonicalDNSNames(names []string) []string {
+	canonical := make([]string, 0, len(names))
+	for _, name := range names {
+		canonical = append(canonical, CanonicalDNSName(name))
+	}
+	return sortedUnique(canonical)
+}
+
+// CanonicalIPAddress returns the canonical form of an IP address, as returned
+// by net.IP.String. IPv4-mapped IPv6 addresses are returned in their IPv4
+// form. If ip cannot be parsed, it is returned unchanged.
+func CanonicalIPAddress(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+	return parsed.String()
+}
+
+// CanonicalIPAddresses returns the sorted canonical forms of the given IP
+// addresses, without duplicates.
+func CanonicalIPAddresses(ips []string) []string {
+	canonical := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		canonical = append(canonical, CanonicalIPAddress(ip))
+	}
+	return sortedUnique(canonical)
+}
+
+// CanonicalURI returns the canonical form of a URI: the scheme and host are
+// lowercase. The rest of the URI is case sensitive and left unchanged. If uri
+// cannot be parsed, it is returned unchanged.
+func CanonicalURI(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	return parsed.String()
+}
+
+// CanonicalURIs returns the sorted canonical forms of the given URIs, without
+// duplicates.
+func CanonicalURIs(uris []string) []string {
+	canonical := make([]string, 0, len(uris))
+	for _, uri := range uris {
+		canonical = append(canonical, CanonicalURI(uri))
+	}
+	return sortedUnique(canonical)
+}
+
+// DNSNamesEqual returns true if a and b contain the same DNS names, ignoring
+// order, duplicates and differences in representation.
+func DNSNamesEqual(a, b []string) bool {
+	return stringSlicesEqual(CanonicalDNSNames(a), CanonicalDNSNames(b))
+}
+
+// IPAddressesEqual returns true if a and b contain the same IP addresses,
+// ignoring order, duplicates and differences in representation.
+func IPAddressesEqual(a, b []string) bool {
+	return stringSlicesEqual(CanonicalIPAddresses(a), CanonicalIPAddresses(b))
+}
+
+// URIsEqual returns true if a and b contain the same URIs, ignoring order,
+// duplicates and differences in representation.
+func URIsEqual(a, b []string) bool {
+	return stringSlicesEqual(CanonicalURIs(a), CanonicalURIs(b))
+}
+
+func sortedUnique(in []string) []string {
+	if len(in) == 0 {
+		return nil
+	}
+	sort.Strings(in)
+	out := in[:1]
+	for _, s := range in[1:] {
+		if s != out[len(out)-1] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCanonicalSANs(t *testing.T) {
+	tests := map[string]struct {
+		canonicalize func([]string) []string
+		in           []string
+		expected     []string
+	}{
+		"DNS names are lowercased, deduplicated and sorted": {
+			canonicalize: CanonicalDNSNames,
+			in:           []string{"b.example.com", "A.Example.com", "a.example.com.", "*.Example.com"},
+			expected:     []string{"*.example.com", "a.example.com", "b.example.com"},
+		},
+		"no DNS names": {
+			canonicalize: CanonicalDNSNames,
+			in:           nil,
+			expected:     nil,
+		},
+		"IPv6 addresses are normalized": {
+			canonicalize: CanonicalIPAddresses,
+			in:           []string{"2001:DB8:0:0:0:0:0:1", "2001:db8::1", "::ffff:10.0.0.1", "10.0.0.1"},
+			expected:     []string{"10.0.0.1", "2001:db8::1"},
+		},
+		"invalid IP addresses are kept unchanged": {
+			canonicalize: CanonicalIPAddresses,
+			in:           []string{"not-an-ip", "127.0.0.1"},
+			expected:     []string{"127.0.0.1", "not-an-ip"},
+		},
+		"URI scheme and host are lowercased": {
+			canonicalize: CanonicalURIs,
+			in:           []string{"SPIFFE://Example.com/Workload", "spiffe://example.com/Workload", "spiffe://example.com/workload"},
+			expected:     []string{"spiffe://example.com/Workload", "spiffe://example.com/workload"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := test.canonicalize(test.in)
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %q, got: %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSANsEqual(t *testing.T) {
+	tests := map[string]struct {
+		equal    func(a, b []string) bool
+		a, b     []string
+		expected bool
+	}{
+		"DNS names differing in case and order": {
+			equal:    DNSNamesEqual,
+			a:        []string{"Example.com", "www.example.com"},
+			b:        []string{"www.example.com", "example.com", "example.com"},
+			expected: true,
+		},
+		"different DNS names": {
+			equal:    DNSNamesEqual,
+			a:        []string{"example.com"},
+			b:        []string{"example.org"},
+			expected: false,
+		},
+		"IPv6 addresses in different notation": {
+			equal:    IPAddressesEqual,
+			a:        []string{"2001:0db8:0000:0000:0000:0000:0000:0001"},
+			b:        []string{"2001:db8::1"},
+			expected: true,
+		},
+		"different IP addresses": {
+			equal:    IPAddressesEqual,
+			a:        []string{"10.0.0.1"},
+			b:        []string{"10.0.0.2"},
+			expected: false,
+		},
+		"URIs differing in host case": {
+			equal:    URIsEqual,
+			a:        []string{"spiffe://Cluster.Local/ns/default"},
+			b:        []string{"spiffe://cluster.local/ns/default"},
+			expected: true,
+		},
+		"URIs differing in path case": {
+			equal:    URIsEqual,
+			a:        []string{"spiffe://cluster.local/ns/Default"},
+			b:        []string{"spiffe://cluster.local/ns/default"},
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := test.equal(test.a, test.b); actual != test.expected {
+				t.Errorf("expected %t, got: %t", test.expected, actual)
+			}
+		})
+	}
+}
@@ -91,6 +91,10 @@ func (r *registryBackedValidator) Validate(admissionSpec *admissionv1beta1.Admis
 	errs := field.ErrorList{}
 	// perform validation on new version of resource
 	errs = append(errs, r.registry.Validate(obj, gvk)...)
+	if admissionSpec.Operation == admissionv1beta1.Create {
+		// perform validation that depends on the user creating the resource
+		errs = append(errs, r.registry.ValidateCreate(obj, admissionSpec.UserInfo, gvk)...)
+	}
 	if oldObj != nil {
 		// perform update validation on resource
 		errs = append(errs, r.registry.ValidateUpdate(oldObj, obj, gvk)...)
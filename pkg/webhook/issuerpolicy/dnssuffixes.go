@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package issuerpolicy contains admission checks that enforce policy defined
+// on Issuer and ClusterIssuer resources. Unlike the checks in the validation
+// registry, these need to read the referenced issuer from the API server so
+// are only enabled when the webhook is configured to do so.
+package issuerpolicy
+
+import (
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/internal/api/validation"
+	cminternal "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// InstallAllowedDNSSuffixesValidation registers the allowed DNS suffixes check
+// for CertificateRequest resources with the given registry.
+// The check only runs when CertificateRequests are created: their spec is
+// immutable, so restricting an issuer later must not block the status updates
+// of requests that are already in flight.
+func InstallAllowedDNSSuffixesValidation(registry *validation.Registry, issuers cmlisters.IssuerLister, clusterIssuers cmlisters.ClusterIssuerLister) error {
+	return registry.AddValidateCreateFunc(&cminternal.CertificateRequest{}, NewAllowedDNSSuffixesValidateFunc(issuers, clusterIssuers))
+}
+
+// NewAllowedDNSSuffixesValidateFunc returns a validation function for
+// CertificateRequest resources that rejects requests for DNS names that are
+// not allowed by the AllowedDNSSuffixes of the referenced issuer.
+// Issuers are read from the given listers, so that admission does not wait
+// for the API server. If the issuer is not found, the request is admitted and
+// the check is left to the CertificateRequest controllers.
+func NewAllowedDNSSuffixesValidateFunc(issuers cmlisters.IssuerLister, clusterIssuers cmlisters.ClusterIssuerLister) validation.ValidateCreateFunc {
+	return func(obj runtime.Object, _ authenticationv1.UserInfo) field.ErrorList {
+		cr := obj.(*cminternal.CertificateRequest)
+
+		ref := cr.Spec.IssuerRef
+		if !(ref.Group == "" || ref.Group == certmanager.GroupName) {
+			return nil
+		}
+
+		kind := ref.Kind
+		if kind == "" {
+			kind = cmapi.IssuerKind
+		}
+
+		var spec *cmapi.IssuerSpec
+		switch kind {
+		case cmapi.IssuerKind:
+			iss, err := issuers.Issuers(cr.Namespace).Get(ref.Name)
+			if err != nil {
+				return nil
+			}
+			spec = &iss.Spec
+		case cmapi.ClusterIssuerKind:
+			iss, err := clusterIssuers.Get(ref.Name)
+			if err != nil {
+				return nil
+			}
+			spec = &iss.Spec
+		default:
+			return nil
+		}
+
+		if len(spec.AllowedDNSSuffixes) == 0 {
+			return nil
+		}
+
+		// an invalid CSR is reported by the CertificateRequest validation
+		dnsNames, err := pki.DNSNamesForCSR(cr.Spec.Request)
+		if err != nil {
+			return nil
+		}
+
+		notAllowed := apiutil.DNSNamesNotAllowedByIssuer(spec, dnsNames)
+		if len(notAllowed) == 0 {
+			return nil
+		}
+
+		return field.ErrorList{
+			field.Forbidden(field.NewPath("spec", "csr"),
+				fmt.Sprintf("referenced %s %q does not allow issuing certificates for DNS names %v", kind, ref.Name, notAllowed)),
+		}
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuerpolicy
+
+import (
+	"crypto/x509"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/client-go/tools/cache"
+
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1alpha2"
+	cminternal "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+	cmmeta "github.com/jetstack/cert-manager/pkg/internal/apis/meta"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestAllowedDNSSuffixesValidateFunc(t *testing.T) {
+	issuers := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	clusterIssuers := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, obj := range []interface{}{
+		gen.Issuer("restricted",
+			gen.SetIssuerNamespace("default"),
+			gen.SetIssuerAllowedDNSSuffixes("team-a.example.com"),
+		),
+		gen.Issuer("unrestricted",
+			gen.SetIssuerNamespace("default"),
+		),
+	} {
+		if err := issuers.Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := clusterIssuers.Add(gen.ClusterIssuer("restricted", gen.SetIssuerAllowedDNSSuffixes("shared.example.com"))); err != nil {
+		t.Fatal(err)
+	}
+	validate := NewAllowedDNSSuffixesValidateFunc(cmlisters.NewIssuerLister(issuers), cmlisters.NewClusterIssuerLister(clusterIssuers))
+
+	tests := map[string]struct {
+		issuer   string
+		kind     string
+		dnsNames []string
+		expErr   bool
+	}{
+		"names within allowed suffix are admitted": {
+			issuer:   "restricted",
+			dnsNames: []string{"team-a.example.com", "app.team-a.example.com"},
+		},
+		"names outside allowed suffix are rejected": {
+			issuer:   "restricted",
+			dnsNames: []string{"app.team-b.example.com"},
+			expErr:   true,
+		},
+		"issuer without allowed suffixes admits any name": {
+			issuer:   "unrestricted",
+			dnsNames: []string{"app.team-b.example.com"},
+		},
+		"names outside the allowed suffix of a cluster issuer are rejected": {
+			issuer:   "restricted",
+			kind:     "ClusterIssuer",
+			dnsNames: []string{"app.team-a.example.com"},
+			expErr:   true,
+		},
+		"names within the allowed suffix of a cluster issuer are admitted": {
+			issuer:   "restricted",
+			kind:     "ClusterIssuer",
+			dnsNames: []string{"app.shared.example.com"},
+		},
+		"missing issuer is admitted": {
+			issuer:   "does-not-exist",
+			dnsNames: []string{"app.team-b.example.com"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			csr, _, err := gen.CSR(x509.ECDSA, gen.SetCSRDNSNames(test.dnsNames...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			cr := &cminternal.CertificateRequest{}
+			cr.Namespace = "default"
+			cr.Spec.Request = csr
+			cr.Spec.IssuerRef = cmmeta.ObjectReference{Name: test.issuer, Kind: test.kind}
+
+			errs := validate(cr, authenticationv1.UserInfo{})
+			if test.expErr != (len(errs) > 0) {
+				t.Errorf("expected error %t but got: %v", test.expErr, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package podidentity contains an admission check that binds the
+// CertificateRequests created by, or by trusted agents on behalf of, Pods to
+// the identity of the Pod, so that one workload cannot obtain certificates
+// for the identity of another.
+package podidentity
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	"github.com/jetstack/cert-manager/pkg/internal/api/validation"
+	cminternal "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	// podNameExtraKey is the key of the user info extra holding the name of
+	// the Pod a bound service account token was issued for.
+	podNameExtraKey = "authentication.kubernetes.io/pod-name"
+
+	serviceAccountUsernamePrefix = "system:serviceaccount:"
+
+	// podLookupTimeout is the maximum time spent fetching the Pod named by
+	// an agent during admission.
+	podLookupTimeout = 5 * time.Second
+)
+
+var serviceAccountAnnotationPath = field.NewPath("metadata", "annotations").Key(cmapi.CertificateRequestPodServiceAccountAnnotationKey)
+
+// Policy defines the names the certificates of a Pod may be issued for.
+// Names are templates in which {namespace}, {serviceaccount} and {pod} are
+// replaced by the namespace, service account and name of the Pod.
+type Policy struct {
+	// DNSNames are the DNS names, and common names, a Pod may request.
+	DNSNames []string
+	// URIs are the URI subject alternative names a Pod may request.
+	URIs []string
+	// Agents are the usernames of trusted agents, such as CSI drivers, that
+	// create CertificateRequests on behalf of Pods. Agents name the Pod in
+	// the cert-manager.io/pod-name annotation of the CertificateRequest.
+	Agents []string
+	// TrustedUsers and TrustedGroups are the usernames and groups of users,
+	// such as the cert-manager controller, whose CertificateRequests are not
+	// bound to the identity of a Pod. Requests by any other user that is
+	// neither a Pod using a bound service account token nor an agent are
+	// rejected.
+	TrustedUsers  []string
+	TrustedGroups []string
+}
+
+// Identity is the identity of the Pod a CertificateRequest is created for.
+type Identity struct {
+	Namespace      string
+	ServiceAccount string
+	Pod            string
+}
+
+// InstallValidation registers the pod identity check for CertificateRequest
+// resources with the given registry.
+func InstallValidation(registry *validation.Registry, policy Policy, pods corev1client.PodsGetter) error {
+	if err := registry.AddValidateCreateFunc(&cminternal.CertificateRequest{}, NewValidateFunc(policy, pods)); err != nil {
+		return err
+	}
+	return registry.AddValidateUpdateFunc(&cminternal.CertificateRequest{}, ValidateUpdate)
+}
+
+// NewValidateFunc returns a validation function for CertificateRequest
+// resources that rejects requests created by a Pod, or by an agent on behalf
+// of a Pod, for names that cannot be derived from the identity of the Pod.
+// Requests created by trusted users are not checked, and requests created by
+// any other user, including Pods using service account tokens that are not
+// bound to the Pod, are rejected.
+// The cert-manager.io/pod-service-account annotation may only be set by Pods
+// and agents, to the service account of the Pod, so that the controller can
+// trust it.
+func NewValidateFunc(policy Policy, pods corev1client.PodsGetter) validation.ValidateCreateFunc {
+	agents := sets.NewString(policy.Agents...)
+	trustedUsers := sets.NewString(policy.TrustedUsers...)
+	trustedGroups := sets.NewString(policy.TrustedGroups...)
+	return func(obj runtime.Object, userInfo authenticationv1.UserInfo) field.ErrorList {
+		cr := obj.(*cminternal.CertificateRequest)
+		serviceAccount, hasServiceAccount := cr.Annotations[cmapi.CertificateRequestPodServiceAccountAnnotationKey]
+
+		var id Identity
+		switch {
+		case trustedUsers.Has(userInfo.Username) || trustedGroups.HasAny(userInfo.Groups...):
+			if hasServiceAccount {
+				// trusted users, such as the controller creating requests
+				// for Certificates, do not create requests for a Pod
+				return field.ErrorList{
+					field.Forbidden(serviceAccountAnnotationPath,
+						fmt.Sprintf("may only be set by Pods using bound service account tokens and pod identity agents, not by user %q", userInfo.Username)),
+				}
+			}
+			return nil
+		case agents.Has(userInfo.Username):
+			var errs field.ErrorList
+			id, errs = identityForAgentRequest(cr, pods)
+			if len(errs) > 0 {
+				return errs
+			}
+		case strings.HasPrefix(userInfo.Username, serviceAccountUsernamePrefix) && len(userInfo.Extra[podNameExtraKey]) > 0:
+			// the username is validated by the API server, so always has
+			// the form system:serviceaccount:<namespace>:<name>
+			parts := strings.SplitN(strings.TrimPrefix(userInfo.Username, serviceAccountUsernamePrefix), ":", 2)
+			if len(parts) != 2 {
+				return field.ErrorList{forbiddenUser(userInfo)}
+			}
+			id = Identity{Namespace: parts[0], ServiceAccount: parts[1], Pod: userInfo.Extra[podNameExtraKey][0]}
+			if cr.Namespace != id.Namespace {
+				return field.ErrorList{
+					field.Forbidden(field.NewPath("metadata", "namespace"),
+						fmt.Sprintf("Pod %q may only request certificates in its own namespace %q", id.Pod, id.Namespace)),
+				}
+			}
+		default:
+			// the identity of the requester cannot be verified, so fail
+			// closed
+			return field.ErrorList{forbiddenUser(userInfo)}
+		}
+
+		if hasServiceAccount && serviceAccount != id.ServiceAccount {
+			return field.ErrorList{
+				field.Invalid(serviceAccountAnnotationPath, serviceAccount,
+					fmt.Sprintf("must be the service account %q of Pod %q", id.ServiceAccount, id.Namespace+"/"+id.Pod)),
+			}
+		}
+
+		// an invalid CSR is reported by the CertificateRequest validation
+		csr, err := pki.DecodeX509CertificateRequestBytes(cr.Spec.Request)
+		if err != nil {
+			return nil
+		}
+
+		if notAllowed := policy.NamesNotAllowed(id, csr); len(notAllowed) > 0 {
+			return field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "csr"),
+					fmt.Sprintf("names %v cannot be derived from the identity of Pod %q with service account %q", notAllowed, id.Namespace+"/"+id.Pod, id.ServiceAccount)),
+			}
+		}
+		return nil
+	}
+}
+
+// ValidateUpdate rejects changes to the cert-manager.io/pod-service-account
+// annotation of CertificateRequests, which is only verified at creation.
+func ValidateUpdate(oldObj, obj runtime.Object) field.ErrorList {
+	oldCR := oldObj.(*cminternal.CertificateRequest)
+	cr := obj.(*cminternal.CertificateRequest)
+
+	oldServiceAccount, hadServiceAccount := oldCR.Annotations[cmapi.CertificateRequestPodServiceAccountAnnotationKey]
+	serviceAccount, hasServiceAccount := cr.Annotations[cmapi.CertificateRequestPodServiceAccountAnnotationKey]
+	if hadServiceAccount != hasServiceAccount || oldServiceAccount != serviceAccount {
+		return field.ErrorList{field.Forbidden(serviceAccountAnnotationPath, "cannot be changed once the CertificateRequest has been created")}
+	}
+	return nil
+}
+
+// forbiddenUser returns the error of requests by users whose requests
+// cannot be bound to the identity of a Pod.
+func forbiddenUser(userInfo authenticationv1.UserInfo) *field.Error {
+	return field.Forbidden(field.NewPath("metadata"),
+		fmt.Sprintf("user %q is not trusted and its requests cannot be bound to the identity of a Pod, as it is neither using a service account token bound to a Pod nor a pod identity agent", userInfo.Username))
+}
+
+// identityForAgentRequest returns the identity of the Pod named in the
+// annotations of a CertificateRequest created by an agent.
+func identityForAgentRequest(cr *cminternal.CertificateRequest, pods corev1client.PodsGetter) (Identity, field.ErrorList) {
+	annotationPath := field.NewPath("metadata", "annotations").Key(cmapi.CertificateRequestPodNameAnnotationKey)
+	podName := cr.Annotations[cmapi.CertificateRequestPodNameAnnotationKey]
+	if podName == "" {
+		return Identity{}, field.ErrorList{field.Required(annotationPath, "must name the Pod the CertificateRequest is created for")}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), podLookupTimeout)
+	defer cancel()
+
+	pod, err := pods.Pods(cr.Namespace).Get(ctx, podName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return Identity{}, field.ErrorList{field.Invalid(annotationPath, podName, "Pod does not exist")}
+	case err != nil:
+		// fail closed, as the identity of the Pod cannot be verified
+		return Identity{}, field.ErrorList{field.InternalError(annotationPath, err)}
+	}
+
+	serviceAccount := pod.Spec.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+	return Identity{Namespace: pod.Namespace, ServiceAccount: serviceAccount, Pod: pod.Name}, nil
+}
+
+// NamesNotAllowed returns the names requested by csr that are not allowed
+// for the Pod with the given identity. IP addresses and email addresses are
+// never allowed, as they cannot be derived from the identity of a Pod.
+func (p Policy) NamesNotAllowed(id Identity, csr *x509.CertificateRequest) []string {
+	r := strings.NewReplacer("{namespace}", id.Namespace, "{serviceaccount}", id.ServiceAccount, "{pod}", id.Pod)
+	dnsNames := sets.NewString()
+	for _, t := range p.DNSNames {
+		dnsNames.Insert(pki.CanonicalDNSName(r.Replace(t)))
+	}
+	uris := sets.NewString()
+	for _, t := range p.URIs {
+		uris.Insert(r.Replace(t))
+	}
+
+	requested := csr.DNSNames
+	if cn := csr.Subject.CommonName; cn != "" && !sets.NewString(pki.CanonicalDNSNames(requested)...).Has(pki.CanonicalDNSName(cn)) {
+		requested = append([]string{cn}, requested...)
+	}
+
+	var notAllowed []string
+	for _, name := range requested {
+		if !dnsNames.Has(pki.CanonicalDNSName(name)) {
+			notAllowed = append(notAllowed, name)
+		}
+	}
+	for _, uri := range pki.URLsToString(csr.URIs) {
+		if !uris.Has(uri) {
+			notAllowed = append(notAllowed, uri)
+		}
+	}
+	notAllowed = append(notAllowed, pki.IPAddressesToString(csr.IPAddresses)...)
+	notAllowed = append(notAllowed, csr.EmailAddresses...)
+	return notAllowed
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podidentity
+
+import (
+	"crypto/x509"
+	"net"
+	"net/url"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cminternal "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestValidateFunc(t *testing.T) {
+	policy := Policy{
+		DNSNames:      []string{"{serviceaccount}.{namespace}.svc", "{pod}.{namespace}.pod"},
+		URIs:          []string{"spiffe://cluster.local/ns/{namespace}/sa/{serviceaccount}"},
+		Agents:        []string{"system:serviceaccount:cert-manager:csi-driver"},
+		TrustedUsers:  []string{"system:serviceaccount:cert-manager:cert-manager"},
+		TrustedGroups: []string{"system:masters"},
+	}
+	pods := kubefake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "app-0"},
+			Spec:       corev1.PodSpec{ServiceAccountName: "app"},
+		},
+	).CoreV1()
+	validate := NewValidateFunc(policy, pods)
+
+	podUser := authenticationv1.UserInfo{
+		Username: "system:serviceaccount:team-a:app",
+		Extra:    map[string]authenticationv1.ExtraValue{podNameExtraKey: {"app-0"}},
+	}
+	agentUser := authenticationv1.UserInfo{Username: "system:serviceaccount:cert-manager:csi-driver"}
+	spiffeID, _ := url.Parse("spiffe://cluster.local/ns/team-a/sa/app")
+	otherSpiffeID, _ := url.Parse("spiffe://cluster.local/ns/team-a/sa/other")
+
+	tests := map[string]struct {
+		userInfo    authenticationv1.UserInfo
+		namespace   string
+		annotations map[string]string
+		csrMods     []gen.CSRModifier
+		expErr      bool
+	}{
+		"Pod requesting names derived from its identity is admitted": {
+			userInfo:  podUser,
+			namespace: "team-a",
+			csrMods:   []gen.CSRModifier{gen.SetCSRDNSNames("app.team-a.svc", "app-0.team-a.pod"), gen.SetCSRURIs(spiffeID)},
+		},
+		"Pod requesting the DNS name of another service account is rejected": {
+			userInfo:  podUser,
+			namespace: "team-a",
+			csrMods:   []gen.CSRModifier{gen.SetCSRDNSNames("other.team-a.svc")},
+			expErr:    true,
+		},
+		"Pod requesting the URI of another service account is rejected": {
+			userInfo:  podUser,
+			namespace: "team-a",
+			csrMods:   []gen.CSRModifier{gen.SetCSRDNSNames("app.team-a.svc"), gen.SetCSRURIs(otherSpiffeID)},
+			expErr:    true,
+		},
+		"Pod requesting an IP address is rejected": {
+			userInfo:  podUser,
+			namespace: "team-a",
+			csrMods:   []gen.CSRModifier{gen.SetCSRDNSNames("app.team-a.svc"), gen.SetCSRIPAddresses(net.ParseIP("10.0.0.1"))},
+			expErr:    true,
+		},
+		"Pod requesting a certificate in another namespace is rejected": {
+			userInfo:  podUser,
+			namespace: "team-b",
+			csrMods:   []gen.CSRModifier{gen.SetCSRDNSNames("app.team-a.svc")},
+			expErr:    true,
+		},
+		"service account token not bound to a Pod is rejected": {
+			userInfo:  authenticationv1.UserInfo{Username: "system:serviceaccount:team-a:app"},
+			namespace: "team-a",
+			csrMods:   []gen.CSRModifier{gen.SetCSRDNSNames("app.team-a.svc")},
+			expErr:    true,
+		},
+		"user that is not a service account is rejected": {
+			userInfo:  authenticationv1.UserInfo{Username: "admin", Groups: []string{"system:authenticated"}},
+			namespace: "team-a",
+			csrMods:   []gen.CSRModifier{gen.SetCSRDNSNames("app.team-a.svc")},
+			expErr:    true,
+		},
+		"trusted user is not checked": {
+			userInfo:  authenticationv1.UserInfo{Username: "system:serviceaccount:cert-manager:cert-manager"},
+			namespace: "team-a",
+			csrMods:   []gen.CSRModifier{gen.SetCSRDNSNames("other.team-a.svc")},
+		},
+		"user in a trusted group is not checked": {
+			userInfo:  authenticationv1.UserInfo{Username: "admin", Groups: []string{"system:masters", "system:authenticated"}},
+			namespace: "team-a",
+			csrMods:   []gen.CSRModifier{gen.SetCSRDNSNames("other.team-a.svc")},
+		},
+		"agent requesting names derived from the identity of the named Pod is admitted": {
+			userInfo:    agentUser,
+			namespace:   "team-a",
+			annotations: map[string]string{cmapi.CertificateRequestPodNameAnnotationKey: "app-0"},
+			csrMods:     []gen.CSRModifier{gen.SetCSRDNSNames("app.team-a.svc"), gen.SetCSRURIs(spiffeID)},
+		},
+		"agent requesting names of another identity than the named Pod is rejected": {
+			userInfo:    agentUser,
+			namespace:   "team-a",
+			annotations: map[string]string{cmapi.CertificateRequestPodNameAnnotationKey: "app-0"},
+			csrMods:     []gen.CSRModifier{gen.SetCSRDNSNames("other.team-a.svc")},
+			expErr:      true,
+		},
+		"agent not naming a Pod is rejected": {
+			userInfo:  agentUser,
+			namespace: "team-a",
+			csrMods:   []gen.CSRModifier{gen.SetCSRDNSNames("app.team-a.svc")},
+			expErr:    true,
+		},
+		"agent naming a Pod that does not exist is rejected": {
+			userInfo:    agentUser,
+			namespace:   "team-a",
+			annotations: map[string]string{cmapi.CertificateRequestPodNameAnnotationKey: "app-1"},
+			csrMods:     []gen.CSRModifier{gen.SetCSRDNSNames("app.team-a.svc")},
+			expErr:      true,
+		},
+		"Pod setting its own service account annotation is admitted": {
+			userInfo:    podUser,
+			namespace:   "team-a",
+			annotations: map[string]string{cmapi.CertificateRequestPodServiceAccountAnnotationKey: "app"},
+			csrMods:     []gen.CSRModifier{gen.SetCSRDNSNames("app.team-a.svc")},
+		},
+		"Pod setting the service account annotation to another service account is rejected": {
+			userInfo:    podUser,
+			namespace:   "team-a",
+			annotations: map[string]string{cmapi.CertificateRequestPodServiceAccountAnnotationKey: "other"},
+			csrMods:     []gen.CSRModifier{gen.SetCSRDNSNames("app.team-a.svc")},
+			expErr:      true,
+		},
+		"agent setting the service account annotation of the named Pod is admitted": {
+			userInfo:  agentUser,
+			namespace: "team-a",
+			annotations: map[string]string{
+				cmapi.CertificateRequestPodNameAnnotationKey:           "app-0",
+				cmapi.CertificateRequestPodServiceAccountAnnotationKey: "app",
+			},
+			csrMods: []gen.CSRModifier{gen.SetCSRDNSNames("app.team-a.svc")},
+		},
+		"agent setting the service account annotation to another service account is rejected": {
+			userInfo:  agentUser,
+			namespace: "team-a",
+			annotations: map[string]string{
+				cmapi.CertificateRequestPodNameAnnotationKey:           "app-0",
+				cmapi.CertificateRequestPodServiceAccountAnnotationKey: "other",
+			},
+			csrMods: []gen.CSRModifier{gen.SetCSRDNSNames("app.team-a.svc")},
+			expErr:  true,
+		},
+		"trusted user setting the service account annotation is rejected": {
+			userInfo:    authenticationv1.UserInfo{Username: "system:serviceaccount:cert-manager:cert-manager"},
+			namespace:   "team-a",
+			annotations: map[string]string{cmapi.CertificateRequestPodServiceAccountAnnotationKey: "app"},
+			csrMods:     []gen.CSRModifier{gen.SetCSRDNSNames("app.team-a.svc")},
+			expErr:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			csr, _, err := gen.CSR(x509.ECDSA, test.csrMods...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cr := &cminternal.CertificateRequest{}
+			cr.Namespace = test.namespace
+			cr.Annotations = test.annotations
+			cr.Spec.Request = csr
+
+			errs := validate(cr, test.userInfo)
+			if test.expErr != (len(errs) > 0) {
+				t.Errorf("expected error %t but got: %v", test.expErr, errs)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	tests := map[string]struct {
+		oldAnnotations, annotations map[string]string
+		expErr                      bool
+	}{
+		"unchanged service account annotation is allowed": {
+			oldAnnotations: map[string]string{cmapi.CertificateRequestPodServiceAccountAnnotationKey: "app"},
+			annotations:    map[string]string{cmapi.CertificateRequestPodServiceAccountAnnotationKey: "app"},
+		},
+		"request without the service account annotation is allowed": {},
+		"changing the service account annotation is rejected": {
+			oldAnnotations: map[string]string{cmapi.CertificateRequestPodServiceAccountAnnotationKey: "app"},
+			annotations:    map[string]string{cmapi.CertificateRequestPodServiceAccountAnnotationKey: "other"},
+			expErr:         true,
+		},
+		"adding the service account annotation is rejected": {
+			annotations: map[string]string{cmapi.CertificateRequestPodServiceAccountAnnotationKey: "app"},
+			expErr:      true,
+		},
+		"removing the service account annotation is rejected": {
+			oldAnnotations: map[string]string{cmapi.CertificateRequestPodServiceAccountAnnotationKey: "app"},
+			expErr:         true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			oldCR := &cminternal.CertificateRequest{}
+			oldCR.Annotations = test.oldAnnotations
+			cr := &cminternal.CertificateRequest{}
+			cr.Annotations = test.annotations
+
+			errs := ValidateUpdate(oldCR, cr)
+			if test.expErr != (len(errs) > 0) {
+				t.Errorf("expected error %t but got: %v", test.expErr, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sizelimits contains admission checks that enforce configurable
+// limits on the number of subject alternative names and the size of CSRs, so
+// that oversized requests are rejected with a clear error at admission rather
+// than failing opaquely at the CA.
+package sizelimits
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/jetstack/cert-manager/pkg/internal/api/validation"
+	cminternal "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// Limits are the limits enforced at admission. A limit of zero disables the
+// respective check.
+type Limits struct {
+	// MaxSANs is the maximum number of subject alternative names a
+	// Certificate or CertificateRequest may request.
+	MaxSANs int
+	// MaxCSRBytes is the maximum size of the PEM encoded CSR of a
+	// CertificateRequest.
+	MaxCSRBytes int
+}
+
+// Enabled returns true if any of the limits is set.
+func (l Limits) Enabled() bool {
+	return l.MaxSANs > 0 || l.MaxCSRBytes > 0
+}
+
+// InstallValidation registers the size limit checks for Certificate and
+// CertificateRequest resources with the given registry.
+func InstallValidation(registry *validation.Registry, limits Limits) error {
+	if err := registry.AddValidateFunc(&cminternal.Certificate{}, NewCertificateValidateFunc(limits)); err != nil {
+		return err
+	}
+	return registry.AddValidateFunc(&cminternal.CertificateRequest{}, NewCertificateRequestValidateFunc(limits))
+}
+
+// NewCertificateValidateFunc returns a validation function for Certificate
+// resources that rejects Certificates requesting more subject alternative
+// names than allowed.
+func NewCertificateValidateFunc(limits Limits) validation.ValidateFunc {
+	return func(obj runtime.Object) field.ErrorList {
+		crt := obj.(*cminternal.Certificate)
+		spec := crt.Spec
+
+		count := len(spec.DNSNames) + len(spec.IPAddresses) + len(spec.URISANs) + len(spec.EmailSANs)
+		if limits.MaxSANs > 0 && count > limits.MaxSANs {
+			return field.ErrorList{
+				field.TooMany(field.NewPath("spec"), count, limits.MaxSANs),
+			}
+		}
+		return nil
+	}
+}
+
+// NewCertificateRequestValidateFunc returns a validation function for
+// CertificateRequest resources that rejects CSRs that are larger or request
+// more subject alternative names than allowed.
+func NewCertificateRequestValidateFunc(limits Limits) validation.ValidateFunc {
+	return func(obj runtime.Object) field.ErrorList {
+		cr := obj.(*cminternal.CertificateRequest)
+		fldPath := field.NewPath("spec", "csr")
+
+		if limits.MaxCSRBytes > 0 && len(cr.Spec.Request) > limits.MaxCSRBytes {
+			return field.ErrorList{
+				field.Forbidden(fldPath, fmt.Sprintf("CSR is %d bytes, the maximum allowed size is %d bytes", len(cr.Spec.Request), limits.MaxCSRBytes)),
+			}
+		}
+
+		if limits.MaxSANs == 0 {
+			return nil
+		}
+		// an invalid CSR is reported by the CertificateRequest validation
+		csr, err := pki.DecodeX509CertificateRequestBytes(cr.Spec.Request)
+		if err != nil {
+			return nil
+		}
+		if count := pki.SANCountForCSR(csr); count > limits.MaxSANs {
+			return field.ErrorList{
+				field.TooMany(fldPath, count, limits.MaxSANs),
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sizelimits
+
+import (
+	"crypto/x509"
+	"fmt"
+	"testing"
+
+	cminternal "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func names(n int) []string {
+	var out []string
+	for i := 0; i < n; i++ {
+		out = append(out, fmt.Sprintf("host-%d.example.com", i))
+	}
+	return out
+}
+
+func TestCertificateValidateFunc(t *testing.T) {
+	tests := map[string]struct {
+		limits   Limits
+		dnsNames []string
+		ips      []string
+		expErr   bool
+	}{
+		"no limit admits any number of names": {
+			dnsNames: names(200),
+		},
+		"names within the limit are admitted": {
+			limits:   Limits{MaxSANs: 3},
+			dnsNames: names(2),
+			ips:      []string{"10.0.0.1"},
+		},
+		"names exceeding the limit are rejected": {
+			limits:   Limits{MaxSANs: 3},
+			dnsNames: names(3),
+			ips:      []string{"10.0.0.1"},
+			expErr:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := &cminternal.Certificate{}
+			crt.Spec.DNSNames = test.dnsNames
+			crt.Spec.IPAddresses = test.ips
+
+			errs := NewCertificateValidateFunc(test.limits)(crt)
+			if test.expErr != (len(errs) > 0) {
+				t.Errorf("expected error %t but got: %v", test.expErr, errs)
+			}
+		})
+	}
+}
+
+func TestCertificateRequestValidateFunc(t *testing.T) {
+	tests := map[string]struct {
+		limits   Limits
+		dnsNames []string
+		expErr   bool
+	}{
+		"no limit admits any CSR": {
+			dnsNames: names(200),
+		},
+		"CSR within the limits is admitted": {
+			limits:   Limits{MaxSANs: 10, MaxCSRBytes: 64 * 1024},
+			dnsNames: names(10),
+		},
+		"CSR with too many names is rejected": {
+			limits:   Limits{MaxSANs: 10},
+			dnsNames: names(11),
+			expErr:   true,
+		},
+		"CSR exceeding the maximum size is rejected": {
+			limits:   Limits{MaxCSRBytes: 1024},
+			dnsNames: names(100),
+			expErr:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			csr, _, err := gen.CSR(x509.ECDSA, gen.SetCSRDNSNames(test.dnsNames...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			cr := &cminternal.CertificateRequest{}
+			cr.Spec.Request = csr
+
+			errs := NewCertificateRequestValidateFunc(test.limits)(cr)
+			if test.expErr != (len(errs) > 0) {
+				t.Errorf("expected error %t but got: %v", test.expErr, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webui implements a read-only web UI listing cert-manager
+// Certificates. The details of a Certificate are rendered by the same code as
+// the 'status certificate' command of kubectl cert-manager, so that teams
+// without terminal access to a cluster see the same information.
+package webui
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"github.com/gorilla/mux"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	ctlclients "github.com/jetstack/cert-manager/pkg/ctl/clients"
+	ctlstatus "github.com/jetstack/cert-manager/pkg/ctl/status"
+)
+
+var (
+	listTemplate = template.Must(template.New("list").Parse(`<!DOCTYPE html>
+<html>
+<head><title>cert-manager Certificates</title></head>
+<body>
+<h1>Certificates</h1>
+{{- if .Certificates }}
+<table>
+<tr><th>Namespace</th><th>Name</th><th>Ready</th><th>Secret</th><th>Not After</th></tr>
+{{- range .Certificates }}
+<tr><td>{{ .Namespace }}</td><td><a href="/certificates/{{ .Namespace }}/{{ .Name }}">{{ .Name }}</a></td><td>{{ .Ready }}</td><td>{{ .SecretName }}</td><td>{{ .NotAfter }}</td></tr>
+{{- end }}
+</table>
+{{- else }}
+<p>No Certificates found.</p>
+{{- end }}
+</body>
+</html>
+`))
+
+	detailTemplate = template.Must(template.New("detail").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Certificate {{ .Namespace }}/{{ .Name }}</title></head>
+<body>
+<p><a href="/">All Certificates</a></p>
+<h1>Certificate {{ .Namespace }}/{{ .Name }}</h1>
+<pre>{{ .Status }}</pre>
+</body>
+</html>
+`))
+)
+
+// certificateRow is a row of the list of Certificates.
+type certificateRow struct {
+	Namespace  string
+	Name       string
+	Ready      string
+	SecretName string
+	NotAfter   string
+}
+
+// Server serves the web UI. It only handles GET and HEAD requests, and never
+// modifies resources.
+type Server struct {
+	clients ctlstatus.Clients
+	// namespace is the namespace Certificates are listed and shown from,
+	// or metav1.NamespaceAll
+	namespace string
+	chunkSize int64
+	log       logr.Logger
+
+	router *mux.Router
+}
+
+// NewServer returns a Server listing the Certificates in namespace, or in all
+// namespaces if namespace is metav1.NamespaceAll, using clients. Resources
+// are listed in pages of chunkSize resources.
+func NewServer(log logr.Logger, clients ctlstatus.Clients, namespace string, chunkSize int64) *Server {
+	s := &Server{
+		clients:   clients,
+		namespace: namespace,
+		chunkSize: chunkSize,
+		log:       log,
+		router:    mux.NewRouter(),
+	}
+	s.router.HandleFunc("/", s.handleList).Methods(http.MethodGet, http.MethodHead)
+	s.router.HandleFunc("/certificates/{namespace}/{name}", s.handleCertificate).Methods(http.MethodGet, http.MethodHead)
+	s.router.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok"))
+	}).Methods(http.MethodGet, http.MethodHead)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	cache := ctlclients.NewCache(s.clients.Kube, s.clients.CM, s.chunkSize)
+	crts, err := cache.ListCertificates(r.Context(), s.namespace, labels.Everything())
+	if err != nil {
+		s.error(w, http.StatusInternalServerError, "error listing Certificates", err)
+		return
+	}
+	sort.Slice(crts, func(i, j int) bool {
+		if crts[i].Namespace != crts[j].Namespace {
+			return crts[i].Namespace < crts[j].Namespace
+		}
+		return crts[i].Name < crts[j].Name
+	})
+
+	rows := make([]certificateRow, 0, len(crts))
+	for _, crt := range crts {
+		row := certificateRow{
+			Namespace:  crt.Namespace,
+			Name:       crt.Name,
+			Ready:      "Unknown",
+			SecretName: crt.Spec.SecretName,
+			NotAfter:   "<none>",
+		}
+		if c := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionReady); c != nil {
+			row.Ready = string(c.Status)
+		}
+		if crt.Status.NotAfter != nil {
+			row.NotAfter = crt.Status.NotAfter.Format("2006-01-02 15:04:05 MST")
+		}
+		rows = append(rows, row)
+	}
+
+	s.render(w, listTemplate, struct{ Certificates []certificateRow }{rows})
+}
+
+func (s *Server) handleCertificate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace, name := vars["namespace"], vars["name"]
+	if s.namespace != metav1.NamespaceAll && namespace != s.namespace {
+		http.NotFound(w, r)
+		return
+	}
+
+	crt, err := s.clients.CM.CertmanagerV1alpha2().Certificates(namespace).Get(r.Context(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		s.error(w, http.StatusInternalServerError, "error getting Certificate", err)
+		return
+	}
+	status, err := ctlstatus.CollectStatusForCertificate(r.Context(), s.clients, crt)
+	if err != nil {
+		s.error(w, http.StatusInternalServerError, "error collecting status of Certificate", err)
+		return
+	}
+
+	s.render(w, detailTemplate, struct {
+		Namespace, Name, Status string
+	}{namespace, name, status.String()})
+}
+
+// render executes t into a buffer first, so that an error can still be
+// reported with a proper status code.
+func (s *Server) render(w http.ResponseWriter, t *template.Template, data interface{}) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		s.error(w, http.StatusInternalServerError, "error rendering page", err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+func (s *Server) error(w http.ResponseWriter, code int, msg string, err error) {
+	s.log.Error(err, msg)
+	http.Error(w, msg+": "+err.Error(), code)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Jetstack cert-manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmfake "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+	ctlstatus "github.com/jetstack/cert-manager/pkg/ctl/status"
+	logtesting "github.com/jetstack/cert-manager/pkg/logs/testing"
+)
+
+func TestServer(t *testing.T) {
+	newCrt := func(namespace, name string, ready cmmeta.ConditionStatus) *cmapi.Certificate {
+		return &cmapi.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       cmapi.CertificateSpec{SecretName: name + "-tls"},
+			Status: cmapi.CertificateStatus{Conditions: []cmapi.CertificateCondition{
+				{Type: cmapi.CertificateConditionReady, Status: ready},
+			}},
+		}
+	}
+	clients := ctlstatus.Clients{
+		Kube: kubefake.NewSimpleClientset(),
+		CM: cmfake.NewSimpleClientset(
+			newCrt("default", "web", cmmeta.ConditionTrue),
+			newCrt("default", "api", cmmeta.ConditionFalse),
+			newCrt("other", "db", cmmeta.ConditionTrue),
+		),
+	}
+
+	tests := map[string]struct {
+		namespace string
+		method    string
+		path      string
+
+		expCode     int
+		expContains []string
+		expExcludes []string
+	}{
+		"list Certificates in all namespaces": {
+			path:        "/",
+			expCode:     http.StatusOK,
+			expContains: []string{`href="/certificates/default/api"`, `href="/certificates/default/web"`, `href="/certificates/other/db"`},
+		},
+		"list Certificates in a single namespace": {
+			namespace:   "default",
+			path:        "/",
+			expCode:     http.StatusOK,
+			expContains: []string{`href="/certificates/default/api"`, `href="/certificates/default/web"`},
+			expExcludes: []string{"other"},
+		},
+		"show the status of a Certificate": {
+			path:        "/certificates/default/web",
+			expCode:     http.StatusOK,
+			expContains: []string{"Name: web", "Namespace: default"},
+		},
+		"Certificate does not exist": {
+			path:    "/certificates/default/missing",
+			expCode: http.StatusNotFound,
+		},
+		"Certificate outside of the namespace is not shown": {
+			namespace: "default",
+			path:      "/certificates/other/db",
+			expCode:   http.StatusNotFound,
+		},
+		"health check": {
+			path:        "/healthz",
+			expCode:     http.StatusOK,
+			expContains: []string{"ok"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(logtesting.TestLogger{T: t}, clients, test.namespace, 0)
+
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+			if rec.Code != test.expCode {
+				t.Errorf("expected status code %d, got %d: %s", test.expCode, rec.Code, rec.Body.String())
+			}
+			for _, s := range test.expContains {
+				if !strings.Contains(rec.Body.String(), s) {
+					t.Errorf("expected response to contain %q, got:\n%s", s, rec.Body.String())
+				}
+			}
+			for _, s := range test.expExcludes {
+				if strings.Contains(rec.Body.String(), s) {
+					t.Errorf("expected response not to contain %q, got:\n%s", s, rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestServerIsReadOnly(t *testing.T) {
+	s := NewServer(logtesting.TestLogger{T: t}, ctlstatus.Clients{}, "", 0)
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(method, "/certificates/default/web", nil))
+		if rec.Code < 400 {
+			t.Errorf("expected %s request to be rejected, got status code %d", method, rec.Code)
+		}
+	}
+}
@@ -136,7 +136,7 @@ func (h *Helper) ValidateIssuedCertificate(certificate *cmapi.Certificate, rootC
 
 	// check the provided certificate is valid
 	expectedOrganization := pki.OrganizationForCertificate(certificate)
-	expectedDNSNames := certificate.Spec.DNSNames
+	expectedDNSNames := pki.CanonicalDNSNames(certificate.Spec.DNSNames)
 	uris, err := pki.URIsForCertificate(certificate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse URIs: %s", err)
@@ -51,8 +51,8 @@ func TestTriggerController(t *testing.T) {
 
 	fakeClock := &fakeclock.FakeClock{}
 	// Build, instantiate and run the trigger controller.
-	_, factory, cmCl, cmFactory := framework.NewClients(t, config)
-	ctrl, queue, mustSync := trigger.NewController(logf.Log, cmCl, factory, cmFactory, framework.NewEventRecorder(t), fakeClock, policies.NewTriggerPolicyChain(fakeClock))
+	kubeClient, factory, cmCl, cmFactory := framework.NewClients(t, config)
+	ctrl, queue, mustSync := trigger.NewController(logf.Log, kubeClient, cmCl, factory, cmFactory, framework.NewEventRecorder(t), fakeClock, policies.NewTriggerPolicyChain(fakeClock), controllerpkg.CertificateOptions{}, nil, "cert-manager-test")
 	c := controllerpkg.NewController(
 		context.Background(),
 		"trigger_test",
@@ -111,8 +111,8 @@ func TestTriggerController_RenewNearExpiry(t *testing.T) {
 	// required
 	policyChain := policies.Chain{policies.CurrentCertificateNearingExpiry(fakeClock)}
 	// Build, instantiate and run the trigger controller.
-	_, factory, cmCl, cmFactory := framework.NewClients(t, config)
-	ctrl, queue, mustSync := trigger.NewController(logf.Log, cmCl, factory, cmFactory, framework.NewEventRecorder(t), fakeClock, policyChain)
+	kubeClient, factory, cmCl, cmFactory := framework.NewClients(t, config)
+	ctrl, queue, mustSync := trigger.NewController(logf.Log, kubeClient, cmCl, factory, cmFactory, framework.NewEventRecorder(t), fakeClock, policyChain, controllerpkg.CertificateOptions{}, nil, "cert-manager-test")
 	c := controllerpkg.NewController(
 		logf.NewContext(context.Background(), logf.Log, "trigger_controller_RenewNearExpiry"),
 		"trigger_test",
@@ -84,6 +84,11 @@ func TestCtlConvert(t *testing.T) {
 			targetVersion: targetv1alpha3,
 			expErr:        true,
 		},
+		"a target version not served by cert-manager should error": {
+			input:         testdataResource1,
+			targetVersion: "cert-manager.io/v2",
+			expErr:        true,
+		},
 		"an object in v1alpha2 that uses a field that has been renamed in v1alpha3 should be converted properly": {
 			input:         testdataResourceWithOrganizationV1alpha2,
 			targetVersion: targetv1alpha3,
@@ -128,6 +128,19 @@ func AddCertificateRequestAnnotations(annotations map[string]string) Certificate
 	}
 }
 
+func AddCertificateRequestLabels(labels map[string]string) CertificateRequestModifier {
+	return func(cr *v1alpha2.CertificateRequest) {
+		labelsNew := cr.GetLabels()
+		if labelsNew == nil {
+			labelsNew = make(map[string]string)
+		}
+		for k, v := range labels {
+			labelsNew[k] = v
+		}
+		cr.SetLabels(labelsNew)
+	}
+}
+
 func AddCertificateRequestOwnerReferences(owners ...metav1.OwnerReference) CertificateRequestModifier {
 	return func(cr *v1alpha2.CertificateRequest) {
 		cr.OwnerReferences = append(cr.OwnerReferences, owners...)
@@ -84,6 +84,12 @@ func SetChallengeReason(s string) ChallengeModifier {
 	}
 }
 
+func SetChallengeFailureType(t cmacme.ACMEErrorType) ChallengeModifier {
+	return func(ch *cmacme.Challenge) {
+		ch.Status.FailureType = t
+	}
+}
+
 func SetChallengeURL(s string) ChallengeModifier {
 	return func(ch *cmacme.Challenge) {
 		ch.Spec.URL = s
@@ -95,3 +101,27 @@ func SetChallengeProcessing(b bool) ChallengeModifier {
 		ch.Status.Processing = b
 	}
 }
+
+func SetChallengeNamespace(namespace string) ChallengeModifier {
+	return func(ch *cmacme.Challenge) {
+		ch.Namespace = namespace
+	}
+}
+
+func SetChallengeSolver(s cmacme.ACMEChallengeSolver) ChallengeModifier {
+	return func(ch *cmacme.Challenge) {
+		ch.Spec.Solver = s
+	}
+}
+
+func SetChallengeStatusCondition(c cmacme.ChallengeCondition) ChallengeModifier {
+	return func(ch *cmacme.Challenge) {
+		for i, existingC := range ch.Status.Conditions {
+			if existingC.Type == c.Type {
+				ch.Status.Conditions[i] = c
+				return
+			}
+		}
+		ch.Status.Conditions = append(ch.Status.Conditions, c)
+	}
+}
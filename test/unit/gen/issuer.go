@@ -100,3 +100,21 @@ func SetIssuerNamespace(namespace string) IssuerModifier {
 		iss.GetObjectMeta().Namespace = namespace
 	}
 }
+
+func SetIssuerAllowedDNSSuffixes(suffixes ...string) IssuerModifier {
+	return func(iss v1alpha2.GenericIssuer) {
+		iss.GetSpec().AllowedDNSSuffixes = suffixes
+	}
+}
+
+func SetIssuerRequestDefaults(defaults v1alpha2.RequestDefaults) IssuerModifier {
+	return func(iss v1alpha2.GenericIssuer) {
+		iss.GetSpec().RequestDefaults = &defaults
+	}
+}
+
+func SetIssuerKeyEscrow(escrow v1alpha2.KeyEscrow) IssuerModifier {
+	return func(iss v1alpha2.GenericIssuer) {
+		iss.GetSpec().KeyEscrow = &escrow
+	}
+}
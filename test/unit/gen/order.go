@@ -84,6 +84,12 @@ func SetOrderCertificate(d []byte) OrderModifier {
 	}
 }
 
+func SetOrderCertificateURL(url string) OrderModifier {
+	return func(order *cmacme.Order) {
+		order.Status.CertificateURL = url
+	}
+}
+
 func SetOrderCommonName(commonName string) OrderModifier {
 	return func(order *cmacme.Order) {
 		order.Spec.CommonName = commonName
@@ -32,6 +32,7 @@ import (
 	controllerapp "github.com/jetstack/cert-manager/cmd/controller/app"
 	ctlcmd "github.com/jetstack/cert-manager/cmd/ctl/cmd"
 	webhookcmd "github.com/jetstack/cert-manager/cmd/webhook/app"
+	webuiapp "github.com/jetstack/cert-manager/cmd/webui/app"
 )
 
 func main() {
@@ -66,6 +67,7 @@ func run(args []string) error {
 		ctlcmd.NewCertManagerCtlCommand(nil, nil, nil, nil),
 		webhookcmd.NewServerCommand(nil),
 		acmesolvercmd.NewACMESolverCommand(nil),
+		webuiapp.NewCommandStartWebUI(nil),
 	} {
 		dir := filepath.Join(root, c.Use)
 